@@ -599,7 +599,7 @@ var specs = []stmtSpec{
 		name:   "drop_view",
 		stmt:   "drop_view_stmt",
 		inline: []string{"opt_drop_behavior", "table_name_list"},
-		match:  []*regexp.Regexp{regexp.MustCompile("'DROP' 'VIEW'")},
+		match:  []*regexp.Regexp{regexp.MustCompile("'DROP' ('MATERIALIZED' )?'VIEW'")},
 	},
 	{
 		name:   "experimental_audit",
@@ -727,6 +727,10 @@ var specs = []stmtSpec{
 		replace: map[string]string{"stmt_list": "'CREATE' 'TABLE' table_name '(' ( column_def ( ',' column_def )* ) ( 'CONSTRAINT' name | ) 'PRIMARY KEY' '(' ( column_name ( ',' column_name )* ) ')' ( table_constraints | ) ')'"},
 		unlink:  []string{"table_name", "column_name", "table_constraints"},
 	},
+	{
+		name: "refresh_materialized_view",
+		stmt: "refresh_stmt",
+	},
 	{
 		name:   "release_savepoint",
 		stmt:   "release_stmt",
@@ -738,6 +742,12 @@ var specs = []stmtSpec{
 		match:   []*regexp.Regexp{regexp.MustCompile("'ALTER' 'TABLE' .* 'RENAME' ('COLUMN'|name)")},
 		replace: map[string]string{"relation_expr": "table_name", "name 'TO'": "current_name 'TO'"},
 		unlink:  []string{"table_name", "current_name"}},
+	{
+		name:    "rename_constraint",
+		stmt:    "alter_rename_table_stmt",
+		match:   []*regexp.Regexp{regexp.MustCompile("'ALTER' 'TABLE' .* 'RENAME' 'CONSTRAINT'")},
+		replace: map[string]string{"relation_expr": "table_name", "constraint_name 'TO'": "current_name 'TO'"},
+		unlink:  []string{"table_name", "current_name"}},
 	{
 		name:  "rename_database",
 		stmt:  "alter_rename_database_stmt",
@@ -59,3 +59,38 @@ func TestNodeIDFromKey(t *testing.T) {
 		})
 	}
 }
+
+func TestTableIDFromTableStatAddedKey(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	testCases := []struct {
+		key     string
+		tableID uint32
+		success bool
+	}{
+		{MakeTableStatAddedKey(0), 0, true},
+		{MakeTableStatAddedKey(1), 1, true},
+		{MakeTableStatAddedKey(53), 53, true},
+		{MakeTableStatAddedKey(53) + "foo", 0, false},
+		{"foo" + MakeTableStatAddedKey(53), 0, false},
+		{KeyTableStatAddedPrefix, 0, false},
+		{KeyTableStatAddedPrefix + ":", 0, false},
+		{KeyTableStatAddedPrefix + ":-1", 0, false},
+		{MakeNodeIDKey(1), 0, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.key, func(t *testing.T) {
+			tableID, err := TableIDFromTableStatAddedKey(tc.key)
+			if err != nil {
+				if tc.success {
+					t.Errorf("expected success, got error: %s", err)
+				}
+			} else if !tc.success {
+				t.Errorf("expected failure, got table ID %d", tableID)
+			} else if tableID != tc.tableID {
+				t.Errorf("expected table ID %d, got %d", tc.tableID, tableID)
+			}
+		})
+	}
+}
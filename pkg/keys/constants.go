@@ -237,6 +237,10 @@ var (
 	// MigrationKeyMax is the maximum value for any system migration key.
 	MigrationKeyMax = MigrationPrefix.PrefixEnd()
 
+	// StatsRefreshLeasePrefix specifies the key prefix of the leases taken by
+	// the jobs that refresh table statistics automatically.
+	StatsRefreshLeasePrefix = roachpb.Key(makeKey(SystemPrefix, roachpb.RKey("stats-refresh/")))
+
 	// DescIDGenerator is the global descriptor ID generator sequence used for
 	// table and namespace IDs.
 	DescIDGenerator = roachpb.Key(makeKey(SystemPrefix, roachpb.RKey("desc-idgen")))
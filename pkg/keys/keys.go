@@ -123,6 +123,15 @@ func NodeLivenessKey(nodeID roachpb.NodeID) roachpb.Key {
 	return key
 }
 
+// StatsRefreshLeaseKey returns the key on which a job must take a lease in
+// order to refresh the statistics of the specified table.
+func StatsRefreshLeaseKey(tableID uint32) roachpb.Key {
+	key := make(roachpb.Key, 0, len(StatsRefreshLeasePrefix)+9)
+	key = append(key, StatsRefreshLeasePrefix...)
+	key = encoding.EncodeUvarintAscending(key, uint64(tableID))
+	return key
+}
+
 // NodeStatusKey returns the key for accessing the node status for the
 // specified node ID.
 func NodeStatusKey(nodeID roachpb.NodeID) roachpb.Key {
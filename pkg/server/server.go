@@ -59,6 +59,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/distsqlrun"
 	"github.com/cockroachdb/cockroach/pkg/sql/jobs"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire"
+	"github.com/cockroachdb/cockroach/pkg/sql/stats"
 	"github.com/cockroachdb/cockroach/pkg/sqlmigrations"
 	"github.com/cockroachdb/cockroach/pkg/storage"
 	"github.com/cockroachdb/cockroach/pkg/storage/engine"
@@ -80,6 +81,15 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 )
 
+const (
+	// tableStatsCacheSize is the number of tables whose statistics are cached
+	// for the optimizer.
+	tableStatsCacheSize = 256
+
+	// histogramCacheSize is the number of histograms cached for the optimizer.
+	histogramCacheSize = 1024
+)
+
 var (
 	// Allocation pool for gzipResponseWriters.
 	gzipResponseWriterPool sync.Pool
@@ -523,7 +533,10 @@ func NewServer(cfg Config, stopper *stop.Stopper) (*Server, error) {
 		HistogramWindowInterval: s.cfg.HistogramWindowInterval(),
 		RangeDescriptorCache:    s.distSender.RangeDescriptorCache(),
 		LeaseHolderCache:        s.distSender.LeaseHolderCache(),
-		TestingKnobs:            sqlExecutorTestingKnobs,
+		TableStatsCache: stats.NewTableStatisticsCache(
+			tableStatsCacheSize, histogramCacheSize, s.gossip, s.db, &sqlExecutor,
+		),
+		TestingKnobs: sqlExecutorTestingKnobs,
 		DistSQLPlanner: sql.NewDistSQLPlanner(
 			ctx,
 			distsqlrun.Version,
@@ -551,6 +564,7 @@ func NewServer(cfg Config, stopper *stop.Stopper) (*Server, error) {
 	if sqlEvalContext := s.cfg.TestingKnobs.SQLEvalContext; sqlEvalContext != nil {
 		execCfg.EvalContextTestingKnobs = *sqlEvalContext.(*tree.EvalContextTestingKnobs)
 	}
+	execCfg.StatsRefresher = sql.NewStatsRefresher(&execCfg)
 	s.sqlExecutor = sql.NewExecutor(execCfg, s.stopper)
 	if s.cfg.UseLegacyConnHandling {
 		s.registry.AddMetricStruct(s.sqlExecutor)
@@ -1480,6 +1494,8 @@ If problems persist, please see ` + base.DocsURL("cluster-setup-troubleshooting.
 		); err != nil {
 			return err
 		}
+		sql.NewTemporarySchemaCleaner(s.execCfg, regLiveness).Start(ctx, s.stopper)
+		s.execCfg.StatsRefresher.Start(ctx, s.stopper)
 	}
 
 	// Before serving SQL requests, we have to make sure the database is
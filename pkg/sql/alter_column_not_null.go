@@ -0,0 +1,154 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+)
+
+// notNullValidationMaxKeys is the maximum number of offending primary keys
+// reported when the validation of a NOT NULL constraint fails.
+const notNullValidationMaxKeys = 10
+
+// alterColumnSetNotNull applies an ALTER COLUMN ... SET NOT NULL command to
+// tableDesc and returns whether the descriptor was changed.
+//
+// The constraint is first added as a check constraint, which is enforced on
+// writes as soon as the new descriptor version is in use, together with a
+// mutation that makes the schema changer validate the existing rows (see
+// SchemaChanger.validateNotNullConstraints). Once the validation succeeds
+// the column is made non-nullable and the check constraint is removed; if it
+// fails, the schema change is rolled back and the check constraint removed.
+func alterColumnSetNotNull(
+	tableDesc *sqlbase.TableDescriptor, t *tree.AlterTableSetNotNull,
+) (bool, error) {
+	col, dropped, err := tableDesc.FindColumnByName(t.Column)
+	if err != nil {
+		return false, err
+	}
+	if dropped {
+		return false, fmt.Errorf("column %q in the middle of being dropped", t.Column)
+	}
+	if _, err := tableDesc.FindActiveColumnByID(col.ID); err != nil {
+		return false, fmt.Errorf("column %q in the middle of being added, try again later", t.Column)
+	}
+	if !col.Nullable {
+		return false, nil
+	}
+	if notNullMutationInProgress(tableDesc, col.ID) {
+		return false, fmt.Errorf("column %q in the middle of being made NOT NULL, try again later", t.Column)
+	}
+
+	info, err := tableDesc.GetConstraintInfo(context.TODO(), nil)
+	if err != nil {
+		return false, err
+	}
+	name := fmt.Sprintf("%s_auto_not_null", col.Name)
+	for i := 1; ; i++ {
+		if _, ok := info[name]; !ok {
+			break
+		}
+		name = fmt.Sprintf("%s_auto_not_null%d", col.Name, i)
+	}
+
+	tableDesc.Checks = append(tableDesc.Checks, &sqlbase.TableDescriptor_CheckConstraint{
+		Expr:                fmt.Sprintf("%s IS NOT NULL", tree.NameString(col.Name)),
+		Name:                name,
+		Validity:            sqlbase.ConstraintValidity_Validating,
+		ColumnIDs:           []sqlbase.ColumnID{col.ID},
+		IsNonNullConstraint: true,
+	})
+	tableDesc.AddNotNullMutation(name, col.ID, sqlbase.DescriptorMutation_ADD)
+	return true, nil
+}
+
+// notNullMutationInProgress returns whether a mutation of tableDesc is making
+// the column colID non-nullable.
+func notNullMutationInProgress(tableDesc *sqlbase.TableDescriptor, colID sqlbase.ColumnID) bool {
+	for _, m := range tableDesc.Mutations {
+		if c := m.GetConstraint(); c != nil &&
+			c.ConstraintType == sqlbase.ConstraintToUpdate_NOT_NULL && c.NotNullColumn == colID {
+			return true
+		}
+	}
+	return false
+}
+
+// validateNotNullConstraints checks that the existing rows of the table
+// satisfy the NOT NULL constraints being added by the mutations of the
+// schema changer. The table is scanned by a distributed query; if any row
+// has a NULL value in a column being made non-nullable, a permanent error
+// listing the primary keys of (some of) the offending rows is returned, so
+// that the schema change is rolled back.
+func (sc *SchemaChanger) validateNotNullConstraints(
+	ctx context.Context, tableDesc *sqlbase.TableDescriptor, constraints []sqlbase.ConstraintToUpdate,
+) error {
+	if len(constraints) == 0 {
+		return nil
+	}
+	pkCols := make([]sqlbase.ColumnDescriptor, len(tableDesc.PrimaryIndex.ColumnIDs))
+	var pkNames bytes.Buffer
+	for i, id := range tableDesc.PrimaryIndex.ColumnIDs {
+		col, err := tableDesc.FindActiveColumnByID(id)
+		if err != nil {
+			return err
+		}
+		pkCols[i] = *col
+		if i > 0 {
+			pkNames.WriteString(", ")
+		}
+		pkNames.WriteString(tree.NameString(col.Name))
+	}
+
+	ie := InternalExecutor{ExecCfg: sc.execCfg}
+	for _, c := range constraints {
+		col, err := tableDesc.FindActiveColumnByID(c.NotNullColumn)
+		if err != nil {
+			// The column is being dropped by a later schema change, which
+			// also removed the check constraint: there is nothing to validate.
+			continue
+		}
+		stmt := fmt.Sprintf(`SELECT %[1]s FROM [%[2]d AS t] WHERE %[3]s IS NULL ORDER BY %[1]s LIMIT %[4]d`,
+			pkNames.String(), tableDesc.ID, tree.NameString(col.Name), notNullValidationMaxKeys+1)
+		rows, _ /* cols */, err := ie.QueryRows(ctx, "validate-not-null", stmt)
+		if err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			continue
+		}
+		var keys bytes.Buffer
+		for i, row := range rows {
+			if i == notNullValidationMaxKeys {
+				keys.WriteString(", ...")
+				break
+			}
+			if i > 0 {
+				keys.WriteString(", ")
+			}
+			fmt.Fprintf(&keys, "(%s)", labeledRowValues(pkCols, row))
+		}
+		return pgerror.NewErrorf(pgerror.CodeNotNullViolationError,
+			"validation of NOT NULL constraint failed on column %q: found NULL values in rows with primary key %s",
+			col.Name, keys.String())
+	}
+	return nil
+}
@@ -0,0 +1,331 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"fmt"
+
+	"github.com/gogo/protobuf/proto"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+)
+
+// alterColumnType applies an ALTER COLUMN ... SET DATA TYPE command to
+// tableDesc.
+//
+// Type changes which leave the encoding of the existing values unchanged,
+// such as INT4 to INT8 or STRING(10) to STRING(20), only update the column
+// descriptor, in which case true is returned. Any other type change adds a
+// hidden shadow column computed from the existing values, together with a
+// copy of every index on the column that uses the shadow column instead. Once
+// the schema changer has backfilled them, it swaps them in place of the
+// original column and indexes and drops the latter (see
+// TableDescriptor.SwapReplacedColumns).
+func alterColumnType(
+	params runParams, tableDesc *sqlbase.TableDescriptor, t *tree.AlterTableAlterColumnType,
+) (bool, error) {
+	col, dropped, err := tableDesc.FindColumnByName(t.Column)
+	if err != nil {
+		return false, err
+	}
+	if dropped {
+		return false, fmt.Errorf("column %q in the middle of being dropped", t.Column)
+	}
+	if _, err := tableDesc.FindActiveColumnByID(col.ID); err != nil {
+		return false, fmt.Errorf("column %q in the middle of being added, try again later", t.Column)
+	}
+	for _, m := range tableDesc.Mutations {
+		if m.ReplacesColumnID == col.ID {
+			return false, fmt.Errorf("column %q in the middle of a type change, try again later", t.Column)
+		}
+	}
+	if notNullMutationInProgress(tableDesc, col.ID) {
+		return false, fmt.Errorf("column %q in the middle of being made NOT NULL, try again later", t.Column)
+	}
+
+	newType, err := sqlbase.MakeColumnType(t.ToType)
+	if err != nil {
+		return false, err
+	}
+	if t.Using == nil && columnTypeChangeIsMetadataOnly(col.Type, newType) {
+		if proto.Equal(&col.Type, &newType) {
+			return false, nil
+		}
+		col.Type = newType
+		tableDesc.UpdateColumnDescriptor(col)
+		return true, nil
+	}
+
+	if err := checkColumnTypeCanBeRewritten(params, tableDesc, col, newType); err != nil {
+		return false, err
+	}
+
+	// The default expression is carried over to the new column, so it must be
+	// valid for the new type.
+	if col.DefaultExpr != nil {
+		defaultExpr, err := parser.ParseExpr(*col.DefaultExpr)
+		if err != nil {
+			return false, err
+		}
+		if _, err := sqlbase.SanitizeVarFreeExpr(
+			defaultExpr, newType.ToDatumType(), "DEFAULT", &params.p.semaCtx, params.EvalContext(),
+		); err != nil {
+			return false, err
+		}
+	}
+
+	expr := t.Using
+	if expr == nil {
+		expr = &tree.CastExpr{
+			Expr:       &tree.ColumnItem{ColumnName: tree.Name(col.Name)},
+			Type:       t.ToType,
+			SyntaxMode: tree.CastShort,
+		}
+	}
+	if err := params.p.txCtx.AssertNoAggregationOrWindowing(
+		expr, "USING expressions", params.SessionData().SearchPath,
+	); err != nil {
+		return false, err
+	}
+	computeExpr := tree.Serialize(expr)
+	shadow := sqlbase.ColumnDescriptor{
+		Name:        makeShadowColumnName(tableDesc, col.Name),
+		Type:        newType,
+		Nullable:    col.Nullable,
+		Hidden:      true,
+		ComputeExpr: &computeExpr,
+	}
+
+	// Verify the expression computing the new values.
+	ivarHelper := tree.MakeIndexedVarHelper(&descContainer{tableDesc.Columns}, len(tableDesc.Columns))
+	typedExprs, err := sqlbase.MakeComputedExprs(
+		[]sqlbase.ColumnDescriptor{shadow}, tableDesc.Columns, &ivarHelper,
+		&params.p.txCtx, params.EvalContext(),
+	)
+	if err != nil {
+		return false, err
+	}
+	if typ := typedExprs[0].ResolvedType(); !typ.Equivalent(newType.ToDatumType()) {
+		return false, pgerror.NewErrorf(pgerror.CodeDatatypeMismatchError,
+			"expected USING expression to have type %s, but '%s' has type %s",
+			newType.SQLString(), expr, typ)
+	}
+
+	tableDesc.AddColumnMutation(shadow, sqlbase.DescriptorMutation_ADD)
+	tableDesc.Mutations[len(tableDesc.Mutations)-1].ReplacesColumnID = col.ID
+	for _, family := range tableDesc.Families {
+		for _, id := range family.ColumnIDs {
+			if id == col.ID && family.Name != "" {
+				if err := tableDesc.AddColumnToFamilyMaybeCreate(
+					shadow.Name, family.Name, false /* create */, false, /* ifNotExists */
+				); err != nil {
+					return false, err
+				}
+			}
+		}
+	}
+
+	for _, idx := range tableDesc.Indexes {
+		if !idx.ContainsColumnID(col.ID) {
+			continue
+		}
+		// The IDs of the new index, and the column IDs derived from its column
+		// names, are filled in by AllocateIDs.
+		newIdx := idx
+		newIdx.ID = 0
+		newIdx.Name = ""
+		newIdx.ColumnNames = replaceColumnName(idx.ColumnNames, col.Name, shadow.Name)
+		newIdx.ColumnIDs = make([]sqlbase.ColumnID, len(idx.ColumnIDs))
+		for i, id := range idx.ColumnIDs {
+			if id != col.ID {
+				newIdx.ColumnIDs[i] = id
+			}
+		}
+		newIdx.StoreColumnNames = replaceColumnName(idx.StoreColumnNames, col.Name, shadow.Name)
+		newIdx.StoreColumnIDs = nil
+		newIdx.ExtraColumnIDs = nil
+		newIdx.CompositeColumnIDs = nil
+		if err := tableDesc.AddIndexMutation(newIdx, sqlbase.DescriptorMutation_ADD); err != nil {
+			return false, err
+		}
+		tableDesc.Mutations[len(tableDesc.Mutations)-1].ReplacesIndexID = idx.ID
+	}
+	return false, nil
+}
+
+// checkColumnTypeCanBeRewritten returns an error if the type of col cannot
+// be changed to newType by rewriting the column.
+func checkColumnTypeCanBeRewritten(
+	params runParams, tableDesc *sqlbase.TableDescriptor, col sqlbase.ColumnDescriptor,
+	newType sqlbase.ColumnType,
+) error {
+	if col.IsComputed() {
+		return pgerror.Unimplemented("alter computed column type",
+			"changing the type of a computed column is not supported")
+	}
+	if len(col.UsesSequenceIds) > 0 {
+		return pgerror.Unimplemented("alter sequence column type",
+			"changing the type of a column using a sequence is not supported")
+	}
+	if tableDesc.PrimaryIndex.ContainsColumnID(col.ID) {
+		return fmt.Errorf("column %q is referenced by the primary key", col.Name)
+	}
+
+	for _, ref := range tableDesc.DependedOnBy {
+		for _, id := range ref.ColumnIDs {
+			if id != col.ID {
+				continue
+			}
+			viewDesc, err := sqlbase.GetTableDescFromID(params.ctx, params.p.txn, ref.ID)
+			if err != nil {
+				return err
+			}
+			return pgerror.NewErrorf(pgerror.CodeDependentObjectsStillExistError,
+				"cannot change the type of column %q because view %q depends on it",
+				col.Name, viewDesc.Name)
+		}
+	}
+
+	for _, check := range tableDesc.Checks {
+		if used, err := check.UsesColumn(tableDesc, col.ID); err != nil {
+			return err
+		} else if used {
+			return pgerror.NewErrorf(pgerror.CodeDependentObjectsStillExistError,
+				"cannot change the type of column %q because check constraint %q depends on it",
+				col.Name, check.Name)
+		}
+	}
+
+	ivarHelper := tree.MakeIndexedVarHelper(&descContainer{tableDesc.Columns}, len(tableDesc.Columns))
+	if _, err := sqlbase.MakeComputedExprs(
+		tableDesc.Columns, tableDesc.Columns, &ivarHelper, &params.p.txCtx, params.EvalContext(),
+	); err != nil {
+		return err
+	}
+	for i := range tableDesc.Columns {
+		if tableDesc.Columns[i].ID == col.ID && ivarHelper.IndexedVarUsed(i) {
+			return pgerror.NewErrorf(pgerror.CodeDependentObjectsStillExistError,
+				"cannot change the type of column %q because a computed column depends on it",
+				col.Name)
+		}
+	}
+
+	for _, m := range tableDesc.Mutations {
+		if idx := m.GetIndex(); idx != nil && idx.ContainsColumnID(col.ID) {
+			return fmt.Errorf("index %q on column %q in the middle of a schema change, try again later",
+				idx.Name, col.Name)
+		}
+	}
+	for _, idx := range tableDesc.Indexes {
+		if !idx.ContainsColumnID(col.ID) {
+			continue
+		}
+		if idx.Type == sqlbase.IndexDescriptor_INVERTED {
+			return pgerror.Unimplemented("alter inverted indexed column type",
+				"changing the type of a column with an inverted index is not supported")
+		}
+		if len(idx.Interleave.Ancestors) > 0 || len(idx.InterleavedBy) > 0 {
+			return pgerror.Unimplemented("alter interleaved column type",
+				"changing the type of a column in an interleaved index is not supported")
+		}
+		if idx.Partitioning.NumColumns > 0 {
+			return pgerror.Unimplemented("alter partitioned column type",
+				"changing the type of a column in a partitioned index is not supported")
+		}
+		for _, id := range idx.ColumnIDs {
+			if id != col.ID {
+				continue
+			}
+			if idx.ForeignKey.IsSet() || len(idx.ReferencedBy) > 0 {
+				return pgerror.NewErrorf(pgerror.CodeDependentObjectsStillExistError,
+					"cannot change the type of column %q because it is used in a foreign key constraint",
+					col.Name)
+			}
+			if sqlbase.MustBeValueEncoded(newType.SemanticType) {
+				return fmt.Errorf("column %s is of type %s and thus is not indexable",
+					col.Name, newType.SemanticType)
+			}
+		}
+	}
+	return nil
+}
+
+// columnTypeChangeIsMetadataOnly returns whether every value of a column of
+// type oldType is also a valid value of type newType, with the same encoding,
+// so that changing the type of the column requires no rewrite.
+func columnTypeChangeIsMetadataOnly(oldType, newType sqlbase.ColumnType) bool {
+	// widens returns whether the size limit newLimit, if any, is at least the
+	// size limit oldLimit.
+	widens := func(oldLimit, newLimit int32) bool {
+		return newLimit == 0 || (oldLimit != 0 && newLimit >= oldLimit)
+	}
+
+	oldBase, newBase := oldType, newType
+	oldBase.Width, newBase.Width = 0, 0
+	oldBase.Precision, newBase.Precision = 0, 0
+	oldBase.VisibleType, newBase.VisibleType = 0, 0
+	if !proto.Equal(&oldBase, &newBase) {
+		return false
+	}
+	switch oldType.SemanticType {
+	case sqlbase.ColumnType_INT:
+		if oldType.VisibleType == sqlbase.ColumnType_BIT || newType.VisibleType == sqlbase.ColumnType_BIT {
+			return proto.Equal(&oldType, &newType)
+		}
+		return widens(oldType.Width, newType.Width)
+	case sqlbase.ColumnType_FLOAT:
+		return widens(oldType.Precision, newType.Precision)
+	case sqlbase.ColumnType_DECIMAL:
+		if newType.Precision == 0 && newType.Width == 0 {
+			return true
+		}
+		return oldType.Width == newType.Width && widens(oldType.Precision, newType.Precision)
+	case sqlbase.ColumnType_STRING, sqlbase.ColumnType_COLLATEDSTRING:
+		return widens(oldType.Width, newType.Width)
+	default:
+		return proto.Equal(&oldType, &newType)
+	}
+}
+
+// makeShadowColumnName returns a name for the column replacing the column
+// colName, which does not clash with the name of any other column.
+func makeShadowColumnName(tableDesc *sqlbase.TableDescriptor, colName string) string {
+	for i := 1; ; i++ {
+		name := fmt.Sprintf("%s_%d", colName, i)
+		if _, _, err := tableDesc.FindColumnByName(tree.Name(name)); err != nil {
+			return name
+		}
+	}
+}
+
+// replaceColumnName returns a copy of names in which oldName is replaced by
+// newName.
+func replaceColumnName(names []string, oldName, newName string) []string {
+	if names == nil {
+		return nil
+	}
+	res := make([]string, len(names))
+	for i, name := range names {
+		if name == oldName {
+			res[i] = newName
+		} else {
+			res[i] = name
+		}
+	}
+	return res
+}
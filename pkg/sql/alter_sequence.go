@@ -61,6 +61,13 @@ func (n *alterSequenceNode) startExec(params runParams) error {
 	if err != nil {
 		return err
 	}
+	for _, option := range n.n.Options {
+		if option.Name == tree.SeqOptOwnedBy {
+			if err := params.p.setSequenceOwner(params.ctx, desc, option.OwnedBy); err != nil {
+				return err
+			}
+		}
+	}
 
 	if err := params.p.writeTableDesc(params.ctx, n.seqDesc); err != nil {
 		return err
@@ -170,9 +170,18 @@ func (n *alterTableNode) startExec(params runParams) error {
 					Unique:           true,
 					StoreColumnNames: d.Storing.ToStrings(),
 				}
-				if err := idx.FillColumns(d.Columns); err != nil {
+				columns, exprCols, err := makeIndexExprColumns(
+					params.ctx, n.tableDesc, tn, d.Columns, &params.p.semaCtx, params.EvalContext(),
+				)
+				if err != nil {
 					return err
 				}
+				if err := idx.FillColumns(columns); err != nil {
+					return err
+				}
+				for _, col := range exprCols {
+					n.tableDesc.AddColumnMutation(col, sqlbase.DescriptorMutation_ADD)
+				}
 				if d.PartitionBy != nil {
 					partitioning, err := CreatePartitioning(
 						params.ctx, params.p.ExecCfg().Settings,
@@ -268,6 +277,14 @@ func (n *alterTableNode) startExec(params runParams) error {
 				}
 			}
 
+			// Sequences owned by the dropped column are dropped along with it.
+			if err := params.p.canRemoveOwnedSequences(params.ctx, &col, nil /* dropping */); err != nil {
+				return err
+			}
+			if err := params.p.dropSequencesOwnedByCol(params.ctx, &col); err != nil {
+				return err
+			}
+
 			// You can't drop a column depended on by a view unless CASCADE was
 			// specified.
 			for _, ref := range n.tableDesc.DependedOnBy {
@@ -302,6 +319,12 @@ func (n *alterTableNode) startExec(params runParams) error {
 			if n.tableDesc.PrimaryIndex.ContainsColumnID(col.ID) {
 				return fmt.Errorf("column %q is referenced by the primary key", col.Name)
 			}
+			// Indexes on expressions using the column are dropped like indexes
+			// on the column itself.
+			exprCols, err := indexExprColumnsUsing(n.tableDesc, col.ID)
+			if err != nil {
+				return err
+			}
 			for _, idx := range n.tableDesc.AllNonDropIndexes() {
 				// We automatically drop indexes on that column that only
 				// index that column (and no other columns). If CASCADE is
@@ -326,7 +349,7 @@ func (n *alterTableNode) startExec(params runParams) error {
 
 				// Analyze the index.
 				for _, id := range idx.ColumnIDs {
-					if id == col.ID {
+					if _, ok := exprCols[id]; ok || id == col.ID {
 						containsThisColumn = true
 					} else {
 						containsOnlyThisColumn = false
@@ -419,6 +442,9 @@ func (n *alterTableNode) startExec(params runParams) error {
 			case sqlbase.ConstraintTypeCheck:
 				for i := range n.tableDesc.Checks {
 					if n.tableDesc.Checks[i].Name == name {
+						if n.tableDesc.Checks[i].Validity == sqlbase.ConstraintValidity_Validating {
+							return fmt.Errorf("constraint %q in the middle of being added, try again later", t.Constraint)
+						}
 						n.tableDesc.Checks = append(n.tableDesc.Checks[:i], n.tableDesc.Checks[i+1:]...)
 						descriptorChanged = true
 						break
@@ -465,6 +491,9 @@ func (n *alterTableNode) startExec(params runParams) error {
 					panic("constraint returned by GetConstraintInfo not found")
 				}
 				ck := n.tableDesc.Checks[idx]
+				if ck.Validity == sqlbase.ConstraintValidity_Validating {
+					return fmt.Errorf("constraint %q in the middle of being added, try again later", t.Constraint)
+				}
 				if err := params.p.validateCheckExpr(
 					params.ctx, ck.Expr, &n.n.Table, n.tableDesc,
 				); err != nil {
@@ -500,6 +529,70 @@ func (n *alterTableNode) startExec(params runParams) error {
 				return errors.Errorf("validating %s constraint %q unsupported", constraint.Kind, t.Constraint)
 			}
 
+		case *tree.AlterTableRenameConstraint:
+			info, err := n.tableDesc.GetConstraintInfo(params.ctx, nil)
+			if err != nil {
+				return err
+			}
+			name, newName := string(t.Constraint), string(t.NewName)
+			details, ok := info[name]
+			if !ok {
+				return fmt.Errorf("constraint %q does not exist", t.Constraint)
+			}
+			if name == newName {
+				// Noop.
+				continue
+			}
+			if _, ok := info[newName]; ok {
+				return pgerror.NewErrorf(pgerror.CodeDuplicateObjectError,
+					"duplicate constraint name: %q", t.NewName)
+			}
+			if err := params.p.renameConstraint(
+				params.ctx, n.tableDesc, details.Kind, name, newName,
+			); err != nil {
+				return err
+			}
+			descriptorChanged = true
+
+		case *tree.AlterTableAlterConstraint:
+			info, err := n.tableDesc.GetConstraintInfo(params.ctx, nil)
+			if err != nil {
+				return err
+			}
+			constraint, ok := info[string(t.Constraint)]
+			if !ok {
+				return fmt.Errorf("constraint %q does not exist", t.Constraint)
+			}
+			if constraint.Kind != sqlbase.ConstraintTypeFK {
+				return pgerror.NewErrorf(pgerror.CodeWrongObjectTypeError,
+					"constraint %q of relation %q is not a foreign key constraint",
+					t.Constraint, n.tableDesc.Name)
+			}
+			// Foreign keys are always checked at the end of each statement,
+			// i.e. NOT DEFERRABLE INITIALLY IMMEDIATE, which is all that can
+			// be requested.
+			for _, attr := range t.Attributes {
+				switch attr {
+				case tree.ConstraintDeferrable, tree.ConstraintInitiallyDeferred:
+					return pgerror.Unimplemented("alter constraint "+attr.String(),
+						"deferrable constraints are not supported")
+				}
+			}
+
+		case *tree.AlterTableAlterColumnType:
+			changed, err := alterColumnType(params, n.tableDesc, t)
+			if err != nil {
+				return err
+			}
+			descriptorChanged = descriptorChanged || changed
+
+		case *tree.AlterTableSetNotNull:
+			changed, err := alterColumnSetNotNull(n.tableDesc, t)
+			if err != nil {
+				return err
+			}
+			descriptorChanged = descriptorChanged || changed
+
 		case tree.ColumnMutationCmd:
 			// Column mutations
 			col, dropped, err := n.tableDesc.FindColumnByName(t.GetColumn())
@@ -628,6 +721,67 @@ func (p *planner) setAuditMode(
 	return desc.SetAuditMode(auditMode)
 }
 
+// renameConstraint renames a constraint of the table. Primary key and
+// unique constraints are backed by indexes, which are renamed along with
+// them.
+func (p *planner) renameConstraint(
+	ctx context.Context,
+	tableDesc *sqlbase.TableDescriptor,
+	kind sqlbase.ConstraintType,
+	oldName, newName string,
+) error {
+	switch kind {
+	case sqlbase.ConstraintTypePK, sqlbase.ConstraintTypeUnique:
+		idx, _, err := tableDesc.FindIndexByName(oldName)
+		if err != nil {
+			return err
+		}
+		if _, _, err := tableDesc.FindIndexByName(newName); err == nil {
+			return fmt.Errorf("index name %q already exists", newName)
+		}
+		for _, tableRef := range tableDesc.DependedOnBy {
+			if tableRef.IndexID == idx.ID {
+				return p.dependentViewRenameError(
+					ctx, "index", oldName, tableDesc.ParentID, tableRef.ID)
+			}
+		}
+		if idx.ID == tableDesc.PrimaryIndex.ID {
+			tableDesc.PrimaryIndex.Name = newName
+		} else {
+			tableDesc.RenameIndexDescriptor(idx, newName)
+		}
+
+	case sqlbase.ConstraintTypeFK:
+		for _, idx := range tableDesc.AllNonDropIndexes() {
+			if idx.ForeignKey.IsSet() && idx.ForeignKey.Name == oldName {
+				fkIdx, err := tableDesc.FindIndexByID(idx.ID)
+				if err != nil {
+					return err
+				}
+				fkIdx.ForeignKey.Name = newName
+				return nil
+			}
+		}
+		panic("constraint returned by GetConstraintInfo not found")
+
+	case sqlbase.ConstraintTypeCheck:
+		for _, ck := range tableDesc.Checks {
+			if ck.Name == oldName {
+				if ck.Validity == sqlbase.ConstraintValidity_Validating {
+					return fmt.Errorf("constraint %q in the middle of being added, try again later", oldName)
+				}
+				ck.Name = newName
+				return nil
+			}
+		}
+		panic("constraint returned by GetConstraintInfo not found")
+
+	default:
+		return errors.Errorf("renaming %s constraint %q unsupported", kind, oldName)
+	}
+	return nil
+}
+
 func (n *alterTableNode) Next(runParams) (bool, error) { return false, nil }
 func (n *alterTableNode) Values() tree.Datums          { return tree.Datums{} }
 func (n *alterTableNode) Close(context.Context)        {}
@@ -681,6 +835,9 @@ func applyColumnMutation(
 		}
 
 	case *tree.AlterTableDropNotNull:
+		if notNullMutationInProgress(tableDesc, col.ID) {
+			return fmt.Errorf("column %q in the middle of being made NOT NULL, try again later", col.Name)
+		}
 		col.Nullable = true
 	}
 	return nil
@@ -794,5 +951,8 @@ func (p *planner) injectTableStats(
 			return err
 		}
 	}
+
+	// Make the new statistics visible to the optimizer.
+	invalidateTableStats(ctx, p.ExecCfg(), desc.ID)
 	return nil
 }
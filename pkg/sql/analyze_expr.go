@@ -23,6 +23,7 @@ import (
 )
 
 // analyzeExpr performs semantic analysis of an expression, including:
+// - inlining calls to user-defined functions;
 // - replacing sub-queries by a sql.subquery node;
 // - resolving names (optional);
 // - type checking (with optional type enforcement);
@@ -39,13 +40,21 @@ func (p *planner) analyzeExpr(
 	requireType bool,
 	typingContext string,
 ) (tree.TypedExpr, error) {
+	// Inline the calls to user-defined functions. Most of them have
+	// already been inlined when planning the enclosing statement, but
+	// not all the expressions of a statement are reachable from there
+	// (e.g. join conditions).
+	raw, err := p.inlineFunctionsInExpr(ctx, raw)
+	if err != nil {
+		return nil, err
+	}
+
 	// Replace the sub-queries.
 	// In all contexts that analyze a single expression, a single value
 	// is expected. Tell this to replaceSubqueries.  (See UPDATE for a
 	// counter-example; cases where a subquery is an operand of a
 	// comparison are handled specially in the subqueryVisitor already.)
-	err := p.analyzeSubqueries(ctx, raw, 1 /* one value expected */)
-	if err != nil {
+	if err := p.analyzeSubqueries(ctx, raw, 1 /* one value expected */); err != nil {
 		return nil, err
 	}
 
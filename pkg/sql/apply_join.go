@@ -0,0 +1,314 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/types"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+)
+
+// lateralFrame makes the columns of the left side of an apply join visible
+// to the name resolution of its LATERAL right side.
+type lateralFrame struct {
+	info *sqlbase.DataSourceInfo
+
+	// row contains the values of the current row of the left side, which
+	// replace the references to its columns. It is nil when the right side is
+	// planned only to determine its columns, in which case the references are
+	// replaced by NULLs.
+	row tree.Datums
+}
+
+// lateralEnvironment is the stack of lateral frames, innermost last.
+type lateralEnvironment []lateralFrame
+
+// columnValue returns the expression that replaces a reference to the given
+// column of the left side.
+func (f *lateralFrame) columnValue(colIdx int) tree.Expr {
+	if f.row != nil && f.row[colIdx] != tree.DNull {
+		return f.row[colIdx]
+	}
+	// Keep the type of the column, so that the right side is typed the same
+	// way whatever the values of the left side.
+	typ := f.info.SourceColumns[colIdx].Typ
+	if typ == types.Unknown {
+		return tree.DNull
+	}
+	expr, err := tree.NewTypedCastExpr(tree.DNull, typ)
+	if err != nil {
+		// Not all types can be the target of a cast.
+		return tree.DNull
+	}
+	return expr
+}
+
+// isUndefinedNameError returns true if err reports a reference to a column or
+// source that does not exist.
+func isUndefinedNameError(err error) bool {
+	pgErr, ok := pgerror.GetPGCause(err)
+	return ok &&
+		(pgErr.Code == pgerror.CodeUndefinedColumnError || pgErr.Code == pgerror.CodeUndefinedTableError)
+}
+
+// resolveLateralColumn attempts to resolve a column reference that cannot be
+// resolved using the sources of the current scope against the left sides of
+// the enclosing apply joins. err is the error returned by the resolution in
+// the current scope; it is returned if the column cannot be resolved in any of
+// the lateral frames either.
+func (v *nameResolutionVisitor) resolveLateralColumn(
+	c *tree.ColumnItem, err error,
+) (tree.Expr, error) {
+	if !isUndefinedNameError(err) {
+		return nil, err
+	}
+	for i := len(v.lateral) - 1; i >= 0; i-- {
+		frame := &v.lateral[i]
+		r := sqlbase.ColumnResolver{Sources: sqlbase.MultiSourceInfo{frame.info}}
+		if _, lateralErr := c.Resolve(context.TODO(), &r); lateralErr != nil {
+			if isUndefinedNameError(lateralErr) {
+				continue
+			}
+			return nil, lateralErr
+		}
+		return frame.columnValue(r.ResolverState.ColIdx), nil
+	}
+	return nil, err
+}
+
+// applyJoinPlanRightFn creates the plan of the right side of an apply join
+// for the given row of the left side.
+type applyJoinPlanRightFn func(params runParams, leftRow tree.Datums) (planNode, error)
+
+// applyJoinNode is a planNode that implements a join whose right side
+// refers to the columns of its left side (a LATERAL subquery or
+// set-returning function). The right side is planned and run again for every
+// row of the left side, with the references to the left side replaced by the
+// values of that row.
+type applyJoinNode struct {
+	joinType sqlbase.JoinType
+
+	// left is the left data source.
+	left planDataSource
+
+	// right is the right data source planned with the references to the left
+	// side replaced by NULLs. It determines the columns of the right side and
+	// is shown by EXPLAIN, but it is never run.
+	right planDataSource
+
+	// planRightFn creates the plan of the right side for a row of the left
+	// side.
+	planRightFn applyJoinPlanRightFn
+
+	// pred represents the join predicate.
+	pred *joinPredicate
+
+	// columns contains the metadata for the results of this node.
+	columns sqlbase.ResultColumns
+
+	run applyJoinRun
+}
+
+// applyJoinRun contains the run-time state of applyJoinNode during local
+// execution.
+type applyJoinRun struct {
+	// leftRow is the current row of the left side.
+	leftRow tree.Datums
+	// rightPlan is the plan of the right side for leftRow, or nil if the next
+	// row of the left side must be read.
+	rightPlan planNode
+	// matched is set once a row of the right side matched leftRow.
+	matched bool
+
+	// output contains the last generated row of results from this node.
+	output tree.Datums
+	// emptyRight contains NULL values to use on the right for left outer joins
+	// when no row matches the current row of the left side.
+	emptyRight tree.Datums
+}
+
+// makeApplyJoin constructs a planDataSource for a join with a LATERAL right
+// side. Only inner and left outer joins are supported.
+func (p *planner) makeApplyJoin(
+	ctx context.Context,
+	joinType sqlbase.JoinType,
+	left planDataSource,
+	right tree.TableExpr,
+	cond tree.JoinCond,
+	scanVisibility scanVisibility,
+) (planDataSource, error) {
+	if joinType != sqlbase.InnerJoin && joinType != sqlbase.LeftOuterJoin {
+		return planDataSource{}, pgerror.NewErrorf(pgerror.CodeInvalidColumnReferenceError,
+			"the combining JOIN type must be INNER or LEFT for a LATERAL reference")
+	}
+
+	cteSnapshot := append(cteNameEnvironment(nil), p.curPlan.cteNameEnvironment...)
+	lateralSnapshot := p.curPlan.lateralEnvironment
+	planRight := func(ctx context.Context, p *planner, leftRow tree.Datums) (planDataSource, error) {
+		defer func(lateral lateralEnvironment) { p.curPlan.lateralEnvironment = lateral }(p.curPlan.lateralEnvironment)
+		p.curPlan.lateralEnvironment = append(
+			lateralSnapshot[:len(lateralSnapshot):len(lateralSnapshot)],
+			lateralFrame{info: left.info, row: leftRow},
+		)
+		return p.getDataSource(ctx, right, nil /* hints */, scanVisibility)
+	}
+
+	rightSrc, err := planRight(ctx, p, nil /* leftRow */)
+	if err != nil {
+		return planDataSource{}, err
+	}
+	if err := checkJoinSourceNames(left, rightSrc); err != nil {
+		rightSrc.plan.Close(ctx)
+		return planDataSource{}, err
+	}
+	pred, usingColumns, err := p.makeJoinPredicate(ctx, left.info, rightSrc.info, joinType, cond)
+	if err != nil {
+		rightSrc.plan.Close(ctx)
+		return planDataSource{}, err
+	}
+
+	n := p.makeApplyJoinNode(left, rightSrc, pred)
+	// firstSubquery and endSubquery delimit the subqueries added to the
+	// top-level plan by the last plan of the right side.
+	var firstSubquery, endSubquery int
+	n.planRightFn = func(params runParams, leftRow tree.Datums) (planNode, error) {
+		p := params.p
+		defer func(env cteNameEnvironment) { p.curPlan.cteNameEnvironment = env }(p.curPlan.cteNameEnvironment)
+		p.curPlan.cteNameEnvironment = cteSnapshot
+
+		// Subqueries in the right side are collected into the top-level plan
+		// and must be prepared before the right side can start. The subqueries
+		// of the previous row, whose plan is closed, are not needed anymore.
+		p.curPlan.releaseSubqueries(params.ctx, firstSubquery, endSubquery)
+		firstSubquery = len(p.curPlan.subqueryPlans)
+		src, err := planRight(params.ctx, p, leftRow)
+		endSubquery = len(p.curPlan.subqueryPlans)
+		if err != nil {
+			return nil, err
+		}
+		plan, err := p.optimizeDeferredPlan(params, src.plan, firstSubquery)
+		if err != nil {
+			plan.Close(params.ctx)
+			return nil, err
+		}
+		return plan, nil
+	}
+	return p.mergeJoinColumns(planDataSource{info: pred.info, plan: n}, left, rightSrc, pred, usingColumns)
+}
+
+func (p *planner) makeApplyJoinNode(
+	left planDataSource, right planDataSource, pred *joinPredicate,
+) *applyJoinNode {
+	return &applyJoinNode{
+		joinType: pred.joinType,
+		left:     left,
+		right:    right,
+		pred:     pred,
+		columns:  pred.info.SourceColumns,
+	}
+}
+
+func (n *applyJoinNode) startExec(params runParams) error {
+	n.run.output = make(tree.Datums, len(n.columns))
+	if n.joinType == sqlbase.LeftOuterJoin {
+		n.run.emptyRight = make(tree.Datums, len(planColumns(n.right.plan)))
+		for i := range n.run.emptyRight {
+			n.run.emptyRight[i] = tree.DNull
+		}
+	}
+	return startExec(params, n.left.plan)
+}
+
+func (n *applyJoinNode) Next(params runParams) (bool, error) {
+	for {
+		if err := params.p.cancelChecker.Check(); err != nil {
+			return false, err
+		}
+
+		if n.run.rightPlan == nil {
+			leftHasRow, err := n.left.plan.Next(params)
+			if err != nil || !leftHasRow {
+				return false, err
+			}
+			n.run.leftRow = n.left.plan.Values()
+			n.run.matched = false
+			rightPlan, err := n.planRightFn(params, n.run.leftRow)
+			if err != nil {
+				return false, err
+			}
+			n.run.rightPlan = rightPlan
+			if err := startPlan(params, rightPlan); err != nil {
+				return false, err
+			}
+		}
+
+		rightHasRow, err := n.run.rightPlan.Next(params)
+		if err != nil {
+			return false, err
+		}
+		if rightHasRow {
+			rightRow := n.run.rightPlan.Values()
+			match, err := n.matches(params.EvalContext(), n.run.leftRow, rightRow)
+			if err != nil {
+				return false, err
+			}
+			if match {
+				n.run.matched = true
+				n.pred.prepareRow(n.run.output, n.run.leftRow, rightRow)
+				return true, nil
+			}
+			continue
+		}
+
+		// The right side is exhausted for the current row of the left side.
+		n.run.rightPlan.Close(params.ctx)
+		n.run.rightPlan = nil
+		if !n.run.matched && n.joinType == sqlbase.LeftOuterJoin {
+			// Left outer join: unmatched rows are padded with NULLs.
+			n.pred.prepareRow(n.run.output, n.run.leftRow, n.run.emptyRight)
+			return true, nil
+		}
+	}
+}
+
+// matches returns true if the given rows pass the equality constraints and the
+// ON condition of the join predicate.
+func (n *applyJoinNode) matches(
+	evalCtx *tree.EvalContext, leftRow, rightRow tree.Datums,
+) (bool, error) {
+	for i, leftIdx := range n.pred.leftEqualityIndices {
+		l, r := leftRow[leftIdx], rightRow[n.pred.rightEqualityIndices[i]]
+		if l == tree.DNull || r == tree.DNull || l.Compare(evalCtx, r) != 0 {
+			return false, nil
+		}
+	}
+	return n.pred.eval(evalCtx, n.run.output, leftRow, rightRow)
+}
+
+func (n *applyJoinNode) Values() tree.Datums {
+	return n.run.output
+}
+
+func (n *applyJoinNode) Close(ctx context.Context) {
+	if n.run.rightPlan != nil {
+		n.run.rightPlan.Close(ctx)
+		n.run.rightPlan = nil
+	}
+	n.right.plan.Close(ctx)
+	n.left.plan.Close(ctx)
+}
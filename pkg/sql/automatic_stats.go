@@ -0,0 +1,429 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/jobs"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+)
+
+// This file implements the automatic collection of table statistics.
+//
+// Each node counts the rows modified by the INSERT, UPDATE, UPSERT and
+// DELETE statements it executes. Every statsRefreshInterval, for each
+// table it modified, the node starts a CREATE STATS job that collects new
+// statistics on the columns of the table with a probability proportional
+// to the number of rows it modified, relative to the number of rows that
+// make the statistics stale. A node only knows about its own mutations,
+// and forgets them when it restarts; since the probabilities of all the
+// nodes add up, a refresh is still expected once enough rows have been
+// modified in total, however the mutations are spread across the
+// cluster.
+//
+// The job takes a lease on the table before collecting statistics, so at
+// most one job refreshes the statistics of a table at any time. A job
+// that cannot take the lease has nothing left to do and succeeds
+// immediately.
+
+// autoStatsName is the name of the statistics collected automatically.
+const autoStatsName = "__auto__"
+
+var automaticStatsEnabled = settings.RegisterBoolSetting(
+	"sql.stats.automatic_collection.enabled",
+	"refresh table statistics automatically when enough rows have been modified",
+	true,
+)
+
+var automaticStatsFractionStaleRows = settings.RegisterNonNegativeFloatSetting(
+	"sql.stats.automatic_collection.fraction_stale_rows",
+	"fraction of the rows of a table that must be modified to trigger a statistics refresh",
+	0.2,
+)
+
+var automaticStatsMinStaleRows = settings.RegisterValidatedIntSetting(
+	"sql.stats.automatic_collection.min_stale_rows",
+	"number of rows of a table that must be modified, in addition to the stale fraction, to trigger a statistics refresh",
+	500,
+	func(v int64) error {
+		if v < 0 {
+			return errors.Errorf("cannot set sql.stats.automatic_collection.min_stale_rows to a negative value: %d", v)
+		}
+		return nil
+	},
+)
+
+// statsMutationBufferSize is the number of mutation notifications that
+// can be queued before new notifications are dropped.
+const statsMutationBufferSize = 256
+
+// statsRefreshInterval is the interval at which a node considers
+// refreshing the statistics of the tables it modified.
+const statsRefreshInterval = 5 * time.Second
+
+const (
+	// statsRefreshLeaseDuration is the duration of the lease taken by a
+	// statistics job on its table.
+	statsRefreshLeaseDuration = time.Minute
+	// statsRefreshLeaseExtendInterval is the interval at which a running
+	// statistics job extends its lease.
+	statsRefreshLeaseExtendInterval = statsRefreshLeaseDuration / 5
+)
+
+// tableMutation is a notification that rows of a table were modified.
+type tableMutation struct {
+	tableID      sqlbase.ID
+	rowsAffected int
+}
+
+// StatsRefresher starts automatic statistics jobs for the tables that
+// were modified enough by the statements executed on this node.
+type StatsRefresher struct {
+	execCfg   *ExecutorConfig
+	mutations chan tableMutation
+}
+
+// NewStatsRefresher creates a StatsRefresher.
+func NewStatsRefresher(execCfg *ExecutorConfig) *StatsRefresher {
+	return &StatsRefresher{
+		execCfg:   execCfg,
+		mutations: make(chan tableMutation, statsMutationBufferSize),
+	}
+}
+
+// NotifyMutation records that rowsAffected rows of the given table were
+// modified. It never blocks: the notification is dropped if the
+// refresher is lagging behind, which only delays the next refresh.
+func (r *StatsRefresher) NotifyMutation(tableID sqlbase.ID, rowsAffected int) {
+	if rowsAffected == 0 || !automaticStatsEnabled.Get(&r.execCfg.Settings.SV) {
+		return
+	}
+	select {
+	case r.mutations <- tableMutation{tableID: tableID, rowsAffected: rowsAffected}:
+	default:
+	}
+}
+
+// Start runs the refresher until the stopper stops.
+func (r *StatsRefresher) Start(ctx context.Context, stopper *stop.Stopper) {
+	stopper.RunWorker(ctx, func(ctx context.Context) {
+		// mutationCounts holds the number of rows modified in each table
+		// since the last tick.
+		mutationCounts := make(map[sqlbase.ID]int64)
+		ticker := time.NewTicker(statsRefreshInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case m := <-r.mutations:
+				mutationCounts[m.tableID] += int64(m.rowsAffected)
+			case <-ticker.C:
+				for tableID, mutations := range mutationCounts {
+					r.maybeRefreshStats(ctx, tableID, mutations)
+				}
+				mutationCounts = make(map[sqlbase.ID]int64)
+			case <-stopper.ShouldStop():
+				return
+			}
+		}
+	})
+}
+
+// maybeRefreshStats starts a statistics job for the given table with a
+// probability of mutations/staleRows, where staleRows is the number of
+// rows that must be modified to make its statistics stale. The row count
+// is read from system.table_statistics rather than from the statistics
+// cache, which may not have caught up with a refresh started by another
+// node.
+func (r *StatsRefresher) maybeRefreshStats(
+	ctx context.Context, tableID sqlbase.ID, mutations int64,
+) {
+	ie := InternalExecutor{ExecCfg: r.execCfg}
+	rows, _ /* cols */, err := ie.QueryRows(ctx, "get-stats-row-count",
+		`SELECT "rowCount" FROM system.table_statistics
+		 WHERE "tableID" = $1 ORDER BY "createdAt" DESC LIMIT 1`,
+		tableID)
+	if err != nil {
+		log.Warningf(ctx, "failed to get the row count of table %d: %s", tableID, err)
+		return
+	}
+	var rowCount int64
+	if len(rows) > 0 {
+		rowCount = int64(tree.MustBeDInt(rows[0][0]))
+	}
+
+	sv := &r.execCfg.Settings.SV
+	staleRows := automaticStatsFractionStaleRows.Get(sv)*float64(rowCount) +
+		float64(automaticStatsMinStaleRows.Get(sv))
+	if rand.Float64()*staleRows >= float64(mutations) {
+		return
+	}
+	if err := startAutomaticStatsJob(ctx, r.execCfg, tableID); err != nil {
+		log.Warningf(ctx, "failed to start statistics job for table %d: %s", tableID, err)
+	}
+}
+
+// notifyMutatedTable notifies the stats refresher of the rows modified
+// by the given plan, if it is a mutation of a user table.
+func notifyMutatedTable(execCfg *ExecutorConfig, plan planNode, rowsAffected int) {
+	if execCfg.StatsRefresher == nil {
+		return
+	}
+	var desc *sqlbase.TableDescriptor
+	switch n := plan.(type) {
+	case *insertNode:
+		desc = n.tableDesc
+	case *updateNode:
+		desc = n.tableDesc
+	case *upsertNode:
+		desc = n.tableDesc
+	case *deleteNode:
+		desc = n.tableDesc
+	case *spoolNode:
+		notifyMutatedTable(execCfg, n.source, rowsAffected)
+		return
+	}
+	if desc == nil || sqlbase.IsReservedID(desc.ID) {
+		return
+	}
+	execCfg.StatsRefresher.NotifyMutation(desc.ID, rowsAffected)
+}
+
+// startAutomaticStatsJob starts a job that refreshes the statistics of
+// the given table.
+func startAutomaticStatsJob(ctx context.Context, execCfg *ExecutorConfig, tableID sqlbase.ID) error {
+	var tn tree.TableName
+	var dropped bool
+	if err := execCfg.DB.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
+		desc, err := sqlbase.GetTableDescFromID(ctx, txn, tableID)
+		if err != nil {
+			return err
+		}
+		if dropped = desc.Dropped(); dropped {
+			return nil
+		}
+		dbDesc, err := sqlbase.GetDatabaseDescFromID(ctx, txn, desc.ParentID)
+		if err != nil {
+			return err
+		}
+		tn = tree.MakeTableName(tree.Name(dbDesc.Name), tree.Name(desc.Name))
+		return nil
+	}); err != nil || dropped {
+		return err
+	}
+
+	_, _, err := execCfg.JobRegistry.StartJob(ctx, nil /* resultsCh */, jobs.Record{
+		Description:   fmt.Sprintf("CREATE STATISTICS %s FROM %s", autoStatsName, tn.String()),
+		Username:      security.RootUser,
+		DescriptorIDs: sqlbase.IDs{tableID},
+		Details:       jobs.CreateStatsDetails{TableID: tableID},
+	})
+	return err
+}
+
+// createAutomaticStats collects statistics on the columns of the table
+// described by desc, using the planner's transaction. Every visible
+// column gets its own statistic; the leading columns of the indexes,
+// which are the ones most likely to be constrained, also get a
+// histogram. The prefixes of the index columns, which are likely to be
+// constrained together, get a multi-column statistic.
+func (p *planner) createAutomaticStats(ctx context.Context, desc *sqlbase.TableDescriptor) error {
+	indexedCols := make(map[sqlbase.ColumnID]bool)
+	var prefixes [][]sqlbase.ColumnID
+	seenPrefixes := make(map[string]bool)
+	for _, idx := range desc.AllNonDropIndexes() {
+		if idx.Type == sqlbase.IndexDescriptor_INVERTED || len(idx.ColumnIDs) == 0 {
+			continue
+		}
+		indexedCols[idx.ColumnIDs[0]] = true
+		for n := 2; n <= len(idx.ColumnIDs); n++ {
+			prefix := idx.ColumnIDs[:n]
+			if key := fmt.Sprint(prefix); !seenPrefixes[key] {
+				seenPrefixes[key] = true
+				prefixes = append(prefixes, prefix)
+			}
+		}
+	}
+	var requested []requestedStat
+	for _, col := range desc.Columns {
+		if col.Hidden {
+			continue
+		}
+		requested = append(requested, requestedStat{
+			columns:             []sqlbase.ColumnID{col.ID},
+			histogram:           indexedCols[col.ID],
+			histogramMaxBuckets: histogramBuckets,
+			name:                autoStatsName,
+		})
+	}
+	for _, prefix := range prefixes {
+		requested = append(requested, requestedStat{
+			columns: prefix,
+			name:    autoStatsName,
+		})
+	}
+	if len(requested) == 0 {
+		return nil
+	}
+
+	execCfg := p.ExecCfg()
+	dsp := execCfg.DistSQLPlanner
+	planCtx := dsp.newPlanningCtx(ctx, p.ExtendedEvalContext(), p.txn)
+	plan, err := dsp.createStatsPlan(&planCtx, desc, requested)
+	if err != nil {
+		return err
+	}
+	dsp.FinalizePlan(&planCtx, &plan)
+
+	rw := &errOnlyResultWriter{}
+	recv := makeDistSQLReceiver(
+		ctx,
+		rw,
+		tree.DDL,
+		execCfg.RangeDescriptorCache,
+		execCfg.LeaseHolderCache,
+		p.txn,
+		func(ts hlc.Timestamp) {
+			_ = execCfg.Clock.Update(ts)
+		},
+	)
+	dsp.Run(&planCtx, p.txn, &plan, recv, p.ExtendedEvalContext())
+	return rw.Err()
+}
+
+type createStatsResumer struct{}
+
+var _ jobs.Resumer = &createStatsResumer{}
+
+// Resume is part of the jobs.Resumer interface.
+func (r *createStatsResumer) Resume(
+	ctx context.Context, job *jobs.Job, phs interface{}, resultsCh chan<- tree.Datums,
+) error {
+	details := job.Record.Details.(jobs.CreateStatsDetails)
+	execCfg := phs.(PlanHookState).ExecCfg()
+
+	// Several nodes may start a job for the same table at the same time.
+	// Only the job that takes the lease refreshes the statistics. Each job
+	// uses its own lease manager, so that two jobs on the same node don't
+	// share the lease.
+	leaseMgr := client.NewLeaseManager(execCfg.DB, execCfg.Clock, client.LeaseManagerOptions{
+		LeaseDuration: statsRefreshLeaseDuration,
+	})
+	lease, err := leaseMgr.AcquireLease(ctx, keys.StatsRefreshLeaseKey(uint32(details.TableID)))
+	if err != nil {
+		if _, ok := err.(*client.LeaseNotAvailableError); ok {
+			log.Infof(ctx, "statistics of table %d are already being refreshed", details.TableID)
+			return nil
+		}
+		return err
+	}
+	extenderDone := make(chan struct{})
+	stopExtender := make(chan struct{})
+	go func() {
+		defer close(extenderDone)
+		ticker := time.NewTicker(statsRefreshLeaseExtendInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := leaseMgr.ExtendLease(ctx, lease); err != nil {
+					log.Warningf(ctx, "unable to extend the statistics lease of table %d: %s",
+						details.TableID, err)
+				}
+			case <-stopExtender:
+				return
+			}
+		}
+	}()
+	defer func() {
+		close(stopExtender)
+		<-extenderDone
+		if err := leaseMgr.ReleaseLease(ctx, lease); err != nil {
+			log.Warningf(ctx, "unable to release the statistics lease of table %d: %s",
+				details.TableID, err)
+		}
+	}()
+
+	if err := execCfg.DB.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
+		desc, err := sqlbase.GetTableDescFromID(ctx, txn, details.TableID)
+		if err != nil {
+			return err
+		}
+		if desc.Dropped() {
+			return nil
+		}
+		p, cleanup := newInternalPlanner(
+			"create-stats", txn, security.RootUser, execCfg.LeaseManager.memMetrics, execCfg)
+		defer cleanup()
+		return p.createAutomaticStats(ctx, desc)
+	}); err != nil {
+		return err
+	}
+
+	// The statistics are inserted in a single transaction, so they all
+	// have the latest creation time. Older automatic statistics, including
+	// those inserted by an attempt of the transaction above that was
+	// retried, are superseded.
+	ie := InternalExecutor{ExecCfg: execCfg}
+	_, err = ie.ExecuteStatement(ctx, "delete-stale-stats",
+		`DELETE FROM system.table_statistics
+		 WHERE "tableID" = $1 AND name = $2 AND "createdAt" < (
+		   SELECT max("createdAt") FROM system.table_statistics WHERE "tableID" = $1 AND name = $2
+		 )`,
+		details.TableID, autoStatsName)
+	invalidateTableStats(ctx, execCfg, details.TableID)
+	return err
+}
+
+// OnSuccess is part of the jobs.Resumer interface.
+func (r *createStatsResumer) OnSuccess(context.Context, *client.Txn, *jobs.Job) error {
+	return nil
+}
+
+// OnTerminal is part of the jobs.Resumer interface.
+func (r *createStatsResumer) OnTerminal(
+	context.Context, *jobs.Job, jobs.Status, chan<- tree.Datums,
+) {
+}
+
+// OnFailOrCancel is part of the jobs.Resumer interface.
+func (r *createStatsResumer) OnFailOrCancel(context.Context, *client.Txn, *jobs.Job) error {
+	return nil
+}
+
+func createStatsResumeHook(typ jobs.Type, _ *cluster.Settings) jobs.Resumer {
+	if typ != jobs.TypeCreateStats {
+		return nil
+	}
+	return &createStatsResumer{}
+}
+
+func init() {
+	jobs.AddResumeHook(createStatsResumeHook)
+}
@@ -0,0 +1,122 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+func TestAutomaticStatsRefresh(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	s, db, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(context.TODO())
+	sqlDB := sqlutils.MakeSQLRunner(db)
+
+	sqlDB.Exec(t, `SET CLUSTER SETTING sql.stats.automatic_collection.min_stale_rows = 10`)
+	sqlDB.Exec(t, `CREATE DATABASE t`)
+	sqlDB.Exec(t, `CREATE TABLE t.a (k INT PRIMARY KEY, v INT, INDEX (v))`)
+
+	countStats := func() int {
+		var count int
+		sqlDB.QueryRow(t,
+			`SELECT count(*) FROM system.table_statistics WHERE name = $1`, autoStatsName,
+		).Scan(&count)
+		return count
+	}
+
+	// The statistics of the table are already being refreshed: the job
+	// started by the refresher has nothing to do.
+	leaseMgr := client.NewLeaseManager(s.DB(), s.Clock(), client.LeaseManagerOptions{})
+	var tableID uint32
+	sqlDB.QueryRow(t, `SELECT id FROM system.namespace WHERE name = 'a'`).Scan(&tableID)
+	lease, err := leaseMgr.AcquireLease(context.TODO(), keys.StatsRefreshLeaseKey(tableID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.Exec(t, `INSERT INTO t.a SELECT generate_series(1, 100), 1`)
+	testutils.SucceedsSoon(t, func() error {
+		var count int
+		sqlDB.QueryRow(t,
+			`SELECT count(*) FROM [SHOW JOBS] WHERE type = 'CREATE STATS' AND status = 'succeeded'`,
+		).Scan(&count)
+		if count == 0 {
+			return errors.New("expected the statistics job to succeed")
+		}
+		return nil
+	})
+	if count := countStats(); count != 0 {
+		t.Fatalf("expected no automatic statistics, got %d", count)
+	}
+	if err := leaseMgr.ReleaseLease(context.TODO(), lease); err != nil {
+		t.Fatal(err)
+	}
+
+	sqlDB.Exec(t, `UPDATE t.a SET v = 2`)
+	testutils.SucceedsSoon(t, func() error {
+		// One statistic per column.
+		if count := countStats(); count != 2 {
+			return errors.Errorf("expected 2 automatic statistics, got %d", count)
+		}
+		return nil
+	})
+
+	var rowCount int
+	sqlDB.QueryRow(t,
+		`SELECT DISTINCT "rowCount" FROM system.table_statistics WHERE name = $1`, autoStatsName,
+	).Scan(&rowCount)
+	if rowCount != 100 {
+		t.Fatalf("expected a row count of 100, got %d", rowCount)
+	}
+
+	// The refresh is visible in SHOW JOBS.
+	testutils.SucceedsSoon(t, func() error {
+		var status string
+		sqlDB.QueryRow(t,
+			`SELECT status FROM [SHOW JOBS] WHERE type = 'CREATE STATS' ORDER BY created DESC LIMIT 1`,
+		).Scan(&status)
+		if status != "succeeded" {
+			return errors.Errorf("expected the statistics job to succeed, got %s", status)
+		}
+		return nil
+	})
+
+	// A second refresh replaces the statistics instead of accumulating them.
+	sqlDB.Exec(t, `DELETE FROM t.a WHERE k > 50`)
+	testutils.SucceedsSoon(t, func() error {
+		var rowCount int
+		sqlDB.QueryRow(t,
+			`SELECT max("rowCount") FROM system.table_statistics WHERE name = $1`, autoStatsName,
+		).Scan(&rowCount)
+		if rowCount != 50 {
+			return errors.Errorf("expected a row count of 50, got %d", rowCount)
+		}
+		return nil
+	})
+	if count := countStats(); count != 2 {
+		t.Fatalf("expected 2 automatic statistics, got %d", count)
+	}
+}
@@ -113,6 +113,7 @@ func (sc *SchemaChanger) runBackfill(
 	// mutations. Collect the elements that are part of the mutation.
 	var droppedIndexDescs []sqlbase.IndexDescriptor
 	var addedIndexDescs []sqlbase.IndexDescriptor
+	var addedConstraints []sqlbase.ConstraintToUpdate
 	// Indexes within the Mutations slice for checkpointing.
 	mutationSentinel := -1
 	var droppedIndexMutationIdx int
@@ -129,6 +130,9 @@ func (sc *SchemaChanger) runBackfill(
 	if tableDesc.Dropped() {
 		return nil
 	}
+	if err := sc.checkCanceled(ctx, tableDesc); err != nil {
+		return err
+	}
 	version := tableDesc.Version
 
 	log.VEventf(ctx, 0, "Running backfill for %q, v=%d, m=%d",
@@ -144,11 +148,19 @@ func (sc *SchemaChanger) runBackfill(
 			switch t := m.Descriptor_.(type) {
 			case *sqlbase.DescriptorMutation_Column:
 				desc := m.GetColumn()
-				if desc.DefaultExpr != nil || !desc.Nullable {
+				// The values of a virtual column aren't stored, so it only
+				// needs a backfill to validate that it isn't NULL.
+				if desc.Virtual {
+					if !desc.Nullable {
+						needColumnBackfill = true
+					}
+				} else if desc.DefaultExpr != nil || !desc.Nullable || desc.IsComputed() {
 					needColumnBackfill = true
 				}
 			case *sqlbase.DescriptorMutation_Index:
 				addedIndexDescs = append(addedIndexDescs, *t.Index)
+			case *sqlbase.DescriptorMutation_Constraint:
+				addedConstraints = append(addedConstraints, *t.Constraint)
 			default:
 				return errors.Errorf("unsupported mutation: %+v", m)
 			}
@@ -162,6 +174,9 @@ func (sc *SchemaChanger) runBackfill(
 				if droppedIndexMutationIdx == mutationSentinel {
 					droppedIndexMutationIdx = i
 				}
+			case *sqlbase.DescriptorMutation_Constraint:
+				// Nothing to do: the constraint is removed when the mutation
+				// is made complete.
 			default:
 				return errors.Errorf("unsupported mutation: %+v", m)
 			}
@@ -196,6 +211,12 @@ func (sc *SchemaChanger) runBackfill(
 		}
 	}
 
+	// Validate the existing rows against the added constraints, which are
+	// already enforced on writes.
+	if err := sc.validateNotNullConstraints(ctx, tableDesc, addedConstraints); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -477,6 +498,9 @@ func (sc *SchemaChanger) distBackfill(
 		if len(spans) <= 0 {
 			break
 		}
+		if err := sc.checkCanceled(ctx, tableDesc); err != nil {
+			return err
+		}
 
 		if err := sc.ExtendLease(ctx, lease); err != nil {
 			return err
@@ -46,7 +46,7 @@ func (p *planner) validateCheckExpr(
 	// use the tableDesc we have, but this is a rare operation and be benefit
 	// would be marginal compared to the work of the actual query, so the added
 	// complexity seems unjustified.
-	rows, err := p.SelectClause(ctx, sel, nil, lim, nil, nil, publicColumns)
+	rows, err := p.SelectClause(ctx, sel, nil, lim, nil, nil, nil, publicColumns)
 	if err != nil {
 		return err
 	}
@@ -111,6 +111,26 @@ func (p *planner) validateForeignKey(
 		where[i] = fmt.Sprintf("(%s IS NOT NULL AND %s IS NULL)", srcCols[i], targetCols[i])
 	}
 
+	if srcIdx.ForeignKey.Match == sqlbase.ForeignKeyReference_FULL && prefix > 1 {
+		// A MATCH FULL foreign key doesn't allow a mix of null and nonnull values.
+		someNull, someNonNull := make([]string, prefix), make([]string, prefix)
+		for i := 0; i < prefix; i++ {
+			someNull[i] = fmt.Sprintf("%s IS NULL", srcCols[i])
+			someNonNull[i] = fmt.Sprintf("%s IS NOT NULL", srcCols[i])
+		}
+		query := fmt.Sprintf(`SELECT %s FROM %s@%s AS s WHERE (%s) AND (%s) LIMIT 1`,
+			strings.Join(srcCols, ", "), srcName, tree.NameString(srcIdx.Name),
+			strings.Join(someNull, " OR "), strings.Join(someNonNull, " OR "),
+		)
+		values, _ /* cols */, err := p.queryRows(ctx, query)
+		if err != nil {
+			return err
+		}
+		if len(values) > 0 {
+			return sqlbase.NewMatchFullViolationError(srcIdx.ColumnNames[:prefix])
+		}
+	}
+
 	query := fmt.Sprintf(
 		`SELECT %s FROM %s@%s AS s LEFT OUTER JOIN %s@%s AS t ON %s WHERE %s LIMIT 1`,
 		strings.Join(srcCols, ", "),
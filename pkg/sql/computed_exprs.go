@@ -80,10 +80,6 @@ func ProcessComputedColumns(
 	txCtx *transform.ExprTransformContext,
 	evalCtx *tree.EvalContext,
 ) ([]sqlbase.ColumnDescriptor, []sqlbase.ColumnDescriptor, []tree.TypedExpr, error) {
-	// TODO(justin): the DEFAULT version of this code also had code to handle
-	// mutations. We don't support adding computed columns yet, but we will need
-	// to add that back in.
-
 	// TODO(justin): is there a way we can somehow cache this property on the
 	// table descriptor so we don't have to iterate through all of these?
 	haveComputed := false
@@ -94,6 +90,17 @@ func ProcessComputedColumns(
 			haveComputed = true
 		}
 	}
+	// Also add any column being added in a mutation that is
+	// DELETE_AND_WRITE_ONLY and is computed, such as the shadow column of an
+	// ALTER COLUMN ... SET DATA TYPE.
+	for _, m := range tableDesc.Mutations {
+		if col := m.GetColumn(); col != nil && col.IsComputed() &&
+			m.Direction == sqlbase.DescriptorMutation_ADD &&
+			m.State == sqlbase.DescriptorMutation_DELETE_AND_WRITE_ONLY {
+			cols = append(cols, *col)
+			haveComputed = true
+		}
+	}
 
 	// If this table has no computed columns, don't bother.
 	if !haveComputed {
@@ -560,6 +560,14 @@ func (ex *connExecutor) close(ctx context.Context, closeType closeType) {
 		log.Warningf(ctx, "error while cleaning up connExecutor: %s", err)
 	}
 
+	// Drop the session's temporary objects. If this fails, the
+	// TemporarySchemaCleaner will try again later.
+	if tempSchemaName := ex.sessionData.SearchPath.GetTemporarySchemaName(); tempSchemaName != "" {
+		if err := dropTemporarySchema(ctx, ex.server.cfg, tempSchemaName); err != nil {
+			log.Warningf(ctx, "error while dropping temporary schema %s: %s", tempSchemaName, err)
+		}
+	}
+
 	if closeType == normalClose {
 		// Close all statements and prepared portals by first unifying the namespaces
 		// and the closing what remains.
@@ -1536,7 +1544,9 @@ func (ex *connExecutor) readWriteModeWithSessionDefault(
 func (ex *connExecutor) evalCtx(p *planner, stmtTS time.Time) extendedEvalContext {
 	txn := ex.state.mu.txn
 
-	scInterface := newSchemaInterface(&ex.extraTxnState.tables, ex.server.cfg.VirtualSchemas)
+	tempSchemaName := temporarySchemaName(ex.sessionID)
+	scInterface := newSchemaInterface(
+		&ex.extraTxnState.tables, ex.server.cfg.VirtualSchemas, tempSchemaName)
 
 	return extendedEvalContext{
 		EvalContext: tree.EvalContext{
@@ -1570,6 +1580,8 @@ func (ex *connExecutor) evalCtx(p *planner, stmtTS time.Time) extendedEvalContex
 		TxnModesSetter:  ex,
 		SchemaChangers:  &ex.extraTxnState.schemaChangers,
 		schemaAccessors: scInterface,
+
+		TemporarySchemaName: tempSchemaName,
 	}
 }
 
@@ -1688,7 +1700,12 @@ func (ex *connExecutor) txnStateTransitionsApplyWrapper(
 			res.SetError(sqlbase.NewStatementCompletionUnknownError(schemaChangeErr))
 		}
 		fallthrough
-	case txnRestart, txnAborted:
+	case txnAborted:
+		// The effects of SET LOCAL end with the transaction, whether it
+		// committed or not.
+		ex.dataMutator.revertLocalOverrides()
+		fallthrough
+	case txnRestart:
 		if err := ex.resetExtraTxnState(ex.Ctx(), advInfo.txnEvent, ex.server.dbCache); err != nil {
 			return advanceInfo{}, err
 		}
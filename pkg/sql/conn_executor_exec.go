@@ -509,6 +509,9 @@ func (ex *connExecutor) execStmtInParallel(
 		recordStatementSummary(
 			planner, stmt, false /* distSQLUsed*/, ex.extraTxnState.autoRetryCounter,
 			res.RowsAffected(), err, &ex.server.EngineMetrics)
+		if err == nil && res.Err() == nil {
+			notifyMutatedTable(ex.server.cfg, planner.curPlan.plan, res.RowsAffected())
+		}
 		if ex.server.cfg.TestingKnobs.AfterExecute != nil {
 			ex.server.cfg.TestingKnobs.AfterExecute(ctx, stmt.String(), res.Err())
 		}
@@ -609,6 +612,9 @@ func (ex *connExecutor) dispatchToExecutionEngine(
 		planner, stmt, useDistSQL, ex.extraTxnState.autoRetryCounter,
 		res.RowsAffected(), res.Err(), &ex.server.EngineMetrics,
 	)
+	if res.Err() == nil {
+		notifyMutatedTable(ex.server.cfg, planner.curPlan.plan, res.RowsAffected())
+	}
 	if ex.server.cfg.TestingKnobs.AfterExecute != nil {
 		ex.server.cfg.TestingKnobs.AfterExecute(ctx, stmt.String(), res.Err())
 	}
@@ -692,6 +698,9 @@ func (ex *connExecutor) execWithDistSQLEngine(
 	ex.server.cfg.DistSQLPlanner.PlanAndRun(
 		ctx, planner.txn, planner.curPlan.plan, recv, planner.ExtendedEvalContext(),
 	)
+	if res.Err() == nil {
+		invalidateCreatedStats(ctx, ex.server.cfg, planner.curPlan.plan)
+	}
 	return recv.commErr
 }
 
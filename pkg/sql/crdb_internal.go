@@ -300,6 +300,9 @@ CREATE TABLE crdb_internal.schema_changes (
 					mutType = "INDEX"
 					targetID = tree.NewDInt(tree.DInt(int64(d.Index.ID)))
 					targetName = tree.NewDString(d.Index.Name)
+				case *sqlbase.DescriptorMutation_Constraint:
+					mutType = "CONSTRAINT"
+					targetName = tree.NewDString(d.Constraint.Name)
 				}
 				if err := addRow(
 					tableID,
@@ -1190,6 +1193,7 @@ CREATE TABLE crdb_internal.backward_dependencies (
 		viewDep := tree.NewDString("view")
 		sequenceDep := tree.NewDString("sequence")
 		interleaveDep := tree.NewDString("interleave")
+		ownerDep := tree.NewDString("owner")
 		return forEachTableDescAll(ctx, p, prefix, hideVirtual, /* virtual tables have no backward/forward dependencies*/
 			func(db *DatabaseDescriptor, _ string, table *TableDescriptor) error {
 				tableID := tree.NewDInt(tree.DInt(table.ID))
@@ -1243,14 +1247,18 @@ CREATE TABLE crdb_internal.backward_dependencies (
 					}
 				}
 
-				// Record the view dependencies.
+				// Record the view dependencies, or the owner of a sequence.
+				depType := viewDep
+				if table.IsSequence() {
+					depType = ownerDep
+				}
 				for _, tIdx := range table.DependsOn {
 					if err := addRow(
 						tableID, tableName,
 						tree.DNull,
 						tree.DNull,
 						tree.NewDInt(tree.DInt(tIdx)),
-						viewDep,
+						depType,
 						tree.DNull,
 						tree.DNull,
 						tree.DNull,
@@ -1301,6 +1309,7 @@ CREATE TABLE crdb_internal.forward_dependencies (
 		viewDep := tree.NewDString("view")
 		interleaveDep := tree.NewDString("interleave")
 		sequenceDep := tree.NewDString("sequence")
+		ownerDep := tree.NewDString("owner")
 		return forEachTableDescAll(ctx, p, prefix, hideVirtual, /* virtual tables have no backward/forward dependencies*/
 			func(db *DatabaseDescriptor, _ string, table *TableDescriptor) error {
 				tableID := tree.NewDInt(tree.DInt(table.ID))
@@ -1366,6 +1375,22 @@ CREATE TABLE crdb_internal.forward_dependencies (
 							return err
 						}
 					}
+					// Record the sequences owned by the columns.
+					for _, col := range table.Columns {
+						for _, seqID := range col.OwnsSequenceIds {
+							if err := addRow(
+								tableID, tableName,
+								tree.DNull,
+								tree.NewDInt(tree.DInt(seqID)),
+								ownerDep,
+								tree.DNull,
+								tree.DNull,
+								tree.NewDString(fmt.Sprintf("Columns: [%d]", col.ID)),
+							); err != nil {
+								return err
+							}
+						}
+					}
 				} else if table.IsSequence() {
 					// Record the sequence dependencies.
 					for _, dep := range table.DependedOnBy {
@@ -35,6 +35,10 @@ func (p *planner) CreateDatabase(ctx context.Context, n *tree.CreateDatabase) (p
 		return nil, errEmptyDatabaseName
 	}
 
+	if err := checkNotTemporarySchemaName(string(n.Name)); err != nil {
+		return nil, err
+	}
+
 	if tmpl := n.Template; tmpl != "" {
 		// See https://www.postgresql.org/docs/current/static/manage-ag-templatedbs.html
 		if !strings.EqualFold(tmpl, "template0") {
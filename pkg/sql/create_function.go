@@ -0,0 +1,220 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/types"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+// createFunctionNode represents a CREATE FUNCTION statement.
+type createFunctionNode struct {
+	n      *tree.CreateFunction
+	dbDesc *sqlbase.DatabaseDescriptor
+	// desc is the descriptor of the new function. Its ID is allocated
+	// during execution.
+	desc sqlbase.FunctionDescriptor
+}
+
+// CreateFunction creates a user-defined SQL function.
+// Privileges: CREATE on database.
+//   notes: postgres requires USAGE on the language and CREATE on the
+//          schema.
+func (p *planner) CreateFunction(ctx context.Context, n *tree.CreateFunction) (planNode, error) {
+	name, err := n.Name.Normalize()
+	if err != nil {
+		return nil, err
+	}
+
+	var dbDesc *DatabaseDescriptor
+	p.runWithOptions(resolveFlags{skipCache: true, allowAdding: true}, func() {
+		dbDesc, err = ResolveTargetObject(ctx, p, name)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.CheckPrivilege(ctx, dbDesc, privilege.CREATE); err != nil {
+		return nil, err
+	}
+
+	// Built-in functions take precedence during name resolution, so a
+	// user-defined function with the same name could never be called.
+	if _, ok := tree.FunDefs[name.Table()]; ok {
+		return nil, pgerror.NewErrorf(pgerror.CodeDuplicateFunctionError,
+			"function %q conflicts with a built-in function", tree.ErrString(name))
+	}
+
+	desc := sqlbase.FunctionDescriptor{
+		Name:     name.Table(),
+		ParentID: dbDesc.ID,
+		Params:   make([]sqlbase.FunctionDescriptor_Parameter, len(n.Params)),
+	}
+	for i := range n.Params {
+		typ, err := sqlbase.MakeColumnType(n.Params[i].Type)
+		if err != nil {
+			return nil, err
+		}
+		desc.Params[i] = sqlbase.FunctionDescriptor_Parameter{
+			Name: string(n.Params[i].Name),
+			Type: typ,
+		}
+	}
+	if desc.ReturnType, err = sqlbase.MakeColumnType(n.ReturnType); err != nil {
+		return nil, err
+	}
+
+	stmt, err := parser.ParseOne(n.Body)
+	if err != nil {
+		return nil, err
+	}
+	sel, ok := stmt.(*tree.Select)
+	if !ok {
+		return nil, pgerror.NewErrorf(pgerror.CodeInvalidFunctionDefinitionError,
+			"function body must be a SELECT statement, found %s", stmt.StatementTag())
+	}
+	// Ensure that all the table names are properly qualified, so that
+	// the function does not depend on the database of its callers.
+	if err := p.qualifyTableNames(ctx, sel); err != nil {
+		return nil, err
+	}
+	desc.Body = tree.AsStringWithFlags(sel, tree.FmtParsable)
+
+	if err := p.checkFunctionBody(ctx, &desc); err != nil {
+		return nil, err
+	}
+
+	return &createFunctionNode{n: n, dbDesc: dbDesc, desc: desc}, nil
+}
+
+// checkFunctionBody verifies that the body of a new function can be
+// planned and produces a single column of the function's return type.
+func (p *planner) checkFunctionBody(ctx context.Context, desc *sqlbase.FunctionDescriptor) error {
+	// The body is planned with NULL arguments: this is sufficient to
+	// type check it, since NULL is cast to the type of each parameter.
+	args := make(tree.Exprs, len(desc.Params))
+	for i := range args {
+		args[i] = tree.DNull
+	}
+	v := functionInliner{p: p, ctx: ctx}
+	body, err := v.expandBody(desc, args)
+	if err != nil {
+		return err
+	}
+
+	// As for views, use the most recent versions of the table
+	// descriptors rather than the copies in the lease cache.
+	defer func(prev bool) { p.avoidCachedDescriptors = prev }(p.avoidCachedDescriptors)
+	p.avoidCachedDescriptors = true
+
+	// Request dependency tracking.
+	defer func(prev planDependencies) { p.curPlan.deps = prev }(p.curPlan.deps)
+	p.curPlan.deps = make(planDependencies)
+
+	plan, err := p.Select(ctx, body, nil /* desiredTypes */)
+	if err != nil {
+		return err
+	}
+	// The plan will not be needed further.
+	defer plan.Close(ctx)
+
+	// Functions are permanent, so they cannot outlive the temporary
+	// tables they depend on.
+	for _, dep := range p.curPlan.deps {
+		if dep.desc.Temporary {
+			return pgerror.NewErrorf(pgerror.CodeFeatureNotSupportedError,
+				"cannot create function %q that depends on temporary table %q",
+				desc.Name, tree.ErrNameString(&dep.desc.Name))
+		}
+	}
+
+	cols := planColumns(plan)
+	if len(cols) != 1 {
+		return pgerror.NewErrorf(pgerror.CodeInvalidFunctionDefinitionError,
+			"function body must return a single column, found %d", len(cols))
+	}
+	retType := desc.ReturnType.ToDatumType()
+	if typ := cols[0].Typ; typ != types.Unknown && !typ.Equivalent(retType) {
+		return pgerror.NewErrorf(pgerror.CodeInvalidFunctionDefinitionError,
+			"return type mismatch in function declared to return %s: body returns %s",
+			retType, typ)
+	}
+	return nil
+}
+
+func (n *createFunctionNode) startExec(params runParams) error {
+	if _, ok := n.dbDesc.FindFunction(n.desc.Name); ok {
+		return sqlbase.NewFunctionAlreadyExistsError(n.n.Name.TableName())
+	}
+
+	id, err := GenerateUniqueDescID(params.ctx, params.extendedEvalCtx.ExecCfg.DB)
+	if err != nil {
+		return err
+	}
+	n.desc.ID = id
+
+	// The owner of a function is granted all the privileges on it.
+	n.desc.Privileges = sqlbase.NewDefaultPrivilegeDescriptor()
+	n.desc.Privileges.Grant(params.SessionData().User, privilege.List{privilege.ALL})
+
+	if err := n.desc.Validate(); err != nil {
+		return err
+	}
+
+	n.dbDesc.Functions = append(n.dbDesc.Functions,
+		sqlbase.DatabaseDescriptor_FunctionReference{Name: n.desc.Name, ID: id})
+	if err := n.dbDesc.Validate(); err != nil {
+		return err
+	}
+
+	b := params.p.txn.NewBatch()
+	descKey := sqlbase.MakeDescMetadataKey(id)
+	dbDescKey := sqlbase.MakeDescMetadataKey(n.dbDesc.ID)
+	if params.p.ExtendedEvalContext().Tracing.KVTracingEnabled() {
+		log.VEventf(params.ctx, 2, "CPut %s -> %s", descKey, &n.desc)
+		log.VEventf(params.ctx, 2, "Put %s -> %s", dbDescKey, n.dbDesc)
+	}
+	b.CPut(descKey, sqlbase.WrapDescriptor(&n.desc), nil)
+	b.Put(dbDescKey, sqlbase.WrapDescriptor(n.dbDesc))
+	if err := params.p.txn.Run(params.ctx, b); err != nil {
+		return err
+	}
+
+	// Log Create Function event. This is an auditable log event and is
+	// recorded in the same transaction as the function descriptor.
+	return MakeEventLogger(params.extendedEvalCtx.ExecCfg).InsertEventRecord(
+		params.ctx,
+		params.p.txn,
+		EventLogCreateFunction,
+		int32(id),
+		int32(params.extendedEvalCtx.NodeID),
+		struct {
+			FunctionName string
+			Statement    string
+			User         string
+		}{n.n.Name.TableName().FQString(), n.n.String(), params.SessionData().User},
+	)
+}
+
+func (*createFunctionNode) Next(runParams) (bool, error) { return false, nil }
+func (*createFunctionNode) Values() tree.Datums          { return tree.Datums{} }
+func (*createFunctionNode) Close(context.Context)        {}
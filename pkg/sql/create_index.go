@@ -43,7 +43,8 @@ func (p *planner) CreateIndex(ctx context.Context, n *tree.CreateIndex) (planNod
 	// DDL statements avoid the cache to avoid leases, and can view non-public descriptors.
 	// TODO(vivek): check if the cache can be used.
 	p.runWithOptions(resolveFlags{skipCache: true, allowAdding: true}, func() {
-		tableDesc, err = ResolveExistingObject(ctx, p, tn, true /*required*/, requireTableDesc)
+		tableDesc, err = ResolveExistingObject(
+			ctx, p, tn, true /*required*/, requireTableOrMaterializedViewDesc)
 	})
 	if err != nil {
 		return nil, err
@@ -92,9 +93,31 @@ func (n *createIndexNode) startExec(params runParams) error {
 		indexDesc.Type = sqlbase.IndexDescriptor_INVERTED
 	}
 
-	if err := indexDesc.FillColumns(n.n.Columns); err != nil {
+	tn, err := n.n.Table.Normalize()
+	if err != nil {
 		return err
 	}
+	columns, exprCols, err := makeIndexExprColumns(
+		params.ctx, n.tableDesc, tn, n.n.Columns, &params.p.semaCtx, params.EvalContext(),
+	)
+	if err != nil {
+		return err
+	}
+	if len(exprCols) > 0 && n.n.Inverted {
+		return pgerror.NewError(pgerror.CodeInvalidSQLStatementNameError, "inverted indexes don't support expressions")
+	}
+	if len(exprCols) > 0 && n.tableDesc.MaterializedView() {
+		return pgerror.NewError(pgerror.CodeFeatureNotSupportedError,
+			"indexes on materialized views don't support expressions")
+	}
+	if err := indexDesc.FillColumns(columns); err != nil {
+		return err
+	}
+	// The columns storing the values of the index expressions are backfilled
+	// by the same schema change as the index.
+	for _, col := range exprCols {
+		n.tableDesc.AddColumnMutation(col, sqlbase.DescriptorMutation_ADD)
+	}
 	if n.n.PartitionBy != nil {
 		partitioning, err := CreatePartitioning(params.ctx, params.p.ExecCfg().Settings,
 			params.EvalContext(), n.tableDesc, &indexDesc, n.n.PartitionBy)
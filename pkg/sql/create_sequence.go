@@ -84,6 +84,14 @@ func (n *createSequenceNode) startExec(params runParams) error {
 		return err
 	}
 
+	for _, option := range n.n.Options {
+		if option.Name == tree.SeqOptOwnedBy {
+			if err := params.p.setSequenceOwner(params.ctx, &desc, option.OwnedBy); err != nil {
+				return err
+			}
+		}
+	}
+
 	if err = params.p.createDescriptorWithID(params.ctx, key, id, &desc); err != nil {
 		return err
 	}
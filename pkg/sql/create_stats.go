@@ -20,6 +20,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/stats"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/pkg/errors"
 )
 
@@ -74,3 +76,28 @@ func (p *planner) CreateStatistics(ctx context.Context, n *tree.CreateStats) (pl
 func (*createStatsNode) Next(runParams) (bool, error) { panic("not implemented") }
 func (*createStatsNode) Close(context.Context)        {}
 func (*createStatsNode) Values() tree.Datums          { panic("not implemented") }
+
+// invalidateCreatedStats evicts the cached statistics of the table whose
+// statistics were created by the given plan, if it is a CREATE STATISTICS
+// plan, so that the optimizer uses the new statistics.
+func invalidateCreatedStats(ctx context.Context, execCfg *ExecutorConfig, plan planNode) {
+	if n, ok := plan.(*createStatsNode); ok {
+		invalidateTableStats(ctx, execCfg, n.tableDesc.ID)
+	}
+}
+
+// invalidateTableStats evicts the cached statistics of the given table on
+// this node, and notifies the other nodes through gossip that they must
+// evict them too.
+func invalidateTableStats(ctx context.Context, execCfg *ExecutorConfig, tableID sqlbase.ID) {
+	if execCfg.TableStatsCache != nil {
+		// The gossip callback evicts the statistics on this node as well, but
+		// asynchronously: make the new statistics visible right away.
+		execCfg.TableStatsCache.InvalidateTableStats(ctx, tableID)
+	}
+	if execCfg.Gossip != nil {
+		if err := stats.GossipTableStatAdded(execCfg.Gossip, tableID); err != nil {
+			log.Warningf(ctx, "failed to gossip new statistics for table %d: %s", tableID, err)
+		}
+	}
+}
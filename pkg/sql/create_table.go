@@ -55,15 +55,29 @@ func (p *planner) CreateTable(ctx context.Context, n *tree.CreateTable) (planNod
 	}
 
 	var dbDesc *DatabaseDescriptor
-	p.runWithOptions(resolveFlags{skipCache: true, allowAdding: true}, func() {
-		dbDesc, err = ResolveTargetObject(ctx, p, tn)
-	})
+	if n.Temporary || p.isTemporaryTarget(tn) {
+		// Temporary tables go to the session's temporary schema. That
+		// schema is created on demand in startExec below, so dbDesc may
+		// be nil.
+		n.Temporary = true
+		p.runWithOptions(resolveFlags{skipCache: true, allowAdding: true}, func() {
+			dbDesc, err = p.resolveTemporaryTarget(ctx, tn)
+		})
+	} else {
+		p.runWithOptions(resolveFlags{skipCache: true, allowAdding: true}, func() {
+			dbDesc, err = ResolveTargetObject(ctx, p, tn)
+		})
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	if err := p.CheckPrivilege(ctx, dbDesc, privilege.CREATE); err != nil {
-		return nil, err
+	// The session's user owns its temporary schema, so only a permanent
+	// database needs to be checked.
+	if dbDesc != nil {
+		if err := p.CheckPrivilege(ctx, dbDesc, privilege.CREATE); err != nil {
+			return nil, err
+		}
 	}
 
 	HoistConstraints(n)
@@ -111,6 +125,15 @@ type createTableRun struct {
 }
 
 func (n *createTableNode) startExec(params runParams) error {
+	if n.dbDesc == nil {
+		// This is the first temporary table of the session.
+		dbDesc, err := params.p.createTemporarySchema(params.ctx)
+		if err != nil {
+			return err
+		}
+		n.dbDesc = dbDesc
+	}
+
 	tKey := tableKey{parentID: n.dbDesc.ID, name: n.n.Table.TableName().Table()}
 	key := tKey.Key()
 	if exists, err := descExists(params.ctx, params.p.txn, key); err == nil && exists {
@@ -350,6 +373,7 @@ func HoistConstraints(n *tree.CreateTable) {
 					ToCols:   targetCol,
 					Name:     col.References.ConstraintName,
 					Actions:  col.References.Actions,
+					Match:    col.References.Match,
 				})
 				col.References.Table = tree.NormalizableTableName{}
 			}
@@ -423,12 +447,24 @@ func resolveFK(
 	backrefs map[sqlbase.ID]*sqlbase.TableDescriptor,
 	mode sqlbase.ConstraintValidity,
 ) error {
+	if d.Match == tree.MatchPartial {
+		return pgerror.Unimplemented("match partial", "MATCH PARTIAL is not yet implemented")
+	}
+
 	targetTable := d.Table.TableName()
 
 	target, err := ResolveExistingObject(ctx, sc, targetTable, true /*required*/, requireTableDesc)
 	if err != nil {
 		return err
 	}
+	if target.Temporary != tbl.Temporary {
+		if tbl.Temporary {
+			return pgerror.NewError(pgerror.CodeInvalidTableDefinitionError,
+				"constraints on temporary tables may reference only temporary tables")
+		}
+		return pgerror.NewError(pgerror.CodeInvalidTableDefinitionError,
+			"constraints on permanent tables may reference only permanent tables")
+	}
 	if target.ID == tbl.ID {
 		// When adding a self-ref FK to an _existing_ table, we want to make sure
 		// we edit the same copy.
@@ -561,6 +597,7 @@ func resolveFK(
 		SharedPrefixLen: int32(len(srcCols)),
 		OnDelete:        sqlbase.ForeignKeyReferenceActionValue[d.Actions.Delete],
 		OnUpdate:        sqlbase.ForeignKeyReferenceActionValue[d.Actions.Update],
+		Match:           sqlbase.ForeignKeyReferenceMatchValue[d.Match],
 	}
 
 	if mode == sqlbase.ConstraintValidity_Unvalidated {
@@ -709,6 +746,10 @@ func addInterleave(
 	if err != nil {
 		return err
 	}
+	if parentTable.Temporary != desc.Temporary {
+		return pgerror.NewError(pgerror.CodeInvalidTableDefinitionError,
+			"cannot interleave temporary and permanent tables")
+	}
 	parentIndex := parentTable.PrimaryIndex
 
 	// typeOfIndex is used to give more informative error messages.
@@ -888,6 +929,7 @@ func makeTableDescIfAs(
 		return desc, err
 	}
 	desc = initTableDescriptor(id, parentID, tableName.Table(), creationTime, privileges)
+	desc.Temporary = p.Temporary
 	for i, colRes := range resultColumns {
 		colType, err := coltypes.DatumTypeToColumnType(colRes.Typ)
 		if err != nil {
@@ -996,6 +1038,7 @@ func MakeTableDesc(
 		return sqlbase.TableDescriptor{}, err
 	}
 	desc := initTableDescriptor(id, parentID, tableName.Table(), creationTime, privileges)
+	desc.Temporary = n.Temporary
 
 	for _, def := range n.Defs {
 		if d, ok := def.(*tree.ColumnTableDef); ok {
@@ -1077,7 +1120,17 @@ func MakeTableDesc(
 			if d.Inverted {
 				idx.Type = sqlbase.IndexDescriptor_INVERTED
 			}
-			if err := idx.FillColumns(d.Columns); err != nil {
+			columns, exprCols, err := makeIndexExprColumns(ctx, &desc, tableName, d.Columns, semaCtx, evalCtx)
+			if err != nil {
+				return desc, err
+			}
+			if len(exprCols) > 0 && d.Inverted {
+				return desc, pgerror.NewError(pgerror.CodeInvalidSQLStatementNameError, "inverted indexes don't support expressions")
+			}
+			for _, col := range exprCols {
+				desc.AddColumn(col)
+			}
+			if err := idx.FillColumns(columns); err != nil {
 				return desc, err
 			}
 			if d.PartitionBy != nil {
@@ -1099,7 +1152,22 @@ func MakeTableDesc(
 				Unique:           true,
 				StoreColumnNames: d.Storing.ToStrings(),
 			}
-			if err := idx.FillColumns(d.Columns); err != nil {
+			columns, exprCols, err := makeIndexExprColumns(ctx, &desc, tableName, d.Columns, semaCtx, evalCtx)
+			if err != nil {
+				return desc, err
+			}
+			for _, col := range exprCols {
+				desc.AddColumn(col)
+			}
+			if d.PrimaryKey {
+				for _, c := range columns {
+					if col, _, err := desc.FindColumnByName(c.Column); err == nil && isIndexExprColumn(&col) {
+						return desc, pgerror.NewError(pgerror.CodeInvalidTableDefinitionError,
+							"primary keys cannot contain expressions")
+					}
+				}
+			}
+			if err := idx.FillColumns(columns); err != nil {
 				return desc, err
 			}
 			if d.PartitionBy != nil {
@@ -1114,7 +1182,7 @@ func MakeTableDesc(
 			}
 			if d.PrimaryKey {
 				primaryIndexColumnSet = make(map[string]struct{})
-				for _, c := range d.Columns {
+				for _, c := range columns {
 					primaryIndexColumnSet[string(c.Column)] = struct{}{}
 				}
 			}
@@ -19,6 +19,7 @@ import (
 	"fmt"
 
 	"github.com/cockroachdb/cockroach/pkg/sql/coltypes"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
@@ -60,34 +61,10 @@ func (p *planner) CreateView(ctx context.Context, n *tree.CreateView) (planNode,
 		return nil, err
 	}
 
-	// Ensure that all the table names are properly qualified.  The
-	// traversal will update the NormalizableTableNames in-place, so the
-	// changes are persisted in n.AsSource. We use tree.FormatNode
-	// merely as a traversal method; its output buffer is discarded
-	// immediately after the traversal because it is not needed further.
-	var fmtErr error
-	{
-		f := tree.NewFmtCtxWithBuf(tree.FmtParsable)
-		f.WithReformatTableNames(
-			func(_ *tree.FmtCtx, t *tree.NormalizableTableName) {
-				tn, err := p.QualifyWithDatabase(ctx, t)
-				if err != nil {
-					log.Warningf(ctx, "failed to qualify table name %q with database name: %v",
-						tree.ErrString(t), err)
-					fmtErr = err
-					return
-				}
-				// Persist the database prefix expansion.
-				tn.ExplicitSchema = true
-				tn.ExplicitCatalog = true
-			},
-		)
-		f.FormatNode(n.AsSource)
-		f.Close() // We don't need the string.
-	}
-
-	if fmtErr != nil {
-		return nil, fmtErr
+	// Ensure that all the table names are properly qualified. The
+	// changes are persisted in n.AsSource.
+	if err := p.qualifyTableNames(ctx, n.AsSource); err != nil {
+		return nil, err
 	}
 
 	planDeps, sourceColumns, err := p.analyzeViewQuery(ctx, n.AsSource)
@@ -95,6 +72,16 @@ func (p *planner) CreateView(ctx context.Context, n *tree.CreateView) (planNode,
 		return nil, err
 	}
 
+	// Views are permanent, so they cannot outlive the temporary tables
+	// they depend on.
+	for _, dep := range planDeps {
+		if dep.desc.Temporary {
+			return nil, pgerror.NewErrorf(pgerror.CodeFeatureNotSupportedError,
+				"cannot create view %q that depends on temporary table %q",
+				tree.ErrString(name), tree.ErrNameString(&dep.desc.Name))
+		}
+	}
+
 	numColNames := len(n.ColumnNames)
 	numColumns := len(sourceColumns)
 	if numColNames != 0 && numColNames != numColumns {
@@ -183,6 +170,15 @@ func (n *createViewNode) startExec(params runParams) error {
 		return err
 	}
 
+	// A materialized view is populated in the same transaction that
+	// creates it.
+	if desc.MaterializedView() {
+		if err := params.p.refreshMaterializedView(
+			params.ctx, &desc, false /* concurrently */); err != nil {
+			return err
+		}
+	}
+
 	// Log Create View event. This is an auditable log event and is
 	// recorded in the same transaction as the table descriptor update.
 	return MakeEventLogger(params.extendedEvalCtx.ExecCfg).InsertEventRecord(
@@ -222,6 +218,9 @@ func (n *createViewNode) makeViewTableDesc(
 	desc := initTableDescriptor(id, parentID, viewName,
 		params.p.txn.CommitTimestamp(), privileges)
 	desc.ViewQuery = tree.AsStringWithFlags(n.n.AsSource, tree.FmtParsable)
+	// The results of a materialized view are stored in a primary index,
+	// which AllocateIDs() keys on a hidden row ID column.
+	desc.IsMaterializedView = n.n.Materialized
 	for i, colRes := range resultColumns {
 		colType, err := coltypes.DatumTypeToColumnType(colRes.Typ)
 		if err != nil {
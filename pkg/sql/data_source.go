@@ -60,6 +60,25 @@ func (p *planner) getSources(
 		if err != nil {
 			return planDataSource{}, err
 		}
+		if hasLateralSource(sources[1:]) {
+			// LATERAL sources can refer to all the sources on their left, so
+			// the sources are joined from left to right.
+			for _, src := range sources[1:] {
+				if isLateralSource(src) {
+					left, err = p.makeApplyJoin(ctx, sqlbase.InnerJoin, left, src, nil, scanVisibility)
+				} else {
+					var right planDataSource
+					right, err = p.getDataSource(ctx, src, nil, scanVisibility)
+					if err == nil {
+						left, err = p.makeJoin(ctx, sqlbase.InnerJoin, left, right, nil)
+					}
+				}
+				if err != nil {
+					return planDataSource{}, err
+				}
+			}
+			return left, nil
+		}
 		right, err := p.getSources(ctx, sources[1:], scanVisibility)
 		if err != nil {
 			return planDataSource{}, err
@@ -69,6 +88,23 @@ func (p *planner) getSources(
 	}
 }
 
+// isLateralSource returns true if the given data source is marked LATERAL.
+func isLateralSource(src tree.TableExpr) bool {
+	t, ok := src.(*tree.AliasedTableExpr)
+	return ok && t.Lateral
+}
+
+// hasLateralSource returns true if any of the given data sources is marked
+// LATERAL.
+func hasLateralSource(sources []tree.TableExpr) bool {
+	for _, src := range sources {
+		if isLateralSource(src) {
+			return true
+		}
+	}
+	return false
+}
+
 // getVirtualDataSource attempts to find a virtual table with the
 // given name.
 func (p *planner) getVirtualDataSource(
@@ -175,6 +211,11 @@ func (p *planner) getDataSource(
 		if err != nil {
 			return left, err
 		}
+		if isLateralSource(t.Right) {
+			return p.makeApplyJoin(
+				ctx, sqlbase.JoinTypeFromAstString(t.Join), left, t.Right, t.Cond, scanVisibility,
+			)
+		}
 		right, err := p.getDataSource(ctx, t.Right, nil, scanVisibility)
 		if err != nil {
 			return right, err
@@ -345,7 +386,7 @@ func (p *planner) getPlanForDesc(
 	hints *tree.IndexHints,
 	colCfg scanColumnsConfig,
 ) (planDataSource, error) {
-	if desc.IsView() {
+	if desc.IsView() && !desc.MaterializedView() {
 		if colCfg.wantedColumns != nil {
 			return planDataSource{},
 				errors.Errorf("cannot specify an explicit column list when accessing a view by reference")
@@ -355,12 +396,13 @@ func (p *planner) getPlanForDesc(
 	if desc.IsSequence() {
 		return p.getSequenceSource(ctx, *tn, desc)
 	}
-	if !desc.IsTable() {
+	if !desc.IsTable() && !desc.MaterializedView() {
 		return planDataSource{}, errors.Errorf(
 			"unexpected table descriptor of type %s for %q", desc.TypeName(), tree.ErrString(tn))
 	}
 
-	// This name designates a real table.
+	// This name designates a real table or the rows stored for a
+	// materialized view.
 	scan := p.Scan()
 	if err := scan.initTable(ctx, p, desc, hints, colCfg); err != nil {
 		return planDataSource{}, err
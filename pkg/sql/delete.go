@@ -109,7 +109,7 @@ func (p *planner) Delete(
 		Exprs: sqlbase.ColumnsSelectors(rd.FetchCols, true /* forUpdateOrDelete */),
 		From:  &tree.From{Tables: []tree.TableExpr{n.Table}},
 		Where: n.Where,
-	}, n.OrderBy, n.Limit, nil, nil, publicAndNonPublicColumns)
+	}, n.OrderBy, n.Limit, nil /* locking */, nil, nil, publicAndNonPublicColumns)
 	if err != nil {
 		return nil, err
 	}
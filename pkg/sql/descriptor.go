@@ -40,6 +40,7 @@ var (
 	errEmptyDatabaseName = pgerror.NewError(pgerror.CodeSyntaxError, "empty database name")
 	errNoDatabase        = pgerror.NewError(pgerror.CodeInvalidNameError, "no database specified")
 	errNoTable           = pgerror.NewError(pgerror.CodeInvalidNameError, "no table specified")
+	errNoFunction        = pgerror.NewError(pgerror.CodeInvalidNameError, "no function specified")
 	errNoMatch           = pgerror.NewError(pgerror.CodeUndefinedObjectError, "no object matched")
 )
 
@@ -206,6 +207,16 @@ func getDescriptorByID(
 			return err
 		}
 		*t = *database
+	case *sqlbase.FunctionDescriptor:
+		function := desc.GetFunction()
+		if function == nil {
+			return errors.Errorf("%q is not a function", desc.String())
+		}
+
+		if err := function.Validate(); err != nil {
+			return err
+		}
+		*t = *function
 	}
 	return nil
 }
@@ -229,6 +240,8 @@ func GetAllDescriptors(ctx context.Context, txn *client.Txn) ([]sqlbase.Descript
 			descs[i] = desc.GetTable()
 		case *sqlbase.Descriptor_Database:
 			descs[i] = desc.GetDatabase()
+		case *sqlbase.Descriptor_Function:
+			descs[i] = desc.GetFunction()
 		default:
 			return nil, errors.Errorf("Descriptor.Union has unexpected type %T", t)
 		}
@@ -42,9 +42,13 @@ func (p *planner) Discard(ctx context.Context, s *tree.Discard) (planNode, error
 
 		// DEALLOCATE ALL
 		p.preparedStatements.DeleteAll(ctx)
+
+		// DISCARD TEMP
+		return p.discardTemporarySchema(ctx)
+	case tree.DiscardModeTemp:
+		return p.discardTemporarySchema(ctx)
 	default:
 		return nil, pgerror.NewErrorf(pgerror.CodeInternalError,
 			"unknown mode for DISCARD: %d", s.Mode)
 	}
-	return newZeroNode(nil /* columns */), nil
 }
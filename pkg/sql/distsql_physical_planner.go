@@ -272,6 +272,7 @@ func newQueryNotSupportedErrorf(format string, args ...interface{}) error {
 
 var mutationsNotSupportedError = newQueryNotSupportedError("mutations not supported")
 var setNotSupportedError = newQueryNotSupportedError("SET / SET CLUSTER SETTING should never distribute")
+var lockingNotSupportedError = newQueryNotSupportedError("row-level locking not supported")
 
 // leafType returns the element type if the given type is an array, and the type
 // itself otherwise.
@@ -338,6 +339,10 @@ func (dsp *DistSQLPlanner) checkSupportForNode(node planNode) (distRecommendatio
 		return rec, nil
 
 	case *scanNode:
+		if n.lockingStrength != tree.ForNone || n.lockingWaitPolicy != tree.LockWaitBlock {
+			// The rows must be locked by the transaction's coordinator.
+			return 0, lockingNotSupportedError
+		}
 		rec := canDistribute
 		if n.softLimit != 0 {
 			// We don't yet recommend distributing plans where soft limits propagate
@@ -375,6 +380,50 @@ func (dsp *DistSQLPlanner) checkSupportForNode(node planNode) (distRecommendatio
 		// Distribute aggregations if possible.
 		return rec.compose(shouldDistribute), nil
 
+	case *windowNode:
+		for _, fn := range n.funcs {
+			funcSpec, err := windowerFuncSpec(fn)
+			if err != nil {
+				return 0, newQueryNotSupportedError(err.Error())
+			}
+			argTypes := make([]sqlbase.ColumnType, len(fn.args))
+			for i, arg := range fn.args {
+				if argTypes[i], err = sqlbase.DatumTypeToColumnType(
+					arg.(tree.TypedExpr).ResolvedType(),
+				); err != nil {
+					return 0, newQueryNotSupportedError(err.Error())
+				}
+			}
+			if _, _, err := distsqlrun.GetWindowFunctionInfo(funcSpec, argTypes...); err != nil {
+				return 0, newQueryNotSupportedError(err.Error())
+			}
+			if fn.expr.Type == tree.DistinctFuncType || fn.expr.Filter != nil {
+				return 0, newQueryNotSupportedErrorf("window function %s is not supported by distsql", fn)
+			}
+			for _, arg := range fn.args {
+				if err := dsp.checkExpr(arg); err != nil {
+					return 0, err
+				}
+			}
+			if err := dsp.checkExpr(fn.startOffset); err != nil {
+				return 0, err
+			}
+			if err := dsp.checkExpr(fn.endOffset); err != nil {
+				return 0, err
+			}
+		}
+		for _, render := range n.windowRender {
+			if err := dsp.checkExpr(render); err != nil {
+				return 0, err
+			}
+		}
+		rec, err := dsp.checkSupportForNode(n.plan)
+		if err != nil {
+			return 0, err
+		}
+		// Distribute window functions if possible.
+		return rec.compose(shouldDistribute), nil
+
 	case *limitNode:
 		if err := dsp.checkExpr(n.countExpr); err != nil {
 			return 0, err
@@ -438,12 +487,39 @@ type planningCtx struct {
 	// physicalPlan we generate with this context.
 	// Nodes that fail a health check have empty addresses.
 	nodeAddresses map[roachpb.NodeID]string
+
+	// collectStats is set if the processors must collect execution
+	// statistics (see FlowSpec.CollectStats).
+	collectStats bool
+
+	// annotatePlanNodes is set if the processors must be related to the plan
+	// nodes they execute (see distsqlplan.Processor.PlanNode). planNodes is
+	// the list of plan nodes the processors refer to.
+	annotatePlanNodes bool
+	planNodes         []planNode
 }
 
 func (p *planningCtx) EvalContext() *tree.EvalContext {
 	return &p.extendedEvalCtx.EvalContext
 }
 
+// annotatePlanNode relates the processors of the physical plan created for
+// the given plan node to it: the processors that were not created for one of
+// its children were created for the node, and the result routers produce its
+// results.
+func (p *planningCtx) annotatePlanNode(node planNode, plan *physicalPlan) {
+	p.planNodes = append(p.planNodes, node)
+	idx := len(p.planNodes)
+	for i := range plan.Processors {
+		if plan.Processors[i].PlanNode == 0 {
+			plan.Processors[i].PlanNode = idx
+		}
+	}
+	for _, r := range plan.ResultRouters {
+		plan.Processors[r].OutputOf = idx
+	}
+}
+
 // sanityCheckAddresses returns an error if the same address is used by two
 // nodes.
 func (p *planningCtx) sanityCheckAddresses() error {
@@ -1010,6 +1086,244 @@ func (dsp *DistSQLPlanner) selectRenders(
 	p.planToStreamColMap = planToStreamColMap
 }
 
+// windowerFuncSpec returns the function of a window function application,
+// which is either a built-in window function or an aggregate function.
+func windowerFuncSpec(fn *windowFuncHolder) (distsqlrun.WindowerSpec_Func, error) {
+	funcStr := strings.ToUpper(fn.expr.Func.String())
+	if fn.expr.GetAggregateConstructor() != nil {
+		if funcIdx, ok := distsqlrun.AggregatorSpec_Func_value[funcStr]; ok {
+			aggFunc := distsqlrun.AggregatorSpec_Func(funcIdx)
+			return distsqlrun.WindowerSpec_Func{AggregateFunc: &aggFunc}, nil
+		}
+	} else if funcIdx, ok := distsqlrun.WindowerSpec_WindowFunc_value[funcStr]; ok {
+		windowFunc := distsqlrun.WindowerSpec_WindowFunc(funcIdx)
+		return distsqlrun.WindowerSpec_Func{WindowFunc: &windowFunc}, nil
+	}
+	return distsqlrun.WindowerSpec_Func{}, errors.Errorf("unknown window function %s", funcStr)
+}
+
+// windowerFrameBoundSpec converts a bound of a window frame. The offset of the
+// bound, if any, is evaluated during planning.
+func windowerFrameBoundSpec(
+	evalCtx *tree.EvalContext, bound *tree.WindowFrameBound, offsetExpr tree.TypedExpr, name string,
+) (distsqlrun.WindowerSpec_Frame_Bound, error) {
+	var spec distsqlrun.WindowerSpec_Frame_Bound
+	switch bound.BoundType {
+	case tree.UnboundedPreceding:
+		spec.BoundType = distsqlrun.WindowerSpec_Frame_UNBOUNDED_PRECEDING
+	case tree.ValuePreceding:
+		spec.BoundType = distsqlrun.WindowerSpec_Frame_OFFSET_PRECEDING
+	case tree.CurrentRow:
+		spec.BoundType = distsqlrun.WindowerSpec_Frame_CURRENT_ROW
+	case tree.ValueFollowing:
+		spec.BoundType = distsqlrun.WindowerSpec_Frame_OFFSET_FOLLOWING
+	case tree.UnboundedFollowing:
+		spec.BoundType = distsqlrun.WindowerSpec_Frame_UNBOUNDED_FOLLOWING
+	default:
+		return spec, errors.Errorf("unexpected window frame bound type %d", bound.BoundType)
+	}
+	offset, err := evalFrameOffset(evalCtx, offsetExpr, name)
+	if err != nil {
+		return spec, err
+	}
+	if offset != nil {
+		spec.Offset = distsqlplan.MakeExpression(offset, evalCtx, nil /* indexVarMap */)
+	}
+	return spec, nil
+}
+
+// windowerFnSpec creates the specification of a window function application
+// computed by a windower, along with the type of its results.
+func windowerFnSpec(
+	evalCtx *tree.EvalContext, p *physicalPlan, fn *windowFuncHolder,
+) (distsqlrun.WindowerSpec_WindowFn, sqlbase.ColumnType, error) {
+	var spec distsqlrun.WindowerSpec_WindowFn
+	var err error
+	if spec.Func, err = windowerFuncSpec(fn); err != nil {
+		return spec, sqlbase.ColumnType{}, err
+	}
+
+	argTypes := make([]sqlbase.ColumnType, fn.argCount)
+	spec.ArgIdxs = make([]uint32, fn.argCount)
+	for i := range spec.ArgIdxs {
+		streamCol := p.planToStreamColMap[fn.argIdxStart+i]
+		spec.ArgIdxs[i] = uint32(streamCol)
+		argTypes[i] = p.ResultTypes[streamCol]
+	}
+	_, outputType, err := distsqlrun.GetWindowFunctionInfo(spec.Func, argTypes...)
+	if err != nil {
+		return spec, sqlbase.ColumnType{}, err
+	}
+
+	spec.Ordering = distsqlrun.ConvertToMappedSpecOrdering(fn.columnOrdering, p.planToStreamColMap)
+
+	if fn.frame != nil {
+		frame := &distsqlrun.WindowerSpec_Frame{}
+		if fn.frame.Mode == tree.ROWS {
+			frame.Mode = distsqlrun.WindowerSpec_Frame_ROWS
+		}
+		frame.Bounds.Start, err = windowerFrameBoundSpec(
+			evalCtx, fn.frame.Bounds.StartBound, fn.startOffset, "starting",
+		)
+		if err != nil {
+			return spec, sqlbase.ColumnType{}, err
+		}
+		if fn.frame.Bounds.EndBound != nil {
+			end, err := windowerFrameBoundSpec(
+				evalCtx, fn.frame.Bounds.EndBound, fn.endOffset, "ending",
+			)
+			if err != nil {
+				return spec, sqlbase.ColumnType{}, err
+			}
+			frame.Bounds.End = &end
+		}
+		spec.Frame = frame
+	}
+	return spec, outputType, nil
+}
+
+// addWindowers adds the windowers computing the window functions of a
+// windowNode, followed by a rendering of the windowNode's columns.
+//
+// Window functions with the same PARTITION BY clause are computed by the same
+// stage of windowers. Each windower outputs its input columns followed by the
+// results of its window functions, so the results of a stage are available to
+// the following ones.
+func (dsp *DistSQLPlanner) addWindowers(
+	planCtx *planningCtx, p *physicalPlan, n *windowNode,
+) error {
+	evalCtx := planCtx.EvalContext()
+
+	// fnStreamCols contains the stream column of the results of each window
+	// function.
+	fnStreamCols := make([]int, len(n.funcs))
+	planned := make([]bool, len(n.funcs))
+	for i, fn := range n.funcs {
+		if planned[i] {
+			continue
+		}
+
+		var spec distsqlrun.WindowerSpec
+		spec.PartitionBy = make([]uint32, len(fn.partitionIdxs))
+		for j, idx := range fn.partitionIdxs {
+			spec.PartitionBy[j] = uint32(p.planToStreamColMap[idx])
+		}
+		outTypes := append([]sqlbase.ColumnType(nil), p.ResultTypes...)
+		for j := i; j < len(n.funcs); j++ {
+			other := n.funcs[j]
+			if planned[j] || !reflect.DeepEqual(fn.partitionIdxs, other.partitionIdxs) {
+				continue
+			}
+			fnSpec, outType, err := windowerFnSpec(evalCtx, p, other)
+			if err != nil {
+				return err
+			}
+			spec.WindowFns = append(spec.WindowFns, fnSpec)
+			fnStreamCols[j] = len(outTypes)
+			outTypes = append(outTypes, outType)
+			planned[j] = true
+		}
+
+		dsp.addWindowerStage(p, &spec, outTypes)
+	}
+
+	// Render the columns of the windowNode from the columns of the wrapped plan
+	// and the results of the window functions.
+	indexVarMap := append(append([]int(nil), p.planToStreamColMap...), fnStreamCols...)
+	renders := n.distSQLRenders()
+	outTypes := make([]sqlbase.ColumnType, len(renders))
+	for i, render := range renders {
+		var err error
+		outTypes[i], err = sqlbase.DatumTypeToColumnType(render.ResolvedType())
+		if err != nil {
+			return err
+		}
+	}
+	p.AddRendering(renders, evalCtx, indexVarMap, outTypes)
+	p.planToStreamColMap = identityMap(p.planToStreamColMap, len(renders))
+	return nil
+}
+
+// addWindowerStage adds a stage of windowers. If the window functions have a
+// PARTITION BY clause, the rows are distributed by hash of the partition
+// columns to one windower for each result router; otherwise all rows are
+// brought to a single windower.
+func (dsp *DistSQLPlanner) addWindowerStage(
+	p *physicalPlan, spec *distsqlrun.WindowerSpec, outTypes []sqlbase.ColumnType,
+) {
+	// Check if the previous stage is all on one node.
+	prevStageNode := p.Processors[p.ResultRouters[0]].Node
+	for i := 1; i < len(p.ResultRouters); i++ {
+		if n := p.Processors[p.ResultRouters[i]].Node; n != prevStageNode {
+			prevStageNode = 0
+			break
+		}
+	}
+
+	if len(spec.PartitionBy) == 0 || len(p.ResultRouters) == 1 {
+		// No PARTITION BY, or we have a single stream. Use a single windower.
+		// If the previous stage was all on a single node, put the windower
+		// there. Otherwise, bring the results back on this node.
+		node := dsp.nodeDesc.NodeID
+		if prevStageNode != 0 {
+			node = prevStageNode
+		}
+		p.AddSingleGroupStage(
+			node,
+			distsqlrun.ProcessorCoreUnion{Windower: spec},
+			distsqlrun.PostProcessSpec{},
+			outTypes,
+		)
+		return
+	}
+
+	// We distribute (by partition columns) to multiple processors.
+
+	// Set up the output routers from the previous stage.
+	for _, resultProc := range p.ResultRouters {
+		p.Processors[resultProc].Spec.Output[0] = distsqlrun.OutputRouterSpec{
+			Type:        distsqlrun.OutputRouterSpec_BY_HASH,
+			HashColumns: spec.PartitionBy,
+		}
+	}
+
+	stageID := p.NewStageID()
+
+	// We have one windower for each result router, as we do for the final stage
+	// of aggregators.
+	pIdxStart := distsqlplan.ProcessorIdx(len(p.Processors))
+	for _, resultProc := range p.ResultRouters {
+		proc := distsqlplan.Processor{
+			Node: p.Processors[resultProc].Node,
+			Spec: distsqlrun.ProcessorSpec{
+				Input: []distsqlrun.InputSyncSpec{{
+					// The other fields will be filled in by mergeResultStreams.
+					ColumnTypes: p.ResultTypes,
+				}},
+				Core: distsqlrun.ProcessorCoreUnion{Windower: spec},
+				Output: []distsqlrun.OutputRouterSpec{{
+					Type: distsqlrun.OutputRouterSpec_PASS_THROUGH,
+				}},
+				StageID: stageID,
+			},
+		}
+		p.AddProcessor(proc)
+	}
+
+	// Connect the streams.
+	for bucket := 0; bucket < len(p.ResultRouters); bucket++ {
+		pIdx := pIdxStart + distsqlplan.ProcessorIdx(bucket)
+		p.MergeResultStreams(p.ResultRouters, bucket, distsqlrun.Ordering{}, pIdx, 0)
+	}
+
+	// Set the new result routers.
+	for i := 0; i < len(p.ResultRouters); i++ {
+		p.ResultRouters[i] = pIdxStart + distsqlplan.ProcessorIdx(i)
+	}
+	p.ResultTypes = outTypes
+	p.SetMergeOrdering(orderingTerminated)
+}
+
 // addSorters adds sorters corresponding to a sortNode and updates the plan to
 // reflect the sort node.
 func (dsp *DistSQLPlanner) addSorters(p *physicalPlan, n *sortNode) {
@@ -1095,6 +1409,9 @@ func (dsp *DistSQLPlanner) addAggregators(
 		}
 		if fholder.argRenderIdx != noRenderIdx {
 			aggregations[i].ColIdx = []uint32{uint32(p.planToStreamColMap[fholder.argRenderIdx])}
+			for _, idx := range fholder.otherArgRenderIdxs {
+				aggregations[i].ColIdx = append(aggregations[i].ColIdx, uint32(p.planToStreamColMap[idx]))
+			}
 		}
 		if fholder.hasFilter() {
 			col := uint32(p.planToStreamColMap[fholder.filterRenderIdx])
@@ -1109,6 +1426,17 @@ func (dsp *DistSQLPlanner) addAggregators(
 		groupCols[i] = uint32(p.planToStreamColMap[idx])
 	}
 
+	var groupingSets []distsqlrun.AggregatorSpec_GroupingSet
+	if n.groupingSets != nil {
+		groupingSets = make([]distsqlrun.AggregatorSpec_GroupingSet, len(n.groupingSets))
+		for i, set := range n.groupingSets {
+			groupingSets[i].GroupCols = make([]uint32, len(set))
+			for j, idx := range set {
+				groupingSets[i].GroupCols[j] = uint32(p.planToStreamColMap[idx])
+			}
+		}
+	}
+
 	// We either have a local stage on each stream followed by a final stage, or
 	// just a final stage. We only use a local stage if:
 	//  - the previous stage is distributed on multiple nodes, and
@@ -1118,6 +1446,8 @@ func (dsp *DistSQLPlanner) addAggregators(
 	//  - we have a mix of aggregations that use distinct and aggregations that
 	//    don't use distinct. TODO(arjun): This would require doing the same as
 	//    the todo as above.
+	//  - there are no grouping sets. The local stages would not produce the
+	//    groups of the empty grouping sets if they see no rows.
 	multiStage := false
 	allDistinct := true
 	anyDistinct := false
@@ -1131,7 +1461,7 @@ func (dsp *DistSQLPlanner) addAggregators(
 		}
 	}
 
-	if prevStageNode == 0 {
+	if prevStageNode == 0 && groupingSets == nil {
 		// Check that all aggregation functions support a local stage.
 		multiStage = true
 		for _, e := range aggregations {
@@ -1206,6 +1536,7 @@ func (dsp *DistSQLPlanner) addAggregators(
 		finalAggsSpec = distsqlrun.AggregatorSpec{
 			Aggregations: aggregations,
 			GroupCols:    groupCols,
+			GroupingSets: groupingSets,
 		}
 	} else {
 		// Some aggregations might need multiple aggregation as part of
@@ -1487,8 +1818,12 @@ func (dsp *DistSQLPlanner) addAggregators(
 		}
 	}
 
-	if len(finalAggsSpec.GroupCols) == 0 || len(p.ResultRouters) == 1 {
+	if len(finalAggsSpec.GroupCols) == 0 || finalAggsSpec.GroupingSets != nil ||
+		len(p.ResultRouters) == 1 {
 		// No GROUP BY, or we have a single stream. Use a single final aggregator.
+		// Grouping sets also use a single final aggregator, since the groups of
+		// a grouping set that doesn't contain all the group columns can't be
+		// distributed by hashing the group columns.
 		// If the previous stage was all on a single node, put the final
 		// aggregator there. Otherwise, bring the results back on this node.
 		node := dsp.nodeDesc.NodeID
@@ -1981,6 +2316,16 @@ func (dsp *DistSQLPlanner) createPlanForNode(
 
 		dsp.addSorters(&plan, n)
 
+	case *windowNode:
+		plan, err = dsp.createPlanForNode(planCtx, n.plan)
+		if err != nil {
+			return physicalPlan{}, err
+		}
+
+		if err := dsp.addWindowers(planCtx, &plan, n); err != nil {
+			return physicalPlan{}, err
+		}
+
 	case *filterNode:
 		plan, err = dsp.createPlanForNode(planCtx, n.source.plan)
 		if err != nil {
@@ -2032,6 +2377,10 @@ func (dsp *DistSQLPlanner) createPlanForNode(
 		)
 	}
 
+	if err == nil && planCtx.annotatePlanNodes {
+		planCtx.annotatePlanNode(node, &plan)
+	}
+
 	return plan, err
 }
 
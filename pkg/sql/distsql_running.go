@@ -126,6 +126,12 @@ func (dsp *DistSQLPlanner) Run(
 	}
 
 	flows := plan.GenerateFlowSpecs(dsp.nodeDesc.NodeID /* gateway */)
+	if planCtx.collectStats {
+		for nodeID, flow := range flows {
+			flow.CollectStats = true
+			flows[nodeID] = flow
+		}
+	}
 
 	if logPlanDiagram {
 		log.VEvent(ctx, 1, "creating plan diagram")
@@ -40,6 +40,18 @@ type Processor struct {
 	// synchronizers and output routers are not set until the end of the planning
 	// process.
 	Spec distsqlrun.ProcessorSpec
+
+	// PlanNode and OutputOf relate the processor to the logical plan, so that
+	// EXPLAIN ANALYZE can attribute its execution statistics to plan nodes.
+	// They are set by the planner only when requested, and hold 1 + the index
+	// of a plan node in a list maintained by the planner (0 means none).
+	//
+	// PlanNode is the plan node the processor was created for. OutputOf is
+	// the outermost plan node whose results the processor produces, which is
+	// different from PlanNode when the processing of other plan nodes (e.g.
+	// filters and renders) was merged into the processor.
+	PlanNode int
+	OutputOf int
 }
 
 // ProcessorIdx identifies a processor by its index in PhysicalPlan.Processors.
@@ -738,11 +750,12 @@ func (p *PhysicalPlan) GenerateFlowSpecs(
 	flowID := distsqlrun.FlowID{UUID: uuid.MakeV4()}
 	flows := make(map[roachpb.NodeID]distsqlrun.FlowSpec)
 
-	for _, proc := range p.Processors {
+	for i, proc := range p.Processors {
 		flowSpec, ok := flows[proc.Node]
 		if !ok {
 			flowSpec = distsqlrun.FlowSpec{FlowID: flowID, Gateway: gateway}
 		}
+		proc.Spec.ProcessorID = int32(i)
 		flowSpec.Processors = append(flowSpec.Processors, proc.Spec)
 		flows[proc.Node] = flowSpec
 	}
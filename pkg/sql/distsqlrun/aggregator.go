@@ -24,6 +24,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/types"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/mon"
 	"github.com/cockroachdb/cockroach/pkg/util/stringarena"
@@ -45,6 +47,18 @@ func GetAggregateInfo(
 		}
 		return builtins.NewIdentAggregate, inputTypes[0], nil
 	}
+	if fn == AggregatorSpec_GROUPING {
+		// GROUPING accepts any number of arguments of any type; its result is
+		// computed by the aggregator from the grouping set of each group.
+		if len(inputTypes) == 0 {
+			return nil, sqlbase.ColumnType{}, errors.Errorf("grouping aggregate needs at least 1 input")
+		}
+		b := builtins.Aggregates["grouping"][0]
+		constructAgg := func(evalCtx *tree.EvalContext) tree.AggregateFunc {
+			return b.AggregateFunc(nil /* params */, evalCtx)
+		}
+		return constructAgg, sqlbase.ColumnType{SemanticType: sqlbase.ColumnType_INT}, nil
+	}
 
 	datumTypes := make([]types.T, len(inputTypes))
 	for i := range inputTypes {
@@ -101,11 +115,23 @@ type aggregator struct {
 	outputTypes  []sqlbase.ColumnType
 	datumAlloc   sqlbase.DatumAlloc
 
+	memMonitor *mon.BytesMonitor
 	bucketsAcc mon.BoundAccount
+	// aggEvalCtx is the evaluation context used by the aggregate functions,
+	// which account for the memory they use, e.g. to buffer the values of
+	// ordered-set aggregates, against memMonitor.
+	aggEvalCtx *tree.EvalContext
 
 	groupCols    columns
 	aggregations []AggregatorSpec_Aggregation
 
+	// groupingSetCols contains the columns of each grouping set, if the rows
+	// are grouped by GROUPING SETS, ROLLUP or CUBE. In that case the bucket
+	// keys are prefixed with the index of their grouping set. groupingSets
+	// contains the same columns as sets.
+	groupingSetCols []columns
+	groupingSets    []util.FastIntSet
+
 	// buckets is used during the accumulation phase to track the bucket keys
 	// that have been seen. After accumulation, the keys are extracted into
 	// bucketsIter for iteration.
@@ -135,9 +161,12 @@ func newAggregator(
 		buckets:      make(map[string]struct{}),
 		funcs:        make([]*aggregateFuncHolder, len(spec.Aggregations)),
 		outputTypes:  make([]sqlbase.ColumnType, len(spec.Aggregations)),
-		bucketsAcc:   flowCtx.EvalCtx.Mon.MakeBoundAccount(),
 	}
+	ag.memMonitor = newMonitor(flowCtx.Ctx, flowCtx.EvalCtx.Mon, "aggregator-mem")
+	ag.bucketsAcc = ag.memMonitor.MakeBoundAccount()
 	ag.arena = stringarena.Make(&ag.bucketsAcc)
+	ag.aggEvalCtx = flowCtx.NewEvalCtx()
+	ag.aggEvalCtx.Mon = ag.memMonitor
 
 	// Loop over the select expressions and extract any aggregate functions --
 	// non-aggregation functions are replaced with parser.NewIdentAggregate,
@@ -177,6 +206,17 @@ func newAggregator(
 
 		ag.outputTypes[i] = retType
 	}
+	for _, set := range spec.GroupingSets {
+		var cols util.FastIntSet
+		for _, c := range set.GroupCols {
+			if c >= uint32(len(ag.inputTypes)) {
+				return nil, errors.Errorf("grouping set column out of range (%d)", c)
+			}
+			cols.Add(int(c))
+		}
+		ag.groupingSetCols = append(ag.groupingSetCols, set.GroupCols)
+		ag.groupingSets = append(ag.groupingSets, cols)
+	}
 	if err := ag.init(post, ag.outputTypes, flowCtx, nil /* evalCtx */, output); err != nil {
 		return nil, err
 	}
@@ -204,6 +244,7 @@ func (ag *aggregator) close() {
 				aggFunc.Close(ag.ctx)
 			}
 		}
+		ag.memMonitor.Stop(ag.ctx)
 		ag.accumulating = false
 		ag.draining = true
 	}
@@ -217,6 +258,7 @@ func (ag *aggregator) close() {
 func (ag *aggregator) producerMeta(err error) *ProducerMetadata {
 	var meta *ProducerMetadata
 	if !ag.closed {
+		ag.recordStats(ag.span, maxMemoryStat(ag.memMonitor))
 		if err != nil {
 			meta = &ProducerMetadata{Err: err}
 		} else if trace := getTraceData(ag.ctx); trace != nil {
@@ -259,9 +301,15 @@ func (ag *aggregator) Next() (sqlbase.EncDatumRow, *ProducerMetadata) {
 
 		// Queries like `SELECT MAX(n) FROM t` expect a row of NULLs if nothing was
 		// aggregated.
-		if len(ag.buckets) < 1 && len(ag.groupCols) == 0 {
+		if len(ag.buckets) < 1 && len(ag.groupCols) == 0 && len(ag.groupingSets) == 0 {
 			ag.buckets[""] = struct{}{}
 		}
+		// Likewise, every empty grouping set produces a group.
+		for i, set := range ag.groupingSets {
+			if set.Empty() {
+				ag.buckets[string(encoding.EncodeUvarintAscending(nil, uint64(i)))] = struct{}{}
+			}
+		}
 
 		ag.bucketsIter = make([]string, 0, len(ag.buckets))
 		for bucket := range ag.buckets {
@@ -301,8 +349,23 @@ func (ag *aggregator) Next() (sqlbase.EncDatumRow, *ProducerMetadata) {
 		bucket := ag.bucketsIter[0]
 		ag.bucketsIter = ag.bucketsIter[1:]
 
+		var set util.FastIntSet
+		if len(ag.groupingSets) > 0 {
+			_, idx, err := encoding.DecodeUvarintAscending([]byte(bucket))
+			if err != nil {
+				return nil, ag.producerMeta(err)
+			}
+			set = ag.groupingSets[idx]
+		}
+
 		for i, f := range ag.funcs {
-			result, err := f.get(bucket)
+			var result tree.Datum
+			var err error
+			if len(ag.groupingSets) > 0 {
+				result, err = ag.getGroupingSetResult(i, f, bucket, set)
+			} else {
+				result, err = f.get(bucket)
+			}
 			if err != nil {
 				return nil, ag.producerMeta(err)
 			}
@@ -338,20 +401,65 @@ func (ag *aggregator) ConsumerClosed() {
 	ag.close()
 }
 
+// getGroupingSetResult returns the result of the i-th aggregation for a group
+// of the given grouping set. Group columns that are not part of the grouping
+// set are NULL, and GROUPING is computed from the grouping set.
+func (ag *aggregator) getGroupingSetResult(
+	i int, f *aggregateFuncHolder, bucket string, set util.FastIntSet,
+) (tree.Datum, error) {
+	a := &ag.aggregations[i]
+	switch a.Func {
+	case AggregatorSpec_IDENT:
+		if !set.Contains(int(a.ColIdx[0])) {
+			return tree.DNull, nil
+		}
+	case AggregatorSpec_GROUPING:
+		var mask int64
+		for _, c := range a.ColIdx {
+			mask <<= 1
+			if !set.Contains(int(c)) {
+				mask |= 1
+			}
+		}
+		return tree.NewDInt(tree.DInt(mask)), nil
+	}
+	return f.get(bucket)
+}
+
 // accumulateRow accumulates a single row, returning an error if accumulation
 // failed for any reason.
 func (ag *aggregator) accumulateRow(row sqlbase.EncDatumRow) error {
 	if err := ag.cancelChecker.Check(); err != nil {
 		return err
 	}
-	// The encoding computed here determines which bucket the non-grouping
-	// datums are accumulated to.
-	encoded, err := ag.encode(ag.scratch, row)
-	if err != nil {
-		return err
+	if len(ag.groupingSets) == 0 {
+		// The encoding computed here determines which bucket the non-grouping
+		// datums are accumulated to.
+		encoded, err := ag.encode(ag.scratch, ag.groupCols, row)
+		if err != nil {
+			return err
+		}
+		ag.scratch = encoded[:0]
+		return ag.accumulateRowIntoBucket(row, encoded)
 	}
-	ag.scratch = encoded[:0]
+	// With grouping sets, the row is accumulated into one bucket per grouping
+	// set.
+	for i := range ag.groupingSets {
+		encoded := encoding.EncodeUvarintAscending(ag.scratch, uint64(i))
+		encoded, err := ag.encode(encoded, ag.groupingSetCols[i], row)
+		if err != nil {
+			return err
+		}
+		ag.scratch = encoded[:0]
+		if err := ag.accumulateRowIntoBucket(row, encoded); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
+// accumulateRowIntoBucket accumulates a single row into the given bucket.
+func (ag *aggregator) accumulateRowIntoBucket(row sqlbase.EncDatumRow, encoded []byte) error {
 	if _, ok := ag.buckets[string(encoded)]; !ok {
 		s, err := ag.arena.AllocBytes(ag.ctx, encoded)
 		if err != nil {
@@ -456,7 +564,7 @@ func (a *aggregateFuncHolder) add(
 	if !ok {
 		// TODO(radu): we should account for the size of impl (this needs to be done
 		// in each aggregate constructor).
-		impl = a.create(&a.group.flowCtx.EvalCtx)
+		impl = a.create(a.group.aggEvalCtx)
 		usage := int64(len(bucket))
 		usage += sizeOfAggregateFunc
 		// TODO(radu): this model of each func having a map of buckets (one per
@@ -474,18 +582,18 @@ func (a *aggregateFuncHolder) add(
 func (a *aggregateFuncHolder) get(bucket string) (tree.Datum, error) {
 	found, ok := a.buckets[bucket]
 	if !ok {
-		found = a.create(&a.group.flowCtx.EvalCtx)
+		found = a.create(a.group.aggEvalCtx)
 	}
 
 	return found.Result()
 }
 
-// encode returns the encoding for the grouping columns, this is then used as
-// our group key to determine which bucket to add to.
+// encode returns the encoding for the given grouping columns, this is then
+// used as our group key to determine which bucket to add to.
 func (ag *aggregator) encode(
-	appendTo []byte, row sqlbase.EncDatumRow,
+	appendTo []byte, groupCols columns, row sqlbase.EncDatumRow,
 ) (encoding []byte, err error) {
-	for _, colIdx := range ag.groupCols {
+	for _, colIdx := range groupCols {
 		appendTo, err = row[colIdx].Encode(&ag.inputTypes[colIdx], &ag.datumAlloc, sqlbase.DatumEncoding_ASCENDING_KEY, appendTo)
 		if err != nil {
 			return appendTo, err
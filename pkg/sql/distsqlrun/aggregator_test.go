@@ -342,6 +342,80 @@ func TestAggregator(t *testing.T) {
 				{v[2], v[3], v[3]},
 			},
 		},
+		{
+			// SELECT @1, @2, SUM(@3), GROUPING(@1, @2) GROUP BY ROLLUP (@1, @2).
+			spec: AggregatorSpec{
+				GroupCols: []uint32{0, 1},
+				GroupingSets: []AggregatorSpec_GroupingSet{
+					{GroupCols: []uint32{0, 1}},
+					{GroupCols: []uint32{0}},
+					{},
+				},
+				Aggregations: []AggregatorSpec_Aggregation{
+					{
+						Func:   AggregatorSpec_IDENT,
+						ColIdx: []uint32{0},
+					},
+					{
+						Func:   AggregatorSpec_IDENT,
+						ColIdx: []uint32{1},
+					},
+					{
+						Func:   AggregatorSpec_SUM,
+						ColIdx: []uint32{2},
+					},
+					{
+						Func:   AggregatorSpec_GROUPING,
+						ColIdx: []uint32{0, 1},
+					},
+				},
+			},
+			inputTypes: threeIntCols,
+			input: sqlbase.EncDatumRows{
+				{v[1], v[2], v[3]},
+				{v[1], v[3], v[4]},
+				{v[2], v[2], v[5]},
+			},
+			outputTypes: []sqlbase.ColumnType{
+				intType, // IDENT
+				intType, // IDENT
+				decType, // SUM
+				intType, // GROUPING
+			},
+			expected: sqlbase.EncDatumRows{
+				{v[1], v[2], v[3], v[0]},
+				{v[1], v[3], v[4], v[0]},
+				{v[2], v[2], v[5], v[0]},
+				{v[1], null, v[7], v[1]},
+				{v[2], null, v[5], v[1]},
+				{null, null, v[12], v[3]},
+			},
+		},
+		{
+			// SELECT @1, COUNT_ROWS GROUP BY GROUPING SETS ((@1), ()) (no rows).
+			spec: AggregatorSpec{
+				GroupCols: []uint32{0},
+				GroupingSets: []AggregatorSpec_GroupingSet{
+					{GroupCols: []uint32{0}},
+					{},
+				},
+				Aggregations: []AggregatorSpec_Aggregation{
+					{
+						Func:   AggregatorSpec_IDENT,
+						ColIdx: []uint32{0},
+					},
+					{
+						Func: AggregatorSpec_COUNT_ROWS,
+					},
+				},
+			},
+			inputTypes:  oneIntCol,
+			input:       sqlbase.EncDatumRows{},
+			outputTypes: twoIntCols,
+			expected: sqlbase.EncDatumRows{
+				{null, v[0]},
+			},
+		},
 	}
 
 	for _, c := range testCases {
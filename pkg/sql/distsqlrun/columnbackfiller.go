@@ -23,6 +23,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/transform"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/types"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/util"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
@@ -39,9 +40,16 @@ type columnBackfiller struct {
 	updateCols  []sqlbase.ColumnDescriptor
 	updateExprs []tree.TypedExpr
 
+	// curRow holds the values of the row being backfilled while evaluating
+	// the computed expressions of the added columns, which can refer to the
+	// columns of the table.
+	curRow tree.Datums
+
 	evalCtx *tree.EvalContext
 }
 
+var _ tree.IndexedVarContainer = &columnBackfiller{}
+
 var _ Processor = &columnBackfiller{}
 var _ chunkBackfiller = &columnBackfiller{}
 
@@ -99,12 +107,25 @@ func (cb *columnBackfiller) init() error {
 		return err
 	}
 
+	// Added columns replacing existing columns (see ALTER COLUMN ... SET DATA
+	// TYPE) are populated from their computed expression.
+	ivarHelper := tree.MakeIndexedVarHelper(cb, len(desc.Columns))
+	computedExprs, err := sqlbase.MakeComputedExprs(
+		cb.added, desc.Columns, &ivarHelper, &transform.ExprTransformContext{}, cb.evalCtx,
+	)
+	if err != nil {
+		return err
+	}
+	cb.evalCtx.IVarContainer = cb
+
 	cb.updateCols = append(cb.added, cb.dropped...)
-	if len(cb.dropped) > 0 || len(defaultExprs) > 0 {
+	if len(cb.dropped) > 0 || len(defaultExprs) > 0 || len(computedExprs) > 0 {
 		// Populate default values.
 		cb.updateExprs = make([]tree.TypedExpr, len(cb.updateCols))
 		for j := range cb.added {
-			if defaultExprs == nil || defaultExprs[j] == nil {
+			if cb.added[j].IsComputed() {
+				cb.updateExprs[j] = computedExprs[j]
+			} else if defaultExprs == nil || defaultExprs[j] == nil {
 				cb.updateExprs[j] = tree.DNull
 			} else {
 				cb.updateExprs[j] = defaultExprs[j]
@@ -130,6 +151,7 @@ func (cb *columnBackfiller) init() error {
 		ColIdxMap:       colIdxMap,
 		Cols:            desc.Columns,
 		ValNeededForCol: valNeededForCol,
+		EvalCtx:         cb.evalCtx,
 	}
 	return cb.fetcher.Init(
 		false /* reverse */, false /* returnRangeInfo */, false /* isCheck */, &cb.alloc, tableArgs,
@@ -233,13 +255,22 @@ func (cb *columnBackfiller) runChunk(
 			}
 			// Evaluate the new values. This must be done separately for
 			// each row so as to handle impure functions correctly.
+			cb.curRow = datums
 			for j, e := range cb.updateExprs {
 				val, err := e.Eval(cb.evalCtx)
 				if err != nil {
 					return sqlbase.NewInvalidSchemaDefinitionError(err)
 				}
-				if j < len(cb.added) && !cb.added[j].Nullable && val == tree.DNull {
-					return sqlbase.NewNonNullViolationError(cb.added[j].Name)
+				if j < len(cb.added) {
+					col := &cb.added[j]
+					if !col.Nullable && val == tree.DNull {
+						return sqlbase.NewNonNullViolationError(col.Name)
+					}
+					if col.IsComputed() {
+						if err := sqlbase.CheckValueWidth(col.Type, val, col.Name); err != nil {
+							return sqlbase.NewInvalidSchemaDefinitionError(err)
+						}
+					}
 				}
 				updateValues[j] = val
 			}
@@ -266,3 +297,19 @@ func (cb *columnBackfiller) runChunk(
 	})
 	return cb.fetcher.Key(), err
 }
+
+// IndexedVarEval implements the tree.IndexedVarContainer interface.
+func (cb *columnBackfiller) IndexedVarEval(idx int, ctx *tree.EvalContext) (tree.Datum, error) {
+	return cb.curRow[idx].Eval(ctx)
+}
+
+// IndexedVarResolvedType implements the tree.IndexedVarContainer interface.
+func (cb *columnBackfiller) IndexedVarResolvedType(idx int) types.T {
+	return cb.spec.Table.Columns[idx].Type.ToDatumType()
+}
+
+// IndexedVarNodeFormatter implements the tree.IndexedVarContainer interface.
+func (cb *columnBackfiller) IndexedVarNodeFormatter(idx int) tree.NodeFormatter {
+	n := tree.Name(cb.spec.Table.Columns[idx].Name)
+	return &n
+}
@@ -36,6 +36,7 @@ type distinct struct {
 	seen         map[string]struct{}
 	orderedCols  []uint32
 	distinctCols util.FastIntSet
+	memMonitor   *mon.BytesMonitor
 	memAcc       mon.BoundAccount
 	datumAlloc   sqlbase.DatumAlloc
 	scratch      []byte
@@ -73,11 +74,13 @@ func newDistinct(
 		distinctCols.Add(int(col))
 	}
 
+	memMonitor := newMonitor(flowCtx.Ctx, flowCtx.EvalCtx.Mon, "distinct-mem")
 	d := &distinct{
 		input:        input,
 		orderedCols:  spec.OrderedColumns,
 		distinctCols: distinctCols,
-		memAcc:       flowCtx.EvalCtx.Mon.MakeBoundAccount(),
+		memMonitor:   memMonitor,
+		memAcc:       memMonitor.MakeBoundAccount(),
 		types:        input.OutputTypes(),
 	}
 
@@ -163,6 +166,7 @@ func (d *distinct) close() {
 	if !d.closed {
 		// Need to close the mem accounting while the context is still valid.
 		d.memAcc.Close(d.ctx)
+		d.memMonitor.Stop(d.ctx)
 	}
 	if d.internalClose() {
 		d.input.ConsumerClosed()
@@ -176,6 +180,7 @@ func (d *distinct) close() {
 func (d *distinct) producerMeta(err error) *ProducerMetadata {
 	var meta *ProducerMetadata
 	if !d.closed {
+		d.recordStats(d.span, maxMemoryStat(d.memMonitor))
 		if err != nil {
 			meta = &ProducerMetadata{Err: err}
 		} else if trace := getTraceData(d.ctx); trace != nil {
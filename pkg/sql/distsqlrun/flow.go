@@ -162,6 +162,10 @@ type Flow struct {
 
 	// spec is the request that produced this flow. Only used for debugging.
 	spec *FlowSpec
+
+	// collectStats is set if the processors collect execution statistics, as
+	// requested by the gateway in the flow spec (e.g. for EXPLAIN ANALYZE).
+	collectStats bool
 }
 
 func newFlow(flowCtx FlowCtx, flowReg *flowRegistry, syncFlowConsumer RowReceiver) *Flow {
@@ -298,10 +302,21 @@ func (f *Flow) makeProcessor(ps *ProcessorSpec, inputs []RowSource) (Processor,
 		outputs[i] = r
 		f.startables = append(f.startables, r)
 	}
+	var inputStats []*InputStatCollector
+	if f.collectStats {
+		inputStats = make([]*InputStatCollector, len(inputs))
+		for i := range inputs {
+			inputStats[i] = NewInputStatCollector(inputs[i])
+			inputs[i] = inputStats[i]
+		}
+	}
 	proc, err := newProcessor(&f.FlowCtx, &ps.Core, &ps.Post, inputs, outputs)
 	if err != nil {
 		return nil, err
 	}
+	if sc, ok := proc.(statsCollector); ok && f.collectStats {
+		sc.setupStats(ps.ProcessorID, inputStats)
+	}
 	// Initialize any routers (the setupRouter case above) and outboxes.
 	types := proc.OutputTypes()
 	for _, o := range outputs {
@@ -317,6 +332,7 @@ func (f *Flow) makeProcessor(ps *ProcessorSpec, inputs []RowSource) (Processor,
 
 func (f *Flow) setup(ctx context.Context, spec *FlowSpec) error {
 	f.spec = spec
+	f.collectStats = spec.CollectStats
 
 	// First step: setup the input synchronizers for all processors.
 	inputSyncs := make([][]RowSource, len(spec.Processors))
@@ -116,6 +116,34 @@ func (a *AggregatorSpec) summary() (string, []string) {
 	return "Aggregator", details
 }
 
+// summary implements the diagramCellType interface.
+func (w *WindowerSpec) summary() (string, []string) {
+	details := make([]string, 0, len(w.WindowFns)+1)
+	if len(w.PartitionBy) > 0 {
+		details = append(details, fmt.Sprintf("PARTITION BY %s", colListStr(w.PartitionBy)))
+	}
+	for _, fn := range w.WindowFns {
+		var buf bytes.Buffer
+		if fn.Func.AggregateFunc != nil {
+			buf.WriteString(fn.Func.AggregateFunc.String())
+		} else if fn.Func.WindowFunc != nil {
+			buf.WriteString(fn.Func.WindowFunc.String())
+		}
+		buf.WriteByte('(')
+		buf.WriteString(colListStr(fn.ArgIdxs))
+		buf.WriteByte(')')
+		if len(fn.Ordering.Columns) > 0 {
+			fmt.Fprintf(&buf, " ORDER BY %s", fn.Ordering.diagramString())
+		}
+		if fn.Frame != nil {
+			fmt.Fprintf(&buf, " %s", fn.Frame.Mode)
+		}
+		details = append(details, buf.String())
+	}
+
+	return "Windower", details
+}
+
 func indexDetails(indexIdx uint32, desc *sqlbase.TableDescriptor) []string {
 	index := "primary"
 	if indexIdx > 0 {
@@ -410,7 +438,19 @@ type diagramData struct {
 	Edges      []diagramEdge      `json:"edges"`
 }
 
-func generateDiagramData(flows []FlowSpec, nodeNames []string) (diagramData, error) {
+// ProcessorSummary returns the title and the details that describe the given
+// processor in a flow diagram.
+func ProcessorSummary(p *ProcessorSpec) (string, []string) {
+	title, details := p.Core.GetValue().(diagramCellType).summary()
+	return title, append(details, p.Post.summary()...)
+}
+
+// generateDiagramData generates the diagram of the given flows. If stats is
+// not nil, the execution statistics of each processor are added to its
+// details.
+func generateDiagramData(
+	flows []FlowSpec, nodeNames []string, stats map[int32]*ProcessorStats,
+) (diagramData, error) {
 	d := diagramData{NodeNames: nodeNames}
 
 	// inPorts maps streams to their "destination" attachment point. Only DestProc
@@ -422,8 +462,14 @@ func generateDiagramData(flows []FlowSpec, nodeNames []string) (diagramData, err
 	for n := range flows {
 		for _, p := range flows[n].Processors {
 			proc := diagramProcessor{NodeIdx: n}
-			proc.Core.Title, proc.Core.Details = p.Core.GetValue().(diagramCellType).summary()
-			proc.Core.Details = append(proc.Core.Details, p.Post.summary()...)
+			proc.Core.Title, proc.Core.Details = ProcessorSummary(&p)
+			if s, ok := stats[p.ProcessorID]; ok {
+				for _, stat := range s.Display() {
+					proc.Core.Details = append(
+						proc.Core.Details, fmt.Sprintf("%s: %s", stat.Name, stat.Value),
+					)
+				}
+			}
 
 			// We need explicit synchronizers if we have multiple inputs, or if the
 			// one input has multiple input streams.
@@ -522,6 +568,12 @@ func generateDiagramData(flows []FlowSpec, nodeNames []string) (diagramData, err
 // GeneratePlanDiagram generates the json data for a flow diagram.  There should // be one FlowSpec per node. The function assumes that StreamIDs are unique
 // across all flows.
 func GeneratePlanDiagram(flows map[roachpb.NodeID]FlowSpec, w io.Writer) error {
+	return generatePlanDiagram(flows, nil /* stats */, w)
+}
+
+func generatePlanDiagram(
+	flows map[roachpb.NodeID]FlowSpec, stats map[int32]*ProcessorStats, w io.Writer,
+) error {
 	// We sort the flows by node because we want the diagram data to be
 	// deterministic.
 	nodeIDs := make([]int, 0, len(flows))
@@ -538,7 +590,7 @@ func GeneratePlanDiagram(flows map[roachpb.NodeID]FlowSpec, w io.Writer) error {
 		nodeNames[i] = n.String()
 	}
 
-	d, err := generateDiagramData(flowSlice, nodeNames)
+	d, err := generateDiagramData(flowSlice, nodeNames, stats)
 	if err != nil {
 		return err
 	}
@@ -549,8 +601,23 @@ func GeneratePlanDiagram(flows map[roachpb.NodeID]FlowSpec, w io.Writer) error {
 // URL which encodes the diagram. There should be one FlowSpec per node. The
 // function assumes that StreamIDs are unique across all flows.
 func GeneratePlanDiagramWithURL(flows map[roachpb.NodeID]FlowSpec) (string, url.URL, error) {
+	return generatePlanDiagramWithURL(flows, nil /* stats */)
+}
+
+// GenerateAnnotatedPlanDiagramWithURL is like GeneratePlanDiagramWithURL, but
+// the details of each processor also contain its execution statistics, keyed
+// by processor ID (see ExtractStatsFromSpans).
+func GenerateAnnotatedPlanDiagramWithURL(
+	flows map[roachpb.NodeID]FlowSpec, stats map[int32]*ProcessorStats,
+) (string, url.URL, error) {
+	return generatePlanDiagramWithURL(flows, stats)
+}
+
+func generatePlanDiagramWithURL(
+	flows map[roachpb.NodeID]FlowSpec, stats map[int32]*ProcessorStats,
+) (string, url.URL, error) {
 	var json, compressed bytes.Buffer
-	if err := GeneratePlanDiagram(flows, &json); err != nil {
+	if err := generatePlanDiagram(flows, stats, &json); err != nil {
 		return "", url.URL{}, err
 	}
 	jsonStr := json.String()
@@ -25,6 +25,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/mon"
 	"github.com/cockroachdb/cockroach/pkg/util/tracing"
+	opentracing "github.com/opentracing/opentracing-go"
 	"github.com/pkg/errors"
 )
 
@@ -108,6 +109,11 @@ type hashJoiner struct {
 
 	// Context cancellation checker.
 	cancelChecker *sqlbase.CancelChecker
+
+	// memMonitor tracks the memory used by the hashJoiner. diskMonitor tracks
+	// its disk usage; it is only set once the hashJoiner falls back to disk.
+	memMonitor  *mon.BytesMonitor
+	diskMonitor *mon.BytesMonitor
 }
 
 var _ Processor = &hashJoiner{}
@@ -153,13 +159,15 @@ func (h *hashJoiner) Run(wg *sync.WaitGroup) {
 		defer wg.Done()
 	}
 
-	pushTrailingMeta := func(ctx context.Context) {
-		sendTraceData(ctx, h.out.output)
-	}
-
 	ctx := log.WithLogTag(h.flowCtx.Ctx, "HashJoiner", nil)
 	ctx, span := processorSpan(ctx, "hash joiner")
 	defer tracing.FinishSpan(span)
+	h.startStats()
+
+	pushTrailingMeta := func(ctx context.Context) {
+		h.recordHashJoinerStats(span)
+		sendTraceData(ctx, h.out.output)
+	}
 
 	h.cancelChecker = sqlbase.NewCancelChecker(ctx)
 
@@ -172,31 +180,38 @@ func (h *hashJoiner) Run(wg *sync.WaitGroup) {
 	useTempStorage := settingUseTempStorageJoins.Get(&st.SV) ||
 		h.flowCtx.testingKnobs.MemoryLimitBytes > 0 ||
 		h.testingKnobMemFailPoint != unset
-	rowContainerMon := h.flowCtx.EvalCtx.Mon
+	// The rows are accounted for by a child monitor, so that the memory used
+	// by this processor can be reported.
+	var limit int64
 	if useTempStorage {
-		// Limit the memory use by creating a child monitor with a hard limit.
+		// Limit the memory use by giving the child monitor a hard limit.
 		// The hashJoiner will overflow to disk if this limit is not enough.
-		limit := h.flowCtx.testingKnobs.MemoryLimitBytes
+		limit = h.flowCtx.testingKnobs.MemoryLimitBytes
 		if limit <= 0 {
 			limit = settingWorkMemBytes.Get(&st.SV)
 		}
-		limitedMon := mon.MakeMonitorInheritWithLimit("hashjoiner-limited", limit, rowContainerMon)
-		limitedMon.Start(ctx, rowContainerMon, mon.BoundAccount{})
-		defer limitedMon.Stop(ctx)
 
 		// Override initialBufferSize to be half of this processor's memory
 		// limit. We consume up to h.initialBufferSize bytes from each input
 		// stream.
 		h.initialBufferSize = limit / 2
-
-		rowContainerMon = &limitedMon
 	}
+	limitedMon := mon.MakeMonitorInheritWithLimit("hashjoiner-limited", limit, h.flowCtx.EvalCtx.Mon)
+	limitedMon.Start(ctx, h.flowCtx.EvalCtx.Mon, mon.BoundAccount{})
+	defer limitedMon.Stop(ctx)
+	h.memMonitor = &limitedMon
+	// The disk monitor, if any, is stopped once the stored rows are closed.
+	defer func() {
+		if h.diskMonitor != nil {
+			h.diskMonitor.Stop(ctx)
+		}
+	}()
 
 	evalCtx := h.flowCtx.NewEvalCtx()
 	h.rows[leftSide].initWithMon(
-		nil /* ordering */, h.leftSource.OutputTypes(), evalCtx, rowContainerMon)
+		nil /* ordering */, h.leftSource.OutputTypes(), evalCtx, h.memMonitor)
 	h.rows[rightSide].initWithMon(
-		nil /* ordering */, h.rightSource.OutputTypes(), evalCtx, rowContainerMon)
+		nil /* ordering */, h.rightSource.OutputTypes(), evalCtx, h.memMonitor)
 	defer h.rows[leftSide].Close(ctx)
 	defer h.rows[rightSide].Close(ctx)
 
@@ -226,6 +241,9 @@ func (h *hashJoiner) Run(wg *sync.WaitGroup) {
 	storedRows, earlyExit, err := h.buildPhase(
 		ctx, useTempStorage, !bufferPhaseOom, /* attemptMemoryBuild */
 	)
+	if storedRows != nil {
+		defer storedRows.Close(ctx)
+	}
 	if earlyExit || err != nil {
 		if err != nil {
 			// We got an error. We still want to drain. Any error encountered while
@@ -236,7 +254,6 @@ func (h *hashJoiner) Run(wg *sync.WaitGroup) {
 		DrainAndClose(ctx, h.out.output, err /* cause */, pushTrailingMeta, h.leftSource, h.rightSource)
 		return
 	}
-	defer storedRows.Close(ctx)
 
 	// If the buffer phase returned a row, this row has not been added to any
 	// row container, since it is the row that caused a memory error. We add
@@ -417,7 +434,8 @@ func (h *hashJoiner) buildPhase(
 
 	log.VEventf(ctx, 2, "build phase falling back to disk")
 
-	storedDiskRows := makeHashDiskRowContainer(h.flowCtx.diskMonitor, h.flowCtx.TempStorage)
+	h.diskMonitor = newMonitor(ctx, h.flowCtx.diskMonitor, "hashjoiner-disk")
+	storedDiskRows := makeHashDiskRowContainer(h.diskMonitor, h.flowCtx.TempStorage)
 	if err := storedDiskRows.Init(
 		ctx,
 		shouldMark(h.storedSide, h.joinType),
@@ -426,6 +444,13 @@ func (h *hashJoiner) buildPhase(
 	); err != nil {
 		return nil, false, err
 	}
+	// The container is closed here unless it is returned to the caller.
+	returned := false
+	defer func() {
+		if !returned {
+			storedDiskRows.Close(ctx)
+		}
+	}()
 
 	// Transfer rows from memory.
 	i := h.rows[h.storedSide].NewIterator(ctx)
@@ -463,6 +488,7 @@ func (h *hashJoiner) buildPhase(
 				return nil, false, err
 			}
 			// Done consuming rows.
+			returned = true
 			return &storedDiskRows, earlyExit, nil
 		}
 		if err := storedDiskRows.AddRow(ctx, row); err != nil {
@@ -636,11 +662,22 @@ func (h *hashJoiner) probePhase(
 		}
 	}
 
+	h.recordHashJoinerStats(opentracing.SpanFromContext(ctx))
 	sendTraceData(ctx, h.out.output)
 	h.out.Close()
 	return false, nil
 }
 
+// recordHashJoinerStats records the execution statistics of the hashJoiner
+// on span.
+func (h *hashJoiner) recordHashJoinerStats(span opentracing.Span) {
+	stats := []extraStat{maxMemoryStat(h.memMonitor)}
+	if h.diskMonitor != nil {
+		stats = append(stats, maxDiskStat(h.diskMonitor))
+	}
+	h.recordStats(span, stats...)
+}
+
 // receiveRow receives a row from either the left or right stream.
 // It takes care of forwarding any metadata, and processes any rows that have
 // NULL on an equality column - these rows will not match anything, they are
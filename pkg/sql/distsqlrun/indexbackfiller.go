@@ -116,6 +116,7 @@ func (ib *indexBackfiller) init() error {
 		ColIdxMap:       ib.colIdxMap,
 		Cols:            cols,
 		ValNeededForCol: valNeededForCol,
+		EvalCtx:         ib.flowCtx.NewEvalCtx(),
 	}
 	return ib.fetcher.Init(
 		false /* reverse */, false /* returnRangeInfo */, false /* isCheck */, &ib.alloc, tableArgs,
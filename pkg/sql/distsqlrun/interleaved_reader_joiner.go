@@ -22,6 +22,7 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/scrub"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/tracing"
@@ -151,7 +152,7 @@ func newInterleavedReaderJoiner(
 	}
 
 	if err := irj.initRowFetcher(
-		spec.Tables, spec.Reverse, &irj.alloc,
+		spec.Tables, spec.Reverse, &irj.alloc, flowCtx.NewEvalCtx(),
 	); err != nil {
 		return nil, err
 	}
@@ -178,7 +179,10 @@ func newInterleavedReaderJoiner(
 }
 
 func (irj *interleavedReaderJoiner) initRowFetcher(
-	tables []InterleavedReaderJoinerSpec_Table, reverseScan bool, alloc *sqlbase.DatumAlloc,
+	tables []InterleavedReaderJoinerSpec_Table,
+	reverseScan bool,
+	alloc *sqlbase.DatumAlloc,
+	evalCtx *tree.EvalContext,
 ) error {
 	args := make([]sqlbase.RowFetcherTableArgs, len(tables))
 
@@ -200,6 +204,7 @@ func (irj *interleavedReaderJoiner) initRowFetcher(
 		}
 		args[i].Desc = &desc
 		args[i].Cols = desc.Columns
+		args[i].EvalCtx = evalCtx
 		args[i].Spans = make(roachpb.Spans, len(table.Spans))
 		for j, trSpan := range table.Spans {
 			args[i].Spans[j] = trSpan.Span
@@ -18,6 +18,7 @@ import (
 	"context"
 	"sync"
 
+	opentracing "github.com/opentracing/opentracing-go"
 	"github.com/pkg/errors"
 
 	"github.com/cockroachdb/cockroach/pkg/internal/client"
@@ -117,7 +118,7 @@ func newJoinReader(
 
 	_, _, err = initRowFetcher(
 		&jr.fetcher, &jr.desc, int(spec.IndexIdx), false, /* reverse */
-		jr.rowFetcherColumns(), false /* isCheck */, &jr.alloc,
+		jr.rowFetcherColumns(), false /* isCheck */, &jr.alloc, flowCtx.NewEvalCtx(),
 	)
 	if err != nil {
 		return nil, err
@@ -229,6 +230,7 @@ func (jr *joinReader) generateKey(
 }
 
 func (jr *joinReader) pushTrailingMeta(ctx context.Context) {
+	jr.recordStats(opentracing.SpanFromContext(ctx), kvBytesReadStat(&jr.fetcher))
 	sendTraceData(ctx, jr.out.output)
 	sendTxnCoordMetaMaybe(jr.flowCtx.txn, jr.out.output)
 }
@@ -410,6 +412,7 @@ func (jr *joinReader) Run(wg *sync.WaitGroup) {
 	ctx := log.WithLogTagInt(jr.flowCtx.Ctx, "JoinReader", int(jr.desc.ID))
 	ctx, span := processorSpan(ctx, "join reader")
 	defer tracing.FinishSpan(span)
+	jr.startStats()
 
 	err := jr.mainLoop(ctx)
 	if err != nil {
@@ -119,6 +119,7 @@ func (m *mergeJoiner) close() {
 func (m *mergeJoiner) producerMeta(err error) *ProducerMetadata {
 	var meta *ProducerMetadata
 	if !m.closed {
+		m.recordStats(m.span)
 		if err != nil {
 			meta = &ProducerMetadata{Err: err}
 		} else if trace := getTraceData(m.ctx); trace != nil {
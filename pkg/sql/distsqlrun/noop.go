@@ -66,6 +66,7 @@ func (n *noopProcessor) close() {
 func (n *noopProcessor) producerMeta(err error) *ProducerMetadata {
 	var meta *ProducerMetadata
 	if !n.closed {
+		n.recordStats(n.span)
 		if err != nil {
 			meta = &ProducerMetadata{Err: err}
 		} else if trace := getTraceData(n.ctx); trace != nil {
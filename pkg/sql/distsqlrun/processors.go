@@ -77,6 +77,9 @@ type ProcOutputHelper struct {
 	maxRowIdx uint64
 
 	rowIdx uint64
+	// numRowsOut is the number of rows produced by post-processing, reported
+	// in the execution statistics of the processor.
+	numRowsOut int64
 }
 
 // Init sets up a ProcOutputHelper. The types describe the internal schema of
@@ -345,6 +348,7 @@ func (h *ProcOutputHelper) ProcessRow(
 		outRow = h.rowAlloc.AllocRow(len(row))
 		copy(outRow, row)
 	}
+	h.numRowsOut++
 
 	return outRow, NeedMoreRows, nil
 }
@@ -373,6 +377,10 @@ type processorBase struct {
 	// used as a RowSource.
 	started bool
 	closed  bool
+
+	// stats is set if the processor collects execution statistics (for
+	// EXPLAIN ANALYZE). See setupStats().
+	stats *processorStats
 }
 
 // OutputTypes is part of the processor interface.
@@ -412,6 +420,7 @@ func (pb *processorBase) maybeStart(name, logTag string) bool {
 		pb.ctx = log.WithLogTag(pb.ctx, logTag, nil)
 	}
 	pb.ctx, pb.span = processorSpan(pb.ctx, name)
+	pb.startStats()
 	return true
 }
 
@@ -526,6 +535,12 @@ func newProcessor(
 		}
 		return newAggregator(flowCtx, core.Aggregator, inputs[0], post, outputs[0])
 	}
+	if core.Windower != nil {
+		if err := checkNumInOut(inputs, outputs, 1, 1); err != nil {
+			return nil, err
+		}
+		return newWindower(flowCtx, core.Windower, inputs[0], post, outputs[0])
+	}
 	if core.MergeJoiner != nil {
 		if err := checkNumInOut(inputs, outputs, 2, 1); err != nil {
 			return nil, err
@@ -70,8 +70,10 @@ func newSamplerProcessor(
 		if _, ok := supportedSketchTypes[s.SketchType]; !ok {
 			return nil, errors.Errorf("unsupported sketch type %s", s.SketchType)
 		}
-		if len(s.Columns) != 1 {
-			return nil, errors.Errorf("multi-column sketches not supported yet")
+		// Multi-column sketches are only supported since version 14; the
+		// planner doesn't schedule flows on servers with older versions.
+		if len(s.Columns) == 0 {
+			return nil, errors.Errorf("no columns")
 		}
 	}
 
@@ -165,22 +167,17 @@ func (s *samplerProcessor) mainLoop(ctx context.Context) (earlyExit bool, _ erro
 		}
 
 		for i := range s.sketches {
-			// TODO(radu): for multi-column sketches, we will need to do this for all
-			// columns.
-			col := s.sketches[i].spec.Columns[0]
-			s.sketches[i].numRows++
-			if row[col].IsNull() {
-				s.sketches[i].numNulls++
-				continue
-			}
-			// We need to use a KEY encoding because equal values should have the same
-			// encoding.
-			// TODO(radu): a fast path for simple columns (like integer)?
+			var hasNull bool
 			var err error
-			buf, err = row[col].Encode(&s.outTypes[col], &da, sqlbase.DatumEncoding_ASCENDING_KEY, buf[:0])
+			buf, hasNull, err = s.encodeSketchValue(&s.sketches[i], row, &da, buf[:0])
 			if err != nil {
 				return false, err
 			}
+			s.sketches[i].numRows++
+			if hasNull {
+				s.sketches[i].numNulls++
+				continue
+			}
 			s.sketches[i].sketch.Insert(buf)
 		}
 
@@ -224,3 +221,27 @@ func (s *samplerProcessor) mainLoop(ctx context.Context) (earlyExit bool, _ erro
 	}
 	return false, nil
 }
+
+// encodeSketchValue appends the encoding of the values of the sketch columns
+// of the given row to buf. The values of a multi-column sketch are encoded
+// one after the other, so that each distinct tuple of values has a distinct
+// encoding. It returns hasNull=true if any of the values is NULL; such rows
+// are only counted as NULLs.
+func (s *samplerProcessor) encodeSketchValue(
+	si *sketchInfo, row sqlbase.EncDatumRow, da *sqlbase.DatumAlloc, buf []byte,
+) (_ []byte, hasNull bool, _ error) {
+	for _, col := range si.spec.Columns {
+		if row[col].IsNull() {
+			return buf, true, nil
+		}
+		// We need to use a KEY encoding because equal values should have the same
+		// encoding.
+		// TODO(radu): a fast path for simple columns (like integer)?
+		var err error
+		buf, err = row[col].Encode(&s.outTypes[col], da, sqlbase.DatumEncoding_ASCENDING_KEY, buf)
+		if err != nil {
+			return buf, false, err
+		}
+	}
+	return buf, false, nil
+}
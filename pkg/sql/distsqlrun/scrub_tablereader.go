@@ -102,7 +102,7 @@ func newScrubTableReader(
 
 	if _, _, err := initRowFetcher(
 		&tr.fetcher, &tr.tableDesc, int(spec.IndexIdx), spec.Reverse,
-		neededColumns, true /* isCheck */, &tr.alloc,
+		neededColumns, true /* isCheck */, &tr.alloc, flowCtx.NewEvalCtx(),
 	); err != nil {
 		return nil, err
 	}
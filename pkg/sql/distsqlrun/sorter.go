@@ -49,6 +49,11 @@ type sorterBase struct {
 	// close is a callback provided by the sorter that is called the first time
 	// producerMeta is called.
 	close func()
+
+	// memMonitor and diskMonitor, if set, track the memory and disk usage of
+	// the sorter alone, which is reported in its execution statistics.
+	memMonitor  *mon.BytesMonitor
+	diskMonitor *mon.BytesMonitor
 }
 
 func (s *sorterBase) init(
@@ -94,6 +99,14 @@ func (s *sorterBase) closeAndQueueTrailingMeta(err error) *ProducerMetadata {
 	if err != nil {
 		s.meta = append(s.meta, ProducerMetadata{Err: err})
 	}
+	var extraStats []extraStat
+	if s.memMonitor != nil {
+		extraStats = append(extraStats, maxMemoryStat(s.memMonitor))
+	}
+	if s.diskMonitor != nil {
+		extraStats = append(extraStats, maxDiskStat(s.diskMonitor))
+	}
+	s.recordStats(s.span, extraStats...)
 	if trace := getTraceData(s.ctx); trace != nil {
 		s.meta = append(s.meta, ProducerMetadata{TraceData: trace})
 	}
@@ -156,10 +169,9 @@ func newSorter(
 type sortAllProcessor struct {
 	sorterBase
 
-	useTempStorage  bool
-	diskContainer   *diskRowContainer
-	rows            memRowContainer
-	rowContainerMon *mon.BytesMonitor
+	useTempStorage bool
+	diskContainer  *diskRowContainer
+	rows           memRowContainer
 
 	// The following variables are used by the state machine, and are used by Next()
 	// to determine where to resume emitting rows.
@@ -177,28 +189,29 @@ func newSortAllProcessor(
 	useTempStorage := settingUseTempStorageSorts.Get(&flowCtx.Settings.SV) ||
 		flowCtx.testingKnobs.MemoryLimitBytes > 0
 
-	rowContainerMon := flowCtx.EvalCtx.Mon
+	// The rows are accounted for by a child monitor, so that the memory used
+	// by this processor can be reported.
+	var limit int64
 	if useTempStorage {
 		// We will use the sortAllProcessor in this case and potentially fall
 		// back to disk.
-		// Limit the memory use by creating a child monitor with a hard limit.
+		// Limit the memory use by giving the child monitor a hard limit.
 		// The processor will overflow to disk if this limit is not enough.
-		limit := flowCtx.testingKnobs.MemoryLimitBytes
+		limit = flowCtx.testingKnobs.MemoryLimitBytes
 		if limit <= 0 {
 			limit = settingWorkMemBytes.Get(&flowCtx.Settings.SV)
 		}
-		limitedMon := mon.MakeMonitorInheritWithLimit(
-			"sortall-limited", limit, flowCtx.EvalCtx.Mon,
-		)
-		limitedMon.Start(flowCtx.Ctx, rowContainerMon, mon.BoundAccount{})
-		rowContainerMon = &limitedMon
 	}
+	limitedMon := mon.MakeMonitorInheritWithLimit(
+		"sortall-limited", limit, flowCtx.EvalCtx.Mon,
+	)
+	limitedMon.Start(flowCtx.Ctx, flowCtx.EvalCtx.Mon, mon.BoundAccount{})
 
 	proc := &sortAllProcessor{
-		rowContainerMon: rowContainerMon,
-		useTempStorage:  useTempStorage,
+		useTempStorage: useTempStorage,
 	}
-	proc.rows.initWithMon(ordering, input.OutputTypes(), flowCtx.NewEvalCtx(), rowContainerMon)
+	proc.memMonitor = &limitedMon
+	proc.rows.initWithMon(ordering, input.OutputTypes(), flowCtx.NewEvalCtx(), proc.memMonitor)
 	if err := proc.sorterBase.init(
 		flowCtx, input, post, out,
 		convertToColumnOrdering(spec.OutputOrdering),
@@ -261,8 +274,9 @@ func (s *sortAllProcessor) fill() error {
 			return errors.Wrap(err, "external storage for large queries disabled")
 		}
 		log.VEventf(ctx, 2, "falling back to disk")
+		s.diskMonitor = newMonitor(ctx, s.flowCtx.diskMonitor, "sortall-disk")
 		diskContainer := makeDiskRowContainer(
-			ctx, s.flowCtx.diskMonitor, s.rows.types, s.rows.ordering, s.tempStorage,
+			ctx, s.diskMonitor, s.rows.types, s.rows.ordering, s.tempStorage,
 		)
 		s.diskContainer = &diskContainer
 
@@ -345,9 +359,10 @@ func (s *sortAllProcessor) close() {
 		ctx := s.evalCtx.Ctx()
 		if s.diskContainer != nil {
 			s.diskContainer.Close(ctx)
+			s.diskMonitor.Stop(ctx)
 		}
 		s.rows.Close(ctx)
-		s.rowContainerMon.Stop(ctx)
+		s.memMonitor.Stop(ctx)
 	}
 	if s.internalClose() {
 		s.input.ConsumerClosed()
@@ -0,0 +1,263 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package distsqlrun
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	opentracing "github.com/opentracing/opentracing-go"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/humanizeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/tracing"
+)
+
+// The execution statistics of a processor are reported through span tags on
+// the processor's span, so that they travel to the gateway along with the
+// rest of the trace data. processorIDTagKey identifies the processor in the
+// physical plan (see ProcessorSpec.ProcessorID) and statsTagKey holds the
+// JSON encoding of the processor's ProcessorStats.
+const (
+	processorIDTagKey = "cockroach.processorid"
+	statsTagKey       = "cockroach.stats"
+)
+
+// InputStats are the statistics collected by an InputStatCollector.
+type InputStats struct {
+	// NumRows is the number of rows produced by the input.
+	NumRows int64
+	// StallTime is the time spent waiting for the input to produce a row or
+	// metadata.
+	StallTime time.Duration
+}
+
+// ProcessorStats are the statistics collected during the execution of a
+// processor.
+type ProcessorStats struct {
+	// Inputs are the statistics of the processor's inputs.
+	Inputs []InputStats
+	// RowsOut is the number of rows produced by the processor.
+	RowsOut int64
+	// WallTime is the time elapsed between the moment the processor started
+	// running and the moment its statistics were recorded.
+	WallTime time.Duration
+
+	// The following statistics are only collected by some processors; they
+	// are nil for the others.
+
+	// MaxMemory is the maximum number of bytes allocated by the processor's
+	// memory monitor.
+	MaxMemory *int64
+	// MaxDisk is the maximum number of bytes allocated by the processor's
+	// disk monitor.
+	MaxDisk *int64
+	// KVBytesRead is the number of bytes read from the KV layer.
+	KVBytesRead *int64
+}
+
+// DisplayStat is a statistic formatted for display.
+type DisplayStat struct {
+	Name  string
+	Value string
+}
+
+// Display returns the statistics formatted for display, in a stable order.
+func (s *ProcessorStats) Display() []DisplayStat {
+	res := make([]DisplayStat, 0, 2*len(s.Inputs)+5)
+	for i, in := range s.Inputs {
+		prefix := ""
+		if len(s.Inputs) > 1 {
+			prefix = fmt.Sprintf("input %d ", i)
+		}
+		res = append(res,
+			DisplayStat{Name: prefix + "rows in", Value: strconv.FormatInt(in.NumRows, 10)},
+			DisplayStat{Name: prefix + "stall time", Value: in.StallTime.String()},
+		)
+	}
+	res = append(res,
+		DisplayStat{Name: "rows out", Value: strconv.FormatInt(s.RowsOut, 10)},
+		DisplayStat{Name: "wall time", Value: s.WallTime.String()},
+	)
+	if s.MaxMemory != nil {
+		res = append(res, DisplayStat{Name: "max memory", Value: humanizeutil.IBytes(*s.MaxMemory)})
+	}
+	if s.MaxDisk != nil {
+		res = append(res, DisplayStat{Name: "max disk", Value: humanizeutil.IBytes(*s.MaxDisk)})
+	}
+	if s.KVBytesRead != nil {
+		res = append(res, DisplayStat{
+			Name: "kv bytes read", Value: humanizeutil.IBytes(*s.KVBytesRead),
+		})
+	}
+	return res
+}
+
+// InputStatCollector wraps a RowSource and collects statistics from it.
+type InputStatCollector struct {
+	RowSource
+	InputStats
+}
+
+var _ RowSource = &InputStatCollector{}
+
+// NewInputStatCollector creates a new InputStatCollector that wraps the given
+// input.
+func NewInputStatCollector(input RowSource) *InputStatCollector {
+	return &InputStatCollector{RowSource: input}
+}
+
+// Next is part of the RowSource interface.
+func (isc *InputStatCollector) Next() (sqlbase.EncDatumRow, *ProducerMetadata) {
+	start := timeutil.Now()
+	row, meta := isc.RowSource.Next()
+	if row != nil {
+		isc.NumRows++
+	}
+	isc.StallTime += timeutil.Since(start)
+	return row, meta
+}
+
+// processorStats holds the state used by a processor to collect its
+// execution statistics.
+type processorStats struct {
+	processorID int32
+	inputs      []*InputStatCollector
+	// start is the time at which the processor started running.
+	start time.Time
+}
+
+// statsCollector is implemented by processors that can collect execution
+// statistics.
+type statsCollector interface {
+	setupStats(processorID int32, inputs []*InputStatCollector)
+}
+
+var _ statsCollector = &processorBase{}
+
+// setupStats enables the collection of execution statistics for the
+// processor. The inputs are the collectors wrapping the processor's inputs.
+func (pb *processorBase) setupStats(processorID int32, inputs []*InputStatCollector) {
+	pb.stats = &processorStats{processorID: processorID, inputs: inputs}
+}
+
+// startStats records the time at which the processor started running. Only
+// the first call has an effect.
+func (pb *processorBase) startStats() {
+	if pb.stats != nil && pb.stats.start.IsZero() {
+		pb.stats.start = timeutil.Now()
+	}
+}
+
+// recordStats sets the statistics collected so far as tags on span, so that
+// they are included in the trace data the processor sends to its consumer.
+// It must be called before the trace data is collected. extra sets the
+// statistics that are specific to the processor.
+func (pb *processorBase) recordStats(span opentracing.Span, extra ...extraStat) {
+	if pb.stats == nil || span == nil {
+		return
+	}
+	s := pb.stats
+	stats := ProcessorStats{
+		Inputs:  make([]InputStats, len(s.inputs)),
+		RowsOut: pb.out.numRowsOut,
+	}
+	for i, in := range s.inputs {
+		stats.Inputs[i] = in.InputStats
+	}
+	if !s.start.IsZero() {
+		stats.WallTime = timeutil.Since(s.start)
+	}
+	for _, f := range extra {
+		f(&stats)
+	}
+
+	encoded, err := json.Marshal(&stats)
+	if err != nil {
+		log.Warningf(pb.ctx, "unable to encode the statistics of processor %d: %v", s.processorID, err)
+		return
+	}
+	span.SetTag(processorIDTagKey, s.processorID)
+	span.SetTag(statsTagKey, string(encoded))
+}
+
+// extraStat sets a statistic that is only collected by some processors.
+type extraStat func(*ProcessorStats)
+
+// maxMemoryStat returns a statistic reporting the maximum number of bytes
+// allocated by the given memory monitor.
+func maxMemoryStat(m *mon.BytesMonitor) extraStat {
+	return func(s *ProcessorStats) {
+		maxBytes := m.MaximumBytes()
+		s.MaxMemory = &maxBytes
+	}
+}
+
+// maxDiskStat returns a statistic reporting the maximum number of bytes
+// allocated by the given disk monitor.
+func maxDiskStat(m *mon.BytesMonitor) extraStat {
+	return func(s *ProcessorStats) {
+		maxBytes := m.MaximumBytes()
+		s.MaxDisk = &maxBytes
+	}
+}
+
+// kvBytesReadStat returns a statistic reporting the number of bytes read
+// from the KV layer by the given RowFetcher.
+func kvBytesReadStat(rf *sqlbase.RowFetcher) extraStat {
+	return func(s *ProcessorStats) {
+		bytesRead := rf.GetBytesRead()
+		s.KVBytesRead = &bytesRead
+	}
+}
+
+// newMonitor creates and starts a child monitor of parent, with the
+// parent's limit. Processors use their own monitor so that the maximum
+// number of bytes they allocate can be reported. The returned monitor must
+// be stopped.
+func newMonitor(ctx context.Context, parent *mon.BytesMonitor, name string) *mon.BytesMonitor {
+	monitor := mon.MakeMonitorInheritWithLimit(name, 0 /* limit */, parent)
+	monitor.Start(ctx, parent, mon.BoundAccount{})
+	return &monitor
+}
+
+// ExtractStatsFromSpans returns the execution statistics recorded by the
+// processors in the given spans, keyed by processor ID. Spans that do not
+// carry statistics are ignored.
+func ExtractStatsFromSpans(spans []tracing.RecordedSpan) map[int32]*ProcessorStats {
+	res := make(map[int32]*ProcessorStats)
+	for _, span := range spans {
+		idStr, ok := span.Tags[processorIDTagKey]
+		if !ok {
+			continue
+		}
+		id, err := strconv.ParseInt(idStr, 10, 32)
+		if err != nil {
+			continue
+		}
+		var stats ProcessorStats
+		if err := json.Unmarshal([]byte(span.Tags[statsTagKey]), &stats); err != nil {
+			continue
+		}
+		res[int32(id)] = &stats
+	}
+	return res
+}
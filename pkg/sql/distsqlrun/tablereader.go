@@ -23,6 +23,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/internal/client"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/scrub"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/util"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
@@ -81,7 +82,7 @@ func newTableReader(
 
 	if _, _, err := initRowFetcher(
 		&tr.fetcher, &tr.tableDesc, int(spec.IndexIdx), spec.Reverse,
-		neededColumns, spec.IsCheck, &tr.alloc,
+		neededColumns, spec.IsCheck, &tr.alloc, flowCtx.NewEvalCtx(),
 	); err != nil {
 		return nil, err
 	}
@@ -125,6 +126,7 @@ func initRowFetcher(
 	valNeededForCol util.FastIntSet,
 	isCheck bool,
 	alloc *sqlbase.DatumAlloc,
+	evalCtx *tree.EvalContext,
 ) (index *sqlbase.IndexDescriptor, isSecondaryIndex bool, err error) {
 	index, isSecondaryIndex, err = desc.FindIndexByIndexIdx(indexIdx)
 	if err != nil {
@@ -143,6 +145,7 @@ func initRowFetcher(
 		IsSecondaryIndex: isSecondaryIndex,
 		Cols:             desc.Columns,
 		ValNeededForCol:  valNeededForCol,
+		EvalCtx:          evalCtx,
 	}
 	if err := fetcher.Init(
 		reverseScan, true /* returnRangeInfo */, isCheck, alloc, tableArgs,
@@ -183,6 +186,7 @@ func (tr *tableReader) producerMeta(err error) *ProducerMetadata {
 		if ranges != nil {
 			tr.trailingMetadata = append(tr.trailingMetadata, ProducerMetadata{Ranges: ranges})
 		}
+		tr.recordStats(tr.span, kvBytesReadStat(&tr.fetcher))
 		traceData := getTraceData(tr.ctx)
 		if traceData != nil {
 			tr.trailingMetadata = append(tr.trailingMetadata, ProducerMetadata{TraceData: traceData})
@@ -75,6 +75,7 @@ func (v *valuesProcessor) close() {
 func (v *valuesProcessor) producerMeta(err error) *ProducerMetadata {
 	var meta *ProducerMetadata
 	if !v.closed {
+		v.recordStats(v.span)
 		if err != nil {
 			meta = &ProducerMetadata{Err: err}
 		} else if trace := getTraceData(v.ctx); trace != nil {
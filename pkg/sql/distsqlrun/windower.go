@@ -0,0 +1,727 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package distsqlrun
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"unsafe"
+
+	"github.com/pkg/errors"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/builtins"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/types"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+)
+
+// GetWindowFunctionInfo returns windowFunc constructor and the return type
+// when given fn is applied to given inputTypes.
+func GetWindowFunctionInfo(
+	fn WindowerSpec_Func, inputTypes ...sqlbase.ColumnType,
+) (
+	windowConstructor func(*tree.EvalContext) tree.WindowFunc,
+	returnType sqlbase.ColumnType,
+	err error,
+) {
+	var funcStr string
+	switch {
+	case fn.AggregateFunc != nil:
+		if *fn.AggregateFunc == AggregatorSpec_IDENT {
+			return nil, sqlbase.ColumnType{}, errors.Errorf("ident is not a window function")
+		}
+		funcStr = fn.AggregateFunc.String()
+	case fn.WindowFunc != nil:
+		funcStr = fn.WindowFunc.String()
+	default:
+		return nil, sqlbase.ColumnType{}, errors.Errorf(
+			"function is neither an aggregate nor a window function")
+	}
+
+	datumTypes := make([]types.T, len(inputTypes))
+	for i := range inputTypes {
+		datumTypes[i] = inputTypes[i].ToDatumType()
+	}
+
+	builtins := builtins.Builtins[strings.ToLower(funcStr)]
+	for _, b := range builtins {
+		if b.WindowFunc == nil {
+			continue
+		}
+		types := b.Types.Types()
+		if len(types) != len(inputTypes) {
+			continue
+		}
+		match := true
+		for i, t := range types {
+			if !datumTypes[i].Equivalent(t) {
+				match = false
+				break
+			}
+		}
+		if match {
+			// Found!
+			constructWindow := func(evalCtx *tree.EvalContext) tree.WindowFunc {
+				return b.WindowFunc(datumTypes, evalCtx)
+			}
+
+			colTyp, err := sqlbase.DatumTypeToColumnType(b.FixedReturnType())
+			if err != nil {
+				return nil, sqlbase.ColumnType{}, err
+			}
+			return constructWindow, colTyp, nil
+		}
+	}
+	return nil, sqlbase.ColumnType{}, errors.Errorf(
+		"no builtin window function for %s on %v", funcStr, inputTypes,
+	)
+}
+
+// windowFunc holds the execution state of a single window function
+// application, as described by a WindowerSpec_WindowFn.
+type windowFunc struct {
+	create      func(*tree.EvalContext) tree.WindowFunc
+	ordering    sqlbase.ColumnOrdering
+	argIdxStart int
+	argCount    int
+	// argIdxs is set if the arguments are not a contiguous range of columns of
+	// the input rows, in which case they are copied at argIdxStart.
+	argIdxs []uint32
+
+	frame       *tree.WindowFrame
+	startOffset tree.Datum
+	endOffset   tree.Datum
+	rangeOps    tree.WindowRangeOps
+}
+
+// windower is the processor that performs the computation of window functions
+// over the rows of its input. Its input rows are accumulated and sorted on the
+// PARTITION BY columns, so that each partition can then be read in turn and
+// every window function can be computed over it. The output rows consist of
+// the input columns followed by one column per window function.
+//
+// When the rows of the input don't fit in memory, they are spilled to disk
+// while being sorted. Each individual partition still needs to fit in memory.
+type windower struct {
+	processorBase
+
+	evalCtx *tree.EvalContext
+
+	input       RowSource
+	inputTypes  []sqlbase.ColumnType
+	partitionBy []uint32
+	windowFns   []windowFunc
+
+	// tempStorage is used to store rows when the working set is larger than can
+	// be stored in memory.
+	tempStorage     engine.Engine
+	useTempStorage  bool
+	diskContainer   *diskRowContainer
+	rows            memRowContainer
+	rowContainerMon *mon.BytesMonitor
+	i               rowIterator
+
+	// partition holds the decoded rows of the partition currently being
+	// emitted, and results the values of the window functions for each of
+	// these rows. emitIdx is the index of the next row of partition to emit.
+	partition    []tree.IndexedRow
+	results      [][]tree.Datum
+	emitIdx      int
+	partitionAcc mon.BoundAccount
+
+	datumAlloc sqlbase.DatumAlloc
+	outputRow  sqlbase.EncDatumRow
+
+	// meta stores metadata that the windower has accumulated for pushing later.
+	meta   []ProducerMetadata
+	closed bool
+}
+
+var _ Processor = &windower{}
+var _ RowSource = &windower{}
+
+func newWindower(
+	flowCtx *FlowCtx, spec *WindowerSpec, input RowSource, post *PostProcessSpec, output RowReceiver,
+) (*windower, error) {
+	w := &windower{
+		input:       input,
+		inputTypes:  input.OutputTypes(),
+		partitionBy: spec.PartitionBy,
+		tempStorage: flowCtx.TempStorage,
+		evalCtx:     flowCtx.NewEvalCtx(),
+	}
+
+	outputTypes := make([]sqlbase.ColumnType, len(w.inputTypes), len(w.inputTypes)+len(spec.WindowFns))
+	copy(outputTypes, w.inputTypes)
+	w.windowFns = make([]windowFunc, len(spec.WindowFns))
+	for i, fnSpec := range spec.WindowFns {
+		fn := &w.windowFns[i]
+
+		argTypes := make([]sqlbase.ColumnType, len(fnSpec.ArgIdxs))
+		contiguous := true
+		for j, idx := range fnSpec.ArgIdxs {
+			if int(idx) >= len(w.inputTypes) {
+				return nil, errors.Errorf("invalid argument index %d", idx)
+			}
+			if j > 0 && idx != fnSpec.ArgIdxs[j-1]+1 {
+				contiguous = false
+			}
+			argTypes[j] = w.inputTypes[idx]
+		}
+		// Window functions look up their arguments as a contiguous range of
+		// columns of the rows of the partition. If the arguments aren't
+		// contiguous in the input rows, they are copied after the input columns
+		// when the function is computed.
+		fn.argCount = len(fnSpec.ArgIdxs)
+		if !contiguous {
+			fn.argIdxs = fnSpec.ArgIdxs
+			fn.argIdxStart = len(w.inputTypes)
+		} else if fn.argCount > 0 {
+			fn.argIdxStart = int(fnSpec.ArgIdxs[0])
+		}
+
+		create, outputType, err := GetWindowFunctionInfo(fnSpec.Func, argTypes...)
+		if err != nil {
+			return nil, err
+		}
+		fn.create = create
+		outputTypes = append(outputTypes, outputType)
+
+		fn.ordering = convertToColumnOrdering(fnSpec.Ordering)
+		if fnSpec.Frame != nil {
+			if err := fn.initFrame(w.evalCtx, fnSpec.Frame, w.inputTypes); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	useTempStorage := settingUseTempStorageSorts.Get(&flowCtx.Settings.SV) ||
+		flowCtx.testingKnobs.MemoryLimitBytes > 0
+	rowContainerMon := flowCtx.EvalCtx.Mon
+	if useTempStorage {
+		// Limit the memory use by creating a child monitor with a hard limit.
+		// The windower will overflow to disk if this limit is not enough.
+		limit := flowCtx.testingKnobs.MemoryLimitBytes
+		if limit <= 0 {
+			limit = settingWorkMemBytes.Get(&flowCtx.Settings.SV)
+		}
+		limitedMon := mon.MakeMonitorInheritWithLimit(
+			"windower-limited", limit, flowCtx.EvalCtx.Mon,
+		)
+		limitedMon.Start(flowCtx.Ctx, rowContainerMon, mon.BoundAccount{})
+		rowContainerMon = &limitedMon
+	}
+	w.useTempStorage = useTempStorage
+	w.rowContainerMon = rowContainerMon
+
+	partitionOrdering := make(sqlbase.ColumnOrdering, len(w.partitionBy))
+	for i, idx := range w.partitionBy {
+		if int(idx) >= len(w.inputTypes) {
+			return nil, errors.Errorf("invalid partition column index %d", idx)
+		}
+		partitionOrdering[i] = sqlbase.ColumnOrderInfo{
+			ColIdx: int(idx), Direction: encoding.Ascending,
+		}
+	}
+	w.rows.initWithMon(partitionOrdering, w.inputTypes, w.evalCtx, rowContainerMon)
+	w.partitionAcc = flowCtx.EvalCtx.Mon.MakeBoundAccount()
+	w.outputRow = make(sqlbase.EncDatumRow, len(outputTypes))
+
+	if err := w.init(post, outputTypes, flowCtx, w.evalCtx, output); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// initFrame converts the frame specification of a window function, evaluating
+// the offsets of its bounds.
+func (fn *windowFunc) initFrame(
+	evalCtx *tree.EvalContext, spec *WindowerSpec_Frame, inputTypes []sqlbase.ColumnType,
+) error {
+	frame := &tree.WindowFrame{}
+	switch spec.Mode {
+	case WindowerSpec_Frame_RANGE:
+		frame.Mode = tree.RANGE
+	case WindowerSpec_Frame_ROWS:
+		frame.Mode = tree.ROWS
+	default:
+		return errors.Errorf("unexpected window frame mode %s", spec.Mode)
+	}
+
+	convertBound := func(spec WindowerSpec_Frame_Bound) (*tree.WindowFrameBound, tree.Datum, error) {
+		bound := &tree.WindowFrameBound{}
+		switch spec.BoundType {
+		case WindowerSpec_Frame_UNBOUNDED_PRECEDING:
+			bound.BoundType = tree.UnboundedPreceding
+		case WindowerSpec_Frame_OFFSET_PRECEDING:
+			bound.BoundType = tree.ValuePreceding
+		case WindowerSpec_Frame_CURRENT_ROW:
+			bound.BoundType = tree.CurrentRow
+		case WindowerSpec_Frame_OFFSET_FOLLOWING:
+			bound.BoundType = tree.ValueFollowing
+		case WindowerSpec_Frame_UNBOUNDED_FOLLOWING:
+			bound.BoundType = tree.UnboundedFollowing
+		default:
+			return nil, nil, errors.Errorf("unexpected window frame bound type %s", spec.BoundType)
+		}
+		if !bound.HasOffset() {
+			return bound, nil, nil
+		}
+		expr, err := processExpression(spec.Offset, &tree.IndexedVarHelper{})
+		if err != nil {
+			return nil, nil, err
+		}
+		if expr == nil {
+			return nil, nil, errors.Errorf("missing offset for window frame bound %s", spec.BoundType)
+		}
+		offset, err := expr.Eval(evalCtx)
+		if err != nil {
+			return nil, nil, err
+		}
+		bound.OffsetExpr = offset
+		return bound, offset, nil
+	}
+
+	var err error
+	frame.Bounds.StartBound, fn.startOffset, err = convertBound(spec.Bounds.Start)
+	if err != nil {
+		return err
+	}
+	if spec.Bounds.End != nil {
+		frame.Bounds.EndBound, fn.endOffset, err = convertBound(*spec.Bounds.End)
+		if err != nil {
+			return err
+		}
+	}
+	fn.frame = frame
+
+	if frame.Mode == tree.RANGE && (fn.startOffset != nil || fn.endOffset != nil) {
+		if len(fn.ordering) != 1 {
+			return errors.Errorf(
+				"RANGE with offset PRECEDING/FOLLOWING requires exactly one ORDER BY column")
+		}
+		offset := fn.startOffset
+		if offset == nil {
+			offset = fn.endOffset
+		}
+		ordType := inputTypes[fn.ordering[0].ColIdx].ToDatumType()
+		var ok bool
+		fn.rangeOps, ok = tree.LookupWindowRangeOps(ordType, offset.ResolvedType())
+		if !ok {
+			return errors.Errorf(
+				"RANGE with offset PRECEDING/FOLLOWING is not supported for column type %s", ordType)
+		}
+	}
+	return nil
+}
+
+// Run is part of the processor interface.
+func (w *windower) Run(wg *sync.WaitGroup) {
+	if w.out.output == nil {
+		panic("windower output not initialized for emitting rows")
+	}
+	Run(w.flowCtx.Ctx, w, w.out.output)
+	if wg != nil {
+		wg.Done()
+	}
+}
+
+// Next is part of the RowSource interface.
+func (w *windower) Next() (sqlbase.EncDatumRow, *ProducerMetadata) {
+	if w.maybeStart("windower", "Windower") {
+		if err := w.fill(); err != nil {
+			return nil, w.closeAndQueueTrailingMeta(err)
+		}
+	}
+	if w.closed {
+		return nil, w.popTrailingMeta()
+	}
+
+	for {
+		if w.emitIdx >= len(w.partition) {
+			// The current partition has been emitted entirely; move on to the
+			// next one.
+			if err := w.nextPartition(); err != nil {
+				return nil, w.closeAndQueueTrailingMeta(err)
+			}
+			if len(w.partition) == 0 {
+				return nil, w.closeAndQueueTrailingMeta(nil)
+			}
+		}
+
+		row := w.partition[w.emitIdx].Row
+		for i, d := range row {
+			w.outputRow[i] = sqlbase.DatumToEncDatum(w.inputTypes[i], d)
+		}
+		for i, d := range w.results[w.emitIdx] {
+			idx := len(w.inputTypes) + i
+			w.outputRow[idx] = sqlbase.DatumToEncDatum(w.out.outputTypes[idx], d)
+		}
+		w.emitIdx++
+
+		outRow, status, err := w.out.ProcessRow(w.ctx, w.outputRow)
+		if outRow != nil {
+			return outRow, nil
+		}
+		if outRow == nil && err == nil && status == NeedMoreRows {
+			continue
+		}
+		return nil, w.closeAndQueueTrailingMeta(err)
+	}
+}
+
+// fill accumulates all the rows from the input and sorts them on the
+// PARTITION BY columns. If the rows don't fit in memory, they are moved to a
+// diskRowContainer.
+func (w *windower) fill() error {
+	ctx := w.evalCtx.Ctx()
+	row, err := w.fillWithContainer(ctx, &w.rows)
+	if err != nil {
+		// We return the memory error if the row is nil because this case implies
+		// that we received the memory error from a code path that was not adding
+		// a row (e.g. from an upstream processor).
+		if pgErr, ok := pgerror.GetPGCause(err); !(ok && pgErr.Code == pgerror.CodeOutOfMemoryError) || row == nil {
+			return err
+		}
+		if !w.useTempStorage {
+			return errors.Wrap(err, "external storage for large queries disabled")
+		}
+		log.VEventf(ctx, 2, "falling back to disk")
+		diskContainer := makeDiskRowContainer(
+			ctx, w.flowCtx.diskMonitor, w.rows.types, w.rows.ordering, w.tempStorage,
+		)
+		w.diskContainer = &diskContainer
+
+		// Transfer the rows from memory to disk. This frees up the memory taken up
+		// by w.rows.
+		i := w.rows.NewIterator(ctx)
+		for i.Rewind(); ; i.Next() {
+			if ok, err := i.Valid(); err != nil {
+				return err
+			} else if !ok {
+				break
+			}
+			memRow, err := i.Row()
+			if err != nil {
+				return err
+			}
+			if err := w.diskContainer.AddRow(ctx, memRow); err != nil {
+				return err
+			}
+		}
+		w.i = nil
+
+		// Add the row that caused the memory container to run out of memory.
+		if err := w.diskContainer.AddRow(ctx, row); err != nil {
+			return err
+		}
+
+		// Continue and fill the rest of the rows from the input.
+		if _, err := w.fillWithContainer(ctx, w.diskContainer); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fillWithContainer reads the rows from the input into the given container.
+// If an error occurs while adding a row to the given container, the row is
+// returned in order to not lose it.
+func (w *windower) fillWithContainer(
+	ctx context.Context, r sortableRowContainer,
+) (sqlbase.EncDatumRow, error) {
+	for {
+		row, meta := w.input.Next()
+		if meta != nil {
+			w.meta = append(w.meta, *meta)
+			continue
+		}
+		if row == nil {
+			break
+		}
+
+		if err := r.AddRow(ctx, row); err != nil {
+			return row, err
+		}
+	}
+	r.Sort(ctx)
+
+	w.i = r.NewIterator(ctx)
+	w.i.Rewind()
+
+	return nil, nil
+}
+
+// nextPartition reads the next partition from the sorted rows and computes the
+// window functions over it. w.partition is left empty if there are no more
+// rows.
+func (w *windower) nextPartition() error {
+	ctx := w.evalCtx.Ctx()
+	w.partitionAcc.Clear(ctx)
+	w.partition = w.partition[:0]
+	w.results = nil
+	w.emitIdx = 0
+
+	for {
+		if ok, err := w.i.Valid(); err != nil {
+			return err
+		} else if !ok {
+			break
+		}
+		encRow, err := w.i.Row()
+		if err != nil {
+			return err
+		}
+		row := make(tree.Datums, len(encRow))
+		sz := int64(unsafe.Sizeof(tree.IndexedRow{})) +
+			int64(uintptr(len(row))*unsafe.Sizeof(tree.Datum(nil)))
+		for i := range encRow {
+			if err := encRow[i].EnsureDecoded(&w.inputTypes[i], &w.datumAlloc); err != nil {
+				return err
+			}
+			row[i] = encRow[i].Datum
+			sz += int64(row[i].Size())
+		}
+		if len(w.partition) > 0 && !w.samePartition(w.partition[0].Row, row) {
+			// This row starts the next partition; it will be decoded again when
+			// that partition is read.
+			break
+		}
+		if err := w.partitionAcc.Grow(ctx, sz); err != nil {
+			return err
+		}
+		w.partition = append(w.partition, tree.IndexedRow{Idx: len(w.partition), Row: row})
+		w.i.Next()
+	}
+
+	if len(w.partition) == 0 {
+		return nil
+	}
+	return w.computeWindowFunctions(ctx)
+}
+
+// samePartition returns whether the two rows have equal PARTITION BY values.
+func (w *windower) samePartition(a, b tree.Datums) bool {
+	for _, idx := range w.partitionBy {
+		if a[idx].Compare(w.evalCtx, b[idx]) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// computeWindowFunctions computes the value of every window function for each
+// row of the current partition, and stores them in w.results.
+func (w *windower) computeWindowFunctions(ctx context.Context) error {
+	rowCount := len(w.partition)
+	windowCount := len(w.windowFns)
+
+	resSz := uintptr(rowCount)*unsafe.Sizeof([]tree.Datum{}) +
+		uintptr(rowCount*windowCount)*unsafe.Sizeof(tree.Datum(nil))
+	if err := w.partitionAcc.Grow(ctx, int64(resSz)); err != nil {
+		return err
+	}
+	w.results = make([][]tree.Datum, rowCount)
+	resultAlloc := make([]tree.Datum, rowCount*windowCount)
+	for i := range w.results {
+		w.results[i] = resultAlloc[i*windowCount : (i+1)*windowCount]
+	}
+
+	if err := w.partitionAcc.Grow(
+		ctx, int64(uintptr(rowCount)*unsafe.Sizeof(tree.IndexedRow{})),
+	); err != nil {
+		return err
+	}
+	rows := make([]tree.IndexedRow, rowCount)
+
+	for fnIdx := range w.windowFns {
+		fn := &w.windowFns[fnIdx]
+
+		// Every window function sorts the partition according to its own ORDER
+		// BY clause, starting from the same order so that window functions with
+		// equivalent orderings see the rows in the same order.
+		copy(rows, w.partition)
+		if fn.argIdxs != nil {
+			if err := w.partitionAcc.Grow(
+				ctx, int64(uintptr(rowCount*len(fn.argIdxs))*unsafe.Sizeof(tree.Datum(nil))),
+			); err != nil {
+				return err
+			}
+			for i := range rows {
+				row := rows[i].Row
+				row = append(row[:len(row):len(row)], make(tree.Datums, len(fn.argIdxs))...)
+				for j, idx := range fn.argIdxs {
+					row[fn.argIdxStart+j] = row[idx]
+				}
+				rows[i].Row = row
+			}
+		}
+		sorter := &windowSorter{evalCtx: w.evalCtx, rows: rows, ordering: fn.ordering}
+		if len(fn.ordering) > 0 {
+			sort.Sort(sorter)
+		}
+
+		frame := tree.WindowFrameRun{
+			Rows:             rows,
+			ArgIdxStart:      fn.argIdxStart,
+			ArgCount:         fn.argCount,
+			Frame:            fn.frame,
+			StartBoundOffset: fn.startOffset,
+			EndBoundOffset:   fn.endOffset,
+			OrdBinOps:        fn.rangeOps,
+		}
+		if len(fn.ordering) > 0 {
+			frame.OrdColIdx = fn.ordering[0].ColIdx
+			frame.OrdDescending = fn.ordering[0].Direction == encoding.Descending
+		}
+
+		builtin := fn.create(w.evalCtx)
+		for frame.RowIdx < rowCount {
+			// Compute the size of the current peer group.
+			frame.FirstPeerIdx = frame.RowIdx
+			frame.PeerRowCount = 1
+			for ; frame.FirstPeerIdx+frame.PeerRowCount < rowCount; frame.PeerRowCount++ {
+				cur := frame.FirstPeerIdx + frame.PeerRowCount
+				if sorter.Compare(cur, cur-1) != 0 {
+					break
+				}
+			}
+
+			// Perform calculations on each row in the current peer group.
+			for ; frame.RowIdx < frame.FirstPeerIdx+frame.PeerRowCount; frame.RowIdx++ {
+				res, err := builtin.Compute(ctx, w.evalCtx, &frame)
+				if err != nil {
+					builtin.Close(ctx, w.evalCtx)
+					return err
+				}
+				// This may overestimate, because WindowFuncs may perform internal
+				// caching.
+				if err := w.partitionAcc.Grow(ctx, int64(res.Size())); err != nil {
+					builtin.Close(ctx, w.evalCtx)
+					return err
+				}
+				w.results[rows[frame.RowIdx].Idx][fnIdx] = res
+			}
+		}
+		builtin.Close(ctx, w.evalCtx)
+	}
+	return nil
+}
+
+// windowSorter sorts the rows of a partition according to the ORDER BY clause
+// of a window function. Rows that compare equal are peers.
+type windowSorter struct {
+	evalCtx  *tree.EvalContext
+	rows     []tree.IndexedRow
+	ordering sqlbase.ColumnOrdering
+}
+
+// windowSorter implements the sort.Interface interface.
+func (s *windowSorter) Len() int      { return len(s.rows) }
+func (s *windowSorter) Swap(i, j int) { s.rows[i], s.rows[j] = s.rows[j], s.rows[i] }
+func (s *windowSorter) Less(i, j int) bool {
+	if c := s.Compare(i, j); c != 0 {
+		return c < 0
+	}
+	// Break ties on the position of the rows in the partition, which makes the
+	// sort deterministic.
+	return s.rows[i].Idx < s.rows[j].Idx
+}
+
+// Compare compares the ORDER BY values of the rows at i and j.
+func (s *windowSorter) Compare(i, j int) int {
+	ra, rb := s.rows[i].Row, s.rows[j].Row
+	for _, o := range s.ordering {
+		if c := ra[o.ColIdx].Compare(s.evalCtx, rb[o.ColIdx]); c != 0 {
+			if o.Direction != encoding.Ascending {
+				return -c
+			}
+			return c
+		}
+	}
+	return 0
+}
+
+// closeAndQueueTrailingMeta closes the windower and puts it in a mode where
+// future calls to Next() will return only "trailing metadata". The first such
+// piece of metadata is returned.
+//
+// If an error is passed in, it will be part of the trailing metadata.
+func (w *windower) closeAndQueueTrailingMeta(err error) *ProducerMetadata {
+	if w.closed {
+		log.Fatalf(w.ctx, "closeAndQueueTrailingMeta() called after close. err: %v", err)
+	}
+
+	if err != nil {
+		w.meta = append(w.meta, ProducerMetadata{Err: err})
+	}
+	if trace := getTraceData(w.ctx); trace != nil {
+		w.meta = append(w.meta, ProducerMetadata{TraceData: trace})
+	}
+	w.close()
+
+	return w.popTrailingMeta()
+}
+
+// popTrailingMeta peels off one piece of trailing metadata.
+func (w *windower) popTrailingMeta() *ProducerMetadata {
+	if len(w.meta) > 0 {
+		meta := &w.meta[0]
+		w.meta = w.meta[1:]
+		return meta
+	}
+	return nil
+}
+
+func (w *windower) close() {
+	// The row containers require a context, so must be closed before
+	// internalClose().
+	if !w.closed {
+		if w.i != nil {
+			w.i.Close()
+		}
+		ctx := w.evalCtx.Ctx()
+		if w.diskContainer != nil {
+			w.diskContainer.Close(ctx)
+		}
+		w.rows.Close(ctx)
+		w.partitionAcc.Close(ctx)
+		if w.useTempStorage {
+			w.rowContainerMon.Stop(ctx)
+		}
+	}
+	if w.internalClose() {
+		w.input.ConsumerClosed()
+		w.closed = true
+	}
+}
+
+// ConsumerDone is part of the RowSource interface.
+func (w *windower) ConsumerDone() {
+	w.input.ConsumerDone()
+}
+
+// ConsumerClosed is part of the RowSource interface.
+func (w *windower) ConsumerClosed() {
+	// The consumer is done, Next() will not be called again.
+	w.close()
+}
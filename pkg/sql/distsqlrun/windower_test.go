@@ -0,0 +1,248 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package distsqlrun
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+)
+
+func TestWindower(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	columnTypeInt := sqlbase.ColumnType{SemanticType: sqlbase.ColumnType_INT}
+	columnTypeFloat := sqlbase.ColumnType{SemanticType: sqlbase.ColumnType_FLOAT}
+	v := [10]sqlbase.EncDatum{}
+	for i := range v {
+		v[i] = sqlbase.DatumToEncDatum(columnTypeInt, tree.NewDInt(tree.DInt(i)))
+	}
+	f := func(x float64) sqlbase.EncDatum {
+		return sqlbase.DatumToEncDatum(columnTypeFloat, tree.NewDFloat(tree.DFloat(x)))
+	}
+
+	rowNumber := WindowerSpec_ROW_NUMBER
+	rank := WindowerSpec_RANK
+	percentRank := WindowerSpec_PERCENT_RANK
+	lag := WindowerSpec_LAG
+	max := AggregatorSpec_MAX
+	asc := Ordering{Columns: []Ordering_Column{{ColIdx: 1, Direction: Ordering_Column_ASC}}}
+	desc := Ordering{Columns: []Ordering_Column{{ColIdx: 1, Direction: Ordering_Column_DESC}}}
+
+	input := sqlbase.EncDatumRows{
+		{v[1], v[3], v[5]},
+		{v[2], v[1], v[6]},
+		{v[1], v[1], v[7]},
+		{v[2], v[2], v[8]},
+		{v[1], v[2], v[9]},
+		{v[1], v[2], v[4]},
+	}
+
+	testCases := []struct {
+		spec     WindowerSpec
+		types    []sqlbase.ColumnType
+		expected sqlbase.EncDatumRows
+	}{
+		{
+			// SELECT a, b, c, row_number() OVER (PARTITION BY a ORDER BY b, c)
+			spec: WindowerSpec{
+				PartitionBy: []uint32{0},
+				WindowFns: []WindowerSpec_WindowFn{{
+					Func: WindowerSpec_Func{WindowFunc: &rowNumber},
+					Ordering: Ordering{Columns: []Ordering_Column{
+						{ColIdx: 1, Direction: Ordering_Column_ASC},
+						{ColIdx: 2, Direction: Ordering_Column_ASC},
+					}},
+				}},
+			},
+			types: []sqlbase.ColumnType{columnTypeInt, columnTypeInt, columnTypeInt, columnTypeInt},
+			expected: sqlbase.EncDatumRows{
+				{v[1], v[1], v[7], v[1]},
+				{v[1], v[2], v[4], v[2]},
+				{v[1], v[2], v[9], v[3]},
+				{v[1], v[3], v[5], v[4]},
+				{v[2], v[1], v[6], v[1]},
+				{v[2], v[2], v[8], v[2]},
+			},
+		},
+		{
+			// SELECT a, b, c, rank() OVER (ORDER BY b DESC),
+			//   percent_rank() OVER (ORDER BY b)
+			spec: WindowerSpec{
+				WindowFns: []WindowerSpec_WindowFn{
+					{Func: WindowerSpec_Func{WindowFunc: &rank}, Ordering: desc},
+					{Func: WindowerSpec_Func{WindowFunc: &percentRank}, Ordering: asc},
+				},
+			},
+			types: []sqlbase.ColumnType{
+				columnTypeInt, columnTypeInt, columnTypeInt, columnTypeInt, columnTypeFloat,
+			},
+			expected: sqlbase.EncDatumRows{
+				{v[1], v[1], v[7], v[5], f(0)},
+				{v[1], v[2], v[4], v[2], f(0.4)},
+				{v[1], v[2], v[9], v[2], f(0.4)},
+				{v[1], v[3], v[5], v[1], f(1)},
+				{v[2], v[1], v[6], v[5], f(0)},
+				{v[2], v[2], v[8], v[2], f(0.4)},
+			},
+		},
+		{
+			// SELECT a, b, c, max(c) OVER (PARTITION BY a ORDER BY b, c
+			//   ROWS BETWEEN 1 PRECEDING AND 1 FOLLOWING)
+			spec: WindowerSpec{
+				PartitionBy: []uint32{0},
+				WindowFns: []WindowerSpec_WindowFn{{
+					Func:    WindowerSpec_Func{AggregateFunc: &max},
+					ArgIdxs: []uint32{2},
+					Ordering: Ordering{Columns: []Ordering_Column{
+						{ColIdx: 1, Direction: Ordering_Column_ASC},
+						{ColIdx: 2, Direction: Ordering_Column_ASC},
+					}},
+					Frame: &WindowerSpec_Frame{
+						Mode: WindowerSpec_Frame_ROWS,
+						Bounds: WindowerSpec_Frame_Bounds{
+							Start: WindowerSpec_Frame_Bound{
+								BoundType: WindowerSpec_Frame_OFFSET_PRECEDING,
+								Offset:    Expression{Expr: "1"},
+							},
+							End: &WindowerSpec_Frame_Bound{
+								BoundType: WindowerSpec_Frame_OFFSET_FOLLOWING,
+								Offset:    Expression{Expr: "1"},
+							},
+						},
+					},
+				}},
+			},
+			types: []sqlbase.ColumnType{columnTypeInt, columnTypeInt, columnTypeInt, columnTypeInt},
+			expected: sqlbase.EncDatumRows{
+				{v[1], v[1], v[7], v[7]},
+				{v[1], v[2], v[4], v[9]},
+				{v[1], v[2], v[9], v[9]},
+				{v[1], v[3], v[5], v[9]},
+				{v[2], v[1], v[6], v[8]},
+				{v[2], v[2], v[8], v[8]},
+			},
+		},
+		{
+			// SELECT a, b, c, lag(c, a) OVER (PARTITION BY a ORDER BY c)
+			// The arguments are not contiguous in the input rows.
+			spec: WindowerSpec{
+				PartitionBy: []uint32{0},
+				WindowFns: []WindowerSpec_WindowFn{{
+					Func:     WindowerSpec_Func{WindowFunc: &lag},
+					ArgIdxs:  []uint32{2, 0},
+					Ordering: Ordering{Columns: []Ordering_Column{{ColIdx: 2}}},
+				}},
+			},
+			types: []sqlbase.ColumnType{columnTypeInt, columnTypeInt, columnTypeInt, columnTypeInt},
+			expected: sqlbase.EncDatumRows{
+				{v[1], v[1], v[7], v[5]},
+				{v[1], v[2], v[4], sqlbase.DatumToEncDatum(columnTypeInt, tree.DNull)},
+				{v[1], v[2], v[9], v[7]},
+				{v[1], v[3], v[5], v[4]},
+				{v[2], v[1], v[6], sqlbase.DatumToEncDatum(columnTypeInt, tree.DNull)},
+				{v[2], v[2], v[8], sqlbase.DatumToEncDatum(columnTypeInt, tree.DNull)},
+			},
+		},
+	}
+
+	ctx := context.Background()
+	st := cluster.MakeTestingClusterSettings()
+	tempEngine, err := engine.NewTempEngine(base.DefaultTestTempStorageConfig(st))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tempEngine.Close()
+
+	evalCtx := tree.MakeTestingEvalContext(st)
+	defer evalCtx.Stop(ctx)
+	diskMonitor := mon.MakeMonitor(
+		"test-disk",
+		mon.DiskResource,
+		nil, /* curCount */
+		nil, /* maxHist */
+		-1,  /* increment: use default block size */
+		math.MaxInt64,
+		st,
+	)
+	diskMonitor.Start(ctx, nil /* pool */, mon.MakeStandaloneBudget(math.MaxInt64))
+	defer diskMonitor.Stop(ctx)
+	flowCtx := FlowCtx{
+		Ctx:         ctx,
+		EvalCtx:     evalCtx,
+		Settings:    cluster.MakeTestingClusterSettings(),
+		TempStorage: tempEngine,
+		diskMonitor: &diskMonitor,
+	}
+
+	for _, c := range testCases {
+		// Test with several memory limits:
+		// 0: Use the default limit.
+		// 1: Immediately switch to disk.
+		// 2048: A memory limit that should not be hit; the processor will not
+		// use disk.
+		for _, memLimit := range []int64{0, 1, 2048} {
+			t.Run(fmt.Sprintf("MemLimit=%d", memLimit), func(t *testing.T) {
+				in := NewRowBuffer(threeIntCols, input, RowBufferArgs{})
+				out := &RowBuffer{}
+
+				// Override the default memory limit. This will result in using a
+				// memory row container which will hit this limit and fall back to
+				// using a disk row container.
+				flowCtx.testingKnobs.MemoryLimitBytes = memLimit
+				w, err := newWindower(&flowCtx, &c.spec, in, &PostProcessSpec{}, out)
+				if err != nil {
+					t.Fatal(err)
+				}
+				w.Run(nil)
+				if !out.ProducerClosed {
+					t.Fatalf("output RowReceiver not closed")
+				}
+
+				// The windower emits the partitions in order, but doesn't order
+				// the rows within a partition.
+				var retRows []string
+				for {
+					row := out.NextNoMeta(t)
+					if row == nil {
+						break
+					}
+					retRows = append(retRows, row.String(c.types))
+				}
+				sort.Strings(retRows)
+
+				var expRows []string
+				for _, row := range c.expected {
+					expRows = append(expRows, row.String(c.types))
+				}
+				sort.Strings(expRows)
+
+				if expStr, retStr := fmt.Sprint(expRows), fmt.Sprint(retRows); expStr != retStr {
+					t.Errorf("invalid results; expected:\n   %s\ngot:\n   %s", expStr, retStr)
+				}
+			})
+		}
+	}
+}
@@ -74,7 +74,7 @@ func (p *planner) DropDatabase(ctx context.Context, n *tree.DropDatabase) (planN
 		return nil, err
 	}
 
-	if len(tbNames) > 0 {
+	if len(tbNames) > 0 || len(dbDesc.Functions) > 0 {
 		switch n.DropBehavior {
 		case tree.DropRestrict:
 			return nil, pgerror.NewErrorf(pgerror.CodeDependentObjectsStillExistError,
@@ -154,6 +154,14 @@ func (n *dropDatabaseNode) startExec(params runParams) error {
 	}
 	b.Del(descKey)
 	b.Del(nameKey)
+	// Delete the descriptors of the functions of this database.
+	for _, fn := range n.dbDesc.Functions {
+		fnDescKey := sqlbase.MakeDescMetadataKey(fn.ID)
+		if p.ExtendedEvalContext().Tracing.KVTracingEnabled() {
+			log.VEventf(ctx, 2, "Del %s", fnDescKey)
+		}
+		b.Del(fnDescKey)
+	}
 	// Delete the zone config entry for this database.
 	b.DelRange(zoneKeyPrefix, zoneKeyPrefix.PrefixEnd(), false /* returnKeys */)
 
@@ -210,6 +218,12 @@ func (p *planner) filterCascadedTables(ctx context.Context, tables []toDelete) (
 func (p *planner) accumulateDependentTables(
 	ctx context.Context, dependentTables map[sqlbase.ID]bool, desc *sqlbase.TableDescriptor,
 ) error {
+	// Owned sequences are dropped along with the table that owns them.
+	for i := range desc.Columns {
+		for _, seqID := range desc.Columns[i].OwnsSequenceIds {
+			dependentTables[seqID] = true
+		}
+	}
 	for _, ref := range desc.DependedOnBy {
 		dependentTables[ref.ID] = true
 		dependentDesc, err := sqlbase.GetTableDescFromID(ctx, p.txn, ref.ID)
@@ -0,0 +1,128 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+type dropFunctionNode struct {
+	n     *tree.DropFunction
+	names []*tree.TableName
+	fns   []*sqlbase.FunctionDescriptor
+	// dbDescs holds the descriptors of the databases containing the
+	// dropped functions, indexed by ID, so that a database descriptor is
+	// written only once when several of its functions are dropped.
+	dbDescs map[sqlbase.ID]*sqlbase.DatabaseDescriptor
+}
+
+// DropFunction drops user-defined functions.
+// Privileges: DROP on function.
+//   notes: postgres requires ownership of the function.
+func (p *planner) DropFunction(ctx context.Context, n *tree.DropFunction) (planNode, error) {
+	node := &dropFunctionNode{
+		n:       n,
+		dbDescs: make(map[sqlbase.ID]*sqlbase.DatabaseDescriptor),
+	}
+	for i := range n.Names {
+		tn, err := n.Names[i].Normalize()
+		if err != nil {
+			return nil, err
+		}
+		var dbDesc *DatabaseDescriptor
+		var fnDesc *sqlbase.FunctionDescriptor
+		p.runWithOptions(resolveFlags{skipCache: true}, func() {
+			dbDesc, fnDesc, err = ResolveExistingFunction(ctx, p, tn, !n.IfExists)
+		})
+		if err != nil {
+			return nil, err
+		}
+		if fnDesc == nil {
+			// IfExists specified and function does not exist.
+			continue
+		}
+		if err := p.CheckPrivilege(ctx, fnDesc, privilege.DROP); err != nil {
+			return nil, err
+		}
+		if _, ok := node.dbDescs[dbDesc.ID]; !ok {
+			node.dbDescs[dbDesc.ID] = dbDesc
+		}
+		node.names = append(node.names, tn)
+		node.fns = append(node.fns, fnDesc)
+	}
+
+	if len(node.fns) == 0 {
+		return newZeroNode(nil /* columns */), nil
+	}
+	return node, nil
+}
+
+func (n *dropFunctionNode) startExec(params runParams) error {
+	ctx := params.ctx
+	p := params.p
+
+	b := p.txn.NewBatch()
+	for _, fn := range n.fns {
+		n.dbDescs[fn.ParentID].RemoveFunction(fn.Name)
+		descKey := sqlbase.MakeDescMetadataKey(fn.ID)
+		if p.ExtendedEvalContext().Tracing.KVTracingEnabled() {
+			log.VEventf(ctx, 2, "Del %s", descKey)
+		}
+		b.Del(descKey)
+	}
+	for _, dbDesc := range n.dbDescs {
+		if err := dbDesc.Validate(); err != nil {
+			return err
+		}
+		dbDescKey := sqlbase.MakeDescMetadataKey(dbDesc.ID)
+		if p.ExtendedEvalContext().Tracing.KVTracingEnabled() {
+			log.VEventf(ctx, 2, "Put %s -> %s", dbDescKey, dbDesc)
+		}
+		b.Put(dbDescKey, sqlbase.WrapDescriptor(dbDesc))
+	}
+	if err := p.txn.Run(ctx, b); err != nil {
+		return err
+	}
+
+	for i, fn := range n.fns {
+		// Log a Drop Function event for this function. This is an
+		// auditable log event and is recorded in the same transaction as
+		// the descriptor updates.
+		if err := MakeEventLogger(params.extendedEvalCtx.ExecCfg).InsertEventRecord(
+			ctx,
+			p.txn,
+			EventLogDropFunction,
+			int32(fn.ID),
+			int32(params.extendedEvalCtx.NodeID),
+			struct {
+				FunctionName string
+				Statement    string
+				User         string
+			}{n.names[i].FQString(), n.n.String(), p.SessionData().User},
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (*dropFunctionNode) Next(runParams) (bool, error) { return false, nil }
+func (*dropFunctionNode) Values() tree.Datums          { return tree.Datums{} }
+func (*dropFunctionNode) Close(context.Context)        {}
@@ -76,7 +76,7 @@ func (n *dropIndexNode) startExec(params runParams) error {
 		var err error
 		params.p.runWithOptions(resolveFlags{allowAdding: true, skipCache: true}, func() {
 			tableDesc, err = ResolveExistingObject(
-				ctx, params.p, index.tn, true /*required*/, requireTableDesc)
+				ctx, params.p, index.tn, true /*required*/, requireTableOrMaterializedViewDesc)
 		})
 		if err != nil {
 			// Somehow the descriptor we had during newPlan() is not there
@@ -199,6 +199,9 @@ func (p *planner) dropIndexByName(
 			droppedViews = append(droppedViews, cascadedViews...)
 		}
 	}
+	// The hidden columns storing the values of the index expressions are
+	// dropped along with the last index using them.
+	idxColumnIDs := append([]sqlbase.ColumnID(nil), idx.ColumnIDs...)
 	found := false
 	for i := range tableDesc.Indexes {
 		if tableDesc.Indexes[i].ID == idx.ID {
@@ -213,6 +216,7 @@ func (p *planner) dropIndexByName(
 	if !found {
 		return fmt.Errorf("index %q in the middle of being added, try again later", idxName)
 	}
+	dropUnusedIndexExprColumns(tableDesc, idxColumnIDs)
 
 	if err := tableDesc.Validate(ctx, p.txn, p.EvalContext().Settings); err != nil {
 		return err
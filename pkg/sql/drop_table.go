@@ -91,6 +91,11 @@ func (p *planner) DropTable(ctx context.Context, n *tree.DropTable) (planNode, e
 				}
 			}
 		}
+		for i := range droppedDesc.Columns {
+			if err := p.canRemoveOwnedSequences(ctx, &droppedDesc.Columns[i], dropping); err != nil {
+				return nil, err
+			}
+		}
 	}
 
 	if len(td) == 0 {
@@ -287,6 +292,13 @@ func (p *planner) dropTableImpl(
 		}
 	}
 
+	// Drop the sequences owned by the columns of the table.
+	for i := range tableDesc.Columns {
+		if err := p.dropSequencesOwnedByCol(ctx, &tableDesc.Columns[i]); err != nil {
+			return droppedViews, err
+		}
+	}
+
 	// Drop all views that depend on this table, assuming that we wouldn't have
 	// made it to this point if `cascade` wasn't enabled.
 	for _, ref := range tableDesc.DependedOnBy {
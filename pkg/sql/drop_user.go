@@ -147,6 +147,24 @@ func (n *DropUserNode) startExec(params runParams) error {
 		}
 	}
 
+	// Then check all the functions.
+	for _, desc := range descs {
+		fn, ok := desc.(*sqlbase.FunctionDescriptor)
+		if !ok {
+			continue
+		}
+		for _, u := range fn.GetPrivileges().Users {
+			if _, ok := userNames[u.User]; ok {
+				if f.Len() > 0 {
+					f.WriteString(", ")
+				}
+				tn := tree.MakeTableName(tree.Name(lCtx.dbNames[fn.ParentID]), tree.Name(fn.Name))
+				f.FormatNode(&tn)
+				break
+			}
+		}
+	}
+
 	// Was there any object dependin on that user?
 	if f.Len() > 0 {
 		fnl := tree.NewFmtCtxWithBuf(tree.FmtSimple)
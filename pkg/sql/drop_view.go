@@ -18,6 +18,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
@@ -49,6 +50,15 @@ func (p *planner) DropView(ctx context.Context, n *tree.DropView) (planNode, err
 			// IfExists specified and the view did not exist.
 			continue
 		}
+		if droppedDesc.MaterializedView() != n.IsMaterialized {
+			if n.IsMaterialized {
+				return nil, pgerror.NewErrorf(pgerror.CodeWrongObjectTypeError,
+					"%q is not a materialized view", tree.ErrString(tn))
+			}
+			return nil, pgerror.NewErrorf(pgerror.CodeWrongObjectTypeError,
+				"%q is a materialized view", tree.ErrString(tn)).SetHintf(
+				"use DROP MATERIALIZED VIEW to remove a materialized view")
+		}
 
 		td = append(td, toDelete{tn, droppedDesc})
 	}
@@ -62,6 +62,11 @@ const (
 	// EventLogAlterSequence is recorded when a sequence is altered.
 	EventLogAlterSequence EventLogType = "alter_sequence"
 
+	// EventLogCreateFunction is recorded when a function is created.
+	EventLogCreateFunction EventLogType = "create_function"
+	// EventLogDropFunction is recorded when a function is dropped.
+	EventLogDropFunction EventLogType = "drop_function"
+
 	// EventLogReverseSchemaChange is recorded when an in-progress schema change
 	// encounters a problem and is reversed.
 	EventLogReverseSchemaChange EventLogType = "reverse_schema_change"
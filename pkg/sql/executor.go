@@ -46,6 +46,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/types"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/stats"
 	"github.com/cockroachdb/cockroach/pkg/util"
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
@@ -279,6 +280,13 @@ type ExecutorConfig struct {
 	RangeDescriptorCache *kv.RangeDescriptorCache
 	LeaseHolderCache     *kv.LeaseHolderCache
 
+	// TableStatsCache caches the table statistics used by the optimizer.
+	TableStatsCache *stats.TableStatisticsCache
+
+	// StatsRefresher refreshes the statistics of the tables modified by
+	// the statements executed on this node.
+	StatsRefresher *StatsRefresher
+
 	// ConnResultsBufferBytes is the size of the buffer in which each connection
 	// accumulates results set. Results are flushed to the network when this
 	// buffer overflows.
@@ -1974,7 +1982,11 @@ func (e *Executor) execDistSQL(
 		},
 	)
 	e.distSQLPlanner.PlanAndRun(ctx, planner.txn, plan, recv, &planner.extendedEvalCtx)
-	return rowResultWriter.Err()
+	if err := rowResultWriter.Err(); err != nil {
+		return err
+	}
+	invalidateCreatedStats(ctx, &e.cfg, plan)
+	return nil
 }
 
 // execLocal runs the current logical plan using the local
@@ -2068,8 +2080,11 @@ func shouldUseOptimizer(optMode sessiondata.OptimizerMode, stmt Statement) bool
 
 	case sessiondata.OptimizerOn:
 		// Only handle a subset of the statement types (currently read-only queries).
-		switch stmt.AST.(type) {
-		case *tree.ParenSelect, *tree.Select, *tree.SelectClause,
+		switch t := stmt.AST.(type) {
+		case *tree.Select:
+			// Row-level locking is only supported by the heuristic planner.
+			return t.Locking == nil
+		case *tree.ParenSelect, *tree.SelectClause,
 			*tree.UnionClause, *tree.ValuesClause:
 			return true
 		case *tree.Explain:
@@ -2220,6 +2235,9 @@ func (e *Executor) execStmt(
 	recordStatementSummary(
 		planner, stmt, useDistSQL, automaticRetryCount, res.RowsAffected(), err, &e.EngineMetrics,
 	)
+	if err == nil {
+		notifyMutatedTable(&e.cfg, planner.curPlan.plan, res.RowsAffected())
+	}
 	if e.cfg.TestingKnobs.AfterExecute != nil {
 		e.cfg.TestingKnobs.AfterExecute(ctx, stmt.String(), err)
 	}
@@ -2289,6 +2307,9 @@ func (e *Executor) execStmtInParallel(
 		planner.statsCollector.PhaseTimes()[plannerEndExecStmt] = timeutil.Now()
 		recordStatementSummary(
 			planner, stmt, false, 0, bufferedWriter.RowsAffected(), err, &e.EngineMetrics)
+		if err == nil {
+			notifyMutatedTable(&e.cfg, planner.curPlan.plan, bufferedWriter.RowsAffected())
+		}
 		if e.cfg.TestingKnobs.AfterExecute != nil {
 			e.cfg.TestingKnobs.AfterExecute(ctx, stmt.String(), err)
 		}
@@ -128,6 +128,12 @@ func doExpandPlan(
 		explainParams := noParamsBase
 		explainParams.atTop = true
 		n.plan, err = doExpandPlan(ctx, p, explainParams, n.plan)
+
+	case *explainAnalyzeNode:
+		// EXPLAIN ANALYZE runs the plan "as if" it was at the top level.
+		explainParams := noParamsBase
+		explainParams.atTop = true
+		n.plan, err = doExpandPlan(ctx, p, explainParams, n.plan)
 		if err != nil {
 			return plan, err
 		}
@@ -183,9 +189,27 @@ func doExpandPlan(
 		}
 		n.left, err = doExpandPlan(ctx, p, params, n.left)
 
+	case *recursiveCTENode:
+		if n.initial != nil {
+			n.initial, err = doExpandPlan(ctx, p, noParams, n.initial)
+			if err != nil {
+				return plan, err
+			}
+		}
+		if n.recursive != nil {
+			n.recursive, err = doExpandPlan(ctx, p, noParams, n.recursive)
+		}
+
 	case *filterNode:
 		n.source.plan, err = doExpandPlan(ctx, p, params, n.source.plan)
 
+	case *applyJoinNode:
+		n.left.plan, err = doExpandPlan(ctx, p, noParams, n.left.plan)
+		if err != nil {
+			return plan, err
+		}
+		n.right.plan, err = doExpandPlan(ctx, p, noParams, n.right.plan)
+
 	case *joinNode:
 		n.left.plan, err = doExpandPlan(ctx, p, noParams, n.left.plan)
 		if err != nil {
@@ -305,17 +329,21 @@ func doExpandPlan(
 	case *CreateUserNode:
 	case *createViewNode:
 	case *createSequenceNode:
+	case *refreshViewNode:
+	case *createFunctionNode:
 	case *createStatsNode:
 	case *dropDatabaseNode:
 	case *dropIndexNode:
 	case *dropTableNode:
 	case *dropViewNode:
 	case *dropSequenceNode:
+	case *dropFunctionNode:
 	case *DropUserNode:
 	case *zeroNode:
 	case *unaryNode:
 	case *hookFnNode:
 	case *valueGenerator:
+	case *scanBufferNode:
 	case *sequenceSelectNode:
 	case *setVarNode:
 	case *setClusterSettingNode:
@@ -569,6 +597,9 @@ func (p *planner) simplifyOrderings(plan planNode, usefulOrdering sqlbase.Column
 	case *explainDistSQLNode:
 		n.plan = p.simplifyOrderings(n.plan, nil)
 
+	case *explainAnalyzeNode:
+		n.plan = p.simplifyOrderings(n.plan, nil)
+
 	case *showTraceNode:
 		n.plan = p.simplifyOrderings(n.plan, nil)
 
@@ -588,10 +619,22 @@ func (p *planner) simplifyOrderings(plan planNode, usefulOrdering sqlbase.Column
 		n.right = p.simplifyOrderings(n.right, nil)
 		n.left = p.simplifyOrderings(n.left, nil)
 
+	case *recursiveCTENode:
+		if n.initial != nil {
+			n.initial = p.simplifyOrderings(n.initial, nil)
+		}
+		if n.recursive != nil {
+			n.recursive = p.simplifyOrderings(n.recursive, nil)
+		}
+
 	case *filterNode:
 		n.source.plan = p.simplifyOrderings(n.source.plan, usefulOrdering)
 		n.computePhysicalProps(p.EvalContext())
 
+	case *applyJoinNode:
+		n.left.plan = p.simplifyOrderings(n.left.plan, nil)
+		n.right.plan = p.simplifyOrderings(n.right.plan, nil)
+
 	case *joinNode:
 		// In DistSQL, we may take advantage of matching orderings on equality
 		// columns and use merge joins. Preserve the orderings in that case.
@@ -718,17 +761,21 @@ func (p *planner) simplifyOrderings(plan planNode, usefulOrdering sqlbase.Column
 	case *CreateUserNode:
 	case *createViewNode:
 	case *createSequenceNode:
+	case *refreshViewNode:
+	case *createFunctionNode:
 	case *createStatsNode:
 	case *dropDatabaseNode:
 	case *dropIndexNode:
 	case *dropTableNode:
 	case *dropViewNode:
 	case *dropSequenceNode:
+	case *dropFunctionNode:
 	case *DropUserNode:
 	case *zeroNode:
 	case *unaryNode:
 	case *hookFnNode:
 	case *valueGenerator:
+	case *scanBufferNode:
 	case *sequenceSelectNode:
 	case *setVarNode:
 	case *setClusterSettingNode:
@@ -31,11 +31,16 @@ const (
 	// query would be run in "auto" DISTSQL mode. See explainDistSQLNode for
 	// details.
 	explainDistSQL
+	// explainOpt shows the expression tree built by the cost-based optimizer
+	// for a query, along with the estimated statistics and cost of each
+	// expression. See makeExplainOptNode for details.
+	explainOpt
 )
 
 var explainStrings = map[explainMode]string{
 	explainPlan:    "plan",
 	explainDistSQL: "distsql",
+	explainOpt:     "opt",
 }
 
 // Explain executes the explain statement, providing debugging and analysis
@@ -43,6 +48,10 @@ var explainStrings = map[explainMode]string{
 //
 // Privileges: the same privileges as the statement being explained.
 func (p *planner) Explain(ctx context.Context, n *tree.Explain) (planNode, error) {
+	if n.Analyze {
+		return p.makeExplainAnalyzeNode(ctx, n)
+	}
+
 	mode := explainNone
 
 	optimized := true
@@ -130,6 +139,9 @@ func (p *planner) Explain(ctx context.Context, n *tree.Explain) (planNode, error
 			plan: plan,
 		}, nil
 
+	case explainOpt:
+		return p.makeExplainOptNode(ctx, n.Statement)
+
 	case explainPlan:
 		// We may want to show placeholder types, so allow missing values.
 		p.semaCtx.Placeholders.PermitUnassigned()
@@ -0,0 +1,384 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	opentracing "github.com/opentracing/opentracing-go"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/distsqlrun"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/types"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/humanizeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/tracing"
+	"github.com/cockroachdb/cockroach/pkg/util/treeprinter"
+)
+
+// explainAnalyzeNode is a planNode that runs a plan and returns the
+// statistics collected during the execution, for each plan node and, when
+// the plan runs under DistSQL, for each processor. The results of the plan
+// are discarded.
+type explainAnalyzeNode struct {
+	optColumnsSlot
+
+	plan planNode
+
+	// distSQL is set if the statistics are shown in a DistSQL plan diagram
+	// (EXPLAIN ANALYZE (DISTSQL)) instead of one row per statistic.
+	distSQL bool
+
+	run explainAnalyzeRun
+}
+
+// explainAnalyzeRun contains the run-time state of explainAnalyzeNode during
+// local execution.
+type explainAnalyzeRun struct {
+	// rows are the rows returned by the node.
+	rows []tree.Datums
+	// curRow is the index of the row returned by Values().
+	curRow int
+}
+
+// makeExplainAnalyzeNode creates the plan for EXPLAIN ANALYZE. The only
+// option supported is DISTSQL.
+func (p *planner) makeExplainAnalyzeNode(ctx context.Context, n *tree.Explain) (planNode, error) {
+	distSQL := false
+	for _, opt := range n.Options {
+		if strings.ToLower(opt) != explainStrings[explainDistSQL] {
+			return nil, fmt.Errorf("unsupported EXPLAIN ANALYZE option: %s", opt)
+		}
+		distSQL = true
+	}
+	plan, err := p.newPlan(ctx, n.Statement, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &explainAnalyzeNode{plan: plan, distSQL: distSQL}, nil
+}
+
+func (n *explainAnalyzeNode) startExec(params runParams) error {
+	auto, err := params.extendedEvalCtx.DistSQLPlanner.CheckSupport(n.plan)
+	if err != nil {
+		if n.distSQL {
+			return pgerror.NewErrorf(pgerror.CodeFeatureNotSupportedError,
+				"EXPLAIN ANALYZE (DISTSQL) cannot run the statement under DistSQL: %v", err)
+		}
+		// The plan cannot run under DistSQL: run it locally. Only the
+		// statistics of the plan nodes are available then.
+		return n.runLocal(params)
+	}
+	return n.runDistSQL(params, auto)
+}
+
+// runLocal runs the plan with the local execution engine. The plan nodes
+// are not instrumented: the statistics of the whole plan are attributed to
+// its root node, which says so in the output, and the scans only report the
+// number of bytes they read.
+func (n *explainAnalyzeNode) runLocal(params runParams) error {
+	start := timeutil.Now()
+	if err := startPlan(params, n.plan); err != nil {
+		return err
+	}
+	rowsOut, err := countRowsAffected(params, n.plan)
+	if err != nil {
+		return err
+	}
+	wallTime := timeutil.Since(start)
+
+	n.addPlanNodeRows(params.ctx, func(plan planNode) []distsqlrun.DisplayStat {
+		var stats []distsqlrun.DisplayStat
+		if plan == n.plan {
+			stats = append(stats,
+				distsqlrun.DisplayStat{Name: "rows out", Value: strconv.Itoa(rowsOut)},
+				distsqlrun.DisplayStat{Name: "wall time", Value: wallTime.String()},
+				distsqlrun.DisplayStat{
+					Name:  "note",
+					Value: "statistics of the whole statement; per-node statistics are unavailable in local execution",
+				},
+			)
+		}
+		if scan, ok := plan.(*scanNode); ok {
+			stats = append(stats, distsqlrun.DisplayStat{
+				Name: "kv bytes read", Value: humanizeutil.IBytes(scan.fetcher.GetBytesRead()),
+			})
+		}
+		return stats
+	})
+	return nil
+}
+
+// runDistSQL runs the plan under DistSQL, with the processors collecting
+// execution statistics. auto is the result of the DistSQL support check,
+// which is shown in the plan diagram.
+func (n *explainAnalyzeNode) runDistSQL(params runParams, auto bool) error {
+	// Trigger limit propagation.
+	params.p.setUnlimited(n.plan)
+
+	distSQLPlanner := params.extendedEvalCtx.DistSQLPlanner
+	execCfg := params.p.ExecCfg()
+
+	// The processors send their statistics back to the gateway along with
+	// their trace data.
+	const opName = "explain analyze"
+	var sp opentracing.Span
+	if parentSp := opentracing.SpanFromContext(params.ctx); parentSp != nil {
+		sp = parentSp.Tracer().StartSpan(
+			opName, opentracing.ChildOf(parentSp.Context()), tracing.Recordable)
+	} else {
+		sp = execCfg.AmbientCtx.Tracer.StartSpan(opName, tracing.Recordable)
+	}
+	tracing.StartRecording(sp, tracing.SnowballRecording)
+	defer sp.Finish()
+	defer tracing.StopRecording(sp)
+	ctx := opentracing.ContextWithSpan(params.ctx, sp)
+
+	planCtx := distSQLPlanner.newPlanningCtx(ctx, params.extendedEvalCtx, params.p.txn)
+	planCtx.collectStats = true
+	planCtx.annotatePlanNodes = true
+	plan, err := distSQLPlanner.createPlanForNode(&planCtx, n.plan)
+	if err != nil {
+		return err
+	}
+	distSQLPlanner.FinalizePlan(&planCtx, &plan)
+
+	rowResultWriter := newCallbackResultWriter(func(context.Context, tree.Datums) error {
+		return nil
+	})
+	recv := makeDistSQLReceiver(
+		ctx,
+		&rowResultWriter,
+		tree.Rows,
+		execCfg.RangeDescriptorCache,
+		execCfg.LeaseHolderCache,
+		params.p.txn,
+		func(ts hlc.Timestamp) {
+			_ = execCfg.Clock.Update(ts)
+		},
+	)
+	distSQLPlanner.Run(&planCtx, params.p.txn, &plan, recv, params.extendedEvalCtx)
+	if err := rowResultWriter.Err(); err != nil {
+		return err
+	}
+
+	stats := distsqlrun.ExtractStatsFromSpans(tracing.GetRecording(sp))
+	flows := plan.GenerateFlowSpecs(params.extendedEvalCtx.NodeID)
+
+	if n.distSQL {
+		planJSON, planURL, err := distsqlrun.GenerateAnnotatedPlanDiagramWithURL(flows, stats)
+		if err != nil {
+			return err
+		}
+		n.run.rows = []tree.Datums{{
+			tree.MakeDBool(tree.DBool(auto)),
+			tree.NewDString(planURL.String()),
+			tree.NewDString(planJSON),
+		}}
+		return nil
+	}
+
+	// Produce the rows of each plan node, with the statistics of the
+	// processors that execute it. The processors are identified by their
+	// index in the physical plan (see GenerateFlowSpecs).
+	nodeStats := make(map[planNode]*planNodeStats)
+	getNodeStats := func(idx int) *planNodeStats {
+		node := planCtx.planNodes[idx-1]
+		s, ok := nodeStats[node]
+		if !ok {
+			s = &planNodeStats{}
+			nodeStats[node] = s
+		}
+		return s
+	}
+	for i := range plan.Processors {
+		procStats, ok := stats[int32(i)]
+		if !ok {
+			// The processor did not report statistics.
+			procStats = &distsqlrun.ProcessorStats{}
+		}
+		if idx := plan.Processors[i].PlanNode; idx != 0 {
+			getNodeStats(idx).addProcessor(i, procStats)
+		}
+		if idx := plan.Processors[i].OutputOf; idx != 0 {
+			getNodeStats(idx).addOutput(procStats)
+		}
+	}
+	n.addPlanNodeRows(ctx, func(plan planNode) []distsqlrun.DisplayStat {
+		if s, ok := nodeStats[plan]; ok {
+			return s.stats()
+		}
+		return nil
+	})
+
+	// Produce the rows of each processor, in the order of the physical plan.
+	type nodeProc struct {
+		node tree.Datum
+		spec *distsqlrun.ProcessorSpec
+	}
+	var procs []nodeProc
+	for nodeID, flow := range flows {
+		node := tree.NewDInt(tree.DInt(nodeID))
+		for i := range flow.Processors {
+			procs = append(procs, nodeProc{node: node, spec: &flow.Processors[i]})
+		}
+	}
+	sort.Slice(procs, func(i, j int) bool {
+		return procs[i].spec.ProcessorID < procs[j].spec.ProcessorID
+	})
+	for _, proc := range procs {
+		id := tree.NewDInt(tree.DInt(proc.spec.ProcessorID))
+		addRow := func(field, description string) {
+			n.run.rows = append(n.run.rows, tree.Datums{
+				emptyString, id, proc.node, tree.NewDString(field), tree.NewDString(description),
+			})
+		}
+		title, details := distsqlrun.ProcessorSummary(proc.spec)
+		addRow("type", title)
+		for _, detail := range details {
+			addRow("spec", detail)
+		}
+		if procStats, ok := stats[proc.spec.ProcessorID]; ok {
+			for _, stat := range procStats.Display() {
+				addRow(stat.Name, stat.Value)
+			}
+		}
+	}
+	return nil
+}
+
+// addPlanNodeRows produces the rows that describe the plan nodes, in the
+// format of EXPLAIN, with the statistics returned by nodeStats for each
+// node.
+func (n *explainAnalyzeNode) addPlanNodeRows(
+	ctx context.Context, nodeStats func(planNode) []distsqlrun.DisplayStat,
+) {
+	var entries []explainEntry
+	level := 0
+	_ = walkPlan(ctx, n.plan, planObserver{
+		enterNode: func(_ context.Context, name string, plan planNode) (bool, error) {
+			entries = append(entries, explainEntry{level: level, node: name, plan: plan})
+			for _, stat := range nodeStats(plan) {
+				entries = append(entries, explainEntry{
+					level: level, field: stat.Name, fieldVal: stat.Value,
+				})
+			}
+			level++
+			return true, nil
+		},
+		leaveNode: func(string, planNode) error {
+			level--
+			return nil
+		},
+	})
+
+	tp := treeprinter.New()
+	// nodes keeps track of the current node on each level.
+	nodes := []treeprinter.Node{tp}
+	for _, entry := range entries {
+		if entry.plan != nil {
+			nodes = append(nodes[:entry.level+1], nodes[entry.level].Child(entry.node))
+		} else {
+			tp.AddEmptyLine()
+		}
+	}
+	treeRows := tp.FormattedRows()
+	for i, entry := range entries {
+		n.run.rows = append(n.run.rows, tree.Datums{
+			tree.NewDString(treeRows[i]),
+			tree.DNull,
+			tree.DNull,
+			tree.NewDString(entry.field),
+			tree.NewDString(entry.fieldVal),
+		})
+	}
+}
+
+// planNodeStats accumulates the statistics of the processors that execute
+// a plan node.
+type planNodeStats struct {
+	// processors are the processors created for the plan node.
+	processors []string
+	// wallTime is the longest wall time of these processors, which run in
+	// parallel.
+	wallTime time.Duration
+	// rowsOut is the number of rows produced by the processors that produce
+	// the results of the plan node, if any. When the processing of a plan
+	// node was merged into the processors of its parent, only the parent
+	// reports the rows it produces.
+	rowsOut    int64
+	hasRowsOut bool
+}
+
+func (s *planNodeStats) addProcessor(id int, stats *distsqlrun.ProcessorStats) {
+	s.processors = append(s.processors, strconv.Itoa(id))
+	if stats.WallTime > s.wallTime {
+		s.wallTime = stats.WallTime
+	}
+}
+
+func (s *planNodeStats) addOutput(stats *distsqlrun.ProcessorStats) {
+	s.hasRowsOut = true
+	s.rowsOut += stats.RowsOut
+}
+
+// stats returns the statistics of the plan node, formatted for display.
+func (s *planNodeStats) stats() []distsqlrun.DisplayStat {
+	var res []distsqlrun.DisplayStat
+	if len(s.processors) > 0 {
+		res = append(res, distsqlrun.DisplayStat{
+			Name: "processors", Value: strings.Join(s.processors, ", "),
+		})
+	}
+	if s.hasRowsOut {
+		res = append(res, distsqlrun.DisplayStat{
+			Name: "rows out", Value: strconv.FormatInt(s.rowsOut, 10),
+		})
+	}
+	if len(s.processors) > 0 {
+		res = append(res, distsqlrun.DisplayStat{Name: "wall time", Value: s.wallTime.String()})
+	}
+	return res
+}
+
+func (n *explainAnalyzeNode) Next(runParams) (bool, error) {
+	if n.run.curRow >= len(n.run.rows) {
+		return false, nil
+	}
+	n.run.curRow++
+	return true, nil
+}
+
+func (n *explainAnalyzeNode) Values() tree.Datums       { return n.run.rows[n.run.curRow-1] }
+func (n *explainAnalyzeNode) Close(ctx context.Context) { n.plan.Close(ctx) }
+
+// explainAnalyzeColumns are the columns of EXPLAIN ANALYZE. The rows that
+// describe plan nodes have a NULL Processor and Node; the rows that describe
+// processors have an empty Tree.
+var explainAnalyzeColumns = sqlbase.ResultColumns{
+	{Name: "Tree", Typ: types.String},
+	{Name: "Processor", Typ: types.Int},
+	{Name: "Node", Typ: types.Int},
+	{Name: "Field", Typ: types.String},
+	{Name: "Description", Typ: types.String},
+}
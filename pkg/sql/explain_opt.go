@@ -0,0 +1,58 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"context"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/optbuilder"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/xform"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/types"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+)
+
+var explainOptColumns = sqlbase.ResultColumns{
+	{Name: "text", Typ: types.String},
+}
+
+// makeExplainOptNode instantiates a planNode that returns the expression tree
+// that the cost-based optimizer builds for the given statement, one line per
+// row. Each relational expression is annotated with its estimated statistics,
+// which are derived from the table statistics when they are available.
+func (p *planner) makeExplainOptNode(ctx context.Context, stmt tree.Statement) (planNode, error) {
+	// execEngine is an opt.Catalog. cleanup is not required on the engine,
+	// since planner is cleaned up elsewhere.
+	eng := newExecEngine(p, nil)
+	defer eng.Close()
+
+	o := xform.NewOptimizer(p.EvalContext())
+	bld := optbuilder.New(ctx, &p.semaCtx, p.EvalContext(), eng.Catalog(), o.Factory(), stmt)
+	root, props, err := bld.Build()
+	if err != nil {
+		return nil, err
+	}
+	ev := o.Optimize(root, props)
+
+	v := p.newContainerValuesNode(explainOptColumns, 0)
+	for _, line := range strings.Split(strings.TrimRight(ev.String(), "\n"), "\n") {
+		if _, err := v.rows.AddRow(ctx, tree.Datums{tree.NewDString(line)}); err != nil {
+			v.Close(ctx)
+			return nil, err
+		}
+	}
+	return v, nil
+}
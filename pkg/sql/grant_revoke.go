@@ -17,6 +17,7 @@ package sql
 import (
 	"context"
 
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
@@ -25,7 +26,7 @@ import (
 
 // Grant adds privileges to users.
 // Current status:
-// - Target: single database, table, or view.
+// - Target: single database, table, view, or function.
 // TODO(marc): open questions:
 // - should we have root always allowed and not present in the permissions list?
 // - should we make users case-insensitive?
@@ -33,6 +34,11 @@ import (
 //   Notes: postgres requires the object owner.
 //          mysql requires the "grant option" and the same privileges, and sometimes superuser.
 func (p *planner) Grant(ctx context.Context, n *tree.Grant) (planNode, error) {
+	if n.Targets.Functions != nil {
+		if err := validateFunctionPrivileges(n.Privileges); err != nil {
+			return nil, err
+		}
+	}
 	return p.changePrivileges(ctx, n.Targets, n.Grantees, func(privDesc *sqlbase.PrivilegeDescriptor, grantee string) {
 		privDesc.Grant(grantee, n.Privileges)
 	})
@@ -40,7 +46,7 @@ func (p *planner) Grant(ctx context.Context, n *tree.Grant) (planNode, error) {
 
 // Revoke removes privileges from users.
 // Current status:
-// - Target: single database, table, or view.
+// - Target: single database, table, view, or function.
 // TODO(marc): open questions:
 // - should we have root always allowed and not present in the permissions list?
 // - should we make users case-insensitive?
@@ -48,11 +54,32 @@ func (p *planner) Grant(ctx context.Context, n *tree.Grant) (planNode, error) {
 //   Notes: postgres requires the object owner.
 //          mysql requires the "grant option" and the same privileges, and sometimes superuser.
 func (p *planner) Revoke(ctx context.Context, n *tree.Revoke) (planNode, error) {
+	if n.Targets.Functions != nil {
+		if err := validateFunctionPrivileges(n.Privileges); err != nil {
+			return nil, err
+		}
+		return p.changePrivileges(ctx, n.Targets, n.Grantees, func(privDesc *sqlbase.PrivilegeDescriptor, grantee string) {
+			privDesc.RevokeFunction(grantee, n.Privileges)
+		})
+	}
 	return p.changePrivileges(ctx, n.Targets, n.Grantees, func(privDesc *sqlbase.PrivilegeDescriptor, grantee string) {
 		privDesc.Revoke(grantee, n.Privileges)
 	})
 }
 
+// validateFunctionPrivileges checks that the given privileges can be
+// held on a function.
+func validateFunctionPrivileges(privs privilege.List) error {
+	allowed := privilege.FunctionPrivileges.ToBitField()
+	for _, priv := range privs {
+		if allowed&priv.Mask() == 0 {
+			return pgerror.NewErrorf(pgerror.CodeInvalidGrantOperationError,
+				"invalid privilege type %s for function", priv)
+		}
+	}
+	return nil
+}
+
 func (p *planner) changePrivileges(
 	ctx context.Context,
 	targets tree.TargetList,
@@ -103,6 +130,11 @@ func (p *planner) changePrivileges(
 				return nil, err
 			}
 
+		case *sqlbase.FunctionDescriptor:
+			if err := d.Validate(); err != nil {
+				return nil, err
+			}
+
 		case *sqlbase.TableDescriptor:
 			if err := d.Validate(ctx, p.txn, p.EvalContext().Settings); err != nil {
 				return nil, err
@@ -24,6 +24,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/types"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util"
 	"github.com/cockroachdb/cockroach/pkg/util/encoding"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/mon"
@@ -51,6 +52,13 @@ type groupNode struct {
 	// Indices of the group by columns in the source plan.
 	groupCols []int
 
+	// groupingSets is set if the GROUP BY clause uses GROUPING SETS, ROLLUP or
+	// CUBE. It contains the indices of the group by columns of each grouping
+	// set. The rows are grouped separately for each grouping set, and the
+	// group by columns that are not part of the grouping set of a group are
+	// NULL.
+	groupingSets [][]int
+
 	// funcs are the aggregation functions that the renders use.
 	funcs []*aggregateFuncHolder
 
@@ -106,7 +114,15 @@ func (p *planner) groupBy(
 		return nil, nil, nil
 	}
 
-	groupByExprs := make([]tree.Expr, len(n.GroupBy))
+	// GROUPING SETS, ROLLUP and CUBE are expanded into the grouping
+	// expressions they contain and the grouping sets built from these
+	// expressions.
+	groupBy, groupingSets, err := expandGroupingSets(n.GroupBy)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	groupByExprs := make([]tree.Expr, len(groupBy))
 
 	// In the construction of the renderNode, when renders are processed (via
 	// computeRender()), the expressions are normalized. In order to compare these
@@ -114,7 +130,7 @@ func (p *planner) groupBy(
 	// the GROUP BY expressions as well. This is done before determining if
 	// aggregation is being performed, because that determination is made during
 	// validation, which will require matching expressions.
-	for i, expr := range n.GroupBy {
+	for i, expr := range groupBy {
 		expr = tree.StripParens(expr)
 
 		// Check whether the GROUP BY clause refers to a rendered column
@@ -205,7 +221,9 @@ func (p *planner) groupBy(
 	// the aggregate function directly; there is no need to add a render. See
 	// extractAggregatesVisitor below.
 	groupStrs := make(groupByStrMap, len(groupByExprs))
-	for _, g := range groupByExprs {
+	// groupByCols contains the columns rendered for each GROUP BY expression.
+	groupByCols := make([][]int, len(groupByExprs))
+	for i, g := range groupByExprs {
 		cols, exprs, hasStar, err := p.computeRenderAllowingStars(
 			ctx, tree.SelectExpr{Expr: g}, types.Any, r.sourceInfo, r.ivarHelper,
 			autoGenerateRenderOutputName)
@@ -218,6 +236,7 @@ func (p *planner) groupBy(
 		cols, exprs = flattenTuples(cols, exprs, &r.ivarHelper)
 
 		colIdxs := r.addOrReuseRenders(cols, exprs, true /* reuseExistingRender */)
+		groupByCols[i] = colIdxs
 		if len(colIdxs) == 1 {
 			// We only remember the render if there is a 1:1 correspondence with
 			// the expression written after GROUP BY and the computed renders.
@@ -237,6 +256,18 @@ func (p *planner) groupBy(
 	for i := range group.groupCols {
 		group.groupCols[i] = i
 	}
+	if groupingSets != nil {
+		group.groupingSets = make([][]int, len(groupingSets))
+		for i, set := range groupingSets {
+			var cols util.FastIntSet
+			for _, e := range set {
+				for _, c := range groupByCols[e] {
+					cols.Add(c)
+				}
+			}
+			group.groupingSets[i] = cols.Ordered()
+		}
+	}
 
 	var havingNode *filterNode
 	plan := planNode(group)
@@ -315,7 +346,8 @@ func (p *planner) groupBy(
 	postRender.sourceInfo = sqlbase.MultiSourceInfo{postRender.source.info}
 
 	// Queries like `SELECT MAX(n) FROM t` expect a row of NULLs if nothing was aggregated.
-	group.run.addNullBucketIfEmpty = len(groupByExprs) == 0
+	// With grouping sets, the empty grouping sets play this role.
+	group.run.addNullBucketIfEmpty = len(groupByExprs) == 0 && groupingSets == nil
 
 	// TODO(peter): This memory isn't being accounted for. The similar code in
 	// sql/distsqlrun/aggregator.go does account for the memory.
@@ -334,6 +366,140 @@ func (p *planner) groupBy(
 	return plan, group, nil
 }
 
+// maxGroupingSets is the maximum number of grouping sets a GROUP BY clause can
+// expand to.
+const maxGroupingSets = 4096
+
+// maxCubeElements is the maximum number of elements of a CUBE, which expands to
+// all the subsets of its elements.
+const maxCubeElements = 12
+
+// expandGroupingSets expands the GROUPING SETS, ROLLUP and CUBE elements of a
+// GROUP BY clause. It returns the grouping expressions of the clause and, if
+// the clause uses grouping sets, the grouping sets as lists of indices into
+// these expressions. For example:
+//
+//   GROUP BY a, ROLLUP (b, c)
+//
+// returns the expressions a, b, c and the grouping sets (a, b, c), (a, b) and
+// (a). The grouping sets are nil for an ordinary GROUP BY clause.
+func expandGroupingSets(groupBy tree.GroupBy) (tree.Exprs, [][]int, error) {
+	hasGroupingSets := false
+	for _, e := range groupBy {
+		if isGroupingSetElement(e) {
+			hasGroupingSets = true
+			break
+		}
+	}
+	if !hasGroupingSets {
+		return tree.Exprs(groupBy), nil, nil
+	}
+
+	var exprs tree.Exprs
+	addExprs := func(list tree.Exprs) []int {
+		idxs := make([]int, len(list))
+		for i, e := range list {
+			idxs[i] = len(exprs)
+			exprs = append(exprs, e)
+		}
+		return idxs
+	}
+	tooManyErr := pgerror.NewErrorf(pgerror.CodeProgramLimitExceededError,
+		"too many grouping sets present (maximum %d)", maxGroupingSets)
+
+	// expand returns the grouping sets of a single GROUP BY element.
+	var expand func(e tree.Expr) ([][]int, error)
+	expand = func(e tree.Expr) ([][]int, error) {
+		switch t := tree.StripParens(e).(type) {
+		case *tree.GroupingSet:
+			switch t.Type {
+			case tree.Rollup:
+				// ROLLUP (a, b) is GROUPING SETS ((a, b), (a), ()).
+				idxs := addExprs(t.Exprs)
+				sets := make([][]int, len(idxs)+1)
+				for i := range sets {
+					sets[i] = idxs[:len(idxs)-i]
+				}
+				return sets, nil
+
+			case tree.Cube:
+				// CUBE (a, b) is GROUPING SETS ((a, b), (a), (b), ()).
+				if len(t.Exprs) > maxCubeElements {
+					return nil, pgerror.NewErrorf(pgerror.CodeProgramLimitExceededError,
+						"CUBE is limited to %d elements", maxCubeElements)
+				}
+				idxs := addExprs(t.Exprs)
+				sets := make([][]int, 0, 1<<uint(len(idxs)))
+				for mask := 1<<uint(len(idxs)) - 1; mask >= 0; mask-- {
+					var set []int
+					for i, idx := range idxs {
+						if mask&(1<<uint(len(idxs)-1-i)) != 0 {
+							set = append(set, idx)
+						}
+					}
+					sets = append(sets, set)
+				}
+				return sets, nil
+
+			default:
+				var sets [][]int
+				for _, e := range t.Exprs {
+					elemSets, err := expand(e)
+					if err != nil {
+						return nil, err
+					}
+					sets = append(sets, elemSets...)
+					if len(sets) > maxGroupingSets {
+						return nil, tooManyErr
+					}
+				}
+				return sets, nil
+			}
+
+		case *tree.Tuple:
+			if len(t.Exprs) == 0 {
+				// () is the empty grouping set.
+				return [][]int{nil}, nil
+			}
+		}
+		return [][]int{addExprs(tree.Exprs{e})}, nil
+	}
+
+	// The grouping sets of a GROUP BY clause with several elements are the
+	// concatenations of the grouping sets of each element, e.g.
+	// GROUP BY a, CUBE (b, c) is GROUPING SETS ((a, b, c), (a, b), (a, c), (a)).
+	sets := [][]int{nil}
+	for _, e := range groupBy {
+		elemSets, err := expand(e)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(sets)*len(elemSets) > maxGroupingSets {
+			return nil, nil, tooManyErr
+		}
+		product := make([][]int, 0, len(sets)*len(elemSets))
+		for _, s := range sets {
+			for _, elemSet := range elemSets {
+				product = append(product, append(append([]int(nil), s...), elemSet...))
+			}
+		}
+		sets = product
+	}
+	return exprs, sets, nil
+}
+
+// isGroupingSetElement returns true if e is a GROUPING SETS, ROLLUP or CUBE
+// element of a GROUP BY clause, or the empty grouping set ().
+func isGroupingSetElement(e tree.Expr) bool {
+	switch t := tree.StripParens(e).(type) {
+	case *tree.GroupingSet:
+		return true
+	case *tree.Tuple:
+		return len(t.Exprs) == 0
+	}
+	return false
+}
+
 // groupRun contains the run-time state for groupNode during local execution.
 type groupRun struct {
 	// The set of bucket keys. We add buckets as we are processing input rows, and
@@ -380,33 +546,53 @@ func (n *groupNode) Next(params runParams) (bool, error) {
 
 		// TODO(dt): optimization: skip buckets when underlying plan is ordered by grouped values.
 
-		bucket := scratch
-		for _, idx := range n.groupCols {
-			var err error
-			bucket, err = sqlbase.EncodeDatum(bucket, values[idx])
-			if err != nil {
-				return false, err
-			}
+		// With grouping sets, the row is added to one bucket per grouping set;
+		// the bucket keys are prefixed with the index of the grouping set.
+		numBuckets := 1
+		if n.groupingSets != nil {
+			numBuckets = len(n.groupingSets)
 		}
+		for set := 0; set < numBuckets; set++ {
+			bucket := scratch
+			groupCols := n.groupCols
+			if n.groupingSets != nil {
+				bucket = encoding.EncodeUvarintAscending(bucket, uint64(set))
+				groupCols = n.groupingSets[set]
+			}
+			for _, idx := range groupCols {
+				var err error
+				bucket, err = sqlbase.EncodeDatum(bucket, values[idx])
+				if err != nil {
+					return false, err
+				}
+			}
 
-		n.run.buckets[string(bucket)] = struct{}{}
+			n.run.buckets[string(bucket)] = struct{}{}
 
-		// Feed the aggregateFuncHolders for this bucket the non-grouped values.
-		for _, f := range n.funcs {
-			if f.hasFilter() && values[f.filterRenderIdx] != tree.DBoolTrue {
-				continue
-			}
+			// Feed the aggregateFuncHolders for this bucket the non-grouped values.
+			for _, f := range n.funcs {
+				if f.hasFilter() && values[f.filterRenderIdx] != tree.DBoolTrue {
+					continue
+				}
 
-			var value tree.Datum
-			if f.argRenderIdx != noRenderIdx {
-				value = values[f.argRenderIdx]
-			}
+				var value tree.Datum
+				if f.argRenderIdx != noRenderIdx {
+					value = values[f.argRenderIdx]
+				}
+				var otherArgs tree.Datums
+				if len(f.otherArgRenderIdxs) > 0 {
+					otherArgs = make(tree.Datums, len(f.otherArgRenderIdxs))
+					for i, idx := range f.otherArgRenderIdxs {
+						otherArgs[i] = values[idx]
+					}
+				}
 
-			if err := f.add(params.ctx, params.EvalContext(), bucket, value); err != nil {
-				return false, err
+				if err := f.add(params.ctx, params.EvalContext(), bucket, value, otherArgs); err != nil {
+					return false, err
+				}
 			}
+			scratch = bucket[:0]
 		}
-		scratch = bucket[:0]
 
 		n.run.gotOneRow = true
 	}
@@ -423,7 +609,26 @@ func (n *groupNode) Next(params runParams) (bool, error) {
 	// code in distsqlrun.aggregator performs a single step of copying all of the
 	// buckets to a slice and then releasing the buckets map.
 	delete(n.run.buckets, bucket)
+	var set []int
+	if n.groupingSets != nil {
+		_, idx, err := encoding.DecodeUvarintAscending([]byte(bucket))
+		if err != nil {
+			return false, err
+		}
+		set = n.groupingSets[idx]
+	}
 	for i, f := range n.funcs {
+		if n.groupingSets != nil {
+			// Group by columns that are not part of the grouping set are NULL.
+			if f.isIdentAggregate() && !containsInt(set, f.argRenderIdx) {
+				n.run.values[i] = tree.DNull
+				continue
+			}
+			if f.isGroupingFunc() {
+				n.run.values[i] = f.groupingResult(set)
+				continue
+			}
+		}
 		aggregateFunc, ok := f.run.buckets[bucket]
 		if !ok {
 			// No input for this bucket (possible if f has a FILTER).
@@ -458,6 +663,12 @@ func (n *groupNode) setupOutput() {
 	if len(n.run.buckets) < 1 && n.run.addNullBucketIfEmpty {
 		n.run.buckets[""] = struct{}{}
 	}
+	// Every empty grouping set produces a group, even if there were no rows.
+	for i, set := range n.groupingSets {
+		if len(set) == 0 {
+			n.run.buckets[string(encoding.EncodeUvarintAscending(nil, uint64(i)))] = struct{}{}
+		}
+	}
 	n.run.values = make(tree.Datums, len(n.funcs))
 }
 
@@ -501,7 +712,7 @@ func (n *groupNode) addIsDistinctFromNullFilter(where *filterNode, render *rende
 // instead have another variable (e.g. from the AST) tell us what type
 // of aggregation we're dealing with, and test that here.
 func (n *groupNode) desiredAggregateOrdering(evalCtx *tree.EvalContext) sqlbase.ColumnOrdering {
-	if len(n.groupCols) > 0 {
+	if len(n.groupCols) > 0 || n.groupingSets != nil {
 		return nil
 	}
 
@@ -592,8 +803,32 @@ func (v *extractAggregatesVisitor) VisitPre(expr tree.Expr) (recurse bool, newEx
 	switch t := expr.(type) {
 	case *tree.FuncExpr:
 		if agg := t.GetAggregateConstructor(); agg != nil {
+			if isGroupingFunc(t.Func.String()) {
+				// The arguments of GROUPING must be GROUP BY expressions; they
+				// are already rendered.
+				argRenderIdxs := make([]int, len(t.Exprs))
+				for i, e := range t.Exprs {
+					groupIdx, ok := v.groupStrs[symbolicExprStr(e)]
+					if !ok {
+						v.err = pgerror.NewError(pgerror.CodeGroupingError,
+							"arguments to GROUPING must be grouping expressions of the associated query level")
+						return false, expr
+					}
+					argRenderIdxs[i] = groupIdx
+				}
+				f := v.groupNode.newAggregateFuncHolder(
+					t.Func.String(),
+					t.ResolvedType(),
+					argRenderIdxs[0],
+					agg,
+					v.planner.EvalContext().Mon.MakeBoundAccount(),
+				)
+				f.otherArgRenderIdxs = argRenderIdxs[1:]
+				return false, v.addAggregation(f)
+			}
+
 			var f *aggregateFuncHolder
-			switch len(t.Exprs) {
+			switch len(t.AggregateArgs()) {
 			case 0:
 				// COUNT_ROWS has no arguments.
 				f = v.groupNode.newAggregateFuncHolder(
@@ -604,38 +839,50 @@ func (v *extractAggregatesVisitor) VisitPre(expr tree.Expr) (recurse bool, newEx
 					v.planner.EvalContext().Mon.MakeBoundAccount(),
 				)
 
-			case 1:
-				argExpr := t.Exprs[0].(tree.TypedExpr)
-
-				if err := v.planner.txCtx.AssertNoAggregationOrWindowing(
-					argExpr,
-					fmt.Sprintf("the argument of %s()", &t.Func),
-					v.planner.SessionData().SearchPath,
-				); err != nil {
-					v.err = err
-					return false, expr
+			default:
+				// Ordered-set aggregates receive their WITHIN GROUP sort
+				// expression as the last argument.
+				args := t.AggregateArgs()
+				if t.OrderBy != nil {
+					for _, e := range t.Exprs {
+						if tree.ContainsVars(v.planner.EvalContext(), e) {
+							v.err = pgerror.NewErrorf(pgerror.CodeFeatureNotSupportedError,
+								"direct arguments of ordered-set aggregate %s() must be constant", &t.Func)
+							return false, expr
+						}
+					}
 				}
 
-				// Add a pre-rendering for the argument.
-				col := sqlbase.ResultColumn{
-					Name: argExpr.String(),
-					Typ:  argExpr.ResolvedType(),
+				argRenderIdxs := make([]int, len(args))
+				for i, arg := range args {
+					argExpr := arg.(tree.TypedExpr)
+
+					if err := v.planner.txCtx.AssertNoAggregationOrWindowing(
+						argExpr,
+						fmt.Sprintf("the argument of %s()", &t.Func),
+						v.planner.SessionData().SearchPath,
+					); err != nil {
+						v.err = err
+						return false, expr
+					}
+
+					// Add a pre-rendering for the argument.
+					col := sqlbase.ResultColumn{
+						Name: argExpr.String(),
+						Typ:  argExpr.ResolvedType(),
+					}
+
+					argRenderIdxs[i] = v.preRender.addOrReuseRender(col, argExpr, true /* reuse */)
 				}
 
-				argRenderIdx := v.preRender.addOrReuseRender(col, argExpr, true /* reuse */)
-
 				f = v.groupNode.newAggregateFuncHolder(
 					t.Func.String(),
 					t.ResolvedType(),
-					argRenderIdx,
+					argRenderIdxs[0],
 					agg,
 					v.planner.EvalContext().Mon.MakeBoundAccount(),
 				)
-
-			default:
-				// TODO: #10495
-				v.err = pgerror.UnimplementedWithIssueError(10495, "aggregate functions with multiple arguments are not supported yet")
-				return false, expr
+				f.otherArgRenderIdxs = argRenderIdxs[1:]
 			}
 
 			if t.Type == tree.DistinctFuncType {
@@ -702,6 +949,10 @@ type aggregateFuncHolder struct {
 	// underneath. If the function has no argument (COUNT_ROWS), it is set to
 	// noRenderIdx.
 	argRenderIdx int
+	// Functions with multiple arguments (e.g. percentile_disc, which takes a
+	// fraction and the WITHIN GROUP sort value) receive the remaining
+	// arguments from these renders.
+	otherArgRenderIdxs []int
 	// If there is a filter, the result is a single value produced by the
 	// renderNode underneath. If there is no filter, it is set to noRenderIdx.
 	filterRenderIdx int
@@ -755,6 +1006,39 @@ func (a *aggregateFuncHolder) isIdentAggregate() bool {
 	return a.funcName == ""
 }
 
+// isGroupingFunc returns true if funcName is the GROUPING function.
+func isGroupingFunc(funcName string) bool {
+	return strings.EqualFold(funcName, "grouping")
+}
+
+func (a *aggregateFuncHolder) isGroupingFunc() bool {
+	return isGroupingFunc(a.funcName)
+}
+
+// groupingResult returns the result of a GROUPING function for a group of the
+// given grouping set: a bit mask with a bit set for each argument that is not
+// part of the grouping set, where the last argument corresponds to the least
+// significant bit.
+func (a *aggregateFuncHolder) groupingResult(set []int) tree.Datum {
+	var mask tree.DInt
+	for _, idx := range append([]int{a.argRenderIdx}, a.otherArgRenderIdxs...) {
+		mask <<= 1
+		if !containsInt(set, idx) {
+			mask |= 1
+		}
+	}
+	return tree.NewDInt(mask)
+}
+
+func containsInt(s []int, i int) bool {
+	for _, v := range s {
+		if v == i {
+			return true
+		}
+	}
+	return false
+}
+
 func (a *aggregateFuncHolder) setFilter(filterRenderIdx int) {
 	a.filterRenderIdx = filterRenderIdx
 }
@@ -776,7 +1060,20 @@ func (a *aggregateFuncHolder) isDistinct() bool {
 
 func aggregateFuncsEqual(a, b *aggregateFuncHolder) bool {
 	return a.funcName == b.funcName && a.resultType == b.resultType &&
-		a.argRenderIdx == b.argRenderIdx && a.filterRenderIdx == b.filterRenderIdx
+		a.argRenderIdx == b.argRenderIdx && a.filterRenderIdx == b.filterRenderIdx &&
+		intSlicesEqual(a.otherArgRenderIdxs, b.otherArgRenderIdxs)
+}
+
+func intSlicesEqual(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
 }
 
 func (a *aggregateFuncHolder) close(ctx context.Context) {
@@ -793,7 +1090,7 @@ func (a *aggregateFuncHolder) close(ctx context.Context) {
 // add accumulates one more value for a particular bucket into an aggregation
 // function.
 func (a *aggregateFuncHolder) add(
-	ctx context.Context, evalCtx *tree.EvalContext, bucket []byte, d tree.Datum,
+	ctx context.Context, evalCtx *tree.EvalContext, bucket []byte, d tree.Datum, otherArgs tree.Datums,
 ) error {
 	// NB: the compiler *should* optimize `myMap[string(myBytes)]`. See:
 	// https://github.com/golang/go/commit/f5f5a8b6209f84961687d993b93ea0d397f5d5bf
@@ -803,6 +1100,12 @@ func (a *aggregateFuncHolder) add(
 		if err != nil {
 			return err
 		}
+		if otherArgs != nil {
+			encoded, err = sqlbase.EncodeDatums(encoded, otherArgs)
+			if err != nil {
+				return err
+			}
+		}
 		if _, ok := a.run.seen[string(encoded)]; ok {
 			// skip
 			return nil
@@ -819,5 +1122,5 @@ func (a *aggregateFuncHolder) add(
 		a.run.buckets[string(bucket)] = impl
 	}
 
-	return impl.Add(ctx, d)
+	return impl.Add(ctx, d, otherArgs...)
 }
@@ -0,0 +1,283 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/transform"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/types"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+)
+
+// indexExprColumnName is the base name of the hidden columns storing the
+// values of index expressions.
+const indexExprColumnName = "crdb_idx_expr"
+
+// makeIndexExprColumns replaces the expressions of an index definition, such
+// as lower(email) in CREATE INDEX ON users (lower(email)), by hidden stored
+// computed columns, which are then indexed like any other column. It returns
+// the rewritten index elements and the columns which must be added to the
+// table for them. An expression which is already computed by a hidden column
+// of the table, for another index, reuses that column.
+//
+// Once indexed, the hidden column is used by the index selection to
+// constrain scans on filters over the same expression (see
+// idxconstraint.Instance).
+func makeIndexExprColumns(
+	ctx context.Context,
+	desc *sqlbase.TableDescriptor,
+	tn *tree.TableName,
+	elems tree.IndexElemList,
+	semaCtx *tree.SemaContext,
+	evalCtx *tree.EvalContext,
+) (tree.IndexElemList, []sqlbase.ColumnDescriptor, error) {
+	hasExprs := false
+	for _, elem := range elems {
+		if elem.Expr != nil {
+			hasExprs = true
+			break
+		}
+	}
+	if !hasExprs {
+		return elems, nil, nil
+	}
+
+	sources := sqlbase.MultiSourceInfo{sqlbase.NewSourceInfoForSingleTable(
+		*tn, sqlbase.ResultColumnsFromColDescs(desc.Columns),
+	)}
+	var newCols []sqlbase.ColumnDescriptor
+	res := make(tree.IndexElemList, len(elems))
+	for i, elem := range elems {
+		res[i] = elem
+		if elem.Expr == nil {
+			continue
+		}
+		res[i].Expr = nil
+
+		// A parenthesized column name is a plain column reference.
+		if vBase, ok := elem.Expr.(tree.VarName); ok {
+			v, err := vBase.NormalizeVarName()
+			if err != nil {
+				return nil, nil, err
+			}
+			if c, ok := v.(*tree.ColumnItem); ok {
+				res[i].Column = c.ColumnName
+				continue
+			}
+		}
+
+		var tCtx transform.ExprTransformContext
+		if err := tCtx.AssertNoAggregationOrWindowing(
+			elem.Expr, "index expressions", semaCtx.SearchPath,
+		); err != nil {
+			return nil, nil, err
+		}
+		expr, err := dequalifyColumnRefs(ctx, sources, elem.Expr)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := iterColDescriptorsInExpr(*desc, expr, func(c sqlbase.ColumnDescriptor) error {
+			if c.IsComputed() {
+				return pgerror.NewErrorf(pgerror.CodeInvalidTableDefinitionError,
+					"index expressions cannot reference computed column %q", c.Name)
+			}
+			return nil
+		}); err != nil {
+			return nil, nil, err
+		}
+		replacedExpr, _, err := replaceVars(*desc, expr)
+		if err != nil {
+			return nil, nil, err
+		}
+		typedExpr, err := sqlbase.SanitizeVarFreeExpr(
+			replacedExpr, types.Any, "index", semaCtx, evalCtx,
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+		if fns := tree.ImpureFunctions(typedExpr); len(fns) != 0 {
+			var errMsg bytes.Buffer
+			fmt.Fprintf(&errMsg, "index expression %s contains impure functions: ", tree.ErrString(elem.Expr))
+			for j, fn := range fns {
+				if j != 0 {
+					errMsg.WriteString(", ")
+				}
+				errMsg.WriteString(fn.String())
+			}
+			return nil, nil, pgerror.NewError(pgerror.CodeInvalidTableDefinitionError, errMsg.String())
+		}
+		colType, err := sqlbase.DatumTypeToColumnType(typedExpr.ResolvedType())
+		if err != nil {
+			return nil, nil, err
+		}
+		if sqlbase.MustBeValueEncoded(colType.SemanticType) {
+			return nil, nil, fmt.Errorf("index expression %s is of type %s and thus is not indexable",
+				tree.ErrString(elem.Expr), colType.SemanticType)
+		}
+
+		serialized := tree.Serialize(expr)
+		if name, ok := findIndexExprColumn(desc.Columns, serialized); ok {
+			res[i].Column = tree.Name(name)
+			continue
+		}
+		if name, ok := findIndexExprColumn(newCols, serialized); ok {
+			res[i].Column = tree.Name(name)
+			continue
+		}
+		name := makeIndexExprColumnName(desc, newCols)
+		newCols = append(newCols, sqlbase.ColumnDescriptor{
+			Name:        name,
+			Type:        colType,
+			Nullable:    true,
+			Hidden:      true,
+			ComputeExpr: &serialized,
+		})
+		res[i].Column = tree.Name(name)
+	}
+	return res, newCols, nil
+}
+
+// isIndexExprColumn returns whether col is a hidden column storing the values
+// of an index expression. The new columns added by column type changes are
+// also hidden computed columns, but they are not named after
+// indexExprColumnName.
+func isIndexExprColumn(col *sqlbase.ColumnDescriptor) bool {
+	return col.Hidden && col.IsComputed() && strings.HasPrefix(col.Name, indexExprColumnName)
+}
+
+// indexColumnExprs returns the serialized expressions stored by the index
+// expression columns of the index idx, keyed by column ID.
+func indexColumnExprs(
+	desc *sqlbase.TableDescriptor, idx *sqlbase.IndexDescriptor,
+) map[sqlbase.ColumnID]string {
+	var res map[sqlbase.ColumnID]string
+	for _, id := range idx.ColumnIDs {
+		col, err := desc.FindColumnByID(id)
+		if err != nil || !isIndexExprColumn(col) {
+			continue
+		}
+		if res == nil {
+			res = make(map[sqlbase.ColumnID]string)
+		}
+		res[id] = *col.ComputeExpr
+	}
+	return res
+}
+
+// findIndexExprColumn returns the name of the column of cols storing the
+// values of the serialized index expression expr, if any.
+func findIndexExprColumn(cols []sqlbase.ColumnDescriptor, expr string) (string, bool) {
+	for i := range cols {
+		if isIndexExprColumn(&cols[i]) && *cols[i].ComputeExpr == expr {
+			return cols[i].Name, true
+		}
+	}
+	return "", false
+}
+
+// makeIndexExprColumnName returns a name for a new index expression column,
+// which differs from the names of the columns of desc and of newCols.
+func makeIndexExprColumnName(desc *sqlbase.TableDescriptor, newCols []sqlbase.ColumnDescriptor) string {
+	exists := func(name string) bool {
+		if _, _, err := desc.FindColumnByName(tree.Name(name)); err == nil {
+			return true
+		}
+		for i := range newCols {
+			if newCols[i].Name == name {
+				return true
+			}
+		}
+		return false
+	}
+	name := indexExprColumnName
+	for i := 1; exists(name); i++ {
+		name = fmt.Sprintf("%s_%d", indexExprColumnName, i)
+	}
+	return name
+}
+
+// indexExprColumnsUsing returns the IDs of the index expression columns of
+// desc whose expression references the column colID.
+func indexExprColumnsUsing(
+	desc *sqlbase.TableDescriptor, colID sqlbase.ColumnID,
+) (map[sqlbase.ColumnID]struct{}, error) {
+	var res map[sqlbase.ColumnID]struct{}
+	for i := range desc.Columns {
+		col := &desc.Columns[i]
+		if !isIndexExprColumn(col) {
+			continue
+		}
+		expr, err := parser.ParseExpr(*col.ComputeExpr)
+		if err != nil {
+			return nil, err
+		}
+		if err := iterColDescriptorsInExpr(*desc, expr, func(c sqlbase.ColumnDescriptor) error {
+			if c.ID == colID {
+				if res == nil {
+					res = make(map[sqlbase.ColumnID]struct{})
+				}
+				res[col.ID] = struct{}{}
+			}
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// dropUnusedIndexExprColumns queues the removal of the index expression
+// columns among colIDs which are no longer indexed, e.g. after the index
+// they were created for has been dropped.
+func dropUnusedIndexExprColumns(desc *sqlbase.TableDescriptor, colIDs []sqlbase.ColumnID) {
+	for _, id := range colIDs {
+		idx := -1
+		for i := range desc.Columns {
+			if desc.Columns[i].ID == id {
+				idx = i
+				break
+			}
+		}
+		if idx == -1 || !isIndexExprColumn(&desc.Columns[idx]) {
+			continue
+		}
+		used := false
+		for _, index := range desc.AllNonDropIndexes() {
+			if index.ContainsColumnID(id) {
+				used = true
+				break
+			}
+		}
+		for _, ref := range desc.DependedOnBy {
+			for _, colID := range ref.ColumnIDs {
+				if colID == id {
+					used = true
+				}
+			}
+		}
+		if used {
+			continue
+		}
+		desc.AddColumnMutation(desc.Columns[idx], sqlbase.DescriptorMutation_DROP)
+		desc.Columns = append(desc.Columns[:idx], desc.Columns[idx+1:]...)
+	}
+}
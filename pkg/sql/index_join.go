@@ -113,6 +113,11 @@ func (p *planner) makeIndexJoin(
 	}
 	table.initOrdering(0 /* exactPrefix */, p.EvalContext())
 	table.disableBatchLimit()
+	// Rows are locked in the primary index: the index scan only needs
+	// to observe the locks held by other transactions.
+	table.lockingStrength = origScan.lockingStrength
+	table.lockingWaitPolicy = origScan.lockingWaitPolicy
+	indexScan.lockingStrength = tree.ForNone
 
 	colIDtoRowIndex := map[sqlbase.ColumnID]int{}
 
@@ -471,10 +471,6 @@ var (
 	matchOptionPartial = tree.NewDString("PARTIAL")
 	matchOptionNone    = tree.NewDString("NONE")
 
-	// Avoid unused warning for constants.
-	_ = matchOptionPartial
-	_ = matchOptionNone
-
 	refConstraintRuleNoAction   = tree.NewDString("NO ACTION")
 	refConstraintRuleRestrict   = tree.NewDString("RESTRICT")
 	refConstraintRuleSetNull    = tree.NewDString("SET NULL")
@@ -498,6 +494,18 @@ func dStringForFKAction(action sqlbase.ForeignKeyReference_Action) tree.Datum {
 	panic(errors.Errorf("unexpected ForeignKeyReference_Action: %v", action))
 }
 
+func dStringForFKMatch(match sqlbase.ForeignKeyReference_Match) tree.Datum {
+	switch match {
+	case sqlbase.ForeignKeyReference_SIMPLE:
+		return matchOptionNone
+	case sqlbase.ForeignKeyReference_FULL:
+		return matchOptionFull
+	case sqlbase.ForeignKeyReference_PARTIAL:
+		return matchOptionPartial
+	}
+	panic(errors.Errorf("unexpected ForeignKeyReference_Match: %v", match))
+}
+
 // Postgres: https://www.postgresql.org/docs/9.6/static/infoschema-referential-constraints.html
 // MySQL:    https://dev.mysql.com/doc/refman/5.7/en/referential-constraints-table.html
 var informationSchemaReferentialConstraintsTable = virtualSchemaTable{
@@ -547,7 +555,7 @@ CREATE TABLE information_schema.referential_constraints (
 					dbNameStr,                       // unique_constraint_catalog
 					scNameStr,                       // unique_constraint_schema
 					tree.NewDString(refIndex.Name),  // unique_constraint_name
-					matchOptionFull,                 // match_option
+					dStringForFKMatch(fk.Match),     // match_option
 					dStringForFKAction(fk.OnUpdate), // update_rule
 					dStringForFKAction(fk.OnDelete), // delete_rule
 					tbNameStr,                       // table_name
@@ -1195,6 +1203,9 @@ func forEachTableDescWithTableLookupInternal(
 		case virtualMany:
 			for _, dbID := range lCtx.dbIDs {
 				dbDesc := lCtx.dbDescs[dbID]
+				if p.isOtherSessionTemporarySchema(dbDesc.Name) {
+					continue
+				}
 				if err := iterate(dbDesc); err != nil {
 					return err
 				}
@@ -1206,7 +1217,8 @@ func forEachTableDescWithTableLookupInternal(
 	for _, tbID := range lCtx.tbIDs {
 		table := lCtx.tbDescs[tbID]
 		dbDesc, parentExists := lCtx.dbDescs[table.GetParentID()]
-		if table.Dropped() || !userCanSeeTable(ctx, p, table, allowAdding) || !parentExists {
+		if table.Dropped() || !userCanSeeTable(ctx, p, table, allowAdding) || !parentExists ||
+			p.isOtherSessionTemporarySchema(dbDesc.Name) {
 			continue
 		}
 		if err := fn(dbDesc, tree.PublicSchema, table, lCtx); err != nil {
@@ -1320,7 +1332,7 @@ func forEachRoleMembership(
 }
 
 func userCanSeeDatabase(ctx context.Context, p *planner, db *sqlbase.DatabaseDescriptor) bool {
-	return p.CheckAnyPrivilege(ctx, db) == nil
+	return !p.isOtherSessionTemporarySchema(db.Name) && p.CheckAnyPrivilege(ctx, db) == nil
 }
 
 func userCanSeeTable(
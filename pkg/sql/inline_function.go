@@ -0,0 +1,383 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/builtins"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+)
+
+// User-defined SQL functions are never called at execution time.
+// Instead, during planning every call is replaced by the body of the
+// function, with the parameters substituted by the arguments of the
+// call:
+//
+// - when the body has the form "SELECT <expr>", the expression is
+//   inlined directly into the calling expression;
+// - otherwise, the body is inlined as a scalar subquery.
+//
+// In both cases the result is cast to the declared return type of the
+// function. Since the heuristic planner does not support correlated
+// subqueries, the arguments of a function whose body is inlined as a
+// subquery cannot refer to columns of the calling query.
+//
+// Inside the body, parameters can be referenced either by name or by
+// position ($1, $2, ...). A parameter name shadows any column with the
+// same name.
+//
+// Since every reference to a parameter is substituted by the argument,
+// an argument is evaluated as many times as its parameter is used. A
+// call that passes a volatile argument, like random(), to a parameter
+// used more than once would thus observe different values for the same
+// parameter, and is rejected.
+
+// inlineFunctions replaces the calls to user-defined functions in the
+// expressions of the given statement by the bodies of the functions.
+// The statement is not modified in-place.
+func (p *planner) inlineFunctions(ctx context.Context, stmt tree.Statement) (tree.Statement, error) {
+	v := functionInliner{p: p, ctx: ctx}
+	newStmt, changed := tree.WalkStmt(&v, stmt)
+	if v.err != nil {
+		return nil, v.err
+	}
+	if changed {
+		keepRenderNames(stmt, newStmt)
+	}
+	return newStmt, nil
+}
+
+// keepRenderNames names the render targets that were function calls
+// before inlining after the function, as they would have been named
+// if the function had not been inlined.
+func keepRenderNames(orig, inlined tree.Statement) {
+	switch o := orig.(type) {
+	case *tree.Select:
+		keepRenderNames(o.Select, inlined.(*tree.Select).Select)
+	case *tree.ParenSelect:
+		keepRenderNames(o.Select, inlined.(*tree.ParenSelect).Select)
+	case *tree.SelectClause:
+		n := inlined.(*tree.SelectClause)
+		for i := range o.Exprs {
+			f, ok := o.Exprs[i].Expr.(*tree.FuncExpr)
+			if !ok || o.Exprs[i].As != "" || n.Exprs[i].Expr == tree.Expr(f) {
+				continue
+			}
+			if un, ok := f.Func.FunctionReference.(*tree.UnresolvedName); ok {
+				n.Exprs[i].As = tree.UnrestrictedName(un.Parts[0])
+			}
+		}
+	}
+}
+
+// inlineFunctionsInExpr is like inlineFunctions for a single
+// expression.
+func (p *planner) inlineFunctionsInExpr(ctx context.Context, expr tree.Expr) (tree.Expr, error) {
+	v := functionInliner{p: p, ctx: ctx}
+	newExpr, _ := tree.WalkExpr(&v, expr)
+	return newExpr, v.err
+}
+
+// functionInliner is a tree.Visitor that implements inlineFunctions.
+// It is also used to substitute the parameters in the body of a
+// function being inlined.
+type functionInliner struct {
+	p   *planner
+	ctx context.Context
+
+	// fn is the function whose body is being visited, if any. Its
+	// parameters are substituted by args.
+	fn   *sqlbase.FunctionDescriptor
+	args tree.Exprs
+	// uses counts the references to each parameter of fn.
+	uses []int
+
+	// inlining is the set of functions whose body is being inlined. It
+	// is used to reject recursive calls, which cannot be inlined.
+	inlining map[sqlbase.ID]struct{}
+
+	err error
+}
+
+var _ tree.Visitor = &functionInliner{}
+
+func (v *functionInliner) VisitPre(expr tree.Expr) (recurse bool, newExpr tree.Expr) {
+	if v.err != nil {
+		return false, expr
+	}
+
+	switch t := expr.(type) {
+	case *tree.FuncExpr:
+		un, ok := t.Func.FunctionReference.(*tree.UnresolvedName)
+		if !ok {
+			// Already resolved to a built-in function.
+			return true, expr
+		}
+		if _, err := t.Func.Resolve(v.p.SessionData().SearchPath); err == nil {
+			// Built-in functions take precedence over user-defined ones.
+			return true, expr
+		}
+		tn, err := tree.NormalizeTableName(un)
+		if err != nil {
+			// Not a valid name for a user-defined function; let type
+			// checking report the error.
+			return true, expr
+		}
+		var fn *sqlbase.FunctionDescriptor
+		v.p.runWithOptions(resolveFlags{skipCache: true}, func() {
+			_, fn, err = ResolveExistingFunction(v.ctx, v.p, &tn, false /* required */)
+		})
+		if err != nil {
+			v.err = err
+			return false, expr
+		}
+		if fn == nil {
+			// Unknown function; let type checking report the error.
+			return true, expr
+		}
+		inlined, err := v.inlineCall(t, fn)
+		if err != nil {
+			v.err = err
+			return false, expr
+		}
+		return false, inlined
+
+	case *tree.UnresolvedName:
+		if v.fn != nil && t.NumParts == 1 && !t.Star {
+			for i := range v.fn.Params {
+				if v.fn.Params[i].Name == t.Parts[0] {
+					v.uses[i]++
+					return false, v.args[i]
+				}
+			}
+		}
+
+	case *tree.Placeholder:
+		if v.fn != nil {
+			idx, err := strconv.Atoi(t.Name)
+			if err != nil || idx < 1 || idx > len(v.args) {
+				v.err = pgerror.NewErrorf(pgerror.CodeUndefinedParameterError,
+					"there is no parameter $%s", t.Name)
+				return false, expr
+			}
+			v.uses[idx-1]++
+			return false, v.args[idx-1]
+		}
+
+	case *tree.Subquery:
+		// The subqueries of the calling statement are planned, and thus
+		// inlined, separately. The subqueries of a function body however
+		// need their parameters substituted now.
+		if v.fn != nil {
+			sel, changed := tree.WalkStmt(v, t.Select)
+			if v.err != nil {
+				return false, expr
+			}
+			if changed {
+				return false, &tree.Subquery{Select: sel.(tree.SelectStatement), Exists: t.Exists}
+			}
+		}
+		return false, expr
+	}
+
+	return true, expr
+}
+
+func (*functionInliner) VisitPost(expr tree.Expr) tree.Expr { return expr }
+
+// inlineCall returns the expression that replaces the given call to a
+// user-defined function.
+func (v *functionInliner) inlineCall(
+	call *tree.FuncExpr, fn *sqlbase.FunctionDescriptor,
+) (tree.Expr, error) {
+	if call.Type != 0 || call.Filter != nil || call.WindowDef != nil || call.OrderBy != nil {
+		return nil, pgerror.NewErrorf(pgerror.CodeWrongObjectTypeError,
+			"%s() is not an aggregate or window function", fn.Name)
+	}
+	if len(call.Exprs) != len(fn.Params) {
+		return nil, pgerror.NewErrorf(pgerror.CodeUndefinedFunctionError,
+			"function %s() takes %d arguments, found %d", fn.Name, len(fn.Params), len(call.Exprs))
+	}
+	if _, ok := v.inlining[fn.ID]; ok {
+		return nil, pgerror.NewErrorf(pgerror.CodeInvalidRecursionError,
+			"function %s() cannot be inlined because it calls itself recursively", fn.Name)
+	}
+	if err := v.p.CheckPrivilege(v.ctx, fn, privilege.EXECUTE); err != nil {
+		return nil, err
+	}
+	v.p.curPlan.hasUserDefinedFunction = true
+
+	// The arguments may themselves contain calls to user-defined
+	// functions, or refer to the parameters of an enclosing function.
+	args := make(tree.Exprs, len(call.Exprs))
+	for i, arg := range call.Exprs {
+		args[i], _ = tree.WalkExpr(v, arg)
+		if v.err != nil {
+			return nil, v.err
+		}
+	}
+
+	body, err := v.expandBody(fn, args)
+	if err != nil {
+		return nil, err
+	}
+	retType, err := parser.ParseType(fn.ReturnType.SQLString())
+	if err != nil {
+		return nil, err
+	}
+	var inlined tree.Expr
+	if expr, ok := v.p.simpleFunctionBody(body); ok {
+		inlined = &tree.ParenExpr{Expr: expr}
+	} else {
+		inlined = &tree.Subquery{Select: &tree.ParenSelect{Select: body}}
+	}
+	return &tree.CastExpr{Expr: inlined, Type: retType, SyntaxMode: tree.CastShort}, nil
+}
+
+// expandBody parses the body of the given function and substitutes its
+// parameters by the given arguments. The calls to user-defined
+// functions in the body are inlined too.
+func (v *functionInliner) expandBody(
+	fn *sqlbase.FunctionDescriptor, args tree.Exprs,
+) (*tree.Select, error) {
+	stmt, err := parser.ParseOne(fn.Body)
+	if err != nil {
+		return nil, err
+	}
+	sel, ok := stmt.(*tree.Select)
+	if !ok {
+		return nil, pgerror.NewErrorf(pgerror.CodeInvalidFunctionDefinitionError,
+			"function body must be a SELECT statement, found %s", stmt.StatementTag())
+	}
+
+	castArgs := make(tree.Exprs, len(args))
+	for i := range args {
+		typ, err := parser.ParseType(fn.Params[i].Type.SQLString())
+		if err != nil {
+			return nil, err
+		}
+		castArgs[i] = &tree.CastExpr{
+			Expr: &tree.ParenExpr{Expr: args[i]}, Type: typ, SyntaxMode: tree.CastShort,
+		}
+	}
+
+	if v.inlining == nil {
+		v.inlining = make(map[sqlbase.ID]struct{})
+	}
+	v.inlining[fn.ID] = struct{}{}
+	defer delete(v.inlining, fn.ID)
+
+	bodyVisitor := functionInliner{
+		p: v.p, ctx: v.ctx, fn: fn, args: castArgs, uses: make([]int, len(args)), inlining: v.inlining,
+	}
+	newStmt, _ := tree.WalkStmt(&bodyVisitor, sel)
+	if bodyVisitor.err != nil {
+		return nil, bodyVisitor.err
+	}
+	// A volatile argument must be evaluated exactly once, like it would be
+	// if the function were called: it cannot be substituted for a parameter
+	// that is used several times, nor dropped with an unused parameter.
+	for i, uses := range bodyVisitor.uses {
+		if uses == 1 || !v.p.isVolatileExpr(args[i]) {
+			continue
+		}
+		name := fn.Params[i].Name
+		if name == "" {
+			name = "$" + strconv.Itoa(i+1)
+		}
+		usage := "used more than once"
+		if uses == 0 {
+			usage = "not used"
+		}
+		return nil, pgerror.NewErrorf(pgerror.CodeFeatureNotSupportedError,
+			"function %s() cannot be inlined: parameter %s is %s "+
+				"and its argument %s is volatile", fn.Name, name, usage, args[i])
+	}
+	return newStmt.(*tree.Select), nil
+}
+
+// isVolatileExpr returns whether the given expression calls volatile
+// functions, like random(), which return different results when the
+// expression is evaluated several times. Aggregate and window functions
+// are not volatile in that sense.
+func (p *planner) isVolatileExpr(expr tree.Expr) bool {
+	volatile := false
+	_, _ = tree.SimpleVisit(expr, func(expr tree.Expr) (error, bool, tree.Expr) {
+		if t, ok := expr.(*tree.FuncExpr); ok {
+			fd, err := t.Func.Resolve(p.SessionData().SearchPath)
+			if err != nil {
+				return nil, true, expr
+			}
+			for _, o := range fd.Definition {
+				if b, ok := o.(*tree.Builtin); ok && b.Impure &&
+					b.Class != tree.AggregateClass && b.Class != tree.WindowClass {
+					volatile = true
+				}
+			}
+		}
+		return nil, !volatile, expr
+	})
+	return volatile
+}
+
+// simpleFunctionBody returns the expression computed by a function body
+// of the form "SELECT <expr>", which can be inlined without a subquery.
+// Bodies using aggregate, window or set-returning functions are not
+// simple, since these would apply to the calling query once inlined.
+func (p *planner) simpleFunctionBody(sel *tree.Select) (tree.Expr, bool) {
+	if sel.With != nil || sel.OrderBy != nil || sel.Limit != nil {
+		return nil, false
+	}
+	clause, ok := sel.Select.(*tree.SelectClause)
+	if !ok || clause.TableSelect || clause.Distinct || clause.DistinctOn != nil ||
+		len(clause.Exprs) != 1 || (clause.From != nil && len(clause.From.Tables) > 0) ||
+		clause.Where != nil || clause.GroupBy != nil || clause.Having != nil ||
+		clause.Window != nil {
+		return nil, false
+	}
+
+	expr := clause.Exprs[0].Expr
+	simple := true
+	_, _ = tree.SimpleVisit(expr, func(expr tree.Expr) (error, bool, tree.Expr) {
+		switch t := expr.(type) {
+		case *tree.Subquery:
+			return nil, false, expr
+		case *tree.FuncExpr:
+			if t.WindowDef != nil {
+				simple = false
+				return nil, false, expr
+			}
+			fd, err := t.Func.Resolve(p.SessionData().SearchPath)
+			if err != nil {
+				// Let type checking report the error.
+				return nil, true, expr
+			}
+			_, isAggregate := builtins.Aggregates[fd.Name]
+			_, isGenerator := builtins.Generators[fd.Name]
+			if isAggregate || isGenerator {
+				simple = false
+				return nil, false, expr
+			}
+		}
+		return nil, true, expr
+	})
+	return expr, simple
+}
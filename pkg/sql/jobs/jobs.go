@@ -61,6 +61,8 @@ var _ Details = BackupDetails{}
 var _ Details = RestoreDetails{}
 var _ Details = SchemaChangeDetails{}
 var _ Details = ChangefeedDetails{}
+var _ Details = RefreshMaterializedViewDetails{}
+var _ Details = CreateStatsDetails{}
 
 // Record stores the job fields that are not automatically managed by Job.
 type Record struct {
@@ -109,6 +111,13 @@ func (e *InvalidStatusError) Error() string {
 	return fmt.Sprintf("cannot %s %s job (id %d)", e.op, e.status, e.id)
 }
 
+// IsCanceledError returns whether err is the error returned when updating a
+// job that has been canceled.
+func IsCanceledError(err error) bool {
+	e, ok := errors.Cause(err).(*InvalidStatusError)
+	return ok && e.status == StatusCanceled
+}
+
 // ID returns the ID of the job that this Job is currently tracking. This will
 // be nil if Created has not yet been called.
 func (j *Job) ID() *int64 {
@@ -479,6 +488,10 @@ func detailsType(d isPayload_Details) Type {
 		return TypeImport
 	case *Payload_Changefeed:
 		return TypeChangefeed
+	case *Payload_RefreshMaterializedView:
+		return TypeRefreshMaterializedView
+	case *Payload_CreateStats:
+		return TypeCreateStats
 	default:
 		panic(fmt.Sprintf("Payload.Type called on a payload with an unknown details type: %T", d))
 	}
@@ -503,6 +516,10 @@ func WrapPayloadDetails(details Details) interface {
 		return &Payload_Import{Import: &d}
 	case ChangefeedDetails:
 		return &Payload_Changefeed{Changefeed: &d}
+	case RefreshMaterializedViewDetails:
+		return &Payload_RefreshMaterializedView{RefreshMaterializedView: &d}
+	case CreateStatsDetails:
+		return &Payload_CreateStats{CreateStats: &d}
 	default:
 		panic(fmt.Sprintf("jobs.WrapPayloadDetails: unknown details type %T", d))
 	}
@@ -526,6 +543,10 @@ func (p *Payload) UnwrapDetails() (Details, error) {
 		return *d.Import, nil
 	case *Payload_Changefeed:
 		return *d.Changefeed, nil
+	case *Payload_RefreshMaterializedView:
+		return *d.RefreshMaterializedView, nil
+	case *Payload_CreateStats:
+		return *d.CreateStats, nil
 	default:
 		return nil, errors.Errorf("jobs.Payload: unsupported details type %T", d)
 	}
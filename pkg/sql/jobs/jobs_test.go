@@ -523,7 +523,7 @@ func TestJobLifecycle(t *testing.T) {
 
 	ctx := context.TODO()
 
-	s, sqlDB, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	s, sqlDB, kvDB := serverutils.StartServer(t, base.TestServerArgs{})
 	defer s.Stopper().Stop(ctx)
 
 	registry := s.JobRegistry().(*jobs.Registry)
@@ -1038,6 +1038,65 @@ func TestJobLifecycle(t *testing.T) {
 			t.Fatalf("unexpected %v", err)
 		}
 	})
+
+	if _, err := sqlDB.Exec(`CREATE DATABASE d; CREATE TABLE d.t (a INT)`); err != nil {
+		t.Fatal(err)
+	}
+
+	// createSchemaChangeJob creates a schema change job for a mutation of
+	// d.t in the given direction.
+	createSchemaChangeJob := func(
+		t *testing.T, direction sqlbase.DescriptorMutation_Direction,
+	) (*jobs.Job, expectation) {
+		desc := sqlbase.GetTableDescriptor(kvDB, "d", "t")
+		job, exp := createJob(jobs.Record{
+			DescriptorIDs: sqlbase.IDs{desc.ID},
+			Details:       jobs.SchemaChangeDetails{},
+		})
+		if err := job.Started(ctx); err != nil {
+			t.Fatal(err)
+		}
+		desc.Mutations = []sqlbase.DescriptorMutation{{
+			Descriptor_: &sqlbase.DescriptorMutation_Column{
+				Column: &sqlbase.ColumnDescriptor{Name: "b", ID: desc.NextColumnID},
+			},
+			State:      sqlbase.DescriptorMutation_DELETE_ONLY,
+			Direction:  direction,
+			MutationID: desc.NextMutationID,
+		}}
+		desc.MutationJobs = []sqlbase.TableDescriptor_MutationJob{{
+			MutationID: desc.NextMutationID, JobID: *job.ID(),
+		}}
+		if err := kvDB.Put(
+			ctx, sqlbase.MakeDescMetadataKey(desc.ID), sqlbase.WrapDescriptor(desc),
+		); err != nil {
+			t.Fatal(err)
+		}
+		return job, exp
+	}
+
+	t.Run("schema change jobs that add can be canceled", func(t *testing.T) {
+		job, exp := createSchemaChangeJob(t, sqlbase.DescriptorMutation_ADD)
+		if err := registry.Cancel(ctx, nil, *job.ID()); err != nil {
+			t.Fatal(err)
+		}
+		if err := exp.verify(job.ID(), jobs.StatusCanceled); err != nil {
+			t.Fatal(err)
+		}
+		if err := job.Progressed(ctx, jobs.FractionUpdater(0.5)); !jobs.IsCanceledError(err) {
+			t.Fatalf("expected canceled error, got %v", err)
+		}
+	})
+
+	t.Run("cannot cancel schema change jobs that drop", func(t *testing.T) {
+		job, exp := createSchemaChangeJob(t, sqlbase.DescriptorMutation_DROP)
+		if err := registry.Cancel(ctx, nil, *job.ID()); !testutils.IsError(err, "is not controllable") {
+			t.Fatalf("unexpected %v", err)
+		}
+		if err := exp.verify(job.ID(), jobs.StatusRunning); err != nil {
+			t.Fatal(err)
+		}
+	})
 }
 
 func TestRunAndWaitForTerminalState(t *testing.T) {
@@ -27,6 +27,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/builtins"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlutil"
 	"github.com/cockroachdb/cockroach/pkg/storage"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
@@ -321,12 +322,70 @@ func (r *Registry) getJobFn(ctx context.Context, txn *client.Txn, id int64) (*Jo
 // Cancel marks the job with id as canceled using the specified txn (may be nil).
 func (r *Registry) Cancel(ctx context.Context, txn *client.Txn, id int64) error {
 	job, resumer, err := r.getJobFn(ctx, txn, id)
+	if job != nil {
+		if payload := job.Payload(); payload.Type() == TypeSchemaChange {
+			// Schema changes are not run by the registry. Marking the job as
+			// canceled makes the schema changer roll back the schema change,
+			// which is only possible if it only adds columns and indexes.
+			canRollback, err := r.schemaChangeCanRollback(ctx, txn, job)
+			if err != nil {
+				return err
+			}
+			if !canRollback {
+				return errors.Errorf("job %d is not controllable", id)
+			}
+			return job.WithTxn(txn).canceled(ctx, NoopFn)
+		}
+	}
 	if err != nil {
 		return err
 	}
 	return job.WithTxn(txn).canceled(ctx, resumer.OnFailOrCancel)
 }
 
+// schemaChangeCanRollback returns whether the schema change run by the given
+// job can be rolled back, which is the case if all the mutations of the
+// schema change are additions that are not already being rolled back.
+func (r *Registry) schemaChangeCanRollback(
+	ctx context.Context, txn *client.Txn, job *Job,
+) (bool, error) {
+	canRollback := false
+	check := func(ctx context.Context, txn *client.Txn) error {
+		canRollback = false
+		for _, descID := range job.Payload().DescriptorIDs {
+			desc, err := sqlbase.GetTableDescFromID(ctx, txn, descID)
+			if err != nil {
+				return err
+			}
+			for _, mutationJob := range desc.MutationJobs {
+				if mutationJob.JobID != *job.ID() {
+					continue
+				}
+				found := false
+				for _, m := range desc.Mutations {
+					if m.MutationID != mutationJob.MutationID {
+						continue
+					}
+					if m.Direction != sqlbase.DescriptorMutation_ADD || m.Rollback {
+						return nil
+					}
+					found = true
+				}
+				canRollback = found
+				return nil
+			}
+		}
+		return nil
+	}
+	var err error
+	if txn != nil {
+		err = check(ctx, txn)
+	} else {
+		err = r.db.Txn(ctx, check)
+	}
+	return canRollback, err
+}
+
 // Pause marks the job with id as paused using the specified txn (may be nil).
 func (r *Registry) Pause(ctx context.Context, txn *client.Txn, id int64) error {
 	job, _, err := r.getJobFn(ctx, txn, id)
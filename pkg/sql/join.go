@@ -146,17 +146,9 @@ func (p *planner) makeJoinNode(
 	return n
 }
 
-// makeJoin constructs a planDataSource for a JOIN.
-// The source might be a joinNode, or it could be a renderNode on top of a
-// joinNode (in the case of outer natural joins).
-func (p *planner) makeJoin(
-	ctx context.Context,
-	joinType sqlbase.JoinType,
-	left planDataSource,
-	right planDataSource,
-	cond tree.JoinCond,
-) (planDataSource, error) {
-	// Check that the same table name is not used on both sides.
+// checkJoinSourceNames checks that the same table name is not used on both
+// sides of a join.
+func checkJoinSourceNames(left, right planDataSource) error {
 	for _, alias := range right.info.SourceAliases {
 		if _, ok := left.info.SourceAliases.SrcIdx(alias.Name); ok {
 			t := alias.Name.Table()
@@ -167,18 +159,46 @@ func (p *planner) makeJoin(
 				// ambiguity later.
 				continue
 			}
-			return planDataSource{}, fmt.Errorf(
+			return fmt.Errorf(
 				"cannot join columns from the same source name %q (missing AS clause)", t)
 		}
 	}
+	return nil
+}
+
+// makeJoin constructs a planDataSource for a JOIN.
+// The source might be a joinNode, or it could be a renderNode on top of a
+// joinNode (in the case of outer natural joins).
+func (p *planner) makeJoin(
+	ctx context.Context,
+	joinType sqlbase.JoinType,
+	left planDataSource,
+	right planDataSource,
+	cond tree.JoinCond,
+) (planDataSource, error) {
+	if err := checkJoinSourceNames(left, right); err != nil {
+		return planDataSource{}, err
+	}
 
 	pred, usingColumns, err := p.makeJoinPredicate(ctx, left.info, right.info, joinType, cond)
 	if err != nil {
 		return planDataSource{}, err
 	}
 	n := p.makeJoinNode(left, right, pred)
-	joinDataSource := planDataSource{info: pred.info, plan: n}
+	return p.mergeJoinColumns(planDataSource{info: pred.info, plan: n}, left, right, pred, usingColumns)
+}
 
+// mergeJoinColumns completes the planDataSource for a join with the given
+// predicate by merging any USING or NATURAL JOIN columns into one column. The
+// joinDataSource is the source that produces the left and right columns
+// side-by-side.
+func (p *planner) mergeJoinColumns(
+	joinDataSource planDataSource,
+	left planDataSource,
+	right planDataSource,
+	pred *joinPredicate,
+	usingColumns []usingColumn,
+) (planDataSource, error) {
 	if len(usingColumns) == 0 {
 		// No merged columns, we are done.
 		return joinDataSource, nil
@@ -265,12 +285,12 @@ func (p *planner) makeJoin(
 		leftHidden.Add(leftCol)
 		rightHidden.Add(rightCol)
 		var expr tree.TypedExpr
-		if n.joinType == sqlbase.InnerJoin || n.joinType == sqlbase.LeftOuterJoin {
+		if pred.joinType == sqlbase.InnerJoin || pred.joinType == sqlbase.LeftOuterJoin {
 			// The merged column is the same with the corresponding column from the
 			// left side.
 			expr = r.ivarHelper.IndexedVar(leftCol)
 			remapped[leftCol] = i
-		} else if n.joinType == sqlbase.RightOuterJoin &&
+		} else if pred.joinType == sqlbase.RightOuterJoin &&
 			!sqlbase.DatumTypeHasCompositeKeyEncoding(left.info.SourceColumns[leftCol].Typ) {
 			// The merged column is the same with the corresponding column from the
 			// right side.
@@ -349,12 +369,12 @@ func (p *planner) makeJoin(
 
 	// Remove any anonymous aliases that refer to hidden equality columns (i.e.
 	// those that weren't equivalent to the merged column).
-	for i, col := range n.pred.leftEqualityIndices {
+	for i, col := range pred.leftEqualityIndices {
 		if target := remapped[col]; target != i {
 			anonymousAlias.ColumnSet.Remove(target)
 		}
 	}
-	for i, col := range n.pred.rightEqualityIndices {
+	for i, col := range pred.rightEqualityIndices {
 		if target := remapped[numLeft+col]; target != i {
 			anonymousAlias.ColumnSet.Remove(remapped[numLeft+col])
 		}
@@ -0,0 +1,142 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+)
+
+// checkLockingClause verifies that a locking clause can be applied to
+// the given SELECT clause. Row-level locks can only be acquired when
+// every result row corresponds to a single row of each table in the
+// FROM clause.
+func checkLockingClause(parsed *tree.SelectClause, locking tree.LockingClause) error {
+	switch {
+	case parsed.Distinct:
+		return newLockingNotAllowedError(locking, "DISTINCT clause")
+	case len(parsed.GroupBy) > 0:
+		return newLockingNotAllowedError(locking, "GROUP BY clause")
+	case parsed.Having != nil:
+		return newLockingNotAllowedError(locking, "HAVING clause")
+	case parsed.From.AsOf.Expr != nil:
+		return newLockingNotAllowedError(locking, "AS OF SYSTEM TIME")
+	}
+	return nil
+}
+
+// newLockingNotAllowedError creates an error for a locking clause used
+// together with a construct that is incompatible with it.
+func newLockingNotAllowedError(locking tree.LockingClause, construct string) error {
+	return pgerror.NewErrorf(pgerror.CodeFeatureNotSupportedError,
+		"%s is not allowed with %s", locking[0].Strength, construct)
+}
+
+// applyLocking configures the scans of the tables in the FROM clause of
+// a SELECT clause, represented by src, to lock the rows they read as
+// requested by the locking clause.
+//
+// An item without targets applies to all the tables in the FROM clause,
+// including the ones in subqueries and views; data sources that are not
+// tables, like set-returning functions, are ignored. An item with
+// targets applies to the named data sources only, which must contain
+// tables.
+func (p *planner) applyLocking(
+	ctx context.Context, locking tree.LockingClause, src planDataSource,
+) error {
+	for _, item := range locking {
+		if len(item.Targets) == 0 {
+			if _, err := p.lockPlan(ctx, src.plan, item); err != nil {
+				return err
+			}
+			continue
+		}
+		for _, target := range item.Targets {
+			ds, ok := findLockingTarget(src, target)
+			if !ok {
+				return pgerror.NewErrorf(pgerror.CodeUndefinedTableError,
+					"relation %q in %s clause not found in FROM clause",
+					tree.ErrString(&target), item.Strength)
+			}
+			locked, err := p.lockPlan(ctx, ds.plan, item)
+			if err != nil {
+				return err
+			}
+			if !locked {
+				return pgerror.NewErrorf(pgerror.CodeFeatureNotSupportedError,
+					"%s cannot be applied to %q", item.Strength, tree.ErrString(&target))
+			}
+		}
+	}
+	return nil
+}
+
+// findLockingTarget finds the data source with the given name in the
+// FROM clause represented by src.
+func findLockingTarget(src planDataSource, name tree.Name) (planDataSource, bool) {
+	if j, ok := src.plan.(*joinNode); ok {
+		if ds, ok := findLockingTarget(j.left, name); ok {
+			return ds, true
+		}
+		if ds, ok := findLockingTarget(j.right, name); ok {
+			return ds, true
+		}
+	}
+	for _, alias := range src.info.SourceAliases {
+		if alias.Name.TableName == name {
+			return src, true
+		}
+	}
+	return planDataSource{}, false
+}
+
+// lockPlan applies a locking item to the table scans of the given data
+// source plan. It returns whether any table was found.
+func (p *planner) lockPlan(ctx context.Context, plan planNode, item *tree.LockingItem) (bool, error) {
+	switch n := plan.(type) {
+	case *scanNode:
+		// Postgres requires the UPDATE privilege for all the lock strengths.
+		if err := p.CheckPrivilege(ctx, n.desc, privilege.UPDATE); err != nil {
+			return false, err
+		}
+		n.lockingStrength = n.lockingStrength.Max(item.Strength)
+		n.lockingWaitPolicy = n.lockingWaitPolicy.Max(item.WaitPolicy)
+		return true, nil
+
+	case *joinNode:
+		left, err := p.lockPlan(ctx, n.left.plan, item)
+		if err != nil {
+			return false, err
+		}
+		right, err := p.lockPlan(ctx, n.right.plan, item)
+		return left || right, err
+
+	case *renderNode:
+		return p.lockPlan(ctx, n.source.plan, item)
+
+	case *filterNode:
+		return p.lockPlan(ctx, n.source.plan, item)
+
+	case *sortNode:
+		return p.lockPlan(ctx, n.plan, item)
+
+	case *limitNode:
+		return p.lockPlan(ctx, n.plan, item)
+	}
+	return false, nil
+}
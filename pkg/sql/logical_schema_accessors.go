@@ -16,6 +16,7 @@ package sql
 
 import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 )
 
@@ -24,19 +25,48 @@ import (
 //
 
 // LogicalSchemaAccessor extends an existing DatabaseLister with the
-// ability to list tables in a virtual schema.
+// ability to list tables in a virtual schema, and with the pg_temp
+// schema that designates the session's temporary schema.
 type LogicalSchemaAccessor struct {
 	SchemaAccessor
 	vt VirtualTabler
+	// tempSchemaName is the name of the session's temporary schema. When
+	// set, the temporary schemas of other sessions are hidden. See
+	// temporary_schema.go.
+	tempSchemaName string
 }
 
 var _ SchemaAccessor = &LogicalSchemaAccessor{}
 
+// isHidden returns true if the database with the given name is the
+// temporary schema of another session.
+func (l *LogicalSchemaAccessor) isHidden(dbName string) bool {
+	return l.tempSchemaName != "" && dbName != l.tempSchemaName && isTemporarySchemaName(dbName)
+}
+
+// GetDatabaseDesc implements the DatabaseAccessor interface.
+func (l *LogicalSchemaAccessor) GetDatabaseDesc(
+	dbName string, flags DatabaseLookupFlags,
+) (*DatabaseDescriptor, error) {
+	if l.isHidden(dbName) {
+		if flags.required {
+			return nil, sqlbase.NewUndefinedDatabaseError(dbName)
+		}
+		return nil, nil
+	}
+
+	// Fallthrough.
+	return l.SchemaAccessor.GetDatabaseDesc(dbName, flags)
+}
+
 // IsValidSchema implements the DatabaseLister interface.
 func (l *LogicalSchemaAccessor) IsValidSchema(dbDesc *DatabaseDescriptor, scName string) bool {
 	if _, ok := l.vt.getVirtualSchemaEntry(scName); ok {
 		return true
 	}
+	if scName == sessiondata.PgTempSchemaName {
+		return l.tempSchemaName != ""
+	}
 
 	// Fallthrough.
 	return l.SchemaAccessor.IsValidSchema(dbDesc, scName)
@@ -56,6 +86,21 @@ func (l *LogicalSchemaAccessor) GetObjectNames(
 		}
 		return names, nil
 	}
+	if scName == sessiondata.PgTempSchemaName {
+		tempDesc, err := l.SchemaAccessor.GetDatabaseDesc(l.tempSchemaName, flags.CommonLookupFlags)
+		if err != nil || tempDesc == nil {
+			return nil, err
+		}
+		tempNames, err := l.SchemaAccessor.GetObjectNames(tempDesc, tree.PublicSchema, flags)
+		if err != nil {
+			return nil, err
+		}
+		for i := range tempNames {
+			tempNames[i].CatalogName = tree.Name(dbDesc.Name)
+			tempNames[i].SchemaName = sessiondata.PgTempSchemaName
+		}
+		return tempNames, nil
+	}
 
 	// Fallthrough.
 	return l.SchemaAccessor.GetObjectNames(dbDesc, scName, flags)
@@ -74,6 +119,27 @@ func (l *LogicalSchemaAccessor) GetObjectDesc(
 		}
 		return nil, nil, nil
 	}
+	if name.Schema() == sessiondata.PgTempSchemaName && l.tempSchemaName != "" {
+		// Temporary objects live in the public schema of the session's
+		// temporary schema, regardless of the current database.
+		tempName := tree.MakeTableName(tree.Name(l.tempSchemaName), tree.Name(name.Table()))
+		lookupFlags := flags
+		lookupFlags.required = false
+		desc, dbDesc, err := l.SchemaAccessor.GetObjectDesc(&tempName, lookupFlags)
+		if err != nil {
+			return nil, nil, err
+		}
+		if desc == nil && flags.required {
+			return nil, nil, sqlbase.NewUndefinedRelationError(name)
+		}
+		return desc, dbDesc, nil
+	}
+	if l.isHidden(name.Catalog()) {
+		if flags.required {
+			return nil, nil, sqlbase.NewUndefinedRelationError(name)
+		}
+		return nil, nil, nil
+	}
 
 	// Fallthrough.
 	return l.SchemaAccessor.GetObjectDesc(name, flags)
@@ -18,6 +18,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/types"
@@ -53,6 +54,14 @@ type Column interface {
 	// IsHidden returns true if the column is hidden (e.g., there is always a
 	// hidden column called rowid if there is no primary key on the table).
 	IsHidden() bool
+
+	// IsComputed returns true if the column is a computed column, whose values
+	// are computed by the expression returned by ComputedExprStr.
+	IsComputed() bool
+
+	// ComputedExprStr returns the serialized expression computing the values
+	// of the column, or the empty string if the column is not computed.
+	ComputedExprStr() string
 }
 
 // IndexColumn describes a single column that is part of an index definition.
@@ -120,6 +129,59 @@ type Table interface {
 	// table's primary key. If a primary key was not explicitly specified, then
 	// the system implicitly creates one based on a hidden rowid column.
 	Index(i int) Index
+
+	// StatisticCount returns the number of statistics available for the table.
+	StatisticCount() int
+
+	// Statistic returns the ith statistic, where i < StatisticCount. The
+	// statistics are ordered from most recently to least recently created.
+	Statistic(i int) TableStatistic
+}
+
+// TableStatistic is an interface to a table statistic. Each statistic is
+// associated with a set of columns.
+type TableStatistic interface {
+	// CreatedAt indicates when the statistic was generated.
+	CreatedAt() time.Time
+
+	// ColumnCount is the number of columns the statistic pertains to.
+	ColumnCount() int
+
+	// ColumnOrdinal returns the column ordinal (see Table.Column) of the ith
+	// column in this statistic, with 0 <= i < ColumnCount.
+	ColumnOrdinal(i int) int
+
+	// RowCount returns the estimated number of rows in the table.
+	RowCount() uint64
+
+	// DistinctCount returns the estimated number of distinct values on the
+	// columns of the statistic. If there are multiple columns, each "value" is
+	// a tuple with the values on each column.
+	DistinctCount() uint64
+
+	// NullCount returns the estimated number of rows which have a NULL value
+	// on any column in the statistic.
+	NullCount() uint64
+
+	// Histogram returns the buckets of the histogram on the statistic's column,
+	// ordered by upper bound, or nil if the statistic has no histogram.
+	// Histograms are only collected for single column statistics.
+	Histogram() []HistogramBucket
+}
+
+// HistogramBucket is a bucket of a histogram, which describes the
+// distribution of the values of a column. NULL values are excluded from the
+// histogram.
+type HistogramBucket struct {
+	// NumEq is the estimated number of values equal to UpperBound.
+	NumEq uint64
+
+	// NumRange is the estimated number of values between the upper bound of
+	// the previous bucket and UpperBound (both boundaries are exclusive).
+	NumRange uint64
+
+	// UpperBound is the upper boundary of the bucket.
+	UpperBound tree.Datum
 }
 
 // Catalog is an interface to a database catalog, exposing only the information
@@ -15,6 +15,7 @@
 package execbuilder
 
 import (
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/exec"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
@@ -26,6 +27,15 @@ import (
 type Builder struct {
 	factory exec.Factory
 	ev      memo.ExprView
+
+	// workTables maps the working tables of the recursive CTEs being built to
+	// the buffer references that are passed to exec.RecursiveCTEIterationFn.
+	workTables map[memo.WorkTableID]exec.Node
+
+	// outerVals maps the columns of the left sides of the apply joins being
+	// built to the values that replace the references to them in the right
+	// sides.
+	outerVals map[opt.ColumnID]tree.Datum
 }
 
 // New constructs an instance of the execution node builder using the
@@ -87,6 +87,9 @@ func (b *Builder) buildRelational(ev memo.ExprView) (execPlan, error) {
 	case opt.ValuesOp:
 		ep, err = b.buildValues(ev)
 
+	case opt.GeneratorOp:
+		ep, err = b.buildGenerator(ev)
+
 	case opt.ScanOp:
 		ep, err = b.buildScan(ev)
 
@@ -114,6 +117,12 @@ func (b *Builder) buildRelational(ev memo.ExprView) (execPlan, error) {
 	case opt.AntiJoinOp:
 		ep, err = b.buildJoin(ev, sqlbase.LeftAntiJoin)
 
+	case opt.InnerJoinApplyOp:
+		ep, err = b.buildApplyJoin(ev, sqlbase.InnerJoin)
+
+	case opt.LeftJoinApplyOp:
+		ep, err = b.buildApplyJoin(ev, sqlbase.LeftOuterJoin)
+
 	case opt.GroupByOp:
 		ep, err = b.buildGroupBy(ev)
 
@@ -127,6 +136,12 @@ func (b *Builder) buildRelational(ev memo.ExprView) (execPlan, error) {
 	case opt.SortOp:
 		ep, err = b.buildSort(ev)
 
+	case opt.RecursiveCTEOp:
+		ep, err = b.buildRecursiveCTE(ev)
+
+	case opt.WorkTableOp:
+		ep, err = b.buildWorkTable(ev)
+
 	default:
 		return execPlan{}, errors.Errorf("unsupported relational op %s", ev.Operator())
 	}
@@ -177,6 +192,30 @@ func (b *Builder) buildValues(ev memo.ExprView) (execPlan, error) {
 	return ep, nil
 }
 
+func (b *Builder) buildGenerator(ev memo.ExprView) (execPlan, error) {
+	md := ev.Metadata()
+	cols := ev.Private().(opt.ColList)
+
+	scalarCtx := buildScalarCtx{}
+	call := b.buildScalar(&scalarCtx, ev.Child(0))
+
+	resultCols := make(sqlbase.ResultColumns, len(cols))
+	for i, col := range cols {
+		resultCols[i].Name = md.ColumnLabel(col)
+		resultCols[i].Typ = md.ColumnType(col)
+	}
+	node, err := b.factory.ConstructGenerator(call, resultCols)
+	if err != nil {
+		return execPlan{}, err
+	}
+	ep := execPlan{root: node}
+	for i, col := range cols {
+		ep.outputCols.Set(int(col), i)
+	}
+
+	return ep, nil
+}
+
 func (b *Builder) buildScan(ev memo.ExprView) (execPlan, error) {
 	def := ev.Private().(*memo.ScanOpDef)
 	md := ev.Metadata()
@@ -283,6 +322,67 @@ func (b *Builder) buildJoin(ev memo.ExprView, joinType sqlbase.JoinType) (execPl
 	return ep, nil
 }
 
+func (b *Builder) buildApplyJoin(ev memo.ExprView, joinType sqlbase.JoinType) (execPlan, error) {
+	left, err := b.buildRelational(ev.Child(0))
+	if err != nil {
+		return execPlan{}, err
+	}
+
+	// The right input is built anew for each row of the left input, with the
+	// references to the left columns replaced by the values of that row. It is
+	// first built with NULL values, to determine its columns. The values of
+	// the enclosing apply joins are captured now, since the right input is
+	// built again during execution.
+	right := ev.Child(1)
+	outerVals := b.outerVals
+	buildRight := func(leftRow tree.Datums) (execPlan, error) {
+		defer func(prev map[opt.ColumnID]tree.Datum) { b.outerVals = prev }(b.outerVals)
+		b.outerVals = make(map[opt.ColumnID]tree.Datum, len(outerVals)+left.outputCols.Len())
+		for col, d := range outerVals {
+			b.outerVals[col] = d
+		}
+		left.outputCols.ForEach(func(col, ord int) {
+			if leftRow == nil {
+				b.outerVals[opt.ColumnID(col)] = tree.DNull
+			} else {
+				b.outerVals[opt.ColumnID(col)] = leftRow[ord]
+			}
+		})
+		return b.buildRelational(right)
+	}
+	rightTemplate, err := buildRight(nil /* leftRow */)
+	if err != nil {
+		return execPlan{}, err
+	}
+	rightCols := make(opt.ColList, rightTemplate.outputCols.Len())
+	rightTemplate.outputCols.ForEach(func(col, ord int) {
+		rightCols[ord] = opt.ColumnID(col)
+	})
+	fn := func(leftRow tree.Datums) (exec.Node, error) {
+		plan, err := buildRight(leftRow)
+		if err != nil {
+			return nil, err
+		}
+		return b.ensureColumns(plan, rightCols)
+	}
+
+	var ep execPlan
+	numLeftCols := left.outputCols.Len()
+	ep.outputCols = left.outputCols.Copy()
+	rightTemplate.outputCols.ForEach(func(colIdx, rightIdx int) {
+		ep.outputCols.Set(colIdx, rightIdx+numLeftCols)
+	})
+
+	ctx := ep.makeBuildScalarCtx()
+	onExpr := b.buildScalar(&ctx, ev.Child(2))
+
+	ep.root, err = b.factory.ConstructApplyJoin(joinType, left.root, rightTemplate.root, fn, onExpr)
+	if err != nil {
+		return execPlan{}, err
+	}
+	return ep, nil
+}
+
 func (b *Builder) buildGroupBy(ev memo.ExprView) (execPlan, error) {
 	input, err := b.buildRelational(ev.Child(0))
 	if err != nil {
@@ -452,6 +552,63 @@ func (b *Builder) buildSort(ev memo.ExprView) (execPlan, error) {
 	return execPlan{root: node, outputCols: input.outputCols}, nil
 }
 
+func (b *Builder) buildRecursiveCTE(ev memo.ExprView) (execPlan, error) {
+	def := ev.Private().(*memo.RecursiveCTEDef)
+	initial, err := b.buildRelational(ev.Child(0))
+	if err != nil {
+		return execPlan{}, err
+	}
+	initialNode, err := b.ensureColumns(initial, def.Initial)
+	if err != nil {
+		return execPlan{}, err
+	}
+
+	// The recursive input is built anew for each iteration, since execution
+	// nodes cannot be restarted. Each iteration reads the working table through
+	// the buffer reference it is given.
+	recursive := ev.Child(1)
+	fn := func(bufferRef exec.Node) (exec.Node, error) {
+		if b.workTables == nil {
+			b.workTables = make(map[memo.WorkTableID]exec.Node)
+		}
+		b.workTables[def.WorkTable] = bufferRef
+		defer delete(b.workTables, def.WorkTable)
+
+		plan, err := b.buildRelational(recursive)
+		if err != nil {
+			return nil, err
+		}
+		return b.ensureColumns(plan, def.Recursive)
+	}
+
+	node, err := b.factory.ConstructRecursiveCTE(initialNode, fn, def.Name, def.UnionAll)
+	if err != nil {
+		return execPlan{}, err
+	}
+	ep := execPlan{root: node}
+	for i, col := range def.Out {
+		ep.outputCols.Set(int(col), i)
+	}
+	return ep, nil
+}
+
+func (b *Builder) buildWorkTable(ev memo.ExprView) (execPlan, error) {
+	def := ev.Private().(*memo.WorkTableDef)
+	bufferRef, ok := b.workTables[def.ID]
+	if !ok {
+		return execPlan{}, errors.Errorf("working table of %q referenced outside of its recursive CTE", def.Name)
+	}
+	node, err := b.factory.ConstructScanBuffer(bufferRef, def.Name)
+	if err != nil {
+		return execPlan{}, err
+	}
+	ep := execPlan{root: node}
+	for i, col := range def.Cols {
+		ep.outputCols.Set(int(col), i)
+	}
+	return ep, nil
+}
+
 // needProjection figures out what projection is needed on top of the input plan
 // to produce the given list of columns. If the input plan already produces
 // the columns (in the same order), returns needProj=false.
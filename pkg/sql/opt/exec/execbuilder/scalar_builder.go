@@ -90,6 +90,14 @@ func (b *Builder) buildVariable(ctx *buildScalarCtx, ev memo.ExprView) tree.Type
 	colID := ev.Private().(opt.ColumnID)
 	idx, ok := ctx.ivarMap.Get(int(colID))
 	if !ok {
+		if d, ok := b.outerVals[colID]; ok {
+			// The variable refers to the left side of an enclosing apply join.
+			expr, err := tree.ReType(d, ev.Metadata().ColumnType(colID))
+			if err != nil {
+				panic(err)
+			}
+			return expr
+		}
 		panic(fmt.Sprintf("cannot map variable %d to an indexed var", colID))
 	}
 	return ctx.ivh.IndexedVarWithType(idx, ev.Metadata().ColumnType(colID))
@@ -40,6 +40,10 @@ type Factory interface {
 	// ConstructValues returns a node that outputs the given rows as results.
 	ConstructValues(rows [][]tree.TypedExpr, cols sqlbase.ResultColumns) (Node, error)
 
+	// ConstructGenerator returns a node that outputs the rows produced by the
+	// given set-returning function call.
+	ConstructGenerator(call tree.TypedExpr, cols sqlbase.ResultColumns) (Node, error)
+
 	// ConstructScan returns a node that represents a scan of the given index on
 	// the given table.
 	//   - Only the given set of needed columns are part of the result.
@@ -76,6 +80,17 @@ type Factory interface {
 	// using IndexedVars (first the left columns, then the right columns).
 	ConstructJoin(joinType sqlbase.JoinType, left, right Node, onCond tree.TypedExpr) (Node, error)
 
+	// ConstructApplyJoin returns a node that runs an apply join: for each row
+	// of the left node, fn is invoked to build the plan of the right side for
+	// that row, and the results are joined. The right node is the plan built
+	// with NULLs in place of the values of the left row; it determines the
+	// columns of the right side and is never run. The ON expression can refer
+	// to columns from both sides using IndexedVars (first the left columns,
+	// then the right columns). Only inner and left outer joins are supported.
+	ConstructApplyJoin(
+		joinType sqlbase.JoinType, left, right Node, fn ApplyJoinPlanRightFn, onCond tree.TypedExpr,
+	) (Node, error)
+
 	// ConstructGroupBy returns a node that runs an aggregation. If group columns
 	// are specified, a set of aggregations is performed for each group of values
 	// on those columns (otherwise there is a single group).
@@ -97,8 +112,31 @@ type Factory interface {
 
 	// RenameColumns modifies the column names of a node.
 	RenameColumns(input Node, colNames []string) (Node, error)
+
+	// ConstructRecursiveCTE returns a node that executes a recursive CTE. The
+	// rows of the initial node are returned and form the initial working table;
+	// fn is then invoked repeatedly to build the plan of each iteration, until
+	// an iteration produces no rows. All the nodes must produce the same number
+	// of columns. If unionAll is false, duplicate rows are discarded.
+	ConstructRecursiveCTE(
+		initial Node, fn RecursiveCTEIterationFn, label string, unionAll bool,
+	) (Node, error)
+
+	// ConstructScanBuffer returns a node that produces the rows of the current
+	// working table of a recursive CTE. The bufferRef argument is the node that
+	// was passed to the RecursiveCTEIterationFn.
+	ConstructScanBuffer(bufferRef Node, label string) (Node, error)
 }
 
+// RecursiveCTEIterationFn creates the plan for one iteration of the recursive
+// term of a recursive CTE. The bufferRef argument must be passed to
+// ConstructScanBuffer to build the nodes that read the working table.
+type RecursiveCTEIterationFn func(bufferRef Node) (Node, error)
+
+// ApplyJoinPlanRightFn creates the plan of the right side of an apply join for
+// the given row of the left side.
+type ApplyJoinPlanRightFn func(leftRow tree.Datums) (Node, error)
+
 // ColumnOrdinal is the 0-based ordinal index of a column produced by a Node.
 type ColumnOrdinal int32
 
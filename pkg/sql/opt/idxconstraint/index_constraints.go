@@ -1104,9 +1104,16 @@ func (c *indexConstraintCtx) init(
 }
 
 // isIndexColumn returns true if ev is a variable on the n indexed var that
-// corresponds to index column <offset>.
+// corresponds to index column <offset>, or if index column <offset> is a
+// computed column and ev is the expression which computes it (see
+// memo.SetComputedColumnExpr).
 func (c *indexConstraintCtx) isIndexColumn(ev memo.ExprView, offset int) bool {
-	return ev.Operator() == opt.VariableOp && ev.Private().(opt.ColumnID) == c.columns[offset].ID()
+	col := c.columns[offset].ID()
+	if ev.Operator() == opt.VariableOp {
+		return ev.Private().(opt.ColumnID) == col
+	}
+	group, ok := c.factory.Memo().ComputedColumnExpr(col)
+	return ok && ev.Group() == group
 }
 
 // isNullable returns true if the index column <offset> is nullable.
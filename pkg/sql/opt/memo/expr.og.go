@@ -13,6 +13,7 @@ var opLayoutTable = [...]opLayout{
 	opt.SortOp:            makeOpLayout(1 /*base*/, 0 /*list*/, 0 /*priv*/),
 	opt.ScanOp:            makeOpLayout(0 /*base*/, 0 /*list*/, 1 /*priv*/),
 	opt.ValuesOp:          makeOpLayout(0 /*base*/, 1 /*list*/, 3 /*priv*/),
+	opt.GeneratorOp:       makeOpLayout(1 /*base*/, 0 /*list*/, 2 /*priv*/),
 	opt.SelectOp:          makeOpLayout(2 /*base*/, 0 /*list*/, 0 /*priv*/),
 	opt.ProjectOp:         makeOpLayout(2 /*base*/, 0 /*list*/, 0 /*priv*/),
 	opt.InnerJoinOp:       makeOpLayout(3 /*base*/, 0 /*list*/, 0 /*priv*/),
@@ -37,6 +38,8 @@ var opLayoutTable = [...]opLayout{
 	opt.LimitOp:           makeOpLayout(2 /*base*/, 0 /*list*/, 3 /*priv*/),
 	opt.OffsetOp:          makeOpLayout(2 /*base*/, 0 /*list*/, 3 /*priv*/),
 	opt.Max1RowOp:         makeOpLayout(1 /*base*/, 0 /*list*/, 0 /*priv*/),
+	opt.RecursiveCTEOp:    makeOpLayout(2 /*base*/, 0 /*list*/, 3 /*priv*/),
+	opt.WorkTableOp:       makeOpLayout(0 /*base*/, 0 /*list*/, 1 /*priv*/),
 	opt.SubqueryOp:        makeOpLayout(2 /*base*/, 0 /*list*/, 0 /*priv*/),
 	opt.AnyOp:             makeOpLayout(1 /*base*/, 0 /*list*/, 0 /*priv*/),
 	opt.VariableOp:        makeOpLayout(0 /*base*/, 0 /*list*/, 1 /*priv*/),
@@ -111,6 +114,7 @@ var isEnforcerLookup = [...]bool{
 	opt.SortOp:            true,
 	opt.ScanOp:            false,
 	opt.ValuesOp:          false,
+	opt.GeneratorOp:       false,
 	opt.SelectOp:          false,
 	opt.ProjectOp:         false,
 	opt.InnerJoinOp:       false,
@@ -135,6 +139,8 @@ var isEnforcerLookup = [...]bool{
 	opt.LimitOp:           false,
 	opt.OffsetOp:          false,
 	opt.Max1RowOp:         false,
+	opt.RecursiveCTEOp:    false,
+	opt.WorkTableOp:       false,
 	opt.SubqueryOp:        false,
 	opt.AnyOp:             false,
 	opt.VariableOp:        false,
@@ -209,6 +215,7 @@ var isRelationalLookup = [...]bool{
 	opt.SortOp:            false,
 	opt.ScanOp:            true,
 	opt.ValuesOp:          true,
+	opt.GeneratorOp:       true,
 	opt.SelectOp:          true,
 	opt.ProjectOp:         true,
 	opt.InnerJoinOp:       true,
@@ -233,6 +240,8 @@ var isRelationalLookup = [...]bool{
 	opt.LimitOp:           true,
 	opt.OffsetOp:          true,
 	opt.Max1RowOp:         true,
+	opt.RecursiveCTEOp:    true,
+	opt.WorkTableOp:       true,
 	opt.SubqueryOp:        false,
 	opt.AnyOp:             false,
 	opt.VariableOp:        false,
@@ -307,6 +316,7 @@ var isJoinLookup = [...]bool{
 	opt.SortOp:            false,
 	opt.ScanOp:            false,
 	opt.ValuesOp:          false,
+	opt.GeneratorOp:       false,
 	opt.SelectOp:          false,
 	opt.ProjectOp:         false,
 	opt.InnerJoinOp:       true,
@@ -331,6 +341,8 @@ var isJoinLookup = [...]bool{
 	opt.LimitOp:           false,
 	opt.OffsetOp:          false,
 	opt.Max1RowOp:         false,
+	opt.RecursiveCTEOp:    false,
+	opt.WorkTableOp:       false,
 	opt.SubqueryOp:        false,
 	opt.AnyOp:             false,
 	opt.VariableOp:        false,
@@ -405,6 +417,7 @@ var isJoinApplyLookup = [...]bool{
 	opt.SortOp:            false,
 	opt.ScanOp:            false,
 	opt.ValuesOp:          false,
+	opt.GeneratorOp:       false,
 	opt.SelectOp:          false,
 	opt.ProjectOp:         false,
 	opt.InnerJoinOp:       false,
@@ -429,6 +442,8 @@ var isJoinApplyLookup = [...]bool{
 	opt.LimitOp:           false,
 	opt.OffsetOp:          false,
 	opt.Max1RowOp:         false,
+	opt.RecursiveCTEOp:    false,
+	opt.WorkTableOp:       false,
 	opt.SubqueryOp:        false,
 	opt.AnyOp:             false,
 	opt.VariableOp:        false,
@@ -503,6 +518,7 @@ var isScalarLookup = [...]bool{
 	opt.SortOp:            false,
 	opt.ScanOp:            false,
 	opt.ValuesOp:          false,
+	opt.GeneratorOp:       false,
 	opt.SelectOp:          false,
 	opt.ProjectOp:         false,
 	opt.InnerJoinOp:       false,
@@ -527,6 +543,8 @@ var isScalarLookup = [...]bool{
 	opt.LimitOp:           false,
 	opt.OffsetOp:          false,
 	opt.Max1RowOp:         false,
+	opt.RecursiveCTEOp:    false,
+	opt.WorkTableOp:       false,
 	opt.SubqueryOp:        true,
 	opt.AnyOp:             true,
 	opt.VariableOp:        true,
@@ -601,6 +619,7 @@ var isConstValueLookup = [...]bool{
 	opt.SortOp:            false,
 	opt.ScanOp:            false,
 	opt.ValuesOp:          false,
+	opt.GeneratorOp:       false,
 	opt.SelectOp:          false,
 	opt.ProjectOp:         false,
 	opt.InnerJoinOp:       false,
@@ -625,6 +644,8 @@ var isConstValueLookup = [...]bool{
 	opt.LimitOp:           false,
 	opt.OffsetOp:          false,
 	opt.Max1RowOp:         false,
+	opt.RecursiveCTEOp:    false,
+	opt.WorkTableOp:       false,
 	opt.SubqueryOp:        false,
 	opt.AnyOp:             false,
 	opt.VariableOp:        false,
@@ -699,6 +720,7 @@ var isBooleanLookup = [...]bool{
 	opt.SortOp:            false,
 	opt.ScanOp:            false,
 	opt.ValuesOp:          false,
+	opt.GeneratorOp:       false,
 	opt.SelectOp:          false,
 	opt.ProjectOp:         false,
 	opt.InnerJoinOp:       false,
@@ -723,6 +745,8 @@ var isBooleanLookup = [...]bool{
 	opt.LimitOp:           false,
 	opt.OffsetOp:          false,
 	opt.Max1RowOp:         false,
+	opt.RecursiveCTEOp:    false,
+	opt.WorkTableOp:       false,
 	opt.SubqueryOp:        false,
 	opt.AnyOp:             false,
 	opt.VariableOp:        false,
@@ -797,6 +821,7 @@ var isComparisonLookup = [...]bool{
 	opt.SortOp:            false,
 	opt.ScanOp:            false,
 	opt.ValuesOp:          false,
+	opt.GeneratorOp:       false,
 	opt.SelectOp:          false,
 	opt.ProjectOp:         false,
 	opt.InnerJoinOp:       false,
@@ -821,6 +846,8 @@ var isComparisonLookup = [...]bool{
 	opt.LimitOp:           false,
 	opt.OffsetOp:          false,
 	opt.Max1RowOp:         false,
+	opt.RecursiveCTEOp:    false,
+	opt.WorkTableOp:       false,
 	opt.SubqueryOp:        false,
 	opt.AnyOp:             false,
 	opt.VariableOp:        false,
@@ -895,6 +922,7 @@ var isBinaryLookup = [...]bool{
 	opt.SortOp:            false,
 	opt.ScanOp:            false,
 	opt.ValuesOp:          false,
+	opt.GeneratorOp:       false,
 	opt.SelectOp:          false,
 	opt.ProjectOp:         false,
 	opt.InnerJoinOp:       false,
@@ -919,6 +947,8 @@ var isBinaryLookup = [...]bool{
 	opt.LimitOp:           false,
 	opt.OffsetOp:          false,
 	opt.Max1RowOp:         false,
+	opt.RecursiveCTEOp:    false,
+	opt.WorkTableOp:       false,
 	opt.SubqueryOp:        false,
 	opt.AnyOp:             false,
 	opt.VariableOp:        false,
@@ -993,6 +1023,7 @@ var isUnaryLookup = [...]bool{
 	opt.SortOp:            false,
 	opt.ScanOp:            false,
 	opt.ValuesOp:          false,
+	opt.GeneratorOp:       false,
 	opt.SelectOp:          false,
 	opt.ProjectOp:         false,
 	opt.InnerJoinOp:       false,
@@ -1017,6 +1048,8 @@ var isUnaryLookup = [...]bool{
 	opt.LimitOp:           false,
 	opt.OffsetOp:          false,
 	opt.Max1RowOp:         false,
+	opt.RecursiveCTEOp:    false,
+	opt.WorkTableOp:       false,
 	opt.SubqueryOp:        false,
 	opt.AnyOp:             false,
 	opt.VariableOp:        false,
@@ -1224,6 +1257,40 @@ func (e *Expr) AsValues() *ValuesExpr {
 	return (*ValuesExpr)(e)
 }
 
+// GeneratorExpr returns the rows produced by a set-returning function used as a
+// data source in the FROM clause, such as generate_series().
+//
+// The Call field contains the Function expression, which evaluates to a table.
+// Its arguments can refer to outer columns when the Generator is the right
+// input of an apply join (see the LATERAL keyword).
+//
+// The Cols field contains the columns of the rows produced by the function,
+// in the same order.
+type GeneratorExpr Expr
+
+func MakeGeneratorExpr(call GroupID, cols PrivateID) GeneratorExpr {
+	return GeneratorExpr{op: opt.GeneratorOp, state: exprState{uint32(call), uint32(cols)}}
+}
+
+func (e *GeneratorExpr) Call() GroupID {
+	return GroupID(e.state[0])
+}
+
+func (e *GeneratorExpr) Cols() PrivateID {
+	return PrivateID(e.state[1])
+}
+
+func (e *GeneratorExpr) Fingerprint() Fingerprint {
+	return Fingerprint(*e)
+}
+
+func (e *Expr) AsGenerator() *GeneratorExpr {
+	if e.op != opt.GeneratorOp {
+		return nil
+	}
+	return (*GeneratorExpr)(e)
+}
+
 // SelectExpr filters rows from its input result set, based on the boolean filter
 // predicate expression. Rows which do not match the filter are discarded. While
 // the Filter operand can be any boolean expression, normalization rules will
@@ -2006,6 +2073,68 @@ func (e *Expr) AsMax1Row() *Max1RowExpr {
 	return (*Max1RowExpr)(e)
 }
 
+// RecursiveCTEExpr implements a recursive common table expression. The Initial
+// expression is evaluated first; its rows are returned and also become the
+// initial contents of the working table. The Recursive expression is then
+// evaluated repeatedly: in each iteration, the WorkTable operators inside it
+// produce the rows returned by the previous iteration. The rows of each
+// iteration are returned as well, until an iteration returns no rows. The
+// private field, Def, matches the columns of the Initial and Recursive inputs
+// with the output columns. See the comment above memo.RecursiveCTEDef for more
+// details.
+type RecursiveCTEExpr Expr
+
+func MakeRecursiveCTEExpr(initial GroupID, recursive GroupID, def PrivateID) RecursiveCTEExpr {
+	return RecursiveCTEExpr{op: opt.RecursiveCTEOp, state: exprState{uint32(initial), uint32(recursive), uint32(def)}}
+}
+
+func (e *RecursiveCTEExpr) Initial() GroupID {
+	return GroupID(e.state[0])
+}
+
+func (e *RecursiveCTEExpr) Recursive() GroupID {
+	return GroupID(e.state[1])
+}
+
+func (e *RecursiveCTEExpr) Def() PrivateID {
+	return PrivateID(e.state[2])
+}
+
+func (e *RecursiveCTEExpr) Fingerprint() Fingerprint {
+	return Fingerprint(*e)
+}
+
+func (e *Expr) AsRecursiveCTE() *RecursiveCTEExpr {
+	if e.op != opt.RecursiveCTEOp {
+		return nil
+	}
+	return (*RecursiveCTEExpr)(e)
+}
+
+// WorkTableExpr returns the contents of the working table of the enclosing
+// RecursiveCTE operator. It can only appear inside the Recursive input of that
+// operator.
+type WorkTableExpr Expr
+
+func MakeWorkTableExpr(def PrivateID) WorkTableExpr {
+	return WorkTableExpr{op: opt.WorkTableOp, state: exprState{uint32(def)}}
+}
+
+func (e *WorkTableExpr) Def() PrivateID {
+	return PrivateID(e.state[0])
+}
+
+func (e *WorkTableExpr) Fingerprint() Fingerprint {
+	return Fingerprint(*e)
+}
+
+func (e *Expr) AsWorkTable() *WorkTableExpr {
+	if e.op != opt.WorkTableOp {
+		return nil
+	}
+	return (*WorkTableExpr)(e)
+}
+
 // SubqueryExpr is a subquery in a single-row context such as
 // `SELECT 1 = (SELECT 1)` or `SELECT (1, 'a') = (SELECT 1, 'a')`.
 // In a single-row context, the outer query is only valid if the subquery
@@ -3744,6 +3873,20 @@ func (m *Memo) InternOrdering(val Ordering) PrivateID {
 	return m.privateStorage.internOrdering(val)
 }
 
+// InternRecursiveCTEDef adds the given value to the memo and returns an ID that
+// can be used for later lookup. If the same value was added previously,
+// this method is a no-op and returns the ID of the previous value.
+func (m *Memo) InternRecursiveCTEDef(val *RecursiveCTEDef) PrivateID {
+	return m.privateStorage.internRecursiveCTEDef(val)
+}
+
+// InternWorkTableDef adds the given value to the memo and returns an ID that
+// can be used for later lookup. If the same value was added previously,
+// this method is a no-op and returns the ID of the previous value.
+func (m *Memo) InternWorkTableDef(val *WorkTableDef) PrivateID {
+	return m.privateStorage.internWorkTableDef(val)
+}
+
 // InternColumnID adds the given value to the memo and returns an ID that
 // can be used for later lookup. If the same value was added previously,
 // this method is a no-op and returns the ID of the previous value.
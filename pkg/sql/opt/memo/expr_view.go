@@ -247,6 +247,9 @@ func (ev ExprView) formatRelational(tp treeprinter.Node, flags ExprFmtFlags) {
 	switch ev.Operator() {
 	case opt.ScanOp:
 		ev.mem.formatScanPrivate(&buf, ev.Private().(*ScanOpDef), true /* short */)
+
+	case opt.RecursiveCTEOp, opt.WorkTableOp:
+		fmt.Fprintf(&buf, " %s", ev.Private())
 	}
 
 	var physProps *PhysicalProps
@@ -273,7 +276,7 @@ func (ev ExprView) formatRelational(tp treeprinter.Node, flags ExprFmtFlags) {
 			colList := ev.Child(1).Private().(opt.ColList)
 			logProps.FormatColList(tp, ev.Metadata(), "columns:", colList)
 
-		case opt.ValuesOp:
+		case opt.ValuesOp, opt.GeneratorOp:
 			colList := ev.Private().(opt.ColList)
 			logProps.FormatColList(tp, ev.Metadata(), "columns:", colList)
 
@@ -282,6 +285,14 @@ func (ev ExprView) formatRelational(tp treeprinter.Node, flags ExprFmtFlags) {
 			colMap := ev.Private().(*SetOpColMap)
 			logProps.FormatColList(tp, ev.Metadata(), "columns:", colMap.Out)
 
+		case opt.RecursiveCTEOp:
+			def := ev.Private().(*RecursiveCTEDef)
+			logProps.FormatColList(tp, ev.Metadata(), "columns:", def.Out)
+
+		case opt.WorkTableOp:
+			def := ev.Private().(*WorkTableDef)
+			logProps.FormatColList(tp, ev.Metadata(), "columns:", def.Cols)
+
 		default:
 			// Fall back to writing output columns in column id order, with
 			// best guess label.
@@ -304,6 +315,13 @@ func (ev ExprView) formatRelational(tp treeprinter.Node, flags ExprFmtFlags) {
 		logProps.FormatColList(tp, ev.Metadata(), "left columns:", colMap.Left)
 		logProps.FormatColList(tp, ev.Metadata(), "right columns:", colMap.Right)
 
+	// Special-case handling for recursive CTEs to show the initial and
+	// recursive input columns that correspond to the output columns.
+	case opt.RecursiveCTEOp:
+		def := ev.Private().(*RecursiveCTEDef)
+		logProps.FormatColList(tp, ev.Metadata(), "initial columns:", def.Initial)
+		logProps.FormatColList(tp, ev.Metadata(), "recursive columns:", def.Recursive)
+
 	case opt.ScanOp:
 		def := ev.Private().(*ScanOpDef)
 		if def.Constraint != nil {
@@ -315,7 +333,7 @@ func (ev ExprView) formatRelational(tp treeprinter.Node, flags ExprFmtFlags) {
 	}
 
 	if !flags.HasFlags(ExprFmtHideStats) {
-		tp.Childf("stats: [rows=%d]", logProps.Relational.Stats.RowCount)
+		logProps.Relational.Stats.format(tp)
 	}
 
 	if !flags.HasFlags(ExprFmtHideCost) && ev.best != normBestOrdinal {
@@ -115,6 +115,73 @@ type RelationalProps struct {
 type Statistics struct {
 	// RowCount is the estimated number of rows returned by the expression.
 	RowCount uint64
+
+	// ColStats contains statistics on the output columns of the expression,
+	// keyed by column id. A column only has an entry if table statistics are
+	// available for it, or if its statistics could be derived from those of
+	// the expression's inputs. The map is immutable once the statistics have
+	// been built, and can therefore be shared with other expressions.
+	ColStats map[opt.ColumnID]*ColumnStatistic
+
+	// MultiColStats contains statistics on sets of output columns whose values
+	// are correlated, so that the number of distinct combinations of their
+	// values is less than the product of their distinct counts. They are only
+	// available on the columns of unconstrained scans that have multi-column
+	// table statistics, and on columns that pass through projections of those
+	// scans.
+	MultiColStats []MultiColumnStatistic
+}
+
+func (s *Statistics) format(tp treeprinter.Node) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "stats: [rows=%d", s.RowCount)
+
+	// Format the column statistics in column id order.
+	var cols opt.ColSet
+	for col := range s.ColStats {
+		cols.Add(int(col))
+	}
+	cols.ForEach(func(i int) {
+		colStat := s.ColStats[opt.ColumnID(i)]
+		fmt.Fprintf(&buf, ", distinct(%d)=%d, null(%d)=%d", i, colStat.DistinctCount, i, colStat.NullCount)
+	})
+	for i := range s.MultiColStats {
+		multiColStat := &s.MultiColStats[i]
+		fmt.Fprintf(&buf, ", distinct%s=%d", multiColStat.Cols, multiColStat.DistinctCount)
+	}
+
+	buf.WriteString("]")
+	tp.Child(buf.String())
+}
+
+// ColumnStatistic is a collection of statistics on a single output column of
+// a relational expression.
+type ColumnStatistic struct {
+	// DistinctCount is the estimated number of distinct non-NULL values of the
+	// column.
+	DistinctCount uint64
+
+	// NullCount is the estimated number of NULL values of the column.
+	NullCount uint64
+
+	// Histogram describes the distribution of the column values, or is nil if
+	// there is no histogram on the column. Histograms are only available on
+	// columns of unconstrained scans, and on columns that pass through
+	// projections of those scans; filters and joins change the distribution in
+	// ways that are not tracked.
+	Histogram []opt.HistogramBucket
+}
+
+// MultiColumnStatistic is a collection of statistics on a set of output
+// columns of a relational expression.
+type MultiColumnStatistic struct {
+	// Cols is the set of columns, which has at least two columns.
+	Cols opt.ColSet
+
+	// DistinctCount is the estimated number of distinct combinations of the
+	// values of the columns, excluding those that have a NULL value on any of
+	// the columns.
+	DistinctCount uint64
 }
 
 // ScalarProps are the subset of logical properties that are computed for
@@ -59,6 +59,9 @@ func (f logicalPropsFactory) constructRelationalProps(ev ExprView) LogicalProps
 	case opt.ValuesOp:
 		return f.constructValuesProps(ev)
 
+	case opt.GeneratorOp:
+		return f.constructGeneratorProps(ev)
+
 	case opt.InnerJoinOp, opt.LeftJoinOp, opt.RightJoinOp, opt.FullJoinOp,
 		opt.SemiJoinOp, opt.AntiJoinOp, opt.InnerJoinApplyOp, opt.LeftJoinApplyOp,
 		opt.RightJoinApplyOp, opt.FullJoinApplyOp, opt.SemiJoinApplyOp, opt.AntiJoinApplyOp:
@@ -76,6 +79,12 @@ func (f logicalPropsFactory) constructRelationalProps(ev ExprView) LogicalProps
 
 	case opt.OffsetOp, opt.Max1RowOp:
 		return f.passThroughRelationalProps(ev, 0 /* childIdx */)
+
+	case opt.RecursiveCTEOp:
+		return f.constructRecursiveCTEProps(ev)
+
+	case opt.WorkTableOp:
+		return f.constructWorkTableProps(ev)
 	}
 
 	panic(fmt.Sprintf("unrecognized relational expression type: %v", ev.op))
@@ -105,17 +114,8 @@ func (f logicalPropsFactory) constructScanProps(ev ExprView) LogicalProps {
 	props.Relational.WeakKeys = md.TableWeakKeys(def.Table)
 	filterWeakKeys(props.Relational)
 
-	// TODO: Need actual number of rows.
-	if def.Constraint != nil {
-		props.Relational.Stats.RowCount = 100
-	} else {
-		props.Relational.Stats.RowCount = 1000
-	}
-
-	// Cap number of rows at limit, if it exists.
-	if def.HardLimit > 0 && uint64(def.HardLimit) < props.Relational.Stats.RowCount {
-		props.Relational.Stats.RowCount = uint64(def.HardLimit)
-	}
+	sb := statisticsBuilder{evalCtx: f.evalCtx, md: md}
+	props.Relational.Stats = sb.buildScan(def)
 
 	return props
 }
@@ -128,8 +128,8 @@ func (f logicalPropsFactory) constructSelectProps(ev ExprView) LogicalProps {
 	// Inherit input properties as starting point.
 	*props.Relational = *inputProps
 
-	// TODO: Need better estimate based on actual filter conditions.
-	props.Relational.Stats.RowCount = inputProps.Stats.RowCount / 10
+	sb := statisticsBuilder{evalCtx: f.evalCtx, md: ev.Metadata()}
+	props.Relational.Stats = sb.buildSelect(ev.Child(1), &inputProps.Stats)
 
 	return props
 }
@@ -154,7 +154,8 @@ func (f logicalPropsFactory) constructProjectProps(ev ExprView) LogicalProps {
 	props.Relational.WeakKeys = inputProps.WeakKeys
 	filterWeakKeys(props.Relational)
 
-	props.Relational.Stats.RowCount = inputProps.Stats.RowCount
+	sb := statisticsBuilder{evalCtx: f.evalCtx, md: ev.Metadata()}
+	props.Relational.Stats = sb.buildProject(props.Relational.OutputCols, &inputProps.Stats)
 
 	return props
 }
@@ -197,11 +198,8 @@ func (f logicalPropsFactory) constructJoinProps(ev ExprView) LogicalProps {
 	// TODO(andyk): Need to derive weak keys for joins, for example when weak
 	//              keys on both sides are equivalent cols.
 
-	// TODO: Need better estimate based on actual on conditions.
-	props.Relational.Stats.RowCount = leftProps.Stats.RowCount * rightProps.Stats.RowCount
-	if ev.Child(2).Operator() != opt.TrueOp {
-		props.Relational.Stats.RowCount /= 10
-	}
+	sb := statisticsBuilder{evalCtx: f.evalCtx, md: ev.Metadata()}
+	props.Relational.Stats = sb.buildJoin(ev, leftProps, rightProps)
 
 	return props
 }
@@ -226,7 +224,6 @@ func (f logicalPropsFactory) constructGroupByProps(ev ExprView) LogicalProps {
 	if groupingColSet.Empty() {
 		// Any combination of columns is a weak key when there is one row.
 		props.Relational.WeakKeys = opt.WeakKeys{groupingColSet}
-		props.Relational.Stats.RowCount = 1
 	} else {
 		// The grouping columns always form a key because the GroupBy operation
 		// eliminates all duplicates. The result WeakKeys property either contains
@@ -240,11 +237,11 @@ func (f logicalPropsFactory) constructGroupByProps(ev ExprView) LogicalProps {
 		} else {
 			props.Relational.WeakKeys = opt.WeakKeys{groupingColSet}
 		}
-
-		// TODO: Need better estimate.
-		props.Relational.Stats.RowCount = inputProps.Stats.RowCount / 10
 	}
 
+	sb := statisticsBuilder{evalCtx: f.evalCtx, md: ev.Metadata()}
+	props.Relational.Stats = sb.buildGroupBy(groupingColSet, &inputProps.Stats)
+
 	return props
 }
 
@@ -285,6 +282,42 @@ func (f logicalPropsFactory) constructSetProps(ev ExprView) LogicalProps {
 	return props
 }
 
+func (f logicalPropsFactory) constructRecursiveCTEProps(ev ExprView) LogicalProps {
+	props := LogicalProps{Relational: &RelationalProps{}}
+
+	initialProps := ev.lookupChildGroup(0).logical.Relational
+	recursiveProps := ev.lookupChildGroup(1).logical.Relational
+	def := ev.Private().(*RecursiveCTEDef)
+
+	// Set the new output columns.
+	props.Relational.OutputCols = opt.ColListToSet(def.Out)
+
+	// Columns have to be not-null in both inputs to be not-null in the result.
+	for i := range def.Out {
+		if initialProps.NotNullCols.Contains(int(def.Initial[i])) &&
+			recursiveProps.NotNullCols.Contains(int(def.Recursive[i])) {
+			props.Relational.NotNullCols.Add(int(def.Out[i]))
+		}
+	}
+
+	// TODO: Need better estimate; the number of iterations is unknown.
+	props.Relational.Stats.RowCount = initialProps.Stats.RowCount + 10*recursiveProps.Stats.RowCount
+
+	return props
+}
+
+func (f logicalPropsFactory) constructWorkTableProps(ev ExprView) LogicalProps {
+	props := LogicalProps{Relational: &RelationalProps{}}
+
+	// Use output columns that are attached to the work table op.
+	props.Relational.OutputCols = opt.ColListToSet(ev.Private().(*WorkTableDef).Cols)
+
+	// TODO: Need better estimate.
+	props.Relational.Stats.RowCount = 100
+
+	return props
+}
+
 func (f logicalPropsFactory) constructValuesProps(ev ExprView) LogicalProps {
 	props := LogicalProps{Relational: &RelationalProps{}}
 
@@ -296,6 +329,22 @@ func (f logicalPropsFactory) constructValuesProps(ev ExprView) LogicalProps {
 	return props
 }
 
+func (f logicalPropsFactory) constructGeneratorProps(ev ExprView) LogicalProps {
+	props := LogicalProps{Relational: &RelationalProps{}}
+
+	// Use output columns that are attached to the generator op.
+	props.Relational.OutputCols = opt.ColListToSet(ev.Private().(opt.ColList))
+
+	// The arguments of the function can refer to outer columns.
+	props.Relational.OuterCols = ev.lookupChildGroup(0).logical.Scalar.OuterCols
+
+	// TODO: Need better estimate; the number of rows produced by the function
+	// is unknown.
+	props.Relational.Stats.RowCount = 10
+
+	return props
+}
+
 func (f logicalPropsFactory) constructLimitProps(ev ExprView) LogicalProps {
 	props := LogicalProps{Relational: &RelationalProps{}}
 
@@ -124,6 +124,11 @@ type Memo struct {
 	// Intern the set of unique privates used by expressions in the memo, since
 	// there are so many duplicates.
 	privateStorage privateStorage
+
+	// computedCols maps from the id of a computed column to the group of the
+	// scalar expression which computes it. It allows filters on an expression
+	// to be matched to an index on a column computing the same expression.
+	computedCols map[opt.ColumnID]GroupID
 }
 
 // New constructs a new empty memo instance.
@@ -155,6 +160,22 @@ func (m *Memo) Metadata() *opt.Metadata {
 	return m.metadata
 }
 
+// SetComputedColumnExpr records that the values of the given column are
+// computed by the scalar expression in the given group.
+func (m *Memo) SetComputedColumnExpr(col opt.ColumnID, group GroupID) {
+	if m.computedCols == nil {
+		m.computedCols = make(map[opt.ColumnID]GroupID)
+	}
+	m.computedCols[col] = group
+}
+
+// ComputedColumnExpr returns the group of the scalar expression computing the
+// values of the given column, if it was recorded by SetComputedColumnExpr.
+func (m *Memo) ComputedColumnExpr(col opt.ColumnID) (GroupID, bool) {
+	group, ok := m.computedCols[col]
+	return group, ok
+}
+
 // --------------------------------------------------------------------
 // Group methods.
 // --------------------------------------------------------------------
@@ -15,6 +15,8 @@
 package memo
 
 import (
+	"fmt"
+
 	"github.com/cockroachdb/cockroach/pkg/sql/opt"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/constraint"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
@@ -133,3 +135,53 @@ type SetOpColMap struct {
 	Right opt.ColList
 	Out   opt.ColList
 }
+
+// WorkTableID identifies the working table of a recursive CTE within a query.
+type WorkTableID int32
+
+// RecursiveCTEDef defines the value of the Def private field of the
+// RecursiveCTE operator. It matches columns from the initial and recursive
+// inputs with the output columns, in the same way that SetOpColMap does for
+// the set operators.
+type RecursiveCTEDef struct {
+	// Name is the name of the CTE.
+	Name string
+
+	// WorkTable identifies the working table that WorkTable operators in the
+	// recursive input refer to.
+	WorkTable WorkTableID
+
+	// UnionAll is true if the initial and recursive inputs are combined using
+	// UNION ALL; otherwise duplicate rows are discarded.
+	UnionAll bool
+
+	Initial   opt.ColList
+	Recursive opt.ColList
+	Out       opt.ColList
+}
+
+func (d RecursiveCTEDef) String() string {
+	if d.UnionAll {
+		return fmt.Sprintf("%s (all)", d.Name)
+	}
+	return d.Name
+}
+
+// WorkTableDef defines the value of the Def private field of the WorkTable
+// operator.
+type WorkTableDef struct {
+	// Name is the name of the CTE that the working table belongs to.
+	Name string
+
+	// ID identifies the working table; it matches the WorkTable field of the
+	// enclosing RecursiveCTEDef.
+	ID WorkTableID
+
+	// Cols are the columns produced by the working table. They are distinct
+	// from the output columns of the initial and recursive inputs.
+	Cols opt.ColList
+}
+
+func (d WorkTableDef) String() string {
+	return d.Name
+}
@@ -206,6 +206,49 @@ func (ps *privateStorage) internSetOpColMap(setOpColMap *SetOpColMap) PrivateID
 	return ps.addValue(privateKey{iface: typ, str: ps.keyBuf.String()}, setOpColMap)
 }
 
+// internRecursiveCTEDef adds the given value to storage and returns an id that
+// can later be used to retrieve the value by calling the lookup method. If the
+// value has been previously added to storage, then internRecursiveCTEDef
+// always returns the same private id that was returned from the previous call.
+func (ps *privateStorage) internRecursiveCTEDef(def *RecursiveCTEDef) PrivateID {
+	// The below code is carefully constructed to not allocate in the case where
+	// the value is already in the map. Be careful when modifying.
+	// The working table ID is unique within a query, so the name does not need
+	// to be written. The column lists are always the same length.
+	ps.keyBuf.Reset()
+	ps.keyBuf.writeUvarint(uint64(def.WorkTable))
+	if def.UnionAll {
+		ps.keyBuf.writeUvarint(1)
+	} else {
+		ps.keyBuf.writeUvarint(0)
+	}
+	ps.keyBuf.writeColList(def.Initial)
+	ps.keyBuf.writeColList(def.Recursive)
+	ps.keyBuf.writeColList(def.Out)
+	typ := (*RecursiveCTEDef)(nil)
+	if id, ok := ps.privatesMap[privateKey{iface: typ, str: ps.keyBuf.String()}]; ok {
+		return id
+	}
+	return ps.addValue(privateKey{iface: typ, str: ps.keyBuf.String()}, def)
+}
+
+// internWorkTableDef adds the given value to storage and returns an id that
+// can later be used to retrieve the value by calling the lookup method. If the
+// value has been previously added to storage, then internWorkTableDef always
+// returns the same private id that was returned from the previous call.
+func (ps *privateStorage) internWorkTableDef(def *WorkTableDef) PrivateID {
+	// The below code is carefully constructed to not allocate in the case where
+	// the value is already in the map. Be careful when modifying.
+	ps.keyBuf.Reset()
+	ps.keyBuf.writeUvarint(uint64(def.ID))
+	ps.keyBuf.writeColList(def.Cols)
+	typ := (*WorkTableDef)(nil)
+	if id, ok := ps.privatesMap[privateKey{iface: typ, str: ps.keyBuf.String()}]; ok {
+		return id
+	}
+	return ps.addValue(privateKey{iface: typ, str: ps.keyBuf.String()}, def)
+}
+
 // internDatum adds the given value to storage and returns an id that can later
 // be used to retrieve the value by calling the lookup method. If the value has
 // been previously added to storage, then internDatum always returns the same
@@ -0,0 +1,867 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package memo
+
+import (
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/constraint"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+)
+
+const (
+	// unknownFilterSelectivity is the fraction of rows that are estimated to
+	// satisfy a filter on columns that have no statistics.
+	unknownFilterSelectivity = 1.0 / 10.0
+
+	// unknownRangeSelectivity is the fraction of rows that are estimated to
+	// fall within a range of values of a column that has no histogram.
+	unknownRangeSelectivity = 1.0 / 3.0
+
+	// unknownConditionSelectivity is the fraction of rows that are estimated
+	// to satisfy the conditions of a filter that are not reflected in its
+	// constraints, or that refer to columns that have no statistics.
+	unknownConditionSelectivity = 1.0 / 3.0
+)
+
+// statisticsBuilder derives the statistics of a relational expression from
+// the table statistics in the catalog and from the statistics of its inputs.
+// Row counts are estimated by propagating the selectivity of filters and join
+// conditions, which is derived from the distinct counts and histograms of the
+// filtered columns. When no statistics are available for the columns involved,
+// the builder falls back on fixed estimates.
+type statisticsBuilder struct {
+	evalCtx *tree.EvalContext
+	md      *opt.Metadata
+}
+
+// buildScan derives the statistics of a Scan operator with the given
+// definition.
+func (sb *statisticsBuilder) buildScan(def *ScanOpDef) Statistics {
+	var stats Statistics
+
+	tab := sb.md.Table(def.Table)
+	if tab.StatisticCount() == 0 {
+		// TODO: Need actual number of rows.
+		if def.Constraint != nil {
+			stats.RowCount = 100
+		} else {
+			stats.RowCount = 1000
+		}
+	} else {
+		// Every statistic contains the number of rows in the table at the time
+		// it was created, so use the most recent one.
+		stats.RowCount = tab.Statistic(0).RowCount()
+
+		// Use the most recent statistic on each output column, and on each set
+		// of output columns.
+		stats.ColStats = make(map[opt.ColumnID]*ColumnStatistic)
+		for i := 0; i < tab.StatisticCount(); i++ {
+			stat := tab.Statistic(i)
+			if stat.ColumnCount() != 1 {
+				sb.addMultiColStat(&stats, def, stat)
+				continue
+			}
+			colID := sb.md.TableColumn(def.Table, stat.ColumnOrdinal(0))
+			if !def.Cols.Contains(int(colID)) {
+				continue
+			}
+			if _, ok := stats.ColStats[colID]; ok {
+				continue
+			}
+			stats.ColStats[colID] = &ColumnStatistic{
+				DistinctCount: stat.DistinctCount(),
+				NullCount:     stat.NullCount(),
+				Histogram:     stat.Histogram(),
+			}
+		}
+
+		if def.Constraint != nil {
+			constraints := []*constraint.Constraint{def.Constraint}
+			stats = sb.applyConstraints(&stats, constraints, true /* tight */)
+		}
+	}
+
+	// Cap number of rows at limit, if it exists.
+	if def.HardLimit > 0 && uint64(def.HardLimit) < stats.RowCount {
+		stats.RowCount = uint64(def.HardLimit)
+	}
+
+	return stats
+}
+
+// addMultiColStat adds the given multi-column table statistic to the
+// statistics of a Scan operator with the given definition, unless some of its
+// columns are not output by the scan, or a more recent statistic on the same
+// columns was already added.
+func (sb *statisticsBuilder) addMultiColStat(
+	stats *Statistics, def *ScanOpDef, stat opt.TableStatistic,
+) {
+	var cols opt.ColSet
+	for i := 0; i < stat.ColumnCount(); i++ {
+		cols.Add(int(sb.md.TableColumn(def.Table, stat.ColumnOrdinal(i))))
+	}
+	if cols.Len() < 2 || !cols.SubsetOf(def.Cols) {
+		return
+	}
+	for i := range stats.MultiColStats {
+		if stats.MultiColStats[i].Cols.Equals(cols) {
+			return
+		}
+	}
+	stats.MultiColStats = append(stats.MultiColStats, MultiColumnStatistic{
+		Cols:          cols,
+		DistinctCount: stat.DistinctCount(),
+	})
+}
+
+// buildSelect derives the statistics of a Select operator with the given
+// filter and input statistics.
+func (sb *statisticsBuilder) buildSelect(filter ExprView, inputStats *Statistics) Statistics {
+	scalar := filter.Logical().Scalar
+	var constraints []*constraint.Constraint
+	if scalar.Constraints != nil {
+		for i := 0; i < scalar.Constraints.Length(); i++ {
+			constraints = append(constraints, scalar.Constraints.Constraint(i))
+		}
+	}
+	return sb.applyConstraints(inputStats, constraints, scalar.TightConstraints)
+}
+
+// buildProject derives the statistics of a Project operator with the given
+// output columns and input statistics.
+func (sb *statisticsBuilder) buildProject(outputCols opt.ColSet, inputStats *Statistics) Statistics {
+	// Synthesized columns have no statistics.
+	return Statistics{
+		RowCount:      inputStats.RowCount,
+		ColStats:      filterColStats(inputStats.ColStats, outputCols),
+		MultiColStats: filterMultiColStats(inputStats.MultiColStats, outputCols),
+	}
+}
+
+// buildJoin derives the statistics of the given join operator from the
+// logical properties of its inputs. The selectivity of the join condition is
+// estimated from its equality conditions between left and right columns: the
+// values of the column with fewer distinct values are assumed to be a subset
+// of the values of the other column.
+func (sb *statisticsBuilder) buildJoin(
+	ev ExprView, leftProps, rightProps *RelationalProps,
+) Statistics {
+	var stats Statistics
+
+	leftRowCount := leftProps.Stats.RowCount
+	rightRowCount := rightProps.Stats.RowCount
+
+	sel, ok := sb.joinSelectivity(ev.Child(2), leftProps, rightProps)
+	if !ok {
+		// TODO: Need better estimate based on actual on conditions.
+		stats.RowCount = leftRowCount * rightRowCount
+		if ev.Child(2).Operator() != opt.TrueOp {
+			stats.RowCount /= 10
+		}
+	} else {
+		innerRowCount := roundRowCount(float64(leftRowCount) * float64(rightRowCount) * sel)
+
+		switch ev.Operator() {
+		case opt.LeftJoinOp, opt.LeftJoinApplyOp:
+			// Every left row is returned at least once.
+			stats.RowCount = maxRowCount(innerRowCount, leftRowCount)
+
+		case opt.RightJoinOp, opt.RightJoinApplyOp:
+			// Every right row is returned at least once.
+			stats.RowCount = maxRowCount(innerRowCount, rightRowCount)
+
+		case opt.FullJoinOp, opt.FullJoinApplyOp:
+			stats.RowCount = maxRowCount(innerRowCount, maxRowCount(leftRowCount, rightRowCount))
+
+		case opt.SemiJoinOp, opt.SemiJoinApplyOp:
+			// Every left row is returned at most once.
+			stats.RowCount = minRowCount(innerRowCount, leftRowCount)
+
+		case opt.AntiJoinOp, opt.AntiJoinApplyOp:
+			stats.RowCount = leftRowCount - minRowCount(innerRowCount, leftRowCount)
+
+		default:
+			stats.RowCount = innerRowCount
+		}
+	}
+
+	// Semi and anti joins only return the left columns.
+	colStats := leftProps.Stats.ColStats
+	switch ev.Operator() {
+	case opt.SemiJoinOp, opt.AntiJoinOp, opt.SemiJoinApplyOp, opt.AntiJoinApplyOp:
+
+	default:
+		if len(rightProps.Stats.ColStats) != 0 {
+			colStats = make(map[opt.ColumnID]*ColumnStatistic)
+			for col, colStat := range leftProps.Stats.ColStats {
+				colStats[col] = colStat
+			}
+			for col, colStat := range rightProps.Stats.ColStats {
+				colStats[col] = colStat
+			}
+		}
+	}
+	stats.ColStats = scaleColStats(colStats, stats.RowCount, 1 /* sel */)
+
+	// The left and right columns of an equality condition have the same
+	// values in the rows of an inner join, none of which are NULL.
+	switch ev.Operator() {
+	case opt.InnerJoinOp, opt.InnerJoinApplyOp:
+		for _, condition := range joinConditions(ev.Child(2)) {
+			leftCol, rightCol, ok := equalityCols(condition, leftProps, rightProps)
+			if !ok {
+				continue
+			}
+			leftStat, rightStat := stats.ColStats[leftCol], stats.ColStats[rightCol]
+			if leftStat == nil || rightStat == nil {
+				continue
+			}
+			distinct := minRowCount(leftStat.DistinctCount, rightStat.DistinctCount)
+			*leftStat = ColumnStatistic{DistinctCount: distinct}
+			*rightStat = ColumnStatistic{DistinctCount: distinct}
+		}
+	}
+
+	return stats
+}
+
+// buildGroupBy derives the statistics of a GroupBy operator with the given
+// grouping columns and input statistics.
+func (sb *statisticsBuilder) buildGroupBy(
+	groupingCols opt.ColSet, inputStats *Statistics,
+) Statistics {
+	var stats Statistics
+
+	// Scalar group by has no grouping columns and always a single row.
+	if groupingCols.Empty() {
+		stats.RowCount = 1
+		return stats
+	}
+
+	// The number of groups is the number of distinct combinations of values
+	// of the grouping columns (including NULL), assuming the columns are
+	// independent, except for the sets of columns that have a multi-column
+	// statistic.
+	groups, ok := 1.0, true
+	groupingCols.ForEach(func(i int) {
+		colStat, found := inputStats.ColStats[opt.ColumnID(i)]
+		if !found {
+			ok = false
+			return
+		}
+		groups *= float64(groupValues(colStat))
+	})
+	if ok {
+		for _, multiColStat := range sb.coveringMultiColStats(inputStats, groupingCols) {
+			var nulls bool
+			multiColStat.Cols.ForEach(func(i int) {
+				nulls = nulls || inputStats.ColStats[opt.ColumnID(i)].NullCount > 0
+			})
+			values := multiColStat.DistinctCount
+			if nulls {
+				values++
+			}
+			groups /= sb.correlationFactor(inputStats, multiColStat.Cols, values, groupValues)
+		}
+	}
+
+	if ok && groups < float64(inputStats.RowCount) {
+		stats.RowCount = roundRowCount(groups)
+	} else if ok {
+		stats.RowCount = inputStats.RowCount
+	} else {
+		// TODO: Need better estimate.
+		stats.RowCount = inputStats.RowCount / 10
+	}
+
+	// Each group has at most one NULL value on each grouping column.
+	colStats := filterColStats(inputStats.ColStats, groupingCols)
+	if len(colStats) != 0 {
+		stats.ColStats = make(map[opt.ColumnID]*ColumnStatistic, len(colStats))
+		for col, colStat := range colStats {
+			stats.ColStats[col] = &ColumnStatistic{
+				DistinctCount: minRowCount(colStat.DistinctCount, stats.RowCount),
+				NullCount:     minRowCount(colStat.NullCount, 1),
+			}
+		}
+	}
+
+	return stats
+}
+
+// applyConstraints returns the statistics of the rows of an expression with
+// the given statistics that satisfy a filter with the given constraints. If
+// tight is false, the filter has conditions that are not reflected in the
+// constraints.
+func (sb *statisticsBuilder) applyConstraints(
+	inputStats *Statistics, constraints []*constraint.Constraint, tight bool,
+) Statistics {
+	sel, known := 1.0, false
+	for _, c := range constraints {
+		constraintSel, ok := sb.constraintSelectivity(c, inputStats)
+		if !ok {
+			tight = false
+			continue
+		}
+		sel *= constraintSel
+		known = true
+	}
+
+	if !known {
+		// None of the filtered columns have statistics.
+		stats := Statistics{RowCount: inputStats.RowCount / 10}
+		stats.ColStats = scaleColStats(inputStats.ColStats, stats.RowCount, unknownFilterSelectivity)
+		return stats
+	}
+
+	// The selectivity of constraining correlated columns to single values is
+	// estimated from the number of distinct combinations of their values,
+	// rather than from the product of their individual selectivities.
+	singleValueCols := sb.singleValueCols(constraints)
+	for _, multiColStat := range sb.coveringMultiColStats(inputStats, singleValueCols) {
+		sel *= sb.correlationFactor(
+			inputStats, multiColStat.Cols, multiColStat.DistinctCount, distinctValues,
+		)
+	}
+	if sel > 1 {
+		sel = 1
+	}
+
+	if !tight {
+		sel *= unknownConditionSelectivity
+	}
+
+	stats := Statistics{RowCount: roundRowCount(float64(inputStats.RowCount) * sel)}
+	stats.ColStats = scaleColStats(inputStats.ColStats, stats.RowCount, sel)
+
+	// The constrained columns have at most as many distinct values as the
+	// constraint allows, and are not NULL unless the constraint allows it.
+	for _, c := range constraints {
+		col := c.Columns.Get(0).ID()
+		colStat, ok := stats.ColStats[col]
+		if !ok {
+			continue
+		}
+		if n, ok := sb.pointCount(c); ok {
+			if sb.includesNull(c) {
+				n--
+			}
+			if n < colStat.DistinctCount {
+				colStat.DistinctCount = n
+			}
+		}
+		if !sb.includesNull(c) {
+			colStat.NullCount = 0
+		}
+	}
+
+	return stats
+}
+
+// constraintSelectivity returns the estimated fraction of the rows of an
+// expression with the given statistics that satisfy the given constraint.
+// The selectivity is estimated from the histogram of the first constrained
+// column if there is one, and otherwise from its distinct count. It returns
+// ok=false if there are no statistics on the first constrained column.
+func (sb *statisticsBuilder) constraintSelectivity(
+	c *constraint.Constraint, stats *Statistics,
+) (sel float64, ok bool) {
+	colStat, ok := stats.ColStats[c.Columns.Get(0).ID()]
+	if !ok {
+		return 0, false
+	}
+
+	if colStat.Histogram != nil {
+		sel = sb.histogramSelectivity(c, colStat)
+	} else if n, ok := sb.pointCount(c); ok {
+		// Every non-NULL value is assumed to be equally frequent.
+		if stats.RowCount != 0 {
+			nonNullFraction := 1 - float64(colStat.NullCount)/float64(stats.RowCount)
+			if sb.includesNull(c) {
+				sel = float64(colStat.NullCount) / float64(stats.RowCount)
+				n--
+			}
+			if colStat.DistinctCount != 0 {
+				sel += nonNullFraction * float64(n) / float64(colStat.DistinctCount)
+			}
+		}
+	} else {
+		sel = unknownRangeSelectivity
+	}
+
+	// Other columns of a multi-column constraint that are constrained to a
+	// single value are assumed to be independent of the first column.
+	for i, n := 1, c.ExactPrefix(sb.evalCtx); i < n; i++ {
+		if colStat, ok := stats.ColStats[c.Columns.Get(i).ID()]; ok && colStat.DistinctCount != 0 {
+			sel /= float64(colStat.DistinctCount)
+		}
+	}
+
+	if sel > 1 {
+		sel = 1
+	}
+	return sel, true
+}
+
+// histogramSelectivity returns the fraction of the rows described by the
+// given column statistic that satisfy the given constraint, according to the
+// histogram of the first constrained column.
+func (sb *statisticsBuilder) histogramSelectivity(
+	c *constraint.Constraint, colStat *ColumnStatistic,
+) float64 {
+	total := float64(colStat.NullCount)
+	for i := range colStat.Histogram {
+		total += float64(colStat.Histogram[i].NumEq + colStat.Histogram[i].NumRange)
+	}
+	if total == 0 {
+		return 0
+	}
+
+	var rows float64
+	var prev spanBounds
+	desc := c.Columns.Get(0).Descending()
+	for i := 0; i < c.Spans.Count(); i++ {
+		bounds := sb.firstColBounds(c.Spans.Get(i), desc)
+		if i > 0 && sb.equalBounds(&bounds, &prev) {
+			// Spans of a multi-column constraint can have the same range of values
+			// on the first column; the rows in that range must only be counted once.
+			continue
+		}
+		rows += sb.rowsInBounds(&bounds, colStat)
+		prev = bounds
+	}
+	return rows / total
+}
+
+// spanBounds is the range of values of a single column that is allowed by a
+// span, in ascending order. A nil boundary value means the range is unbounded
+// on that side, and includes NULL if it is the lower boundary.
+type spanBounds struct {
+	lo, hi         tree.Datum
+	loIncl, hiIncl bool
+}
+
+// firstColBounds returns the range of values of the first column that is
+// allowed by the given span.
+func (sb *statisticsBuilder) firstColBounds(sp *constraint.Span, desc bool) spanBounds {
+	var bounds spanBounds
+	if start := sp.StartKey(); !start.IsEmpty() {
+		bounds.lo = start.Value(0)
+		bounds.loIncl = start.Length() > 1 || sp.StartBoundary() == constraint.IncludeBoundary
+	}
+	if end := sp.EndKey(); !end.IsEmpty() {
+		bounds.hi = end.Value(0)
+		bounds.hiIncl = end.Length() > 1 || sp.EndBoundary() == constraint.IncludeBoundary
+	}
+	if desc {
+		bounds.lo, bounds.hi = bounds.hi, bounds.lo
+		bounds.loIncl, bounds.hiIncl = bounds.hiIncl, bounds.loIncl
+	}
+	return bounds
+}
+
+// equalBounds returns true if the given ranges of values are equal.
+func (sb *statisticsBuilder) equalBounds(a, b *spanBounds) bool {
+	return sb.equalBound(a.lo, b.lo) && a.loIncl == b.loIncl &&
+		sb.equalBound(a.hi, b.hi) && a.hiIncl == b.hiIncl
+}
+
+func (sb *statisticsBuilder) equalBound(a, b tree.Datum) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Compare(sb.evalCtx, b) == 0
+}
+
+// rowsInBounds estimates the number of rows described by the given column
+// statistic that have a value within the given range. Values in the range
+// part of a histogram bucket that is only partially covered are assumed to be
+// split evenly between the covered and the uncovered parts.
+func (sb *statisticsBuilder) rowsInBounds(bounds *spanBounds, colStat *ColumnStatistic) float64 {
+	var rows float64
+
+	// NULL values sort before all other values.
+	lo, hi := bounds.lo, bounds.hi
+	if lo == nil || (lo == tree.DNull && bounds.loIncl) {
+		rows += float64(colStat.NullCount)
+	}
+	if lo == tree.DNull {
+		lo = nil
+	}
+	if hi == tree.DNull {
+		return rows
+	}
+
+	var prev tree.Datum
+	for i := range colStat.Histogram {
+		b := &colStat.Histogram[i]
+		upper := b.UpperBound
+
+		// The NumRange values are strictly between the upper bounds of the
+		// previous bucket and of this bucket.
+		disjoint := (hi != nil && prev != nil && hi.Compare(sb.evalCtx, prev) <= 0) ||
+			(lo != nil && lo.Compare(sb.evalCtx, upper) >= 0)
+		if b.NumRange != 0 && !disjoint {
+			covered := (lo == nil || (prev != nil && sb.lowerBoundBelow(lo, bounds.loIncl, prev))) &&
+				(hi == nil || sb.upperBoundAbove(hi, bounds.hiIncl, upper))
+			if covered {
+				rows += float64(b.NumRange)
+			} else {
+				rows += float64(b.NumRange) / 2
+			}
+		}
+
+		// The NumEq values are equal to the upper bound.
+		if sb.boundsContain(lo, bounds.loIncl, hi, bounds.hiIncl, upper) {
+			rows += float64(b.NumEq)
+		}
+
+		prev = upper
+	}
+
+	return rows
+}
+
+// lowerBoundBelow returns true if the given lower boundary allows all values
+// greater than val. An inclusive boundary that is the value immediately
+// following val qualifies, since spans on discrete types are built with
+// inclusive boundaries.
+func (sb *statisticsBuilder) lowerBoundBelow(lo tree.Datum, loIncl bool, val tree.Datum) bool {
+	if lo.Compare(sb.evalCtx, val) <= 0 {
+		return true
+	}
+	next, ok := val.Next(sb.evalCtx)
+	return ok && loIncl && lo.Compare(sb.evalCtx, next) == 0
+}
+
+// upperBoundAbove returns true if the given upper boundary allows all values
+// less than val.
+func (sb *statisticsBuilder) upperBoundAbove(hi tree.Datum, hiIncl bool, val tree.Datum) bool {
+	if hi.Compare(sb.evalCtx, val) >= 0 {
+		return true
+	}
+	prev, ok := val.Prev(sb.evalCtx)
+	return ok && hiIncl && hi.Compare(sb.evalCtx, prev) == 0
+}
+
+// boundsContain returns true if the given value is within the given range.
+func (sb *statisticsBuilder) boundsContain(
+	lo tree.Datum, loIncl bool, hi tree.Datum, hiIncl bool, val tree.Datum,
+) bool {
+	if lo != nil {
+		if cmp := val.Compare(sb.evalCtx, lo); cmp < 0 || (cmp == 0 && !loIncl) {
+			return false
+		}
+	}
+	if hi != nil {
+		if cmp := val.Compare(sb.evalCtx, hi); cmp > 0 || (cmp == 0 && !hiIncl) {
+			return false
+		}
+	}
+	return true
+}
+
+// pointCount returns the number of distinct values of the first column that
+// are allowed by the given constraint, if every span of the constraint
+// allows a single value of the first column. Otherwise, it returns ok=false.
+func (sb *statisticsBuilder) pointCount(c *constraint.Constraint) (n uint64, ok bool) {
+	var prev tree.Datum
+	for i := 0; i < c.Spans.Count(); i++ {
+		sp := c.Spans.Get(i)
+		start, end := sp.StartKey(), sp.EndKey()
+		if start.IsEmpty() || end.IsEmpty() {
+			return 0, false
+		}
+		val := start.Value(0)
+		if val.Compare(sb.evalCtx, end.Value(0)) != 0 {
+			return 0, false
+		}
+		if prev == nil || val.Compare(sb.evalCtx, prev) != 0 {
+			n++
+		}
+		prev = val
+	}
+	return n, true
+}
+
+// includesNull returns true if the given constraint allows NULL values of
+// its first column.
+func (sb *statisticsBuilder) includesNull(c *constraint.Constraint) bool {
+	if c.Spans.Count() == 0 {
+		return false
+	}
+
+	// NULL values sort first in ascending order, and last in descending order.
+	var bounds spanBounds
+	if c.Columns.Get(0).Descending() {
+		bounds = sb.firstColBounds(c.Spans.Get(c.Spans.Count()-1), true /* desc */)
+	} else {
+		bounds = sb.firstColBounds(c.Spans.Get(0), false /* desc */)
+	}
+	return bounds.lo == nil || (bounds.lo == tree.DNull && bounds.loIncl)
+}
+
+// singleValueCols returns the columns that the given constraints restrict to
+// a single non-NULL value.
+func (sb *statisticsBuilder) singleValueCols(constraints []*constraint.Constraint) opt.ColSet {
+	var cols opt.ColSet
+	for _, c := range constraints {
+		n := c.ExactPrefix(sb.evalCtx)
+		if n == 0 {
+			continue
+		}
+		start := c.Spans.Get(0).StartKey()
+		for i := 0; i < n; i++ {
+			if start.Value(i) != tree.DNull {
+				cols.Add(int(c.Columns.Get(i).ID()))
+			}
+		}
+	}
+	return cols
+}
+
+// coveringMultiColStats returns the multi-column statistics in the given
+// statistics whose columns are a subset of the given columns and all have a
+// column statistic. The returned statistics have disjoint sets of columns;
+// statistics on more columns are preferred, since they capture more of the
+// correlation between the columns.
+func (sb *statisticsBuilder) coveringMultiColStats(
+	stats *Statistics, cols opt.ColSet,
+) []*MultiColumnStatistic {
+	var candidates []*MultiColumnStatistic
+	for i := range stats.MultiColStats {
+		multiColStat := &stats.MultiColStats[i]
+		if !multiColStat.Cols.SubsetOf(cols) {
+			continue
+		}
+		covered := true
+		multiColStat.Cols.ForEach(func(col int) {
+			_, ok := stats.ColStats[opt.ColumnID(col)]
+			covered = covered && ok
+		})
+		if covered {
+			candidates = append(candidates, multiColStat)
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Cols.Len() > candidates[j].Cols.Len()
+	})
+
+	var used opt.ColSet
+	var result []*MultiColumnStatistic
+	for _, multiColStat := range candidates {
+		if !multiColStat.Cols.Intersects(used) {
+			result = append(result, multiColStat)
+			used.UnionWith(multiColStat.Cols)
+		}
+	}
+	return result
+}
+
+// correlationFactor returns the ratio between the number of combinations of
+// values of the given columns assuming they are independent, which is the
+// product of the number of values of each column, and the given number of
+// combinations. The number of values of each column is returned by the given
+// function. The given number of combinations is adjusted to lie between the
+// largest number of values of a single column and the product, so that the
+// factor is at least 1.
+func (sb *statisticsBuilder) correlationFactor(
+	stats *Statistics, cols opt.ColSet, combinations uint64, values func(*ColumnStatistic) uint64,
+) float64 {
+	product, largest := 1.0, 1.0
+	cols.ForEach(func(i int) {
+		n := float64(values(stats.ColStats[opt.ColumnID(i)]))
+		product *= n
+		if n > largest {
+			largest = n
+		}
+	})
+	n := float64(combinations)
+	if n < largest {
+		n = largest
+	}
+	if n > product {
+		n = product
+	}
+	if n == 0 {
+		return 1
+	}
+	return product / n
+}
+
+// distinctValues returns the number of distinct non-NULL values of the column
+// with the given statistic.
+func distinctValues(colStat *ColumnStatistic) uint64 {
+	return colStat.DistinctCount
+}
+
+// groupValues returns the number of distinct values of the column with the
+// given statistic, counting NULL as a value.
+func groupValues(colStat *ColumnStatistic) uint64 {
+	if colStat.NullCount > 0 {
+		return colStat.DistinctCount + 1
+	}
+	return colStat.DistinctCount
+}
+
+// joinSelectivity returns the estimated fraction of the pairs of left and
+// right rows that satisfy the given join condition. It returns ok=false if
+// none of the equality conditions between left and right columns refers to
+// columns with statistics.
+func (sb *statisticsBuilder) joinSelectivity(
+	on ExprView, leftProps, rightProps *RelationalProps,
+) (sel float64, ok bool) {
+	if on.Operator() == opt.TrueOp {
+		return 1, false
+	}
+
+	sel, unknown := 1.0, false
+	for _, condition := range joinConditions(on) {
+		var leftStat, rightStat *ColumnStatistic
+		if leftCol, rightCol, ok := equalityCols(condition, leftProps, rightProps); ok {
+			leftStat, rightStat = leftProps.Stats.ColStats[leftCol], rightProps.Stats.ColStats[rightCol]
+		}
+		if leftStat == nil || rightStat == nil {
+			unknown = true
+			continue
+		}
+		distinct := maxRowCount(leftStat.DistinctCount, rightStat.DistinctCount)
+		if distinct != 0 {
+			sel /= float64(distinct)
+		}
+		ok = true
+	}
+
+	if unknown {
+		sel *= unknownConditionSelectivity
+	}
+	return sel, ok
+}
+
+// joinConditions returns the conditions of the given join filter.
+func joinConditions(on ExprView) []ExprView {
+	if on.Operator() != opt.FiltersOp {
+		return []ExprView{on}
+	}
+	conditions := make([]ExprView, on.ChildCount())
+	for i := range conditions {
+		conditions[i] = on.Child(i)
+	}
+	return conditions
+}
+
+// equalityCols returns the left and right columns of the given join
+// condition, if it is an equality between a left column and a right column.
+// Otherwise, it returns ok=false.
+func equalityCols(
+	condition ExprView, leftProps, rightProps *RelationalProps,
+) (leftCol, rightCol opt.ColumnID, ok bool) {
+	if condition.Operator() != opt.EqOp {
+		return 0, 0, false
+	}
+	left, right := condition.Child(0), condition.Child(1)
+	if left.Operator() != opt.VariableOp || right.Operator() != opt.VariableOp {
+		return 0, 0, false
+	}
+	leftCol = left.Private().(opt.ColumnID)
+	rightCol = right.Private().(opt.ColumnID)
+	if rightProps.OutputCols.Contains(int(leftCol)) && leftProps.OutputCols.Contains(int(rightCol)) {
+		leftCol, rightCol = rightCol, leftCol
+	}
+	if !leftProps.OutputCols.Contains(int(leftCol)) || !rightProps.OutputCols.Contains(int(rightCol)) {
+		return 0, 0, false
+	}
+	return leftCol, rightCol, true
+}
+
+// filterColStats returns the column statistics of the given columns. It
+// respects immutability by making a copy of the map if it needs to be updated.
+func filterColStats(
+	colStats map[opt.ColumnID]*ColumnStatistic, cols opt.ColSet,
+) map[opt.ColumnID]*ColumnStatistic {
+	for col := range colStats {
+		if !cols.Contains(int(col)) {
+			filtered := make(map[opt.ColumnID]*ColumnStatistic)
+			for col, colStat := range colStats {
+				if cols.Contains(int(col)) {
+					filtered[col] = colStat
+				}
+			}
+			return filtered
+		}
+	}
+	return colStats
+}
+
+// filterMultiColStats returns the multi-column statistics on subsets of the
+// given columns. It respects immutability by making a copy of the slice if it
+// needs to be updated.
+func filterMultiColStats(
+	multiColStats []MultiColumnStatistic, cols opt.ColSet,
+) []MultiColumnStatistic {
+	for i := range multiColStats {
+		if !multiColStats[i].Cols.SubsetOf(cols) {
+			var filtered []MultiColumnStatistic
+			for i := range multiColStats {
+				if multiColStats[i].Cols.SubsetOf(cols) {
+					filtered = append(filtered, multiColStats[i])
+				}
+			}
+			return filtered
+		}
+	}
+	return multiColStats
+}
+
+// scaleColStats returns the column statistics of the rows of an expression
+// with the given column statistics that satisfy a filter with the given
+// selectivity, and whose row count is the given row count. Histograms are not
+// kept, since the filter changes the distribution of the values.
+func scaleColStats(
+	colStats map[opt.ColumnID]*ColumnStatistic, rowCount uint64, sel float64,
+) map[opt.ColumnID]*ColumnStatistic {
+	if len(colStats) == 0 {
+		return nil
+	}
+	scaled := make(map[opt.ColumnID]*ColumnStatistic, len(colStats))
+	for col, colStat := range colStats {
+		scaled[col] = &ColumnStatistic{
+			DistinctCount: minRowCount(colStat.DistinctCount, rowCount),
+			NullCount:     minRowCount(roundRowCount(float64(colStat.NullCount)*sel), rowCount),
+		}
+	}
+	return scaled
+}
+
+// roundRowCount rounds the given estimate to the nearest row count.
+func roundRowCount(rows float64) uint64 {
+	return uint64(rows + 0.5)
+}
+
+func minRowCount(a, b uint64) uint64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxRowCount(a, b uint64) uint64 {
+	if a > b {
+		return a
+	}
+	return b
+}
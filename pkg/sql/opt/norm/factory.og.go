@@ -44,6 +44,20 @@ func (_f *Factory) InternOrdering(val memo.Ordering) memo.PrivateID {
 	return _f.mem.InternOrdering(val)
 }
 
+// InternRecursiveCTEDef adds the given value to the memo and returns an ID that
+// can be used for later lookup. If the same value was added previously,
+// this method is a no-op and returns the ID of the previous value.
+func (_f *Factory) InternRecursiveCTEDef(val *memo.RecursiveCTEDef) memo.PrivateID {
+	return _f.mem.InternRecursiveCTEDef(val)
+}
+
+// InternWorkTableDef adds the given value to the memo and returns an ID that
+// can be used for later lookup. If the same value was added previously,
+// this method is a no-op and returns the ID of the previous value.
+func (_f *Factory) InternWorkTableDef(val *memo.WorkTableDef) memo.PrivateID {
+	return _f.mem.InternWorkTableDef(val)
+}
+
 // InternColumnID adds the given value to the memo and returns an ID that
 // can be used for later lookup. If the same value was added previously,
 // this method is a no-op and returns the ID of the previous value.
@@ -119,6 +133,29 @@ func (_f *Factory) ConstructValues(
 	return _f.onConstruct(_f.mem.MemoizeNormExpr(_f.evalCtx, memo.Expr(_valuesExpr)))
 }
 
+// ConstructGenerator constructs an expression for the Generator operator.
+// Generator returns the rows produced by a set-returning function used as a
+// data source in the FROM clause, such as generate_series().
+//
+// The Call field contains the Function expression, which evaluates to a table.
+// Its arguments can refer to outer columns when the Generator is the right
+// input of an apply join (see the LATERAL keyword).
+//
+// The Cols field contains the columns of the rows produced by the function,
+// in the same order.
+func (_f *Factory) ConstructGenerator(
+	call memo.GroupID,
+	cols memo.PrivateID,
+) memo.GroupID {
+	_generatorExpr := memo.MakeGeneratorExpr(call, cols)
+	_group := _f.mem.GroupByFingerprint(_generatorExpr.Fingerprint())
+	if _group != 0 {
+		return _group
+	}
+
+	return _f.onConstruct(_f.mem.MemoizeNormExpr(_f.evalCtx, memo.Expr(_generatorExpr)))
+}
+
 // ConstructSelect constructs an expression for the Select operator.
 // Select filters rows from its input result set, based on the boolean filter
 // predicate expression. Rows which do not match the filter are discarded. While
@@ -1820,6 +1857,46 @@ func (_f *Factory) ConstructMax1Row(
 	return _f.onConstruct(_f.mem.MemoizeNormExpr(_f.evalCtx, memo.Expr(_max1RowExpr)))
 }
 
+// ConstructRecursiveCTE constructs an expression for the RecursiveCTE operator.
+// RecursiveCTE implements a recursive common table expression. The Initial
+// expression is evaluated first; its rows are returned and also become the
+// initial contents of the working table. The Recursive expression is then
+// evaluated repeatedly: in each iteration, the WorkTable operators inside it
+// produce the rows returned by the previous iteration. The rows of each
+// iteration are returned as well, until an iteration returns no rows. The
+// private field, Def, matches the columns of the Initial and Recursive inputs
+// with the output columns. See the comment above memo.RecursiveCTEDef for more
+// details.
+func (_f *Factory) ConstructRecursiveCTE(
+	initial memo.GroupID,
+	recursive memo.GroupID,
+	def memo.PrivateID,
+) memo.GroupID {
+	_recursiveCTEExpr := memo.MakeRecursiveCTEExpr(initial, recursive, def)
+	_group := _f.mem.GroupByFingerprint(_recursiveCTEExpr.Fingerprint())
+	if _group != 0 {
+		return _group
+	}
+
+	return _f.onConstruct(_f.mem.MemoizeNormExpr(_f.evalCtx, memo.Expr(_recursiveCTEExpr)))
+}
+
+// ConstructWorkTable constructs an expression for the WorkTable operator.
+// WorkTable returns the contents of the working table of the enclosing
+// RecursiveCTE operator. It can only appear inside the Recursive input of that
+// operator.
+func (_f *Factory) ConstructWorkTable(
+	def memo.PrivateID,
+) memo.GroupID {
+	_workTableExpr := memo.MakeWorkTableExpr(def)
+	_group := _f.mem.GroupByFingerprint(_workTableExpr.Fingerprint())
+	if _group != 0 {
+		return _group
+	}
+
+	return _f.onConstruct(_f.mem.MemoizeNormExpr(_f.evalCtx, memo.Expr(_workTableExpr)))
+}
+
 // ConstructSubquery constructs an expression for the Subquery operator.
 // Subquery is a subquery in a single-row context such as
 // `SELECT 1 = (SELECT 1)` or `SELECT (1, 'a') = (SELECT 1, 'a')`.
@@ -5337,6 +5414,11 @@ func init() {
 		return f.ConstructValues(operands[0].ListID(), memo.PrivateID(operands[1]))
 	}
 
+	// GeneratorOp
+	dynConstructLookup[opt.GeneratorOp] = func(f *Factory, operands DynamicOperands) memo.GroupID {
+		return f.ConstructGenerator(memo.GroupID(operands[0]), memo.PrivateID(operands[1]))
+	}
+
 	// SelectOp
 	dynConstructLookup[opt.SelectOp] = func(f *Factory, operands DynamicOperands) memo.GroupID {
 		return f.ConstructSelect(memo.GroupID(operands[0]), memo.GroupID(operands[1]))
@@ -5457,6 +5539,16 @@ func init() {
 		return f.ConstructMax1Row(memo.GroupID(operands[0]))
 	}
 
+	// RecursiveCTEOp
+	dynConstructLookup[opt.RecursiveCTEOp] = func(f *Factory, operands DynamicOperands) memo.GroupID {
+		return f.ConstructRecursiveCTE(memo.GroupID(operands[0]), memo.GroupID(operands[1]), memo.PrivateID(operands[2]))
+	}
+
+	// WorkTableOp
+	dynConstructLookup[opt.WorkTableOp] = func(f *Factory, operands DynamicOperands) memo.GroupID {
+		return f.ConstructWorkTable(memo.PrivateID(operands[0]))
+	}
+
 	// SubqueryOp
 	dynConstructLookup[opt.SubqueryOp] = func(f *Factory, operands DynamicOperands) memo.GroupID {
 		return f.ConstructSubquery(memo.GroupID(operands[0]), memo.GroupID(operands[1]))
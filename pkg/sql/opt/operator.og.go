@@ -37,6 +37,17 @@ const (
 	// as an opt.ColList. It is legal for Cols to be empty.
 	ValuesOp
 
+	// GeneratorOp returns the rows produced by a set-returning function used as a
+	// data source in the FROM clause, such as generate_series().
+	//
+	// The Call field contains the Function expression, which evaluates to a table.
+	// Its arguments can refer to outer columns when the Generator is the right
+	// input of an apply join (see the LATERAL keyword).
+	//
+	// The Cols field contains the columns of the rows produced by the function,
+	// in the same order.
+	GeneratorOp
+
 	// SelectOp filters rows from its input result set, based on the boolean filter
 	// predicate expression. Rows which do not match the filter are discarded. While
 	// the Filter operand can be any boolean expression, normalization rules will
@@ -187,6 +198,22 @@ const (
 	// Subquery for more details.
 	Max1RowOp
 
+	// RecursiveCTEOp implements a recursive common table expression. The Initial
+	// expression is evaluated first; its rows are returned and also become the
+	// initial contents of the working table. The Recursive expression is then
+	// evaluated repeatedly: in each iteration, the WorkTable operators inside it
+	// produce the rows returned by the previous iteration. The rows of each
+	// iteration are returned as well, until an iteration returns no rows. The
+	// private field, Def, matches the columns of the Initial and Recursive inputs
+	// with the output columns. See the comment above memo.RecursiveCTEDef for more
+	// details.
+	RecursiveCTEOp
+
+	// WorkTableOp returns the contents of the working table of the enclosing
+	// RecursiveCTE operator. It can only appear inside the Recursive input of that
+	// operator.
+	WorkTableOp
+
 	// ------------------------------------------------------------
 	// Scalar Operators
 	// ------------------------------------------------------------
@@ -456,9 +483,9 @@ const (
 	NumOperators
 )
 
-const opNames = "unknownsortscanvaluesselectprojectinner-joinleft-joinright-joinfull-joinsemi-joinanti-joininner-join-applyleft-join-applyright-join-applyfull-join-applysemi-join-applyanti-join-applygroup-byunionintersectexceptunion-allintersect-allexcept-alllimitoffsetmax1-rowsubqueryanyvariableconstnulltruefalseplaceholdertupleprojectionsaggregationsexistsfiltersandornoteqltgtlegeneinnot-inlikenot-likei-likenot-i-likesimilar-tonot-similar-toreg-matchnot-reg-matchreg-i-matchnot-reg-i-matchisis-notcontainsjson-existsjson-all-existsjson-some-existsbitandbitorbitxorplusminusmultdivfloor-divmodpowconcatl-shiftr-shiftfetch-valfetch-textfetch-val-pathfetch-text-pathunary-minusunary-complementcastcasewhenarrayfunctioncoalesceunsupported-expr"
+const opNames = "unknownsortscanvaluesgeneratorselectprojectinner-joinleft-joinright-joinfull-joinsemi-joinanti-joininner-join-applyleft-join-applyright-join-applyfull-join-applysemi-join-applyanti-join-applygroup-byunionintersectexceptunion-allintersect-allexcept-alllimitoffsetmax1-rowrecursive-c-t-ework-tablesubqueryanyvariableconstnulltruefalseplaceholdertupleprojectionsaggregationsexistsfiltersandornoteqltgtlegeneinnot-inlikenot-likei-likenot-i-likesimilar-tonot-similar-toreg-matchnot-reg-matchreg-i-matchnot-reg-i-matchisis-notcontainsjson-existsjson-all-existsjson-some-existsbitandbitorbitxorplusminusmultdivfloor-divmodpowconcatl-shiftr-shiftfetch-valfetch-textfetch-val-pathfetch-text-pathunary-minusunary-complementcastcasewhenarrayfunctioncoalesceunsupported-expr"
 
-var opIndexes = [...]uint32{0, 7, 11, 15, 21, 27, 34, 44, 53, 63, 72, 81, 90, 106, 121, 137, 152, 167, 182, 190, 195, 204, 210, 219, 232, 242, 247, 253, 261, 269, 272, 280, 285, 289, 293, 298, 309, 314, 325, 337, 343, 350, 353, 355, 358, 360, 362, 364, 366, 368, 370, 372, 378, 382, 390, 396, 406, 416, 430, 439, 452, 463, 478, 480, 486, 494, 505, 520, 536, 542, 547, 553, 557, 562, 566, 569, 578, 581, 584, 590, 597, 604, 613, 623, 637, 652, 663, 679, 683, 687, 691, 696, 704, 712, 728}
+var opIndexes = [...]uint32{0, 7, 11, 15, 21, 30, 36, 43, 53, 62, 72, 81, 90, 99, 115, 130, 146, 161, 176, 191, 199, 204, 213, 219, 228, 241, 251, 256, 262, 270, 285, 295, 303, 306, 314, 319, 323, 327, 332, 343, 348, 359, 371, 377, 384, 387, 389, 392, 394, 396, 398, 400, 402, 404, 406, 412, 416, 424, 430, 440, 450, 464, 473, 486, 497, 512, 514, 520, 528, 539, 554, 570, 576, 581, 587, 591, 596, 600, 603, 612, 615, 618, 624, 631, 638, 647, 657, 671, 686, 697, 713, 717, 721, 725, 730, 738, 746, 762}
 
 var EnforcerOperators = [...]Operator{
 	SortOp,
@@ -467,6 +494,7 @@ var EnforcerOperators = [...]Operator{
 var RelationalOperators = [...]Operator{
 	ScanOp,
 	ValuesOp,
+	GeneratorOp,
 	SelectOp,
 	ProjectOp,
 	InnerJoinOp,
@@ -491,6 +519,8 @@ var RelationalOperators = [...]Operator{
 	LimitOp,
 	OffsetOp,
 	Max1RowOp,
+	RecursiveCTEOp,
+	WorkTableOp,
 }
 
 var JoinOperators = [...]Operator{
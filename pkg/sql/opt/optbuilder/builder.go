@@ -64,6 +64,10 @@ type Builder struct {
 
 	// Skip index 0 in order to reserve it to indicate the "unknown" column.
 	colMap []scopeColumn
+
+	// workTableCount is the number of working tables of recursive CTEs that
+	// have been built so far. It is used to assign each a unique ID.
+	workTableCount int
 }
 
 // New creates a new Builder structure initialized with the given
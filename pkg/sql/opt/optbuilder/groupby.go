@@ -296,6 +296,12 @@ func (b *Builder) buildGrouping(
 	// Unwrap parenthesized expressions like "((a))" to "a".
 	groupBy = tree.StripParens(groupBy)
 
+	if _, ok := groupBy.(*tree.GroupingSet); ok {
+		panic(builderError{
+			pgerror.Unimplemented("grouping sets", "GROUPING SETS, ROLLUP and CUBE are not supported yet"),
+		})
+	}
+
 	// Check whether the GROUP BY clause refers to a column in the SELECT list
 	// by index, e.g. `SELECT a, SUM(b) FROM y GROUP BY 1`.
 	col := colIndex(len(selects), groupBy, "GROUP BY")
@@ -328,7 +334,10 @@ func (b *Builder) buildGrouping(
 func (b *Builder) buildAggregateFunction(
 	f *tree.FuncExpr, funcDef memo.FuncOpDef, label string, inScope *scope,
 ) *scopeColumn {
-	if len(f.Exprs) > 1 {
+	// The sort expressions of ordered-set aggregates are passed as trailing
+	// arguments.
+	args := f.AggregateArgs()
+	if len(args) > 1 {
 		// TODO: #10495
 		panic(builderError{
 			pgerror.UnimplementedWithIssueError(10495, "aggregate functions with multiple arguments are not supported yet"),
@@ -339,10 +348,10 @@ func (b *Builder) buildAggregateFunction(
 
 	info := aggregateInfo{
 		def:  funcDef,
-		args: make([]memo.GroupID, len(f.Exprs)),
+		args: make([]memo.GroupID, len(args)),
 	}
 	aggInScopeColsBefore := len(aggInScope.cols)
-	for i, pexpr := range f.Exprs {
+	for i, pexpr := range args {
 		b.assertNoAggregationOrWindowing(pexpr, fmt.Sprintf("the argument of %s()", &f.Func))
 
 		// This synthesizes a new aggInScope column, unless the argument is a simple
@@ -19,6 +19,7 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/sql/opt"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/types"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
@@ -30,8 +31,21 @@ import (
 // See Builder.buildStmt for a description of the remaining input and
 // return values.
 func (b *Builder) buildJoin(join *tree.JoinTableExpr, inScope *scope) (outScope *scope) {
+	joinType := sqlbase.JoinTypeFromAstString(join.Join)
 	leftScope := b.buildTable(join.Left, inScope)
-	rightScope := b.buildTable(join.Right, inScope)
+
+	// A LATERAL right side can refer to the columns of the left side.
+	lateral := isLateral(join.Right)
+	var rightScope *scope
+	if lateral {
+		if joinType != sqlbase.InnerJoin && joinType != sqlbase.LeftOuterJoin {
+			panic(builderError{pgerror.NewErrorf(pgerror.CodeInvalidColumnReferenceError,
+				"the combining JOIN type must be INNER or LEFT for a LATERAL reference")})
+		}
+		rightScope = b.buildTable(join.Right, leftScope)
+	} else {
+		rightScope = b.buildTable(join.Right, inScope)
+	}
 
 	// Check that the same table name is not used on both sides.
 	leftTables := make(map[tree.TableName]struct{})
@@ -53,8 +67,6 @@ func (b *Builder) buildJoin(join *tree.JoinTableExpr, inScope *scope) (outScope
 		}
 	}
 
-	joinType := sqlbase.JoinTypeFromAstString(join.Join)
-
 	switch cond := join.Cond.(type) {
 	case tree.NaturalJoinCond, *tree.UsingJoinCond:
 		var usingColNames tree.NameList
@@ -66,7 +78,7 @@ func (b *Builder) buildJoin(join *tree.JoinTableExpr, inScope *scope) (outScope
 			usingColNames = t.Cols
 		}
 
-		return b.buildUsingJoin(joinType, usingColNames, leftScope, rightScope, inScope)
+		return b.buildUsingJoin(joinType, lateral, usingColNames, leftScope, rightScope, inScope)
 
 	case *tree.OnJoinCond, nil:
 		// Append columns added by the children, as they are visible to the filter.
@@ -81,7 +93,7 @@ func (b *Builder) buildJoin(join *tree.JoinTableExpr, inScope *scope) (outScope
 			filter = b.factory.ConstructTrue()
 		}
 
-		outScope.group = b.constructJoin(joinType, leftScope.group, rightScope.group, filter)
+		outScope.group = b.constructJoin(joinType, lateral, leftScope.group, rightScope.group, filter)
 		return outScope
 
 	default:
@@ -89,6 +101,12 @@ func (b *Builder) buildJoin(join *tree.JoinTableExpr, inScope *scope) (outScope
 	}
 }
 
+// isLateral returns true if the given table expression is marked LATERAL.
+func isLateral(texpr tree.TableExpr) bool {
+	t, ok := texpr.(*tree.AliasedTableExpr)
+	return ok && t.Lateral
+}
+
 // commonColumns returns the names of columns common on the
 // left and right sides, for use by NATURAL JOIN.
 func commonColumns(leftScope, rightScope *scope) (common tree.NameList) {
@@ -116,6 +134,7 @@ func commonColumns(leftScope, rightScope *scope) (common tree.NameList) {
 // USING and NATURAL joins.
 //
 // joinType    The join type (inner, left, right or outer)
+// lateral     Whether the right side is LATERAL and may refer to the left side
 // names       The list of `USING` column names
 // leftScope   The outScope from the left table
 // rightScope  The outScope from the right table
@@ -123,14 +142,17 @@ func commonColumns(leftScope, rightScope *scope) (common tree.NameList) {
 // See Builder.buildStmt for a description of the remaining input and
 // return values.
 func (b *Builder) buildUsingJoin(
-	joinType sqlbase.JoinType, names tree.NameList, leftScope, rightScope, inScope *scope,
+	joinType sqlbase.JoinType,
+	lateral bool,
+	names tree.NameList,
+	leftScope, rightScope, inScope *scope,
 ) (outScope *scope) {
 	// Build the join predicate.
 	mergedCols, filter, outScope := b.buildUsingJoinPredicate(
 		joinType, leftScope.cols, rightScope.cols, names, inScope,
 	)
 
-	outScope.group = b.constructJoin(joinType, leftScope.group, rightScope.group, filter)
+	outScope.group = b.constructJoin(joinType, lateral, leftScope.group, rightScope.group, filter)
 
 	if len(mergedCols) > 0 {
 		// Wrap in a projection to include the merged columns.
@@ -323,9 +345,23 @@ func (b *Builder) constructFilter(conditions []memo.GroupID) memo.GroupID {
 	}
 }
 
+// constructJoin constructs a join of the given type. If lateral is true, the
+// right side may refer to the columns of the left side, and an apply join is
+// constructed.
 func (b *Builder) constructJoin(
-	joinType sqlbase.JoinType, left, right, filter memo.GroupID,
+	joinType sqlbase.JoinType, lateral bool, left, right, filter memo.GroupID,
 ) memo.GroupID {
+	if lateral {
+		switch joinType {
+		case sqlbase.InnerJoin:
+			return b.factory.ConstructInnerJoinApply(left, right, filter)
+		case sqlbase.LeftOuterJoin:
+			return b.factory.ConstructLeftJoinApply(left, right, filter)
+		default:
+			panic(fmt.Errorf("unsupported LATERAL JOIN type %d", joinType))
+		}
+	}
+
 	switch joinType {
 	case sqlbase.InnerJoin:
 		return b.factory.ConstructInnerJoin(left, right, filter)
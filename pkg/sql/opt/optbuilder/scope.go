@@ -42,6 +42,10 @@ type scope struct {
 	// group is the memo.GroupID of the relational operator built with this scope.
 	group memo.GroupID
 
+	// ctes contains the common table expressions defined by a WITH clause that
+	// are visible from this scope (and its children).
+	ctes map[tree.Name]*cteSource
+
 	// Desired number of columns for subqueries found during name resolution and
 	// type checking. This only applies to the top-level subqueries that are
 	// anchored directly to a relational expression.
@@ -74,6 +78,22 @@ func (s *scope) replace() *scope {
 	return &scope{builder: s.builder, parent: s.parent}
 }
 
+// resolveCTE looks up the given table name in the CTEs that are visible from
+// this scope, most recent WITH clause first. It returns nil if the name does
+// not refer to a CTE.
+func (s *scope) resolveCTE(tn *tree.TableName) *cteSource {
+	if tn.ExplicitSchema {
+		// If the name was prefixed, it cannot be a CTE.
+		return nil
+	}
+	for ; s != nil; s = s.parent {
+		if cte, ok := s.ctes[tn.TableName]; ok {
+			return cte
+		}
+	}
+	return nil
+}
+
 // appendColumns adds newly bound variables to this scope.
 func (s *scope) appendColumns(src *scope) {
 	s.cols = append(s.cols, src.cols...)
@@ -388,28 +408,33 @@ func (s *scope) FindSourceMatchingName(
 	srcMeta tree.ColumnSourceMeta,
 	err error,
 ) {
-	sources := make(map[tree.TableName]struct{})
-	for _, col := range s.cols {
-		sources[col.table] = exists
-	}
-
-	found := false
-	var source tree.TableName
-	for src := range sources {
-		if !sourceNameMatches(src, tn) {
-			continue
+	// If no source matches in the current scope, we search the parent scope,
+	// so that the sources of an outer query (for example the left side of a
+	// LATERAL join) can be referenced.
+	for ; s != nil; s = s.parent {
+		sources := make(map[tree.TableName]struct{})
+		for _, col := range s.cols {
+			sources[col.table] = exists
+		}
+
+		found := false
+		var source tree.TableName
+		for src := range sources {
+			if !sourceNameMatches(src, tn) {
+				continue
+			}
+			if found {
+				return tree.MoreThanOne, nil, s, newAmbiguousSourceError(&tn)
+			}
+			found = true
+			source = src
 		}
+
 		if found {
-			return tree.MoreThanOne, nil, s, newAmbiguousSourceError(&tn)
+			return tree.ExactlyOne, &source, s, nil
 		}
-		found = true
-		source = src
 	}
-
-	if !found {
-		return tree.NoResults, nil, s, nil
-	}
-	return tree.ExactlyOne, &source, s, nil
+	return tree.NoResults, nil, s, nil
 }
 
 // sourceNameMatches checks whether a request for table name toFind
@@ -451,7 +476,7 @@ func (s *scope) Resolve(
 	// Otherwise, a table is known but not the column yet.
 	inScope := srcMeta.(*scope)
 	for i := range inScope.cols {
-		col := &s.cols[i]
+		col := &inScope.cols[i]
 		if col.name == colName && sourceNameMatches(col.table, *prefix) {
 			return col, nil
 		}
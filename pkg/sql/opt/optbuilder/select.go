@@ -17,6 +17,8 @@ package optbuilder
 import (
 	"github.com/cockroachdb/cockroach/pkg/sql/opt"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/types"
 )
@@ -41,6 +43,9 @@ func (b *Builder) buildTable(texpr tree.TableExpr, inScope *scope) (outScope *sc
 		b.renameSource(source.As, outScope)
 		return outScope
 
+	case *tree.FuncExpr:
+		return b.buildFunctionSource(source, inScope)
+
 	case *tree.JoinTableExpr:
 		return b.buildJoin(source, inScope)
 
@@ -49,6 +54,10 @@ func (b *Builder) buildTable(texpr tree.TableExpr, inScope *scope) (outScope *sc
 		if err != nil {
 			panic(builderError{err})
 		}
+		if cte := inScope.resolveCTE(tn); cte != nil {
+			return b.buildCTEReference(cte, tn, inScope)
+		}
+
 		tab, err := b.catalog.FindTable(b.ctx, tn)
 		if err != nil {
 			panic(builderError{err})
@@ -130,10 +139,68 @@ func (b *Builder) buildScan(tab opt.Table, tn *tree.TableName, inScope *scope) (
 		outScope.cols = append(outScope.cols, colProps)
 	}
 
+	b.buildComputedColumnExprs(tab, outScope)
+
 	outScope.group = b.factory.ConstructScan(b.factory.InternScanOpDef(&scanOpDef))
 	return outScope
 }
 
+// buildComputedColumnExprs builds the expressions of the computed columns of
+// the given table, and records them in the memo (see
+// memo.SetComputedColumnExpr). This allows a filter on an expression to be
+// constrained by an index on a column computing the same expression, such as
+// the hidden column of an expression index.
+func (b *Builder) buildComputedColumnExprs(tab opt.Table, scanScope *scope) {
+	for i := 0; i < tab.ColumnCount(); i++ {
+		col := tab.Column(i)
+		if !col.IsComputed() {
+			continue
+		}
+		expr, err := parser.ParseExpr(col.ComputedExprStr())
+		if err != nil {
+			panic(builderError{err})
+		}
+		texpr := scanScope.resolveType(expr, col.DatumType())
+		group := b.buildScalar(texpr, scanScope)
+		b.factory.Memo().SetComputedColumnExpr(scanScope.cols[i].id, group)
+	}
+}
+
+// buildFunctionSource builds a set of memo groups that represent a function
+// used as a data source in the FROM clause. A set-returning function, such as
+// generate_series(), is built as a Generator operator that produces the rows
+// of the function. Any other function is built as a Values operator with a
+// single row containing the result of the function.
+//
+// See Builder.buildStmt for a description of the remaining input and
+// return values.
+func (b *Builder) buildFunctionSource(f *tree.FuncExpr, inScope *scope) (outScope *scope) {
+	texpr := inScope.resolveType(f, types.Any)
+
+	// Make sure there are no aggregation/window functions in the function
+	// (after subqueries have been expanded).
+	b.assertNoAggregationOrWindowing(texpr, "FROM")
+
+	call := b.buildScalar(texpr, inScope)
+	outScope = inScope.push()
+	if tType, ok := texpr.ResolvedType().(types.TTable); ok {
+		for i := range tType.Cols {
+			b.synthesizeColumn(outScope, tType.Labels[i], tType.Cols[i], nil, 0 /* group */)
+		}
+		outScope.group = b.factory.ConstructGenerator(
+			call, b.factory.InternColList(colsToColList(outScope.cols)),
+		)
+		return outScope
+	}
+
+	b.synthesizeColumn(outScope, f.Func.String(), texpr.ResolvedType(), nil, 0 /* group */)
+	rows := []memo.GroupID{b.factory.ConstructTuple(b.factory.InternList([]memo.GroupID{call}))}
+	outScope.group = b.factory.ConstructValues(
+		b.factory.InternList(rows), b.factory.InternColList(colsToColList(outScope.cols)),
+	)
+	return outScope
+}
+
 // buildSelect builds a set of memo groups that represent the given select
 // statement.
 //
@@ -143,10 +210,18 @@ func (b *Builder) buildSelect(stmt *tree.Select, inScope *scope) (outScope *scop
 	wrapped := stmt.Select
 	orderBy := stmt.OrderBy
 	limit := stmt.Limit
+	locking := stmt.Locking
+
+	if stmt.With != nil {
+		inScope = b.buildCTEs(stmt.With, inScope)
+	}
 
 	for s, ok := wrapped.(*tree.ParenSelect); ok; s, ok = wrapped.(*tree.ParenSelect) {
 		stmt = s.Select
 		wrapped = stmt.Select
+		if stmt.With != nil {
+			inScope = b.buildCTEs(stmt.With, inScope)
+		}
 		if stmt.OrderBy != nil {
 			if orderBy != nil {
 				panic(errorf("multiple ORDER BY clauses not allowed"))
@@ -159,6 +234,14 @@ func (b *Builder) buildSelect(stmt *tree.Select, inScope *scope) (outScope *scop
 			}
 			limit = stmt.Limit
 		}
+		if stmt.Locking != nil {
+			locking = stmt.Locking
+		}
+	}
+
+	if locking != nil {
+		panic(builderError{pgerror.NewErrorf(pgerror.CodeFeatureNotSupportedError,
+			"%s is not supported by the optimizer", locking[0].Strength)})
 	}
 
 	// NB: The case statements are sorted lexicographically.
@@ -234,6 +317,17 @@ func (b *Builder) buildSelectClause(
 // return values.
 func (b *Builder) buildFrom(from *tree.From, where *tree.Where, inScope *scope) (outScope *scope) {
 	for _, table := range from.Tables {
+		if outScope != nil && isLateral(table) {
+			// A LATERAL source can refer to the columns of the sources on its
+			// left.
+			tableScope := b.buildTable(table, outScope)
+			outScope.appendColumns(tableScope)
+			outScope.group = b.factory.ConstructInnerJoinApply(
+				outScope.group, tableScope.group, b.factory.ConstructTrue(),
+			)
+			continue
+		}
+
 		tableScope := b.buildTable(table, inScope)
 
 		if outScope == nil {
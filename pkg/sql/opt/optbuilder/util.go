@@ -40,7 +40,7 @@ func (b *Builder) expandStar(expr tree.Expr, inScope *scope) (exprs []tree.Typed
 			panic(builderError{err})
 		}
 
-		numRes, src, _, err := inScope.FindSourceMatchingName(b.ctx, tn)
+		numRes, src, srcMeta, err := inScope.FindSourceMatchingName(b.ctx, tn)
 		if err != nil {
 			panic(builderError{err})
 		}
@@ -49,8 +49,10 @@ func (b *Builder) expandStar(expr tree.Expr, inScope *scope) (exprs []tree.Typed
 				"no data source named %q", tree.ErrString(&tn))})
 		}
 
-		for i := range inScope.cols {
-			col := inScope.cols[i]
+		// The source may belong to an outer scope.
+		srcScope := srcMeta.(*scope)
+		for i := range srcScope.cols {
+			col := srcScope.cols[i]
 			if col.table == *src && !col.hidden {
 				exprs = append(exprs, &col)
 			}
@@ -0,0 +1,207 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package optbuilder
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/types"
+)
+
+// cteSource is the value part of the common table expressions that are
+// visible from a scope. It holds the memo group and the columns that are
+// produced when the CTE is referenced.
+type cteSource struct {
+	cols  []scopeColumn
+	group memo.GroupID
+
+	// used is set to true once the CTE has been referenced. Since the columns
+	// of the CTE can only be bound once, multiple uses of a CTE are not
+	// supported.
+	used bool
+
+	// recursionErr, if set, is the error reported when the CTE is referenced.
+	// It is used while building a CTE defined in a WITH RECURSIVE clause, at
+	// places where the CTE must not refer to itself.
+	recursionErr error
+}
+
+// buildCTEs builds the common table expressions of the given WITH clause. It
+// returns a new scope in which the CTEs are visible.
+//
+// See Builder.buildStmt for a description of the remaining input and
+// return values.
+func (b *Builder) buildCTEs(with *tree.With, inScope *scope) (outScope *scope) {
+	outScope = inScope.push()
+	outScope.ctes = make(map[tree.Name]*cteSource)
+	for _, cte := range with.CTEList {
+		name := cte.Name.Alias
+		if _, ok := outScope.ctes[name]; ok {
+			panic(builderError{pgerror.NewErrorf(
+				pgerror.CodeDuplicateAliasError,
+				"WITH query name %s specified more than once", name)})
+		}
+
+		var cteScope *scope
+		if with.Recursive {
+			cteScope = b.buildRecursiveCTE(cte, outScope)
+		} else {
+			cteScope = b.buildStmt(cte.Stmt, outScope)
+		}
+		cteScope.removeHiddenCols()
+		if len(cteScope.cols) == 0 {
+			panic(builderError{pgerror.NewErrorf(pgerror.CodeFeatureNotSupportedError,
+				"WITH clause %q does not have a RETURNING clause", tree.ErrString(&name))})
+		}
+		b.renameSource(cte.Name, cteScope)
+
+		outScope.ctes[name] = &cteSource{cols: cteScope.cols, group: cteScope.group}
+	}
+	return outScope
+}
+
+// buildRecursiveCTE builds a CTE defined in a WITH RECURSIVE clause. The CTEs
+// defined before this one in the same clause are visible in inScope.
+//
+// A recursive CTE has the form "initial-term UNION [ALL] recursive-term",
+// where the recursive term refers to the CTE itself. It is built as a
+// RecursiveCTE operator, whose recursive input refers to the rows produced by
+// the previous iteration through a WorkTable operator.
+//
+// See Builder.buildStmt for a description of the remaining input and
+// return values.
+func (b *Builder) buildRecursiveCTE(cte *tree.CTE, inScope *scope) (outScope *scope) {
+	name := cte.Name.Alias
+	defer delete(inScope.ctes, name)
+
+	union, ok := recursiveUnion(cte.Stmt)
+	if !ok {
+		// This is not of the form accepted for recursive CTEs, so it must not
+		// refer to itself.
+		inScope.ctes[name] = &cteSource{recursionErr: pgerror.NewErrorf(
+			pgerror.CodeInvalidRecursionError,
+			"recursive query %q does not have the form non-recursive-term UNION [ALL] recursive-term",
+			tree.ErrString(&name))}
+		return b.buildStmt(cte.Stmt, inScope)
+	}
+
+	inScope.ctes[name] = &cteSource{recursionErr: pgerror.NewErrorf(
+		pgerror.CodeInvalidRecursionError,
+		"recursive reference to query %q must not appear within its non-recursive term",
+		tree.ErrString(&name))}
+	initialScope := b.buildSelect(union.Left, inScope)
+	initialScope.removeHiddenCols()
+	b.renameSource(cte.Name, initialScope)
+
+	// Synthesize the columns of the working table, with the same names and
+	// types as the columns of the initial term.
+	b.workTableCount++
+	workTableID := memo.WorkTableID(b.workTableCount)
+	workScope := inScope.push()
+	for i := range initialScope.cols {
+		col := &initialScope.cols[i]
+		b.synthesizeColumn(workScope, string(col.name), col.typ, nil, 0 /* group */)
+	}
+	workTableDef := memo.WorkTableDef{
+		Name: string(name),
+		ID:   workTableID,
+		Cols: colsToColList(workScope.cols),
+	}
+	workScope.group = b.factory.ConstructWorkTable(b.factory.InternWorkTableDef(&workTableDef))
+	for i := range workScope.cols {
+		workScope.cols[i].table.TableName = name
+	}
+	inScope.ctes[name] = &cteSource{cols: workScope.cols, group: workScope.group}
+
+	recursiveScope := b.buildSelect(union.Right, inScope)
+	recursiveScope.removeHiddenCols()
+	if !inScope.ctes[name].used {
+		// The CTE does not actually refer to itself: build it as a regular
+		// UNION.
+		delete(inScope.ctes, name)
+		return b.buildUnion(union, inScope)
+	}
+
+	if len(initialScope.cols) != len(recursiveScope.cols) {
+		panic(builderError{pgerror.NewErrorf(pgerror.CodeSyntaxError,
+			"each UNION query must have the same number of columns: %d vs %d",
+			len(initialScope.cols), len(recursiveScope.cols))})
+	}
+
+	// Synthesize the output columns.
+	outScope = inScope.push()
+	for i := range initialScope.cols {
+		l := &initialScope.cols[i]
+		r := &recursiveScope.cols[i]
+		if !l.typ.Equivalent(r.typ) && r.typ != types.Unknown {
+			panic(builderError{pgerror.NewErrorf(pgerror.CodeDatatypeMismatchError,
+				"recursive query %q column %d has type %s in non-recursive term but type %s overall",
+				tree.ErrString(&name), i+1, l.typ, r.typ)})
+		}
+		b.synthesizeColumn(outScope, string(l.name), l.typ, nil, 0 /* group */)
+	}
+
+	def := memo.RecursiveCTEDef{
+		Name:      string(name),
+		WorkTable: workTableID,
+		UnionAll:  union.All,
+		Initial:   colsToColList(initialScope.cols),
+		Recursive: colsToColList(recursiveScope.cols),
+		Out:       colsToColList(outScope.cols),
+	}
+	outScope.group = b.factory.ConstructRecursiveCTE(
+		initialScope.group, recursiveScope.group, b.factory.InternRecursiveCTEDef(&def),
+	)
+	return outScope
+}
+
+// buildCTEReference builds a reference to the given CTE.
+//
+// See Builder.buildStmt for a description of the remaining input and
+// return values.
+func (b *Builder) buildCTEReference(
+	cte *cteSource, tn *tree.TableName, inScope *scope,
+) (outScope *scope) {
+	if cte.recursionErr != nil {
+		panic(builderError{cte.recursionErr})
+	}
+	if cte.used {
+		// TODO(jordan): figure out how to lift this restriction.
+		panic(builderError{pgerror.NewErrorf(pgerror.CodeFeatureNotSupportedError,
+			"unsupported multiple use of CTE clause %q", tree.ErrString(tn))})
+	}
+	cte.used = true
+
+	outScope = inScope.push()
+	outScope.cols = append(outScope.cols, cte.cols...)
+	outScope.group = cte.group
+	return outScope
+}
+
+// recursiveUnion returns the UNION clause of the statement defining a
+// recursive CTE, if the statement has the required form
+// "non-recursive-term UNION [ALL] recursive-term".
+func recursiveUnion(stmt tree.Statement) (*tree.UnionClause, bool) {
+	sel, ok := stmt.(*tree.Select)
+	if !ok || sel.With != nil || sel.OrderBy != nil || sel.Limit != nil {
+		return nil, false
+	}
+	union, ok := sel.Select.(*tree.UnionClause)
+	if !ok || union.Type != tree.UnionOp {
+		return nil, false
+	}
+	return union, true
+}
@@ -58,6 +58,10 @@ func mapPrivateType(typ string) string {
 		return "*memo.ScanOpDef"
 	case "SetOpColMap":
 		return "*memo.SetOpColMap"
+	case "RecursiveCTEDef":
+		return "*memo.RecursiveCTEDef"
+	case "WorkTableDef":
+		return "*memo.WorkTableDef"
 	case "Datum":
 		return "tree.Datum"
 	case "Type":
@@ -81,6 +81,9 @@ const (
 	// ------------------------------------------------------------
 	// Explore Rule Names
 	// ------------------------------------------------------------
+	CommuteJoin
+	AssociateJoin
+	GenerateGreedyJoinOrder
 	PushLimitIntoScan
 	GenerateIndexScans
 	ConstrainScan
@@ -0,0 +1,129 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package testutils
+
+import (
+	gojson "encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/coltypes"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/types"
+	"github.com/cockroachdb/cockroach/pkg/sql/stats"
+)
+
+// AlterTable is a partial implementation of the ALTER TABLE statement. The
+// only supported command is INJECT STATISTICS, which replaces the statistics
+// of the test table with the given statistics (in the same format as the
+// result of SHOW STATISTICS USING JSON).
+func (tc *TestCatalog) AlterTable(stmt *tree.AlterTable) {
+	tn, err := stmt.Table.Normalize()
+	if err != nil {
+		panic(fmt.Errorf("%s", err))
+	}
+
+	tab := tc.Table(tn.Table())
+	if tab == nil {
+		panic(fmt.Errorf("table %q not found", tn.Table()))
+	}
+
+	for _, cmd := range stmt.Cmds {
+		switch cmd := cmd.(type) {
+		case *tree.AlterTableInjectStats:
+			tab.injectStats(cmd)
+
+		default:
+			panic(fmt.Errorf("unsupported ALTER TABLE command: %T", cmd))
+		}
+	}
+}
+
+// injectStats sets the table statistics as specified by the given INJECT
+// STATISTICS command.
+func (tt *TestTable) injectStats(cmd *tree.AlterTableInjectStats) {
+	semaCtx := tree.MakeSemaContext(false /* privileged */)
+	evalCtx := tree.MakeTestingEvalContext(cluster.MakeTestingClusterSettings())
+	typedExpr, err := tree.TypeCheckAndRequire(cmd.Stats, &semaCtx, types.JSON, "INJECT STATISTICS")
+	if err != nil {
+		panic(err)
+	}
+	val, err := typedExpr.Eval(&evalCtx)
+	if err != nil {
+		panic(err)
+	}
+
+	var jsonStats []stats.JSONStatistic
+	if err := gojson.Unmarshal([]byte(val.(*tree.DJSON).JSON.String()), &jsonStats); err != nil {
+		panic(err)
+	}
+
+	tt.Stats = make([]*TestTableStat, len(jsonStats))
+	for i := range jsonStats {
+		tt.Stats[i] = tt.makeStat(&evalCtx, &jsonStats[i])
+	}
+
+	// Order the statistics from most recently to least recently created, as
+	// required by the opt.Table interface.
+	sort.SliceStable(tt.Stats, func(i, j int) bool {
+		return tt.Stats[i].createdAt.After(tt.Stats[j].createdAt)
+	})
+}
+
+// makeStat converts the given JSON statistic to a test statistic.
+func (tt *TestTable) makeStat(evalCtx *tree.EvalContext, js *stats.JSONStatistic) *TestTableStat {
+	createdAt, err := tree.ParseDTimestamp(js.CreatedAt, time.Microsecond)
+	if err != nil {
+		panic(err)
+	}
+
+	ts := &TestTableStat{
+		createdAt:     createdAt.Time,
+		rowCount:      js.RowCount,
+		distinctCount: js.DistinctCount,
+		nullCount:     js.NullCount,
+	}
+	for _, colName := range js.Columns {
+		ts.columnOrdinals = append(ts.columnOrdinals, tt.FindOrdinal(colName))
+	}
+
+	if len(js.HistogramBuckets) != 0 {
+		colType, err := parser.ParseType(js.HistogramColumnType)
+		if err != nil {
+			panic(err)
+		}
+		datumType := coltypes.CastTargetToDatumType(colType)
+		var collationEnv tree.CollationEnvironment
+		ts.histogram = make([]opt.HistogramBucket, len(js.HistogramBuckets))
+		for i := range js.HistogramBuckets {
+			b := &js.HistogramBuckets[i]
+			upperBound, err := parser.ParseStringAs(datumType, b.UpperBound, evalCtx, &collationEnv)
+			if err != nil {
+				panic(err)
+			}
+			ts.histogram[i] = opt.HistogramBucket{
+				NumEq:      uint64(b.NumEq),
+				NumRange:   uint64(b.NumRange),
+				UpperBound: upperBound,
+			}
+		}
+	}
+
+	return ts
+}
@@ -87,6 +87,9 @@ func (tt *TestTable) addColumn(def *tree.ColumnTableDef) {
 	nullable := !def.PrimaryKey && def.Nullable.Nullability != tree.NotNull
 	typ := coltypes.CastTargetToDatumType(def.Type)
 	col := &TestColumn{Name: string(def.Name), Type: typ, Nullable: nullable}
+	if def.IsComputed() {
+		col.ComputedExpr = tree.Serialize(def.Computed.Expr)
+	}
 	tt.Columns = append(tt.Columns, col)
 
 	if def.PrimaryKey {
@@ -69,6 +69,11 @@ type OptTesterFlags struct {
 	// an UnsupportedExpr node. This is temporary; it is used for interfacing with
 	// the old planning code.
 	AllowUnsupportedExpr bool
+
+	// LookupJoinEnabled if set: the optimizer costs joins that can be run as
+	// lookup joins accordingly, as it does when the
+	// experimental_force_lookup_join session variable is set.
+	LookupJoinEnabled bool
 }
 
 // NewOptTester constructs a new instance of the OptTester for the given SQL
@@ -120,6 +125,9 @@ func NewOptTester(catalog opt.Catalog, sql string) *OptTester {
 //
 //  - allow-unsupported: wrap unsupported expressions in UnsupportedOp.
 //
+//  - lookup-join: enable lookup joins, as if experimental_force_lookup_join
+//    was set for the session.
+//
 func (e *OptTester) RunCommand(tb testing.TB, d *datadriven.TestData) string {
 	// Allow testcases to override the flags.
 	for _, a := range d.CmdArgs {
@@ -127,6 +135,7 @@ func (e *OptTester) RunCommand(tb testing.TB, d *datadriven.TestData) string {
 			d.Fatalf(tb, "%s", err)
 		}
 	}
+	e.evalCtx.SessionData.LookupJoinEnabled = e.Flags.LookupJoinEnabled
 
 	switch d.Cmd {
 	case "exec-ddl":
@@ -197,6 +206,9 @@ func (f *OptTesterFlags) Set(arg datadriven.CmdArg) error {
 	case "allow-unsupported":
 		f.AllowUnsupportedExpr = true
 
+	case "lookup-join":
+		f.LookupJoinEnabled = true
+
 	default:
 		return fmt.Errorf("unknown argument: %s", arg.Key)
 	}
@@ -17,6 +17,7 @@ package testutils
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/sql/opt"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
@@ -59,6 +60,7 @@ type TestTable struct {
 	Name    string
 	Columns []*TestColumn
 	Indexes []*TestIndex
+	Stats   []*TestTableStat
 }
 
 var _ opt.Table = &TestTable{}
@@ -94,6 +96,16 @@ func (tt *TestTable) Index(i int) opt.Index {
 	return tt.Indexes[i]
 }
 
+// StatisticCount is part of the opt.Table interface.
+func (tt *TestTable) StatisticCount() int {
+	return len(tt.Stats)
+}
+
+// Statistic is part of the opt.Table interface.
+func (tt *TestTable) Statistic(i int) opt.TableStatistic {
+	return tt.Stats[i]
+}
+
 // FindOrdinal returns the ordinal of the column with the given name.
 func (tt *TestTable) FindOrdinal(name string) int {
 	for i, col := range tt.Columns {
@@ -140,10 +152,11 @@ func (ti *TestIndex) Column(i int) opt.IndexColumn {
 
 // TestColumn implements the opt.Column interface for testing purposes.
 type TestColumn struct {
-	Hidden   bool
-	Nullable bool
-	Name     string
-	Type     types.T
+	Hidden       bool
+	Nullable     bool
+	Name         string
+	Type         types.T
+	ComputedExpr string
 }
 
 var _ opt.Column = &TestColumn{}
@@ -167,3 +180,61 @@ func (tc *TestColumn) DatumType() types.T {
 func (tc *TestColumn) IsHidden() bool {
 	return tc.Hidden
 }
+
+// IsComputed is part of the opt.Column interface.
+func (tc *TestColumn) IsComputed() bool {
+	return tc.ComputedExpr != ""
+}
+
+// ComputedExprStr is part of the opt.Column interface.
+func (tc *TestColumn) ComputedExprStr() string {
+	return tc.ComputedExpr
+}
+
+// TestTableStat implements the opt.TableStatistic interface for testing
+// purposes.
+type TestTableStat struct {
+	createdAt      time.Time
+	columnOrdinals []int
+	rowCount       uint64
+	distinctCount  uint64
+	nullCount      uint64
+	histogram      []opt.HistogramBucket
+}
+
+var _ opt.TableStatistic = &TestTableStat{}
+
+// CreatedAt is part of the opt.TableStatistic interface.
+func (ts *TestTableStat) CreatedAt() time.Time {
+	return ts.createdAt
+}
+
+// ColumnCount is part of the opt.TableStatistic interface.
+func (ts *TestTableStat) ColumnCount() int {
+	return len(ts.columnOrdinals)
+}
+
+// ColumnOrdinal is part of the opt.TableStatistic interface.
+func (ts *TestTableStat) ColumnOrdinal(i int) int {
+	return ts.columnOrdinals[i]
+}
+
+// RowCount is part of the opt.TableStatistic interface.
+func (ts *TestTableStat) RowCount() uint64 {
+	return ts.rowCount
+}
+
+// DistinctCount is part of the opt.TableStatistic interface.
+func (ts *TestTableStat) DistinctCount() uint64 {
+	return ts.distinctCount
+}
+
+// NullCount is part of the opt.TableStatistic interface.
+func (ts *TestTableStat) NullCount() uint64 {
+	return ts.nullCount
+}
+
+// Histogram is part of the opt.TableStatistic interface.
+func (ts *TestTableStat) Histogram() []opt.HistogramBucket {
+	return ts.histogram
+}
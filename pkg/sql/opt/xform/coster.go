@@ -17,6 +17,7 @@ package xform
 import (
 	"github.com/cockroachdb/cockroach/pkg/sql/opt"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 )
 
 // coster encapsulates the cost model for the optimizer. The coster assigns an
@@ -25,17 +26,20 @@ import (
 // approximation of the actual cost of execution, based on table and index
 // statistics that are propagated throughout the logical expression tree.
 type coster struct {
-	mem *memo.Memo
+	mem     *memo.Memo
+	evalCtx *tree.EvalContext
 }
 
-func (c *coster) init(mem *memo.Memo) {
+func (c *coster) init(mem *memo.Memo, evalCtx *tree.EvalContext) {
 	c.mem = mem
+	c.evalCtx = evalCtx
 }
 
 // computeCost calculates the estimated cost of the candidate best expression,
 // based on its logical properties as well as the cost of its children. Each
 // expression's cost must always be >= the total costs of its children, so that
-// branch-and-bound pruning will work properly.
+// branch-and-bound pruning will work properly. The only exception is the
+// lookup join, which never executes its right input (see computeJoinCost).
 //
 // TODO: This is just a skeleton, and needs to compute real costs.
 func (c *coster) computeCost(candidate *memo.BestExpr, props *memo.LogicalProps) {
@@ -53,6 +57,10 @@ func (c *coster) computeCost(candidate *memo.BestExpr, props *memo.LogicalProps)
 	case opt.ValuesOp:
 		cost = c.computeValuesCost(candidate, props)
 
+	case opt.InnerJoinOp, opt.LeftJoinOp, opt.RightJoinOp, opt.FullJoinOp,
+		opt.SemiJoinOp, opt.AntiJoinOp:
+		cost = c.computeJoinCost(candidate, props)
+
 	default:
 		// By default, cost of parent is sum of child costs.
 		cost = c.computeChildrenCost(candidate)
@@ -81,6 +89,187 @@ func (c *coster) computeValuesCost(candidate *memo.BestExpr, props *memo.Logical
 	return memo.Cost(props.Relational.Stats.RowCount)
 }
 
+// joinAlgorithm is the algorithm used by the execution engine to run a join.
+type joinAlgorithm int
+
+const (
+	// hashJoin builds a hash table on the equality columns of the smaller input,
+	// and probes it with the rows of the larger input. A join with no equality
+	// columns is run as a hash join where every row has the same hash, so every
+	// pair of input rows has to be compared.
+	hashJoin joinAlgorithm = iota
+
+	// mergeJoin merges the rows of two inputs that are both ordered on their
+	// equality columns.
+	mergeJoin
+
+	// lookupJoin looks up the rows of the right input in the primary index of
+	// its table, using the equality column values of each left row. The right
+	// input is never scanned.
+	lookupJoin
+)
+
+// computeJoinCost determines the algorithm the execution engine will use to
+// run the join (see joinAlgorithm), and costs the join accordingly.
+func (c *coster) computeJoinCost(candidate *memo.BestExpr, props *memo.LogicalProps) memo.Cost {
+	leftRowCount := memo.Cost(c.mem.BestExprLogical(candidate.Child(0)).Relational.Stats.RowCount)
+	rightRowCount := memo.Cost(c.mem.BestExprLogical(candidate.Child(1)).Relational.Stats.RowCount)
+
+	leftEq, rightEq := c.joinEqualityCols(candidate)
+	if len(leftEq) == 0 {
+		// Every pair of input rows has to be compared.
+		cost := leftRowCount * rightRowCount * 0.1
+		return cost + c.computeChildrenCost(candidate)
+	}
+
+	switch c.joinAlgorithm(candidate, leftEq, rightEq) {
+	case mergeJoin:
+		// Each input row is compared with the current row of the other input.
+		cost := (leftRowCount + rightRowCount) * 0.1
+		return cost + c.computeChildrenCost(candidate)
+
+	case lookupJoin:
+		// Each left row requires a lookup into the right table, which is more
+		// expensive than reading the next row of a scan. The right input is not
+		// executed, so its cost is not included.
+		cost := leftRowCount * 2
+		return cost + c.mem.BestExprCost(candidate.Child(0)) + c.mem.BestExprCost(candidate.Child(2))
+
+	default:
+		// Inserting a row into the hash table is more expensive than probing it
+		// with a row. The hash table is built on the smaller input.
+		buildRowCount, probeRowCount := leftRowCount, rightRowCount
+		if rightRowCount < leftRowCount {
+			buildRowCount, probeRowCount = rightRowCount, leftRowCount
+		}
+		cost := buildRowCount*0.25 + probeRowCount*0.1
+		return cost + c.computeChildrenCost(candidate)
+	}
+}
+
+// joinEqualityCols returns the pairs of left and right columns that are
+// constrained to be equal by the conditions of the given join. Only conditions
+// of the form left.col = right.col are used as equality columns by the
+// execution engine.
+func (c *coster) joinEqualityCols(candidate *memo.BestExpr) (leftEq, rightEq []opt.ColumnID) {
+	leftCols := c.mem.BestExprLogical(candidate.Child(0)).Relational.OutputCols
+	rightCols := c.mem.BestExprLogical(candidate.Child(1)).Relational.OutputCols
+
+	on := memo.MakeExprView(c.mem, candidate.Child(2))
+	conditions := []memo.ExprView{on}
+	if on.Operator() == opt.FiltersOp {
+		conditions = conditions[:0]
+		for i := 0; i < on.ChildCount(); i++ {
+			conditions = append(conditions, on.Child(i))
+		}
+	}
+
+	for _, condition := range conditions {
+		if condition.Operator() != opt.EqOp {
+			continue
+		}
+		left, right := condition.Child(0), condition.Child(1)
+		if left.Operator() != opt.VariableOp || right.Operator() != opt.VariableOp {
+			continue
+		}
+		leftCol, rightCol := left.Private().(opt.ColumnID), right.Private().(opt.ColumnID)
+		if rightCols.Contains(int(leftCol)) && leftCols.Contains(int(rightCol)) {
+			leftCol, rightCol = rightCol, leftCol
+		}
+		if leftCols.Contains(int(leftCol)) && rightCols.Contains(int(rightCol)) {
+			leftEq = append(leftEq, leftCol)
+			rightEq = append(rightEq, rightCol)
+		}
+	}
+	return leftEq, rightEq
+}
+
+// joinAlgorithm returns the algorithm that the execution engine will use to
+// run the given join, which has the given equality columns. The rules mirror
+// the ones used by the DistSQL physical planner:
+//
+//  - a lookup join is used for an inner join whose right input is a scan of
+//    the primary index, if the right equality columns are a prefix of the index
+//    columns, and if lookup joins are enabled for the session.
+//
+//  - a merge join is used if both inputs are ordered on the equality columns.
+//    The inputs are ordered if their rows are produced by a scan of an index
+//    with the equality columns as its first columns.
+//
+//  - a hash join is used otherwise.
+//
+func (c *coster) joinAlgorithm(
+	candidate *memo.BestExpr, leftEq, rightEq []opt.ColumnID,
+) joinAlgorithm {
+	md := c.mem.Metadata()
+	right := memo.MakeExprView(c.mem, candidate.Child(1))
+
+	if candidate.Operator() == opt.InnerJoinOp && right.Operator() == opt.ScanOp &&
+		c.evalCtx.SessionData != nil && c.evalCtx.SessionData.LookupJoinEnabled {
+		def := right.Private().(*memo.ScanOpDef)
+		index := md.Table(def.Table).Index(def.Index)
+		if def.Index == opt.PrimaryIndex && len(rightEq) <= index.UniqueColumnCount() {
+			var eqCols, prefixCols opt.ColSet
+			for i := range rightEq {
+				eqCols.Add(int(rightEq[i]))
+				prefixCols.Add(int(md.TableColumn(def.Table, index.Column(i).Ordinal)))
+			}
+			if eqCols.Equals(prefixCols) {
+				return lookupJoin
+			}
+		}
+	}
+
+	// Build the ordering of the left input on the equality columns, following
+	// the order of the index columns, and the corresponding ordering of the
+	// right input.
+	leftDef := c.scanInput(memo.MakeExprView(c.mem, candidate.Child(0)))
+	rightDef := c.scanInput(right)
+	if leftDef == nil || rightDef == nil {
+		return hashJoin
+	}
+	index := md.Table(leftDef.Table).Index(leftDef.Index)
+	if len(leftEq) > index.UniqueColumnCount() {
+		return hashJoin
+	}
+	rightOrdering := make(memo.Ordering, len(leftEq))
+	for i := range rightOrdering {
+		indexCol := index.Column(i)
+		colID := md.TableColumn(leftDef.Table, indexCol.Ordinal)
+		j := 0
+		for j < len(leftEq) && leftEq[j] != colID {
+			j++
+		}
+		if j == len(leftEq) {
+			return hashJoin
+		}
+		rightOrdering[i] = opt.MakeOrderingColumn(rightEq[j], indexCol.Descending)
+	}
+	if !rightDef.CanProvideOrdering(md, rightOrdering) {
+		return hashJoin
+	}
+	return mergeJoin
+}
+
+// scanInput returns the definition of the Scan operator that produces the
+// rows of the given expression, if the expression is a Scan, possibly filtered
+// or projected. Such an expression returns its rows in the order of the
+// scanned index. scanInput returns nil for any other expression.
+func (c *coster) scanInput(ev memo.ExprView) *memo.ScanOpDef {
+	for {
+		switch ev.Operator() {
+		case opt.ScanOp:
+			return ev.Private().(*memo.ScanOpDef)
+
+		case opt.SelectOp, opt.ProjectOp:
+			ev = ev.Child(0)
+
+		default:
+			return nil
+		}
+	}
+}
+
 func (c *coster) computeChildrenCost(candidate *memo.BestExpr) memo.Cost {
 	var cost memo.Cost
 	for i := 0; i < candidate.ChildCount(); i++ {
@@ -75,10 +75,10 @@ import (
 //    (InnerJoin
 //      $r
 //      $t
-//      (Filters (ConstructConditionsNotUsing $s $lowerOn $upperOn))
+//      (ConstructFiltersNotUsing $s $lowerOn $upperOn)
 //    )
 //    $s
-//    (Filters (ConstructConditionsUsing $s $lowerOn $upperOn))
+//    (ConstructFiltersUsing $s $lowerOn $upperOn)
 //  )
 //
 // In this example, if the upper and lower groups each contain two InnerJoin
@@ -347,6 +347,223 @@ func (e *explorer) limitScanDef(def memo.PrivateID, limit memo.PrivateID) memo.P
 	return e.mem.InternScanOpDef(&defCopy)
 }
 
+// ----------------------------------------------------------------------
+//
+// Join Rules
+//   Custom match and replace functions used with join.opt rules.
+//
+// ----------------------------------------------------------------------
+
+// joinReorderLimit is the maximum number of joins in a tree of inner joins
+// that the CommuteJoin and AssociateJoin rules reorder exhaustively. The number
+// of join orders grows exponentially with the number of joins, so larger trees
+// are reordered by the GenerateGreedyJoinOrder rule instead.
+const joinReorderLimit = 8
+
+// canAssociateJoin returns true if the join tree ((r JOIN s) JOIN t) can be
+// reordered to ((r JOIN t) JOIN s). This is the case if the tree has no more
+// than joinReorderLimit joins, and if at least one of the upper join conditions
+// connects r and t without referencing s. The second restriction prevents the
+// rule from introducing a cross product.
+func (e *explorer) canAssociateJoin(r, s, t, innerOn, on memo.GroupID) bool {
+	if 2+e.numInnerJoins(r)+e.numInnerJoins(s)+e.numInnerJoins(t) > joinReorderLimit {
+		return false
+	}
+
+	rCols := e.mem.GroupProperties(r).Relational.OutputCols
+	sCols := e.mem.GroupProperties(s).Relational.OutputCols
+	tCols := e.mem.GroupProperties(t).Relational.OutputCols
+	for _, condition := range e.joinConditions(on) {
+		outerCols := e.mem.GroupProperties(condition).OuterCols()
+		if outerCols.Intersects(rCols) && outerCols.Intersects(tCols) && !outerCols.Intersects(sCols) {
+			return true
+		}
+	}
+	return false
+}
+
+// constructFiltersUsing constructs a Filters operator containing the conditions
+// of the two given join filters that reference columns of the given group.
+func (e *explorer) constructFiltersUsing(group, on1, on2 memo.GroupID) memo.GroupID {
+	return e.constructJoinFilters(on1, on2, e.mem.GroupProperties(group).Relational.OutputCols, true)
+}
+
+// constructFiltersNotUsing is the inverse of constructFiltersUsing. It
+// constructs a Filters operator containing the conditions of the two given join
+// filters that do *not* reference columns of the given group.
+func (e *explorer) constructFiltersNotUsing(group, on1, on2 memo.GroupID) memo.GroupID {
+	return e.constructJoinFilters(on1, on2, e.mem.GroupProperties(group).Relational.OutputCols, false)
+}
+
+// constructJoinFilters constructs a Filters operator containing the conditions
+// of the two given join filters that reference at least one of the given
+// columns (if using is true), or none of them (if using is false).
+func (e *explorer) constructJoinFilters(
+	on1, on2 memo.GroupID, cols opt.ColSet, using bool,
+) memo.GroupID {
+	var conditions []memo.GroupID
+	for _, on := range [...]memo.GroupID{on1, on2} {
+		for _, condition := range e.joinConditions(on) {
+			if e.mem.GroupProperties(condition).OuterCols().Intersects(cols) == using {
+				conditions = append(conditions, condition)
+			}
+		}
+	}
+	return e.f.ConstructFilters(e.f.InternList(conditions))
+}
+
+// shouldReorderJoinsGreedily returns true if the tree of inner joins made up of
+// a join of the given inputs has more than joinReorderLimit joins, and is
+// therefore too large to be reordered by the CommuteJoin and AssociateJoin
+// rules.
+func (e *explorer) shouldReorderJoinsGreedily(left, right memo.GroupID) bool {
+	return 1+e.numInnerJoins(left)+e.numInnerJoins(right) > joinReorderLimit
+}
+
+// generateGreedyJoinOrder flattens the tree of inner joins made up of a join
+// of the given inputs into its inputs and conditions, and then builds a
+// left-deep join tree from them. It starts with the input that has the fewest
+// rows. At each step, it joins the input that results in the fewest rows,
+// preferring inputs that are connected to the inputs joined so far by a join
+// condition. Each condition is evaluated by the first join that has all the
+// columns it references.
+func (e *explorer) generateGreedyJoinOrder(left, right, on memo.GroupID) []memo.Expr {
+	e.exprs = e.exprs[:0]
+
+	var inputs, conditions []memo.GroupID
+	inputs, conditions = e.flattenInnerJoins(left, inputs, conditions)
+	inputs, conditions = e.flattenInnerJoins(right, inputs, conditions)
+	conditions = append(conditions, e.joinConditions(on)...)
+
+	// Conditions can reference outer columns in addition to the columns of the
+	// inputs. Outer columns are available to every join.
+	var treeCols opt.ColSet
+	for _, input := range inputs {
+		treeCols.UnionWith(e.mem.GroupProperties(input).Relational.OutputCols)
+	}
+	canEvaluate := func(condition memo.GroupID, cols opt.ColSet) bool {
+		outerCols := e.mem.GroupProperties(condition).OuterCols()
+		return outerCols.Intersection(treeCols).SubsetOf(cols)
+	}
+
+	// Start with the input that has the fewest rows.
+	first := 0
+	for i := range inputs {
+		if e.rowCount(inputs[i]) < e.rowCount(inputs[first]) {
+			first = i
+		}
+	}
+	joined := inputs[first]
+	inputs = append(inputs[:first], inputs[first+1:]...)
+
+	// applied records the conditions that are evaluated by the joins built so
+	// far.
+	applied := make([]bool, len(conditions))
+	var joinConditions []memo.GroupID
+	for len(inputs) > 1 {
+		joinedCols := e.mem.GroupProperties(joined).Relational.OutputCols
+
+		best := -1
+		var bestJoin memo.GroupID
+		var bestConnected bool
+		for i, input := range inputs {
+			inputCols := e.mem.GroupProperties(input).Relational.OutputCols
+			cols := joinedCols.Union(inputCols)
+
+			joinConditions = joinConditions[:0]
+			connected := false
+			for j, condition := range conditions {
+				if applied[j] || !canEvaluate(condition, cols) {
+					continue
+				}
+				joinConditions = append(joinConditions, condition)
+				outerCols := e.mem.GroupProperties(condition).OuterCols()
+				if outerCols.Intersects(joinedCols) && outerCols.Intersects(inputCols) {
+					connected = true
+				}
+			}
+			if bestConnected && !connected {
+				continue
+			}
+
+			join := e.f.ConstructInnerJoin(
+				joined, input, e.f.ConstructFilters(e.f.InternList(joinConditions)),
+			)
+			if best == -1 || connected != bestConnected || e.rowCount(join) < e.rowCount(bestJoin) {
+				best, bestJoin, bestConnected = i, join, connected
+			}
+		}
+
+		cols := joinedCols.Union(e.mem.GroupProperties(inputs[best]).Relational.OutputCols)
+		for j, condition := range conditions {
+			if !applied[j] && canEvaluate(condition, cols) {
+				applied[j] = true
+			}
+		}
+		joined = bestJoin
+		inputs = append(inputs[:best], inputs[best+1:]...)
+	}
+
+	// The last join evaluates all the remaining conditions.
+	joinConditions = joinConditions[:0]
+	for j, condition := range conditions {
+		if !applied[j] {
+			joinConditions = append(joinConditions, condition)
+		}
+	}
+	join := memo.MakeInnerJoinExpr(
+		joined, inputs[0], e.f.ConstructFilters(e.f.InternList(joinConditions)),
+	)
+	e.exprs = append(e.exprs, memo.Expr(join))
+	return e.exprs
+}
+
+// flattenInnerJoins appends the inputs and the join conditions of the tree of
+// inner joins rooted at the given group to the given slices, and returns the
+// resulting slices. The tree is traversed through the normalized expression of
+// each group.
+func (e *explorer) flattenInnerJoins(
+	group memo.GroupID, inputs, conditions []memo.GroupID,
+) (_, _ []memo.GroupID) {
+	join := e.mem.NormExpr(group).AsInnerJoin()
+	if join == nil {
+		return append(inputs, group), conditions
+	}
+	inputs, conditions = e.flattenInnerJoins(join.Left(), inputs, conditions)
+	inputs, conditions = e.flattenInnerJoins(join.Right(), inputs, conditions)
+	return inputs, append(conditions, e.joinConditions(join.On())...)
+}
+
+// numInnerJoins returns the number of joins in the tree of inner joins rooted
+// at the given group. The tree is traversed through the normalized expression
+// of each group, so the result does not depend on the join order.
+func (e *explorer) numInnerJoins(group memo.GroupID) int {
+	join := e.mem.NormExpr(group).AsInnerJoin()
+	if join == nil {
+		return 0
+	}
+	return 1 + e.numInnerJoins(join.Left()) + e.numInnerJoins(join.Right())
+}
+
+// joinConditions returns the conditions of the given join filter. The filter
+// is a Filters operator, a True operator (no conditions), or any other single
+// boolean condition. The returned slice must not be modified.
+func (e *explorer) joinConditions(on memo.GroupID) []memo.GroupID {
+	expr := e.mem.NormExpr(on)
+	switch expr.Operator() {
+	case opt.FiltersOp:
+		return e.mem.LookupList(expr.AsFilters().Conditions())
+	case opt.TrueOp:
+		return nil
+	}
+	return []memo.GroupID{on}
+}
+
+// rowCount returns the estimated number of rows returned by the given group.
+func (e *explorer) rowCount(group memo.GroupID) uint64 {
+	return e.mem.GroupProperties(group).Relational.Stats.RowCount
+}
+
 // ----------------------------------------------------------------------
 //
 // Exploration state
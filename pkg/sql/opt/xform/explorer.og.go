@@ -14,6 +14,8 @@ func (_e *explorer) exploreExpr(_state *exploreState, _eid memo.ExprID) (_fullyE
 		return _e.exploreScan(_state, _eid)
 	case opt.SelectOp:
 		return _e.exploreSelect(_state, _eid)
+	case opt.InnerJoinOp:
+		return _e.exploreInnerJoin(_state, _eid)
 	case opt.LimitOp:
 		return _e.exploreLimit(_state, _eid)
 	}
@@ -80,6 +82,85 @@ func (_e *explorer) exploreSelect(_rootState *exploreState, _root memo.ExprID) (
 	return _fullyExplored
 }
 
+func (_e *explorer) exploreInnerJoin(_rootState *exploreState, _root memo.ExprID) (_fullyExplored bool) {
+	_rootExpr := _e.mem.Expr(_root).AsInnerJoin()
+	_fullyExplored = true
+
+	// [CommuteJoin]
+	{
+		if _root.Expr >= _rootState.start {
+			left := _rootExpr.Left()
+			right := _rootExpr.Right()
+			on := _rootExpr.On()
+			if _e.o.onRuleMatch == nil || _e.o.onRuleMatch(opt.CommuteJoin) {
+				_expr := memo.MakeInnerJoinExpr(
+					right,
+					left,
+					on,
+				)
+				_e.mem.MemoizeDenormExpr(_root.Group, memo.Expr(_expr))
+			}
+		}
+	}
+
+	// [AssociateJoin]
+	{
+		_partlyExplored := _root.Expr < _rootState.start
+		_state := _e.exploreGroup(_rootExpr.Left())
+		if !_state.fullyExplored {
+			_fullyExplored = false
+		}
+		start := memo.ExprOrdinal(0)
+		if _partlyExplored {
+			start = _state.start
+		}
+		for _ord := start; _ord < _state.end; _ord++ {
+			_eid := memo.ExprID{Group: _rootExpr.Left(), Expr: _ord}
+			_innerJoinExpr := _e.mem.Expr(_eid).AsInnerJoin()
+			if _innerJoinExpr != nil {
+				r := _innerJoinExpr.Left()
+				s := _innerJoinExpr.Right()
+				innerOn := _innerJoinExpr.On()
+				t := _rootExpr.Right()
+				on := _rootExpr.On()
+				if _e.canAssociateJoin(r, s, t, innerOn, on) {
+					if _e.o.onRuleMatch == nil || _e.o.onRuleMatch(opt.AssociateJoin) {
+						_expr := memo.MakeInnerJoinExpr(
+							_e.f.ConstructInnerJoin(
+								r,
+								t,
+								_e.constructFiltersNotUsing(s, innerOn, on),
+							),
+							s,
+							_e.constructFiltersUsing(s, innerOn, on),
+						)
+						_e.mem.MemoizeDenormExpr(_root.Group, memo.Expr(_expr))
+					}
+				}
+			}
+		}
+	}
+
+	// [GenerateGreedyJoinOrder]
+	{
+		if _root.Expr >= _rootState.start {
+			left := _rootExpr.Left()
+			right := _rootExpr.Right()
+			on := _rootExpr.On()
+			if _e.shouldReorderJoinsGreedily(left, right) {
+				if _e.o.onRuleMatch == nil || _e.o.onRuleMatch(opt.GenerateGreedyJoinOrder) {
+					exprs := _e.generateGreedyJoinOrder(left, right, on)
+					for i := range exprs {
+						_e.mem.MemoizeDenormExpr(_root.Group, exprs[i])
+					}
+				}
+			}
+		}
+	}
+
+	return _fullyExplored
+}
+
 func (_e *explorer) exploreLimit(_rootState *exploreState, _root memo.ExprID) (_fullyExplored bool) {
 	_rootExpr := _e.mem.Expr(_root).AsLimit()
 	_fullyExplored = true
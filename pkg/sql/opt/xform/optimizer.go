@@ -61,7 +61,7 @@ func NewOptimizer(evalCtx *tree.EvalContext) *Optimizer {
 		mem:      f.Memo(),
 		stateMap: make(map[optStateKey]*optState),
 	}
-	o.coster.init(o.mem)
+	o.coster.init(o.mem, o.evalCtx)
 	o.explorer.init(o)
 	return o
 }
@@ -16,10 +16,14 @@ package sql
 
 import (
 	"context"
+	"sort"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/sql/opt"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/stats"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
 )
 
 // optCatalog implements the opt.Catalog interface over the SchemaResolver
@@ -30,6 +34,10 @@ type optCatalog struct {
 	// resolver needs to be set via a call to init before calling other methods.
 	resolver SchemaResolver
 
+	// statsCache is used to look up the statistics of the tables. If it is nil,
+	// the tables have no statistics.
+	statsCache *stats.TableStatisticsCache
+
 	// wrappers is a cache of table wrappers that's used to satisfy repeated
 	// calls to the FindTable method for the same table.
 	wrappers map[*sqlbase.TableDescriptor]*optTable
@@ -38,8 +46,9 @@ type optCatalog struct {
 var _ opt.Catalog = &optCatalog{}
 
 // init allows the optCatalog wrapper to be inlined.
-func (oc *optCatalog) init(resolver SchemaResolver) {
+func (oc *optCatalog) init(resolver SchemaResolver, statsCache *stats.TableStatisticsCache) {
 	oc.resolver = resolver
+	oc.statsCache = statsCache
 }
 
 // FindTable is part of the opt.Catalog interface.
@@ -56,6 +65,11 @@ func (oc *optCatalog) FindTable(ctx context.Context, name *tree.TableName) (opt.
 	wrapper, ok := oc.wrappers[desc]
 	if !ok {
 		wrapper = newOptTable(desc)
+		if oc.statsCache != nil && !desc.IsVirtualTable() {
+			if err := wrapper.loadStats(ctx, oc.statsCache); err != nil {
+				return nil, err
+			}
+		}
 		oc.wrappers[desc] = wrapper
 	}
 	return wrapper, nil
@@ -76,6 +90,10 @@ type optTable struct {
 	// wrappers is a cache of index wrappers that's used to satisfy repeated
 	// calls to the SecondaryIndex method for the same index.
 	wrappers map[*sqlbase.IndexDescriptor]*optIndex
+
+	// stats are the statistics of the table, ordered from most recently to
+	// least recently created.
+	stats []optTableStat
 }
 
 var _ opt.Table = &optTable{}
@@ -135,16 +153,154 @@ func (ot *optTable) Index(i int) opt.Index {
 	return wrapper
 }
 
+// StatisticCount is part of the opt.Table interface.
+func (ot *optTable) StatisticCount() int {
+	return len(ot.stats)
+}
+
+// Statistic is part of the opt.Table interface.
+func (ot *optTable) Statistic(i int) opt.TableStatistic {
+	return &ot.stats[i]
+}
+
 // lookupColumnOrdinal returns the ordinal of the column with the given ID. A
 // cache makes the lookup O(1).
 func (ot *optTable) lookupColumnOrdinal(colID sqlbase.ColumnID) int {
+	ord, _ := ot.findColumnOrdinal(colID)
+	return ord
+}
+
+// findColumnOrdinal is like lookupColumnOrdinal, except that it returns
+// ok=false if the table has no column with the given ID.
+func (ot *optTable) findColumnOrdinal(colID sqlbase.ColumnID) (ord int, ok bool) {
 	if ot.colMap == nil {
 		ot.colMap = make(map[sqlbase.ColumnID]int, len(ot.desc.Columns))
 		for i := range ot.desc.Columns {
 			ot.colMap[ot.desc.Columns[i].ID] = i
 		}
 	}
-	return ot.colMap[colID]
+	ord, ok = ot.colMap[colID]
+	return ord, ok
+}
+
+// loadStats retrieves the statistics of the table (including histograms)
+// from the given cache. Statistics on columns that no longer exist are
+// ignored.
+func (ot *optTable) loadStats(ctx context.Context, statsCache *stats.TableStatisticsCache) error {
+	tableStats, err := statsCache.GetTableStats(ctx, ot.desc.ID)
+	if err != nil {
+		return err
+	}
+
+	ot.stats = make([]optTableStat, 0, len(tableStats))
+	for _, stat := range tableStats {
+		ts := optTableStat{
+			createdAt:      stat.CreatedAt,
+			columnOrdinals: make([]int, len(stat.ColumnIDs)),
+			rowCount:       stat.RowCount,
+			distinctCount:  stat.DistinctCount,
+			nullCount:      stat.NullCount,
+		}
+		valid := true
+		for i, colID := range stat.ColumnIDs {
+			ord, ok := ot.findColumnOrdinal(colID)
+			if !ok {
+				valid = false
+				break
+			}
+			ts.columnOrdinals[i] = ord
+		}
+		if !valid {
+			continue
+		}
+
+		if stat.HasHistogram {
+			histogram, err := statsCache.GetHistogram(ctx, ot.desc.ID, stat.StatisticID)
+			if err != nil {
+				return err
+			}
+			if histogram != nil {
+				if ts.histogram, err = decodeHistogram(histogram); err != nil {
+					return err
+				}
+			}
+		}
+		ot.stats = append(ot.stats, ts)
+	}
+
+	sort.SliceStable(ot.stats, func(i, j int) bool {
+		return ot.stats[i].createdAt.After(ot.stats[j].createdAt)
+	})
+	return nil
+}
+
+// decodeHistogram decodes the upper bounds of the buckets of the given
+// histogram.
+func decodeHistogram(histogram *stats.HistogramData) ([]opt.HistogramBucket, error) {
+	typ := histogram.ColumnType.ToDatumType()
+	buckets := make([]opt.HistogramBucket, len(histogram.Buckets))
+	var a sqlbase.DatumAlloc
+	for i := range histogram.Buckets {
+		b := &histogram.Buckets[i]
+		upperBound, _, err := sqlbase.DecodeTableKey(&a, typ, b.UpperBound, encoding.Ascending)
+		if err != nil {
+			return nil, err
+		}
+		buckets[i] = opt.HistogramBucket{
+			NumEq:      uint64(b.NumEq),
+			NumRange:   uint64(b.NumRange),
+			UpperBound: upperBound,
+		}
+	}
+	return buckets, nil
+}
+
+// optTableStat is a wrapper around stats.TableStatistic that refers to
+// columns by ordinal and holds the decoded histogram of the statistic.
+type optTableStat struct {
+	createdAt      time.Time
+	columnOrdinals []int
+	rowCount       uint64
+	distinctCount  uint64
+	nullCount      uint64
+	histogram      []opt.HistogramBucket
+}
+
+var _ opt.TableStatistic = &optTableStat{}
+
+// CreatedAt is part of the opt.TableStatistic interface.
+func (ts *optTableStat) CreatedAt() time.Time {
+	return ts.createdAt
+}
+
+// ColumnCount is part of the opt.TableStatistic interface.
+func (ts *optTableStat) ColumnCount() int {
+	return len(ts.columnOrdinals)
+}
+
+// ColumnOrdinal is part of the opt.TableStatistic interface.
+func (ts *optTableStat) ColumnOrdinal(i int) int {
+	return ts.columnOrdinals[i]
+}
+
+// RowCount is part of the opt.TableStatistic interface.
+func (ts *optTableStat) RowCount() uint64 {
+	return ts.rowCount
+}
+
+// DistinctCount is part of the opt.TableStatistic interface.
+func (ts *optTableStat) DistinctCount() uint64 {
+	return ts.distinctCount
+}
+
+// NullCount is part of the opt.TableStatistic interface.
+func (ts *optTableStat) NullCount() uint64 {
+	return ts.nullCount
+}
+
+// Histogram is part of the opt.TableStatistic interface.
+func (ts *optTableStat) Histogram() []opt.HistogramBucket {
+	return ts.histogram
 }
 
 // optIndex is a wrapper around sqlbase.IndexDescriptor that caches some
@@ -52,7 +52,7 @@ var _ exec.TestEngine = &execEngine{}
 
 func newExecEngine(p *planner, cleanup func()) *execEngine {
 	ee := &execEngine{planner: p, cleanup: cleanup}
-	ee.catalog.init(p)
+	ee.catalog.init(p, p.ExecCfg().TableStatsCache)
 	return ee
 }
 
@@ -138,6 +138,16 @@ func (ee *execEngine) ConstructValues(
 	}, nil
 }
 
+// ConstructGenerator is part of the exec.Factory interface.
+func (ee *execEngine) ConstructGenerator(
+	call tree.TypedExpr, cols sqlbase.ResultColumns,
+) (exec.Node, error) {
+	return &valueGenerator{
+		expr:    call,
+		columns: cols,
+	}, nil
+}
+
 // ConstructScan is part of the exec.Factory interface.
 func (ee *execEngine) ConstructScan(
 	table opt.Table,
@@ -275,6 +285,39 @@ func (ee *execEngine) ConstructJoin(
 	return p.makeJoinNode(leftSrc, rightSrc, pred), nil
 }
 
+// ConstructApplyJoin is part of the exec.Factory interface.
+func (ee *execEngine) ConstructApplyJoin(
+	joinType sqlbase.JoinType,
+	left, right exec.Node,
+	fn exec.ApplyJoinPlanRightFn,
+	onCond tree.TypedExpr,
+) (exec.Node, error) {
+	p := ee.planner
+	leftSrc := asDataSource(left)
+	rightSrc := asDataSource(right)
+	pred, _, err := p.makeJoinPredicate(
+		context.TODO(), leftSrc.info, rightSrc.info, joinType, nil, /* cond */
+	)
+	if err != nil {
+		return nil, err
+	}
+	if onCond != tree.DBoolTrue {
+		pred.onCond = pred.iVarHelper.Rebind(
+			onCond, false /* alsoReset */, false, /* normmalizeToNonNil */
+		)
+	}
+
+	n := p.makeApplyJoinNode(leftSrc, rightSrc, pred)
+	n.planRightFn = func(_ runParams, leftRow tree.Datums) (planNode, error) {
+		plan, err := fn(leftRow)
+		if err != nil {
+			return nil, err
+		}
+		return plan.(planNode), nil
+	}
+	return n, nil
+}
+
 // ConstructGroupBy is part of the exec.Factory interface.
 func (ee *execEngine) ConstructGroupBy(
 	input exec.Node, groupCols []exec.ColumnOrdinal, aggregations []exec.AggInfo,
@@ -393,3 +436,29 @@ func (ee *execEngine) ConstructLimit(
 		offsetExpr: offsetVal,
 	}, nil
 }
+
+// ConstructRecursiveCTE is part of the exec.Factory interface.
+func (ee *execEngine) ConstructRecursiveCTE(
+	initial exec.Node, fn exec.RecursiveCTEIterationFn, label string, unionAll bool,
+) (exec.Node, error) {
+	initialCols := planColumns(initial.(planNode))
+	cols := make(sqlbase.ResultColumns, len(initialCols))
+	for i := range initialCols {
+		cols[i] = sqlbase.ResultColumn{Name: initialCols[i].Name, Typ: initialCols[i].Typ}
+	}
+	n := newRecursiveCTENode(initial.(planNode), label, cols, unionAll)
+	n.genIterationFn = func(_ runParams, n *recursiveCTENode) (planNode, error) {
+		plan, err := fn(n)
+		if err != nil {
+			return nil, err
+		}
+		return plan.(planNode), nil
+	}
+	return n, nil
+}
+
+// ConstructScanBuffer is part of the exec.Factory interface.
+func (ee *execEngine) ConstructScanBuffer(bufferRef exec.Node, label string) (exec.Node, error) {
+	n := bufferRef.(*recursiveCTENode)
+	return &scanBufferNode{buffer: n, label: label, columns: n.columns}, nil
+}
@@ -198,6 +198,16 @@ func (p *planner) propagateFilters(
 	case *joinNode:
 		return p.addJoinFilter(ctx, n, extraFilter)
 
+	case *applyJoinNode:
+		// The right side is re-planned for every row of the left side, so
+		// filters are not pushed down.
+		if n.left.plan, err = p.triggerFilterPropagation(ctx, n.left.plan); err != nil {
+			return plan, extraFilter, err
+		}
+		if n.right.plan, err = p.triggerFilterPropagation(ctx, n.right.plan); err != nil {
+			return plan, extraFilter, err
+		}
+
 	case *indexJoinNode:
 		panic("filter optimization must occur before index selection")
 
@@ -236,6 +246,20 @@ func (p *planner) propagateFilters(
 		n.right = newRight
 		return plan, tree.DBoolTrue, nil
 
+	case *recursiveCTENode:
+		// Filters cannot be pushed into the terms of a recursive CTE, as the
+		// rows they produce feed the next iteration.
+		if n.initial != nil {
+			if n.initial, err = p.triggerFilterPropagation(ctx, n.initial); err != nil {
+				return plan, extraFilter, err
+			}
+		}
+		if n.recursive != nil {
+			if n.recursive, err = p.triggerFilterPropagation(ctx, n.recursive); err != nil {
+				return plan, extraFilter, err
+			}
+		}
+
 	case *groupNode:
 		return p.addGroupFilter(ctx, n, info, extraFilter)
 
@@ -291,6 +315,11 @@ func (p *planner) propagateFilters(
 			return plan, extraFilter, err
 		}
 
+	case *explainAnalyzeNode:
+		if n.plan, err = p.triggerFilterPropagation(ctx, n.plan); err != nil {
+			return plan, extraFilter, err
+		}
+
 	case *explainPlanNode:
 		if n.optimized {
 			if n.plan, err = p.triggerFilterPropagation(ctx, n.plan); err != nil {
@@ -338,15 +367,19 @@ func (p *planner) propagateFilters(
 	case *CreateUserNode:
 	case *createViewNode:
 	case *createSequenceNode:
+	case *refreshViewNode:
+	case *createFunctionNode:
 	case *createStatsNode:
 	case *dropDatabaseNode:
 	case *dropIndexNode:
 	case *dropTableNode:
 	case *dropViewNode:
 	case *dropSequenceNode:
+	case *dropFunctionNode:
 	case *DropUserNode:
 	case *hookFnNode:
 	case *valueGenerator:
+	case *scanBufferNode:
 	case *valuesNode:
 	case *sequenceSelectNode:
 	case *setVarNode:
@@ -425,7 +458,10 @@ func (p *planner) addGroupFilter(
 		convFunc := func(v tree.VariableExpr) (bool, tree.Expr) {
 			if iv, ok := v.(*tree.IndexedVar); ok {
 				f := g.funcs[iv.Idx]
-				if f.isIdentAggregate() {
+				// With grouping sets, the group by columns are NULL in the
+				// groups of the grouping sets that don't contain them, so
+				// filters on them can't be applied to the source.
+				if f.isIdentAggregate() && g.groupingSets == nil {
 					return true, &tree.IndexedVar{Idx: f.argRenderIdx}
 				}
 			}
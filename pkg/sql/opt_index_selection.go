@@ -30,6 +30,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/optbuilder"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/xform"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/transform"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/util/encoding"
@@ -141,6 +142,27 @@ func (p *planner) selectIndex(
 		}
 		bld := optbuilder.NewScalar(ctx, &p.semaCtx, p.EvalContext(), optimizer.Factory())
 		bld.AllowUnsupportedExpr = true
+
+		// Register the expressions of the computed columns, so that a filter on
+		// an expression can be constrained by an index on a column computing the
+		// same expression (e.g. an expression index).
+		var txCtx transform.ExprTransformContext
+		ivarHelper := tree.MakeIndexedVarHelper(&descContainer{s.cols}, len(s.cols))
+		computedExprs, err := sqlbase.MakeComputedExprs(s.cols, s.cols, &ivarHelper, &txCtx, p.EvalContext())
+		if err != nil {
+			return nil, err
+		}
+		for i, e := range computedExprs {
+			if !s.cols[i].IsComputed() {
+				continue
+			}
+			group, err := bld.Build(e)
+			if err != nil {
+				return nil, err
+			}
+			optimizer.Memo().SetComputedColumnExpr(opt.ColumnID(i+1), group)
+		}
+
 		filterGroup, err := bld.Build(s.filter)
 		if err != nil {
 			return nil, err
@@ -372,6 +394,10 @@ func (v *indexInfo) isCoveringIndex(scan *scanNode) bool {
 		// The primary key index always covers all of the columns.
 		return true
 	}
+	if scan.lockingStrength != tree.ForNone {
+		// Rows are locked in the primary index, so it must be scanned.
+		return false
+	}
 
 	for _, colIdx := range scan.valNeededForCol.Ordered() {
 		// This is possible during a schema change when we have
@@ -112,6 +112,14 @@ func (p *planner) applyLimit(plan planNode, numRows int64, soft bool) {
 			p.applyLimit(n.left, numRows, true)
 		}
 
+	case *recursiveCTENode:
+		if n.initial != nil {
+			p.setUnlimited(n.initial)
+		}
+		if n.recursive != nil {
+			p.setUnlimited(n.recursive)
+		}
+
 	case *distinctNode:
 		p.applyLimit(n.plan, numRows, true)
 
@@ -128,6 +136,10 @@ func (p *planner) applyLimit(plan planNode, numRows int64, soft bool) {
 		p.setUnlimited(n.left.plan)
 		p.setUnlimited(n.right.plan)
 
+	case *applyJoinNode:
+		p.setUnlimited(n.left.plan)
+		p.setUnlimited(n.right.plan)
+
 	case *ordinalityNode:
 		p.applyLimit(n.source, numRows, soft)
 
@@ -168,6 +180,8 @@ func (p *planner) applyLimit(plan planNode, numRows int64, soft bool) {
 		}
 	case *explainDistSQLNode:
 		p.setUnlimited(n.plan)
+	case *explainAnalyzeNode:
+		p.setUnlimited(n.plan)
 	case *showTraceNode:
 		p.setUnlimited(n.plan)
 	case *showTraceReplicaNode:
@@ -197,17 +211,21 @@ func (p *planner) applyLimit(plan planNode, numRows int64, soft bool) {
 	case *CreateUserNode:
 	case *createViewNode:
 	case *createSequenceNode:
+	case *refreshViewNode:
+	case *createFunctionNode:
 	case *createStatsNode:
 	case *dropDatabaseNode:
 	case *dropIndexNode:
 	case *dropTableNode:
 	case *dropViewNode:
 	case *dropSequenceNode:
+	case *dropFunctionNode:
 	case *DropUserNode:
 	case *zeroNode:
 	case *unaryNode:
 	case *hookFnNode:
 	case *valueGenerator:
+	case *scanBufferNode:
 	case *sequenceSelectNode:
 	case *setVarNode:
 	case *setClusterSettingNode:
@@ -34,6 +34,9 @@ func setNeededColumns(plan planNode, needed []bool) {
 	case *explainDistSQLNode:
 		setNeededColumns(n.plan, allColumns(n.plan))
 
+	case *explainAnalyzeNode:
+		setNeededColumns(n.plan, allColumns(n.plan))
+
 	case *showTraceNode:
 		setNeededColumns(n.plan, allColumns(n.plan))
 
@@ -72,6 +75,15 @@ func setNeededColumns(plan planNode, needed []bool) {
 		setNeededColumns(n.right, needed)
 		markOmitted(n.columns, needed)
 
+	case *recursiveCTENode:
+		// All the columns are needed to populate the working table.
+		if n.initial != nil {
+			setNeededColumns(n.initial, allColumns(n.initial))
+		}
+		if n.recursive != nil {
+			setNeededColumns(n.recursive, allColumns(n.recursive))
+		}
+
 	case *joinNode:
 		// Note: getNeededColumns takes into account both the columns
 		// tested for equality and the join predicate expression.
@@ -80,6 +92,13 @@ func setNeededColumns(plan planNode, needed []bool) {
 		setNeededColumns(n.right.plan, rightNeeded)
 		markOmitted(n.columns, needed)
 
+	case *applyJoinNode:
+		// All the columns of the left side are needed to plan the right side,
+		// and all the columns of the right side are needed as it is re-planned
+		// for every row of the left side.
+		setNeededColumns(n.left.plan, allColumns(n.left.plan))
+		setNeededColumns(n.right.plan, allColumns(n.right.plan))
+
 	case *ordinalityNode:
 		setNeededColumns(n.source, needed[:len(needed)-1])
 		markOmitted(n.columns[:len(needed)-1], needed[:len(needed)-1])
@@ -213,17 +232,21 @@ func setNeededColumns(plan planNode, needed []bool) {
 	case *CreateUserNode:
 	case *createViewNode:
 	case *createSequenceNode:
+	case *refreshViewNode:
+	case *createFunctionNode:
 	case *createStatsNode:
 	case *dropDatabaseNode:
 	case *dropIndexNode:
 	case *dropTableNode:
 	case *dropViewNode:
 	case *dropSequenceNode:
+	case *dropFunctionNode:
 	case *DropUserNode:
 	case *zeroNode:
 	case *unaryNode:
 	case *hookFnNode:
 	case *valueGenerator:
+	case *scanBufferNode:
 	case *sequenceSelectNode:
 	case *setVarNode:
 	case *setClusterSettingNode:
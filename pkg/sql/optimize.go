@@ -56,6 +56,29 @@ func (p *planner) optimizePlan(
 	return newPlan, nil
 }
 
+// optimizeDeferredPlan optimizes a plan that is built while the query is
+// already executing, for example the plan of an iteration of a recursive CTE.
+// The subqueries added to the query plan while building it, starting at index
+// firstSubquery, are optimized and evaluated too, so that the resulting plan
+// can be started right away.
+func (p *planner) optimizeDeferredPlan(
+	params runParams, plan planNode, firstSubquery int,
+) (planNode, error) {
+	plan, err := p.optimizePlan(params.ctx, plan, allColumns(plan))
+	if err != nil {
+		return plan, err
+	}
+	for i := firstSubquery; i < len(p.curPlan.subqueryPlans); i++ {
+		if err := p.optimizeSubquery(params.ctx, &p.curPlan.subqueryPlans[i]); err != nil {
+			return plan, err
+		}
+	}
+	if err := p.curPlan.evalSubqueries(params); err != nil {
+		return plan, err
+	}
+	return plan, nil
+}
+
 // optimizeSubquery ensures plan optimization has been perfomed on the given subquery.
 func (p *planner) optimizeSubquery(ctx context.Context, sq *subquery) error {
 	if sq.expanded {
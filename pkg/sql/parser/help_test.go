@@ -100,6 +100,7 @@ func TestContextualHelp(t *testing.T) {
 		{`CREATE VIEW blah AS (SELECT c FROM x) ??`, `CREATE VIEW`},
 		{`CREATE VIEW blah AS SELECT c FROM x ??`, `SELECT`},
 		{`CREATE VIEW blah AS (??`, `<SELECTCLAUSE>`},
+		{`CREATE MATERIALIZED VIEW blah (??`, `CREATE VIEW`},
 
 		{`CREATE SEQUENCE ??`, `CREATE SEQUENCE`},
 
@@ -144,6 +145,7 @@ func TestContextualHelp(t *testing.T) {
 		{`DROP VIEW blah ??`, `DROP VIEW`},
 		{`DROP VIEW IF ??`, `DROP VIEW`},
 		{`DROP VIEW IF EXISTS blih, bloh ??`, `DROP VIEW`},
+		{`DROP MATERIALIZED VIEW blah ??`, `DROP VIEW`},
 
 		{`DROP USER ??`, `DROP USER`},
 		{`DROP USER IF ??`, `DROP USER`},
@@ -286,6 +288,9 @@ func TestContextualHelp(t *testing.T) {
 
 		{`SHOW USERS ??`, `SHOW USERS`},
 
+		{`REFRESH ??`, `REFRESH MATERIALIZED VIEW`},
+		{`REFRESH MATERIALIZED VIEW blah ??`, `REFRESH MATERIALIZED VIEW`},
+
 		{`TRUNCATE foo ??`, `TRUNCATE`},
 		{`TRUNCATE foo, ??`, `TRUNCATE`},
 
@@ -79,6 +79,11 @@ func TestParse(t *testing.T) {
 		{`CREATE INDEX ON a (b) INTERLEAVE IN PARENT c (d)`},
 		{`CREATE INDEX ON a (b) INTERLEAVE IN PARENT c.d (e)`},
 		{`CREATE INDEX ON a (b ASC, c DESC)`},
+		{`CREATE INDEX ON a (lower(b))`},
+		{`CREATE INDEX ON a (lower(b) DESC, c)`},
+		{`CREATE INDEX ON a ((b + c))`},
+		{`CREATE INDEX ON a ((b->>'c') ASC) STORING (d)`},
+		{`CREATE UNIQUE INDEX ON a (lower(b))`},
 		{`CREATE UNIQUE INDEX a ON b (c)`},
 		{`CREATE UNIQUE INDEX a ON b (c) STORING (d)`},
 		{`CREATE UNIQUE INDEX a ON b (c) INTERLEAVE IN PARENT d (e, f)`},
@@ -142,12 +147,17 @@ func TestParse(t *testing.T) {
 		{`CREATE TABLE a (b INT, c TEXT, FOREIGN KEY (b, c) REFERENCES other)`},
 		{`CREATE TABLE a (b INT, c TEXT, FOREIGN KEY (b, c) REFERENCES other (x, y))`},
 		{`CREATE TABLE a (b INT, c TEXT, CONSTRAINT s FOREIGN KEY (b, c) REFERENCES other (x, y))`},
+		{`CREATE TABLE a (b INT, c TEXT, FOREIGN KEY (b, c) REFERENCES other (x, y) MATCH FULL)`},
+		{`CREATE TABLE a (b INT, c TEXT, FOREIGN KEY (b, c) REFERENCES other MATCH FULL ON DELETE CASCADE)`},
+		{`CREATE TABLE a (b INT, c TEXT, FOREIGN KEY (b, c) REFERENCES other MATCH PARTIAL)`},
 		{`CREATE TABLE a (b INT, c TEXT, INDEX (b, c))`},
 		{`CREATE TABLE a (b INT, c TEXT, INDEX d (b, c))`},
 		{`CREATE TABLE a (b INT, c TEXT, CONSTRAINT d UNIQUE (b, c))`},
 		{`CREATE TABLE a (b INT, c TEXT, CONSTRAINT d UNIQUE (b, c) INTERLEAVE IN PARENT d (e, f))`},
 		{`CREATE TABLE a (b INT, UNIQUE (b))`},
 		{`CREATE TABLE a (b INT, UNIQUE (b) STORING (c))`},
+		{`CREATE TABLE a (b STRING, INDEX (lower(b)))`},
+		{`CREATE TABLE a (b INT, c INT, UNIQUE ((b * c)))`},
 		{`CREATE TABLE a (b INT, INDEX (b))`},
 		{`CREATE TABLE a (b INT, c INT REFERENCES foo)`},
 		{`CREATE TABLE a (b INT, c INT REFERENCES foo ON UPDATE RESTRICT)`},
@@ -166,6 +176,7 @@ func TestParse(t *testing.T) {
 		{`CREATE TABLE a (b INT, c INT REFERENCES foo ON DELETE SET DEFAULT ON UPDATE CASCADE)`},
 		{`CREATE TABLE a (b INT, c INT REFERENCES foo ON DELETE CASCADE ON UPDATE SET NULL)`},
 		{`CREATE TABLE a (b INT, c INT REFERENCES foo ON DELETE SET NULL ON UPDATE RESTRICT)`},
+		{`CREATE TABLE a (b INT, c INT REFERENCES foo (x) MATCH FULL ON UPDATE CASCADE)`},
 		{`CREATE TABLE a (b INT, c INT REFERENCES foo ON UPDATE CASCADE)`},
 		{`CREATE TABLE a (b INT, c INT REFERENCES foo ON UPDATE SET NULL)`},
 		{`CREATE TABLE a (b INT, c INT REFERENCES foo ON UPDATE SET DEFAULT)`},
@@ -181,6 +192,9 @@ func TestParse(t *testing.T) {
 		{`CREATE TABLE a.b (b INT)`},
 		{`CREATE TABLE IF NOT EXISTS a (b INT)`},
 		{`CREATE TABLE a (b INT AS (a + b) STORED)`},
+		{`CREATE TABLE a (b INT AS (a + b) VIRTUAL)`},
+		{`CREATE TABLE a (b JSONB, c STRING AS (b->>'c') VIRTUAL, INDEX (c))`},
+		{`ALTER TABLE a ADD COLUMN b INT AS (c * 2) VIRTUAL`},
 
 		{`CREATE TABLE a (b INT) PARTITION BY LIST (b) (PARTITION p1 VALUES IN (1, DEFAULT), PARTITION p2 VALUES IN ((1, 2), (3, 4)))`},
 		{`CREATE TABLE a (b INT) PARTITION BY RANGE (b) (PARTITION p1 VALUES FROM (MINVALUE) TO (1), PARTITION p2 VALUES FROM (2, MAXVALUE) TO (4, 4), PARTITION p3 VALUES FROM (4, 4) TO (MAXVALUE))`},
@@ -208,6 +222,9 @@ func TestParse(t *testing.T) {
 		{`ALTER INDEX a@idx PARTITION BY LIST (b) (PARTITION p1 VALUES IN (1))`},
 
 		{`CREATE TABLE a AS SELECT * FROM b`},
+		{`CREATE TEMP TABLE a AS SELECT * FROM b`},
+		{`CREATE TEMP TABLE IF NOT EXISTS a (b INT)`},
+		{`CREATE TEMP TABLE pg_temp.a (b INT PRIMARY KEY)`},
 		{`CREATE TABLE IF NOT EXISTS a AS SELECT * FROM b`},
 		{`CREATE TABLE a AS SELECT * FROM b ORDER BY c`},
 		{`CREATE TABLE IF NOT EXISTS a AS SELECT * FROM b ORDER BY c`},
@@ -231,7 +248,12 @@ func TestParse(t *testing.T) {
 		{`CREATE VIEW a AS VALUES (1, 'one'), (2, 'two')`},
 		{`CREATE VIEW a (x, y) AS VALUES (1, 'one'), (2, 'two')`},
 		{`CREATE VIEW a AS TABLE b`},
+		{`CREATE MATERIALIZED VIEW a AS SELECT * FROM b`},
+		{`CREATE MATERIALIZED VIEW a (x, y) AS SELECT c, d FROM b`},
 
+		{`CREATE FUNCTION f() RETURNS INT LANGUAGE SQL AS 'SELECT 1'`},
+		{`CREATE FUNCTION a.f(x INT, y STRING) RETURNS STRING LANGUAGE SQL AS 'SELECT y || x::STRING'`},
+		{`CREATE FUNCTION f(x INT) RETURNS INT LANGUAGE SQL AS 'SELECT $1 + 1'`},
 		{`CREATE SEQUENCE a`},
 		{`CREATE SEQUENCE IF NOT EXISTS a`},
 		{`CREATE SEQUENCE a CYCLE`},
@@ -249,6 +271,11 @@ func TestParse(t *testing.T) {
 		{`CREATE SEQUENCE a START WITH 1000`},
 		{`CREATE SEQUENCE a INCREMENT 5 NO MAXVALUE MINVALUE 1 START 3`},
 		{`CREATE SEQUENCE a INCREMENT 5 NO CYCLE NO MAXVALUE MINVALUE 1 START 3 CACHE 1`},
+		{`CREATE SEQUENCE a AS INT2`},
+		{`CREATE SEQUENCE a AS BIGINT INCREMENT 5`},
+		{`CREATE SEQUENCE a OWNED BY b.c`},
+		{`CREATE SEQUENCE a OWNED BY b.c.d`},
+		{`CREATE SEQUENCE a OWNED BY NONE`},
 
 		{`CREATE STATISTICS a ON col1 FROM t`},
 		{`CREATE STATISTICS a ON col1, col2 FROM t`},
@@ -267,6 +294,7 @@ func TestParse(t *testing.T) {
 		{`DELETE FROM a WHERE a = b ORDER BY c LIMIT d RETURNING e`},
 
 		{`DISCARD ALL`},
+		{`DISCARD TEMP`},
 
 		{`DROP DATABASE a`},
 		{`DROP DATABASE IF EXISTS a`},
@@ -300,6 +328,11 @@ func TestParse(t *testing.T) {
 		{`DROP VIEW IF EXISTS a, b RESTRICT`},
 		{`DROP VIEW a.b CASCADE`},
 		{`DROP VIEW a, b CASCADE`},
+		{`DROP MATERIALIZED VIEW a`},
+		{`DROP MATERIALIZED VIEW IF EXISTS a, b CASCADE`},
+		{`DROP FUNCTION a`},
+		{`DROP FUNCTION a.b, c`},
+		{`DROP FUNCTION IF EXISTS a`},
 		{`DROP SEQUENCE a`},
 		{`DROP SEQUENCE a.b`},
 		{`DROP SEQUENCE a, b`},
@@ -320,6 +353,8 @@ func TestParse(t *testing.T) {
 		{`EXPLAIN SELECT 1`},
 		{`EXPLAIN EXPLAIN SELECT 1`},
 		{`EXPLAIN (A, B, C) SELECT 1`},
+		{`EXPLAIN ANALYZE SELECT 1`},
+		{`EXPLAIN ANALYZE (DISTSQL) SELECT 1`},
 		{`SELECT * FROM [EXPLAIN SELECT 1]`},
 		{`SELECT * FROM [SHOW TRANSACTION STATUS]`},
 
@@ -448,6 +483,8 @@ func TestParse(t *testing.T) {
 		{`GRANT SELECT, INSERT ON DATABASE bar TO foo, bar, baz`},
 		{`GRANT SELECT, INSERT ON DATABASE db1, db2 TO foo, bar, baz`},
 		{`GRANT SELECT, INSERT ON DATABASE db1, db2 TO "test-user"`},
+		{`GRANT EXECUTE ON FUNCTION f TO root`},
+		{`GRANT ALL ON FUNCTION a.f, g TO root, bar`},
 		{`GRANT rolea, roleb TO usera, userb`},
 		{`GRANT rolea, roleb TO usera, userb WITH ADMIN OPTION`},
 
@@ -456,6 +493,7 @@ func TestParse(t *testing.T) {
 		{`REVOKE SELECT ON foo FROM root`},
 		{`REVOKE UPDATE, DELETE ON foo, db.foo FROM root, bar`},
 		{`REVOKE INSERT ON DATABASE foo FROM root`},
+		{`REVOKE EXECUTE ON FUNCTION f FROM root`},
 		{`REVOKE ALL ON DATABASE foo FROM root, test`},
 		{`REVOKE SELECT, INSERT ON DATABASE bar FROM foo, bar, baz`},
 		{`REVOKE SELECT, INSERT ON DATABASE db1, db2 FROM foo, bar, baz`},
@@ -503,6 +541,8 @@ func TestParse(t *testing.T) {
 		{`INSERT INTO a VALUES (1) ON CONFLICT (a) DO UPDATE SET (a, b) = (SELECT 1, 2) RETURNING 1, 2`},
 		{`INSERT INTO a VALUES (1) ON CONFLICT (a) DO UPDATE SET (a, b) = (SELECT 1, 2) RETURNING a + b`},
 		{`INSERT INTO a VALUES (1) ON CONFLICT (a) DO UPDATE SET (a, b) = (SELECT 1, 2) RETURNING NOTHING`},
+		{`INSERT INTO a VALUES (1) ON CONFLICT ON CONSTRAINT a_pkey DO NOTHING`},
+		{`INSERT INTO a VALUES (1) ON CONFLICT ON CONSTRAINT a_b_key DO UPDATE SET b = excluded.b WHERE a.c > 2`},
 
 		{`SELECT 1 + 1`},
 		{`SELECT -1`},
@@ -640,6 +680,16 @@ func TestParse(t *testing.T) {
 		{`SELECT a FROM generate_series(1, 32)`},
 		{`SELECT a FROM generate_series(1, 32) AS s (x)`},
 		{`SELECT a FROM generate_series(1, 32) WITH ORDINALITY AS s (x)`},
+		{`SELECT * FROM ab, LATERAL (SELECT * FROM kv)`},
+		{`SELECT * FROM ab, LATERAL (SELECT * FROM kv WHERE k = a LIMIT 3) AS x`},
+		{`SELECT * FROM ab JOIN LATERAL (SELECT * FROM kv) AS x ON true`},
+		{`SELECT * FROM ab LEFT JOIN LATERAL (SELECT * FROM kv) AS x ON true`},
+		{`SELECT * FROM ab, LATERAL foo(a)`},
+		{`SELECT * FROM ab, LATERAL foo(a) WITH ORDINALITY AS x`},
+		{`WITH a AS (SELECT 1) SELECT * FROM a`},
+		{`WITH a (x) AS (SELECT 1), b AS (SELECT x FROM a) SELECT * FROM b`},
+		{`WITH RECURSIVE a (x) AS (SELECT 1 UNION ALL SELECT x + 1 FROM a WHERE x < 10) SELECT * FROM a`},
+		{`WITH RECURSIVE a AS (SELECT 1 UNION SELECT 2 FROM a) SELECT * FROM a`},
 		{`SELECT a FROM t1, t2`},
 		{`SELECT a FROM t AS t1`},
 		{`SELECT a FROM t AS t1 (c1)`},
@@ -723,6 +773,11 @@ func TestParse(t *testing.T) {
 
 		{`SELECT 1 FROM t GROUP BY a`},
 		{`SELECT 1 FROM t GROUP BY a, b`},
+		{`SELECT a, b, sum(c) FROM t GROUP BY ROLLUP (a, b)`},
+		{`SELECT a, b, sum(c) FROM t GROUP BY CUBE (a, (b, c))`},
+		{`SELECT a, b, sum(c) FROM t GROUP BY GROUPING SETS (a, (a, b), ())`},
+		{`SELECT a, b, sum(c) FROM t GROUP BY a, GROUPING SETS (ROLLUP (b), CUBE (c), GROUPING SETS (d))`},
+		{`SELECT a, grouping(a), grouping(a, b) FROM t GROUP BY ROLLUP (a, b)`},
 
 		{`SELECT a FROM t HAVING a = b`},
 
@@ -741,6 +796,23 @@ func TestParse(t *testing.T) {
 		{`SELECT avg(1) OVER (ORDER BY c) FROM t`},
 		{`SELECT avg(1) OVER (PARTITION BY b ORDER BY c) FROM t`},
 		{`SELECT avg(1) OVER (w PARTITION BY b ORDER BY c) FROM t`},
+		{`SELECT avg(1) OVER (ROWS UNBOUNDED PRECEDING) FROM t`},
+		{`SELECT avg(1) OVER (ROWS 1 PRECEDING) FROM t`},
+		{`SELECT avg(1) OVER (ROWS CURRENT ROW) FROM t`},
+		{`SELECT avg(1) OVER (ROWS BETWEEN 1 PRECEDING AND 1 FOLLOWING) FROM t`},
+		{`SELECT avg(1) OVER (ROWS BETWEEN UNBOUNDED PRECEDING AND UNBOUNDED FOLLOWING) FROM t`},
+		{`SELECT avg(1) OVER (ROWS BETWEEN CURRENT ROW AND 2 FOLLOWING) FROM t`},
+		{`SELECT avg(1) OVER (PARTITION BY b ORDER BY c ROWS BETWEEN 2 PRECEDING AND CURRENT ROW) FROM t`},
+		{`SELECT avg(1) OVER (w ROWS BETWEEN 2 FOLLOWING AND 4 FOLLOWING) FROM t`},
+		{`SELECT avg(1) OVER (RANGE UNBOUNDED PRECEDING) FROM t`},
+		{`SELECT avg(1) OVER (ORDER BY c RANGE BETWEEN CURRENT ROW AND UNBOUNDED FOLLOWING) FROM t`},
+		{`SELECT avg(1) OVER (ORDER BY c RANGE BETWEEN 10 PRECEDING AND 10 FOLLOWING) FROM t`},
+		{`SELECT avg(1) OVER (ORDER BY c RANGE BETWEEN '1 day' PRECEDING AND CURRENT ROW) FROM t`},
+		{`SELECT a FROM t WINDOW w AS (ORDER BY c ROWS BETWEEN $1 PRECEDING AND $2 FOLLOWING)`},
+
+		{`SELECT percentile_disc(0.5) WITHIN GROUP (ORDER BY a) FROM t`},
+		{`SELECT percentile_cont(ARRAY[0.5, 0.95]) WITHIN GROUP (ORDER BY a) FROM t GROUP BY b`},
+		{`SELECT mode() WITHIN GROUP (ORDER BY a DESC) FILTER (WHERE b > 1) FROM t`},
 
 		{`SELECT a FROM t UNION SELECT 1 FROM t`},
 		{`SELECT a FROM t UNION SELECT 1 FROM t UNION SELECT 1 FROM t`},
@@ -767,6 +839,16 @@ func TestParse(t *testing.T) {
 		{`SELECT a FROM t LIMIT a`},
 		{`SELECT a FROM t OFFSET b`},
 		{`SELECT a FROM t LIMIT a OFFSET b`},
+		{`SELECT a FROM t FOR UPDATE`},
+		{`SELECT a FROM t FOR NO KEY UPDATE`},
+		{`SELECT a FROM t FOR SHARE`},
+		{`SELECT a FROM t FOR KEY SHARE`},
+		{`SELECT a FROM t FOR UPDATE NOWAIT`},
+		{`SELECT a FROM t FOR UPDATE SKIP LOCKED`},
+		{`SELECT a FROM t, u FOR UPDATE OF t, u`},
+		{`SELECT a FROM t, u FOR UPDATE OF t FOR SHARE OF u SKIP LOCKED`},
+		{`SELECT a FROM t ORDER BY a LIMIT 1 FOR UPDATE`},
+		{`WITH a AS (SELECT 1) SELECT * FROM a FOR UPDATE`},
 		{`SELECT DISTINCT * FROM t`},
 		{`SELECT DISTINCT a, b FROM t`},
 		{`SET a = 3`},
@@ -775,6 +857,8 @@ func TestParse(t *testing.T) {
 		{`SET a = 3.0`},
 		{`SET a = $1`},
 		{`SET a = off`},
+		{`SET LOCAL a = 3`},
+		{`SET LOCAL a = 3, 4`},
 		{`SET TRANSACTION READ ONLY`},
 		{`SET TRANSACTION READ WRITE`},
 		{`SET TRANSACTION ISOLATION LEVEL SNAPSHOT`},
@@ -806,6 +890,10 @@ func TestParse(t *testing.T) {
 		{`TABLE a`}, // Shorthand for: SELECT * FROM a; used e.g. in CREATE VIEW v AS TABLE t
 		{`TABLE [123 AS a]`},
 
+		{`REFRESH MATERIALIZED VIEW a`},
+		{`REFRESH MATERIALIZED VIEW CONCURRENTLY a.b`},
+		{`REFRESH MATERIALIZED VIEW concurrently`},
+
 		{`TRUNCATE TABLE a`},
 		{`TRUNCATE TABLE a, b.c`},
 		{`TRUNCATE TABLE a CASCADE`},
@@ -839,6 +927,8 @@ func TestParse(t *testing.T) {
 		{`ALTER INDEX IF EXISTS a@primary RENAME TO like`},
 		{`ALTER TABLE a RENAME COLUMN c1 TO c2`},
 		{`ALTER TABLE IF EXISTS a RENAME COLUMN c1 TO c2`},
+		{`ALTER TABLE a RENAME CONSTRAINT c1 TO c2`},
+		{`ALTER TABLE IF EXISTS a RENAME CONSTRAINT c1 TO c2`},
 
 		{`ALTER TABLE a ADD b INT, ADD CONSTRAINT a_idx UNIQUE (a)`},
 		{`ALTER TABLE a ADD IF NOT EXISTS b INT, ADD CONSTRAINT a_idx UNIQUE (a)`},
@@ -866,12 +956,20 @@ func TestParse(t *testing.T) {
 		{`ALTER TABLE a DROP CONSTRAINT b CASCADE`},
 		{`ALTER TABLE a DROP CONSTRAINT IF EXISTS b RESTRICT`},
 		{`ALTER TABLE a VALIDATE CONSTRAINT a`},
+		{`ALTER TABLE a ALTER CONSTRAINT b`},
+		{`ALTER TABLE a ALTER CONSTRAINT b NOT DEFERRABLE INITIALLY IMMEDIATE`},
+		{`ALTER TABLE a ALTER CONSTRAINT b DEFERRABLE INITIALLY DEFERRED`},
 
 		{`ALTER TABLE a ALTER COLUMN b SET DEFAULT 42`},
 		{`ALTER TABLE a ALTER COLUMN b SET DEFAULT NULL`},
 		{`ALTER TABLE a ALTER COLUMN b DROP DEFAULT`},
 		{`ALTER TABLE a ALTER COLUMN b DROP NOT NULL`},
 		{`ALTER TABLE a ALTER b DROP NOT NULL`},
+		{`ALTER TABLE a ALTER COLUMN b SET NOT NULL`},
+		{`ALTER TABLE a ALTER b SET NOT NULL`},
+		{`ALTER TABLE a ALTER COLUMN b SET DATA TYPE INT8`},
+		{`ALTER TABLE a ALTER b SET DATA TYPE STRING(100)`},
+		{`ALTER TABLE a ALTER COLUMN b SET DATA TYPE INT8 USING b::INT8 * 2`},
 
 		{`COPY t FROM STDIN`},
 		{`COPY t (a, b, c) FROM STDIN`},
@@ -914,6 +1012,8 @@ func TestParse(t *testing.T) {
 		{`ALTER SEQUENCE a INCREMENT BY 5 START WITH 1000`},
 		{`ALTER SEQUENCE IF EXISTS a INCREMENT BY 5 START WITH 1000`},
 		{`ALTER SEQUENCE IF EXISTS a NO CYCLE CACHE 1`},
+		{`ALTER SEQUENCE a AS INT4 OWNED BY b.c`},
+		{`ALTER SEQUENCE a OWNED BY NONE`},
 
 		{`EXPERIMENTAL SCRUB DATABASE x`},
 		{`EXPERIMENTAL SCRUB DATABASE x AS OF SYSTEM TIME 1`},
@@ -984,6 +1084,12 @@ func TestParse2(t *testing.T) {
 			`CREATE DATABASE a TEMPLATE = 'template0'`},
 		{`CREATE DATABASE a TEMPLATE = invalid`,
 			`CREATE DATABASE a TEMPLATE = 'invalid'`},
+		{`ALTER TABLE a ALTER COLUMN b TYPE INT8`,
+			`ALTER TABLE a ALTER COLUMN b SET DATA TYPE INT8`},
+		{`CREATE TABLE a (b INT, c TEXT, FOREIGN KEY (b, c) REFERENCES other MATCH SIMPLE)`,
+			`CREATE TABLE a (b INT, c TEXT, FOREIGN KEY (b, c) REFERENCES other)`},
+		{`ALTER TABLE a ALTER b TYPE DECIMAL(10,2) USING b::DECIMAL`,
+			`ALTER TABLE a ALTER b SET DATA TYPE DECIMAL(10,2) USING b::DECIMAL`},
 		{`CREATE TABLE a (b INT, UNIQUE INDEX foo (b))`,
 			`CREATE TABLE a (b INT, CONSTRAINT foo UNIQUE (b))`},
 		{`CREATE TABLE a (b INT, UNIQUE INDEX foo (b) INTERLEAVE IN PARENT c (d))`,
@@ -991,6 +1097,10 @@ func TestParse2(t *testing.T) {
 		{`CREATE TABLE a (UNIQUE INDEX (b) PARTITION BY LIST (c) (PARTITION d VALUES IN (1)))`,
 			`CREATE TABLE a (UNIQUE (b) PARTITION BY LIST (c) (PARTITION d VALUES IN (1)))`},
 		{`CREATE INDEX ON a (b) COVERING (c)`, `CREATE INDEX ON a (b) STORING (c)`},
+		{`CREATE FUNCTION f(x INT) RETURNS INT AS 'SELECT x' LANGUAGE sql`,
+			`CREATE FUNCTION f(x INT) RETURNS INT LANGUAGE SQL AS 'SELECT x'`},
+		{`CREATE INDEX ON a ((lower(b)))`, `CREATE INDEX ON a (lower(b))`},
+		{`CREATE INDEX ON a (CURRENT_DATE)`, `CREATE INDEX ON a (current_date())`},
 
 		{`SELECT TIMESTAMP WITHOUT TIME ZONE 'foo'`, `SELECT TIMESTAMP 'foo'`},
 		{`SELECT CAST('foo' AS TIMESTAMP WITHOUT TIME ZONE)`, `SELECT CAST('foo' AS TIMESTAMP)`},
@@ -1124,6 +1234,14 @@ func TestParse2(t *testing.T) {
 			`SELECT a FROM t LIMIT 2 * a OFFSET b`},
 		{`SELECT a FROM t FETCH FIRST (2 * a) ROWS ONLY OFFSET b`,
 			`SELECT a FROM t LIMIT 2 * a OFFSET b`},
+		// The locking clause may precede the LIMIT clause, but is always
+		// output last.
+		{`SELECT a FROM t FOR UPDATE LIMIT 1`,
+			`SELECT a FROM t LIMIT 1 FOR UPDATE`},
+		{`SELECT a FROM t ORDER BY a FOR SHARE OFFSET 2`,
+			`SELECT a FROM t ORDER BY a OFFSET 2 FOR SHARE`},
+		{`SELECT a FROM t FOR READ ONLY`,
+			`SELECT a FROM t`},
 		// Double negation. See #1800.
 		{`SELECT *,-/* comment */-5`,
 			`SELECT *, -(-5)`},
@@ -1166,6 +1284,22 @@ func TestParse2(t *testing.T) {
 			`SET timezone = DEFAULT`},
 		{`SET TIME ZONE LOCAL`,
 			`SET timezone = 'local'`},
+		{`SELECT a AT TIME ZONE 'UTC'`,
+			`SELECT timezone('UTC', a)`},
+		{`SELECT a + b AT TIME ZONE c`,
+			`SELECT a + timezone(c, b)`},
+		{`SELECT a, GROUPING(a) FROM t GROUP BY ROLLUP(a)`,
+			`SELECT a, grouping(a) FROM t GROUP BY ROLLUP (a)`},
+		{`SELECT a FROM t GROUP BY GROUPING SETS((a), (), (a,b))`,
+			`SELECT a FROM t GROUP BY GROUPING SETS ((a), (), (a, b))`},
+		{`SELECT a::TIMESTAMP AT TIME ZONE 'UTC' AT TIME ZONE INTERVAL '1h'`,
+			`SELECT timezone('1h', timezone('UTC', a::TIMESTAMP))`},
+		{`SET LOCAL TIME ZONE 'Europe/Rome'`,
+			`SET LOCAL timezone = 'Europe/Rome'`},
+		{`SET LOCAL SCHEMA 'public'`,
+			`SET LOCAL search_path = 'public'`},
+		{`SET LOCAL statement_timeout TO '10s'`,
+			`SET LOCAL statement_timeout = '10s'`},
 		{`SET TIME ZONE pst8pdt`,
 			`SET timezone = 'pst8pdt'`},
 		{`SET TIME ZONE "Europe/Rome"`,
@@ -1300,6 +1434,10 @@ func TestParse2(t *testing.T) {
 
 		{`SHOW NAMES`, `SHOW client_encoding`},
 
+		{`CREATE TEMPORARY TABLE a (b INT)`, `CREATE TEMP TABLE a (b INT)`},
+		{`CREATE TEMPORARY TABLE IF NOT EXISTS a AS SELECT 1`, `CREATE TEMP TABLE IF NOT EXISTS a AS SELECT 1`},
+		{`DISCARD TEMPORARY`, `DISCARD TEMP`},
+
 		{`SHOW TRANSACTION ISOLATION LEVEL`, `SHOW transaction_isolation`},
 		{`SHOW TRANSACTION PRIORITY`, `SHOW transaction_priority`},
 
@@ -1485,6 +1623,34 @@ SET TIME ZONE INTERVAL 'foobar'
 		{`SELECT INTERVAL 'foo'`, `could not parse "foo" as type interval: interval: missing unit at position 0: "foo" at or near "EOF"
 SELECT INTERVAL 'foo'
                      ^
+`},
+		{`CREATE SEQUENCE a OWNED BY b`, `invalid OWNED BY option: specify OWNED BY table.column or OWNED BY NONE at or near "EOF"
+CREATE SEQUENCE a OWNED BY b
+                            ^
+`},
+		{`SELECT avg(1) OVER (ROWS UNBOUNDED FOLLOWING) FROM t`, `frame start cannot be UNBOUNDED FOLLOWING at or near "following"
+SELECT avg(1) OVER (ROWS UNBOUNDED FOLLOWING) FROM t
+                                   ^
+`},
+		{`SELECT avg(1) OVER (ROWS 1 FOLLOWING) FROM t`, `frame starting from following row cannot end with current row at or near "following"
+SELECT avg(1) OVER (ROWS 1 FOLLOWING) FROM t
+                           ^
+`},
+		{`SELECT avg(1) OVER (ROWS BETWEEN UNBOUNDED FOLLOWING AND UNBOUNDED FOLLOWING) FROM t`, `frame start cannot be UNBOUNDED FOLLOWING at or near "following"
+SELECT avg(1) OVER (ROWS BETWEEN UNBOUNDED FOLLOWING AND UNBOUNDED FOLLOWING) FROM t
+                                                                   ^
+`},
+		{`SELECT avg(1) OVER (ROWS BETWEEN CURRENT ROW AND UNBOUNDED PRECEDING) FROM t`, `frame end cannot be UNBOUNDED PRECEDING at or near "preceding"
+SELECT avg(1) OVER (ROWS BETWEEN CURRENT ROW AND UNBOUNDED PRECEDING) FROM t
+                                                           ^
+`},
+		{`SELECT avg(1) OVER (ROWS BETWEEN CURRENT ROW AND 1 PRECEDING) FROM t`, `frame starting from current row cannot have preceding rows at or near "preceding"
+SELECT avg(1) OVER (ROWS BETWEEN CURRENT ROW AND 1 PRECEDING) FROM t
+                                                   ^
+`},
+		{`SELECT avg(1) OVER (ROWS BETWEEN 1 FOLLOWING AND CURRENT ROW) FROM t`, `frame starting from following row cannot have preceding rows at or near "row"
+SELECT avg(1) OVER (ROWS BETWEEN 1 FOLLOWING AND CURRENT ROW) FROM t
+                                                         ^
 `},
 		{`SELECT 1 /* hello`, `unterminated comment
 SELECT 1 /* hello
@@ -1537,6 +1703,10 @@ CREATE TABLE test (
   foo INT FAMILY a FAMILY b
 )
 ^
+`},
+		{`SELECT mode(DISTINCT a) WITHIN GROUP (ORDER BY a) FROM t`, `cannot use DISTINCT with WITHIN GROUP at or near "from"
+SELECT mode(DISTINCT a) WITHIN GROUP (ORDER BY a) FROM t
+                                                  ^
 `},
 		{`SELECT family FROM test`, `syntax error at or near "from"
 SELECT family FROM test
@@ -1809,20 +1979,6 @@ UPDATE foo SET a.b = 1
                  ^
 HINT: See: https://github.com/cockroachdb/cockroach/issues/8318`,
 		},
-		{
-			`SELECT * FROM ab, LATERAL (SELECT * FROM kv)`,
-			`unimplemented at or near "EOF"
-SELECT * FROM ab, LATERAL (SELECT * FROM kv)
-                                            ^
-HINT: See: https://github.com/cockroachdb/cockroach/issues/24560`,
-		},
-		{
-			`SELECT * FROM ab, LATERAL foo(a)`,
-			`unimplemented at or near "EOF"
-SELECT * FROM ab, LATERAL foo(a)
-                                ^
-HINT: See: https://github.com/cockroachdb/cockroach/issues/24560`,
-		},
 	}
 	for _, d := range testData {
 		_, err := Parse(d.sql)
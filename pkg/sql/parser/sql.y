@@ -192,6 +192,12 @@ func (u *sqlSymUnion) colDef() *tree.ColumnTableDef {
 func (u *sqlSymUnion) constraintDef() tree.ConstraintTableDef {
     return u.val.(tree.ConstraintTableDef)
 }
+func (u *sqlSymUnion) constraintAttribute() tree.ConstraintAttribute {
+    return u.val.(tree.ConstraintAttribute)
+}
+func (u *sqlSymUnion) constraintAttributes() []tree.ConstraintAttribute {
+    return u.val.([]tree.ConstraintAttribute)
+}
 func (u *sqlSymUnion) tblDef() tree.TableDef {
     return u.val.(tree.TableDef)
 }
@@ -228,6 +234,12 @@ func (u *sqlSymUnion) colTypes() []coltypes.T {
 func (u *sqlSymUnion) int64() int64 {
     return u.val.(int64)
 }
+func (u *sqlSymUnion) funcParam() tree.FuncParam {
+    return u.val.(tree.FuncParam)
+}
+func (u *sqlSymUnion) funcParams() tree.FuncParams {
+    return u.val.(tree.FuncParams)
+}
 func (u *sqlSymUnion) seqOpt() tree.SequenceOption {
     return u.val.(tree.SequenceOption)
 }
@@ -303,6 +315,18 @@ func (u *sqlSymUnion) privilegeList() privilege.List {
 func (u *sqlSymUnion) onConflict() *tree.OnConflict {
     return u.val.(*tree.OnConflict)
 }
+func (u *sqlSymUnion) lockingClause() tree.LockingClause {
+    return u.val.(tree.LockingClause)
+}
+func (u *sqlSymUnion) lockingItem() *tree.LockingItem {
+    return u.val.(*tree.LockingItem)
+}
+func (u *sqlSymUnion) lockingStrength() tree.LockingStrength {
+    return u.val.(tree.LockingStrength)
+}
+func (u *sqlSymUnion) lockingWaitPolicy() tree.LockingWaitPolicy {
+    return u.val.(tree.LockingWaitPolicy)
+}
 func (u *sqlSymUnion) orderBy() tree.OrderBy {
     return u.val.(tree.OrderBy)
 }
@@ -381,6 +405,15 @@ func (u *sqlSymUnion) windowDef() *tree.WindowDef {
 func (u *sqlSymUnion) window() tree.Window {
     return u.val.(tree.Window)
 }
+func (u *sqlSymUnion) windowFrame() *tree.WindowFrame {
+    return u.val.(*tree.WindowFrame)
+}
+func (u *sqlSymUnion) windowFrameBounds() tree.WindowFrameBounds {
+    return u.val.(tree.WindowFrameBounds)
+}
+func (u *sqlSymUnion) windowFrameBound() *tree.WindowFrameBound {
+    return u.val.(*tree.WindowFrameBound)
+}
 func (u *sqlSymUnion) op() tree.Operator {
     return u.val.(tree.Operator)
 }
@@ -408,6 +441,9 @@ func (u *sqlSymUnion) referenceAction() tree.ReferenceAction {
 func (u *sqlSymUnion) referenceActions() tree.ReferenceActions {
     return u.val.(tree.ReferenceActions)
 }
+func (u *sqlSymUnion) compositeKeyMatchMethod() tree.CompositeKeyMatchMethod {
+    return u.val.(tree.CompositeKeyMatchMethod)
+}
 
 func (u *sqlSymUnion) scrubOptions() tree.ScrubOptions {
     return u.val.(tree.ScrubOptions)
@@ -456,14 +492,14 @@ func newNameFromStr(s string) *tree.Name {
 %token <str>   CACHE CANCEL CASCADE CASE CAST CHAR
 %token <str>   CHARACTER CHARACTERISTICS CHECK
 %token <str>   CLUSTER COALESCE COLLATE COLLATION COLUMN COLUMNS COMMENT COMMIT
-%token <str>   COMMITTED COMPACT CONCAT CONFIGURATION CONFIGURATIONS CONFIGURE
+%token <str>   COMMITTED COMPACT CONCAT CONCURRENTLY CONFIGURATION CONFIGURATIONS CONFIGURE
 %token <str>   CONFLICT CONSTRAINT CONSTRAINTS CONTAINS COPY COVERING CREATE
 %token <str>   CROSS CSV CUBE CURRENT CURRENT_CATALOG CURRENT_DATE CURRENT_SCHEMA
 %token <str>   CURRENT_ROLE CURRENT_TIME CURRENT_TIMESTAMP
 %token <str>   CURRENT_USER CYCLE
 
 %token <str>   DATA DATABASE DATABASES DATE DAY DEC DECIMAL DEFAULT
-%token <str>   DEALLOCATE DEFERRABLE DELETE DESC
+%token <str>   DEALLOCATE DEFERRABLE DEFERRED DELETE DESC
 %token <str>   DISCARD DISTINCT DO DOUBLE DROP
 
 %token <str>   ELSE EMIT ENCODING END ESCAPE EXCEPT
@@ -474,13 +510,14 @@ func newNameFromStr(s string) *tree.Name {
 
 %token <str>   FALSE FAMILY FETCH FETCHVAL FETCHTEXT FETCHVAL_PATH FETCHTEXT_PATH FILTER
 %token <str>   FIRST FLOAT FLOAT4 FLOAT8 FLOORDIV FOLLOWING FOR FORCE_INDEX FOREIGN FROM FULL
+%token <str>   FUNCTION
 
 %token <str>   GIN GRANT GRANTS GREATEST GROUP GROUPING
 
 %token <str>   HAVING HIGH HISTOGRAM HOUR
 
 
-%token <str>   IMPORT INCREMENT INCREMENTAL IF IFNULL ILIKE IN
+%token <str>   IMMEDIATE IMPORT INCREMENT INCREMENTAL IF IFNULL ILIKE IN
 %token <str>   INET INET_CONTAINED_BY_OR_EQUALS INET_CONTAINS_OR_CONTAINED_BY
 %token <str>   INET_CONTAINS_OR_EQUALS INDEX INDEXES INJECT INTERLEAVE INITIALLY
 %token <str>   INNER INSERT INT INT2VECTOR INT2 INT4 INT8 INT64 INTEGER
@@ -490,14 +527,14 @@ func newNameFromStr(s string) *tree.Name {
 
 %token <str>   KEY KEYS KV
 
-%token <str>   LATERAL LC_CTYPE LC_COLLATE
+%token <str>   LANGUAGE LATERAL LC_CTYPE LC_COLLATE
 %token <str>   LEADING LEAST LEFT LESS LEVEL LIKE LIMIT LIST LOCAL
-%token <str>   LOCALTIME LOCALTIMESTAMP LOW LSHIFT
+%token <str>   LOCALTIME LOCALTIMESTAMP LOCKED LOW LSHIFT
 
-%token <str>   MATCH MINVALUE MAXVALUE MINUTE MONTH
+%token <str>   MATCH MATERIALIZED MINVALUE MAXVALUE MINUTE MONTH
 
 %token <str>   NAN NAME NAMES NATURAL NEXT NO NO_INDEX_JOIN NORMAL
-%token <str>   NOT NOTHING NOTNULL NULL NULLIF
+%token <str>   NOT NOTHING NOTNULL NOWAIT NULL NULLIF
 %token <str>   NULLS NUMERIC
 
 %token <str>   OF OFF OFFSET OID OIDVECTOR ON ONLY OPTION OPTIONS OR
@@ -508,16 +545,16 @@ func newNameFromStr(s string) *tree.Name {
 
 %token <str>   QUERIES QUERY
 
-%token <str>   RANGE READ REAL RECURSIVE REF REFERENCES
+%token <str>   RANGE READ REAL RECURSIVE REF REFERENCES REFRESH
 %token <str>   REGCLASS REGPROC REGPROCEDURE REGNAMESPACE REGTYPE
 %token <str>   REMOVE_PATH RENAME REPEATABLE
-%token <str>   RELEASE RESET RESTORE RESTRICT RESUME RETURNING REVOKE RIGHT
+%token <str>   RELEASE RESET RESTORE RESTRICT RESUME RETURNING RETURNS REVOKE RIGHT
 %token <str>   ROLE ROLES ROLLBACK ROLLUP ROW ROWS RSHIFT
 
 %token <str>   SAVEPOINT SCATTER SCHEMA SCHEMAS SCRUB SEARCH SECOND SELECT SEQUENCE SEQUENCES
 %token <str>   SERIAL SERIAL2 SERIAL4 SERIAL8
-%token <str>   SERIALIZABLE SESSION SESSIONS SESSION_USER SET SETTING SETTINGS
-%token <str>   SHOW SIMILAR SIMPLE SMALLINT SMALLSERIAL SNAPSHOT SOME SPLIT SQL
+%token <str>   SERIALIZABLE SESSION SESSIONS SESSION_USER SET SETS SETTING SETTINGS
+%token <str>   SHARE SHOW SIMILAR SIMPLE SKIP SMALLINT SMALLSERIAL SNAPSHOT SOME SPLIT SQL
 
 %token <str>   START STATISTICS STATUS STDIN STRICT STRING STORE STORED STORING SUBSTRING
 %token <str>   SYMMETRIC SYNTAX SYSTEM
@@ -632,6 +669,7 @@ func newNameFromStr(s string) *tree.Name {
 %type <tree.Statement> create_view_stmt
 %type <tree.Statement> create_changefeed_stmt
 %type <tree.Statement> create_sequence_stmt
+%type <tree.Statement> create_function_stmt
 %type <tree.Statement> create_stats_stmt
 %type <tree.Statement> delete_stmt
 %type <tree.Statement> discard_stmt
@@ -645,6 +683,7 @@ func newNameFromStr(s string) *tree.Name {
 %type <tree.Statement> drop_user_stmt
 %type <tree.Statement> drop_view_stmt
 %type <tree.Statement> drop_sequence_stmt
+%type <tree.Statement> drop_function_stmt
 
 %type <tree.Statement> explain_stmt
 %type <tree.Statement> prepare_stmt
@@ -656,6 +695,7 @@ func newNameFromStr(s string) *tree.Name {
 %type <tree.Statement> insert_stmt
 %type <tree.Statement> import_stmt
 %type <tree.Statement> pause_stmt
+%type <tree.Statement> refresh_stmt
 %type <tree.Statement> release_stmt
 %type <tree.Statement> reset_stmt reset_session_stmt reset_csetting_stmt
 %type <tree.Statement> resume_stmt
@@ -720,7 +760,7 @@ func newNameFromStr(s string) *tree.Name {
 %type <tree.SelectStatement> select_clause select_with_parens simple_select values_clause table_clause simple_select_clause
 %type <tree.SelectStatement> set_operation
 
-%type <empty> alter_using
+%type <tree.Expr> alter_using
 %type <tree.Expr> alter_column_default
 %type <tree.Direction> opt_asc_desc
 
@@ -735,6 +775,8 @@ func newNameFromStr(s string) *tree.Name {
 %type <tree.DropBehavior> opt_interleave_drop_behavior
 
 %type <tree.ValidationBehavior> opt_validate_behavior
+%type <[]tree.ConstraintAttribute> opt_constraint_attributes
+%type <tree.ConstraintAttribute> constraint_attribute
 
 %type <str> opt_template_clause opt_encoding_clause opt_lc_collate_clause opt_lc_ctype_clause
 %type <tree.Expr> opt_password
@@ -793,10 +835,19 @@ func newNameFromStr(s string) *tree.Name {
 %type <*tree.UpdateExpr> set_clause multiple_set_clause
 %type <tree.ArraySubscripts> array_subscripts
 %type <tree.GroupBy> group_clause
-%type <*tree.Limit> select_limit
+%type <tree.Exprs> group_by_list
+%type <tree.Expr> group_by_item
+%type <*tree.Limit> select_limit opt_select_limit
+%type <tree.LockingClause> for_locking_clause for_locking_items
+%type <*tree.LockingItem> for_locking_item
+%type <tree.LockingStrength> for_locking_strength
+%type <tree.LockingWaitPolicy> opt_nowait_or_skip
+%type <tree.NameList> opt_locked_rels
 %type <tree.NormalizableTableNames> relation_expr_list
 %type <tree.ReturningClause> returning_clause
 
+%type <tree.FuncParam> func_param
+%type <tree.FuncParams> func_param_list opt_func_param_list
 %type <[]tree.SequenceOption> sequence_option_list opt_sequence_option_list
 %type <tree.SequenceOption> sequence_option_elem
 
@@ -814,7 +865,7 @@ func newNameFromStr(s string) *tree.Name {
 %type <tree.DurationField> opt_interval interval_second
 %type <tree.Expr> overlay_placing
 
-%type <bool> opt_unique opt_column
+%type <bool> opt_unique opt_column opt_temp
 %type <bool> opt_using_gin
 
 %type <empty> opt_set_data
@@ -826,8 +877,7 @@ func newNameFromStr(s string) *tree.Name {
 %type <empty> first_or_next
 
 %type <tree.Statement>  insert_rest
-%type <tree.NameList> opt_conf_expr
-%type <*tree.OnConflict> on_conflict
+%type <*tree.OnConflict> on_conflict opt_conf_expr
 
 %type <tree.Statement>  begin_transaction
 %type <tree.TransactionModes> transaction_mode_list transaction_mode
@@ -888,7 +938,7 @@ func newNameFromStr(s string) *tree.Name {
 %type <tree.Expr>  var_value
 %type <tree.Exprs> var_list
 %type <tree.NameList> var_name
-%type <str>   unrestricted_name type_function_name
+%type <str>   unrestricted_name type_function_name param_name
 %type <str>   non_reserved_word
 %type <str>   non_reserved_word_or_sconst
 %type <tree.Expr>  zone_value
@@ -904,7 +954,7 @@ func newNameFromStr(s string) *tree.Name {
 %type <[]tree.NamedColumnQualification> col_qual_list
 %type <tree.NamedColumnQualification> col_qualification
 %type <tree.ColumnQualification> col_qualification_elem
-%type <empty> key_match
+%type <tree.CompositeKeyMatchMethod> key_match
 %type <tree.ReferenceActions> reference_actions
 %type <tree.ReferenceAction> reference_action reference_on_delete reference_on_update
 
@@ -915,13 +965,15 @@ func newNameFromStr(s string) *tree.Name {
 %type <[]*tree.CTE> cte_list
 %type <*tree.CTE> common_table_expr
 
-%type <empty> within_group_clause
+%type <tree.OrderBy> within_group_clause
 %type <tree.Expr> filter_clause
 %type <tree.Exprs> opt_partition_clause
 %type <tree.Window> window_clause window_definition_list
 %type <*tree.WindowDef> window_definition over_clause window_specification
 %type <str> opt_existing_window_name
-%type <empty> opt_frame_clause frame_extent frame_bound
+%type <*tree.WindowFrame> opt_frame_clause
+%type <tree.WindowFrameBounds> frame_extent
+%type <*tree.WindowFrameBound> frame_bound
 
 %type <[]tree.ColumnID> opt_tableref_col_list tableref_col_list
 
@@ -1047,6 +1099,7 @@ stmt:
   {
     $$.val = $1.slct()
   }
+| refresh_stmt      // EXTEND WITH HELP: REFRESH MATERIALIZED VIEW
 | release_stmt      // EXTEND WITH HELP: RELEASE
 | reset_stmt        // help texts in sub-rule
 | set_stmt          // help texts in sub-rule
@@ -1087,9 +1140,12 @@ alter_ddl_stmt:
 //   ALTER TABLE ... DROP [COLUMN] [IF EXISTS] <colname> [RESTRICT | CASCADE]
 //   ALTER TABLE ... DROP CONSTRAINT [IF EXISTS] <constraintname> [RESTRICT | CASCADE]
 //   ALTER TABLE ... ALTER [COLUMN] <colname> {SET DEFAULT <expr> | DROP DEFAULT}
-//   ALTER TABLE ... ALTER [COLUMN] <colname> DROP NOT NULL
+//   ALTER TABLE ... ALTER [COLUMN] <colname> {SET NOT NULL | DROP NOT NULL}
+//   ALTER TABLE ... ALTER [COLUMN] <colname> [SET DATA] TYPE <type> [USING <expr>]
 //   ALTER TABLE ... RENAME TO <newname>
 //   ALTER TABLE ... RENAME [COLUMN] <colname> TO <newname>
+//   ALTER TABLE ... RENAME CONSTRAINT <constraintname> TO <newname>
+//   ALTER TABLE ... ALTER CONSTRAINT <constraintname> [NOT DEFERRABLE] [INITIALLY IMMEDIATE]
 //   ALTER TABLE ... VALIDATE CONSTRAINT <constraintname>
 //   ALTER TABLE ... SPLIT AT <selectclause>
 //   ALTER TABLE ... SCATTER [ FROM ( <exprs...> ) TO ( <exprs...> ) ]
@@ -1357,7 +1413,10 @@ alter_table_cmd:
     $$.val = &tree.AlterTableDropNotNull{ColumnKeyword: $2.bool(), Column: tree.Name($3)}
   }
   // ALTER TABLE <name> ALTER [COLUMN] <colname> SET NOT NULL
-| ALTER opt_column column_name SET NOT NULL { return unimplemented(sqllex, "alter set non null") }
+| ALTER opt_column column_name SET NOT NULL
+  {
+    $$.val = &tree.AlterTableSetNotNull{ColumnKeyword: $2.bool(), Column: tree.Name($3)}
+  }
   // ALTER TABLE <name> DROP [COLUMN] IF EXISTS <colname> [RESTRICT|CASCADE]
 | DROP opt_column IF EXISTS column_name opt_drop_behavior
   {
@@ -1380,7 +1439,15 @@ alter_table_cmd:
   }
   // ALTER TABLE <name> ALTER [COLUMN] <colname> [SET DATA] TYPE <typename>
   //     [ USING <expression> ]
-| ALTER opt_column column_name opt_set_data TYPE typename opt_collate_clause alter_using { return unimplemented(sqllex, "alter set type") }
+| ALTER opt_column column_name opt_set_data TYPE typename opt_collate_clause alter_using
+  {
+    $$.val = &tree.AlterTableAlterColumnType{
+      ColumnKeyword: $2.bool(),
+      Column: tree.Name($3),
+      ToType: $6.colType(),
+      Using: $8.expr(),
+    }
+  }
   // ALTER TABLE <name> ADD CONSTRAINT ...
 | ADD table_constraint opt_validate_behavior
   {
@@ -1390,7 +1457,13 @@ alter_table_cmd:
     }
   }
   // ALTER TABLE <name> ALTER CONSTRAINT ...
-| ALTER CONSTRAINT constraint_name { return unimplemented(sqllex, "alter constraint") }
+| ALTER CONSTRAINT constraint_name opt_constraint_attributes
+  {
+    $$.val = &tree.AlterTableAlterConstraint{
+      Constraint: tree.Name($3),
+      Attributes: $4.constraintAttributes(),
+    }
+  }
   // ALTER TABLE <name> VALIDATE CONSTRAINT ...
 | VALIDATE CONSTRAINT constraint_name
   {
@@ -1492,13 +1565,47 @@ opt_validate_behavior:
     $$.val = tree.ValidationDefault
   }
 
+opt_constraint_attributes:
+  opt_constraint_attributes constraint_attribute
+  {
+    $$.val = append($1.constraintAttributes(), $2.constraintAttribute())
+  }
+| /* EMPTY */
+  {
+    $$.val = []tree.ConstraintAttribute(nil)
+  }
+
+constraint_attribute:
+  DEFERRABLE
+  {
+    $$.val = tree.ConstraintDeferrable
+  }
+| NOT DEFERRABLE
+  {
+    $$.val = tree.ConstraintNotDeferrable
+  }
+| INITIALLY DEFERRED
+  {
+    $$.val = tree.ConstraintInitiallyDeferred
+  }
+| INITIALLY IMMEDIATE
+  {
+    $$.val = tree.ConstraintInitiallyImmediate
+  }
+
 opt_collate_clause:
   COLLATE collation_name { return unimplementedWithIssue(sqllex, 9851) }
 | /* EMPTY */ {}
 
 alter_using:
-  USING a_expr { return unimplemented(sqllex, "alter using") }
-| /* EMPTY */ {}
+  USING a_expr
+  {
+    $$.val = $2.expr()
+  }
+| /* EMPTY */
+  {
+    $$.val = tree.Expr(nil)
+  }
 
 // %Help: BACKUP - back up data to external storage
 // %Category: CCL
@@ -1759,9 +1866,10 @@ create_ddl_stmt:
 | create_table_stmt    // EXTEND WITH HELP: CREATE TABLE
 | create_table_as_stmt // EXTEND WITH HELP: CREATE TABLE
 // Error case for both CREATE TABLE and CREATE TABLE ... AS in one
-| CREATE TABLE error   // SHOW HELP: CREATE TABLE
+| CREATE opt_temp TABLE error // SHOW HELP: CREATE TABLE
 | create_view_stmt     // EXTEND WITH HELP: CREATE VIEW
 | create_sequence_stmt // EXTEND WITH HELP: CREATE SEQUENCE
+| create_function_stmt // EXTEND WITH HELP: CREATE FUNCTION
 | create_changefeed_stmt
 
 // %Help: CREATE STATISTICS - create a new table statistic
@@ -1819,7 +1927,7 @@ delete_stmt:
 
 // %Help: DISCARD - reset the session to its initial state
 // %Category: Cfg
-// %Text: DISCARD ALL
+// %Text: DISCARD { ALL | TEMP }
 discard_stmt:
   DISCARD ALL
   {
@@ -1827,8 +1935,14 @@ discard_stmt:
   }
 | DISCARD PLANS { return unimplemented(sqllex, "discard plans") }
 | DISCARD SEQUENCES { return unimplemented(sqllex, "discard sequences") }
-| DISCARD TEMP { return unimplemented(sqllex, "discard temp") }
-| DISCARD TEMPORARY { return unimplemented(sqllex, "discard temporary") }
+| DISCARD TEMP
+  {
+    $$.val = &tree.Discard{Mode: tree.DiscardModeTemp}
+  }
+| DISCARD TEMPORARY
+  {
+    $$.val = &tree.Discard{Mode: tree.DiscardModeTemp}
+  }
 | DISCARD error // SHOW HELP: DISCARD
 
 // %Help: DROP
@@ -1848,10 +1962,11 @@ drop_ddl_stmt:
 | drop_table_stmt    // EXTEND WITH HELP: DROP TABLE
 | drop_view_stmt     // EXTEND WITH HELP: DROP VIEW
 | drop_sequence_stmt // EXTEND WITH HELP: DROP SEQUENCE
+| drop_function_stmt // EXTEND WITH HELP: DROP FUNCTION
 
 // %Help: DROP VIEW - remove a view
 // %Category: DDL
-// %Text: DROP VIEW [IF EXISTS] <tablename> [, ...] [CASCADE | RESTRICT]
+// %Text: DROP [MATERIALIZED] VIEW [IF EXISTS] <tablename> [, ...] [CASCADE | RESTRICT]
 // %SeeAlso: WEBDOCS/drop-index.html
 drop_view_stmt:
   DROP VIEW table_name_list opt_drop_behavior
@@ -1862,7 +1977,16 @@ drop_view_stmt:
   {
     $$.val = &tree.DropView{Names: $5.normalizableTableNames(), IfExists: true, DropBehavior: $6.dropBehavior()}
   }
+| DROP MATERIALIZED VIEW table_name_list opt_drop_behavior
+  {
+    $$.val = &tree.DropView{Names: $4.normalizableTableNames(), IfExists: false, DropBehavior: $5.dropBehavior(), IsMaterialized: true}
+  }
+| DROP MATERIALIZED VIEW IF EXISTS table_name_list opt_drop_behavior
+  {
+    $$.val = &tree.DropView{Names: $6.normalizableTableNames(), IfExists: true, DropBehavior: $7.dropBehavior(), IsMaterialized: true}
+  }
 | DROP VIEW error // SHOW HELP: DROP VIEW
+| DROP MATERIALIZED VIEW error // SHOW HELP: DROP VIEW
 
 // %Help: DROP SEQUENCE - remove a sequence
 // %Category: DDL
@@ -1879,6 +2003,21 @@ drop_sequence_stmt:
   }
 | DROP SEQUENCE error // SHOW HELP: DROP VIEW
 
+// %Help: DROP FUNCTION - remove a user-defined function
+// %Category: DDL
+// %Text: DROP FUNCTION [IF EXISTS] <funcname> [, ...]
+// %SeeAlso: CREATE FUNCTION
+drop_function_stmt:
+  DROP FUNCTION table_name_list
+  {
+    $$.val = &tree.DropFunction{Names: $3.normalizableTableNames(), IfExists: false}
+  }
+| DROP FUNCTION IF EXISTS table_name_list
+  {
+    $$.val = &tree.DropFunction{Names: $5.normalizableTableNames(), IfExists: true}
+  }
+| DROP FUNCTION error // SHOW HELP: DROP FUNCTION
+
 // %Help: DROP TABLE - remove a table
 // %Category: DDL
 // %Text: DROP TABLE [IF EXISTS] <tablename> [, ...] [CASCADE | RESTRICT]
@@ -1985,6 +2124,8 @@ table_name_list:
 // %Text:
 // EXPLAIN <statement>
 // EXPLAIN [( [PLAN ,] <planoptions...> )] <statement>
+// EXPLAIN ANALYZE [(DISTSQL)] <statement>
+// EXPLAIN (OPT) <statement>
 //
 // Explainable statements:
 //     SELECT, CREATE, DROP, ALTER, INSERT, UPSERT, UPDATE, DELETE,
@@ -2004,6 +2145,14 @@ explain_stmt:
   {
     $$.val = &tree.Explain{Options: $3.strs(), Statement: $5.stmt()}
   }
+| EXPLAIN ANALYZE explainable_stmt
+  {
+    $$.val = &tree.Explain{Analyze: true, Statement: $3.stmt()}
+  }
+| EXPLAIN ANALYZE '(' explain_option_list ')' explainable_stmt
+  {
+    $$.val = &tree.Explain{Analyze: true, Options: $4.strs(), Statement: $6.stmt()}
+  }
 // This second error rule is necessary, because otherwise
 // explainable_stmt also provides "selectclause := '(' error ..."  and
 // cause a help text for the select clause, which will be confusing in
@@ -2203,6 +2352,10 @@ targets:
   {
     $$.val = tree.TargetList{Databases: $2.nameList()}
   }
+| FUNCTION table_name_list
+  {
+    $$.val = tree.TargetList{Functions: $2.normalizableTableNames()}
+  }
 
 // ALL is always by itself.
 privileges:
@@ -2290,7 +2443,6 @@ set_stmt:
 | set_transaction_stmt // EXTEND WITH HELP: SET TRANSACTION
 | set_exprs_internal   { /* SKIP DOC */ }
 | use_stmt             // EXTEND WITH HELP: USE
-| SET LOCAL error { return unimplemented(sqllex, "set local") }
 
 // %Help: SCRUB - run checks against databases or tables
 // %Category: Experimental
@@ -2421,10 +2573,12 @@ set_exprs_internal:
 // %Help: SET SESSION - change a session variable
 // %Category: Cfg
 // %Text:
-// SET [SESSION] <var> { TO | = } <values...>
-// SET [SESSION] TIME ZONE <tz>
+// SET [SESSION | LOCAL] <var> { TO | = } <values...>
+// SET [SESSION | LOCAL] TIME ZONE <tz>
 // SET [SESSION] CHARACTERISTICS AS TRANSACTION ISOLATION LEVEL { SNAPSHOT | SERIALIZABLE }
 //
+// SET LOCAL only lasts until the end of the current transaction.
+//
 // %SeeAlso: SHOW SESSION, RESET, DISCARD, SHOW, SET CLUSTER SETTING, SET TRANSACTION,
 // WEBDOCS/set-vars.html
 set_session_stmt:
@@ -2436,6 +2590,12 @@ set_session_stmt:
   {
     $$.val = $2.stmt()
   }
+| SET LOCAL set_rest_more
+  {
+    setVar := $3.stmt().(*tree.SetVar)
+    setVar.Local = true
+    $$.val = setVar
+  }
 // Special form for pg compatibility:
 | SET SESSION CHARACTERISTICS AS TRANSACTION transaction_mode_list
   {
@@ -3138,8 +3298,8 @@ pause_stmt:
 // %Help: CREATE TABLE - create a new table
 // %Category: DDL
 // %Text:
-// CREATE TABLE [IF NOT EXISTS] <tablename> ( <elements...> ) [<interleave>]
-// CREATE TABLE [IF NOT EXISTS] <tablename> [( <colnames...> )] AS <source>
+// CREATE [TEMP] TABLE [IF NOT EXISTS] <tablename> ( <elements...> ) [<interleave>]
+// CREATE [TEMP] TABLE [IF NOT EXISTS] <tablename> [( <colnames...> )] AS <source>
 //
 // Table elements:
 //    <name> <type> [<qualifiers...>]
@@ -3168,55 +3328,73 @@ pause_stmt:
 // WEBDOCS/create-table.html
 // WEBDOCS/create-table-as.html
 create_table_stmt:
-  CREATE TABLE table_name '(' opt_table_elem_list ')' opt_interleave opt_partition_by
+  CREATE opt_temp TABLE table_name '(' opt_table_elem_list ')' opt_interleave opt_partition_by
   {
     $$.val = &tree.CreateTable{
-      Table: $3.normalizableTableNameFromUnresolvedName(),
+      Table: $4.normalizableTableNameFromUnresolvedName(),
+      Temporary: $2.bool(),
       IfNotExists: false,
-      Interleave: $7.interleave(),
-      Defs: $5.tblDefs(),
+      Interleave: $8.interleave(),
+      Defs: $6.tblDefs(),
       AsSource: nil,
       AsColumnNames: nil,
-      PartitionBy: $8.partitionBy(),
+      PartitionBy: $9.partitionBy(),
     }
   }
-| CREATE TABLE IF NOT EXISTS table_name '(' opt_table_elem_list ')' opt_interleave opt_partition_by
+| CREATE opt_temp TABLE IF NOT EXISTS table_name '(' opt_table_elem_list ')' opt_interleave opt_partition_by
   {
     $$.val = &tree.CreateTable{
-      Table: $6.normalizableTableNameFromUnresolvedName(),
+      Table: $7.normalizableTableNameFromUnresolvedName(),
+      Temporary: $2.bool(),
       IfNotExists: true,
-      Interleave: $10.interleave(),
-      Defs: $8.tblDefs(),
+      Interleave: $11.interleave(),
+      Defs: $9.tblDefs(),
       AsSource: nil,
       AsColumnNames: nil,
-      PartitionBy: $11.partitionBy(),
+      PartitionBy: $12.partitionBy(),
     }
   }
 
 create_table_as_stmt:
-  CREATE TABLE table_name opt_column_list AS select_stmt
+  CREATE opt_temp TABLE table_name opt_column_list AS select_stmt
   {
     $$.val = &tree.CreateTable{
-      Table: $3.normalizableTableNameFromUnresolvedName(),
+      Table: $4.normalizableTableNameFromUnresolvedName(),
+      Temporary: $2.bool(),
       IfNotExists: false,
       Interleave: nil,
       Defs: nil,
-      AsSource: $6.slct(),
-      AsColumnNames: $4.nameList(),
+      AsSource: $7.slct(),
+      AsColumnNames: $5.nameList(),
     }
   }
-| CREATE TABLE IF NOT EXISTS table_name opt_column_list AS select_stmt
+| CREATE opt_temp TABLE IF NOT EXISTS table_name opt_column_list AS select_stmt
   {
     $$.val = &tree.CreateTable{
-      Table: $6.normalizableTableNameFromUnresolvedName(),
+      Table: $7.normalizableTableNameFromUnresolvedName(),
+      Temporary: $2.bool(),
       IfNotExists: true,
       Interleave: nil,
       Defs: nil,
-      AsSource: $9.slct(),
-      AsColumnNames: $7.nameList(),
+      AsSource: $10.slct(),
+      AsColumnNames: $8.nameList(),
     }
   }
 
+opt_temp:
+  TEMP
+  {
+    $$.val = true
+  }
+| TEMPORARY
+  {
+    $$.val = true
+  }
+| /* EMPTY */
+  {
+    $$.val = false
+  }
+
 opt_table_elem_list:
   table_elem_list
 | /* EMPTY */
@@ -3452,6 +3630,7 @@ col_qualification_elem:
       Table: $2.normalizableTableNameFromUnresolvedName(),
       Col: tree.Name($3),
       Actions: $5.referenceActions(),
+      Match: $4.compositeKeyMatchMethod(),
     }
  }
 | AS '(' a_expr ')' STORED
@@ -3460,11 +3639,11 @@ col_qualification_elem:
  }
 | AS '(' a_expr ')' VIRTUAL
  {
-    return unimplemented(sqllex, "virtual computed columns")
+    $$.val = &tree.ColumnComputedDef{Expr: $3.expr(), Virtual: true}
  }
 | AS error
  {
-    sqllex.Error("syntax error: use AS ( <expr> ) STORED")
+    sqllex.Error("syntax error: use AS ( <expr> ) STORED or AS ( <expr> ) VIRTUAL")
     return 1
  }
 
@@ -3558,6 +3737,7 @@ constraint_elem:
       FromCols: $4.nameList(),
       ToCols: $8.nameList(),
       Actions: $10.referenceActions(),
+      Match: $9.compositeKeyMatchMethod(),
     }
   }
 
@@ -3595,10 +3775,22 @@ opt_column_list:
   }
 
 key_match:
-  MATCH FULL { return unimplemented(sqllex, "match full") }
-| MATCH PARTIAL { return unimplemented(sqllex, "match partial") }
-| MATCH SIMPLE { return unimplemented(sqllex, "match simple") }
-| /* EMPTY */ {}
+  MATCH SIMPLE
+  {
+    $$.val = tree.MatchSimple
+  }
+| MATCH FULL
+  {
+    $$.val = tree.MatchFull
+  }
+| MATCH PARTIAL
+  {
+    $$.val = tree.MatchPartial
+  }
+| /* EMPTY */
+  {
+    $$.val = tree.MatchSimple
+  }
 
 // We combine the update and delete actions into one value temporarily for
 // simplicity of parsing, and then break them down again in the calling
@@ -3707,6 +3899,68 @@ create_sequence_stmt:
   }
 | CREATE SEQUENCE error // SHOW HELP: CREATE SEQUENCE
 
+// %Help: CREATE FUNCTION - create a new user-defined function
+// %Category: DDL
+// %Text:
+// CREATE FUNCTION <funcname> ( [<paramname> <type> [, ...]] )
+//   RETURNS <type>
+//   LANGUAGE SQL
+//   AS '<select statement>'
+//
+// The function body is a single SELECT statement returning at most one
+// row with one column. Parameters are referenced by name or as $1, $2, ...
+//
+// %SeeAlso: DROP FUNCTION
+create_function_stmt:
+  CREATE FUNCTION db_object_name '(' opt_func_param_list ')' RETURNS typename LANGUAGE name AS SCONST
+  {
+    if $10 != "sql" {
+      return unimplemented(sqllex, "create function language " + $10)
+    }
+    $$.val = &tree.CreateFunction{
+      Name: $3.normalizableTableNameFromUnresolvedName(),
+      Params: $5.funcParams(),
+      ReturnType: $8.colType(),
+      Body: $12,
+    }
+  }
+| CREATE FUNCTION db_object_name '(' opt_func_param_list ')' RETURNS typename AS SCONST LANGUAGE name
+  {
+    if $12 != "sql" {
+      return unimplemented(sqllex, "create function language " + $12)
+    }
+    $$.val = &tree.CreateFunction{
+      Name: $3.normalizableTableNameFromUnresolvedName(),
+      Params: $5.funcParams(),
+      ReturnType: $8.colType(),
+      Body: $10,
+    }
+  }
+| CREATE FUNCTION error // SHOW HELP: CREATE FUNCTION
+
+opt_func_param_list:
+  func_param_list
+| /* EMPTY */
+  {
+    $$.val = tree.FuncParams(nil)
+  }
+
+func_param_list:
+  func_param
+  {
+    $$.val = tree.FuncParams{$1.funcParam()}
+  }
+| func_param_list ',' func_param
+  {
+    $$.val = append($1.funcParams(), $3.funcParam())
+  }
+
+func_param:
+  param_name typename
+  {
+    $$.val = tree.FuncParam{Name: tree.Name($1), Type: $2.colType()}
+  }
+
 opt_sequence_option_list:
   sequence_option_list
 | /* EMPTY */          { $$.val = []tree.SequenceOption(nil) }
@@ -3716,11 +3970,19 @@ sequence_option_list:
 | sequence_option_list sequence_option_elem  { $$.val = append($1.seqOpts(), $2.seqOpt()) }
 
 sequence_option_elem:
-  AS typename                  { return unimplemented(sqllex, "create sequence AS option") }
+  AS typename                  { $$.val = tree.SequenceOption{Name: tree.SeqOptAs, AsType: $2.colType()} }
 | CYCLE                        { /* SKIP DOC */
                                  $$.val = tree.SequenceOption{Name: tree.SeqOptCycle} }
 | NO CYCLE                     { $$.val = tree.SequenceOption{Name: tree.SeqOptNoCycle} }
-| OWNED BY column_path         { return unimplemented(sqllex, "create sequence OWNED BY option") }
+| OWNED BY column_path         { name := $3.unresolvedName()
+                                 if name.NumParts == 1 {
+                                   if name.Parts[0] != "none" {
+                                     sqllex.Error("invalid OWNED BY option: specify OWNED BY table.column or OWNED BY NONE")
+                                     return 1
+                                   }
+                                   name = nil
+                                 }
+                                 $$.val = tree.SequenceOption{Name: tree.SeqOptOwnedBy, OwnedBy: name} }
 | CACHE signed_iconst64        { /* SKIP DOC */
                                  x := $2.int64()
                                  $$.val = tree.SequenceOption{Name: tree.SeqOptCache, IntVal: &x} }
@@ -3739,6 +4001,21 @@ sequence_option_elem:
 | START WITH signed_iconst64   { x := $3.int64()
                                  $$.val = tree.SequenceOption{Name: tree.SeqOptStart, IntVal: &x, OptionalWord: true} }
 
+// %Help: REFRESH MATERIALIZED VIEW - recompute the contents of a materialized view
+// %Category: DDL
+// %Text: REFRESH MATERIALIZED VIEW [CONCURRENTLY] <viewname>
+// %SeeAlso: CREATE VIEW
+refresh_stmt:
+  REFRESH MATERIALIZED VIEW view_name
+  {
+    $$.val = &tree.RefreshMaterializedView{Name: $4.normalizableTableNameFromUnresolvedName()}
+  }
+| REFRESH MATERIALIZED VIEW CONCURRENTLY view_name
+  {
+    $$.val = &tree.RefreshMaterializedView{Name: $5.normalizableTableNameFromUnresolvedName(), Concurrently: true}
+  }
+| REFRESH error // SHOW HELP: REFRESH MATERIALIZED VIEW
+
 // %Help: TRUNCATE - empty one or more tables
 // %Category: DML
 // %Text: TRUNCATE [TABLE] <tablename> [, ...] [CASCADE | RESTRICT]
@@ -3792,8 +4069,9 @@ create_role_stmt:
 
 // %Help: CREATE VIEW - create a new view
 // %Category: DDL
-// %Text: CREATE VIEW <viewname> [( <colnames...> )] AS <source>
-// %SeeAlso: CREATE TABLE, SHOW CREATE VIEW, WEBDOCS/create-view.html
+// %Text: CREATE [MATERIALIZED] VIEW <viewname> [( <colnames...> )] AS <source>
+// %SeeAlso: CREATE TABLE, SHOW CREATE VIEW, REFRESH MATERIALIZED VIEW,
+// WEBDOCS/create-view.html
 create_view_stmt:
   CREATE VIEW view_name opt_column_list AS select_stmt
   {
@@ -3803,7 +4081,17 @@ create_view_stmt:
       AsSource: $6.slct(),
     }
   }
+| CREATE MATERIALIZED VIEW view_name opt_column_list AS select_stmt
+  {
+    $$.val = &tree.CreateView{
+      Name: $4.normalizableTableNameFromUnresolvedName(),
+      ColumnNames: $5.nameList(),
+      AsSource: $7.slct(),
+      Materialized: true,
+    }
+  }
 | CREATE VIEW error // SHOW HELP: CREATE VIEW
+| CREATE MATERIALIZED VIEW error // SHOW HELP: CREATE VIEW
 
 // TODO(a-robinson): CREATE OR REPLACE VIEW support (#2971).
 
@@ -3907,8 +4195,14 @@ index_elem:
   {
     $$.val = tree.IndexElem{Column: tree.Name($1), Direction: $3.dir()}
   }
-| func_expr_windowless opt_collate opt_asc_desc { return unimplemented(sqllex, "index_elem func expr") }
-| '(' a_expr ')' opt_collate opt_asc_desc { return unimplemented(sqllex, "index_elem a_expr") }
+| func_expr_windowless opt_collate opt_asc_desc
+  {
+    $$.val = tree.IndexElem{Expr: $1.expr(), Direction: $3.dir()}
+  }
+| '(' a_expr ')' opt_collate opt_asc_desc
+  {
+    $$.val = tree.IndexElem{Expr: $2.expr(), Direction: $5.dir()}
+  }
 
 opt_collate:
   COLLATE collation_name { return unimplementedWithIssue(sqllex, 16619) }
@@ -3963,9 +4257,21 @@ alter_rename_table_stmt:
     $$.val = &tree.RenameColumn{Table: $5.normalizableTableNameFromUnresolvedName(), Name: tree.Name($8), NewName: tree.Name($10), IfExists: true}
   }
 | ALTER TABLE relation_expr RENAME CONSTRAINT constraint_name TO constraint_name
-  { return unimplemented(sqllex, "alter table rename constraint") }
+  {
+    $$.val = &tree.AlterTable{
+      Table: $3.normalizableTableNameFromUnresolvedName(),
+      IfExists: false,
+      Cmds: tree.AlterTableCmds{&tree.AlterTableRenameConstraint{Constraint: tree.Name($6), NewName: tree.Name($8)}},
+    }
+  }
 | ALTER TABLE IF EXISTS relation_expr RENAME CONSTRAINT constraint_name TO constraint_name
-  { return unimplemented(sqllex, "alter table rename constraint") }
+  {
+    $$.val = &tree.AlterTable{
+      Table: $5.normalizableTableNameFromUnresolvedName(),
+      IfExists: true,
+      Cmds: tree.AlterTableCmds{&tree.AlterTableRenameConstraint{Constraint: tree.Name($8), NewName: tree.Name($10)}},
+    }
+  }
 
 alter_rename_view_stmt:
   ALTER VIEW relation_expr RENAME TO view_name
@@ -4296,7 +4602,7 @@ opt_equal:
 // %Text:
 // INSERT INTO <tablename> [[AS] <name>] [( <colnames...> )]
 //        <selectclause>
-//        [ON CONFLICT [( <colnames...> )] {DO UPDATE SET ... [WHERE <expr>] | DO NOTHING}]
+//        [ON CONFLICT [( <colnames...> ) | ON CONSTRAINT <name>] {DO UPDATE SET ... [WHERE <expr>] | DO NOTHING}]
 //        [RETURNING <exprs...>]
 // %SeeAlso: UPSERT, UPDATE, DELETE, WEBDOCS/insert.html
 insert_stmt:
@@ -4394,23 +4700,31 @@ insert_column_item:
 on_conflict:
   ON CONFLICT opt_conf_expr DO UPDATE SET set_clause_list where_clause
   {
-    $$.val = &tree.OnConflict{Columns: $3.nameList(), Exprs: $7.updateExprs(), Where: tree.NewWhere(tree.AstWhere, $8.expr())}
+    oc := $3.onConflict()
+    oc.Exprs = $7.updateExprs()
+    oc.Where = tree.NewWhere(tree.AstWhere, $8.expr())
+    $$.val = oc
   }
 | ON CONFLICT opt_conf_expr DO NOTHING
   {
-    $$.val = &tree.OnConflict{Columns: $3.nameList(), DoNothing: true}
+    oc := $3.onConflict()
+    oc.DoNothing = true
+    $$.val = oc
   }
 
 opt_conf_expr:
   '(' name_list ')' where_clause
   {
     // TODO(dan): Support the where_clause.
-    $$.val = $2.nameList()
+    $$.val = &tree.OnConflict{Columns: $2.nameList()}
+  }
+| ON CONSTRAINT constraint_name
+  {
+    $$.val = &tree.OnConflict{Constraint: tree.Name($3)}
   }
-| ON CONSTRAINT constraint_name { return unimplemented(sqllex, "on conflict on constraint") }
 | /* EMPTY */
   {
-    $$.val = tree.NameList(nil)
+    $$.val = &tree.OnConflict{}
   }
 
 returning_clause:
@@ -4564,6 +4878,14 @@ select_no_parens:
   {
     $$.val = &tree.Select{Select: $1.selectStmt(), OrderBy: $2.orderBy(), Limit: $3.limit()}
   }
+| select_clause opt_sort_clause for_locking_clause opt_select_limit
+  {
+    $$.val = &tree.Select{Select: $1.selectStmt(), OrderBy: $2.orderBy(), Limit: $4.limit(), Locking: $3.lockingClause()}
+  }
+| select_clause opt_sort_clause select_limit for_locking_clause
+  {
+    $$.val = &tree.Select{Select: $1.selectStmt(), OrderBy: $2.orderBy(), Limit: $3.limit(), Locking: $4.lockingClause()}
+  }
 | with_clause select_clause
   {
     $$.val = &tree.Select{With: $1.with(), Select: $2.selectStmt()}
@@ -4576,6 +4898,14 @@ select_no_parens:
   {
     $$.val = &tree.Select{With: $1.with(), Select: $2.selectStmt(), OrderBy: $3.orderBy(), Limit: $4.limit()}
   }
+| with_clause select_clause opt_sort_clause for_locking_clause opt_select_limit
+  {
+    $$.val = &tree.Select{With: $1.with(), Select: $2.selectStmt(), OrderBy: $3.orderBy(), Limit: $5.limit(), Locking: $4.lockingClause()}
+  }
+| with_clause select_clause opt_sort_clause select_limit for_locking_clause
+  {
+    $$.val = &tree.Select{With: $1.with(), Select: $2.selectStmt(), OrderBy: $3.orderBy(), Limit: $4.limit(), Locking: $5.lockingClause()}
+  }
 
 select_clause:
 // We only provide help if an open parenthesis is provided, because
@@ -4634,6 +4964,8 @@ simple_select:
 //        [ ORDER BY <expr> [ ASC | DESC ] [, ...] ]
 //        [ LIMIT { <expr> | ALL } ]
 //        [ OFFSET <expr> [ ROW | ROWS ] ]
+//        [ FOR { UPDATE | NO KEY UPDATE | SHARE | KEY SHARE } [ OF <tablename> [, ...] ]
+//              [ NOWAIT | SKIP LOCKED ] [...] ]
 // %SeeAlso: WEBDOCS/select-clause.html
 simple_select_clause:
   SELECT opt_all_clause target_list
@@ -4738,7 +5070,10 @@ with_clause:
     $$.val = &tree.With{CTEList: $2.ctes()}
   }
 | WITH_LA cte_list { return unimplemented(sqllex, "with cte_list") }
-| WITH RECURSIVE cte_list { return unimplemented(sqllex, "with recursive") }
+| WITH RECURSIVE cte_list
+  {
+    $$.val = &tree.With{Recursive: true, CTEList: $3.ctes()}
+  }
 
 cte_list:
   common_table_expr
@@ -4847,6 +5182,77 @@ sortby:
 // TODO(pmattis): Support ordering using arbitrary math ops?
 // | a_expr USING math_op {}
 
+// The locking clause acquires row-level locks on the rows read by the
+// query, for the duration of the enclosing transaction.
+for_locking_clause:
+  for_locking_items
+| FOR READ ONLY
+  {
+    $$.val = tree.LockingClause(nil)
+  }
+
+for_locking_items:
+  for_locking_item
+  {
+    $$.val = tree.LockingClause{$1.lockingItem()}
+  }
+| for_locking_items for_locking_item
+  {
+    $$.val = append($1.lockingClause(), $2.lockingItem())
+  }
+
+for_locking_item:
+  for_locking_strength opt_locked_rels opt_nowait_or_skip
+  {
+    $$.val = &tree.LockingItem{
+      Strength:   $1.lockingStrength(),
+      Targets:    $2.nameList(),
+      WaitPolicy: $3.lockingWaitPolicy(),
+    }
+  }
+
+for_locking_strength:
+  FOR UPDATE
+  {
+    $$.val = tree.ForUpdate
+  }
+| FOR NO KEY UPDATE
+  {
+    $$.val = tree.ForNoKeyUpdate
+  }
+| FOR SHARE
+  {
+    $$.val = tree.ForShare
+  }
+| FOR KEY SHARE
+  {
+    $$.val = tree.ForKeyShare
+  }
+
+opt_locked_rels:
+  /* EMPTY */
+  {
+    $$.val = tree.NameList(nil)
+  }
+| OF name_list
+  {
+    $$.val = $2.nameList()
+  }
+
+opt_nowait_or_skip:
+  /* EMPTY */
+  {
+    $$.val = tree.LockWaitBlock
+  }
+| SKIP LOCKED
+  {
+    $$.val = tree.LockWaitSkip
+  }
+| NOWAIT
+  {
+    $$.val = tree.LockWaitError
+  }
+
 select_limit:
   limit_clause offset_clause
   {
@@ -4867,6 +5273,10 @@ select_limit:
 | limit_clause
 | offset_clause
 
+opt_select_limit:
+  select_limit
+| /* EMPTY */ { $$.val = (*tree.Limit)(nil) }
+
 opt_limit_clause:
   limit_clause
 | /* EMPTY */ { $$.val = (*tree.Limit)(nil) }
@@ -4951,7 +5361,7 @@ first_or_next:
 // Each item in the group_clause list is either an expression tree or a
 // GroupingSet node of some type.
 group_clause:
-  GROUP BY expr_list
+  GROUP BY group_by_list
   {
     $$.val = tree.GroupBy($3.exprs())
   }
@@ -4960,6 +5370,35 @@ group_clause:
     $$.val = tree.GroupBy(nil)
   }
 
+group_by_list:
+  group_by_item
+  {
+    $$.val = tree.Exprs{$1.expr()}
+  }
+| group_by_list ',' group_by_item
+  {
+    $$.val = append($1.exprs(), $3.expr())
+  }
+
+group_by_item:
+  a_expr
+| '(' ')'
+  {
+    $$.val = &tree.Tuple{}
+  }
+| ROLLUP '(' expr_list ')'
+  {
+    $$.val = &tree.GroupingSet{Type: tree.Rollup, Exprs: $3.exprs()}
+  }
+| CUBE '(' expr_list ')'
+  {
+    $$.val = &tree.GroupingSet{Type: tree.Cube, Exprs: $3.exprs()}
+  }
+| GROUPING SETS '(' group_by_list ')'
+  {
+    $$.val = &tree.GroupingSet{Type: tree.GroupingSets, Exprs: $4.exprs()}
+  }
+
 having_clause:
   HAVING a_expr
   {
@@ -5127,19 +5566,28 @@ table_ref:
   {
     $$.val = &tree.AliasedTableExpr{Expr: &tree.FuncExpr{Func: $1.resolvableFuncRefFromName(), Exprs: $3.exprs()}, Ordinality: $5.bool(), As: $6.aliasClause() }
   }
-| LATERAL func_name '(' opt_expr_list ')' opt_ordinality opt_alias_clause { return unimplementedWithIssue(sqllex, 24560) }
+| LATERAL func_name '(' opt_expr_list ')' opt_ordinality opt_alias_clause
+  {
+    $$.val = &tree.AliasedTableExpr{Expr: &tree.FuncExpr{Func: $2.resolvableFuncRefFromName(), Exprs: $4.exprs()}, Ordinality: $6.bool(), Lateral: true, As: $7.aliasClause() }
+  }
 | func_name '(' error { return helpWithFunction(sqllex, $1.resolvableFuncRefFromName()) }
 | LATERAL func_name '(' error { return helpWithFunction(sqllex, $2.resolvableFuncRefFromName()) }
 | special_function opt_ordinality opt_alias_clause
   {
       $$.val = &tree.AliasedTableExpr{Expr: $1.expr().(tree.TableExpr), Ordinality: $2.bool(), As: $3.aliasClause() }
   }
-| LATERAL special_function opt_ordinality opt_alias_clause { return unimplementedWithIssue(sqllex, 24560) }
+| LATERAL special_function opt_ordinality opt_alias_clause
+  {
+    $$.val = &tree.AliasedTableExpr{Expr: $2.expr().(tree.TableExpr), Ordinality: $3.bool(), Lateral: true, As: $4.aliasClause() }
+  }
 | select_with_parens opt_ordinality opt_alias_clause
   {
     $$.val = &tree.AliasedTableExpr{Expr: &tree.Subquery{Select: $1.selectStmt()}, Ordinality: $2.bool(), As: $3.aliasClause() }
   }
-| LATERAL select_with_parens opt_ordinality opt_alias_clause { return unimplementedWithIssue(sqllex, 24560) }
+| LATERAL select_with_parens opt_ordinality opt_alias_clause
+  {
+    $$.val = &tree.AliasedTableExpr{Expr: &tree.Subquery{Select: $2.selectStmt()}, Ordinality: $3.bool(), Lateral: true, As: $4.aliasClause() }
+  }
 | joined_table
   {
     $$.val = $1.tblExpr()
@@ -5868,7 +6316,10 @@ a_expr:
   {
     $$.val = &tree.CollateExpr{Expr: $1.expr(), Locale: $3}
   }
-| a_expr AT TIME ZONE a_expr %prec AT { return unimplemented(sqllex, "at tz") }
+| a_expr AT TIME ZONE a_expr %prec AT
+  {
+    $$.val = &tree.FuncExpr{Func: tree.WrapFunction("timezone"), Exprs: tree.Exprs{$5.expr(), $1.expr()}}
+  }
   // These operators must be called out explicitly in order to make use of
   // bison's automatic operator-precedence handling. All other operator names
   // are handled by the generic productions using "OP", below; and all those
@@ -6411,8 +6862,6 @@ d_expr:
   {
     $$.val = $1.expr()
   }
-// TODO(pmattis): Support this notation?
-// | GROUPING '(' expr_list ')' { return unimplemented(sqllex) }
 
 func_application:
   func_name '(' ')'
@@ -6450,6 +6899,11 @@ func_expr:
   func_application within_group_clause filter_clause over_clause
   {
     f := $1.expr().(*tree.FuncExpr)
+    f.OrderBy = $2.orderBy()
+    if f.OrderBy != nil && f.Type == tree.DistinctFuncType {
+      sqllex.Error("cannot use DISTINCT with WITHIN GROUP")
+      return 1
+    }
     f.Filter = $3.expr()
     f.WindowDef = $4.windowDef()
     $$.val = f
@@ -6464,8 +6918,8 @@ func_expr:
 // expressions are not allowed, where needed to disambiguate the grammar
 // (e.g. in CREATE INDEX).
 func_expr_windowless:
-  func_application { return unimplemented(sqllex, "func_application") }
-| func_expr_common_subexpr { return unimplemented(sqllex, "func_expr_common_subexpr") }
+  func_application
+| func_expr_common_subexpr
 
 // Special expressions that are considered to be functions.
 func_expr_common_subexpr:
@@ -6604,12 +7058,23 @@ special_function:
     $$.val = &tree.FuncExpr{Func: tree.WrapFunction($1), Exprs: $3.exprs()}
   }
 | LEAST '(' error { return helpWithFunctionByName(sqllex, $1) }
+| GROUPING '(' expr_list ')'
+  {
+    $$.val = &tree.FuncExpr{Func: tree.WrapFunction($1), Exprs: $3.exprs()}
+  }
+| GROUPING '(' error { return helpWithFunctionByName(sqllex, $1) }
 
 
 // Aggregate decoration clauses
 within_group_clause:
-WITHIN GROUP '(' sort_clause ')' { return unimplemented(sqllex, "within group") }
-| /* EMPTY */ {}
+  WITHIN GROUP '(' sort_clause ')'
+  {
+    $$.val = $4.orderBy()
+  }
+| /* EMPTY */
+  {
+    $$.val = tree.OrderBy(nil)
+  }
 
 filter_clause:
   FILTER '(' WHERE a_expr ')'
@@ -6672,6 +7137,7 @@ window_specification:
       RefName: tree.Name($2),
       Partitions: $3.exprs(),
       OrderBy: $4.orderBy(),
+      Frame: $5.windowFrame(),
     }
   }
 
@@ -6700,29 +7166,96 @@ opt_partition_clause:
     $$.val = tree.Exprs(nil)
   }
 
-// For frame clauses, we return a tree.WindowDef, but only some fields are used:
-// frameOptions, startOffset, and endOffset.
-//
 // This is only a subset of the full SQL:2008 frame_clause grammar. We don't
 // support <window frame exclusion> yet.
 opt_frame_clause:
-  RANGE frame_extent { return unimplemented(sqllex, "frame range") }
-| ROWS frame_extent { return unimplemented(sqllex, "frame rows") }
-| /* EMPTY */ {}
+  RANGE frame_extent
+  {
+    $$.val = &tree.WindowFrame{
+      Mode: tree.RANGE,
+      Bounds: $2.windowFrameBounds(),
+    }
+  }
+| ROWS frame_extent
+  {
+    $$.val = &tree.WindowFrame{
+      Mode: tree.ROWS,
+      Bounds: $2.windowFrameBounds(),
+    }
+  }
+| /* EMPTY */
+  {
+    $$.val = (*tree.WindowFrame)(nil)
+  }
 
 frame_extent:
-  frame_bound { return unimplemented(sqllex, "frame_extent") }
-| BETWEEN frame_bound AND frame_bound { return unimplemented(sqllex, "frame_extent") }
+  frame_bound
+  {
+    startBound := $1.windowFrameBound()
+    switch {
+    case startBound.BoundType == tree.UnboundedFollowing:
+      sqllex.Error("frame start cannot be UNBOUNDED FOLLOWING")
+      return 1
+    case startBound.BoundType == tree.ValueFollowing:
+      sqllex.Error("frame starting from following row cannot end with current row")
+      return 1
+    }
+    $$.val = tree.WindowFrameBounds{StartBound: startBound}
+  }
+| BETWEEN frame_bound AND frame_bound
+  {
+    startBound := $2.windowFrameBound()
+    endBound := $4.windowFrameBound()
+    switch {
+    case startBound.BoundType == tree.UnboundedFollowing:
+      sqllex.Error("frame start cannot be UNBOUNDED FOLLOWING")
+      return 1
+    case endBound.BoundType == tree.UnboundedPreceding:
+      sqllex.Error("frame end cannot be UNBOUNDED PRECEDING")
+      return 1
+    case startBound.BoundType == tree.CurrentRow && endBound.BoundType == tree.ValuePreceding:
+      sqllex.Error("frame starting from current row cannot have preceding rows")
+      return 1
+    case startBound.BoundType == tree.ValueFollowing && endBound.BoundType == tree.ValuePreceding:
+      sqllex.Error("frame starting from following row cannot have preceding rows")
+      return 1
+    case startBound.BoundType == tree.ValueFollowing && endBound.BoundType == tree.CurrentRow:
+      sqllex.Error("frame starting from following row cannot have preceding rows")
+      return 1
+    }
+    $$.val = tree.WindowFrameBounds{StartBound: startBound, EndBound: endBound}
+  }
 
 // This is used for both frame start and frame end, with output set up on the
 // assumption it's frame start; the frame_extent productions must reject
 // invalid cases.
 frame_bound:
-  UNBOUNDED PRECEDING { return unimplemented(sqllex, "frame_bound") }
-| UNBOUNDED FOLLOWING { return unimplemented(sqllex, "frame_bound") }
-| CURRENT ROW { return unimplemented(sqllex, "frame_bound") }
-| a_expr PRECEDING { return unimplemented(sqllex, "frame_bound") }
-| a_expr FOLLOWING { return unimplemented(sqllex, "frame_bound") }
+  UNBOUNDED PRECEDING
+  {
+    $$.val = &tree.WindowFrameBound{BoundType: tree.UnboundedPreceding}
+  }
+| UNBOUNDED FOLLOWING
+  {
+    $$.val = &tree.WindowFrameBound{BoundType: tree.UnboundedFollowing}
+  }
+| CURRENT ROW
+  {
+    $$.val = &tree.WindowFrameBound{BoundType: tree.CurrentRow}
+  }
+| a_expr PRECEDING
+  {
+    $$.val = &tree.WindowFrameBound{
+      OffsetExpr: $1.expr(),
+      BoundType: tree.ValuePreceding,
+    }
+  }
+| a_expr FOLLOWING
+  {
+    $$.val = &tree.WindowFrameBound{
+      OffsetExpr: $1.expr(),
+      BoundType: tree.ValueFollowing,
+    }
+  }
 
 // Supporting nonterminals for expressions.
 
@@ -7445,6 +7978,9 @@ non_reserved_word_or_sconst:
   non_reserved_word
 | SCONST
 
+// Function parameter names.
+param_name: type_function_name
+
 // Type/function identifier --- names that can be type or function names.
 type_function_name:
   IDENT
@@ -7500,6 +8036,7 @@ unreserved_keyword:
 | COMMIT
 | COMMITTED
 | COMPACT
+| CONCURRENTLY
 | CONFLICT
 | CONFIGURATION
 | CONFIGURATIONS
@@ -7517,6 +8054,7 @@ unreserved_keyword:
 | DATE
 | DAY
 | DEALLOCATE
+| DEFERRED
 | DELETE
 | DISCARD
 | DOUBLE
@@ -7536,11 +8074,13 @@ unreserved_keyword:
 | FLOAT8
 | FOLLOWING
 | FORCE_INDEX
+| FUNCTION
 | GIN
 | GRANTS
 | HIGH
 | HISTOGRAM
 | HOUR
+| IMMEDIATE
 | IMPORT
 | INCREMENT
 | INCREMENTAL
@@ -7563,14 +8103,17 @@ unreserved_keyword:
 | KEY
 | KEYS
 | KV
+| LANGUAGE
 | LC_COLLATE
 | LC_CTYPE
 | LESS
 | LEVEL
 | LIST
 | LOCAL
+| LOCKED
 | LOW
 | MATCH
+| MATERIALIZED
 | MINUTE
 | MONTH
 | NAMES
@@ -7580,6 +8123,7 @@ unreserved_keyword:
 | NO
 | NORMAL
 | NO_INDEX_JOIN
+| NOWAIT
 | NULLS
 | OF
 | OFF
@@ -7606,6 +8150,7 @@ unreserved_keyword:
 | READ
 | RECURSIVE
 | REF
+| REFRESH
 | REGCLASS
 | REGPROC
 | REGPROCEDURE
@@ -7618,6 +8163,7 @@ unreserved_keyword:
 | RESTORE
 | RESTRICT
 | RESUME
+| RETURNS
 | REVOKE
 | ROLES
 | ROLLBACK
@@ -7643,8 +8189,11 @@ unreserved_keyword:
 | SESSION
 | SESSIONS
 | SET
+| SETS
+| SHARE
 | SHOW
 | SIMPLE
+| SKIP
 | SMALLSERIAL
 | SNAPSHOT
 | SQL
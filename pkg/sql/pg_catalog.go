@@ -31,6 +31,7 @@ import (
 	"bytes"
 
 	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/builtins"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/types"
@@ -262,10 +263,11 @@ CREATE TABLE pg_catalog.pg_auth_members (
 }
 
 var (
-	relKindTable    = tree.NewDString("r")
-	relKindIndex    = tree.NewDString("i")
-	relKindView     = tree.NewDString("v")
-	relKindSequence = tree.NewDString("S")
+	relKindTable            = tree.NewDString("r")
+	relKindIndex            = tree.NewDString("i")
+	relKindView             = tree.NewDString("v")
+	relKindMaterializedView = tree.NewDString("m")
+	relKindSequence         = tree.NewDString("S")
 
 	relPersistencePermanent = tree.NewDString("p")
 )
@@ -309,7 +311,9 @@ CREATE TABLE pg_catalog.pg_class (
 			func(db *sqlbase.DatabaseDescriptor, scName string, table *sqlbase.TableDescriptor) error {
 				// The only difference between tables, views and sequences is the relkind column.
 				relKind := relKindTable
-				if table.IsView() {
+				if table.MaterializedView() {
+					relKind = relKindMaterializedView
+				} else if table.IsView() {
 					relKind = relKindView
 				} else if table.IsSequence() {
 					relKind = relKindSequence
@@ -455,10 +459,6 @@ var (
 	fkMatchTypeFull    = tree.NewDString("f")
 	fkMatchTypePartial = tree.NewDString("p")
 	fkMatchTypeSimple  = tree.NewDString("s")
-
-	// Avoid unused warning for constants.
-	_ = fkMatchTypeFull
-	_ = fkMatchTypePartial
 )
 
 // See: https://www.postgresql.org/docs/9.6/static/catalog-pg-constraint.html.
@@ -549,7 +549,14 @@ CREATE TABLE pg_catalog.pg_constraint (
 					confrelid = h.TableOid(referencedDB, tree.PublicSchema, con.ReferencedTable)
 					confupdtype = fkActionNone
 					confdeltype = fkActionNone
-					confmatchtype = fkMatchTypeSimple
+					switch con.FK.Match {
+					case sqlbase.ForeignKeyReference_FULL:
+						confmatchtype = fkMatchTypeFull
+					case sqlbase.ForeignKeyReference_PARTIAL:
+						confmatchtype = fkMatchTypePartial
+					default:
+						confmatchtype = fkMatchTypeSimple
+					}
 					if conkey, err = colIDArrayToDatum(con.Index.ColumnIDs); err != nil {
 						return err
 					}
@@ -1015,11 +1022,19 @@ func indexDefFromDescriptor(
 		Columns: make(tree.IndexElemList, len(index.ColumnNames)),
 		Storing: make(tree.NameList, len(index.StoreColumnNames)),
 	}
+	exprs := indexColumnExprs(table, index)
 	for i, name := range index.ColumnNames {
 		elem := tree.IndexElem{
 			Column:    tree.Name(name),
 			Direction: tree.Ascending,
 		}
+		if e, ok := exprs[index.ColumnIDs[i]]; ok {
+			expr, err := parser.ParseExpr(e)
+			if err != nil {
+				return "", err
+			}
+			elem.Column, elem.Expr = "", expr
+		}
 		if index.ColumnDirections[i] == sqlbase.IndexDescriptor_DESC {
 			elem.Direction = tree.Descending
 		}
@@ -1971,7 +1986,8 @@ CREATE TABLE pg_catalog.pg_views (
 		// because it does not distinguish views in separate databases.
 		return forEachTableDesc(ctx, p, prefix, hideVirtual, /*virtual schemas do not have views*/
 			func(db *sqlbase.DatabaseDescriptor, scName string, desc *sqlbase.TableDescriptor) error {
-				if !desc.IsView() {
+				// Materialized views are not listed, as in postgres.
+				if !desc.IsView() || desc.MaterializedView() {
 					return nil
 				}
 				// Note that the view query printed will not include any column aliases
@@ -88,6 +88,7 @@ const (
 	CodeNonstandardUseOfEscapeCharacterError       = "22P06"
 	CodeInvalidIndicatorParameterValueError        = "22010"
 	CodeInvalidParameterValueError                 = "22023"
+	CodeInvalidPrecedingOrFollowingSizeError       = "22013"
 	CodeInvalidRegularExpressionError              = "2201B"
 	CodeInvalidRowCountInLimitClauseError          = "2201W"
 	CodeInvalidRowCountInResultOffsetClauseError   = "2201X"
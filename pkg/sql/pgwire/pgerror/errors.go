@@ -55,6 +55,18 @@ func NewErrorf(code string, format string, args ...interface{}) *Error {
 	return NewErrorWithDepthf(1, code, format, args...)
 }
 
+// Wrapf annotates err with a formatted message. If err carries a pg code
+// (see GetPGCause), it is wrapped with errors.Wrapf and keeps that code.
+// Otherwise, since an Error cannot hold a cause, the result is an Error with
+// the given code whose message is the annotation followed by the message of
+// err.
+func Wrapf(err error, code string, format string, args ...interface{}) error {
+	if _, ok := GetPGCause(err); ok {
+		return errors.Wrapf(err, format, args...)
+	}
+	return NewErrorWithDepthf(1, code, "%s: %v", fmt.Sprintf(format, args...), err)
+}
+
 // NewDangerousStatementErrorf creates a new Error for "rejected dangerous statements".
 func NewDangerousStatementErrorf(format string, args ...interface{}) *Error {
 	var buf bytes.Buffer
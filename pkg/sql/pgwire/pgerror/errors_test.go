@@ -65,4 +65,22 @@ func TestPGError(t *testing.T) {
 		t.Fatal("cannot find pgerror")
 	}
 	checkErr(pErr, expected)
+
+	// Test Wrapf.
+	err = Wrapf(errors.New(msg), code, "%s", prefix)
+	pErr, ok = GetPGCause(err)
+	if !ok {
+		t.Fatal("cannot find pgerror")
+	}
+	checkErr(pErr, expected)
+
+	err = Wrapf(pErr, "other", "wrap")
+	if err.Error() != "wrap: "+expected {
+		t.Fatalf("got: %q\nwant: %q", err.Error(), "wrap: "+expected)
+	}
+	pErr, ok = GetPGCause(err)
+	if !ok {
+		t.Fatal("cannot find pgerror")
+	}
+	checkErr(pErr, expected)
 }
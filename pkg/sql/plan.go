@@ -158,6 +158,7 @@ type planNodeFastPath interface {
 }
 
 var _ planNode = &alterIndexNode{}
+var _ planNode = &applyJoinNode{}
 var _ planNode = &alterTableNode{}
 var _ planNode = &alterSequenceNode{}
 var _ planNode = &createDatabaseNode{}
@@ -165,6 +166,7 @@ var _ planNode = &createIndexNode{}
 var _ planNode = &createTableNode{}
 var _ planNode = &createViewNode{}
 var _ planNode = &createSequenceNode{}
+var _ planNode = &createFunctionNode{}
 var _ planNode = &createStatsNode{}
 var _ planNode = &delayedNode{}
 var _ planNode = &deleteNode{}
@@ -174,8 +176,10 @@ var _ planNode = &dropIndexNode{}
 var _ planNode = &dropTableNode{}
 var _ planNode = &dropViewNode{}
 var _ planNode = &dropSequenceNode{}
+var _ planNode = &dropFunctionNode{}
 var _ planNode = &zeroNode{}
 var _ planNode = &unaryNode{}
+var _ planNode = &explainAnalyzeNode{}
 var _ planNode = &explainDistSQLNode{}
 var _ planNode = &explainPlanNode{}
 var _ planNode = &showTraceNode{}
@@ -188,6 +192,7 @@ var _ planNode = &joinNode{}
 var _ planNode = &limitNode{}
 var _ planNode = &ordinalityNode{}
 var _ planNode = &testingRelocateNode{}
+var _ planNode = &refreshViewNode{}
 var _ planNode = &renderNode{}
 var _ planNode = &scanNode{}
 var _ planNode = &scatterNode{}
@@ -196,6 +201,8 @@ var _ planNode = &showFingerprintsNode{}
 var _ planNode = &sortNode{}
 var _ planNode = &splitNode{}
 var _ planNode = &unionNode{}
+var _ planNode = &recursiveCTENode{}
+var _ planNode = &scanBufferNode{}
 var _ planNode = &updateNode{}
 var _ planNode = &upsertNode{}
 var _ planNode = &valueGenerator{}
@@ -264,11 +271,22 @@ type planTop struct {
 	// to the planNodes that represent their source.
 	cteNameEnvironment cteNameEnvironment
 
+	// lateralEnvironment collects the left sides of the apply joins whose
+	// LATERAL right side is being planned. Their columns are visible to the
+	// right side.
+	lateralEnvironment lateralEnvironment
+
 	// hasStar collects whether any star expansion has occurred during
 	// logical plan construction. This is used by CREATE VIEW until
 	// #10028 is addressed.
 	hasStar bool
 
+	// hasUserDefinedFunction collects whether any call to a user-defined
+	// function was inlined during logical plan construction. This is used
+	// by CREATE VIEW, since views do not record their dependencies on
+	// functions.
+	hasUserDefinedFunction bool
+
 	// subqueryPlans contains all the sub-query plans.
 	subqueryPlans []subquery
 
@@ -495,7 +513,7 @@ func startExec(params runParams, plan planNode) error {
 	o := planObserver{
 		enterNode: func(ctx context.Context, _ string, p planNode) (bool, error) {
 			switch p.(type) {
-			case *explainPlanNode, *explainDistSQLNode:
+			case *explainPlanNode, *explainDistSQLNode, *explainAnalyzeNode:
 				// Do not recurse: we're not starting the plan if we just show its structure with EXPLAIN.
 				return false, nil
 			case *showTraceNode:
@@ -503,6 +521,14 @@ func startExec(params runParams, plan planNode) error {
 				return false, nil
 			case *createStatsNode:
 				return false, errors.Errorf("statistics can only be created via DistSQL")
+			case *recursiveCTENode:
+				// The recursive term can only be started once the working table
+				// has been populated; recursiveCTENode starts its children itself.
+				return false, nil
+			case *applyJoinNode:
+				// The right side is re-planned and started for every row of the
+				// left side; applyJoinNode starts its children itself.
+				return false, nil
 			}
 			return true, nil
 		},
@@ -627,6 +653,16 @@ func (p *planner) newPlan(
 		return plan, err
 	}
 
+	// Replace the calls to user-defined functions by their body. Schema
+	// changes are excluded, since they may persist the expressions they
+	// contain and those must keep referring to the functions.
+	if !canModifySchema {
+		var err error
+		if stmt, err = p.inlineFunctions(ctx, stmt); err != nil {
+			return nil, err
+		}
+	}
+
 	switch n := stmt.(type) {
 	case *tree.AlterIndex:
 		return p.AlterIndex(ctx, n)
@@ -646,6 +682,8 @@ func (p *planner) newPlan(
 		return p.Scrub(ctx, n)
 	case *tree.CreateDatabase:
 		return p.CreateDatabase(ctx, n)
+	case *tree.CreateFunction:
+		return p.CreateFunction(ctx, n)
 	case *tree.CreateIndex:
 		return p.CreateIndex(ctx, n)
 	case *tree.CreateTable:
@@ -666,6 +704,8 @@ func (p *planner) newPlan(
 		return p.Discard(ctx, n)
 	case *tree.DropDatabase:
 		return p.DropDatabase(ctx, n)
+	case *tree.DropFunction:
+		return p.DropFunction(ctx, n)
 	case *tree.DropIndex:
 		return p.DropIndex(ctx, n)
 	case *tree.DropTable:
@@ -690,6 +730,8 @@ func (p *planner) newPlan(
 		return p.PauseJob(ctx, n)
 	case *tree.TestingRelocate:
 		return p.TestingRelocate(ctx, n)
+	case *tree.RefreshMaterializedView:
+		return p.RefreshMaterializedView(ctx, n)
 	case *tree.RenameColumn:
 		return p.RenameColumn(ctx, n)
 	case *tree.RenameDatabase:
@@ -707,8 +749,8 @@ func (p *planner) newPlan(
 	case *tree.Select:
 		return p.Select(ctx, n, desiredTypes)
 	case *tree.SelectClause:
-		return p.SelectClause(ctx, n, nil /* orderBy */, nil /* limit */, nil, /* with */
-			desiredTypes, publicColumns)
+		return p.SelectClause(ctx, n, nil /* orderBy */, nil /* limit */, nil, /* locking */
+			nil /* with */, desiredTypes, publicColumns)
 	case *tree.SetClusterSetting:
 		return p.SetClusterSetting(ctx, n)
 	case *tree.SetZoneConfig:
@@ -844,8 +886,8 @@ func (p *planner) doPrepare(ctx context.Context, stmt tree.Statement) (planNode,
 	case *tree.Select:
 		return p.Select(ctx, n, nil)
 	case *tree.SelectClause:
-		return p.SelectClause(ctx, n, nil /* orderBy */, nil /* limit */, nil, /* with */
-			nil /* desiredTypes */, publicColumns)
+		return p.SelectClause(ctx, n, nil /* orderBy */, nil /* limit */, nil, /* locking */
+			nil /* with */, nil /* desiredTypes */, publicColumns)
 	case *tree.SetClusterSetting:
 		return p.SetClusterSetting(ctx, n)
 	case *tree.SetVar:
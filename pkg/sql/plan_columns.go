@@ -57,6 +57,8 @@ func getPlanColumns(plan planNode, mut bool) sqlbase.ResultColumns {
 		return n.header
 	case *joinNode:
 		return n.columns
+	case *applyJoinNode:
+		return n.columns
 	case *ordinalityNode:
 		return n.columns
 	case *renderNode:
@@ -67,6 +69,10 @@ func getPlanColumns(plan planNode, mut bool) sqlbase.ResultColumns {
 		return n.columns
 	case *unionNode:
 		return n.columns
+	case *recursiveCTENode:
+		return n.columns
+	case *scanBufferNode:
+		return n.columns
 	case *valueGenerator:
 		return n.columns
 	case *valuesNode:
@@ -85,6 +91,11 @@ func getPlanColumns(plan planNode, mut bool) sqlbase.ResultColumns {
 		return n.getColumns(mut, scrubColumns)
 	case *explainDistSQLNode:
 		return n.getColumns(mut, explainDistSQLColumns)
+	case *explainAnalyzeNode:
+		if n.distSQL {
+			return n.getColumns(mut, explainDistSQLColumns)
+		}
+		return n.getColumns(mut, explainAnalyzeColumns)
 	case *testingRelocateNode:
 		return n.getColumns(mut, relocateNodeColumns)
 	case *scatterNode:
@@ -34,6 +34,7 @@ func collectSpans(params runParams, plan planNode) (reads, writes roachpb.Spans,
 	switch n := plan.(type) {
 	case
 		*valueGenerator,
+		*scanBufferNode,
 		*valuesNode,
 		*zeroNode,
 		*unaryNode:
@@ -64,6 +65,8 @@ func collectSpans(params runParams, plan planNode) (reads, writes roachpb.Spans,
 		return collectSpans(params, n.plan)
 	case *explainDistSQLNode:
 		return collectSpans(params, n.plan)
+	case *explainAnalyzeNode:
+		return collectSpans(params, n.plan)
 	case *explainPlanNode:
 		return collectSpans(params, n.plan)
 	case *showTraceNode:
@@ -89,8 +92,19 @@ func collectSpans(params runParams, plan planNode) (reads, writes roachpb.Spans,
 		return indexJoinSpans(params, n)
 	case *joinNode:
 		return concatSpans(params, n.left.plan, n.right.plan)
+	case *applyJoinNode:
+		// The right side, which is planned for every row of the left side at
+		// execution time, reads the same spans as its template plan.
+		return concatSpans(params, n.left.plan, n.right.plan)
 	case *unionNode:
 		return concatSpans(params, n.left, n.right)
+	case *recursiveCTENode:
+		// The iterations of the recursive term which are planned at execution
+		// time read the same spans as the first one.
+		if n.recursive == nil {
+			return collectSpans(params, n.initial)
+		}
+		return concatSpans(params, n.initial, n.recursive)
 	}
 
 	panic(fmt.Sprintf("don't know how to collect spans for node %T", plan))
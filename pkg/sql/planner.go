@@ -68,6 +68,11 @@ type extendedEvalContext struct {
 
 	SchemaChangers *schemaChangerCollection
 
+	// TemporarySchemaName is the name of the session's temporary schema,
+	// whether or not it has been created yet. It is empty for internal
+	// planners, which cannot create temporary objects.
+	TemporarySchemaName string
+
 	schemaAccessors *schemaInterface
 }
 
@@ -40,12 +40,15 @@ const (
 	INSERT
 	DELETE
 	UPDATE
+	EXECUTE
 )
 
 // Predefined sets of privileges.
 var (
 	ReadData      = List{GRANT, SELECT}
 	ReadWriteData = List{GRANT, SELECT, INSERT, DELETE, UPDATE}
+	// FunctionPrivileges are the privileges that can be held on a function.
+	FunctionPrivileges = List{ALL, DROP, GRANT, EXECUTE}
 )
 
 // Mask returns the bitmask for a given privilege.
@@ -55,19 +58,20 @@ func (k Kind) Mask() uint32 {
 
 // ByValue is just an array of privilege kinds sorted by value.
 var ByValue = [...]Kind{
-	ALL, CREATE, DROP, GRANT, SELECT, INSERT, DELETE, UPDATE,
+	ALL, CREATE, DROP, GRANT, SELECT, INSERT, DELETE, UPDATE, EXECUTE,
 }
 
 // ByName is a map of string -> kind value.
 var ByName = map[string]Kind{
-	"ALL":    ALL,
-	"CREATE": CREATE,
-	"DROP":   DROP,
-	"GRANT":  GRANT,
-	"SELECT": SELECT,
-	"INSERT": INSERT,
-	"DELETE": DELETE,
-	"UPDATE": UPDATE,
+	"ALL":     ALL,
+	"CREATE":  CREATE,
+	"DROP":    DROP,
+	"GRANT":   GRANT,
+	"SELECT":  SELECT,
+	"INSERT":  INSERT,
+	"DELETE":  DELETE,
+	"UPDATE":  UPDATE,
+	"EXECUTE": EXECUTE,
 }
 
 // List is a list of privileges.
@@ -0,0 +1,462 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/types"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+)
+
+// This file contains the implementation of recursive common table
+// expressions, i.e. CTEs of the form:
+//
+//   WITH RECURSIVE t(x) AS (
+//       <initial term>
+//     UNION [ALL]
+//       <recursive term, which refers to t>
+//   ) ...
+//
+// Execution proceeds iteratively. The initial term is run first; its rows are
+// emitted and also stored in a "working table". The recursive term is then
+// planned and run with the CTE name bound to the working table, and the rows
+// it produces are again emitted and become the working table for the next
+// iteration. Execution stops when an iteration produces no rows.
+//
+// With UNION (as opposed to UNION ALL), rows that were already produced by a
+// previous iteration are discarded and do not enter the working table; this
+// guarantees termination for queries that walk cyclic graphs.
+
+var recursiveCTEMaxIterations = settings.RegisterIntSetting(
+	"sql.recursive_cte.max_iterations",
+	"maximum number of iterations of the recursive term of a recursive CTE; 0 means no limit",
+	0,
+)
+
+var recursiveCTEMaxMemory = settings.RegisterByteSizeSetting(
+	"sql.recursive_cte.max_memory",
+	"maximum amount of memory used by the working tables of a recursive CTE",
+	64<<20, /* 64 MiB */
+)
+
+// recursiveCTEIterationFn creates the plan for one iteration of the recursive
+// term of a recursive CTE. The returned plan reads the working table via a
+// scanBufferNode that refers to the given recursiveCTENode.
+type recursiveCTEIterationFn func(params runParams, n *recursiveCTENode) (planNode, error)
+
+// recursiveCTENode implements the execution of a recursive CTE.
+type recursiveCTENode struct {
+	// initial is the plan for the non-recursive term.
+	initial planNode
+
+	// recursive, if set, is the plan for the first iteration of the recursive
+	// term. It is created at planning time to validate the recursive term, and
+	// consumed by the first iteration.
+	recursive planNode
+
+	// genIterationFn creates the plans for the subsequent iterations of the
+	// recursive term.
+	genIterationFn recursiveCTEIterationFn
+
+	// label is the name of the CTE.
+	label string
+
+	columns sqlbase.ResultColumns
+
+	// unionAll is set when the initial and the recursive term are combined
+	// using UNION ALL. When not set, duplicate rows are removed.
+	unionAll bool
+
+	run recursiveCTERun
+}
+
+// recursiveCTERun contains the run-time state of recursiveCTENode during
+// local execution.
+type recursiveCTERun struct {
+	// mon limits the memory used by the working tables and the set of rows
+	// seen so far.
+	mon mon.BytesMonitor
+	// acc accounts for the memory used by seen.
+	acc mon.BoundAccount
+
+	// workingRows contains the rows produced by the previous iteration; they
+	// are read by the scanBufferNode during the current iteration.
+	workingRows *sqlbase.RowContainer
+	// nextRows accumulates the rows produced by the current iteration.
+	nextRows *sqlbase.RowContainer
+
+	// seen contains the encoding of all the rows produced so far. It is only
+	// used when unionAll is not set.
+	seen map[string]struct{}
+	// scratch is a preallocated buffer for encoding rows.
+	scratch []byte
+
+	// cur is the plan currently being read; either the initial term or an
+	// iteration of the recursive term.
+	cur planNode
+	// iterations is the number of iterations of the recursive term that have
+	// been started so far.
+	iterations int64
+
+	currentRow tree.Datums
+}
+
+// newRecursiveCTENode creates a recursiveCTENode. The recursive term is
+// attached separately, once the node is available to be referenced by the
+// working table.
+func newRecursiveCTENode(
+	initial planNode, label string, columns sqlbase.ResultColumns, unionAll bool,
+) *recursiveCTENode {
+	return &recursiveCTENode{
+		initial:  initial,
+		label:    label,
+		columns:  columns,
+		unionAll: unionAll,
+	}
+}
+
+func (n *recursiveCTENode) startExec(params runParams) error {
+	ctx := params.ctx
+	limit := recursiveCTEMaxMemory.Get(&params.p.ExecCfg().Settings.SV)
+	n.run.mon = mon.MakeMonitorInheritWithLimit("recursive-cte", limit, params.EvalContext().Mon)
+	n.run.mon.Start(ctx, params.EvalContext().Mon, mon.BoundAccount{})
+	n.run.acc = n.run.mon.MakeBoundAccount()
+
+	ti := sqlbase.ColTypeInfoFromResCols(n.columns)
+	n.run.workingRows = sqlbase.NewRowContainer(n.run.mon.MakeBoundAccount(), ti, 0)
+	n.run.nextRows = sqlbase.NewRowContainer(n.run.mon.MakeBoundAccount(), ti, 0)
+	if !n.unionAll {
+		n.run.seen = make(map[string]struct{})
+	}
+
+	// The recursive term cannot be started until the working table has been
+	// populated; see the recursiveCTENode case in startExec().
+	n.run.cur = n.initial
+	return startExec(params, n.initial)
+}
+
+func (n *recursiveCTENode) Next(params runParams) (bool, error) {
+	if err := params.p.cancelChecker.Check(); err != nil {
+		return false, err
+	}
+	n.run.currentRow = nil
+	for n.run.cur != nil {
+		next, err := n.run.cur.Next(params)
+		if err != nil {
+			return false, err
+		}
+		if next {
+			row := n.run.cur.Values()
+			emit, err := n.addRow(params.ctx, row)
+			if err != nil {
+				return false, err
+			}
+			if emit {
+				n.run.currentRow = row
+				return true, nil
+			}
+			continue
+		}
+
+		// The current term is exhausted; move on to the next iteration.
+		if err := n.startNextIteration(params); err != nil {
+			return false, err
+		}
+	}
+	return false, nil
+}
+
+// addRow records a row produced by the current term in the working table for
+// the next iteration. It returns false if the row is a duplicate that must not
+// be emitted.
+func (n *recursiveCTENode) addRow(ctx context.Context, row tree.Datums) (bool, error) {
+	if !n.unionAll {
+		var err error
+		n.run.scratch, err = sqlbase.EncodeDatums(n.run.scratch[:0], row)
+		if err != nil {
+			return false, err
+		}
+		if _, ok := n.run.seen[string(n.run.scratch)]; ok {
+			return false, nil
+		}
+		if err := n.run.acc.Grow(ctx, int64(len(n.run.scratch))); err != nil {
+			return false, err
+		}
+		n.run.seen[string(n.run.scratch)] = struct{}{}
+	}
+	if _, err := n.run.nextRows.AddRow(ctx, row); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// startNextIteration closes the plan of the current term, and, if the current
+// term produced any rows, starts the next iteration of the recursive term.
+// Once there are no more iterations to run, run.cur is left nil.
+func (n *recursiveCTENode) startNextIteration(params runParams) error {
+	if n.run.cur == n.initial {
+		n.initial = nil
+	}
+	n.run.cur.Close(params.ctx)
+	n.run.cur = nil
+
+	if n.run.nextRows.Len() == 0 {
+		return nil
+	}
+
+	n.run.iterations++
+	if max := recursiveCTEMaxIterations.Get(&params.p.ExecCfg().Settings.SV); max > 0 &&
+		n.run.iterations > max {
+		return pgerror.NewErrorf(pgerror.CodeProgramLimitExceededError,
+			"recursive query %q exceeded the maximum number of iterations (%d)", n.label, max)
+	}
+
+	// The rows produced by the last iteration become the working table.
+	n.run.workingRows, n.run.nextRows = n.run.nextRows, n.run.workingRows
+	n.run.nextRows.Clear(params.ctx)
+
+	plan := n.recursive
+	n.recursive = nil
+	if plan == nil {
+		var err error
+		plan, err = n.genIterationFn(params, n)
+		if err != nil {
+			return err
+		}
+	}
+	n.run.cur = plan
+	return startPlan(params, plan)
+}
+
+func (n *recursiveCTENode) Values() tree.Datums {
+	return n.run.currentRow
+}
+
+func (n *recursiveCTENode) Close(ctx context.Context) {
+	if n.run.cur != nil && n.run.cur != n.initial {
+		n.run.cur.Close(ctx)
+	}
+	n.run.cur = nil
+	if n.initial != nil {
+		n.initial.Close(ctx)
+		n.initial = nil
+	}
+	if n.recursive != nil {
+		n.recursive.Close(ctx)
+		n.recursive = nil
+	}
+	if n.run.workingRows != nil {
+		n.run.workingRows.Close(ctx)
+		n.run.nextRows.Close(ctx)
+		n.run.workingRows = nil
+		n.run.nextRows = nil
+		n.run.seen = nil
+		n.run.acc.Close(ctx)
+		n.run.mon.Stop(ctx)
+	}
+}
+
+// scanBufferNode reads the working table of a recursive CTE.
+type scanBufferNode struct {
+	buffer  *recursiveCTENode
+	label   string
+	columns sqlbase.ResultColumns
+
+	run struct {
+		nextRowIdx int
+		currentRow tree.Datums
+	}
+}
+
+func (n *scanBufferNode) Next(params runParams) (bool, error) {
+	rows := n.buffer.run.workingRows
+	if n.run.nextRowIdx >= rows.Len() {
+		return false, nil
+	}
+	n.run.currentRow = rows.At(n.run.nextRowIdx)
+	n.run.nextRowIdx++
+	return true, nil
+}
+
+func (n *scanBufferNode) Values() tree.Datums {
+	return n.run.currentRow
+}
+
+func (n *scanBufferNode) Close(context.Context) {}
+
+// planRecursiveCTE plans a CTE defined within a WITH RECURSIVE clause. The
+// given frame is the environment frame of the WITH clause; it already contains
+// the CTEs defined before this one.
+func (p *planner) planRecursiveCTE(
+	ctx context.Context, frame cteNameEnvironmentFrame, cte *tree.CTE,
+) (planNode, error) {
+	name := cte.Name.Alias
+	union, ok := recursiveUnion(cte.Stmt)
+	if !ok {
+		// This is not of the form accepted for recursive CTEs, so it must not
+		// refer to itself.
+		frame[name] = cteSource{alias: cte.Name, recursionErr: pgerror.NewErrorf(
+			pgerror.CodeInvalidRecursionError,
+			"recursive query %q does not have the form non-recursive-term UNION [ALL] recursive-term",
+			tree.ErrString(&name))}
+		defer delete(frame, name)
+		return p.newPlan(ctx, cte.Stmt, nil /* desiredTypes */)
+	}
+
+	frame[name] = cteSource{alias: cte.Name, recursionErr: pgerror.NewErrorf(
+		pgerror.CodeInvalidRecursionError,
+		"recursive reference to query %q must not appear within its non-recursive term",
+		tree.ErrString(&name))}
+	initial, err := p.newPlan(ctx, union.Left, nil /* desiredTypes */)
+	delete(frame, name)
+	if err != nil {
+		return nil, err
+	}
+
+	initialCols := planColumns(initial)
+	if len(initialCols) == 0 {
+		initial.Close(ctx)
+		return nil, pgerror.NewErrorf(pgerror.CodeFeatureNotSupportedError,
+			"WITH clause %q does not have a RETURNING clause", tree.ErrString(&name))
+	}
+	cols := make(sqlbase.ResultColumns, len(initialCols))
+	for i := range initialCols {
+		cols[i] = sqlbase.ResultColumn{Name: initialCols[i].Name, Typ: initialCols[i].Typ}
+	}
+	n := newRecursiveCTENode(initial, string(name), cols, union.All)
+
+	// The recursive term is planned now to validate it. The resulting plan is
+	// used for the first iteration; the following iterations re-plan the
+	// recursive term, as plans cannot be restarted.
+	envSnapshot := append(cteNameEnvironment(nil), p.curPlan.cteNameEnvironment...)
+	lateralSnapshot := p.curPlan.lateralEnvironment
+	recursive, err := p.planRecursiveTerm(ctx, frame, cte, union.Right, n)
+	if err != nil {
+		n.Close(ctx)
+		return nil, err
+	}
+	if !recursive.selfReferencing {
+		// The CTE does not actually refer to itself: plan it as a regular
+		// UNION.
+		n.initial = nil
+		return p.newUnionNode(tree.UnionOp, union.All, initial, recursive.plan)
+	}
+	n.recursive = recursive.plan
+
+	recursiveCols := planColumns(recursive.plan)
+	if len(recursiveCols) != len(cols) {
+		n.Close(ctx)
+		return nil, pgerror.NewErrorf(pgerror.CodeSyntaxError,
+			"each UNION query must have the same number of columns: %d vs %d",
+			len(cols), len(recursiveCols))
+	}
+	for i := range cols {
+		if l, r := cols[i].Typ, recursiveCols[i].Typ; !l.Equivalent(r) && r != types.Unknown {
+			n.Close(ctx)
+			return nil, pgerror.NewErrorf(pgerror.CodeDatatypeMismatchError,
+				"recursive query %q column %d has type %s in non-recursive term but type %s overall",
+				tree.ErrString(&name), i+1, l, r)
+		}
+	}
+
+	// firstSubquery and endSubquery delimit the subqueries added to the
+	// top-level plan by the last iteration.
+	var firstSubquery, endSubquery int
+	n.genIterationFn = func(params runParams, n *recursiveCTENode) (planNode, error) {
+		p := params.p
+		defer func(env cteNameEnvironment, lateral lateralEnvironment) {
+			p.curPlan.cteNameEnvironment = env
+			p.curPlan.lateralEnvironment = lateral
+		}(p.curPlan.cteNameEnvironment, p.curPlan.lateralEnvironment)
+		p.curPlan.cteNameEnvironment = envSnapshot
+		p.curPlan.lateralEnvironment = lateralSnapshot
+
+		// Subqueries in the recursive term are collected into the top-level
+		// plan and must be prepared before the iteration can start. The
+		// subqueries of the previous iteration, whose plan is closed, are not
+		// needed anymore.
+		p.curPlan.releaseSubqueries(params.ctx, firstSubquery, endSubquery)
+		firstSubquery = len(p.curPlan.subqueryPlans)
+		iteration, err := p.planRecursiveTerm(params.ctx, frame, cte, union.Right, n)
+		endSubquery = len(p.curPlan.subqueryPlans)
+		if err != nil {
+			return nil, err
+		}
+		plan, err := p.optimizeDeferredPlan(params, iteration.plan, firstSubquery)
+		if err != nil {
+			plan.Close(params.ctx)
+			return nil, err
+		}
+		return plan, nil
+	}
+	return n, nil
+}
+
+// recursiveTermPlan is the result of planRecursiveTerm.
+type recursiveTermPlan struct {
+	plan planNode
+	// selfReferencing is set if the recursive term refers to the working
+	// table.
+	selfReferencing bool
+}
+
+// planRecursiveTerm plans the recursive term of a recursive CTE, with the
+// name of the CTE bound to the working table of the given recursiveCTENode.
+func (p *planner) planRecursiveTerm(
+	ctx context.Context,
+	frame cteNameEnvironmentFrame,
+	cte *tree.CTE,
+	stmt *tree.Select,
+	n *recursiveCTENode,
+) (recursiveTermPlan, error) {
+	name := cte.Name.Alias
+	prev, hadPrev := frame[name]
+	defer func() {
+		if hadPrev {
+			frame[name] = prev
+		} else {
+			delete(frame, name)
+		}
+	}()
+	frame[name] = cteSource{
+		plan:  &scanBufferNode{buffer: n, label: n.label, columns: n.columns},
+		alias: cte.Name,
+	}
+	plan, err := p.newPlan(ctx, stmt, nil /* desiredTypes */)
+	if err != nil {
+		return recursiveTermPlan{}, err
+	}
+	return recursiveTermPlan{plan: plan, selfReferencing: frame[name].used}, nil
+}
+
+// recursiveUnion returns the UNION clause of the statement defining a
+// recursive CTE, if the statement has the required form
+// "non-recursive-term UNION [ALL] recursive-term".
+func recursiveUnion(stmt tree.Statement) (*tree.UnionClause, bool) {
+	sel, ok := stmt.(*tree.Select)
+	if !ok || sel.With != nil || sel.OrderBy != nil || sel.Limit != nil {
+		return nil, false
+	}
+	union, ok := sel.Select.(*tree.UnionClause)
+	if !ok || union.Type != tree.UnionOp {
+		return nil, false
+	}
+	return union, true
+}
@@ -0,0 +1,369 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"unsafe"
+
+	"github.com/pkg/errors"
+
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/jobs"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/builtins"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+)
+
+type refreshViewNode struct {
+	n    *tree.RefreshMaterializedView
+	desc *sqlbase.TableDescriptor
+}
+
+// RefreshMaterializedView recomputes the rows stored for a materialized
+// view. The work is performed by a job, which the statement waits for.
+// Privileges: CREATE on view.
+//   Notes: postgres requires ownership of the view.
+func (p *planner) RefreshMaterializedView(
+	ctx context.Context, n *tree.RefreshMaterializedView,
+) (planNode, error) {
+	if !p.autoCommit {
+		return nil, pgerror.NewError(pgerror.CodeActiveSQLTransactionError,
+			"REFRESH MATERIALIZED VIEW cannot run inside a transaction block")
+	}
+
+	tn, err := n.Name.Normalize()
+	if err != nil {
+		return nil, err
+	}
+	desc, err := ResolveExistingObject(ctx, p, tn, true /*required*/, requireViewDesc)
+	if err != nil {
+		return nil, err
+	}
+	if !desc.MaterializedView() {
+		return nil, pgerror.NewErrorf(pgerror.CodeWrongObjectTypeError,
+			"%q is not a materialized view", tree.ErrString(tn))
+	}
+
+	if err := p.CheckPrivilege(ctx, desc, privilege.CREATE); err != nil {
+		return nil, err
+	}
+
+	if n.Concurrently {
+		if err := checkConcurrentRefresh(tn, desc); err != nil {
+			return nil, err
+		}
+	}
+
+	return &refreshViewNode{n: n, desc: desc}, nil
+}
+
+func (n *refreshViewNode) startExec(params runParams) error {
+	_, errCh, err := params.p.ExecCfg().JobRegistry.StartJob(params.ctx, nil /* resultsCh */, jobs.Record{
+		Description:   n.n.String(),
+		Username:      params.SessionData().User,
+		DescriptorIDs: sqlbase.IDs{n.desc.ID},
+		Details: jobs.RefreshMaterializedViewDetails{
+			TableID:      n.desc.ID,
+			Concurrently: n.n.Concurrently,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return <-errCh
+}
+
+func (*refreshViewNode) Next(runParams) (bool, error) { return false, nil }
+func (*refreshViewNode) Values() tree.Datums          { return tree.Datums{} }
+func (*refreshViewNode) Close(context.Context)        {}
+
+// refreshChunkSize is the number of rows written to a materialized view by
+// each KV batch of a refresh.
+const refreshChunkSize = 1000
+
+// checkConcurrentRefresh verifies that the materialized view described by
+// desc can be refreshed concurrently. Like in postgres, this requires a
+// unique index on one or more columns of the view, which identifies the
+// stored rows.
+func checkConcurrentRefresh(tn *tree.TableName, desc *sqlbase.TableDescriptor) error {
+	for i := range desc.Indexes {
+		if desc.Indexes[i].Unique {
+			return nil
+		}
+	}
+	return pgerror.NewErrorf(pgerror.CodeObjectNotInPrerequisiteStateError,
+		"cannot refresh materialized view %q concurrently", tree.ErrString(tn)).SetHintf(
+		"Create a unique index on one or more columns of the materialized view.")
+}
+
+// refreshMaterializedView runs the query of the materialized view
+// described by desc and stores its results in the view, using the
+// planner's transaction. The results are buffered in memory, in a row
+// container accounted against the planner's memory monitor; only the
+// writes to the view are performed in batches of refreshChunkSize rows.
+//
+// Unless concurrently is set, the previously stored rows are deleted
+// and all the results are written anew. With concurrently set, the
+// results are compared to the stored rows and only the rows that
+// differ are deleted and then inserted, so that readers of unchanged
+// rows do not conflict with the refresh.
+func (p *planner) refreshMaterializedView(
+	ctx context.Context, desc *sqlbase.TableDescriptor, concurrently bool,
+) error {
+	rows, err := p.queryRowContainer(ctx, desc.ViewQuery)
+	if err != nil {
+		return err
+	}
+	defer rows.Close(ctx)
+
+	// The hidden row ID column added by ensurePrimaryKey() is the last
+	// column of the view; the others map 1-1 to the query results.
+	pkColIdx := len(desc.Columns) - 1
+	traceKV := p.extendedEvalCtx.Tracing.KVTracingEnabled()
+
+	b := p.txn.NewBatch()
+	batchRows := 0
+	// finishRow runs the current batch once it holds the writes of
+	// refreshChunkSize rows.
+	finishRow := func() error {
+		if batchRows++; batchRows < refreshChunkSize {
+			return nil
+		}
+		if err := p.txn.Run(ctx, b); err != nil {
+			return err
+		}
+		b = p.txn.NewBatch()
+		batchRows = 0
+		return nil
+	}
+
+	ri, err := sqlbase.MakeRowInserter(
+		p.txn, desc, nil, desc.Columns, sqlbase.SkipFKs, &p.alloc)
+	if err != nil {
+		return err
+	}
+	rowBuffer := make(tree.Datums, len(desc.Columns))
+	insertRow := func(row tree.Datums) error {
+		copy(rowBuffer, row)
+		rowBuffer[pkColIdx] = tree.NewDInt(builtins.GenerateUniqueInt(p.EvalContext().NodeID))
+		if err := ri.InsertRow(
+			ctx, b, rowBuffer, false /* ignoreConflicts */, sqlbase.SkipFKs, traceKV,
+		); err != nil {
+			return err
+		}
+		return finishRow()
+	}
+
+	if !concurrently {
+		// The secondary indexes of the view are cleared too.
+		span := desc.TableSpan()
+		if err := p.txn.DelRange(ctx, span.Key, span.EndKey); err != nil {
+			return err
+		}
+		for i := 0; i < rows.Len(); i++ {
+			if err := insertRow(rows.At(i)); err != nil {
+				return err
+			}
+		}
+		return p.txn.Run(ctx, b)
+	}
+
+	// Load the stored rows, including their row IDs, and index them by
+	// the values of the visible columns. Duplicate rows are allowed, so
+	// each value maps to all the stored rows that carry it.
+	var buf bytes.Buffer
+	for i := range desc.Columns {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		name := tree.Name(desc.Columns[i].Name)
+		buf.WriteString(name.String())
+	}
+	stored, err := p.queryRowContainer(
+		ctx, fmt.Sprintf("SELECT %s FROM [%d AS v]", buf.String(), desc.ID))
+	if err != nil {
+		return err
+	}
+	defer stored.Close(ctx)
+	acc := p.EvalContext().Mon.MakeBoundAccount()
+	defer acc.Close(ctx)
+	storedByValue := make(map[string][]int, stored.Len())
+	for i := 0; i < stored.Len(); i++ {
+		k := materializedViewRowKey(stored.At(i)[:pkColIdx])
+		if err := acc.Grow(ctx, int64(len(k))+int64(unsafe.Sizeof(i))); err != nil {
+			return err
+		}
+		storedByValue[k] = append(storedByValue[k], i)
+	}
+
+	// Match the results to the stored rows. The results that don't match
+	// any stored row are inserted, and the stored rows that are left are
+	// deleted.
+	var toInsert []int
+	for i := 0; i < rows.Len(); i++ {
+		k := materializedViewRowKey(rows.At(i))
+		if matches := storedByValue[k]; len(matches) > 0 {
+			storedByValue[k] = matches[1:]
+			continue
+		}
+		if err := acc.Grow(ctx, int64(unsafe.Sizeof(i))); err != nil {
+			return err
+		}
+		toInsert = append(toInsert, i)
+	}
+
+	// The stale rows are deleted before the new rows are inserted: a new
+	// row that has the same values on a unique index as a stale row would
+	// otherwise conflict with the index entry of the stale row.
+	rd, err := sqlbase.MakeRowDeleter(
+		p.txn, desc, nil, desc.Columns, sqlbase.SkipFKs, p.EvalContext(), &p.alloc)
+	if err != nil {
+		return err
+	}
+	for _, matches := range storedByValue {
+		for _, i := range matches {
+			if err := rd.DeleteRow(ctx, b, stored.At(i), sqlbase.SkipFKs, traceKV); err != nil {
+				return err
+			}
+			if err := finishRow(); err != nil {
+				return err
+			}
+		}
+	}
+	if err := p.txn.Run(ctx, b); err != nil {
+		return err
+	}
+	b = p.txn.NewBatch()
+	batchRows = 0
+
+	for _, i := range toInsert {
+		if err := insertRow(rows.At(i)); err != nil {
+			return err
+		}
+	}
+	return p.txn.Run(ctx, b)
+}
+
+// queryRowContainer runs the given query and returns its results in a row
+// container accounted against the planner's memory monitor. The caller
+// must close the container.
+func (p *planner) queryRowContainer(
+	ctx context.Context, sql string,
+) (*sqlbase.RowContainer, error) {
+	// makeInternalPlan() clobbers p.curplan and the placeholder info
+	// map, so we have to save/restore them here.
+	defer func(psave planTop, pisave tree.PlaceholderInfo) {
+		p.semaCtx.Placeholders = pisave
+		p.curPlan = psave
+	}(p.curPlan, p.semaCtx.Placeholders)
+
+	if err := p.makeInternalPlan(ctx, sql); err != nil {
+		return nil, err
+	}
+	defer p.curPlan.close(ctx)
+
+	params := runParams{
+		ctx:             ctx,
+		extendedEvalCtx: &p.extendedEvalCtx,
+		p:               p,
+	}
+	if err := p.curPlan.start(params); err != nil {
+		return nil, err
+	}
+	rows := sqlbase.NewRowContainer(
+		p.EvalContext().Mon.MakeBoundAccount(),
+		sqlbase.ColTypeInfoFromResCols(planColumns(p.curPlan.plan)),
+		0, /* rowCapacity */
+	)
+	if err := forEachRow(params, p.curPlan.plan, func(values tree.Datums) error {
+		_, err := rows.AddRow(ctx, values)
+		return err
+	}); err != nil {
+		rows.Close(ctx)
+		return nil, err
+	}
+	return rows, nil
+}
+
+// materializedViewRowKey returns a string that identifies the values
+// of a row of a materialized view.
+func materializedViewRowKey(row tree.Datums) string {
+	return tree.AsStringWithFlags(&row, tree.FmtParsable)
+}
+
+type refreshMaterializedViewResumer struct{}
+
+var _ jobs.Resumer = &refreshMaterializedViewResumer{}
+
+// Resume is part of the jobs.Resumer interface.
+func (r *refreshMaterializedViewResumer) Resume(
+	ctx context.Context, job *jobs.Job, phs interface{}, resultsCh chan<- tree.Datums,
+) error {
+	details := job.Record.Details.(jobs.RefreshMaterializedViewDetails)
+	execCfg := phs.(PlanHookState).ExecCfg()
+
+	return execCfg.DB.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
+		desc, err := sqlbase.GetTableDescFromID(ctx, txn, details.TableID)
+		if err != nil {
+			return err
+		}
+		if desc.Dropped() {
+			return errors.Errorf("materialized view %q was dropped", desc.Name)
+		}
+		// The privileges of the user were checked when the job was
+		// created. The view query itself runs with full privileges, as it
+		// does when the view is read.
+		p, cleanup := newInternalPlanner(
+			"refresh-materialized-view", txn, security.RootUser, execCfg.LeaseManager.memMetrics, execCfg)
+		defer cleanup()
+		return p.refreshMaterializedView(ctx, desc, details.Concurrently)
+	})
+}
+
+// OnSuccess is part of the jobs.Resumer interface.
+func (r *refreshMaterializedViewResumer) OnSuccess(context.Context, *client.Txn, *jobs.Job) error {
+	return nil
+}
+
+// OnTerminal is part of the jobs.Resumer interface.
+func (r *refreshMaterializedViewResumer) OnTerminal(
+	context.Context, *jobs.Job, jobs.Status, chan<- tree.Datums,
+) {
+}
+
+// OnFailOrCancel is part of the jobs.Resumer interface.
+func (r *refreshMaterializedViewResumer) OnFailOrCancel(
+	context.Context, *client.Txn, *jobs.Job,
+) error {
+	return nil
+}
+
+func refreshMaterializedViewResumeHook(typ jobs.Type, _ *cluster.Settings) jobs.Resumer {
+	if typ != jobs.TypeRefreshMaterializedView {
+		return nil
+	}
+	return &refreshMaterializedViewResumer{}
+}
+
+func init() {
+	jobs.AddResumeHook(refreshMaterializedViewResumeHook)
+}
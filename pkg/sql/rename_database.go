@@ -34,6 +34,13 @@ func (p *planner) RenameDatabase(ctx context.Context, n *tree.RenameDatabase) (p
 		return nil, errEmptyDatabaseName
 	}
 
+	// Temporary schemas are named after their session.
+	for _, name := range []tree.Name{n.Name, n.NewName} {
+		if err := checkNotTemporarySchemaName(string(name)); err != nil {
+			return nil, err
+		}
+	}
+
 	if string(n.Name) == p.SessionData().Database && p.SessionData().SafeUpdates {
 		return nil, pgerror.NewDangerousStatementErrorf("RENAME DATABASE on current database")
 	}
@@ -20,8 +20,10 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/internal/client"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 )
@@ -79,22 +81,38 @@ func (p *planner) RenameTable(ctx context.Context, n *tree.RenameTable) (planNod
 			ctx, tableDesc.TypeName(), oldTn.String(), tableDesc.ParentID, tableDesc.DependedOnBy[0].ID)
 	}
 
-	var prevDbDesc *DatabaseDescriptor
-	p.runWithOptions(resolveFlags{skipCache: true}, func() {
-		prevDbDesc, err = ResolveDatabase(ctx, p, oldTn.Catalog(), true /*required*/)
-	})
-	if err != nil {
-		return nil, err
+	if newTn.ExplicitSchema &&
+		(newTn.Schema() == sessiondata.PgTempSchemaName) != tableDesc.Temporary {
+		return nil, pgerror.NewError(pgerror.CodeFeatureNotSupportedError,
+			"cannot move objects into or out of temporary schemas")
 	}
 
-	// Check if target database exists.
-	// We also look at uncached descriptors here.
-	var targetDbDesc *DatabaseDescriptor
-	p.runWithOptions(resolveFlags{skipCache: true, allowAdding: true}, func() {
-		targetDbDesc, err = ResolveTargetObject(ctx, p, newTn)
-	})
-	if err != nil {
-		return nil, err
+	var prevDbDesc, targetDbDesc *DatabaseDescriptor
+	if tableDesc.Temporary {
+		// A temporary table stays in the session's temporary schema.
+		p.runWithOptions(resolveFlags{skipCache: true, allowAdding: true}, func() {
+			targetDbDesc, err = p.resolveTemporaryTarget(ctx, newTn)
+		})
+		if err != nil {
+			return nil, err
+		}
+		prevDbDesc = targetDbDesc
+	} else {
+		p.runWithOptions(resolveFlags{skipCache: true}, func() {
+			prevDbDesc, err = ResolveDatabase(ctx, p, oldTn.Catalog(), true /*required*/)
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		// Check if target database exists.
+		// We also look at uncached descriptors here.
+		p.runWithOptions(resolveFlags{skipCache: true, allowAdding: true}, func() {
+			targetDbDesc, err = ResolveTargetObject(ctx, p, newTn)
+		})
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	if err := p.CheckPrivilege(ctx, targetDbDesc, privilege.CREATE); err != nil {
@@ -97,6 +97,7 @@ func (p *planner) Select(
 	limit := n.Limit
 	orderBy := n.OrderBy
 	with := n.With
+	locking := n.Locking
 
 	for s, ok := wrapped.(*tree.ParenSelect); ok; s, ok = wrapped.(*tree.ParenSelect) {
 		wrapped = s.Select.Select
@@ -112,13 +113,17 @@ func (p *planner) Select(
 			}
 			limit = s.Select.Limit
 		}
+		if s.Select.Locking != nil {
+			// The locking clauses at all levels apply to the statement.
+			locking = append(append(tree.LockingClause(nil), locking...), s.Select.Locking...)
+		}
 	}
 
 	switch s := wrapped.(type) {
 	case *tree.SelectClause:
 		// Select can potentially optimize index selection if it's being ordered,
 		// so we allow it to do its own sorting.
-		return p.SelectClause(ctx, s, orderBy, limit, with, desiredTypes, publicColumns)
+		return p.SelectClause(ctx, s, orderBy, limit, locking, with, desiredTypes, publicColumns)
 
 	// TODO(dan): Union can also do optimizations when it has an ORDER BY, but
 	// currently expects the ordering to be done externally, so we let it fall
@@ -128,6 +133,15 @@ func (p *planner) Select(
 	// TODO(jordan): this limitation also applies to CTEs, which do not yet
 	// propagate into VALUES and UNION clauses
 	default:
+		if locking != nil {
+			switch s.(type) {
+			case *tree.UnionClause:
+				return nil, newLockingNotAllowedError(locking, "UNION/INTERSECT/EXCEPT")
+			case *tree.ValuesClause:
+				return nil, pgerror.NewErrorf(pgerror.CodeFeatureNotSupportedError,
+					"%s cannot be applied to VALUES", locking[0].Strength)
+			}
+		}
 		plan, err := p.newPlan(ctx, s, desiredTypes)
 		if err != nil {
 			return nil, err
@@ -163,7 +177,7 @@ func (p *planner) Select(
 // LIMIT, or parenthesis in the parsed SELECT. See `sql/tree.Select` and
 // `sql/tree.SelectStatement`.
 //
-// Privileges: SELECT on table
+// Privileges: SELECT on table, UPDATE on table for FOR UPDATE/SHARE.
 //   Notes: postgres requires SELECT. Also requires UPDATE on "FOR UPDATE".
 //          mysql requires SELECT.
 func (p *planner) SelectClause(
@@ -171,6 +185,7 @@ func (p *planner) SelectClause(
 	parsed *tree.SelectClause,
 	orderBy tree.OrderBy,
 	limit *tree.Limit,
+	locking tree.LockingClause,
 	with *tree.With,
 	desiredTypes []types.T,
 	scanVisibility scanVisibility,
@@ -185,10 +200,22 @@ func (p *planner) SelectClause(
 		defer resetter(p)
 	}
 
+	if locking != nil {
+		if err := checkLockingClause(parsed, locking); err != nil {
+			return nil, err
+		}
+	}
+
 	if err := p.initFrom(ctx, r, parsed, scanVisibility); err != nil {
 		return nil, err
 	}
 
+	if locking != nil {
+		if err := p.applyLocking(ctx, locking, r.source); err != nil {
+			return nil, err
+		}
+	}
+
 	var where *filterNode
 	if parsed.Where != nil {
 		var err error
@@ -268,6 +295,15 @@ func (p *planner) SelectClause(
 		return nil, err
 	}
 
+	if locking != nil {
+		if groupComplex != nil {
+			return nil, newLockingNotAllowedError(locking, "aggregate functions")
+		}
+		if window != nil {
+			return nil, newLockingNotAllowedError(locking, "window functions")
+		}
+	}
+
 	if group != nil && group.requiresIsDistinctFromNullFilter() {
 		if where == nil {
 			var err error
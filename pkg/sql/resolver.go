@@ -24,6 +24,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
 )
 
 // SchemaResolver abstracts the interfaces needed from the logical
@@ -101,6 +102,8 @@ func ResolveExistingObject(
 		goodType = desc.IsTable() || desc.IsView()
 	case requireSequenceDesc:
 		goodType = desc.IsSequence()
+	case requireTableOrMaterializedViewDesc:
+		goodType = desc.IsTable() || desc.MaterializedView()
 	}
 	if !goodType {
 		return nil, sqlbase.NewWrongObjectTypeError(tn, requiredTypeNames[requiredType])
@@ -108,6 +111,84 @@ func ResolveExistingObject(
 	return desc, nil
 }
 
+// ResolveExistingFunction looks up an existing user-defined function.
+// Function names are resolved like the names of existing tables: an
+// unqualified name designates the first function with that name in the
+// schemas of the search path. If required is true, an error is returned
+// if the function does not exist.
+//
+// The descriptor of the database containing the function is returned
+// alongside the function descriptor. The function name is modified
+// in-place with the result of the name resolution.
+func ResolveExistingFunction(
+	ctx context.Context, sc SchemaResolver, tn *ObjectName, required bool,
+) (*DatabaseDescriptor, *sqlbase.FunctionDescriptor, error) {
+	curDb := sc.CurrentDatabase()
+	if tn.ExplicitSchema {
+		// The name is qualified: nothing to search.
+		found, descI, err := tn.ResolveTarget(ctx, sc, curDb, sc.CurrentSearchPath())
+		if err != nil {
+			return nil, nil, err
+		}
+		if found {
+			dbDesc, fnDesc, err := lookupFunction(ctx, sc, descI, tn.Schema(), tn.Table())
+			if err != nil || fnDesc != nil {
+				return dbDesc, fnDesc, err
+			}
+		}
+	} else {
+		// This is a naked function name. Use the search path.
+		iter := sc.CurrentSearchPath().IterRelations()
+		for next, ok := iter(); ok; next, ok = iter() {
+			found, descI, err := sc.LookupSchema(ctx, curDb, next)
+			if err != nil {
+				return nil, nil, err
+			}
+			if !found {
+				continue
+			}
+			dbDesc, fnDesc, err := lookupFunction(ctx, sc, descI, next, tn.Table())
+			if err != nil {
+				return nil, nil, err
+			}
+			if fnDesc != nil {
+				tn.CatalogName = tree.Name(curDb)
+				tn.SchemaName = tree.Name(next)
+				return dbDesc, fnDesc, nil
+			}
+		}
+	}
+	if required {
+		return nil, nil, sqlbase.NewUndefinedFunctionError(tn)
+	}
+	return nil, nil, nil
+}
+
+// lookupFunction looks up the user-defined function with the given name
+// in a schema, described by the result of a schema lookup. Only the
+// public schema of a database contains user-defined functions. It
+// returns a nil descriptor if there is no such function.
+func lookupFunction(
+	ctx context.Context, sc SchemaResolver, scMeta tree.SchemaMeta, scName, fnName string,
+) (*DatabaseDescriptor, *sqlbase.FunctionDescriptor, error) {
+	if scName != tree.PublicSchema {
+		return nil, nil, nil
+	}
+	dbDesc, ok := scMeta.(*DatabaseDescriptor)
+	if !ok {
+		return nil, nil, nil
+	}
+	id, ok := dbDesc.FindFunction(fnName)
+	if !ok {
+		return nil, nil, nil
+	}
+	fnDesc, err := sqlbase.GetFunctionDescFromID(ctx, sc.Txn(), id)
+	if err != nil {
+		return nil, nil, err
+	}
+	return dbDesc, fnDesc, nil
+}
+
 // runWithOptions sets the provided resolution flags for the
 // duration of the call of the passed argument fn.
 //
@@ -171,13 +252,15 @@ const (
 	requireViewDesc
 	requireTableOrViewDesc
 	requireSequenceDesc
+	requireTableOrMaterializedViewDesc
 )
 
 var requiredTypeNames = [...]string{
-	requireTableDesc:       "table",
-	requireViewDesc:        "view",
-	requireTableOrViewDesc: "table or view",
-	requireSequenceDesc:    "sequence",
+	requireTableDesc:                   "table",
+	requireViewDesc:                    "view",
+	requireTableOrViewDesc:             "table or view",
+	requireSequenceDesc:                "sequence",
+	requireTableOrMaterializedViewDesc: "table or materialized view",
 }
 
 // LookupSchema implements the tree.TableNameTargetResolver interface.
@@ -241,6 +324,25 @@ func getDescriptorsFromTargetList(
 		return descs, nil
 	}
 
+	if targets.Functions != nil {
+		if len(targets.Functions) == 0 {
+			return nil, errNoFunction
+		}
+		descs := make([]sqlbase.DescriptorProto, 0, len(targets.Functions))
+		for i := range targets.Functions {
+			tn, err := targets.Functions[i].Normalize()
+			if err != nil {
+				return nil, err
+			}
+			_, fnDesc, err := ResolveExistingFunction(ctx, sc, tn, true /*required*/)
+			if err != nil {
+				return nil, err
+			}
+			descs = append(descs, fnDesc)
+		}
+		return descs, nil
+	}
+
 	if len(targets.Tables) == 0 {
 		return nil, errNoTable
 	}
@@ -269,6 +371,33 @@ func getDescriptorsFromTargetList(
 	return descs, nil
 }
 
+// qualifyTableNames qualifies all the table names in the given syntax
+// node with their database and schema. The traversal updates the
+// NormalizableTableNames in-place. We use tree.FormatNode merely as a
+// traversal method; its output buffer is discarded immediately after
+// the traversal because it is not needed further.
+func (p *planner) qualifyTableNames(ctx context.Context, node tree.NodeFormatter) error {
+	var fmtErr error
+	f := tree.NewFmtCtxWithBuf(tree.FmtParsable)
+	f.WithReformatTableNames(
+		func(_ *tree.FmtCtx, t *tree.NormalizableTableName) {
+			tn, err := p.QualifyWithDatabase(ctx, t)
+			if err != nil {
+				log.Warningf(ctx, "failed to qualify table name %q with database name: %v",
+					tree.ErrString(t), err)
+				fmtErr = err
+				return
+			}
+			// Persist the database prefix expansion.
+			tn.ExplicitSchema = true
+			tn.ExplicitCatalog = true
+		},
+	)
+	f.FormatNode(node)
+	f.Close() // We don't need the string.
+	return fmtErr
+}
+
 // getQualifiedTableName returns the database-qualified name of the table
 // or view represented by the provided descriptor. It is a sort of
 // reverse of the Resolve() functions.
@@ -314,7 +443,7 @@ func findTableContainingIndex(
 		if err != nil {
 			return nil, nil, err
 		}
-		if tableDesc == nil || !tableDesc.IsTable() {
+		if tableDesc == nil || !(tableDesc.IsTable() || tableDesc.MaterializedView()) {
 			continue
 		}
 
@@ -358,7 +487,8 @@ func expandIndexName(
 
 	if !index.SearchTable {
 		// The index and its table prefix must exist already. Resolve the table.
-		desc, err = ResolveExistingObject(ctx, sc, tn, requireTable, requireTableDesc)
+		desc, err = ResolveExistingObject(
+			ctx, sc, tn, requireTable, requireTableOrMaterializedViewDesc)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -97,6 +97,15 @@ type scanNode struct {
 
 	disableBatchLimits bool
 
+	// lockingStrength and lockingWaitPolicy describe the row-level locks
+	// acquired on the rows read by the scan, as requested by a FOR UPDATE
+	// or FOR SHARE clause. Only the rows that pass the filter are locked.
+	// They are locked with one KV batch per batch of rows fetched, before
+	// they are emitted, so a limit above the scan may lock more rows than
+	// it consumes, up to the size of a batch.
+	lockingStrength   tree.LockingStrength
+	lockingWaitPolicy tree.LockingWaitPolicy
+
 	run scanRun
 
 	// This struct must be allocated on the heap and its location stay
@@ -171,6 +180,11 @@ type scanRun struct {
 	scanInitialized  bool
 	isSecondaryIndex bool
 
+	// lockedRows holds the rows that were locked but not emitted yet, and
+	// scanDone is set once the fetcher is out of rows. See nextLocked.
+	lockedRows []tree.Datums
+	scanDone   bool
+
 	// Indicates if this scanNode will do a physical data check. This is
 	// only true when running SCRUB commands.
 	isCheck bool
@@ -186,9 +200,14 @@ func (n *scanNode) startExec(params runParams) error {
 		IsSecondaryIndex: n.run.isSecondaryIndex,
 		Cols:             n.cols,
 		ValNeededForCol:  n.valNeededForCol.Copy(),
+		EvalCtx:          params.EvalContext(),
 	}
-	return n.run.fetcher.Init(n.reverse, false, /* returnRangeInfo */
-		false /* isCheck */, &params.p.alloc, tableArgs)
+	if err := n.run.fetcher.Init(n.reverse, false, /* returnRangeInfo */
+		false /* isCheck */, &params.p.alloc, tableArgs); err != nil {
+		return err
+	}
+	n.run.fetcher.SetLocking(n.lockingStrength, n.lockingWaitPolicy)
+	return nil
 }
 
 func (n *scanNode) Close(context.Context) {
@@ -204,6 +223,10 @@ func (n *scanNode) Next(params runParams) (bool, error) {
 		}
 	}
 
+	if n.lockingStrength != tree.ForNone {
+		return n.nextLocked(params)
+	}
+
 	// We fetch one row at a time until we find one that passes the filter.
 	for n.hardLimit == 0 || n.run.rowIndex < n.hardLimit {
 		var err error
@@ -224,6 +247,69 @@ func (n *scanNode) Next(params runParams) (bool, error) {
 	return false, nil
 }
 
+// nextLocked implements Next for a scan that locks the rows it emits.
+// The rows that pass the filter are buffered until the fetcher reads a
+// new batch of key/value pairs, and are then locked with a single KV
+// batch before they are emitted.
+func (n *scanNode) nextLocked(params runParams) (bool, error) {
+	for len(n.run.lockedRows) == 0 {
+		if n.run.scanDone || (n.hardLimit != 0 && n.run.rowIndex >= n.hardLimit) {
+			return false, nil
+		}
+		if err := n.lockNextRows(params); err != nil {
+			return false, err
+		}
+	}
+	n.run.row = n.run.lockedRows[0]
+	n.run.lockedRows = n.run.lockedRows[1:]
+	n.run.rowIndex++
+	return true, nil
+}
+
+// lockNextRows reads the rows of the current batch of the fetcher, and
+// locks the ones that pass the filter.
+func (n *scanNode) lockNextRows(params runParams) error {
+	var queued []tree.Datums
+	kvBatches := n.run.fetcher.KVBatchesFetched()
+	for n.hardLimit == 0 || n.run.rowIndex+int64(len(queued)) < n.hardLimit {
+		var err error
+		n.run.row, _, _, err = n.run.fetcher.NextRowDecoded(params.ctx)
+		if err != nil {
+			return err
+		}
+		if n.run.row == nil {
+			n.run.scanDone = true
+			break
+		}
+		params.extendedEvalCtx.IVarContainer = n
+		passesFilter, err := sqlbase.RunFilter(n.filter, params.EvalContext())
+		if err != nil {
+			return err
+		}
+		if passesFilter {
+			queued = append(queued, append(tree.Datums(nil), n.run.row...))
+			n.run.fetcher.QueueRowLock()
+		}
+		if n.run.fetcher.KVBatchesFetched() != kvBatches {
+			// The fetcher read the start of a new batch while decoding the
+			// row, which is the last row of the previous batch.
+			break
+		}
+	}
+	locked, err := n.run.fetcher.LockQueuedRows(params.ctx, params.p.txn)
+	if err != nil {
+		return err
+	}
+	for i, row := range queued {
+		// A row is left unlocked if it was locked by another transaction
+		// after it was scanned, and SKIP LOCKED was requested.
+		if locked[i] {
+			n.run.lockedRows = append(n.run.lockedRows, row)
+		}
+	}
+	return nil
+}
+
 func (n *scanNode) Values() tree.Datums {
 	return n.run.row
 }
@@ -250,6 +336,8 @@ func (n *scanNode) initScan(params runParams) error {
 		return err
 	}
 	n.run.scanInitialized = true
+	n.run.lockedRows = n.run.lockedRows[:0]
+	n.run.scanDone = false
 	return nil
 }
 
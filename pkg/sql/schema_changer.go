@@ -31,6 +31,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql/jobs"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
@@ -131,6 +132,8 @@ var errSchemaChangeNotFirstInLine = errors.New(
 	"schema change not first in line")
 var errNotHitGCTTLDeadline = errors.New(
 	"not hit gc ttl deadline")
+var errSchemaChangeCanceled = pgerror.NewError(
+	pgerror.CodeQueryCanceledError, "schema change job canceled")
 
 func shouldLogSchemaChangeError(err error) bool {
 	return err != errExistingSchemaChangeLease &&
@@ -500,9 +503,9 @@ func (sc *SchemaChanger) exec(
 	err = sc.runStateMachineAndBackfill(ctx, &lease, evalCtx)
 
 	// Purge the mutations if the application of the mutations failed due to
-	// a permanent error. All other errors are transient errors that are
-	// resolved by retrying the backfill.
-	if sqlbase.IsPermanentSchemaChangeError(err) {
+	// a permanent error, or if the job was canceled. All other errors are
+	// transient errors that are resolved by retrying the backfill.
+	if sqlbase.IsPermanentSchemaChangeError(err) || err == errSchemaChangeCanceled {
 		if err := sc.rollbackSchemaChange(ctx, err, &lease, evalCtx); err != nil {
 			return err
 		}
@@ -636,9 +639,19 @@ func (sc *SchemaChanger) waitToUpdateLeases(ctx context.Context, tableID sqlbase
 // It ensures that all nodes are on the current (pre-update) version of the
 // schema.
 // Returns the updated of the descriptor.
-func (sc *SchemaChanger) done(ctx context.Context) (*sqlbase.Descriptor, error) {
+//
+// If the mutations replace columns and indexes (ALTER COLUMN ... SET DATA
+// TYPE), the new columns and indexes are swapped in and the replaced ones are
+// queued to be dropped under the same mutation ID; the schema change then
+// isn't done yet, and done returns swapped = true.
+func (sc *SchemaChanger) done(
+	ctx context.Context,
+) (desc *sqlbase.Descriptor, swapped bool, err error) {
 	isRollback := false
-	return sc.leaseMgr.Publish(ctx, sc.tableID, func(desc *sqlbase.TableDescriptor) error {
+	var resumeSpan roachpb.Span
+	var numDropMutations int
+	desc, err = sc.leaseMgr.Publish(ctx, sc.tableID, func(desc *sqlbase.TableDescriptor) error {
+		swapped = false
 		i := 0
 		for _, mutation := range desc.Mutations {
 			if mutation.MutationID != sc.mutationID {
@@ -655,9 +668,28 @@ func (sc *SchemaChanger) done(ctx context.Context) (*sqlbase.Descriptor, error)
 			// the version.
 			return errDidntUpdateDescriptor
 		}
+		completed := desc.Mutations[:i]
 		// Trim the executed mutations from the descriptor.
 		desc.Mutations = desc.Mutations[i:]
 
+		var err error
+		if swapped, err = desc.SwapReplacedColumns(completed, sc.mutationID); err != nil {
+			return err
+		}
+		if swapped {
+			// The replaced columns and indexes still need to be dropped; keep
+			// the job around.
+			resumeSpan = desc.PrimaryIndexSpan()
+			numDropMutations = 0
+			for _, m := range desc.Mutations {
+				if m.MutationID != sc.mutationID {
+					break
+				}
+				numDropMutations++
+			}
+			return nil
+		}
+
 		for i, g := range desc.MutationJobs {
 			if g.MutationID == sc.mutationID {
 				// Trim the executed mutation group from the descriptor.
@@ -667,6 +699,16 @@ func (sc *SchemaChanger) done(ctx context.Context) (*sqlbase.Descriptor, error)
 		}
 		return nil
 	}, func(txn *client.Txn) error {
+		if swapped {
+			// Reset the resume spans for the backfill of the dropped columns.
+			spanList := make([]jobs.ResumeSpanList, numDropMutations)
+			for i := range spanList {
+				spanList[i].ResumeSpans = []roachpb.Span{resumeSpan}
+			}
+			return sc.job.WithTxn(txn).SetDetails(
+				ctx, jobs.SchemaChangeDetails{ResumeSpanList: spanList},
+			)
+		}
 		if err := sc.job.WithTxn(txn).Succeeded(ctx, jobs.NoopFn); err != nil {
 			return errors.Wrapf(err, "failed to mark job %d as as successful", *sc.job.ID())
 		}
@@ -691,6 +733,26 @@ func (sc *SchemaChanger) done(ctx context.Context) (*sqlbase.Descriptor, error)
 			}{uint32(sc.mutationID)},
 		)
 	})
+	return desc, swapped, err
+}
+
+// checkCanceled returns errSchemaChangeCanceled if the schema change job was
+// canceled through the jobs registry and the schema change can still be
+// rolled back, that is, all its mutations are still being added.
+func (sc *SchemaChanger) checkCanceled(ctx context.Context, desc *sqlbase.TableDescriptor) error {
+	for _, m := range desc.Mutations {
+		if m.MutationID != sc.mutationID {
+			break
+		}
+		if m.Direction != sqlbase.DescriptorMutation_ADD || m.Rollback {
+			return nil
+		}
+	}
+	fraction := sc.job.Payload().FractionCompleted
+	if err := sc.job.Progressed(ctx, jobs.FractionUpdater(fraction)); jobs.IsCanceledError(err) {
+		return errSchemaChangeCanceled
+	}
+	return nil
 }
 
 // notFirstInLine returns true whenever the schema change has been queued
@@ -734,14 +796,24 @@ func (sc *SchemaChanger) runStateMachineAndBackfill(
 			*sc.job.ID(), err)
 	}
 
-	// Run backfill(s).
-	if err := sc.runBackfill(ctx, lease, evalCtx); err != nil {
-		return err
-	}
+	for {
+		// Run backfill(s).
+		if err := sc.runBackfill(ctx, lease, evalCtx); err != nil {
+			return err
+		}
 
-	// Mark the mutations as completed.
-	_, err := sc.done(ctx)
-	return err
+		// Mark the mutations as completed.
+		_, swapped, err := sc.done(ctx)
+		if err != nil || !swapped {
+			return err
+		}
+
+		// A column type change swapped in the new columns and indexes; run
+		// the state machine again to drop the replaced ones.
+		if err := sc.RunStateMachineBeforeBackfill(ctx); err != nil {
+			return err
+		}
+	}
 }
 
 // reverseMutations reverses the direction of all the mutations with the
@@ -2863,6 +2863,137 @@ CREATE TABLE t.test (k INT PRIMARY KEY, v INT);
 	}
 }
 
+// Test TRUNCATE while a column type change is outstanding: the type change
+// completes on the new, empty table.
+func TestTruncateWhileColumnTypeChange(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	params, _ := tests.CreateTestServerParams()
+	params.Knobs.SQLSchemaChanger = &sql.SchemaChangerTestingKnobs{
+		SyncFilter: func(tscc sql.TestingSchemaChangerCollection) {
+			tscc.ClearSchemaChangers()
+		},
+		AsyncExecNotification: asyncSchemaChangerDisabled,
+	}
+	s, sqlDB, kvDB := serverutils.StartServer(t, params)
+	defer s.Stopper().Stop(context.TODO())
+
+	if _, err := sqlDB.Exec(`
+CREATE DATABASE t;
+CREATE TABLE t.test (k INT PRIMARY KEY, v INT, INDEX foo (v));
+INSERT INTO t.test VALUES (1, 1), (2, 2);
+ALTER TABLE t.test ALTER COLUMN v SET DATA TYPE STRING;
+`); err != nil {
+		t.Fatal(err)
+	}
+
+	// The new column and index are outstanding mutations.
+	tableDesc := sqlbase.GetTableDescriptor(kvDB, "t", "test")
+	if len(tableDesc.Mutations) == 0 {
+		t.Fatal("expected outstanding mutations")
+	}
+
+	if _, err := sqlDB.Exec("TRUNCATE TABLE t.test"); err != nil {
+		t.Fatal(err)
+	}
+
+	// The new column replaces the old one, and is neither hidden nor computed.
+	tableDesc = sqlbase.GetTableDescriptor(kvDB, "t", "test")
+	if num := len(tableDesc.Mutations); num > 0 {
+		t.Fatalf("%d outstanding mutation", num)
+	}
+	if lenCols := len(tableDesc.Columns); lenCols != 2 {
+		t.Fatalf("%d columns", lenCols)
+	}
+	col := tableDesc.Columns[1]
+	if col.Name != "v" || col.Type.SemanticType != sqlbase.ColumnType_STRING {
+		t.Fatalf("unexpected column %s %s", col.Name, col.Type.SemanticType)
+	}
+	if col.Hidden || col.IsComputed() {
+		t.Fatalf("column %s is hidden or computed", col.Name)
+	}
+	if lenIndexes := len(tableDesc.Indexes); lenIndexes != 1 {
+		t.Fatalf("%d indexes", lenIndexes)
+	}
+	if idx := tableDesc.Indexes[0]; idx.Name != "foo" || idx.ColumnIDs[0] != col.ID {
+		t.Fatalf("unexpected index %s on columns %v", idx.Name, idx.ColumnIDs)
+	}
+	for _, fam := range tableDesc.Families {
+		if len(fam.ColumnIDs) != 2 {
+			t.Fatalf("unexpected columns %v in family %s", fam.ColumnNames, fam.Name)
+		}
+	}
+
+	if _, err := sqlDB.Exec("INSERT INTO t.test VALUES (1, 'a')"); err != nil {
+		t.Fatal(err)
+	}
+	var v string
+	if err := sqlDB.QueryRow("SELECT v FROM t.test@foo").Scan(&v); err != nil {
+		t.Fatal(err)
+	} else if v != "a" {
+		t.Fatalf("expected a, got %s", v)
+	}
+}
+
+// Test TRUNCATE while a SET NOT NULL is outstanding: the constraint takes
+// effect on the new, empty table, and no validating check is left behind.
+func TestTruncateWhileSetNotNull(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	params, _ := tests.CreateTestServerParams()
+	params.Knobs.SQLSchemaChanger = &sql.SchemaChangerTestingKnobs{
+		SyncFilter: func(tscc sql.TestingSchemaChangerCollection) {
+			tscc.ClearSchemaChangers()
+		},
+		AsyncExecNotification: asyncSchemaChangerDisabled,
+	}
+	s, sqlDB, kvDB := serverutils.StartServer(t, params)
+	defer s.Stopper().Stop(context.TODO())
+
+	if _, err := sqlDB.Exec(`
+CREATE DATABASE t;
+CREATE TABLE t.test (k INT PRIMARY KEY, v INT);
+INSERT INTO t.test VALUES (1, 1), (2, 2);
+ALTER TABLE t.test ALTER COLUMN v SET NOT NULL;
+`); err != nil {
+		t.Fatal(err)
+	}
+
+	// The NOT NULL constraint is enforced by a validating check until the
+	// outstanding mutation completes.
+	tableDesc := sqlbase.GetTableDescriptor(kvDB, "t", "test")
+	if num := len(tableDesc.Mutations); num != 1 {
+		t.Fatalf("%d outstanding mutations", num)
+	}
+	if num := len(tableDesc.Checks); num != 1 {
+		t.Fatalf("%d checks", num)
+	}
+
+	if _, err := sqlDB.Exec("TRUNCATE TABLE t.test"); err != nil {
+		t.Fatal(err)
+	}
+
+	tableDesc = sqlbase.GetTableDescriptor(kvDB, "t", "test")
+	if num := len(tableDesc.Mutations); num > 0 {
+		t.Fatalf("%d outstanding mutation", num)
+	}
+	if num := len(tableDesc.Checks); num > 0 {
+		t.Fatalf("%d checks left: %+v", num, tableDesc.Checks)
+	}
+	if tableDesc.Columns[1].Nullable {
+		t.Fatal("expected column v to be non-nullable")
+	}
+
+	if _, err := sqlDB.Exec("INSERT INTO t.test VALUES (1, NULL)"); !testutils.IsError(
+		err, "violates not-null constraint",
+	) {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if _, err := sqlDB.Exec("INSERT INTO t.test VALUES (1, 1)"); err != nil {
+		t.Fatal(err)
+	}
+}
+
 // Test that, when DDL statements are run in a transaction, their errors are
 // received as the results of the commit statement.
 func TestSchemaChangeErrorOnCommit(t *testing.T) {
@@ -44,6 +44,10 @@ type nameResolutionVisitor struct {
 	// foundStars is set to true if a star is expanded during name
 	// resolution, e.g. in SELECT (kv.*) FROM kv.
 	foundStars bool
+
+	// lateral contains the left sides of the enclosing apply joins, whose
+	// columns are visible when they cannot be found in sources.
+	lateral lateralEnvironment
 }
 
 var _ tree.Visitor = &nameResolutionVisitor{}
@@ -116,8 +120,12 @@ func (v *nameResolutionVisitor) VisitPre(expr tree.Expr) (recurse bool, newNode
 		v.resolver.ResolverState.ForUpdateOrDelete = t.ForUpdateOrDelete
 		_, err := t.Resolve(context.TODO(), &v.resolver)
 		if err != nil {
-			v.err = err
-			return false, expr
+			lateralExpr, err := v.resolveLateralColumn(t, err)
+			if err != nil {
+				v.err = err
+				return false, expr
+			}
+			return false, lateralExpr
 		}
 
 		srcIdx := v.resolver.ResolverState.SrcIdx
@@ -232,6 +240,7 @@ func (p *planner) resolveNames(
 		resolver: sqlbase.ColumnResolver{
 			Sources: sources,
 		},
+		lateral: p.curPlan.lateralEnvironment,
 	}
 	return resolveNamesUsingVisitor(expr, v)
 }
@@ -19,6 +19,8 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"sort"
+	"unsafe"
 
 	"github.com/cockroachdb/apd"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
@@ -140,12 +142,19 @@ var Aggregates = map[string][]tree.Builtin{
 			ReturnType:    tree.FixedReturnType(types.Int),
 			AggregateFunc: newCountRowsAggregate,
 			WindowFunc: func(params []types.T, evalCtx *tree.EvalContext) tree.WindowFunc {
-				return newAggregateWindow(newCountRowsAggregate(params, evalCtx))
+				return newAggregateWindow(func() tree.AggregateFunc {
+					return newCountRowsAggregate(params, evalCtx)
+				})
 			},
 			Info: "Calculates the number of rows.",
 		},
 	},
 
+	// grouping is only meaningful together with GROUPING SETS, ROLLUP or CUBE,
+	// where its result is computed by the grouping operator itself. For a
+	// plain GROUP BY every argument is grouped, so the result is always 0.
+	"grouping": {makeGroupingBuiltin()},
+
 	"max": collectBuiltins(func(t types.T) tree.Builtin {
 		return makeAggBuiltin([]types.T{t}, t, newMaxAggregate,
 			"Identifies the maximum selected value.")
@@ -155,6 +164,33 @@ var Aggregates = map[string][]tree.Builtin{
 			"Identifies the minimum selected value.")
 	}, types.AnyNonArray...),
 
+	"mode": collectBuiltins(func(t types.T) tree.Builtin {
+		return makeOrderedSetAggBuiltin(nil, t, t, newModeAggregate,
+			"Identifies the most frequent sorted value. Ties are resolved in favor of "+
+				"the value that sorts first.")
+	}, types.AnyNonArray...),
+
+	"percentile_disc": makePercentileBuiltins(
+		types.AnyNonArray, func(t types.T) types.T { return t }, newPercentileDiscAggregate,
+		"Identifies the first sorted value whose position is at or above the given "+
+			"fraction of the selected values.",
+		"Identifies, for each of the given fractions, the first sorted value whose "+
+			"position is at or above that fraction of the selected values."),
+
+	"percentile_cont": makePercentileBuiltins(
+		[]types.T{types.Int, types.Float, types.Decimal, types.Interval},
+		func(t types.T) types.T {
+			if t == types.Interval {
+				return types.Interval
+			}
+			return types.Float
+		},
+		newPercentileContAggregate,
+		"Calculates the value at the given fraction of the sorted values, "+
+			"interpolating linearly between adjacent values if needed.",
+		"Calculates the values at each of the given fractions of the sorted values, "+
+			"interpolating linearly between adjacent values if needed."),
+
 	"sum_int": {
 		makeAggBuiltin([]types.T{types.Int}, types.Int, newSmallIntSumAggregate,
 			"Calculates the sum of the selected values."),
@@ -272,13 +308,78 @@ func makeAggBuiltinWithReturnType(
 		ReturnType:    retType,
 		AggregateFunc: f,
 		WindowFunc: func(params []types.T, evalCtx *tree.EvalContext) tree.WindowFunc {
-			return newAggregateWindow(f(params, evalCtx))
+			return newAggregateWindow(func() tree.AggregateFunc {
+				return f(params, evalCtx)
+			})
 		},
 		Info:         info,
 		NullableArgs: nullableArgs,
 	}
 }
 
+// makeGroupingBuiltin creates the overload of GROUPING, which takes one or
+// more grouping expressions of any type.
+func makeGroupingBuiltin() tree.Builtin {
+	b := makeAggBuiltinWithReturnType(nil, tree.FixedReturnType(types.Int), newGroupingAggregate,
+		"Returns a bit mask indicating which of the given grouping expressions are "+
+			"not included in the current grouping set. The last argument corresponds "+
+			"to the least significant bit.",
+		true /* nullableArgs */)
+	b.Types = tree.VariadicType{FixedTypes: []types.T{types.Any}, VarType: types.Any}
+	return b
+}
+
+// makeOrderedSetAggBuiltin creates the overload of an ordered-set aggregate
+// with the given direct argument types. The type of the WITHIN GROUP sort
+// expression is passed separately and becomes the last argument.
+func makeOrderedSetAggBuiltin(
+	direct []types.T,
+	sortTyp types.T,
+	ret types.T,
+	f func([]types.T, *tree.EvalContext) tree.AggregateFunc,
+	info string,
+) tree.Builtin {
+	// NULL direct arguments produce a NULL result, but only once all the
+	// rows of the group have been aggregated.
+	b := makeAggBuiltinWithReturnType(
+		append(append([]types.T(nil), direct...), sortTyp), tree.FixedReturnType(ret), f, info,
+		true /* nullableArgs */)
+	if len(direct) > 0 {
+		b.Types.(tree.ArgTypes)[0].Name = "fraction"
+	}
+	b.Types.(tree.ArgTypes)[len(direct)].Name = "sort_value"
+	b.OrderedSetAggregate = true
+	return b
+}
+
+// makePercentileBuiltins creates the overloads of a percentile ordered-set
+// aggregate for the given sort value types. The fraction can be given either
+// as a single value or as an array, in which case the result is an array of
+// the results for each fraction.
+func makePercentileBuiltins(
+	sortTypes []types.T,
+	retType func(types.T) types.T,
+	f func([]types.T, *tree.EvalContext) tree.AggregateFunc,
+	info string,
+	arrayInfo string,
+) []tree.Builtin {
+	var r []tree.Builtin
+	for _, t := range sortTypes {
+		ret := retType(t)
+		r = append(r, makeOrderedSetAggBuiltin([]types.T{types.Float}, t, ret, f, info))
+		if !types.IsValidArrayElementType(ret) {
+			continue
+		}
+		// Array literals of fractions like ARRAY[0.5, 0.9] are typed as
+		// DECIMAL[], so accept those as well.
+		for _, fractionTyp := range []types.T{types.Float, types.Decimal} {
+			r = append(r, makeOrderedSetAggBuiltin(
+				[]types.T{types.TArray{Typ: fractionTyp}}, t, types.TArray{Typ: ret}, f, arrayInfo))
+		}
+	}
+	return r
+}
+
 var _ tree.AggregateFunc = &arrayAggregate{}
 var _ tree.AggregateFunc = &avgAggregate{}
 var _ tree.AggregateFunc = &countAggregate{}
@@ -297,9 +398,13 @@ var _ tree.AggregateFunc = &decimalVarianceAggregate{}
 var _ tree.AggregateFunc = &floatStdDevAggregate{}
 var _ tree.AggregateFunc = &decimalStdDevAggregate{}
 var _ tree.AggregateFunc = &identAggregate{}
+var _ tree.AggregateFunc = &groupingAggregate{}
 var _ tree.AggregateFunc = &concatAggregate{}
 var _ tree.AggregateFunc = &bytesXorAggregate{}
 var _ tree.AggregateFunc = &intXorAggregate{}
+var _ tree.AggregateFunc = &modeAggregate{}
+var _ tree.AggregateFunc = &percentileDiscAggregate{}
+var _ tree.AggregateFunc = &percentileContAggregate{}
 
 // In order to render the unaggregated (i.e. grouped) fields, during aggregation,
 // the values for those fields have to be stored for each bucket.
@@ -345,6 +450,28 @@ func (a *identAggregate) Result() (tree.Datum, error) {
 // Close is no-op in aggregates using constant space.
 func (a *identAggregate) Close(context.Context) {}
 
+// groupingAggregate implements GROUPING() for queries without grouping sets,
+// where it always returns 0. With grouping sets the grouping operator
+// computes the result directly from the grouping set of each group.
+type groupingAggregate struct{}
+
+func newGroupingAggregate(_ []types.T, _ *tree.EvalContext) tree.AggregateFunc {
+	return &groupingAggregate{}
+}
+
+// Add is a no-op.
+func (a *groupingAggregate) Add(context.Context, tree.Datum, ...tree.Datum) error {
+	return nil
+}
+
+// Result returns 0.
+func (a *groupingAggregate) Result() (tree.Datum, error) {
+	return tree.NewDInt(0), nil
+}
+
+// Close is no-op in aggregates using constant space.
+func (a *groupingAggregate) Close(context.Context) {}
+
 type arrayAggregate struct {
 	arr *tree.DArray
 	acc mon.BoundAccount
@@ -1009,7 +1136,8 @@ func (a *decimalSqrDiffAggregate) Result() (tree.Datum, error) {
 	if a.count.Cmp(decimalOne) < 0 {
 		return tree.DNull, nil
 	}
-	dd := &tree.DDecimal{Decimal: a.sqrDiff}
+	dd := &tree.DDecimal{}
+	dd.Set(&a.sqrDiff)
 	// Remove trailing zeros. Depending on the order in which the input
 	// is processed, some number of trailing zeros could be added to the
 	// output. Remove them so that the results are the same regardless of order.
@@ -1164,7 +1292,8 @@ func (a *decimalSumSqrDiffsAggregate) Result() (tree.Datum, error) {
 	if a.count.Cmp(decimalOne) < 0 {
 		return tree.DNull, nil
 	}
-	dd := &tree.DDecimal{Decimal: a.sqrDiff}
+	dd := &tree.DDecimal{}
+	dd.Set(&a.sqrDiff)
 	return dd, nil
 }
 
@@ -1482,3 +1611,254 @@ func (a *jsonAggregate) Result() (tree.Datum, error) {
 func (a *jsonAggregate) Close(ctx context.Context) {
 	a.acc.Close(ctx)
 }
+
+const sizeOfDatum = int64(unsafe.Sizeof(tree.Datum(nil)))
+
+// sortedValues buffers the WITHIN GROUP sort values of an ordered-set
+// aggregate, which can only be computed once all the values are known.
+// NULL values are skipped, as in Postgres. The memory used by the values is
+// accounted against the monitor of the evaluation context, which is the
+// monitor of the aggregator when running in DistSQL.
+type sortedValues struct {
+	evalCtx *tree.EvalContext
+	values  tree.Datums
+	acc     mon.BoundAccount
+	sorted  bool
+}
+
+func makeSortedValues(evalCtx *tree.EvalContext) sortedValues {
+	return sortedValues{evalCtx: evalCtx, acc: evalCtx.Mon.MakeBoundAccount()}
+}
+
+func (s *sortedValues) add(ctx context.Context, datum tree.Datum) error {
+	if datum == tree.DNull {
+		return nil
+	}
+	if err := s.acc.Grow(ctx, sizeOfDatum+int64(datum.Size())); err != nil {
+		return err
+	}
+	s.values = append(s.values, datum)
+	s.sorted = false
+	return nil
+}
+
+// sort sorts the buffered values in ascending order.
+func (s *sortedValues) sort() {
+	if !s.sorted {
+		sort.Slice(s.values, func(i, j int) bool {
+			return s.values[i].Compare(s.evalCtx, s.values[j]) < 0
+		})
+		s.sorted = true
+	}
+}
+
+func (s *sortedValues) close(ctx context.Context) {
+	s.values = nil
+	s.acc.Close(ctx)
+}
+
+type modeAggregate struct {
+	vals sortedValues
+}
+
+func newModeAggregate(_ []types.T, evalCtx *tree.EvalContext) tree.AggregateFunc {
+	return &modeAggregate{vals: makeSortedValues(evalCtx)}
+}
+
+// Add buffers the passed datum.
+func (a *modeAggregate) Add(ctx context.Context, datum tree.Datum, _ ...tree.Datum) error {
+	return a.vals.add(ctx, datum)
+}
+
+// Result returns the most frequent value passed to Add.
+func (a *modeAggregate) Result() (tree.Datum, error) {
+	if len(a.vals.values) == 0 {
+		return tree.DNull, nil
+	}
+	a.vals.sort()
+	values := a.vals.values
+	var mode tree.Datum
+	modeCount := 0
+	for i := 0; i < len(values); {
+		j := i + 1
+		for j < len(values) && values[j].Compare(a.vals.evalCtx, values[i]) == 0 {
+			j++
+		}
+		if j-i > modeCount {
+			mode, modeCount = values[i], j-i
+		}
+		i = j
+	}
+	return mode, nil
+}
+
+// Close allows the aggregate to release the memory it requested during
+// operation.
+func (a *modeAggregate) Close(ctx context.Context) {
+	a.vals.close(ctx)
+}
+
+// percentileFractions holds the direct argument of a percentile aggregate,
+// either a single fraction or an array of fractions. It is evaluated once per
+// group, so the value from the first row is used.
+type percentileFractions struct {
+	datum tree.Datum
+}
+
+func (f *percentileFractions) set(datum tree.Datum) {
+	if f.datum == nil {
+		f.datum = datum
+	}
+}
+
+// result calls fn for every fraction and returns the results in the shape of
+// the direct argument: a single datum for a single fraction, an array of
+// datums of type typ for an array of fractions. NULL fractions produce NULL
+// results.
+func (f *percentileFractions) result(
+	typ types.T, fn func(fraction float64) (tree.Datum, error),
+) (tree.Datum, error) {
+	get := func(d tree.Datum) (tree.Datum, error) {
+		if d == tree.DNull {
+			return tree.DNull, nil
+		}
+		var fraction float64
+		switch t := d.(type) {
+		case *tree.DFloat:
+			fraction = float64(*t)
+		case *tree.DDecimal:
+			var err error
+			if fraction, err = t.Float64(); err != nil {
+				return nil, err
+			}
+		}
+		if fraction < 0 || fraction > 1 || math.IsNaN(fraction) {
+			return nil, pgerror.NewErrorf(pgerror.CodeNumericValueOutOfRangeError,
+				"percentile value %g is not between 0 and 1", fraction)
+		}
+		return fn(fraction)
+	}
+	arr, ok := f.datum.(*tree.DArray)
+	if !ok {
+		return get(f.datum)
+	}
+	res := tree.NewDArray(typ)
+	for _, d := range arr.Array {
+		r, err := get(d)
+		if err != nil {
+			return nil, err
+		}
+		if err := res.Append(r); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+type percentileDiscAggregate struct {
+	fractions percentileFractions
+	typ       types.T
+	vals      sortedValues
+}
+
+func newPercentileDiscAggregate(params []types.T, evalCtx *tree.EvalContext) tree.AggregateFunc {
+	return &percentileDiscAggregate{typ: params[1], vals: makeSortedValues(evalCtx)}
+}
+
+// Add buffers the passed sort value.
+func (a *percentileDiscAggregate) Add(
+	ctx context.Context, fraction tree.Datum, others ...tree.Datum,
+) error {
+	a.fractions.set(fraction)
+	return a.vals.add(ctx, others[0])
+}
+
+// Result returns the first value whose position in the sort order is at or
+// above the requested fraction(s).
+func (a *percentileDiscAggregate) Result() (tree.Datum, error) {
+	if len(a.vals.values) == 0 || a.fractions.datum == tree.DNull {
+		return tree.DNull, nil
+	}
+	a.vals.sort()
+	values := a.vals.values
+	return a.fractions.result(a.typ, func(fraction float64) (tree.Datum, error) {
+		idx := int(math.Ceil(fraction*float64(len(values)))) - 1
+		if idx < 0 {
+			idx = 0
+		}
+		return values[idx], nil
+	})
+}
+
+// Close allows the aggregate to release the memory it requested during
+// operation.
+func (a *percentileDiscAggregate) Close(ctx context.Context) {
+	a.vals.close(ctx)
+}
+
+type percentileContAggregate struct {
+	fractions percentileFractions
+	typ       types.T
+	vals      sortedValues
+}
+
+func newPercentileContAggregate(params []types.T, evalCtx *tree.EvalContext) tree.AggregateFunc {
+	typ := types.T(types.Float)
+	if params[1] == types.Interval {
+		typ = types.Interval
+	}
+	return &percentileContAggregate{typ: typ, vals: makeSortedValues(evalCtx)}
+}
+
+// Add buffers the passed sort value. Numeric values are converted to floats.
+func (a *percentileContAggregate) Add(
+	ctx context.Context, fraction tree.Datum, others ...tree.Datum,
+) error {
+	a.fractions.set(fraction)
+	datum := others[0]
+	switch t := datum.(type) {
+	case *tree.DInt:
+		datum = tree.NewDFloat(tree.DFloat(*t))
+	case *tree.DDecimal:
+		f, err := t.Float64()
+		if err != nil {
+			return err
+		}
+		datum = tree.NewDFloat(tree.DFloat(f))
+	}
+	return a.vals.add(ctx, datum)
+}
+
+// Result returns the value at the requested fraction(s) of the sort order,
+// interpolating between the two closest values.
+func (a *percentileContAggregate) Result() (tree.Datum, error) {
+	if len(a.vals.values) == 0 || a.fractions.datum == tree.DNull {
+		return tree.DNull, nil
+	}
+	a.vals.sort()
+	values := a.vals.values
+	return a.fractions.result(a.typ, func(fraction float64) (tree.Datum, error) {
+		pos := fraction * float64(len(values)-1)
+		lower, upper := values[int(math.Floor(pos))], values[int(math.Ceil(pos))]
+		proportion := pos - math.Floor(pos)
+		switch l := lower.(type) {
+		case *tree.DFloat:
+			u := float64(*upper.(*tree.DFloat))
+			return tree.NewDFloat(tree.DFloat(float64(*l) + (u-float64(*l))*proportion)), nil
+		case *tree.DInterval:
+			u := upper.(*tree.DInterval).Duration
+			return &tree.DInterval{
+				Duration: l.Duration.Add(u.Sub(l.Duration).MulFloat(proportion)),
+			}, nil
+		default:
+			return nil, pgerror.NewErrorf(pgerror.CodeInternalError,
+				"unexpected percentile_cont value of type %s", lower.ResolvedType())
+		}
+	})
+}
+
+// Close allows the aggregate to release the memory it requested during
+// operation.
+func (a *percentileContAggregate) Close(ctx context.Context) {
+	a.vals.close(ctx)
+}
@@ -1533,6 +1533,67 @@ CockroachDB supports the following flags:
 		},
 	},
 
+	// https://www.postgresql.org/docs/10/static/functions-datetime.html#FUNCTIONS-DATETIME-ZONECONVERT
+	//
+	// `ts AT TIME ZONE zone` is parsed as `timezone(zone, ts)`.
+	"timezone": {
+		tree.Builtin{
+			Types:      tree.ArgTypes{{"timezone", types.String}, {"timestamp", types.Timestamp}},
+			ReturnType: tree.FixedReturnType(types.TimestampTZ),
+			Category:   categoryDateAndTime,
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				loc, err := timeutil.TimeZoneStringToLocation(string(tree.MustBeDString(args[0])))
+				if err != nil {
+					return nil, pgerror.Wrapf(err, pgerror.CodeInvalidParameterValueError, "timezone()")
+				}
+				return tree.TimestampAtTimeZone(args[1].(*tree.DTimestamp), loc), nil
+			},
+			Info: "Treats `timestamp` as the local time in `timezone` and converts it to a\n" +
+				"timestamp with time zone.",
+		},
+		tree.Builtin{
+			Types:      tree.ArgTypes{{"timezone", types.String}, {"timestamptz", types.TimestampTZ}},
+			ReturnType: tree.FixedReturnType(types.Timestamp),
+			Category:   categoryDateAndTime,
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				loc, err := timeutil.TimeZoneStringToLocation(string(tree.MustBeDString(args[0])))
+				if err != nil {
+					return nil, pgerror.Wrapf(err, pgerror.CodeInvalidParameterValueError, "timezone()")
+				}
+				return tree.TimestampTZAtTimeZone(args[1].(*tree.DTimestampTZ), loc), nil
+			},
+			Info: "Converts `timestamptz` to the local time in `timezone`, without time zone.",
+		},
+		tree.Builtin{
+			Types:      tree.ArgTypes{{"timezone", types.Interval}, {"timestamp", types.Timestamp}},
+			ReturnType: tree.FixedReturnType(types.TimestampTZ),
+			Category:   categoryDateAndTime,
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				loc, err := tree.TimeZoneIntervalToLocation(args[0].(*tree.DInterval))
+				if err != nil {
+					return nil, err
+				}
+				return tree.TimestampAtTimeZone(args[1].(*tree.DTimestamp), loc), nil
+			},
+			Info: "Treats `timestamp` as the local time at the UTC offset `timezone` and\n" +
+				"converts it to a timestamp with time zone.",
+		},
+		tree.Builtin{
+			Types:      tree.ArgTypes{{"timezone", types.Interval}, {"timestamptz", types.TimestampTZ}},
+			ReturnType: tree.FixedReturnType(types.Timestamp),
+			Category:   categoryDateAndTime,
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				loc, err := tree.TimeZoneIntervalToLocation(args[0].(*tree.DInterval))
+				if err != nil {
+					return nil, err
+				}
+				return tree.TimestampTZAtTimeZone(args[1].(*tree.DTimestampTZ), loc), nil
+			},
+			Info: "Converts `timestamptz` to the local time at the UTC offset `timezone`,\n" +
+				"without time zone.",
+		},
+	},
+
 	// Math functions
 	"abs": {
 		floatBuiltin1(func(x float64) (tree.Datum, error) {
@@ -137,31 +137,37 @@ var _ tree.WindowFunc = &nthValueWindow{}
 // aggregateWindowFunc aggregates over the the current row's window frame, using
 // the internal tree.AggregateFunc to perform the aggregation.
 type aggregateWindowFunc struct {
+	newAgg  func() tree.AggregateFunc
 	agg     tree.AggregateFunc
 	peerRes tree.Datum
+
+	// frameAgg is used instead of agg when the window has a frame other than
+	// the default one. It is created lazily on the first call to Compute.
+	frameAgg windowFrameAggregator
 }
 
-func newAggregateWindow(agg tree.AggregateFunc) tree.WindowFunc {
-	return &aggregateWindowFunc{agg: agg}
+func newAggregateWindow(newAgg func() tree.AggregateFunc) tree.WindowFunc {
+	return &aggregateWindowFunc{newAgg: newAgg}
 }
 
 func (w *aggregateWindowFunc) Compute(
-	ctx context.Context, evalCtx *tree.EvalContext, wf tree.WindowFrame,
+	ctx context.Context, evalCtx *tree.EvalContext, wfr *tree.WindowFrameRun,
 ) (tree.Datum, error) {
-	if !wf.FirstInPeerGroup() {
+	if !wfr.IsDefaultFrame() {
+		return w.computeFrame(ctx, evalCtx, wfr)
+	}
+
+	if !wfr.FirstInPeerGroup() {
 		return w.peerRes, nil
 	}
+	if w.agg == nil {
+		w.agg = w.newAgg()
+	}
 
 	// Accumulate all values in the peer group at the same time, as these
 	// must return the same value.
-	for i := 0; i < wf.PeerRowCount; i++ {
-		args := wf.ArgsWithRowOffset(i)
-		var value tree.Datum
-		// COUNT_ROWS takes no arguments.
-		if len(args) > 0 {
-			value = args[0]
-		}
-		if err := w.agg.Add(ctx, value); err != nil {
+	for i := 0; i < wfr.PeerRowCount; i++ {
+		if err := w.agg.Add(ctx, aggregateWindowArg(wfr, wfr.RowIdx+i)); err != nil {
 			return nil, err
 		}
 	}
@@ -176,8 +182,43 @@ func (w *aggregateWindowFunc) Compute(
 	return w.peerRes, nil
 }
 
+// computeFrame aggregates over the rows of an explicitly specified window
+// frame, which may not contain all the rows preceding the current row.
+func (w *aggregateWindowFunc) computeFrame(
+	ctx context.Context, evalCtx *tree.EvalContext, wfr *tree.WindowFrameRun,
+) (tree.Datum, error) {
+	start, err := wfr.FrameStartIdx(evalCtx)
+	if err != nil {
+		return nil, err
+	}
+	end, err := wfr.FrameEndIdx(evalCtx)
+	if err != nil {
+		return nil, err
+	}
+	if w.frameAgg == nil {
+		w.frameAgg = newWindowFrameAggregator(ctx, w.newAgg)
+	}
+	return w.frameAgg.compute(ctx, evalCtx, wfr, start, end)
+}
+
+// aggregateWindowArg returns the value passed to the aggregate function for
+// the row at idx in the partition.
+func aggregateWindowArg(wfr *tree.WindowFrameRun, idx int) tree.Datum {
+	args := wfr.ArgsByRowIdx(idx)
+	// COUNT_ROWS takes no arguments.
+	if len(args) == 0 {
+		return nil
+	}
+	return args[0]
+}
+
 func (w *aggregateWindowFunc) Close(ctx context.Context, evalCtx *tree.EvalContext) {
-	w.agg.Close(ctx)
+	if w.agg != nil {
+		w.agg.Close(ctx)
+	}
+	if w.frameAgg != nil {
+		w.frameAgg.close(ctx)
+	}
 }
 
 // rowNumberWindow computes the number of the current row within its partition,
@@ -189,9 +230,9 @@ func newRowNumberWindow([]types.T, *tree.EvalContext) tree.WindowFunc {
 }
 
 func (rowNumberWindow) Compute(
-	_ context.Context, _ *tree.EvalContext, wf tree.WindowFrame,
+	_ context.Context, _ *tree.EvalContext, wfr *tree.WindowFrameRun,
 ) (tree.Datum, error) {
-	return tree.NewDInt(tree.DInt(wf.RowIdx + 1 /* one-indexed */)), nil
+	return tree.NewDInt(tree.DInt(wfr.RowIdx + 1 /* one-indexed */)), nil
 }
 
 func (rowNumberWindow) Close(context.Context, *tree.EvalContext) {}
@@ -206,10 +247,10 @@ func newRankWindow([]types.T, *tree.EvalContext) tree.WindowFunc {
 }
 
 func (w *rankWindow) Compute(
-	_ context.Context, _ *tree.EvalContext, wf tree.WindowFrame,
+	_ context.Context, _ *tree.EvalContext, wfr *tree.WindowFrameRun,
 ) (tree.Datum, error) {
-	if wf.FirstInPeerGroup() {
-		w.peerRes = tree.NewDInt(tree.DInt(wf.Rank()))
+	if wfr.FirstInPeerGroup() {
+		w.peerRes = tree.NewDInt(tree.DInt(wfr.Rank()))
 	}
 	return w.peerRes, nil
 }
@@ -227,9 +268,9 @@ func newDenseRankWindow([]types.T, *tree.EvalContext) tree.WindowFunc {
 }
 
 func (w *denseRankWindow) Compute(
-	_ context.Context, _ *tree.EvalContext, wf tree.WindowFrame,
+	_ context.Context, _ *tree.EvalContext, wfr *tree.WindowFrameRun,
 ) (tree.Datum, error) {
-	if wf.FirstInPeerGroup() {
+	if wfr.FirstInPeerGroup() {
 		w.denseRank++
 		w.peerRes = tree.NewDInt(tree.DInt(w.denseRank))
 	}
@@ -251,16 +292,16 @@ func newPercentRankWindow([]types.T, *tree.EvalContext) tree.WindowFunc {
 var dfloatZero = tree.NewDFloat(0)
 
 func (w *percentRankWindow) Compute(
-	_ context.Context, _ *tree.EvalContext, wf tree.WindowFrame,
+	_ context.Context, _ *tree.EvalContext, wfr *tree.WindowFrameRun,
 ) (tree.Datum, error) {
 	// Return zero if there's only one row, per spec.
-	if wf.RowCount() <= 1 {
+	if wfr.RowCount() <= 1 {
 		return dfloatZero, nil
 	}
 
-	if wf.FirstInPeerGroup() {
+	if wfr.FirstInPeerGroup() {
 		// (rank - 1) / (total rows - 1)
-		w.peerRes = tree.NewDFloat(tree.DFloat(wf.Rank()-1) / tree.DFloat(wf.RowCount()-1))
+		w.peerRes = tree.NewDFloat(tree.DFloat(wfr.Rank()-1) / tree.DFloat(wfr.RowCount()-1))
 	}
 	return w.peerRes, nil
 }
@@ -278,11 +319,11 @@ func newCumulativeDistWindow([]types.T, *tree.EvalContext) tree.WindowFunc {
 }
 
 func (w *cumulativeDistWindow) Compute(
-	_ context.Context, _ *tree.EvalContext, wf tree.WindowFrame,
+	_ context.Context, _ *tree.EvalContext, wfr *tree.WindowFrameRun,
 ) (tree.Datum, error) {
-	if wf.FirstInPeerGroup() {
+	if wfr.FirstInPeerGroup() {
 		// (number of rows preceding or peer with current row) / (total rows)
-		w.peerRes = tree.NewDFloat(tree.DFloat(wf.FrameSize()) / tree.DFloat(wf.RowCount()))
+		w.peerRes = tree.NewDFloat(tree.DFloat(wfr.DefaultFrameSize()) / tree.DFloat(wfr.RowCount()))
 	}
 	return w.peerRes, nil
 }
@@ -306,13 +347,13 @@ var errInvalidArgumentForNtile = pgerror.NewErrorf(
 	pgerror.CodeInvalidParameterValueError, "argument of ntile() must be greater than zero")
 
 func (w *ntileWindow) Compute(
-	_ context.Context, _ *tree.EvalContext, wf tree.WindowFrame,
+	_ context.Context, _ *tree.EvalContext, wfr *tree.WindowFrameRun,
 ) (tree.Datum, error) {
 	if w.ntile == nil {
 		// If this is the first call to ntileWindow.Compute, set up the buckets.
-		total := wf.RowCount()
+		total := wfr.RowCount()
 
-		arg := wf.Args()[0]
+		arg := wfr.Args()[0]
 		if arg == tree.DNull {
 			// per spec: If argument is the null value, then the result is the null value.
 			return tree.DNull, nil
@@ -376,11 +417,11 @@ func makeLeadLagWindowConstructor(
 }
 
 func (w *leadLagWindow) Compute(
-	_ context.Context, _ *tree.EvalContext, wf tree.WindowFrame,
+	_ context.Context, _ *tree.EvalContext, wfr *tree.WindowFrameRun,
 ) (tree.Datum, error) {
 	offset := 1
 	if w.withOffset {
-		offsetArg := wf.Args()[1]
+		offsetArg := wfr.Args()[1]
 		if offsetArg == tree.DNull {
 			return tree.DNull, nil
 		}
@@ -390,16 +431,16 @@ func (w *leadLagWindow) Compute(
 		offset *= -1
 	}
 
-	if targetRow := wf.RowIdx + offset; targetRow < 0 || targetRow >= wf.RowCount() {
+	if targetRow := wfr.RowIdx + offset; targetRow < 0 || targetRow >= wfr.RowCount() {
 		// Target row is out of the partition; supply default value if provided,
 		// otherwise return NULL.
 		if w.withDefault {
-			return wf.Args()[2], nil
+			return wfr.Args()[2], nil
 		}
 		return tree.DNull, nil
 	}
 
-	return wf.ArgsWithRowOffset(offset)[0], nil
+	return wfr.ArgsWithRowOffset(offset)[0], nil
 }
 
 func (w *leadLagWindow) Close(context.Context, *tree.EvalContext) {}
@@ -412,9 +453,21 @@ func newFirstValueWindow([]types.T, *tree.EvalContext) tree.WindowFunc {
 }
 
 func (firstValueWindow) Compute(
-	_ context.Context, _ *tree.EvalContext, wf tree.WindowFrame,
+	_ context.Context, evalCtx *tree.EvalContext, wfr *tree.WindowFrameRun,
 ) (tree.Datum, error) {
-	return wf.Rows[0].Row[wf.ArgIdxStart], nil
+	start, err := wfr.FrameStartIdx(evalCtx)
+	if err != nil {
+		return nil, err
+	}
+	end, err := wfr.FrameEndIdx(evalCtx)
+	if err != nil {
+		return nil, err
+	}
+	if start >= end {
+		// The window frame is empty.
+		return tree.DNull, nil
+	}
+	return wfr.Rows[start].Row[wfr.ArgIdxStart], nil
 }
 
 func (firstValueWindow) Close(context.Context, *tree.EvalContext) {}
@@ -427,9 +480,21 @@ func newLastValueWindow([]types.T, *tree.EvalContext) tree.WindowFunc {
 }
 
 func (lastValueWindow) Compute(
-	_ context.Context, _ *tree.EvalContext, wf tree.WindowFrame,
+	_ context.Context, evalCtx *tree.EvalContext, wfr *tree.WindowFrameRun,
 ) (tree.Datum, error) {
-	return wf.Rows[wf.FrameSize()-1].Row[wf.ArgIdxStart], nil
+	start, err := wfr.FrameStartIdx(evalCtx)
+	if err != nil {
+		return nil, err
+	}
+	end, err := wfr.FrameEndIdx(evalCtx)
+	if err != nil {
+		return nil, err
+	}
+	if start >= end {
+		// The window frame is empty.
+		return tree.DNull, nil
+	}
+	return wfr.Rows[end-1].Row[wfr.ArgIdxStart], nil
 }
 
 func (lastValueWindow) Close(context.Context, *tree.EvalContext) {}
@@ -446,9 +511,9 @@ var errInvalidArgumentForNthValue = pgerror.NewErrorf(
 	pgerror.CodeInvalidParameterValueError, "argument of nth_value() must be greater than zero")
 
 func (nthValueWindow) Compute(
-	_ context.Context, _ *tree.EvalContext, wf tree.WindowFrame,
+	_ context.Context, evalCtx *tree.EvalContext, wfr *tree.WindowFrameRun,
 ) (tree.Datum, error) {
-	arg := wf.Args()[1]
+	arg := wfr.Args()[1]
 	if arg == tree.DNull {
 		return tree.DNull, nil
 	}
@@ -460,10 +525,18 @@ func (nthValueWindow) Compute(
 
 	// per spec: Only consider the rows within the "window frame", which by default contains
 	// the rows from the start of the partition through the last peer of the current row.
-	if nth > wf.FrameSize() {
+	start, err := wfr.FrameStartIdx(evalCtx)
+	if err != nil {
+		return nil, err
+	}
+	end, err := wfr.FrameEndIdx(evalCtx)
+	if err != nil {
+		return nil, err
+	}
+	if nth > end-start {
 		return tree.DNull, nil
 	}
-	return wf.Rows[nth-1].Row[wf.ArgIdxStart], nil
+	return wfr.Rows[start+nth-1].Row[wfr.ArgIdxStart], nil
 }
 
 func (nthValueWindow) Close(context.Context, *tree.EvalContext) {}
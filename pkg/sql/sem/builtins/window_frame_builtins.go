@@ -0,0 +1,407 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package builtins
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/cockroachdb/apd"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/duration"
+)
+
+// windowFrameAggregator computes the result of an aggregate function over
+// the window frame of each row of a partition. It is called for every row
+// of the partition in turn, with the bounds [start, end) of the row's frame.
+type windowFrameAggregator interface {
+	compute(
+		ctx context.Context, evalCtx *tree.EvalContext, wfr *tree.WindowFrameRun, start, end int,
+	) (tree.Datum, error)
+	close(ctx context.Context)
+}
+
+// newWindowFrameAggregator returns the most efficient windowFrameAggregator
+// for the aggregate functions created by newAgg. Aggregates from which values
+// can be removed use a slidingWindowAggregator, so that each row of the
+// partition is only added to and removed from the aggregate once. Other
+// aggregates are recomputed for each frame, unless the frame only grows.
+func newWindowFrameAggregator(
+	ctx context.Context, newAgg func() tree.AggregateFunc,
+) windowFrameAggregator {
+	agg := newAgg()
+	defer agg.Close(ctx)
+
+	var removable removableAggregate
+	switch t := agg.(type) {
+	case *MaxAggregate:
+		removable = &slidingMinMax{max: true}
+	case *MinAggregate:
+		removable = &slidingMinMax{}
+	case *countAggregate:
+		removable = &slidingCount{}
+	case *countRowsAggregate:
+		removable = &slidingCount{countNulls: true}
+	case *smallIntSumAggregate:
+		removable = &slidingSmallIntSum{}
+	case *intSumAggregate, *decimalSumAggregate:
+		removable = &slidingDecimalSum{}
+	case *intervalSumAggregate:
+		removable = &slidingIntervalSum{}
+	case *avgAggregate:
+		switch t.agg.(type) {
+		case *intSumAggregate, *decimalSumAggregate:
+			removable = &slidingDecimalSum{avg: true}
+		}
+	}
+	if removable == nil {
+		// Floating point sums are not removable either, as subtracting values
+		// from them would accumulate rounding errors.
+		return &recomputingWindowAggregator{newAgg: newAgg}
+	}
+	return &slidingWindowAggregator{agg: removable}
+}
+
+// recomputingWindowAggregator is a windowFrameAggregator that recomputes the
+// aggregate from scratch whenever a row leaves the window frame. Rows that
+// enter the frame are added to the previous aggregate, so frames that start
+// at the beginning of the partition are computed in linear time.
+type recomputingWindowAggregator struct {
+	newAgg func() tree.AggregateFunc
+	agg    tree.AggregateFunc
+
+	// start and end are the bounds of the frame aggregated by agg, and res is
+	// its result.
+	start, end int
+	res        tree.Datum
+}
+
+func (a *recomputingWindowAggregator) compute(
+	ctx context.Context, _ *tree.EvalContext, wfr *tree.WindowFrameRun, start, end int,
+) (tree.Datum, error) {
+	if a.agg != nil && start == a.start && end == a.end {
+		return a.res, nil
+	}
+	if a.agg == nil || start != a.start || end < a.end {
+		if a.agg != nil {
+			a.agg.Close(ctx)
+		}
+		a.agg = a.newAgg()
+		a.start, a.end = start, start
+	}
+	for ; a.end < end; a.end++ {
+		if err := a.agg.Add(ctx, aggregateWindowArg(wfr, a.end)); err != nil {
+			return nil, err
+		}
+	}
+	res, err := a.agg.Result()
+	if err != nil {
+		return nil, err
+	}
+	a.res = res
+	return a.res, nil
+}
+
+func (a *recomputingWindowAggregator) close(ctx context.Context) {
+	if a.agg != nil {
+		a.agg.Close(ctx)
+	}
+}
+
+// removableAggregate is an aggregate from which the values previously added
+// to it can be removed, in the order in which they were added.
+type removableAggregate interface {
+	add(evalCtx *tree.EvalContext, idx int, datum tree.Datum) error
+	remove(evalCtx *tree.EvalContext, idx int, datum tree.Datum) error
+	result() (tree.Datum, error)
+	reset()
+}
+
+// slidingWindowAggregator is a windowFrameAggregator that maintains a
+// removableAggregate over the current window frame. Since the bounds of the
+// frame never move backwards from one row to the next, the aggregation over
+// a partition runs in linear time (amortized, for min and max).
+type slidingWindowAggregator struct {
+	agg removableAggregate
+
+	// start and end are the bounds of the frame aggregated by agg.
+	start, end int
+}
+
+func (a *slidingWindowAggregator) compute(
+	_ context.Context, evalCtx *tree.EvalContext, wfr *tree.WindowFrameRun, start, end int,
+) (tree.Datum, error) {
+	if start < a.start || end < a.end || start >= a.end {
+		// The new frame does not overlap with the previous one (or the frame
+		// moved backwards, which is not expected): start from scratch.
+		a.agg.reset()
+		a.start, a.end = start, start
+	}
+	for ; a.end < end; a.end++ {
+		if err := a.agg.add(evalCtx, a.end, aggregateWindowArg(wfr, a.end)); err != nil {
+			return nil, err
+		}
+	}
+	for ; a.start < start; a.start++ {
+		if err := a.agg.remove(evalCtx, a.start, aggregateWindowArg(wfr, a.start)); err != nil {
+			return nil, err
+		}
+	}
+	return a.agg.result()
+}
+
+func (a *slidingWindowAggregator) close(context.Context) {}
+
+// slidingMinMax computes min or max over a sliding window using a deque of
+// the values that can still become the result as the window slides: each
+// value in the deque is at least as good as all the values that follow it.
+type slidingMinMax struct {
+	max  bool
+	idxs []int
+	vals tree.Datums
+}
+
+func (a *slidingMinMax) add(evalCtx *tree.EvalContext, idx int, datum tree.Datum) error {
+	if datum == tree.DNull {
+		return nil
+	}
+	// Values in the deque that are worse than the new value will leave the
+	// window before it, so they can never become the result. Equal values are
+	// kept, so that the result is the first of them like in MinAggregate and
+	// MaxAggregate.
+	for n := len(a.vals); n > 0; n-- {
+		c := a.vals[n-1].Compare(evalCtx, datum)
+		if (a.max && c >= 0) || (!a.max && c <= 0) {
+			break
+		}
+		a.idxs = a.idxs[:n-1]
+		a.vals = a.vals[:n-1]
+	}
+	a.idxs = append(a.idxs, idx)
+	a.vals = append(a.vals, datum)
+	return nil
+}
+
+func (a *slidingMinMax) remove(_ *tree.EvalContext, idx int, _ tree.Datum) error {
+	if len(a.idxs) > 0 && a.idxs[0] == idx {
+		a.idxs = a.idxs[1:]
+		a.vals = a.vals[1:]
+	}
+	return nil
+}
+
+func (a *slidingMinMax) result() (tree.Datum, error) {
+	if len(a.vals) == 0 {
+		return tree.DNull, nil
+	}
+	return a.vals[0], nil
+}
+
+func (a *slidingMinMax) reset() {
+	a.idxs = a.idxs[:0]
+	a.vals = a.vals[:0]
+}
+
+// slidingCount computes count and count_rows over a sliding window.
+type slidingCount struct {
+	countNulls bool
+	count      int
+}
+
+func (a *slidingCount) add(_ *tree.EvalContext, _ int, datum tree.Datum) error {
+	if datum != tree.DNull || a.countNulls {
+		a.count++
+	}
+	return nil
+}
+
+func (a *slidingCount) remove(_ *tree.EvalContext, _ int, datum tree.Datum) error {
+	if datum != tree.DNull || a.countNulls {
+		a.count--
+	}
+	return nil
+}
+
+func (a *slidingCount) result() (tree.Datum, error) {
+	return tree.NewDInt(tree.DInt(a.count)), nil
+}
+
+func (a *slidingCount) reset() {
+	a.count = 0
+}
+
+// slidingSmallIntSum computes the sum of small integers over a sliding
+// window. Like smallIntSumAggregate, it does not check for overflows.
+type slidingSmallIntSum struct {
+	sum   int64
+	count int
+}
+
+func (a *slidingSmallIntSum) add(_ *tree.EvalContext, _ int, datum tree.Datum) error {
+	if datum == tree.DNull {
+		return nil
+	}
+	a.sum += int64(tree.MustBeDInt(datum))
+	a.count++
+	return nil
+}
+
+func (a *slidingSmallIntSum) remove(_ *tree.EvalContext, _ int, datum tree.Datum) error {
+	if datum == tree.DNull {
+		return nil
+	}
+	a.sum -= int64(tree.MustBeDInt(datum))
+	a.count--
+	return nil
+}
+
+func (a *slidingSmallIntSum) result() (tree.Datum, error) {
+	if a.count == 0 {
+		return tree.DNull, nil
+	}
+	return tree.NewDInt(tree.DInt(a.sum)), nil
+}
+
+func (a *slidingSmallIntSum) reset() {
+	*a = slidingSmallIntSum{}
+}
+
+// slidingDecimalSum computes the sum or the average of integers or decimals
+// over a sliding window, using exact decimal arithmetic.
+type slidingDecimalSum struct {
+	avg    bool
+	sum    apd.Decimal
+	tmpDec apd.Decimal
+	count  int
+
+	// exponents counts the values in the window by exponent. The sum of
+	// decimals has the smallest exponent of the values added to it, which is
+	// not necessarily the smallest exponent of the values remaining in the
+	// window. The sum is rescaled to the latter to get the same result as
+	// aggregating the values of the window from scratch.
+	exponents map[int32]int
+}
+
+func (a *slidingDecimalSum) operand(datum tree.Datum) *apd.Decimal {
+	switch t := datum.(type) {
+	case *tree.DInt:
+		a.tmpDec.SetInt64(int64(*t))
+		return &a.tmpDec
+	default:
+		return &datum.(*tree.DDecimal).Decimal
+	}
+}
+
+func (a *slidingDecimalSum) add(_ *tree.EvalContext, _ int, datum tree.Datum) error {
+	if datum == tree.DNull {
+		return nil
+	}
+	d := a.operand(datum)
+	if _, err := tree.ExactCtx.Add(&a.sum, &a.sum, d); err != nil {
+		return err
+	}
+	if a.exponents == nil {
+		a.exponents = make(map[int32]int)
+	}
+	a.exponents[d.Exponent]++
+	a.count++
+	return nil
+}
+
+func (a *slidingDecimalSum) remove(_ *tree.EvalContext, _ int, datum tree.Datum) error {
+	if datum == tree.DNull {
+		return nil
+	}
+	d := a.operand(datum)
+	if _, err := tree.ExactCtx.Sub(&a.sum, &a.sum, d); err != nil {
+		return err
+	}
+	if a.exponents[d.Exponent]--; a.exponents[d.Exponent] == 0 {
+		delete(a.exponents, d.Exponent)
+	}
+	a.count--
+	return nil
+}
+
+func (a *slidingDecimalSum) result() (tree.Datum, error) {
+	if a.count == 0 {
+		return tree.DNull, nil
+	}
+	exp := int32(0)
+	for e := range a.exponents {
+		if e < exp {
+			exp = e
+		}
+	}
+	dd := &tree.DDecimal{}
+	dd.Set(&a.sum)
+	if diff := exp - dd.Exponent; diff > 0 {
+		// The coefficient is a multiple of 10^diff, as all the values in the
+		// window are.
+		scale := big.NewInt(10)
+		scale.Exp(scale, big.NewInt(int64(diff)), nil)
+		dd.Coeff.Quo(&dd.Coeff, scale)
+		dd.Exponent = exp
+	}
+	if a.avg {
+		count := apd.New(int64(a.count), 0)
+		if _, err := tree.DecimalCtx.Quo(&dd.Decimal, &dd.Decimal, count); err != nil {
+			return nil, err
+		}
+	}
+	return dd, nil
+}
+
+func (a *slidingDecimalSum) reset() {
+	a.sum.SetInt64(0)
+	a.count = 0
+	a.exponents = nil
+}
+
+// slidingIntervalSum computes the sum of intervals over a sliding window.
+type slidingIntervalSum struct {
+	sum   duration.Duration
+	count int
+}
+
+func (a *slidingIntervalSum) add(_ *tree.EvalContext, _ int, datum tree.Datum) error {
+	if datum == tree.DNull {
+		return nil
+	}
+	a.sum = a.sum.Add(datum.(*tree.DInterval).Duration)
+	a.count++
+	return nil
+}
+
+func (a *slidingIntervalSum) remove(_ *tree.EvalContext, _ int, datum tree.Datum) error {
+	if datum == tree.DNull {
+		return nil
+	}
+	a.sum = a.sum.Sub(datum.(*tree.DInterval).Duration)
+	a.count--
+	return nil
+}
+
+func (a *slidingIntervalSum) result() (tree.Datum, error) {
+	if a.count == 0 {
+		return tree.DNull, nil
+	}
+	return &tree.DInterval{Duration: a.sum}, nil
+}
+
+func (a *slidingIntervalSum) reset() {
+	*a = slidingIntervalSum{}
+}
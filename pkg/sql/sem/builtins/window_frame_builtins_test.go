@@ -0,0 +1,163 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package builtins
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/types"
+)
+
+// testWindowFrameAggregate verifies that aggregating over sliding ROWS frames
+// returns the same results as aggregating over each frame from scratch.
+func testWindowFrameAggregate(
+	t *testing.T, aggFunc func([]types.T, *tree.EvalContext) tree.AggregateFunc, vals []tree.Datum,
+) {
+	ctx := context.Background()
+	evalCtx := tree.NewTestingEvalContext(cluster.MakeTestingClusterSettings())
+	defer evalCtx.Stop(ctx)
+
+	// Interleave NULLs with the values.
+	rows := make([]tree.IndexedRow, 0, 2*len(vals))
+	for i, val := range vals {
+		if i%3 == 1 {
+			rows = append(rows, tree.IndexedRow{Idx: len(rows), Row: tree.Datums{tree.DNull}})
+		}
+		rows = append(rows, tree.IndexedRow{Idx: len(rows), Row: tree.Datums{val}})
+	}
+	params := []types.T{vals[0].ResolvedType()}
+
+	for _, startType := range []tree.WindowFrameBoundType{
+		tree.UnboundedPreceding, tree.ValuePreceding, tree.CurrentRow, tree.ValueFollowing,
+	} {
+		for _, endType := range []tree.WindowFrameBoundType{
+			tree.ValuePreceding, tree.CurrentRow, tree.ValueFollowing, tree.UnboundedFollowing,
+		} {
+			for _, offsets := range [][2]int{{0, 0}, {1, 2}, {3, 1}, {20, 20}} {
+				startOffset := tree.NewDInt(tree.DInt(offsets[0]))
+				endOffset := tree.NewDInt(tree.DInt(offsets[1]))
+				frame := &tree.WindowFrame{
+					Mode: tree.ROWS,
+					Bounds: tree.WindowFrameBounds{
+						StartBound: &tree.WindowFrameBound{BoundType: startType, OffsetExpr: startOffset},
+						EndBound:   &tree.WindowFrameBound{BoundType: endType, OffsetExpr: endOffset},
+					},
+				}
+				wfr := &tree.WindowFrameRun{
+					Rows:             rows,
+					ArgCount:         1,
+					Frame:            frame,
+					StartBoundOffset: startOffset,
+					EndBoundOffset:   endOffset,
+				}
+				name := fmt.Sprintf("%s/%d/%d", tree.AsString(frame), offsets[0], offsets[1])
+
+				w := newAggregateWindow(func() tree.AggregateFunc { return aggFunc(params, evalCtx) })
+				for wfr.RowIdx = range rows {
+					wfr.FirstPeerIdx, wfr.PeerRowCount = wfr.RowIdx, 1
+					res, err := w.Compute(ctx, evalCtx, wfr)
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					start, err := wfr.FrameStartIdx(evalCtx)
+					if err != nil {
+						t.Fatal(err)
+					}
+					end, err := wfr.FrameEndIdx(evalCtx)
+					if err != nil {
+						t.Fatal(err)
+					}
+					agg := aggFunc(params, evalCtx)
+					for i := start; i < end; i++ {
+						if err := agg.Add(ctx, rows[i].Row[0]); err != nil {
+							t.Fatal(err)
+						}
+					}
+					expected, err := agg.Result()
+					if err != nil {
+						t.Fatal(err)
+					}
+					agg.Close(ctx)
+
+					if res.Compare(evalCtx, expected) != 0 || res.String() != expected.String() {
+						t.Fatalf("%s: row %d: expected %s, but found %s", name, wfr.RowIdx, expected, res)
+					}
+				}
+				w.Close(ctx, evalCtx)
+			}
+		}
+	}
+}
+
+func TestWindowFrameAvgInt(t *testing.T) {
+	testWindowFrameAggregate(t, newIntAvgAggregate, makeIntTestDatum(10))
+}
+
+func TestWindowFrameAvgDecimal(t *testing.T) {
+	testWindowFrameAggregate(t, newDecimalAvgAggregate, makeDecimalTestDatum(10))
+}
+
+func TestWindowFrameCount(t *testing.T) {
+	testWindowFrameAggregate(t, newCountAggregate, makeIntTestDatum(10))
+}
+
+func TestWindowFrameCountRows(t *testing.T) {
+	testWindowFrameAggregate(t, newCountRowsAggregate, makeIntTestDatum(10))
+}
+
+func TestWindowFrameMaxInt(t *testing.T) {
+	testWindowFrameAggregate(t, newMaxAggregate, makeSmallIntTestDatum(10))
+}
+
+func TestWindowFrameMaxBool(t *testing.T) {
+	testWindowFrameAggregate(t, newMaxAggregate, makeBoolTestDatum(10))
+}
+
+func TestWindowFrameMinDecimal(t *testing.T) {
+	testWindowFrameAggregate(t, newMinAggregate, makeDecimalTestDatum(10))
+}
+
+func TestWindowFrameMinBool(t *testing.T) {
+	testWindowFrameAggregate(t, newMinAggregate, makeBoolTestDatum(10))
+}
+
+func TestWindowFrameSumSmallInt(t *testing.T) {
+	testWindowFrameAggregate(t, newSmallIntSumAggregate, makeSmallIntTestDatum(10))
+}
+
+func TestWindowFrameSumInt(t *testing.T) {
+	testWindowFrameAggregate(t, newIntSumAggregate, makeIntTestDatum(10))
+}
+
+func TestWindowFrameSumFloat(t *testing.T) {
+	testWindowFrameAggregate(t, newFloatSumAggregate, makeFloatTestDatum(10))
+}
+
+func TestWindowFrameSumDecimal(t *testing.T) {
+	testWindowFrameAggregate(t, newDecimalSumAggregate, makeDecimalTestDatum(10))
+}
+
+func TestWindowFrameSumInterval(t *testing.T) {
+	testWindowFrameAggregate(t, newIntervalSumAggregate, makeIntervalTestDatum(10))
+}
+
+func TestWindowFrameVarianceDecimal(t *testing.T) {
+	testWindowFrameAggregate(t, newDecimalVarianceAggregate, makeDecimalTestDatum(10))
+}
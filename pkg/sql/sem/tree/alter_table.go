@@ -14,6 +14,8 @@
 
 package tree
 
+import "github.com/cockroachdb/cockroach/pkg/sql/coltypes"
+
 // AlterTable represents an ALTER TABLE statement.
 type AlterTable struct {
 	IfExists bool
@@ -54,25 +56,33 @@ type AlterTableCmd interface {
 
 func (*AlterTableAddColumn) alterTableCmd()          {}
 func (*AlterTableAddConstraint) alterTableCmd()      {}
+func (*AlterTableAlterColumnType) alterTableCmd()    {}
+func (*AlterTableAlterConstraint) alterTableCmd()    {}
 func (*AlterTableDropColumn) alterTableCmd()         {}
 func (*AlterTableDropConstraint) alterTableCmd()     {}
 func (*AlterTableDropNotNull) alterTableCmd()        {}
 func (*AlterTableSetAudit) alterTableCmd()           {}
 func (*AlterTableSetDefault) alterTableCmd()         {}
+func (*AlterTableSetNotNull) alterTableCmd()         {}
 func (*AlterTableValidateConstraint) alterTableCmd() {}
 func (*AlterTablePartitionBy) alterTableCmd()        {}
 func (*AlterTableInjectStats) alterTableCmd()        {}
+func (*AlterTableRenameConstraint) alterTableCmd()   {}
 
 var _ AlterTableCmd = &AlterTableAddColumn{}
 var _ AlterTableCmd = &AlterTableAddConstraint{}
+var _ AlterTableCmd = &AlterTableAlterColumnType{}
+var _ AlterTableCmd = &AlterTableAlterConstraint{}
 var _ AlterTableCmd = &AlterTableDropColumn{}
 var _ AlterTableCmd = &AlterTableDropConstraint{}
 var _ AlterTableCmd = &AlterTableDropNotNull{}
 var _ AlterTableCmd = &AlterTableSetAudit{}
 var _ AlterTableCmd = &AlterTableSetDefault{}
+var _ AlterTableCmd = &AlterTableSetNotNull{}
 var _ AlterTableCmd = &AlterTableValidateConstraint{}
 var _ AlterTableCmd = &AlterTablePartitionBy{}
 var _ AlterTableCmd = &AlterTableInjectStats{}
+var _ AlterTableCmd = &AlterTableRenameConstraint{}
 
 // ColumnMutationCmd is the subset of AlterTableCmds that modify an
 // existing column.
@@ -178,6 +188,58 @@ func (node *AlterTableValidateConstraint) Format(ctx *FmtCtx) {
 	ctx.FormatNode(&node.Constraint)
 }
 
+// AlterTableRenameConstraint represents a RENAME CONSTRAINT command.
+type AlterTableRenameConstraint struct {
+	Constraint Name
+	NewName    Name
+}
+
+// Format implements the NodeFormatter interface.
+func (node *AlterTableRenameConstraint) Format(ctx *FmtCtx) {
+	ctx.WriteString(" RENAME CONSTRAINT ")
+	ctx.FormatNode(&node.Constraint)
+	ctx.WriteString(" TO ")
+	ctx.FormatNode(&node.NewName)
+}
+
+// ConstraintAttribute is a deferrability attribute of a constraint.
+type ConstraintAttribute int
+
+// ConstraintAttribute values.
+const (
+	ConstraintDeferrable ConstraintAttribute = iota
+	ConstraintNotDeferrable
+	ConstraintInitiallyDeferred
+	ConstraintInitiallyImmediate
+)
+
+var constraintAttributeName = [...]string{
+	ConstraintDeferrable:         "DEFERRABLE",
+	ConstraintNotDeferrable:      "NOT DEFERRABLE",
+	ConstraintInitiallyDeferred:  "INITIALLY DEFERRED",
+	ConstraintInitiallyImmediate: "INITIALLY IMMEDIATE",
+}
+
+func (a ConstraintAttribute) String() string {
+	return constraintAttributeName[a]
+}
+
+// AlterTableAlterConstraint represents an ALTER CONSTRAINT command.
+type AlterTableAlterConstraint struct {
+	Constraint Name
+	Attributes []ConstraintAttribute
+}
+
+// Format implements the NodeFormatter interface.
+func (node *AlterTableAlterConstraint) Format(ctx *FmtCtx) {
+	ctx.WriteString(" ALTER CONSTRAINT ")
+	ctx.FormatNode(&node.Constraint)
+	for _, attr := range node.Attributes {
+		ctx.WriteByte(' ')
+		ctx.WriteString(attr.String())
+	}
+}
+
 // AlterTableSetDefault represents an ALTER COLUMN SET DEFAULT
 // or DROP DEFAULT command.
 type AlterTableSetDefault struct {
@@ -228,6 +290,59 @@ func (node *AlterTableDropNotNull) Format(ctx *FmtCtx) {
 	ctx.WriteString(" DROP NOT NULL")
 }
 
+// AlterTableSetNotNull represents an ALTER COLUMN SET NOT NULL
+// command.
+type AlterTableSetNotNull struct {
+	ColumnKeyword bool
+	Column        Name
+}
+
+// GetColumn implements the ColumnMutationCmd interface.
+func (node *AlterTableSetNotNull) GetColumn() Name {
+	return node.Column
+}
+
+// Format implements the NodeFormatter interface.
+func (node *AlterTableSetNotNull) Format(ctx *FmtCtx) {
+	ctx.WriteString(" ALTER ")
+	if node.ColumnKeyword {
+		ctx.WriteString("COLUMN ")
+	}
+	ctx.FormatNode(&node.Column)
+	ctx.WriteString(" SET NOT NULL")
+}
+
+// AlterTableAlterColumnType represents an ALTER COLUMN ... SET DATA TYPE
+// command.
+type AlterTableAlterColumnType struct {
+	ColumnKeyword bool
+	Column        Name
+	ToType        coltypes.T
+	// Using is the optional expression used to convert existing values to
+	// the new type. If nil, the values are cast to the new type.
+	Using Expr
+}
+
+// GetColumn implements the ColumnMutationCmd interface.
+func (node *AlterTableAlterColumnType) GetColumn() Name {
+	return node.Column
+}
+
+// Format implements the NodeFormatter interface.
+func (node *AlterTableAlterColumnType) Format(ctx *FmtCtx) {
+	ctx.WriteString(" ALTER ")
+	if node.ColumnKeyword {
+		ctx.WriteString("COLUMN ")
+	}
+	ctx.FormatNode(&node.Column)
+	ctx.WriteString(" SET DATA TYPE ")
+	node.ToType.Format(ctx.Buffer, ctx.flags.EncodeFlags())
+	if node.Using != nil {
+		ctx.WriteString(" USING ")
+		ctx.FormatNode(node.Using)
+	}
+}
+
 // AlterTablePartitionBy represents an ALTER TABLE PARTITION BY
 // command.
 type AlterTablePartitionBy struct {
@@ -92,6 +92,12 @@ type Builtin struct {
 	// Class is the kind of built-in function (normal/aggregate/window/etc.)
 	Class FunctionClass
 
+	// OrderedSetAggregate is set to true for aggregate functions that must be
+	// called with a WITHIN GROUP (ORDER BY ...) clause, e.g. percentile_disc.
+	// The last argument in Types is the sort expression; the preceding ones are
+	// the direct arguments written between the parentheses.
+	OrderedSetAggregate bool
+
 	// Category is used to generate documentation strings.
 	Category string
 
@@ -70,15 +70,28 @@ func (node *CreateDatabase) Format(ctx *FmtCtx) {
 	}
 }
 
-// IndexElem represents a column with a direction in a CREATE INDEX statement.
+// IndexElem represents a column or an expression with a direction in a CREATE
+// INDEX statement.
 type IndexElem struct {
-	Column    Name
+	Column Name
+	// Expr is set instead of Column for an expression index element.
+	Expr      Expr
 	Direction Direction
 }
 
 // Format implements the NodeFormatter interface.
 func (node *IndexElem) Format(ctx *FmtCtx) {
-	ctx.FormatNode(&node.Column)
+	if node.Expr != nil {
+		if _, ok := node.Expr.(*FuncExpr); ok {
+			ctx.FormatNode(node.Expr)
+		} else {
+			ctx.WriteByte('(')
+			ctx.FormatNode(node.Expr)
+			ctx.WriteByte(')')
+		}
+	} else {
+		ctx.FormatNode(&node.Column)
+	}
 	if node.Direction != DefaultDirection {
 		ctx.WriteByte(' ')
 		ctx.WriteString(node.Direction.String())
@@ -211,10 +224,12 @@ type ColumnTableDef struct {
 		Col            Name
 		ConstraintName Name
 		Actions        ReferenceActions
+		Match          CompositeKeyMatchMethod
 	}
 	Computed struct {
 		Computed bool
 		Expr     Expr
+		Virtual  bool
 	}
 	Family struct {
 		Name        Name
@@ -313,9 +328,11 @@ func NewColumnTableDef(
 			d.References.Col = t.Col
 			d.References.ConstraintName = c.Name
 			d.References.Actions = t.Actions
+			d.References.Match = t.Match
 		case *ColumnComputedDef:
 			d.Computed.Computed = true
 			d.Computed.Expr = t.Expr
+			d.Computed.Virtual = t.Virtual
 		case *ColumnFamilyConstraint:
 			if d.HasColumnFamily() {
 				return nil, pgerror.NewErrorf(pgerror.CodeInvalidTableDefinitionError,
@@ -405,12 +422,17 @@ func (node *ColumnTableDef) Format(ctx *FmtCtx) {
 			ctx.FormatNode(&node.References.Col)
 			ctx.WriteByte(')')
 		}
+		ctx.FormatNode(node.References.Match)
 		ctx.FormatNode(&node.References.Actions)
 	}
 	if node.IsComputed() {
 		ctx.WriteString(" AS (")
 		ctx.FormatNode(node.Computed.Expr)
-		ctx.WriteString(") STORED")
+		if node.Computed.Virtual {
+			ctx.WriteString(") VIRTUAL")
+		} else {
+			ctx.WriteString(") STORED")
+		}
 	}
 	if node.HasColumnFamily() {
 		if node.Family.Create {
@@ -479,11 +501,15 @@ type ColumnFKConstraint struct {
 	Table   NormalizableTableName
 	Col     Name // empty-string means use PK
 	Actions ReferenceActions
+	Match   CompositeKeyMatchMethod
 }
 
 // ColumnComputedDef represents the description of a computed column.
 type ColumnComputedDef struct {
 	Expr Expr
+	// Virtual is set for a column computed when it is read instead of being
+	// stored.
+	Virtual bool
 }
 
 // ColumnFamilyConstraint represents FAMILY on a column.
@@ -606,6 +632,41 @@ func (ra ReferenceAction) String() string {
 	return referenceActionName[ra]
 }
 
+// CompositeKeyMatchMethod is the algorithm used when matching the values of a
+// multi-column foreign key against the referenced key.
+type CompositeKeyMatchMethod int
+
+// The values for CompositeKeyMatchMethod.
+const (
+	// MatchSimple allows some of the referencing columns to be NULL, in which
+	// case the row isn't required to match any row in the referenced table.
+	MatchSimple CompositeKeyMatchMethod = iota
+	// MatchFull requires the referencing columns to be either all NULL, or all
+	// non-NULL and matching a row in the referenced table.
+	MatchFull
+	// MatchPartial is not supported.
+	MatchPartial
+)
+
+var compositeKeyMatchMethodName = [...]string{
+	MatchSimple:  "MATCH SIMPLE",
+	MatchFull:    "MATCH FULL",
+	MatchPartial: "MATCH PARTIAL",
+}
+
+func (c CompositeKeyMatchMethod) String() string {
+	return compositeKeyMatchMethodName[c]
+}
+
+// Format implements the NodeFormatter interface. MATCH SIMPLE is the
+// default, and is omitted.
+func (c CompositeKeyMatchMethod) Format(ctx *FmtCtx) {
+	if c != MatchSimple {
+		ctx.WriteByte(' ')
+		ctx.WriteString(c.String())
+	}
+}
+
 // ReferenceActions contains the actions specified to maintain referential
 // integrity through foreign keys for different operations.
 type ReferenceActions struct {
@@ -632,6 +693,7 @@ type ForeignKeyConstraintTableDef struct {
 	FromCols NameList
 	ToCols   NameList
 	Actions  ReferenceActions
+	Match    CompositeKeyMatchMethod
 }
 
 // Format implements the NodeFormatter interface.
@@ -653,6 +715,7 @@ func (node *ForeignKeyConstraintTableDef) Format(ctx *FmtCtx) {
 		ctx.WriteByte(')')
 	}
 
+	ctx.FormatNode(node.Match)
 	ctx.FormatNode(&node.Actions)
 }
 
@@ -830,6 +893,7 @@ func (node *RangePartition) Format(ctx *FmtCtx) {
 
 // CreateTable represents a CREATE TABLE statement.
 type CreateTable struct {
+	Temporary     bool
 	IfNotExists   bool
 	Table         NormalizableTableName
 	Interleave    *InterleaveDef
@@ -847,7 +911,11 @@ func (node *CreateTable) As() bool {
 
 // Format implements the NodeFormatter interface.
 func (node *CreateTable) Format(ctx *FmtCtx) {
-	ctx.WriteString("CREATE TABLE ")
+	ctx.WriteString("CREATE ")
+	if node.Temporary {
+		ctx.WriteString("TEMP ")
+	}
+	ctx.WriteString("TABLE ")
 	if node.IfNotExists {
 		ctx.WriteString("IF NOT EXISTS ")
 	}
@@ -873,6 +941,52 @@ func (node *CreateTable) Format(ctx *FmtCtx) {
 	}
 }
 
+// CreateFunction represents a CREATE FUNCTION statement.
+type CreateFunction struct {
+	Name       NormalizableTableName
+	Params     FuncParams
+	ReturnType coltypes.T
+	Body       string
+}
+
+// Format implements the NodeFormatter interface.
+func (node *CreateFunction) Format(ctx *FmtCtx) {
+	ctx.WriteString("CREATE FUNCTION ")
+	ctx.FormatNode(&node.Name)
+	ctx.WriteByte('(')
+	ctx.FormatNode(&node.Params)
+	ctx.WriteString(") RETURNS ")
+	node.ReturnType.Format(ctx.Buffer, ctx.flags.EncodeFlags())
+	ctx.WriteString(" LANGUAGE SQL AS ")
+	lex.EncodeSQLStringWithFlags(ctx.Buffer, node.Body, ctx.flags.EncodeFlags())
+}
+
+// FuncParam is a parameter of a user-defined function.
+type FuncParam struct {
+	Name Name
+	Type coltypes.T
+}
+
+// Format implements the NodeFormatter interface.
+func (node *FuncParam) Format(ctx *FmtCtx) {
+	ctx.FormatNode(&node.Name)
+	ctx.WriteByte(' ')
+	node.Type.Format(ctx.Buffer, ctx.flags.EncodeFlags())
+}
+
+// FuncParams is a list of function parameters.
+type FuncParams []FuncParam
+
+// Format implements the NodeFormatter interface.
+func (node *FuncParams) Format(ctx *FmtCtx) {
+	for i := range *node {
+		if i > 0 {
+			ctx.WriteString(", ")
+		}
+		ctx.FormatNode(&(*node)[i])
+	}
+}
+
 // CreateSequence represents a CREATE SEQUENCE statement.
 type CreateSequence struct {
 	IfNotExists bool
@@ -899,8 +1013,20 @@ func (node *SequenceOptions) Format(ctx *FmtCtx) {
 		option := &(*node)[i]
 		ctx.WriteByte(' ')
 		switch option.Name {
+		case SeqOptAs:
+			ctx.WriteString(option.Name)
+			ctx.WriteByte(' ')
+			option.AsType.Format(ctx.Buffer, ctx.flags.EncodeFlags())
 		case SeqOptCycle, SeqOptNoCycle:
 			ctx.WriteString(option.Name)
+		case SeqOptOwnedBy:
+			ctx.WriteString(option.Name)
+			ctx.WriteByte(' ')
+			if option.OwnedBy == nil {
+				ctx.WriteString("NONE")
+			} else {
+				ctx.FormatNode(option.OwnedBy)
+			}
 		case SeqOptCache:
 			ctx.WriteString(option.Name)
 			ctx.WriteByte(' ')
@@ -941,6 +1067,13 @@ type SequenceOption struct {
 	IntVal *int64
 
 	OptionalWord bool
+
+	// AsType is the data type of an AS option.
+	AsType coltypes.T
+
+	// OwnedBy is the column of an OWNED BY option, or nil for OWNED BY
+	// NONE.
+	OwnedBy *UnresolvedName
 }
 
 // Names of options on CREATE SEQUENCE.
@@ -954,10 +1087,6 @@ const (
 	SeqOptMinValue  = "MINVALUE"
 	SeqOptMaxValue  = "MAXVALUE"
 	SeqOptStart     = "START"
-
-	// Avoid unused warning for constants.
-	_ = SeqOptAs
-	_ = SeqOptOwnedBy
 )
 
 // CreateUser represents a CREATE USER statement.
@@ -1028,14 +1157,19 @@ func (node *CreateRole) Format(ctx *FmtCtx) {
 
 // CreateView represents a CREATE VIEW statement.
 type CreateView struct {
-	Name        NormalizableTableName
-	ColumnNames NameList
-	AsSource    *Select
+	Name         NormalizableTableName
+	ColumnNames  NameList
+	AsSource     *Select
+	Materialized bool
 }
 
 // Format implements the NodeFormatter interface.
 func (node *CreateView) Format(ctx *FmtCtx) {
-	ctx.WriteString("CREATE VIEW ")
+	ctx.WriteString("CREATE ")
+	if node.Materialized {
+		ctx.WriteString("MATERIALIZED ")
+	}
+	ctx.WriteString("VIEW ")
 	ctx.FormatNode(&node.Name)
 
 	if len(node.ColumnNames) > 0 {
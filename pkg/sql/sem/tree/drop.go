@@ -105,14 +105,19 @@ func (node *DropTable) Format(ctx *FmtCtx) {
 
 // DropView represents a DROP VIEW statement.
 type DropView struct {
-	Names        NormalizableTableNames
-	IfExists     bool
-	DropBehavior DropBehavior
+	Names          NormalizableTableNames
+	IfExists       bool
+	DropBehavior   DropBehavior
+	IsMaterialized bool
 }
 
 // Format implements the NodeFormatter interface.
 func (node *DropView) Format(ctx *FmtCtx) {
-	ctx.WriteString("DROP VIEW ")
+	ctx.WriteString("DROP ")
+	if node.IsMaterialized {
+		ctx.WriteString("MATERIALIZED ")
+	}
+	ctx.WriteString("VIEW ")
 	if node.IfExists {
 		ctx.WriteString("IF EXISTS ")
 	}
@@ -143,6 +148,21 @@ func (node *DropSequence) Format(ctx *FmtCtx) {
 	}
 }
 
+// DropFunction represents a DROP FUNCTION statement.
+type DropFunction struct {
+	Names    NormalizableTableNames
+	IfExists bool
+}
+
+// Format implements the NodeFormatter interface.
+func (node *DropFunction) Format(ctx *FmtCtx) {
+	ctx.WriteString("DROP FUNCTION ")
+	if node.IfExists {
+		ctx.WriteString("IF EXISTS ")
+	}
+	ctx.FormatNode(&node.Names)
+}
+
 // DropUser represents a DROP USER statement
 type DropUser struct {
 	Names    Exprs
@@ -1483,6 +1483,55 @@ func init() {
 	timestampMinusBinOp, _ = BinOps[Minus].lookupImpl(types.TimestampTZ, types.TimestampTZ)
 }
 
+// TimeZoneIntervalToLocation returns the location with the fixed UTC
+// offset given by an interval, as in AT TIME ZONE INTERVAL '-08:00'.
+func TimeZoneIntervalToLocation(d *DInterval) (*time.Location, error) {
+	if d.Months != 0 || d.Days != 0 {
+		return nil, pgerror.NewErrorf(pgerror.CodeInvalidParameterValueError,
+			"interval time zone %s must not include months or days", d)
+	}
+	offset := int(d.Nanos / int64(time.Second))
+	return timeutil.FixedOffsetTimeZoneToLocation(offset, d.String()), nil
+}
+
+// TimestampAtTimeZone implements ts AT TIME ZONE loc for a TIMESTAMP: the
+// wall time of ts is interpreted in loc, and the corresponding TIMESTAMPTZ
+// is returned.
+//
+// Like in PostgreSQL, a wall time that is skipped by a daylight saving
+// time transition is interpreted with the UTC offset in effect before the
+// transition, and a wall time that occurs twice is interpreted with the
+// UTC offset in effect after the transition.
+func TimestampAtTimeZone(ts *DTimestamp, loc *time.Location) *DTimestampTZ {
+	// wall is the wall time of ts as if it were in UTC.
+	wall := ts.UTC()
+	offsetAt := func(t time.Time) time.Duration {
+		_, offset := t.In(loc).Zone()
+		return time.Duration(offset) * time.Second
+	}
+	// UTC offsets never exceed a day, and time zones do not change their
+	// offset twice in two days, so the offsets in effect on either side of
+	// the transition that may affect wall, if any, are those a day before
+	// and a day after.
+	before, after := offsetAt(wall.Add(-24*time.Hour)), offsetAt(wall.Add(24*time.Hour))
+	t := wall.Add(-before)
+	if before != after {
+		if tAfter := wall.Add(-after); offsetAt(tAfter) == after {
+			t = tAfter
+		}
+	}
+	return MakeDTimestampTZ(t, time.Microsecond)
+}
+
+// TimestampTZAtTimeZone implements ts AT TIME ZONE loc for a TIMESTAMPTZ:
+// the wall time of ts in loc is returned as a TIMESTAMP.
+func TimestampTZAtTimeZone(ts *DTimestampTZ, loc *time.Location) *DTimestamp {
+	t := ts.In(loc)
+	return MakeDTimestamp(time.Date(
+		t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC,
+	), time.Microsecond)
+}
+
 // CmpOp is a comparison operator.
 type CmpOp struct {
 	LeftType  types.T
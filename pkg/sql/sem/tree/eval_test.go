@@ -833,6 +833,25 @@ func TestEval(t *testing.T) {
 		{`'2010-09-28 12:00:00.1-04'::timestamp`, `'2010-09-28 16:00:00.1+00:00'`},
 		{`'2010-09-28 12:00:00.1-04'::timestamp::text`, `'2010-09-28 16:00:00.1+00:00'`},
 		{`'2010-09-28 12:00:00.1-04'::timestamptz::text`, `'2010-09-28 12:00:00.1-04:00'`},
+		// AT TIME ZONE.
+		{`'2018-01-01 12:00:00'::timestamp AT TIME ZONE 'America/New_York'`, `'2018-01-01 17:00:00+00:00'`},
+		{`'2018-07-01 12:00:00'::timestamp AT TIME ZONE 'America/New_York'`, `'2018-07-01 16:00:00+00:00'`},
+		{`'2018-07-01 12:00:00'::timestamp AT TIME ZONE 'utc'`, `'2018-07-01 12:00:00+00:00'`},
+		{`'2018-07-01 12:00:00'::timestamp AT TIME ZONE '-8h'::interval`, `'2018-07-01 20:00:00+00:00'`},
+		// Wall times skipped by a DST transition use the offset before it.
+		{`'2018-03-11 02:30:00'::timestamp AT TIME ZONE 'America/New_York'`, `'2018-03-11 07:30:00+00:00'`},
+		{`'2018-03-11 01:30:00'::timestamp AT TIME ZONE 'America/New_York'`, `'2018-03-11 06:30:00+00:00'`},
+		{`'2018-03-11 03:30:00'::timestamp AT TIME ZONE 'America/New_York'`, `'2018-03-11 07:30:00+00:00'`},
+		// Wall times repeated by a DST transition use the offset after it.
+		{`'2018-11-04 00:30:00'::timestamp AT TIME ZONE 'America/New_York'`, `'2018-11-04 04:30:00+00:00'`},
+		{`'2018-11-04 01:30:00'::timestamp AT TIME ZONE 'America/New_York'`, `'2018-11-04 06:30:00+00:00'`},
+		{`'2018-11-04 02:30:00'::timestamp AT TIME ZONE 'America/New_York'`, `'2018-11-04 07:30:00+00:00'`},
+		{`'2018-11-04 05:30:00+00'::timestamptz AT TIME ZONE 'America/New_York'`, `'2018-11-04 01:30:00+00:00'`},
+		{`'2018-11-04 06:30:00+00'::timestamptz AT TIME ZONE 'America/New_York'`, `'2018-11-04 01:30:00+00:00'`},
+		{`'2018-07-01 12:00:00-04'::timestamptz AT TIME ZONE 'Europe/Rome'`, `'2018-07-01 18:00:00+00:00'`},
+		{`'2018-07-01 12:00:00+00'::timestamptz AT TIME ZONE '5h30m'::interval`, `'2018-07-01 17:30:00+00:00'`},
+		{`timezone('Europe/Rome', '2018-01-01 12:00:00'::timestamp)`, `'2018-01-01 11:00:00+00:00'`},
+		{`'2018-01-01 12:00:00'::timestamp AT TIME ZONE 'Europe/Rome' AT TIME ZONE 'Asia/Tokyo'`, `'2018-01-01 20:00:00+00:00'`},
 		{`'12h2m1s23ms'::interval`, `'12h2m1s23ms'`},
 		{`'12h2m1s23ms'::interval::text`, `'12h2m1s23ms'`},
 		{`interval '1'`, `'1s'`},
@@ -1300,6 +1319,9 @@ func TestEvalError(t *testing.T) {
 		{`'2010-09-28 12:00:00.1q'::date`,
 			`could not parse "2010-09-28 12:00:00.1q" as type date`},
 		{`'12:00:00q'::time`, `could not parse "12:00:00q" as type time`},
+		{`'2018-01-01'::timestamp AT TIME ZONE 'foo'`, `cannot find time zone "foo"`},
+		{`'2018-01-01'::timestamp AT TIME ZONE '1d'::interval`,
+			`interval time zone '1d' must not include months or days`},
 		{`'2010-09-28 12:00.1 MST'::timestamp`,
 			`could not parse "2010-09-28 12:00.1 MST" as type timestamp`},
 		{`'abcd'::interval`,
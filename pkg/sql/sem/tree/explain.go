@@ -25,6 +25,10 @@ type Explain struct {
 	// sql/explain.go for details.
 	Options []string
 
+	// Analyze is set for EXPLAIN ANALYZE, which executes the statement and
+	// reports the statistics collected during its execution.
+	Analyze bool
+
 	// Statement is the statement being EXPLAINed.
 	Statement Statement
 }
@@ -32,6 +36,9 @@ type Explain struct {
 // Format implements the NodeFormatter interface.
 func (node *Explain) Format(ctx *FmtCtx) {
 	ctx.WriteString("EXPLAIN ")
+	if node.Analyze {
+		ctx.WriteString("ANALYZE ")
+	}
 	if len(node.Options) > 0 {
 		ctx.WriteByte('(')
 		for i, opt := range node.Options {
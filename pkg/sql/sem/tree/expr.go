@@ -1045,6 +1045,9 @@ type FuncExpr struct {
 	Func  ResolvableFunctionReference
 	Type  funcType
 	Exprs Exprs
+	// OrderBy is used for the sort specification of ordered-set aggregates:
+	// percentile_disc(0.5) WITHIN GROUP (ORDER BY k)
+	OrderBy OrderBy
 	// Filter is used for filters on aggregates: SUM(k) FILTER (WHERE k > 0)
 	Filter    Expr
 	WindowDef *WindowDef
@@ -1091,11 +1094,26 @@ func (node *FuncExpr) GetAggregateConstructor() func(*EvalContext) AggregateFunc
 		return nil
 	}
 	return func(evalCtx *EvalContext) AggregateFunc {
-		types := typesOfExprs(node.Exprs)
+		types := typesOfExprs(node.AggregateArgs())
 		return node.fn.AggregateFunc(types, evalCtx)
 	}
 }
 
+// AggregateArgs returns the arguments that are passed to the aggregate
+// function for every row: the function arguments followed, for ordered-set
+// aggregates, by the WITHIN GROUP sort expressions.
+func (node *FuncExpr) AggregateArgs() Exprs {
+	if len(node.OrderBy) == 0 {
+		return node.Exprs
+	}
+	args := make(Exprs, 0, len(node.Exprs)+len(node.OrderBy))
+	args = append(args, node.Exprs...)
+	for _, o := range node.OrderBy {
+		args = append(args, o.Expr)
+	}
+	return args
+}
+
 // GetWindowConstructor returns a window function constructor if the
 // FuncExpr is a built-in window function.
 func (node *FuncExpr) GetWindowConstructor() func(*EvalContext) WindowFunc {
@@ -1163,6 +1181,16 @@ func (node *FuncExpr) Format(ctx *FmtCtx) {
 	ctx.WriteString(typ)
 	ctx.FormatNode(&node.Exprs)
 	ctx.WriteByte(')')
+	if node.OrderBy != nil {
+		ctx.WriteString(" WITHIN GROUP (ORDER BY ")
+		for i, o := range node.OrderBy {
+			if i > 0 {
+				ctx.WriteString(", ")
+			}
+			ctx.FormatNode(o)
+		}
+		ctx.WriteByte(')')
+	}
 	if window := node.WindowDef; window != nil {
 		ctx.WriteString(" OVER ")
 		if window.Name != "" {
@@ -1489,6 +1517,7 @@ func (node *RangeCond) String() string        { return AsString(node) }
 func (node *StrVal) String() string           { return AsString(node) }
 func (node *Subquery) String() string         { return AsString(node) }
 func (node *Tuple) String() string            { return AsString(node) }
+func (node *GroupingSet) String() string      { return AsString(node) }
 func (node *AnnotateTypeExpr) String() string { return AsString(node) }
 func (node *UnaryExpr) String() string        { return AsString(node) }
 func (node DefaultVal) String() string        { return AsString(node) }
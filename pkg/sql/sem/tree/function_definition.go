@@ -45,6 +45,17 @@ func NewFunctionDefinition(name string, def []Builtin) *FunctionDefinition {
 	}
 }
 
+// IsOrderedSetAggregate returns whether the function is an ordered-set
+// aggregate, which must be called with a WITHIN GROUP clause.
+func (fd *FunctionDefinition) IsOrderedSetAggregate() bool {
+	for _, o := range fd.Definition {
+		if b, ok := o.(*Builtin); ok && b.OrderedSetAggregate {
+			return true
+		}
+	}
+	return false
+}
+
 // FunDefs holds pre-allocated FunctionDefinition instances
 // for every builtin function. Initialized by builtins.init().
 var FunDefs map[string]*FunctionDefinition
@@ -39,6 +39,7 @@ type Grant struct {
 type TargetList struct {
 	Databases NameList
 	Tables    TablePatterns
+	Functions NormalizableTableNames
 }
 
 // Format implements the NodeFormatter interface.
@@ -46,6 +47,9 @@ func (tl *TargetList) Format(ctx *FmtCtx) {
 	if tl.Databases != nil {
 		ctx.WriteString("DATABASE ")
 		ctx.FormatNode(&tl.Databases)
+	} else if tl.Functions != nil {
+		ctx.WriteString("FUNCTION ")
+		ctx.FormatNode(&tl.Functions)
 	} else {
 		ctx.FormatNode(&tl.Tables)
 	}
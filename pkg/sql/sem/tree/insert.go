@@ -56,7 +56,10 @@ func (node *Insert) Format(ctx *FmtCtx) {
 	}
 	if node.OnConflict != nil && !node.OnConflict.IsUpsertAlias() {
 		ctx.WriteString(" ON CONFLICT")
-		if len(node.OnConflict.Columns) > 0 {
+		if node.OnConflict.Constraint != "" {
+			ctx.WriteString(" ON CONSTRAINT ")
+			ctx.FormatNode(&node.OnConflict.Constraint)
+		} else if len(node.OnConflict.Columns) > 0 {
 			ctx.WriteString(" (")
 			ctx.FormatNode(&node.OnConflict.Columns)
 			ctx.WriteString(")")
@@ -80,19 +83,23 @@ func (node *Insert) DefaultValues() bool {
 }
 
 // OnConflict represents an `ON CONFLICT (columns) DO UPDATE SET exprs WHERE
-// where` clause.
+// where` clause. The conflict index is specified either by its Columns, or by
+// the name of the unique constraint it backs (`ON CONFLICT ON CONSTRAINT
+// name`), in which case Constraint is set.
 //
 // The zero value for OnConflict is used to signal the UPSERT short form, which
 // uses the primary key for as the conflict index and the values being inserted
 // for Exprs.
 type OnConflict struct {
-	Columns   NameList
-	Exprs     UpdateExprs
-	Where     *Where
-	DoNothing bool
+	Columns    NameList
+	Constraint Name
+	Exprs      UpdateExprs
+	Where      *Where
+	DoNothing  bool
 }
 
 // IsUpsertAlias returns true if the UPSERT syntactic sugar was used.
 func (oc *OnConflict) IsUpsertAlias() bool {
-	return oc != nil && oc.Columns == nil && oc.Exprs == nil && oc.Where == nil && !oc.DoNothing
+	return oc != nil && oc.Columns == nil && oc.Constraint == "" && oc.Exprs == nil &&
+		oc.Where == nil && !oc.DoNothing
 }
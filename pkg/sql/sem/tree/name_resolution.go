@@ -288,7 +288,7 @@ func (t *TableName) ResolveExisting(
 	}
 
 	// This is a naked table name. Use the search path.
-	iter := searchPath.Iter()
+	iter := searchPath.IterRelations()
 	for next, ok := iter(); ok; next, ok = iter() {
 		if found, objMeta, err := r.LookupObject(ctx, curDb, next, t.Table()); found || err != nil {
 			if err == nil {
@@ -44,6 +44,7 @@ type Select struct {
 	Select  SelectStatement
 	OrderBy OrderBy
 	Limit   *Limit
+	Locking LockingClause
 }
 
 // Format implements the NodeFormatter interface.
@@ -52,6 +53,7 @@ func (node *Select) Format(ctx *FmtCtx) {
 	ctx.FormatNode(node.Select)
 	ctx.FormatNode(&node.OrderBy)
 	ctx.FormatNode(node.Limit)
+	ctx.FormatNode(&node.Locking)
 }
 
 // ParenSelect represents a parenthesized SELECT/UNION/VALUES statement.
@@ -276,11 +278,15 @@ type AliasedTableExpr struct {
 	Expr       TableExpr
 	Hints      *IndexHints
 	Ordinality bool
+	Lateral    bool
 	As         AliasClause
 }
 
 // Format implements the NodeFormatter interface.
 func (node *AliasedTableExpr) Format(ctx *FmtCtx) {
+	if node.Lateral {
+		ctx.WriteString("LATERAL ")
+	}
 	ctx.FormatNode(node.Expr)
 	if node.Hints != nil {
 		ctx.FormatNode(node.Hints)
@@ -433,6 +439,47 @@ func (node *GroupBy) Format(ctx *FmtCtx) {
 	}
 }
 
+// GroupingSetType is the kind of a GroupingSet.
+type GroupingSetType int
+
+// GroupingSet types.
+const (
+	// GroupingSets is GROUPING SETS (...).
+	GroupingSets GroupingSetType = iota
+	// Rollup is ROLLUP (...).
+	Rollup
+	// Cube is CUBE (...).
+	Cube
+)
+
+var groupingSetTypeName = [...]string{
+	GroupingSets: "GROUPING SETS",
+	Rollup:       "ROLLUP",
+	Cube:         "CUBE",
+}
+
+func (t GroupingSetType) String() string {
+	return groupingSetTypeName[t]
+}
+
+// GroupingSet represents a GROUPING SETS, ROLLUP or CUBE element of a GROUP
+// BY clause. The elements of a ROLLUP or CUBE are expressions or Tuples
+// that group several expressions together. The elements of GROUPING SETS
+// are themselves GROUP BY elements: expressions, Tuples (the empty Tuple is
+// the empty grouping set), ROLLUPs, CUBEs or nested GROUPING SETS.
+type GroupingSet struct {
+	Type  GroupingSetType
+	Exprs Exprs
+}
+
+// Format implements the NodeFormatter interface.
+func (node *GroupingSet) Format(ctx *FmtCtx) {
+	ctx.WriteString(node.Type.String())
+	ctx.WriteString(" (")
+	ctx.FormatNode(&node.Exprs)
+	ctx.WriteByte(')')
+}
+
 // DistinctOn represents a DISTINCT ON clause.
 type DistinctOn []Expr
 
@@ -564,6 +611,7 @@ type WindowDef struct {
 	RefName    Name
 	Partitions Exprs
 	OrderBy    OrderBy
+	Frame      *WindowFrame
 }
 
 // Format implements the NodeFormatter interface.
@@ -592,9 +640,227 @@ func (node *WindowDef) Format(ctx *FmtCtx) {
 			ctx.WriteString(orderByStr[1:])
 		}
 		needSpaceSeparator = true
-		_ = needSpaceSeparator // avoid compiler warning until TODO below is addressed.
 	}
-	// TODO(nvanbenschoten): Support Window Frames.
-	// if node.Frame != nil {}
+	if node.Frame != nil {
+		if needSpaceSeparator {
+			ctx.WriteRune(' ')
+		}
+		ctx.FormatNode(node.Frame)
+	}
 	ctx.WriteRune(')')
 }
+
+// WindowFrameMode indicates which mode of framing is used.
+type WindowFrameMode int
+
+const (
+	// RANGE is the mode of specifying frame in terms of logical range (e.g. 100 units cheaper).
+	RANGE WindowFrameMode = iota
+	// ROWS is the mode of specifying frame in terms of physical offsets (e.g. 1 row before etc).
+	ROWS
+)
+
+var windowFrameModeName = [...]string{
+	RANGE: "RANGE",
+	ROWS:  "ROWS",
+}
+
+func (m WindowFrameMode) String() string {
+	return windowFrameModeName[m]
+}
+
+// WindowFrameBoundType indicates which type of boundary is used.
+type WindowFrameBoundType int
+
+const (
+	// UnboundedPreceding represents UNBOUNDED PRECEDING type of boundary.
+	UnboundedPreceding WindowFrameBoundType = iota
+	// ValuePreceding represents 'value' PRECEDING type of boundary.
+	ValuePreceding
+	// CurrentRow represents CURRENT ROW type of boundary.
+	CurrentRow
+	// ValueFollowing represents 'value' FOLLOWING type of boundary.
+	ValueFollowing
+	// UnboundedFollowing represents UNBOUNDED FOLLOWING type of boundary.
+	UnboundedFollowing
+)
+
+// WindowFrameBound specifies the offset and the type of boundary.
+type WindowFrameBound struct {
+	BoundType  WindowFrameBoundType
+	OffsetExpr Expr
+}
+
+// HasOffset returns whether node contains an offset.
+func (node *WindowFrameBound) HasOffset() bool {
+	return node.BoundType == ValuePreceding || node.BoundType == ValueFollowing
+}
+
+// Format implements the NodeFormatter interface.
+func (node *WindowFrameBound) Format(ctx *FmtCtx) {
+	switch node.BoundType {
+	case UnboundedPreceding:
+		ctx.WriteString("UNBOUNDED PRECEDING")
+	case ValuePreceding:
+		ctx.FormatNode(node.OffsetExpr)
+		ctx.WriteString(" PRECEDING")
+	case CurrentRow:
+		ctx.WriteString("CURRENT ROW")
+	case ValueFollowing:
+		ctx.FormatNode(node.OffsetExpr)
+		ctx.WriteString(" FOLLOWING")
+	case UnboundedFollowing:
+		ctx.WriteString("UNBOUNDED FOLLOWING")
+	default:
+		panic(fmt.Sprintf("unhandled case: %d", node.BoundType))
+	}
+}
+
+// WindowFrameBounds specifies boundaries of the window frame. Both bounds are
+// inclusive. EndBound is nil when only the start of the frame was specified,
+// in which case the frame ends at the current row.
+type WindowFrameBounds struct {
+	StartBound *WindowFrameBound
+	EndBound   *WindowFrameBound
+}
+
+// WindowFrame represents the frame clause of a window definition, which
+// determines the set of rows over which the window function is computed for
+// each row.
+type WindowFrame struct {
+	Mode   WindowFrameMode   // the mode of framing being used
+	Bounds WindowFrameBounds // the bounds of the frame
+}
+
+// Format implements the NodeFormatter interface.
+func (node *WindowFrame) Format(ctx *FmtCtx) {
+	ctx.WriteString(node.Mode.String())
+	ctx.WriteRune(' ')
+	if node.Bounds.EndBound != nil {
+		ctx.WriteString("BETWEEN ")
+		ctx.FormatNode(node.Bounds.StartBound)
+		ctx.WriteString(" AND ")
+		ctx.FormatNode(node.Bounds.EndBound)
+	} else {
+		ctx.FormatNode(node.Bounds.StartBound)
+	}
+}
+
+// LockingClause represents a locking clause, like FOR UPDATE.
+type LockingClause []*LockingItem
+
+// Format implements the NodeFormatter interface.
+func (node *LockingClause) Format(ctx *FmtCtx) {
+	for _, n := range *node {
+		ctx.FormatNode(n)
+	}
+}
+
+// LockingItem represents a single locking item in a locking clause.
+type LockingItem struct {
+	Strength   LockingStrength
+	Targets    NameList
+	WaitPolicy LockingWaitPolicy
+}
+
+// Format implements the NodeFormatter interface.
+func (node *LockingItem) Format(ctx *FmtCtx) {
+	ctx.FormatNode(node.Strength)
+	if len(node.Targets) > 0 {
+		ctx.WriteString(" OF ")
+		ctx.FormatNode(&node.Targets)
+	}
+	ctx.FormatNode(node.WaitPolicy)
+}
+
+// LockingStrength represents the possible row-level lock modes for a SELECT
+// statement. The values are ordered from weakest to strongest.
+type LockingStrength byte
+
+// The ordering of the variants is important, because the highest numerical
+// value takes precedence when row-level locking is specified multiple ways.
+const (
+	// ForNone represents the default - no row-level locking.
+	ForNone LockingStrength = iota
+	// ForKeyShare represents FOR KEY SHARE.
+	ForKeyShare
+	// ForShare represents FOR SHARE.
+	ForShare
+	// ForNoKeyUpdate represents FOR NO KEY UPDATE.
+	ForNoKeyUpdate
+	// ForUpdate represents FOR UPDATE.
+	ForUpdate
+)
+
+var lockingStrengthName = [...]string{
+	ForNone:        "",
+	ForKeyShare:    "FOR KEY SHARE",
+	ForShare:       "FOR SHARE",
+	ForNoKeyUpdate: "FOR NO KEY UPDATE",
+	ForUpdate:      "FOR UPDATE",
+}
+
+func (s LockingStrength) String() string {
+	return lockingStrengthName[s]
+}
+
+// Format implements the NodeFormatter interface.
+func (s LockingStrength) Format(ctx *FmtCtx) {
+	if s != ForNone {
+		ctx.WriteString(" ")
+		ctx.WriteString(s.String())
+	}
+}
+
+// Max returns the maximum of the two locking strengths.
+func (s LockingStrength) Max(s2 LockingStrength) LockingStrength {
+	if s > s2 {
+		return s
+	}
+	return s2
+}
+
+// LockingWaitPolicy represents the possible policies for dealing with rows
+// being locked by FOR UPDATE/SHARE clauses (i.e., it represents the NOWAIT
+// and SKIP LOCKED options). The values are ordered from the least to the
+// most strict.
+type LockingWaitPolicy byte
+
+// The ordering of the variants is important, because the highest numerical
+// value takes precedence when row-level locking is specified multiple ways.
+const (
+	// LockWaitBlock represents the default - wait for the lock to become
+	// available.
+	LockWaitBlock LockingWaitPolicy = iota
+	// LockWaitSkip represents SKIP LOCKED - skip rows that can't be locked.
+	LockWaitSkip
+	// LockWaitError represents NOWAIT - raise an error if a row cannot be
+	// locked.
+	LockWaitError
+)
+
+var lockingWaitPolicyName = [...]string{
+	LockWaitBlock: "",
+	LockWaitSkip:  "SKIP LOCKED",
+	LockWaitError: "NOWAIT",
+}
+
+func (p LockingWaitPolicy) String() string {
+	return lockingWaitPolicyName[p]
+}
+
+// Format implements the NodeFormatter interface.
+func (p LockingWaitPolicy) Format(ctx *FmtCtx) {
+	if p != LockWaitBlock {
+		ctx.WriteString(" ")
+		ctx.WriteString(p.String())
+	}
+}
+
+// Max returns the maximum of the two locking wait policies.
+func (p LockingWaitPolicy) Max(p2 LockingWaitPolicy) LockingWaitPolicy {
+	if p > p2 {
+		return p
+	}
+	return p2
+}
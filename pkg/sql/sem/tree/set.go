@@ -27,11 +27,17 @@ package tree
 type SetVar struct {
 	Name   string
 	Values Exprs
+	// Local is set for SET LOCAL, which only lasts until the end of the
+	// current transaction.
+	Local bool
 }
 
 // Format implements the NodeFormatter interface.
 func (node *SetVar) Format(ctx *FmtCtx) {
 	ctx.WriteString("SET ")
+	if node.Local {
+		ctx.WriteString("LOCAL ")
+	}
 	if node.Name == "" {
 		ctx.WriteString("ROW (")
 		ctx.FormatNode(&node.Values)
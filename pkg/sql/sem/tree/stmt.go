@@ -299,7 +299,18 @@ func (*CreateRole) hiddenFromShowQueries() {}
 func (*CreateView) StatementType() StatementType { return DDL }
 
 // StatementTag returns a short string identifying the type of statement.
-func (*CreateView) StatementTag() string { return "CREATE VIEW" }
+func (n *CreateView) StatementTag() string {
+	if n.Materialized {
+		return "CREATE MATERIALIZED VIEW"
+	}
+	return "CREATE VIEW"
+}
+
+// StatementType implements the Statement interface.
+func (*CreateFunction) StatementType() StatementType { return DDL }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*CreateFunction) StatementTag() string { return "CREATE FUNCTION" }
 
 // StatementType implements the Statement interface.
 func (*CreateSequence) StatementType() StatementType { return DDL }
@@ -361,7 +372,18 @@ func (*DropTable) StatementTag() string { return "DROP TABLE" }
 func (*DropView) StatementType() StatementType { return DDL }
 
 // StatementTag returns a short string identifying the type of statement.
-func (*DropView) StatementTag() string { return "DROP VIEW" }
+func (n *DropView) StatementTag() string {
+	if n.IsMaterialized {
+		return "DROP MATERIALIZED VIEW"
+	}
+	return "DROP VIEW"
+}
+
+// StatementType implements the Statement interface.
+func (*DropFunction) StatementType() StatementType { return DDL }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*DropFunction) StatementTag() string { return "DROP FUNCTION" }
 
 // StatementType implements the Statement interface.
 func (*DropSequence) StatementType() StatementType { return DDL }
@@ -443,6 +465,12 @@ func (*Prepare) StatementTag() string { return "PREPARE" }
 
 func (*Prepare) hiddenFromStats() {}
 
+// StatementType implements the Statement interface.
+func (*RefreshMaterializedView) StatementType() StatementType { return DDL }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*RefreshMaterializedView) StatementTag() string { return "REFRESH MATERIALIZED VIEW" }
+
 // StatementType implements the Statement interface.
 func (*ReleaseSavepoint) StatementType() StatementType { return Ack }
 
@@ -874,10 +902,12 @@ func (n *AlterTable) String() string                { return AsString(n) }
 func (n *AlterTableCmds) String() string            { return AsString(n) }
 func (n *AlterTableAddColumn) String() string       { return AsString(n) }
 func (n *AlterTableAddConstraint) String() string   { return AsString(n) }
+func (n *AlterTableAlterColumnType) String() string { return AsString(n) }
 func (n *AlterTableDropColumn) String() string      { return AsString(n) }
 func (n *AlterTableDropConstraint) String() string  { return AsString(n) }
 func (n *AlterTableDropNotNull) String() string     { return AsString(n) }
 func (n *AlterTableSetDefault) String() string      { return AsString(n) }
+func (n *AlterTableSetNotNull) String() string      { return AsString(n) }
 func (n *AlterUserSetPassword) String() string      { return AsString(n) }
 func (n *AlterSequence) String() string             { return AsString(n) }
 func (n *Backup) String() string                    { return AsString(n) }
@@ -889,6 +919,7 @@ func (n *CommitTransaction) String() string         { return AsString(n) }
 func (n *CopyFrom) String() string                  { return AsString(n) }
 func (n *CreateChangefeed) String() string          { return AsString(n) }
 func (n *CreateDatabase) String() string            { return AsString(n) }
+func (n *CreateFunction) String() string            { return AsString(n) }
 func (n *CreateIndex) String() string               { return AsString(n) }
 func (n *CreateRole) String() string                { return AsString(n) }
 func (n *CreateTable) String() string               { return AsString(n) }
@@ -899,6 +930,7 @@ func (n *CreateView) String() string                { return AsString(n) }
 func (n *Deallocate) String() string                { return AsString(n) }
 func (n *Delete) String() string                    { return AsString(n) }
 func (n *DropDatabase) String() string              { return AsString(n) }
+func (n *DropFunction) String() string              { return AsString(n) }
 func (n *DropIndex) String() string                 { return AsString(n) }
 func (n *DropRole) String() string                  { return AsString(n) }
 func (n *DropTable) String() string                 { return AsString(n) }
@@ -914,6 +946,7 @@ func (n *Import) String() string                    { return AsString(n) }
 func (n *ParenSelect) String() string               { return AsString(n) }
 func (n *PauseJob) String() string                  { return AsString(n) }
 func (n *Prepare) String() string                   { return AsString(n) }
+func (n *RefreshMaterializedView) String() string   { return AsString(n) }
 func (n *ReleaseSavepoint) String() string          { return AsString(n) }
 func (n *TestingRelocate) String() string           { return AsString(n) }
 func (n *RenameColumn) String() string              { return AsString(n) }
@@ -445,6 +445,10 @@ var (
 	errInvalidMinUsage      = pgerror.NewError(pgerror.CodeSyntaxError, "MINVALUE can only appear within a range partition expression")
 )
 
+var errInvalidGroupingSetUsage = pgerror.NewError(pgerror.CodeSyntaxError, "grouping sets can only appear in GROUP BY")
+
+var errOrderByIndexInWithinGroup = pgerror.NewError(pgerror.CodeFeatureNotSupportedError, "ORDER BY INDEX in WITHIN GROUP is not supported")
+
 // TypeCheck implements the Expr interface.
 func (expr *FuncExpr) TypeCheck(ctx *SemaContext, desired types.T) (TypedExpr, error) {
 	var searchPath sessiondata.SearchPath
@@ -456,7 +460,33 @@ func (expr *FuncExpr) TypeCheck(ctx *SemaContext, desired types.T) (TypedExpr, e
 		return nil, err
 	}
 
-	typedSubExprs, fns, err := typeCheckOverloadedExprs(ctx, desired, def.Definition, false, expr.Exprs...)
+	// Same error messages as Postgres.
+	if def.IsOrderedSetAggregate() {
+		if expr.OrderBy == nil {
+			return nil, pgerror.NewErrorf(pgerror.CodeWrongObjectTypeError,
+				"WITHIN GROUP is required for ordered-set aggregate %s", &expr.Func)
+		}
+		if expr.IsWindowFunctionApplication() {
+			return nil, pgerror.NewErrorf(pgerror.CodeFeatureNotSupportedError,
+				"OVER is not supported for ordered-set aggregate %s", &expr.Func)
+		}
+	} else if expr.OrderBy != nil {
+		return nil, pgerror.NewErrorf(pgerror.CodeWrongObjectTypeError,
+			"%s is not an ordered-set aggregate, so it cannot have WITHIN GROUP", &expr.Func)
+	}
+
+	// The sort expressions of an ordered-set aggregate are resolved as
+	// trailing arguments of the function.
+	for _, o := range expr.OrderBy {
+		if o.OrderType != OrderByColumn {
+			return nil, errOrderByIndexInWithinGroup
+		}
+		if o.Direction == Descending {
+			return nil, pgerror.Unimplemented("within group desc",
+				"WITHIN GROUP (ORDER BY ... DESC) is not supported")
+		}
+	}
+	typedSubExprs, fns, err := typeCheckOverloadedExprs(ctx, desired, def.Definition, false, expr.AggregateArgs()...)
 	if err != nil {
 		return nil, errors.Wrapf(err, "%s()", def.Name)
 	}
@@ -571,9 +601,12 @@ func (expr *FuncExpr) TypeCheck(ctx *SemaContext, desired types.T) (TypedExpr, e
 			"insufficient privilege to use %s", expr.Func)
 	}
 
-	for i, subExpr := range typedSubExprs {
+	for i, subExpr := range typedSubExprs[:len(expr.Exprs)] {
 		expr.Exprs[i] = subExpr
 	}
+	for i, subExpr := range typedSubExprs[len(expr.Exprs):] {
+		expr.OrderBy[i].Expr = subExpr
+	}
 	expr.fn = builtin
 	expr.typ = builtin.returnType()(typedSubExprs)
 	return expr, nil
@@ -768,6 +801,11 @@ func (expr MaxVal) TypeCheck(_ *SemaContext, desired types.T) (TypedExpr, error)
 	return nil, errInvalidMaxUsage
 }
 
+// TypeCheck implements the Expr interface.
+func (expr *GroupingSet) TypeCheck(_ *SemaContext, desired types.T) (TypedExpr, error) {
+	return nil, errInvalidGroupingSetUsage
+}
+
 // TypeCheck implements the Expr interface.
 func (expr *NumVal) TypeCheck(ctx *SemaContext, desired types.T) (TypedExpr, error) {
 	return typeCheckConstant(expr, ctx, desired)
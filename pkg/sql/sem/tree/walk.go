@@ -191,6 +191,13 @@ func (expr *FuncExpr) CopyNode() *FuncExpr {
 		exprCopy.WindowDef = &windowDefCopy
 	}
 	exprCopy.Exprs = append(Exprs(nil), exprCopy.Exprs...)
+	if len(expr.OrderBy) > 0 {
+		newOrderBy := make(OrderBy, len(expr.OrderBy))
+		for i, o := range expr.OrderBy {
+			newOrderBy[i] = &Order{OrderType: o.OrderType, Expr: o.Expr, Direction: o.Direction}
+		}
+		exprCopy.OrderBy = newOrderBy
+	}
 	if windowDef := exprCopy.WindowDef; windowDef != nil {
 		windowDef.Partitions = append(Exprs(nil), windowDef.Partitions...)
 		if len(windowDef.OrderBy) > 0 {
@@ -200,6 +207,17 @@ func (expr *FuncExpr) CopyNode() *FuncExpr {
 			}
 			windowDef.OrderBy = newOrderBy
 		}
+		if windowDef.Frame != nil {
+			frameCopy := *windowDef.Frame
+			boundsCopy := &frameCopy.Bounds
+			startBoundCopy := *boundsCopy.StartBound
+			boundsCopy.StartBound = &startBoundCopy
+			if boundsCopy.EndBound != nil {
+				endBoundCopy := *boundsCopy.EndBound
+				boundsCopy.EndBound = &endBoundCopy
+			}
+			windowDef.Frame = &frameCopy
+		}
 	}
 	return &exprCopy
 }
@@ -216,6 +234,18 @@ func (expr *FuncExpr) Walk(v Visitor) Expr {
 			ret.Exprs[i] = e
 		}
 	}
+	for i := range expr.OrderBy {
+		if expr.OrderBy[i].OrderType != OrderByColumn {
+			continue
+		}
+		e, changed := WalkExpr(v, expr.OrderBy[i].Expr)
+		if changed {
+			if ret == expr {
+				ret = expr.CopyNode()
+			}
+			ret.OrderBy[i].Expr = e
+		}
+	}
 	if expr.WindowDef != nil {
 		for i := range expr.WindowDef.Partitions {
 			e, changed := WalkExpr(v, expr.WindowDef.Partitions[i])
@@ -238,6 +268,27 @@ func (expr *FuncExpr) Walk(v Visitor) Expr {
 				ret.WindowDef.OrderBy[i].Expr = e
 			}
 		}
+		if expr.WindowDef.Frame != nil {
+			bounds := &expr.WindowDef.Frame.Bounds
+			if bounds.StartBound.HasOffset() {
+				e, changed := WalkExpr(v, bounds.StartBound.OffsetExpr)
+				if changed {
+					if ret == expr {
+						ret = expr.CopyNode()
+					}
+					ret.WindowDef.Frame.Bounds.StartBound.OffsetExpr = e
+				}
+			}
+			if bounds.EndBound != nil && bounds.EndBound.HasOffset() {
+				e, changed := WalkExpr(v, bounds.EndBound.OffsetExpr)
+				if changed {
+					if ret == expr {
+						ret = expr.CopyNode()
+					}
+					ret.WindowDef.Frame.Bounds.EndBound.OffsetExpr = e
+				}
+			}
+		}
 	}
 	if expr.Filter != nil {
 		e, changed := WalkExpr(v, expr.Filter)
@@ -453,6 +504,16 @@ func (expr *Tuple) Walk(v Visitor) Expr {
 	return expr
 }
 
+// Walk implements the Expr interface.
+func (expr *GroupingSet) Walk(v Visitor) Expr {
+	if exprs, changed := walkExprSlice(v, expr.Exprs); changed {
+		exprCopy := *expr
+		exprCopy.Exprs = exprs
+		return &exprCopy
+	}
+	return expr
+}
+
 // Walk implements the Expr interface.
 func (expr *Array) Walk(v Visitor) Expr {
 	if exprs, changed := walkExprSlice(v, expr.Exprs); changed {
@@ -14,7 +14,13 @@
 
 package tree
 
-import "context"
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/types"
+)
 
 // IndexedRow is a row with a corresponding index.
 type IndexedRow struct {
@@ -22,14 +28,27 @@ type IndexedRow struct {
 	Row Datums
 }
 
-// WindowFrame is a view into a subset of data over which calculations are made.
-type WindowFrame struct {
-	// constant for all calls to WindowFunc.Add
+// WindowFrameRun contains the runtime state of window frame during calculations.
+type WindowFrameRun struct {
+	// constant for all calls to WindowFunc.Compute
 	Rows        []IndexedRow
-	ArgIdxStart int // the index which arguments to the window function begin
-	ArgCount    int // the number of window function arguments
+	ArgIdxStart int          // the index which arguments to the window function begin
+	ArgCount    int          // the number of window function arguments
+	Frame       *WindowFrame // the frame specification, or nil for the default frame
+
+	// StartBoundOffset and EndBoundOffset are the values of the offsets of
+	// the frame bounds, if they have one. They are DInts in ROWS mode, and
+	// have the right operand type of OrdBinOps in RANGE mode.
+	StartBoundOffset Datum
+	EndBoundOffset   Datum
 
-	// changes for each row (each call to WindowFunc.Add)
+	// The following fields are only used in RANGE mode with an offset, in
+	// which case the window has exactly one ORDER BY column.
+	OrdColIdx     int            // the index of the ORDER BY column in Rows
+	OrdDescending bool           // whether the ORDER BY column is sorted in descending order
+	OrdBinOps     WindowRangeOps // the operators used to add offsets to the ORDER BY column
+
+	// changes for each row (each call to WindowFunc.Compute)
 	RowIdx int // the current row index
 
 	// changes for each peer group
@@ -37,39 +56,236 @@ type WindowFrame struct {
 	PeerRowCount int // the number of rows in the current peer group
 }
 
-// Rank returns the rank of this frame.
-func (wf WindowFrame) Rank() int {
-	return wf.RowIdx + 1
+// WindowRangeOps contains the binary operators that add and subtract an offset
+// to the values of the ORDER BY column of a window in RANGE mode.
+type WindowRangeOps struct {
+	Plus, Minus BinOp
+}
+
+// LookupWindowRangeOps returns the operators used to add and subtract an
+// offset of type offsetType to values of type ordType, if they exist.
+func LookupWindowRangeOps(ordType, offsetType types.T) (WindowRangeOps, bool) {
+	plus, ok := BinOps[Plus].lookupImpl(ordType, offsetType)
+	if !ok {
+		return WindowRangeOps{}, false
+	}
+	minus, ok := BinOps[Minus].lookupImpl(ordType, offsetType)
+	if !ok {
+		return WindowRangeOps{}, false
+	}
+	return WindowRangeOps{Plus: plus, Minus: minus}, true
+}
+
+// Rank returns the rank of the current row.
+func (wfr *WindowFrameRun) Rank() int {
+	return wfr.RowIdx + 1
+}
+
+// RowCount returns the number of rows in the current partition.
+func (wfr *WindowFrameRun) RowCount() int {
+	return len(wfr.Rows)
+}
+
+// DefaultFrameSize returns the size of the default window frame, which
+// contains all rows from the start of the partition to the last peer of the
+// current row.
+func (wfr *WindowFrameRun) DefaultFrameSize() int {
+	return wfr.FirstPeerIdx + wfr.PeerRowCount
+}
+
+// IsDefaultFrame returns whether the frame of the window is the default one,
+// RANGE BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW.
+func (wfr *WindowFrameRun) IsDefaultFrame() bool {
+	if wfr.Frame == nil {
+		return true
+	}
+	bounds := wfr.Frame.Bounds
+	return wfr.Frame.Mode == RANGE &&
+		bounds.StartBound.BoundType == UnboundedPreceding &&
+		(bounds.EndBound == nil || bounds.EndBound.BoundType == CurrentRow)
+}
+
+// FrameStartIdx returns the index of the first row in the window frame of
+// the current row. The frame is empty if it is not less than FrameEndIdx.
+func (wfr *WindowFrameRun) FrameStartIdx(evalCtx *EvalContext) (int, error) {
+	if wfr.Frame == nil {
+		return 0, nil
+	}
+	bound := wfr.Frame.Bounds.StartBound
+	return wfr.boundIdx(evalCtx, bound, wfr.StartBoundOffset, true /* start */)
 }
 
-// RowCount returns the number of rows in this frame.
-func (wf WindowFrame) RowCount() int {
-	return len(wf.Rows)
+// FrameEndIdx returns the index of the row following the last row in the
+// window frame of the current row. It is never smaller than FrameStartIdx.
+func (wfr *WindowFrameRun) FrameEndIdx(evalCtx *EvalContext) (int, error) {
+	if wfr.Frame == nil {
+		return wfr.DefaultFrameSize(), nil
+	}
+	bound := wfr.Frame.Bounds.EndBound
+	if bound == nil {
+		bound = &WindowFrameBound{BoundType: CurrentRow}
+	}
+	end, err := wfr.boundIdx(evalCtx, bound, wfr.EndBoundOffset, false /* start */)
+	if err != nil {
+		return 0, err
+	}
+	start, err := wfr.FrameStartIdx(evalCtx)
+	if err != nil {
+		return 0, err
+	}
+	if end < start {
+		// The frame is empty, e.g. ROWS BETWEEN 2 FOLLOWING AND 1 FOLLOWING.
+		return start, nil
+	}
+	return end, nil
 }
 
-// FrameSize returns the size of this frame.
-// TODO(nvanbenschoten): This definition only holds while we don't support
-// frame specification (RANGE or ROWS) in the OVER clause.
-func (wf WindowFrame) FrameSize() int {
-	return wf.FirstPeerIdx + wf.PeerRowCount
+// FrameSize returns the number of rows in the window frame of the current
+// row.
+func (wfr *WindowFrameRun) FrameSize(evalCtx *EvalContext) (int, error) {
+	start, err := wfr.FrameStartIdx(evalCtx)
+	if err != nil {
+		return 0, err
+	}
+	end, err := wfr.FrameEndIdx(evalCtx)
+	if err != nil {
+		return 0, err
+	}
+	return end - start, nil
+}
+
+// boundIdx returns the index of the first row of the frame (if start is true)
+// or of the row following the frame (if start is false), as determined by the
+// given bound.
+func (wfr *WindowFrameRun) boundIdx(
+	evalCtx *EvalContext, bound *WindowFrameBound, offset Datum, start bool,
+) (int, error) {
+	switch bound.BoundType {
+	case UnboundedPreceding:
+		return 0, nil
+
+	case UnboundedFollowing:
+		return wfr.RowCount(), nil
+
+	case CurrentRow:
+		if wfr.Frame.Mode == ROWS {
+			if start {
+				return wfr.RowIdx, nil
+			}
+			return wfr.RowIdx + 1, nil
+		}
+		// In RANGE mode, the frame starts with the first peer of the current
+		// row and ends with the last one.
+		if start {
+			return wfr.FirstPeerIdx, nil
+		}
+		return wfr.FirstPeerIdx + wfr.PeerRowCount, nil
+
+	case ValuePreceding, ValueFollowing:
+		preceding := bound.BoundType == ValuePreceding
+		if wfr.Frame.Mode == ROWS {
+			return wfr.rowsOffsetIdx(int64(MustBeDInt(offset)), preceding, start), nil
+		}
+		return wfr.rangeOffsetIdx(evalCtx, offset, preceding, start)
+
+	default:
+		panic(fmt.Sprintf("unhandled bound type: %d", bound.BoundType))
+	}
+}
+
+// rowsOffsetIdx implements boundIdx for a bound with an offset in ROWS mode.
+func (wfr *WindowFrameRun) rowsOffsetIdx(offset int64, preceding bool, start bool) int {
+	// The offset can be arbitrarily large: clamp it to the partition size to
+	// avoid overflows.
+	if offset > int64(wfr.RowCount()) {
+		offset = int64(wfr.RowCount())
+	}
+	idx := wfr.RowIdx + int(offset)
+	if preceding {
+		idx = wfr.RowIdx - int(offset)
+	}
+	if !start {
+		idx++
+	}
+	if idx < 0 {
+		return 0
+	}
+	if idx > wfr.RowCount() {
+		return wfr.RowCount()
+	}
+	return idx
+}
+
+// rangeOffsetIdx implements boundIdx for a bound with an offset in RANGE
+// mode. The frame contains the rows whose value in the ORDER BY column is
+// within offset of the value of the current row. Since the partition is
+// sorted on that column, the bound is found using binary search.
+func (wfr *WindowFrameRun) rangeOffsetIdx(
+	evalCtx *EvalContext, offset Datum, preceding bool, start bool,
+) (int, error) {
+	cur := wfr.Rows[wfr.RowIdx].Row[wfr.OrdColIdx]
+	if cur == DNull {
+		// The frame of a row with a NULL value only contains its peers.
+		if start {
+			return wfr.FirstPeerIdx, nil
+		}
+		return wfr.FirstPeerIdx + wfr.PeerRowCount, nil
+	}
+
+	// Rows that precede the current row in a descending order have larger
+	// values.
+	op := wfr.OrdBinOps.Plus
+	if preceding != wfr.OrdDescending {
+		op = wfr.OrdBinOps.Minus
+	}
+	target, err := op.fn(evalCtx, cur, offset)
+	if err != nil {
+		// The target value is out of the range of the type, so it is beyond
+		// all the rows of the partition.
+		if preceding {
+			return 0, nil
+		}
+		return wfr.RowCount(), nil
+	}
+
+	// cmp returns the position of the i-th row relative to the target in the
+	// order of the partition.
+	cmp := func(i int) int {
+		c := wfr.Rows[i].Row[wfr.OrdColIdx].Compare(evalCtx, target)
+		if wfr.OrdDescending {
+			return -c
+		}
+		return c
+	}
+	return sort.Search(wfr.RowCount(), func(i int) bool {
+		if start {
+			return cmp(i) >= 0
+		}
+		return cmp(i) > 0
+	}), nil
 }
 
 // FirstInPeerGroup returns if the current row is the first in its peer group.
-func (wf WindowFrame) FirstInPeerGroup() bool {
-	return wf.RowIdx == wf.FirstPeerIdx
+func (wfr *WindowFrameRun) FirstInPeerGroup() bool {
+	return wfr.RowIdx == wfr.FirstPeerIdx
 }
 
 // Args returns the current argument set in the window frame.
-func (wf WindowFrame) Args() Datums {
-	return wf.ArgsWithRowOffset(0)
+func (wfr *WindowFrameRun) Args() Datums {
+	return wfr.ArgsWithRowOffset(0)
+}
+
+// ArgsWithRowOffset returns the argument set at the given offset in the window frame.
+func (wfr *WindowFrameRun) ArgsWithRowOffset(offset int) Datums {
+	return wfr.ArgsByRowIdx(wfr.RowIdx + offset)
 }
 
-// ArgsWithRowOffset returns the argumnent set at the given offset in the window frame.
-func (wf WindowFrame) ArgsWithRowOffset(offset int) Datums {
-	return wf.Rows[wf.RowIdx+offset].Row[wf.ArgIdxStart : wf.ArgIdxStart+wf.ArgCount]
+// ArgsByRowIdx returns the argument set of the row at idx in the partition.
+func (wfr *WindowFrameRun) ArgsByRowIdx(idx int) Datums {
+	return wfr.Rows[idx].Row[wfr.ArgIdxStart : wfr.ArgIdxStart+wfr.ArgCount]
 }
 
-// WindowFunc performs a computation on each row using data from a provided WindowFrame.
+// WindowFunc performs a computation on each row using data from a provided WindowFrameRun.
 type WindowFunc interface {
 	// Compute computes the window function for the provided window frame, given the
 	// current state of WindowFunc. The method should be called sequentially for every
@@ -77,7 +293,7 @@ type WindowFunc interface {
 	// because there is an implicit carried dependency between each row and all those
 	// that have come before it (like in an AggregateFunc). As such, this approach does
 	// not present any exploitable associativity/commutativity for optimization.
-	Compute(context.Context, *EvalContext, WindowFrame) (Datum, error)
+	Compute(context.Context, *EvalContext, *WindowFrameRun) (Datum, error)
 
 	// Close allows the window function to free any memory it requested during execution,
 	// such as during the execution of an aggregation like CONCAT_AGG or ARRAY_AGG.
@@ -21,6 +21,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/internal/client"
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/coltypes"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
@@ -216,9 +217,13 @@ func assignSequenceOptions(
 		optionsSeen[option.Name] = true
 
 		switch option.Name {
+		case tree.SeqOptAs:
+			// Handled below, once the bounds are known.
 		case tree.SeqOptCycle:
 			return pgerror.NewError(pgerror.CodeFeatureNotSupportedError,
 				"CYCLE option is not supported")
+		case tree.SeqOptOwnedBy:
+			// Handled by the callers, which have access to the owning table.
 		case tree.SeqOptNoCycle:
 			// Do nothing; this is the default.
 		case tree.SeqOptCache:
@@ -250,6 +255,32 @@ func assignSequenceOptions(
 		}
 	}
 
+	// Restrict the bounds to the range of the data type. Bounds that were
+	// not specified explicitly are narrowed to fit.
+	for _, option := range optsNode {
+		if option.Name != tree.SeqOptAs {
+			continue
+		}
+		typeMin, typeMax, err := sequenceTypeBounds(option.AsType)
+		if err != nil {
+			return err
+		}
+		if opts.MinValue < typeMin {
+			if optionsSeen[tree.SeqOptMinValue] {
+				return pgerror.NewErrorf(pgerror.CodeInvalidParameterValueError,
+					"MINVALUE (%d) is out of range for sequence data type %s", opts.MinValue, option.AsType)
+			}
+			opts.MinValue = typeMin
+		}
+		if opts.MaxValue > typeMax {
+			if optionsSeen[tree.SeqOptMaxValue] {
+				return pgerror.NewErrorf(pgerror.CodeInvalidParameterValueError,
+					"MAXVALUE (%d) is out of range for sequence data type %s", opts.MaxValue, option.AsType)
+			}
+			opts.MaxValue = typeMax
+		}
+	}
+
 	// If start option not specified, set it to MinValue (for ascending sequences)
 	// or MaxValue (for descending sequences).
 	if _, startSeen := optionsSeen[tree.SeqOptStart]; !startSeen {
@@ -274,6 +305,23 @@ func assignSequenceOptions(
 	return nil
 }
 
+// sequenceTypeBounds returns the range of values of the integer type
+// used in the AS option of a sequence.
+func sequenceTypeBounds(typ coltypes.T) (int64, int64, error) {
+	if t, ok := typ.(*coltypes.TInt); ok && !t.IsSerial() {
+		switch t.Width {
+		case 16:
+			return math.MinInt16, math.MaxInt16, nil
+		case 32:
+			return math.MinInt32, math.MaxInt32, nil
+		case 0, 64:
+			return math.MinInt64, math.MaxInt64, nil
+		}
+	}
+	return 0, 0, pgerror.NewErrorf(pgerror.CodeInvalidParameterValueError,
+		"sequence type must be smallint, integer, or bigint, found %s", typ)
+}
+
 // maybeAddSequenceDependencies adds references between the column and sequence descriptors,
 // if the column has a DEFAULT expression that uses one or more sequences. (Usually just one,
 // e.g. `DEFAULT nextval('my_sequence')`.
@@ -347,6 +395,136 @@ func removeSequenceDependencies(
 	return nil
 }
 
+// setSequenceOwner makes the column named by owner, as found in an OWNED
+// BY option, the owner of the sequence in place of its current owner, if
+// any. A nil owner (OWNED BY NONE) just removes the current owner.
+//   - the sequence references the table of the column in DependsOn.
+//   - the column references the sequence in OwnsSequenceIds.
+// The table descriptor is saved, but the sequence descriptor is mutated
+// and not saved; the caller must save it.
+func (p *planner) setSequenceOwner(
+	ctx context.Context, seqDesc *sqlbase.TableDescriptor, owner *tree.UnresolvedName,
+) error {
+	if err := p.removeSequenceOwner(ctx, seqDesc); err != nil {
+		return err
+	}
+	if owner == nil {
+		return nil
+	}
+
+	tableName := tree.UnresolvedName{NumParts: owner.NumParts - 1}
+	copy(tableName.Parts[:], owner.Parts[1:])
+	tn, err := tree.NormalizeTableName(&tableName)
+	if err != nil {
+		return err
+	}
+	var tableDesc *TableDescriptor
+	// DDL statements avoid the cache to avoid leases, and can view non-public descriptors.
+	p.runWithOptions(resolveFlags{allowAdding: true, skipCache: true}, func() {
+		tableDesc, err = ResolveExistingObject(ctx, p, &tn, true /*required*/, requireTableDesc)
+	})
+	if err != nil {
+		return err
+	}
+	if tableDesc.ParentID != seqDesc.ParentID {
+		return pgerror.NewError(pgerror.CodeObjectNotInPrerequisiteStateError,
+			"sequence must be in same database as table it is linked to")
+	}
+	if err := p.CheckPrivilege(ctx, tableDesc, privilege.CREATE); err != nil {
+		return err
+	}
+
+	var col *sqlbase.ColumnDescriptor
+	for i := range tableDesc.Columns {
+		if tableDesc.Columns[i].Name == owner.Parts[0] {
+			col = &tableDesc.Columns[i]
+			break
+		}
+	}
+	if col == nil {
+		return sqlbase.NewUndefinedColumnError(owner.Parts[0])
+	}
+	col.OwnsSequenceIds = append(col.OwnsSequenceIds, seqDesc.ID)
+	if err := p.saveNonmutationAndNotify(ctx, tableDesc); err != nil {
+		return err
+	}
+	seqDesc.DependsOn = []sqlbase.ID{tableDesc.ID}
+	return nil
+}
+
+// removeSequenceOwner removes the reference to the sequence from the
+// column that owns it, if any. The sequence descriptor is mutated but not
+// saved; the caller must save it.
+func (p *planner) removeSequenceOwner(ctx context.Context, seqDesc *sqlbase.TableDescriptor) error {
+	for _, tableID := range seqDesc.DependsOn {
+		tableDesc, err := sqlbase.GetTableDescFromID(ctx, p.txn, tableID)
+		if err != nil {
+			return err
+		}
+		if tableDesc.Dropped() {
+			// The owning table is being dropped. No need to modify it further.
+			continue
+		}
+		for i := range tableDesc.Columns {
+			col := &tableDesc.Columns[i]
+			for j, id := range col.OwnsSequenceIds {
+				if id == seqDesc.ID {
+					col.OwnsSequenceIds = append(col.OwnsSequenceIds[:j], col.OwnsSequenceIds[j+1:]...)
+					break
+				}
+			}
+		}
+		if err := p.saveNonmutationAndNotify(ctx, tableDesc); err != nil {
+			return err
+		}
+	}
+	seqDesc.DependsOn = nil
+	return nil
+}
+
+// canRemoveOwnedSequences verifies that the sequences owned by a column
+// that is about to be dropped are not used by the columns of tables that
+// are not being dropped as well.
+func (p *planner) canRemoveOwnedSequences(
+	ctx context.Context, col *sqlbase.ColumnDescriptor, dropping map[sqlbase.ID]bool,
+) error {
+	for _, seqID := range col.OwnsSequenceIds {
+		seqDesc, err := sqlbase.GetTableDescFromID(ctx, p.txn, seqID)
+		if err != nil {
+			return err
+		}
+		for _, ref := range seqDesc.DependedOnBy {
+			if !dropping[ref.ID] {
+				return p.sequenceDependencyError(ctx, seqDesc)
+			}
+		}
+	}
+	return nil
+}
+
+// dropSequencesOwnedByCol drops the sequences owned by a column that is
+// being dropped. The column descriptor is mutated but not saved; the
+// caller must save it.
+func (p *planner) dropSequencesOwnedByCol(ctx context.Context, col *sqlbase.ColumnDescriptor) error {
+	for _, seqID := range col.OwnsSequenceIds {
+		seqDesc, err := sqlbase.GetTableDescFromID(ctx, p.txn, seqID)
+		if err != nil {
+			return err
+		}
+		if seqDesc.Dropped() {
+			continue
+		}
+		// There is no need to remove the reference from the column, which
+		// is going away.
+		seqDesc.DependsOn = nil
+		if err := p.dropSequenceImpl(ctx, seqDesc, tree.DropCascade); err != nil {
+			return err
+		}
+	}
+	col.OwnsSequenceIds = nil
+	return nil
+}
+
 // getUsedSequenceNames returns the name of the sequence passed to
 // a call to nextval in the given expression, or nil if there is
 // no call to nextval.
@@ -314,6 +314,15 @@ func (r *SessionRegistry) deregister(id ClusterWideID) {
 	r.Unlock()
 }
 
+// isRegistered returns true if the session with the given ID is
+// registered.
+func (r *SessionRegistry) isRegistered(id ClusterWideID) bool {
+	r.Lock()
+	defer r.Unlock()
+	_, ok := r.store[id]
+	return ok
+}
+
 type registrySession interface {
 	user() string
 	cancelQuery(queryID ClusterWideID) bool
@@ -669,7 +678,7 @@ func (s *Session) extendedEvalCtx(
 		statusServer = s.execCfg.StatusServer
 	}
 
-	scInterface := newSchemaInterface(&s.tables, s.execCfg.VirtualSchemas)
+	scInterface := newSchemaInterface(&s.tables, s.execCfg.VirtualSchemas, "" /* tempSchemaName */)
 
 	return extendedEvalContext{
 		EvalContext: tree.EvalContext{
@@ -700,7 +709,9 @@ func (s *Session) extendedEvalCtx(
 	}
 }
 
-func newSchemaInterface(tables *TableCollection, vt VirtualTabler) *schemaInterface {
+func newSchemaInterface(
+	tables *TableCollection, vt VirtualTabler, tempSchemaName string,
+) *schemaInterface {
 	sc := &schemaInterface{
 		physical: &CachedPhysicalAccessor{
 			SchemaAccessor: UncachedPhysicalAccessor{},
@@ -710,6 +721,7 @@ func newSchemaInterface(tables *TableCollection, vt VirtualTabler) *schemaInterf
 	sc.logical = &LogicalSchemaAccessor{
 		SchemaAccessor: sc.physical,
 		vt:             vt,
+		tempSchemaName: tempSchemaName,
 	}
 	return sc
 }
@@ -1181,6 +1193,8 @@ func (ts *txnState) resetStateAndTxn(state TxnStateEnum) {
 // the current SQL txn. This needs to be called before resetForNewSQLTxn() is
 // called for starting another SQL txn.
 func (ts *txnState) finishSQLTxn(s *Session) {
+	// The effects of SET LOCAL end with the transaction.
+	s.dataMutator.revertLocalOverrides()
 	ts.mon.Stop(ts.Ctx)
 	if ts.cancel != nil {
 		ts.cancel()
@@ -1897,6 +1911,45 @@ type sessionDataMutator struct {
 	// applicationNamedChanged, if set, is called when the "application name"
 	// variable is updated.
 	applicationNameChanged func(newName string)
+	// sessionLevelData, if set, holds the values that the session variables
+	// changed with SET LOCAL revert to when the current transaction ends.
+	sessionLevelData *sessiondata.SessionData
+}
+
+// beginLocalOverride is called before a session variable is changed with SET
+// LOCAL. It saves the session-level values of the session variables, unless
+// an earlier SET LOCAL in the same transaction has already done so.
+func (m *sessionDataMutator) beginLocalOverride() {
+	if m.sessionLevelData == nil {
+		m.sessionLevelData = m.data.Clone()
+	}
+}
+
+// sessionLevelMutator returns a mutator for the session-level values saved by
+// beginLocalOverride, or nil if no SET LOCAL is in effect. Changes made through
+// it outlive the current transaction.
+func (m *sessionDataMutator) sessionLevelMutator() *sessionDataMutator {
+	if m.sessionLevelData == nil {
+		return nil
+	}
+	sm := *m
+	sm.data = m.sessionLevelData
+	sm.sessionLevelData = nil
+	sm.applicationNameChanged = nil
+	return &sm
+}
+
+// revertLocalOverrides undoes the effects of the SET LOCAL statements of the
+// transaction that just ended.
+func (m *sessionDataMutator) revertLocalOverrides() {
+	if m.sessionLevelData == nil {
+		return
+	}
+	if appName := m.sessionLevelData.ApplicationName(); appName != m.data.ApplicationName() {
+		m.SetApplicationName(appName)
+	}
+	m.data.CopyFrom(m.sessionLevelData)
+	m.sessionLevelData = nil
 }
 
 // SetApplicationName sets the application name.
@@ -1944,7 +1997,18 @@ func (m *sessionDataMutator) SetSafeUpdates(val bool) {
 }
 
 func (m *sessionDataMutator) SetSearchPath(val sessiondata.SearchPath) {
-	m.data.SearchPath = val
+	// The temporary schema outlives changes to search_path.
+	m.data.SearchPath = val.WithTemporarySchemaName(m.data.SearchPath.GetTemporarySchemaName())
+}
+
+// SetTemporarySchemaName records the name of the session's temporary
+// schema once it has been created.
+func (m *sessionDataMutator) SetTemporarySchemaName(name string) {
+	m.data.SearchPath = m.data.SearchPath.WithTemporarySchemaName(name)
+	if m.sessionLevelData != nil {
+		// The temporary schema outlives SET LOCAL too.
+		m.sessionLevelData.SearchPath = m.sessionLevelData.SearchPath.WithTemporarySchemaName(name)
+	}
 }
 
 func (m *sessionDataMutator) SetLocation(loc *time.Location) {
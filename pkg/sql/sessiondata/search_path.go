@@ -21,28 +21,52 @@ import (
 // PgCatalogName is the name of the pg_catalog system database.
 const PgCatalogName = "pg_catalog"
 
+// PgTempSchemaName is the alias for the temporary schema of the
+// current session.
+const PgTempSchemaName = "pg_temp"
+
 // SearchPath represents a list of namespaces to search builtins in.
 // The names must be normalized (as per Name.Normalize) already.
 type SearchPath struct {
 	paths             []string
 	containsPgCatalog bool
+	containsPgTemp    bool
+	tempSchemaName    string
 }
 
 // MakeSearchPath returns a new SearchPath struct.
 func MakeSearchPath(paths []string) SearchPath {
 	containsPgCatalog := false
+	containsPgTemp := false
 	for _, e := range paths {
-		if e == PgCatalogName {
+		switch e {
+		case PgCatalogName:
 			containsPgCatalog = true
-			break
+		case PgTempSchemaName:
+			containsPgTemp = true
 		}
 	}
 	return SearchPath{
 		paths:             paths,
 		containsPgCatalog: containsPgCatalog,
+		containsPgTemp:    containsPgTemp,
 	}
 }
 
+// WithTemporarySchemaName returns a copy of the search path in which
+// pg_temp designates the given temporary schema.
+func (s SearchPath) WithTemporarySchemaName(tempSchemaName string) SearchPath {
+	s.tempSchemaName = tempSchemaName
+	return s
+}
+
+// GetTemporarySchemaName returns the name of the temporary schema
+// designated by pg_temp, or an empty string if there is none (for
+// example in internal sessions).
+func (s SearchPath) GetTemporarySchemaName() string {
+	return s.tempSchemaName
+}
+
 // FirstSpecified returns true and the first element if the list of
 // specified items is non-empty, or false and an empty string
 // otherwise.  Used by current_schema().
@@ -79,6 +103,27 @@ func (s SearchPath) Iter() func() (next string, ok bool) {
 	}
 }
 
+// IterRelations is the same as Iter, but also includes the implicit
+// pg_temp schema, which is only searched for relations.
+// "Likewise, the current session's temporary-table schema, pg_temp_nnn, is
+// always searched if it exists. It can be explicitly listed in the path by
+// using the alias pg_temp. If it is not listed in the path then it is
+// searched first (even before pg_catalog). However, the temporary schema is
+// only searched for relation (table, view, sequence, etc) and data type
+// names. It is never searched for function or operator names."
+// - https://www.postgresql.org/docs/10/static/runtime-config-client.html
+func (s SearchPath) IterRelations() func() (next string, ok bool) {
+	implicitPgTemp := s.tempSchemaName != "" && !s.containsPgTemp
+	iter := s.Iter()
+	return func() (next string, ok bool) {
+		if implicitPgTemp {
+			implicitPgTemp = false
+			return PgTempSchemaName, true
+		}
+		return iter()
+	}
+}
+
 // IterWithoutImplicitPGCatalog is the same as Iter, but does not include the implicit pg_catalog.
 func (s SearchPath) IterWithoutImplicitPGCatalog() func() (next string, ok bool) {
 	i := 0
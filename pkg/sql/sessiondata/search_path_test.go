@@ -58,3 +58,33 @@ func TestImpliedSearchPath(t *testing.T) {
 		})
 	}
 }
+
+func TestImpliedSearchPathForRelations(t *testing.T) {
+	testCases := []struct {
+		explicitSearchPath []string
+		tempSchemaName     string
+		expectedSearchPath []string
+	}{
+		{[]string{`foobar`}, ``, []string{`pg_catalog`, `foobar`}},
+		{[]string{`foobar`}, `pg_temp_1_2`, []string{`pg_temp`, `pg_catalog`, `foobar`}},
+		{[]string{`foobar`, `pg_temp`}, `pg_temp_1_2`, []string{`pg_catalog`, `foobar`, `pg_temp`}},
+		{[]string{`pg_catalog`, `pg_temp`, `foobar`}, `pg_temp_1_2`, []string{`pg_catalog`, `pg_temp`, `foobar`}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(strings.Join(tc.explicitSearchPath, ",")+"/"+tc.tempSchemaName, func(t *testing.T) {
+			searchPath := MakeSearchPath(tc.explicitSearchPath).WithTemporarySchemaName(tc.tempSchemaName)
+			actualSearchPath := make([]string, 0)
+			iter := searchPath.IterRelations()
+			for p, ok := iter(); ok; p, ok = iter() {
+				actualSearchPath = append(actualSearchPath, p)
+			}
+			if !reflect.DeepEqual(tc.expectedSearchPath, actualSearchPath) {
+				t.Errorf(`Expected search path to be %#v, but was %#v.`, tc.expectedSearchPath, actualSearchPath)
+			}
+			if name := searchPath.GetTemporarySchemaName(); name != tc.tempSchemaName {
+				t.Errorf(`Expected temporary schema name to be %q, but was %q.`, tc.tempSchemaName, name)
+			}
+		})
+	}
+}
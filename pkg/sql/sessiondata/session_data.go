@@ -92,6 +92,32 @@ func (s *SessionData) SetApplicationName(name string) {
 	s.mu.Unlock()
 }
 
+// CopyFrom sets the session parameters to those of other.
+func (s *SessionData) CopyFrom(other *SessionData) {
+	s.Database = other.Database
+	s.DefaultIsolationLevel = other.DefaultIsolationLevel
+	s.DefaultReadOnly = other.DefaultReadOnly
+	s.DistSQLMode = other.DistSQLMode
+	s.LookupJoinEnabled = other.LookupJoinEnabled
+	s.Location = other.Location
+	s.OptimizerMode = other.OptimizerMode
+	s.SearchPath = other.SearchPath
+	s.StmtTimeout = other.StmtTimeout
+	s.User = other.User
+	s.SafeUpdates = other.SafeUpdates
+	s.SequenceState = other.SequenceState
+	s.RemoteAddr = other.RemoteAddr
+	s.SetApplicationName(other.ApplicationName())
+}
+
+// Clone returns a copy of the session data. The copy shares the
+// SequenceState of the original.
+func (s *SessionData) Clone() *SessionData {
+	c := &SessionData{}
+	c.CopyFrom(s)
+	return c
+}
+
 // DistSQLExecMode controls if and when the Executor uses DistSQL.
 type DistSQLExecMode int64
 
@@ -0,0 +1,68 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sessiondata
+
+import (
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/storage/engine/enginepb"
+)
+
+func TestCopyFrom(t *testing.T) {
+	s := &SessionData{
+		Database:              "db",
+		DefaultIsolationLevel: enginepb.SNAPSHOT,
+		DefaultReadOnly:       true,
+		DistSQLMode:           DistSQLAlways,
+		LookupJoinEnabled:     true,
+		Location:              time.FixedZone("foo", 3600),
+		OptimizerMode:         OptimizerAlways,
+		SearchPath:            MakeSearchPath([]string{"a", "b"}),
+		StmtTimeout:           time.Second,
+		User:                  "user",
+		SafeUpdates:           true,
+		SequenceState:         NewSequenceState(),
+		RemoteAddr:            &net.TCPAddr{Port: 26257},
+	}
+	s.SetApplicationName("app")
+
+	c := s.Clone()
+
+	// Make sure that every exported field has been set above, so that this
+	// test catches the fields that CopyFrom forgets.
+	v, cv := reflect.ValueOf(s).Elem(), reflect.ValueOf(c).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Type().Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		if reflect.DeepEqual(v.Field(i).Interface(), reflect.Zero(f.Type).Interface()) {
+			t.Errorf("field %s is not set by the test", f.Name)
+		}
+		if !reflect.DeepEqual(v.Field(i).Interface(), cv.Field(i).Interface()) {
+			t.Errorf("field %s was not copied: expected %v, got %v",
+				f.Name, v.Field(i).Interface(), cv.Field(i).Interface())
+		}
+	}
+	if c.ApplicationName() != "app" {
+		t.Errorf("application name was not copied: got %q", c.ApplicationName())
+	}
+	if c.SequenceState != s.SequenceState {
+		t.Errorf("expected the sequence state to be shared")
+	}
+}
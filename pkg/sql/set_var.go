@@ -29,11 +29,24 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 )
 
-// setVarNode represents a SET SESSION statement.
+// setVarNode represents a SET SESSION or SET LOCAL statement.
 type setVarNode struct {
-	v sessionVar
+	name string
+	v    sessionVar
 	// typedValues == nil means RESET.
 	typedValues []tree.TypedExpr
+	// local is set for SET LOCAL, whose effects last until the end of the
+	// current transaction.
+	local bool
+}
+
+// txnScopedVars are the session variables whose values do not live in the
+// session data but in the current transaction or the session tracing state.
+// SET LOCAL has no additional effect on them.
+var txnScopedVars = map[string]struct{}{
+	`tracing`:               {},
+	`transaction_isolation`: {},
+	`transaction_read_only`: {},
 }
 
 // SetVar sets session variables.
@@ -95,7 +108,13 @@ func (p *planner) SetVar(ctx context.Context, n *tree.SetVar) (planNode, error)
 		}
 	}
 
-	return &setVarNode{v: v, typedValues: typedValues}, nil
+	if n.Local && p.EvalContext().TxnImplicit {
+		// As in PostgreSQL, SET LOCAL outside of a transaction block has no
+		// effect, since the implicit transaction ends with the statement.
+		return newZeroNode(nil /* columns */), nil
+	}
+
+	return &setVarNode{name: name, v: v, typedValues: typedValues, local: n.Local}, nil
 }
 
 func (n *setVarNode) startExec(params runParams) error {
@@ -107,11 +126,29 @@ func (n *setVarNode) startExec(params runParams) error {
 			}
 			n.typedValues[i] = d
 		}
-		return n.v.Set(
-			params.ctx, params.p.sessionDataMutator,
-			params.extendedEvalCtx, n.typedValues)
 	}
-	return n.v.Reset(params.p.sessionDataMutator)
+
+	m := params.p.sessionDataMutator
+	if _, ok := txnScopedVars[n.name]; !ok {
+		if n.local {
+			m.beginLocalOverride()
+		} else if sm := m.sessionLevelMutator(); sm != nil {
+			// A SET SESSION that follows a SET LOCAL in the same transaction
+			// outlives the transaction.
+			if err := n.apply(params, sm); err != nil {
+				return err
+			}
+		}
+	}
+	return n.apply(params, m)
+}
+
+// apply performs the SET or RESET through the given mutator.
+func (n *setVarNode) apply(params runParams, m *sessionDataMutator) error {
+	if n.typedValues != nil {
+		return n.v.Set(params.ctx, m, params.extendedEvalCtx, n.typedValues)
+	}
+	return n.v.Reset(m)
 }
 
 func (n *setVarNode) Next(_ runParams) (bool, error) { return false, nil }
@@ -153,17 +190,9 @@ func setTimeZone(
 	var offset int64
 	switch v := tree.UnwrapDatum(&evalCtx.EvalContext, d).(type) {
 	case *tree.DString:
-		location := string(*v)
-		loc, err = timeutil.LoadLocation(location)
+		loc, err = timeutil.TimeZoneStringToLocation(string(*v))
 		if err != nil {
-			var err1 error
-			loc, err1 = timeutil.LoadLocation(strings.ToUpper(location))
-			if err1 != nil {
-				loc, err1 = timeutil.LoadLocation(strings.ToTitle(location))
-				if err1 != nil {
-					return fmt.Errorf("cannot find time zone %q: %v", location, err)
-				}
-			}
+			return err
 		}
 
 	case *tree.DInterval:
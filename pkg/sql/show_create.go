@@ -96,14 +96,23 @@ func (p *planner) showCreateView(
 	ctx context.Context, tn *tree.Name, desc *sqlbase.TableDescriptor,
 ) (string, error) {
 	f := tree.NewFmtCtxWithBuf(tree.FmtSimple)
-	f.WriteString("CREATE VIEW ")
+	f.WriteString("CREATE ")
+	if desc.MaterializedView() {
+		f.WriteString("MATERIALIZED ")
+	}
+	f.WriteString("VIEW ")
 	f.FormatNode(tn)
 	f.WriteString(" (")
+	sep := ""
 	for i := range desc.Columns {
-		if i > 0 {
-			f.WriteString(", ")
+		// Skip the row ID column that stores the results of a
+		// materialized view.
+		if desc.Columns[i].Hidden {
+			continue
 		}
+		f.WriteString(sep)
 		f.FormatNameP(&desc.Columns[i].Name)
+		sep = ", "
 	}
 	f.WriteString(") AS ")
 	f.WriteString(desc.ViewQuery)
@@ -144,6 +153,10 @@ func (p *planner) printForeignKeyConstraint(
 	formatQuoteNames(buf, fkIdx.ColumnNames...)
 	buf.WriteByte(')')
 	idx.ColNamesString()
+	if fk.Match != sqlbase.ForeignKeyReference_SIMPLE {
+		buf.WriteString(" MATCH ")
+		buf.WriteString(fk.Match.String())
+	}
 	if fk.OnDelete != sqlbase.ForeignKeyReference_NO_ACTION {
 		buf.WriteString(" ON DELETE ")
 		buf.WriteString(fk.OnDelete.String())
@@ -189,7 +202,11 @@ func (p *planner) showCreateTable(
 	a := &sqlbase.DatumAlloc{}
 
 	f := tree.NewFmtCtxWithBuf(tree.FmtSimple)
-	f.WriteString("CREATE TABLE ")
+	f.WriteString("CREATE ")
+	if desc.Temporary {
+		f.WriteString("TEMP ")
+	}
+	f.WriteString("TABLE ")
 	f.FormatNode(tn)
 	f.WriteString(" (")
 	primaryKeyIsOnVisibleColumn := false
@@ -225,7 +242,7 @@ func (p *planner) showCreateTable(
 		if idx.ID != desc.PrimaryIndex.ID {
 			// Showing the primary index is handled above.
 			f.WriteString(",\n\t")
-			f.WriteString(idx.SQLString(""))
+			f.WriteString(idx.SQLStringWithExprs("", indexColumnExprs(desc, idx)))
 			// Showing the INTERLEAVE and PARTITION BY for the primary index are
 			// handled last.
 			if err := p.showCreateInterleave(ctx, idx, f.Buffer, dbPrefix, lCtx); err != nil {
@@ -242,7 +259,8 @@ func (p *planner) showCreateTable(
 	for _, fam := range desc.Families {
 		activeColumnNames := make([]string, 0, len(fam.ColumnNames))
 		for i, colID := range fam.ColumnIDs {
-			if _, err := desc.FindActiveColumnByID(colID); err == nil {
+			// The columns of index expressions are created by the indexes.
+			if col, err := desc.FindActiveColumnByID(colID); err == nil && !isIndexExprColumn(col) {
 				activeColumnNames = append(activeColumnNames, fam.ColumnNames[i])
 			}
 		}
@@ -254,6 +272,11 @@ func (p *planner) showCreateTable(
 	}
 
 	for _, e := range desc.Checks {
+		if e.IsNonNullConstraint {
+			// The constraint is shown as NOT NULL on the column once it has
+			// been validated.
+			continue
+		}
 		f.WriteString(",\n\t")
 		if len(e.Name) > 0 {
 			f.WriteString("CONSTRAINT ")
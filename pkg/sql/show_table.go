@@ -55,12 +55,20 @@ func (p *planner) showTableDetails(
 		return nil, err
 	}
 
+	catalogName, schemaName := tn.CatalogName, tn.SchemaName
+	if desc.Temporary {
+		// The virtual tables list temporary tables under the database that
+		// stores the session's temporary schema.
+		catalogName = tree.Name(p.ExtendedEvalContext().TemporarySchemaName)
+		schemaName = tree.PublicSchemaName
+	}
+
 	fullQuery := fmt.Sprintf(query,
-		lex.EscapeSQLString(tn.Catalog()),
+		lex.EscapeSQLString(string(catalogName)),
 		lex.EscapeSQLString(tn.Table()),
 		lex.EscapeSQLString(tn.String()),
-		tn.CatalogName.String(), // note: CatalogName.String() != Catalog()
-		lex.EscapeSQLString(tn.Schema()),
+		catalogName.String(), // note: CatalogName.String() != Catalog()
+		lex.EscapeSQLString(string(schemaName)),
 	)
 
 	// log.VEventf(ctx, 2, "using table detail query: %s", fullQuery)
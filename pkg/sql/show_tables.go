@@ -20,6 +20,7 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/sql/lex"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 )
 
@@ -40,6 +41,21 @@ func (p *planner) ShowTables(ctx context.Context, n *tree.ShowTables) (planNode,
 		return nil, sqlbase.NewInvalidWildcardError(tree.ErrString(&n.TableNamePrefix))
 	}
 
+	if n.Schema() == sessiondata.PgTempSchemaName {
+		// Temporary tables are listed under the database that stores the
+		// session's temporary schema, which may not exist yet.
+		const getTemporaryTablesQuery = `
+				SELECT table_name AS "Table"
+				FROM "".information_schema.tables
+				WHERE table_catalog = %[1]s AND table_schema = 'public'
+				ORDER BY table_name`
+
+		return p.delegateQuery(ctx, "SHOW TABLES",
+			fmt.Sprintf(getTemporaryTablesQuery,
+				lex.EscapeSQLString(p.ExtendedEvalContext().TemporarySchemaName)),
+			func(_ context.Context) error { return nil }, nil)
+	}
+
 	const getTablesQuery = `
 				SELECT table_name AS "Table"
 				FROM %[1]s.information_schema.tables
@@ -191,6 +191,37 @@ func (c *cascader) clear(ctx context.Context) {
 	}
 }
 
+// checkMatchFullCascade returns an error if the values which are about to be
+// cascaded into the columns of a MATCH FULL foreign key are neither all NULL
+// nor all non-NULL.
+func checkMatchFullCascade(
+	referencedIndex *IndexDescriptor,
+	referencingIndex *IndexDescriptor,
+	colIDtoRowIndex map[ColumnID]int,
+	row tree.Datums,
+) error {
+	prefixLen := len(referencingIndex.ColumnIDs)
+	if len(referencedIndex.ColumnIDs) < prefixLen {
+		prefixLen = len(referencedIndex.ColumnIDs)
+	}
+	nulls, nonNulls := false, false
+	for _, colID := range referencingIndex.ColumnIDs[:prefixLen] {
+		rowIndex, ok := colIDtoRowIndex[colID]
+		if !ok {
+			continue
+		}
+		if row[rowIndex] == tree.DNull {
+			nulls = true
+		} else {
+			nonNulls = true
+		}
+	}
+	if nulls && nonNulls {
+		return NewMatchFullViolationError(referencingIndex.ColumnNames[:prefixLen])
+	}
+	return nil
+}
+
 // spanForIndexValues creates a span against an index to extract the primary
 // keys needed for cascading.
 func spanForIndexValues(
@@ -333,6 +364,7 @@ func (c *cascader) addIndexPKRowFetcher(
 			IsSecondaryIndex: isSecondary,
 			Cols:             colDesc,
 			ValNeededForCol:  valNeededForCol,
+			EvalCtx:          c.evalCtx,
 		},
 	); err != nil {
 		return RowFetcher{}, err
@@ -381,6 +413,7 @@ func (c *cascader) addRowDeleter(table *TableDescriptor) (RowDeleter, RowFetcher
 		IsSecondaryIndex: false,
 		Cols:             rowDeleter.FetchCols,
 		ValNeededForCol:  valNeededForCol,
+		EvalCtx:          c.evalCtx,
 	}
 	var rowFetcher RowFetcher
 	if err := rowFetcher.Init(
@@ -440,6 +473,7 @@ func (c *cascader) addRowUpdater(table *TableDescriptor) (RowUpdater, RowFetcher
 		IsSecondaryIndex: false,
 		Cols:             rowUpdater.FetchCols,
 		ValNeededForCol:  valNeededForCol,
+		EvalCtx:          c.evalCtx,
 	}
 	var rowFetcher RowFetcher
 	if err := rowFetcher.Init(
@@ -832,6 +866,16 @@ func (c *cascader) updateRows(
 					}
 				}
 
+				// A MATCH FULL foreign key cannot be cascaded into a mix of null and
+				// nonnull values.
+				if referencingIndex.ForeignKey.Match == ForeignKeyReference_FULL {
+					if err := checkMatchFullCascade(
+						referencedIndex, referencingIndex, rowUpdater.updateColIDtoRowIndex, updateRow,
+					); err != nil {
+						return nil, nil, nil, 0, err
+					}
+				}
+
 				// Is there something to update?  If not, skip it.
 				if !rowToUpdate.IsDistinctFrom(c.evalCtx, updateRow) {
 					continue
@@ -28,6 +28,7 @@ import (
 // CheckHelper validates check constraints on rows, on INSERT and UPDATE.
 type CheckHelper struct {
 	Exprs        []tree.TypedExpr
+	notNullCols  []string
 	cols         []ColumnDescriptor
 	sourceInfo   *DataSourceInfo
 	ivarHelper   *tree.IndexedVarHelper
@@ -64,9 +65,17 @@ func (c *CheckHelper) Init(
 	)
 
 	c.Exprs = make([]tree.TypedExpr, len(tableDesc.Checks))
+	c.notNullCols = make([]string, len(tableDesc.Checks))
 	exprStrings := make([]string, len(tableDesc.Checks))
 	for i, check := range tableDesc.Checks {
 		exprStrings[i] = check.Expr
+		if check.IsNonNullConstraint && len(check.ColumnIDs) == 1 {
+			col, err := tableDesc.FindColumnByID(check.ColumnIDs[0])
+			if err != nil {
+				return err
+			}
+			c.notNullCols[i] = col.Name
+		}
 	}
 	exprs, err := parser.ParseExprs(exprStrings)
 	if err != nil {
@@ -142,12 +151,17 @@ func (c *CheckHelper) IndexedVarNodeFormatter(idx int) tree.NodeFormatter {
 func (c *CheckHelper) Check(ctx *tree.EvalContext) error {
 	ctx.PushIVarContainer(c)
 	defer func() { ctx.PopIVarContainer() }()
-	for _, expr := range c.Exprs {
+	for i, expr := range c.Exprs {
 		if d, err := expr.Eval(ctx); err != nil {
 			return err
 		} else if res, err := tree.GetBool(d); err != nil {
 			return err
 		} else if !res && d != tree.DNull {
+			if c.notNullCols[i] != "" {
+				// The check enforces a NOT NULL constraint which is still being
+				// validated; report it as such.
+				return NewNonNullViolationError(c.notNullCols[i])
+			}
 			// Failed to satisfy CHECK constraint.
 			return pgerror.NewErrorf(pgerror.CodeCheckViolationError,
 				"failed to satisfy CHECK constraint (%s)", expr)
@@ -0,0 +1,126 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sqlbase
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/transform"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+)
+
+// MakeComputedExprs returns a slice of the computed expressions for the slice
+// of input column descriptors, or nil if none of the input column descriptors
+// are computed. References to the columns in tableCols are replaced by
+// IndexedVars from ivarHelper, whose container must resolve the type of
+// tableCols[i] for index i.
+// The length of the result slice matches the length of the input column
+// descriptors. For every column that is not computed, a NULL expression is
+// reported.
+//
+// Unlike the name resolution of the planner, this can be used outside of the
+// sql package, e.g. by the column backfiller.
+func MakeComputedExprs(
+	cols []ColumnDescriptor,
+	tableCols []ColumnDescriptor,
+	ivarHelper *tree.IndexedVarHelper,
+	txCtx *transform.ExprTransformContext,
+	evalCtx *tree.EvalContext,
+) ([]tree.TypedExpr, error) {
+	haveComputed := false
+	for _, col := range cols {
+		if col.IsComputed() {
+			haveComputed = true
+			break
+		}
+	}
+	if !haveComputed {
+		return nil, nil
+	}
+
+	exprStrings := make([]string, 0, len(cols))
+	for _, col := range cols {
+		if col.IsComputed() {
+			exprStrings = append(exprStrings, *col.ComputeExpr)
+		}
+	}
+	exprs, err := parser.ParseExprs(exprStrings)
+	if err != nil {
+		return nil, err
+	}
+
+	semaCtx := tree.MakeSemaContext(false /* privileged */)
+	semaCtx.IVarContainer = ivarHelper.Container()
+
+	computedExprs := make([]tree.TypedExpr, 0, len(cols))
+	compExprIdx := 0
+	for _, col := range cols {
+		if !col.IsComputed() {
+			computedExprs = append(computedExprs, tree.DNull)
+			continue
+		}
+		v := columnNameVisitor{cols: tableCols, ivarHelper: ivarHelper}
+		expr, _ := tree.WalkExpr(&v, exprs[compExprIdx])
+		if v.err != nil {
+			return nil, v.err
+		}
+		typedExpr, err := tree.TypeCheck(expr, &semaCtx, col.Type.ToDatumType())
+		if err != nil {
+			return nil, err
+		}
+		if typedExpr, err = txCtx.NormalizeExpr(evalCtx, typedExpr); err != nil {
+			return nil, err
+		}
+		computedExprs = append(computedExprs, typedExpr)
+		compExprIdx++
+	}
+	return computedExprs, nil
+}
+
+// columnNameVisitor replaces column names by the IndexedVars of the
+// corresponding columns.
+type columnNameVisitor struct {
+	cols       []ColumnDescriptor
+	ivarHelper *tree.IndexedVarHelper
+	err        error
+}
+
+var _ tree.Visitor = &columnNameVisitor{}
+
+func (v *columnNameVisitor) VisitPre(expr tree.Expr) (recurse bool, newExpr tree.Expr) {
+	if v.err != nil {
+		return false, expr
+	}
+	switch t := expr.(type) {
+	case *tree.UnresolvedName:
+		vn, err := t.NormalizeVarName()
+		if err != nil {
+			v.err = err
+			return false, expr
+		}
+		return v.VisitPre(vn)
+
+	case *tree.ColumnItem:
+		for i := range v.cols {
+			if v.cols[i].Name == string(t.ColumnName) {
+				return false, v.ivarHelper.IndexedVar(i)
+			}
+		}
+		v.err = NewUndefinedColumnError(string(t.ColumnName))
+		return false, expr
+	}
+	return true, expr
+}
+
+func (*columnNameVisitor) VisitPost(expr tree.Expr) tree.Expr { return expr }
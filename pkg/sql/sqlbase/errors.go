@@ -76,6 +76,14 @@ func NewNonNullViolationError(columnName string) error {
 	return pgerror.NewErrorf(pgerror.CodeNotNullViolationError, "null value in column %q violates not-null constraint", columnName)
 }
 
+// NewMatchFullViolationError creates an error for the values of a MATCH FULL
+// foreign key which are neither all NULL nor all non-NULL.
+func NewMatchFullViolationError(columnNames []string) error {
+	return pgerror.NewErrorf(pgerror.CodeForeignKeyViolationError,
+		"foreign key violation: MATCH FULL does not allow mixing of null and nonnull values in columns %s",
+		columnNames)
+}
+
 // NewUniquenessConstraintViolationError creates an error that represents a
 // violation of a UNIQUE constraint.
 func NewUniquenessConstraintViolationError(index *IndexDescriptor, vals []tree.Datum) error {
@@ -165,6 +173,19 @@ func NewUndefinedRelationError(name tree.NodeFormatter) error {
 		"relation %q does not exist", tree.ErrString(name))
 }
 
+// NewUndefinedFunctionError creates an error that represents a missing
+// user-defined function.
+func NewUndefinedFunctionError(name tree.NodeFormatter) error {
+	return pgerror.NewErrorf(pgerror.CodeUndefinedFunctionError,
+		"function %q does not exist", tree.ErrString(name))
+}
+
+// NewFunctionAlreadyExistsError creates an error for a preexisting function.
+func NewFunctionAlreadyExistsError(name tree.NodeFormatter) error {
+	return pgerror.NewErrorf(pgerror.CodeDuplicateFunctionError,
+		"function %q already exists", tree.ErrString(name))
+}
+
 // NewUndefinedColumnError creates an error that represents a missing database column.
 func NewUndefinedColumnError(name string) error {
 	return pgerror.NewErrorf(pgerror.CodeUndefinedColumnError, "column %q does not exist", name)
@@ -216,6 +237,13 @@ func NewWindowingError(in string) error {
 	return pgerror.NewErrorf(pgerror.CodeWindowingError, "window functions are not allowed in %s", in)
 }
 
+// NewLockNotAvailableError creates an error for a row that cannot be
+// locked without waiting, as requested by NOWAIT.
+func NewLockNotAvailableError(tableName string) error {
+	return pgerror.NewErrorf(pgerror.CodeLockNotAvailableError,
+		"could not obtain lock on row in relation %q", tableName)
+}
+
 // NewStatementCompletionUnknownError creates an error with the corresponding pg
 // code. This is used to inform the client that it's unknown whether a statement
 // succeeded or not. Of particular interest to clients is when this error is
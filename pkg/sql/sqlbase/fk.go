@@ -451,20 +451,24 @@ func checkIdx(
 	row tree.Datums,
 ) error {
 	for i, fk := range fks[idx] {
-		nulls := true
+		nulls, nonNulls := false, false
 		for _, colID := range fk.searchIdx.ColumnIDs[:fk.prefixLen] {
 			found, ok := fk.ids[colID]
 			if !ok {
 				panic(fmt.Sprintf("fk ids (%v) missing column id %d", fk.ids, colID))
 			}
-			if row[found] != tree.DNull {
-				nulls = false
-				break
+			if row[found] == tree.DNull {
+				nulls = true
+			} else {
+				nonNulls = true
 			}
 		}
-		if nulls {
+		if !nonNulls {
 			continue
 		}
+		if nulls && fk.match == ForeignKeyReference_FULL {
+			return NewMatchFullViolationError(fk.writeIdx.ColumnNames[:fk.prefixLen])
+		}
 		if err := checker.addCheck(row, &fks[idx][i]); err != nil {
 			return err
 		}
@@ -621,6 +625,7 @@ type baseFKHelper struct {
 	searchPrefix []byte           // prefix of keys in searchIdx
 	ids          map[ColumnID]int // col IDs
 	dir          FKCheck          // direction of check
+	match        ForeignKeyReference_Match
 }
 
 func makeBaseFKHelper(
@@ -632,7 +637,13 @@ func makeBaseFKHelper(
 	alloc *DatumAlloc,
 	dir FKCheck,
 ) (baseFKHelper, error) {
-	b := baseFKHelper{txn: txn, writeIdx: writeIdx, searchTable: otherTables[ref.Table].Table, dir: dir}
+	b := baseFKHelper{
+		txn:         txn,
+		writeIdx:    writeIdx,
+		searchTable: otherTables[ref.Table].Table,
+		dir:         dir,
+		match:       ref.Match,
+	}
 	if b.searchTable == nil {
 		return b, errors.Errorf("referenced table %d not in provided table map %+v", ref.Table, otherTables)
 	}
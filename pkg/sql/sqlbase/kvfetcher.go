@@ -24,6 +24,7 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/internal/client"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/util/encoding"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 )
@@ -150,6 +151,9 @@ type txnKVFetcher struct {
 	// returnRangeInfo, if set, causes the kvFetcher to populate rangeInfos.
 	// See also rowFetcher.returnRangeInfo.
 	returnRangeInfo bool
+	// lockWaitPolicy determines what happens when the scanned rows are
+	// locked by other transactions. See also RowFetcher.lockWaitPolicy.
+	lockWaitPolicy tree.LockingWaitPolicy
 
 	fetchEnd  bool
 	batchIdx  int
@@ -219,6 +223,9 @@ func (f *txnKVFetcher) getBatchSize() int64 {
 // Subsequent batches are larger, up to kvBatchSize.
 //
 // Batch limits can only be used if the spans are ordered.
+//
+// lockWaitPolicy determines what happens when a row is locked by another
+// transaction.
 func makeKVFetcher(
 	txn *client.Txn,
 	spans roachpb.Spans,
@@ -226,6 +233,7 @@ func makeKVFetcher(
 	useBatchLimit bool,
 	firstBatchLimit int64,
 	returnRangeInfo bool,
+	lockWaitPolicy tree.LockingWaitPolicy,
 ) (txnKVFetcher, error) {
 	if firstBatchLimit < 0 || (!useBatchLimit && firstBatchLimit != 0) {
 		return txnKVFetcher{}, errors.Errorf("invalid batch limit %d (useBatchLimit: %t)",
@@ -259,14 +267,29 @@ func makeKVFetcher(
 		useBatchLimit:   useBatchLimit,
 		firstBatchLimit: firstBatchLimit,
 		returnRangeInfo: returnRangeInfo,
+		lockWaitPolicy:  lockWaitPolicy,
 	}, nil
 }
 
+// kvWaitPolicy returns the KV wait policy corresponding to a locking wait
+// policy.
+func kvWaitPolicy(waitPolicy tree.LockingWaitPolicy) roachpb.WaitPolicy {
+	switch waitPolicy {
+	case tree.LockWaitSkip:
+		return roachpb.WaitPolicy_SkipLocked
+	case tree.LockWaitError:
+		return roachpb.WaitPolicy_Error
+	default:
+		return roachpb.WaitPolicy_Block
+	}
+}
+
 // fetch retrieves spans from the kv
 func (f *txnKVFetcher) fetch(ctx context.Context) error {
 	var ba roachpb.BatchRequest
 	ba.Header.MaxSpanRequestKeys = f.getBatchSize()
 	ba.Header.ReturnRangeInfo = f.returnRangeInfo
+	ba.Header.WaitPolicy = kvWaitPolicy(f.lockWaitPolicy)
 	ba.Requests = make([]roachpb.RequestUnion, len(f.spans))
 	if f.reverse {
 		scans := make([]roachpb.ReverseScanRequest, len(f.spans))
@@ -147,6 +147,23 @@ func (p *PrivilegeDescriptor) Grant(user string, privList privilege.List) {
 
 // Revoke removes privileges from this descriptor for a given list of users.
 func (p *PrivilegeDescriptor) Revoke(user string, privList privilege.List) {
+	p.revoke(user, privList, func(k privilege.Kind) bool { return k != privilege.EXECUTE })
+}
+
+// RevokeFunction is like Revoke for the privilege descriptor of a
+// function: a user holding ALL keeps the remaining function privileges.
+func (p *PrivilegeDescriptor) RevokeFunction(user string, privList privilege.List) {
+	functionBits := privilege.FunctionPrivileges.ToBitField()
+	p.revoke(user, privList, func(k privilege.Kind) bool {
+		return isPrivilegeSet(functionBits, k)
+	})
+}
+
+// revoke implements Revoke and RevokeFunction. When the user holds ALL,
+// it is first expanded into the privileges selected by applies.
+func (p *PrivilegeDescriptor) revoke(
+	user string, privList privilege.List, applies func(privilege.Kind) bool,
+) {
 	userPriv, ok := p.findUser(user)
 	if !ok || userPriv.Privileges == 0 {
 		// Removing privileges from a user without privileges is a no-op.
@@ -167,7 +184,7 @@ func (p *PrivilegeDescriptor) Revoke(user string, privList privilege.List) {
 		// all other privileges one.
 		userPriv.Privileges = 0
 		for _, v := range privilege.ByValue {
-			if v != privilege.ALL {
+			if v != privilege.ALL && applies(v) {
 				userPriv.Privileges |= v.Mask()
 			}
 		}
@@ -75,6 +75,10 @@ type tableInfo struct {
 	// Map used to get the index for columns in cols.
 	colIdxMap map[ColumnID]int
 
+	// virtual is set if some of the needed columns are virtual columns which
+	// must be computed (see initVirtualColumns).
+	virtual *virtualColumns
+
 	// One value per column that is part of the key; each value is a column
 	// index (into cols); -1 if we don't need the value for that column.
 	indexColIdx []int
@@ -116,6 +120,9 @@ type RowFetcherTableArgs struct {
 	Cols             []ColumnDescriptor
 	// The indexes (0 to # of columns - 1) of the columns to return.
 	ValNeededForCol util.FastIntSet
+	// EvalCtx is used to compute the values of the needed virtual columns
+	// which are not stored in Index. It can be nil if there are none.
+	EvalCtx *tree.EvalContext
 }
 
 // RowFetcher handles fetching kvs and forming table rows for an
@@ -175,6 +182,15 @@ type RowFetcher struct {
 	// If set, GetRangeInfo() can be used to retrieve the accumulated info.
 	returnRangeInfo bool
 
+	// lockStrength, if set, causes the RowFetcher to keep the key/value
+	// pairs of the last row, which can then be locked until the end of the
+	// transaction with QueueRowLock and LockQueuedRows, as requested by a
+	// FOR UPDATE or FOR SHARE clause. lockWaitPolicy determines the
+	// behavior when the rows are locked by other transactions. See
+	// SetLocking.
+	lockStrength   tree.LockingStrength
+	lockWaitPolicy tree.LockingWaitPolicy
+
 	// traceKV indicates whether or not session tracing is enabled. It is set
 	// when beginning a new scan.
 	traceKV bool
@@ -182,7 +198,11 @@ type RowFetcher struct {
 	// -- Fields updated during a scan --
 
 	kvFetcher      kvFetcher
-	indexKey       []byte // the index key of the current row
+	indexKey       []byte             // the index key of the current row
+	rowKVs         []roachpb.KeyValue // the key/value pairs of the last row, see QueueRowLock
+	lockKVs        []roachpb.KeyValue // the key/value pairs of the rows queued by QueueRowLock
+	lockRowEnds    []int              // the end of each queued row in lockKVs
+	kvBatches      int                // the number of batches of key/value pairs fetched so far
 	prettyValueBuf *bytes.Buffer
 
 	valueColsFound int // how many needed cols we've found so far in the value
@@ -199,6 +219,9 @@ type RowFetcher struct {
 
 	kvs []roachpb.KeyValue
 
+	// bytesRead is the total size of the keys and values fetched so far.
+	bytesRead int64
+
 	// isCheck indicates whether or not we are running checks for k/v
 	// correctness. It is set only during SCRUB commands.
 	isCheck bool
@@ -241,7 +264,11 @@ func (rf *RowFetcher) Init(
 			decodedRow:       make([]tree.Datum, len(tableArgs.Cols)),
 		}
 
-		var err error
+		valNeededForCol, err := table.initVirtualColumns(tableArgs.ValNeededForCol, tableArgs.EvalCtx, alloc)
+		if err != nil {
+			return err
+		}
+
 		if len(tables) > 1 {
 			// We produce references to every signature's reference.
 			equivSignatures, err := TableEquivSignatures(table.desc, table.index)
@@ -275,7 +302,7 @@ func (rf *RowFetcher) Init(
 		// Scan through the entire columns map to see which columns are
 		// required.
 		for col, idx := range table.colIdxMap {
-			if tableArgs.ValNeededForCol.Contains(idx) {
+			if valNeededForCol.Contains(idx) {
 				// The idx-th column is required.
 				table.neededCols.Add(int(col))
 			}
@@ -286,7 +313,7 @@ func (rf *RowFetcher) Init(
 		var indexColumnIDs []ColumnID
 		indexColumnIDs, table.indexColumnDirs = table.index.FullColumnIDs()
 
-		table.neededValueColsByIdx = tableArgs.ValNeededForCol.Copy()
+		table.neededValueColsByIdx = valNeededForCol.Copy()
 		neededIndexCols := 0
 		table.indexColIdx = make([]int, len(indexColumnIDs))
 		for i, id := range indexColumnIDs {
@@ -369,6 +396,106 @@ func (rf *RowFetcher) Init(
 	return nil
 }
 
+// SetLocking configures the row-level locks acquired on the rows read by
+// subsequent scans. It must be called before StartScan.
+func (rf *RowFetcher) SetLocking(
+	strength tree.LockingStrength, waitPolicy tree.LockingWaitPolicy,
+) {
+	rf.lockStrength = strength
+	rf.lockWaitPolicy = waitPolicy
+}
+
+// QueueRowLock queues a lock on the last row returned by NextRow. The
+// queued rows are locked by LockQueuedRows. Only the rows that are
+// actually emitted should be locked, so that a filter above the scan
+// does not lock rows that are discarded. It can only be used if
+// SetLocking was called with a locking strength.
+func (rf *RowFetcher) QueueRowLock() {
+	rf.lockKVs = append(rf.lockKVs, rf.rowKVs...)
+	rf.lockRowEnds = append(rf.lockRowEnds, len(rf.lockKVs))
+}
+
+// LockQueuedRows locks the rows queued by QueueRowLock until the end of
+// the transaction, by writing their current key/value pairs back with a
+// single KV batch, which lays intents on them. Concurrent transactions
+// that want to lock or modify the rows must then wait for this
+// transaction to finish. Since the transaction is serializable, a
+// concurrent modification of a row between the scan and the writes
+// causes the transaction to restart, so the locked rows are never stale.
+//
+// Intents are exclusive, so the rows are locked exclusively whatever the
+// locking strength: FOR SHARE and FOR KEY SHARE prevent concurrent
+// modifications of the rows, like in postgres, but also conflict with
+// each other.
+//
+// LockQueuedRows returns, for each queued row, whether it was locked. A
+// row is only left unlocked if it was locked by another transaction and
+// must be skipped, as requested by SKIP LOCKED.
+func (rf *RowFetcher) LockQueuedRows(ctx context.Context, txn *client.Txn) ([]bool, error) {
+	defer func() {
+		rf.lockKVs = rf.lockKVs[:0]
+		rf.lockRowEnds = rf.lockRowEnds[:0]
+	}()
+	locked := make([]bool, len(rf.lockRowEnds))
+	if len(locked) == 0 {
+		return locked, nil
+	}
+	err := rf.lockKVPairs(ctx, txn, rf.lockKVs)
+	if err == nil {
+		for i := range locked {
+			locked[i] = true
+		}
+		return locked, nil
+	}
+	if _, ok := err.(*roachpb.WriteIntentError); !ok {
+		return nil, err
+	}
+	switch rf.lockWaitPolicy {
+	case tree.LockWaitSkip:
+		// Another transaction locked some of the rows after they were
+		// scanned. Lock the rows one at a time to find out which.
+		start := 0
+		for i, end := range rf.lockRowEnds {
+			err := rf.lockKVPairs(ctx, txn, rf.lockKVs[start:end])
+			start = end
+			if err == nil {
+				locked[i] = true
+			} else if _, ok := err.(*roachpb.WriteIntentError); !ok {
+				return nil, err
+			}
+		}
+		return locked, nil
+	case tree.LockWaitError:
+		return nil, NewLockNotAvailableError(rf.tables[0].desc.Name)
+	}
+	return nil, err
+}
+
+// lockKVPairs lays intents on the given key/value pairs by writing their
+// values back.
+func (rf *RowFetcher) lockKVPairs(
+	ctx context.Context, txn *client.Txn, kvs []roachpb.KeyValue,
+) error {
+	b := txn.NewBatch()
+	if rf.lockWaitPolicy != tree.LockWaitBlock {
+		b.Header.WaitPolicy = roachpb.WaitPolicy_Error
+	}
+	for _, kv := range kvs {
+		b.Put(kv.Key, &roachpb.Value{RawBytes: kv.Value.RawBytes})
+	}
+	if rf.traceKV {
+		log.VEventf(ctx, 2, "locking %d keys", len(kvs))
+	}
+	return txn.Run(ctx, b)
+}
+
+// KVBatchesFetched returns the number of batches of key/value pairs
+// fetched so far. It can be used to lock the rows emitted by a scan
+// with one KV batch per batch of rows fetched.
+func (rf *RowFetcher) KVBatchesFetched() int {
+	return rf.kvBatches
+}
+
 // StartScan initializes and starts the key-value scan. Can be used multiple
 // times.
 func (rf *RowFetcher) StartScan(
@@ -399,7 +526,8 @@ func (rf *RowFetcher) StartScan(
 		firstBatchLimit++
 	}
 
-	f, err := makeKVFetcher(txn, spans, rf.reverse, limitBatches, firstBatchLimit, rf.returnRangeInfo)
+	f, err := makeKVFetcher(txn, spans, rf.reverse, limitBatches, firstBatchLimit,
+		rf.returnRangeInfo, rf.lockWaitPolicy)
 	if err != nil {
 		return err
 	}
@@ -425,11 +553,18 @@ func (rf *RowFetcher) nextKV(ctx context.Context) (ok bool, kv roachpb.KeyValue,
 	}
 	ok, rf.kvs, err = rf.kvFetcher.nextBatch(ctx)
 	if err != nil {
+		if _, isWIErr := err.(*roachpb.WriteIntentError); isWIErr && rf.lockWaitPolicy == tree.LockWaitError {
+			err = NewLockNotAvailableError(rf.tables[0].desc.Name)
+		}
 		return ok, kv, err
 	}
 	if !ok {
 		return false, kv, nil
 	}
+	rf.kvBatches++
+	for i := range rf.kvs {
+		rf.bytesRead += int64(len(rf.kvs[i].Key) + len(rf.kvs[i].Value.RawBytes))
+	}
 	return rf.nextKV(ctx)
 }
 
@@ -905,11 +1040,15 @@ func (rf *RowFetcher) NextRow(
 	// ID to lookup the column and decode the value. All of these values go
 	// into a map keyed by column name. When the index key changes we
 	// output a row containing the current values.
+	rf.rowKVs = rf.rowKVs[:0]
 	for {
 		prettyKey, prettyVal, err := rf.processKV(ctx, rf.kv)
 		if err != nil {
 			return nil, nil, nil, err
 		}
+		if rf.lockStrength != tree.ForNone {
+			rf.rowKVs = append(rf.rowKVs, rf.kv)
+		}
 		if rf.traceKV {
 			log.VEventf(ctx, 2, "fetched: %s -> %s", prettyKey, prettyVal)
 		}
@@ -923,7 +1062,10 @@ func (rf *RowFetcher) NextRow(
 		}
 		if rowDone {
 			err := rf.finalizeRow()
-			return rf.rowReadyTable.row, rf.rowReadyTable.desc, rf.rowReadyTable.index, err
+			if err == nil {
+				err = rf.computeVirtualColumns()
+			}
+			return rf.rowReadyTable.outputRow(), rf.rowReadyTable.desc, rf.rowReadyTable.index, err
 		}
 	}
 }
@@ -956,7 +1098,7 @@ func (rf *RowFetcher) NextRowDecoded(
 		rf.rowReadyTable.decodedRow[i] = encDatum.Datum
 	}
 
-	return rf.rowReadyTable.decodedRow, table, index, nil
+	return rf.rowReadyTable.decodedRow[:len(row)], table, index, nil
 }
 
 // NextRowWithErrors calls NextRow to fetch the next row and also run
@@ -985,15 +1127,15 @@ func (rf *RowFetcher) NextRowWithErrors(ctx context.Context) (EncDatumRow, error
 
 	// Decode the row in-place. The following check datum encoding
 	// functions require that the table.row datums are decoded.
-	for i := range row {
-		if row[i].IsUnset() {
+	for i, encDatum := range rf.rowReadyTable.row {
+		if encDatum.IsUnset() {
 			rf.rowReadyTable.decodedRow[i] = tree.DNull
 			continue
 		}
-		if err := row[i].EnsureDecoded(&rf.rowReadyTable.cols[i].Type, rf.alloc); err != nil {
+		if err := encDatum.EnsureDecoded(&rf.rowReadyTable.cols[i].Type, rf.alloc); err != nil {
 			return nil, err
 		}
-		rf.rowReadyTable.decodedRow[i] = row[i].Datum
+		rf.rowReadyTable.decodedRow[i] = encDatum.Datum
 	}
 
 	if index.ID == table.PrimaryIndex.ID {
@@ -1180,6 +1322,12 @@ func (rf *RowFetcher) Key() roachpb.Key {
 	return rf.kv.Key
 }
 
+// GetBytesRead returns the total size of the keys and values fetched from KV
+// since the RowFetcher was initialized.
+func (rf *RowFetcher) GetBytesRead() int64 {
+	return rf.bytesRead
+}
+
 // GetRangeInfo returns information about the ranges where the rows came from.
 // The RangeInfo's are deduped and not ordered.
 func (rf *RowFetcher) GetRangeInfo() []roachpb.RangeInfo {
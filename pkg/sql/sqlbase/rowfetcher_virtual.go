@@ -0,0 +1,181 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sqlbase
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/transform"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/types"
+	"github.com/cockroachdb/cockroach/pkg/util"
+)
+
+// virtualColumns holds what a RowFetcher needs to compute the values of the
+// needed virtual columns of a table which are not stored in the scanned
+// index. It is the IndexedVarContainer of the expressions of these columns,
+// whose IndexedVars refer to the columns of the table descriptor.
+type virtualColumns struct {
+	// colIdxs are the indexes (into the fetched columns) of the virtual
+	// columns, parallel to exprs.
+	colIdxs []int
+	exprs   []tree.TypedExpr
+	evalCtx *tree.EvalContext
+
+	// descCols are the columns of the table descriptor, and rowIdx maps the
+	// index of a column in descCols to its index in the fetched columns (or
+	// -1 if it isn't fetched).
+	descCols []ColumnDescriptor
+	rowIdx   []int
+
+	// numOutputCols is the number of columns in the rows returned by the
+	// RowFetcher. The columns needed by the virtual columns which were not
+	// requested are fetched after them.
+	numOutputCols int
+
+	row   EncDatumRow
+	cols  []ColumnDescriptor
+	alloc *DatumAlloc
+}
+
+var _ tree.IndexedVarContainer = &virtualColumns{}
+
+// IndexedVarEval is part of the tree.IndexedVarContainer interface.
+func (v *virtualColumns) IndexedVarEval(idx int, ctx *tree.EvalContext) (tree.Datum, error) {
+	rowIdx := v.rowIdx[idx]
+	if v.row[rowIdx].IsUnset() {
+		return tree.DNull, nil
+	}
+	if err := v.row[rowIdx].EnsureDecoded(&v.cols[rowIdx].Type, v.alloc); err != nil {
+		return nil, err
+	}
+	return v.row[rowIdx].Datum, nil
+}
+
+// IndexedVarResolvedType is part of the tree.IndexedVarContainer interface.
+func (v *virtualColumns) IndexedVarResolvedType(idx int) types.T {
+	return v.descCols[idx].Type.ToDatumType()
+}
+
+// IndexedVarNodeFormatter is part of the tree.IndexedVarContainer interface.
+func (v *virtualColumns) IndexedVarNodeFormatter(idx int) tree.NodeFormatter {
+	n := tree.Name(v.descCols[idx].Name)
+	return &n
+}
+
+// initVirtualColumns prepares the computation of the virtual columns which
+// are among the needed columns of the table but not stored in the scanned
+// index. The values of such a column are not read from the key-value pairs,
+// but computed from the columns it references once the rest of the row has
+// been decoded; the referenced columns which were not requested are added to
+// the fetched columns. It returns the set of the indexes of the columns to
+// read from the key-value pairs.
+func (table *tableInfo) initVirtualColumns(
+	valNeededForCol util.FastIntSet, evalCtx *tree.EvalContext, alloc *DatumAlloc,
+) (util.FastIntSet, error) {
+	var colIdxs []int
+	var virtualCols []ColumnDescriptor
+	for i := range table.cols {
+		col := &table.cols[i]
+		if col.Virtual && valNeededForCol.Contains(i) && !table.index.ContainsColumnID(col.ID) {
+			colIdxs = append(colIdxs, i)
+			virtualCols = append(virtualCols, *col)
+		}
+	}
+	if len(virtualCols) == 0 {
+		return valNeededForCol, nil
+	}
+	if evalCtx == nil {
+		return util.FastIntSet{}, errors.Errorf(
+			"cannot compute virtual column %q without an evaluation context", virtualCols[0].Name)
+	}
+
+	v := &virtualColumns{
+		colIdxs:       colIdxs,
+		evalCtx:       evalCtx,
+		descCols:      table.desc.Columns,
+		rowIdx:        make([]int, len(table.desc.Columns)),
+		numOutputCols: len(table.cols),
+		alloc:         alloc,
+	}
+	ivarHelper := tree.MakeIndexedVarHelper(v, len(v.descCols))
+	var txCtx transform.ExprTransformContext
+	exprs, err := MakeComputedExprs(virtualCols, v.descCols, &ivarHelper, &txCtx, evalCtx)
+	if err != nil {
+		return util.FastIntSet{}, err
+	}
+	v.exprs = exprs
+
+	valNeededForCol = valNeededForCol.Copy()
+	for _, idx := range colIdxs {
+		valNeededForCol.Remove(idx)
+	}
+	// Don't modify the columns and map provided by the caller.
+	table.cols = table.cols[:len(table.cols):len(table.cols)]
+	colIdxMap := make(map[ColumnID]int, len(table.colIdxMap))
+	for id, idx := range table.colIdxMap {
+		colIdxMap[id] = idx
+	}
+	table.colIdxMap = colIdxMap
+	for i := range v.descCols {
+		v.rowIdx[i] = -1
+		if !ivarHelper.IndexedVarUsed(i) {
+			continue
+		}
+		idx, ok := table.colIdxMap[v.descCols[i].ID]
+		if !ok {
+			idx = len(table.cols)
+			table.cols = append(table.cols, v.descCols[i])
+			table.colIdxMap[v.descCols[i].ID] = idx
+		}
+		v.rowIdx[i] = idx
+		valNeededForCol.Add(idx)
+	}
+	table.row = make(EncDatumRow, len(table.cols))
+	table.decodedRow = make(tree.Datums, len(table.cols))
+	v.row, v.cols = table.row, table.cols
+	table.virtual = v
+	return valNeededForCol, nil
+}
+
+// computeVirtualColumns computes the values of the virtual columns of the
+// row which was just decoded.
+func (rf *RowFetcher) computeVirtualColumns() error {
+	table := rf.rowReadyTable
+	v := table.virtual
+	if v == nil {
+		return nil
+	}
+	v.evalCtx.PushIVarContainer(v)
+	defer v.evalCtx.PopIVarContainer()
+	for i, expr := range v.exprs {
+		d, err := expr.Eval(v.evalCtx)
+		if err != nil {
+			return err
+		}
+		idx := v.colIdxs[i]
+		table.row[idx] = DatumToEncDatum(table.cols[idx].Type, d)
+	}
+	return nil
+}
+
+// outputRow returns the row to return to the caller of the RowFetcher, which
+// excludes the columns fetched only to compute the virtual columns.
+func (table *tableInfo) outputRow() EncDatumRow {
+	if table.virtual != nil {
+		return table.row[:table.virtual.numOutputCols]
+	}
+	return table.row
+}
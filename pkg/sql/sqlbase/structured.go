@@ -175,6 +175,25 @@ func GetTableDescFromID(ctx context.Context, txn *client.Txn, id ID) (*TableDesc
 	return table, nil
 }
 
+// GetFunctionDescFromID retrieves the function descriptor for the
+// function ID passed in using an existing txn. Returns an error if the
+// descriptor doesn't exist or if it exists and is not a function.
+func GetFunctionDescFromID(
+	ctx context.Context, txn *client.Txn, id ID,
+) (*FunctionDescriptor, error) {
+	desc := &Descriptor{}
+	descKey := MakeDescMetadataKey(id)
+
+	if err := txn.GetProto(ctx, descKey, desc); err != nil {
+		return nil, err
+	}
+	function := desc.GetFunction()
+	if function == nil {
+		return nil, ErrDescriptorNotFound
+	}
+	return function, nil
+}
+
 // RunOverAllColumns applies its argument fn to each of the column IDs in desc.
 // If there is an error, that error is returned immediately.
 func (desc *IndexDescriptor) RunOverAllColumns(fn func(id ColumnID) error) error {
@@ -288,11 +307,24 @@ func (desc *IndexDescriptor) FullColumnIDs() ([]ColumnID, []encoding.Direction)
 // ColNamesFormat writes a string describing the column names and directions
 // in this index to the given buffer.
 func (desc *IndexDescriptor) ColNamesFormat(ctx *tree.FmtCtxWithBuf) {
+	desc.colNamesFormat(ctx, nil /* exprs */)
+}
+
+func (desc *IndexDescriptor) colNamesFormat(ctx *tree.FmtCtxWithBuf, exprs map[ColumnID]string) {
 	for i := range desc.ColumnNames {
 		if i > 0 {
 			ctx.WriteString(", ")
 		}
-		ctx.FormatNameP(&desc.ColumnNames[i])
+		formatted := false
+		if e, ok := exprs[desc.ColumnIDs[i]]; ok {
+			if expr, err := parser.ParseExpr(e); err == nil {
+				ctx.FormatNode(&tree.IndexElem{Expr: expr})
+				formatted = true
+			}
+		}
+		if !formatted {
+			ctx.FormatNameP(&desc.ColumnNames[i])
+		}
 		if desc.Type != IndexDescriptor_INVERTED {
 			ctx.WriteByte(' ')
 			ctx.WriteString(desc.ColumnDirections[i].String())
@@ -311,6 +343,13 @@ func (desc *IndexDescriptor) ColNamesString() string {
 // SQLString returns the SQL string describing this index. If non-empty,
 // "ON tableName" is included in the output in the correct place.
 func (desc *IndexDescriptor) SQLString(tableName string) string {
+	return desc.SQLStringWithExprs(tableName, nil /* exprs */)
+}
+
+// SQLStringWithExprs is like SQLString, but the index columns found in exprs,
+// which store the values of index expressions, are described by their
+// expression instead of their name.
+func (desc *IndexDescriptor) SQLStringWithExprs(tableName string, exprs map[ColumnID]string) string {
 	f := tree.NewFmtCtxWithBuf(tree.FmtSimple)
 	if desc.Unique {
 		f.WriteString("UNIQUE ")
@@ -325,7 +364,7 @@ func (desc *IndexDescriptor) SQLString(tableName string) string {
 	}
 	f.FormatNameP(&desc.Name)
 	f.WriteString(" (")
-	desc.ColNamesFormat(f)
+	desc.colNamesFormat(f, exprs)
 	f.WriteByte(')')
 
 	if len(desc.StoreColumnNames) > 0 {
@@ -374,6 +413,12 @@ func (desc *TableDescriptor) IsView() bool {
 	return desc.ViewQuery != ""
 }
 
+// MaterializedView returns true if the TableDescriptor describes a
+// materialized view, whose results are stored like the rows of a table.
+func (desc *TableDescriptor) MaterializedView() bool {
+	return desc.IsMaterializedView
+}
+
 // IsSequence returns true if the TableDescriptor actually describes a
 // Sequence resource rather than a Table.
 func (desc *TableDescriptor) IsSequence() bool {
@@ -392,9 +437,10 @@ func (desc *TableDescriptor) IsVirtualTable() bool {
 // different resource like a view or a virtual table. Physical tables have
 // primary keys, column families, and indexes (unlike virtual tables).
 // Sequences count as physical tables because their values are stored in
-// the KV layer.
+// the KV layer, and so do materialized views because their results are.
 func (desc *TableDescriptor) IsPhysicalTable() bool {
-	return desc.IsSequence() || (desc.IsTable() && !desc.IsVirtualTable())
+	return desc.IsSequence() || desc.MaterializedView() ||
+		(desc.IsTable() && !desc.IsVirtualTable())
 }
 
 // KeysPerRow returns the maximum number of keys used to encode a row for the
@@ -697,6 +743,11 @@ func (desc *TableDescriptor) allocateIndexIDs(columnNames map[string]ColumnID) e
 			isCompositeColumn[col.ID] = struct{}{}
 		}
 	}
+	for _, m := range desc.Mutations {
+		if col := m.GetColumn(); col != nil && HasCompositeKeyEncoding(col.Type.SemanticType) {
+			isCompositeColumn[col.ID] = struct{}{}
+		}
+	}
 
 	// Populate IDs.
 	for _, index := range indexes {
@@ -800,6 +851,10 @@ func (desc *TableDescriptor) allocateColumnFamilyIDs(columnNames map[string]Colu
 		if _, ok := columnsInFamilies[col.ID]; ok {
 			return
 		}
+		if col.Virtual {
+			// Virtual columns are not stored.
+			return
+		}
 		if _, ok := primaryIndexColIDs[col.ID]; ok {
 			// Primary index columns are required to be assigned to family 0.
 			desc.Families[0].ColumnNames = append(desc.Families[0].ColumnNames, col.Name)
@@ -1139,8 +1194,13 @@ func (desc *TableDescriptor) ValidateTable(st *cluster.Settings) error {
 				idx := desc.Index
 				return errors.Errorf("mutation in state %s, direction %s, index %s, id %v", m.State, m.Direction, idx.Name, idx.ID)
 			}
+		case *DescriptorMutation_Constraint:
+			if unSetEnums {
+				c := desc.Constraint
+				return errors.Errorf("mutation in state %s, direction %s, constraint %q", m.State, m.Direction, c.Name)
+			}
 		default:
-			return errors.Errorf("mutation in state %s, direction %s, and no column/index descriptor", m.State, m.Direction)
+			return errors.Errorf("mutation in state %s, direction %s, and no column/index/constraint descriptor", m.State, m.Direction)
 		}
 	}
 
@@ -1214,6 +1274,9 @@ func (desc *TableDescriptor) validateColumnFamilies(
 			if !ok {
 				return nil, fmt.Errorf("family %q contains unknown column \"%d\"", family.Name, colID)
 			}
+			if col, err := desc.FindColumnByID(colID); err == nil && col.Virtual {
+				return nil, fmt.Errorf("family %q contains virtual column %q", family.Name, name)
+			}
 			if name != family.ColumnNames[i] {
 				return nil, fmt.Errorf("family %q column %d should have name %q, but found name %q",
 					family.Name, colID, name, family.ColumnNames[i])
@@ -1229,6 +1292,9 @@ func (desc *TableDescriptor) validateColumnFamilies(
 	}
 	for colID := range columnIDs {
 		if _, ok := colIDToFamilyID[colID]; !ok {
+			if col, err := desc.FindColumnByID(colID); err == nil && col.Virtual {
+				continue
+			}
 			return nil, fmt.Errorf("column %d is not in any column family", colID)
 		}
 	}
@@ -1309,6 +1375,9 @@ func (desc *TableDescriptor) validateTableIndexes(
 	}
 
 	for _, colID := range desc.PrimaryIndex.ColumnIDs {
+		if col, err := desc.FindColumnByID(colID); err == nil && col.Virtual {
+			return fmt.Errorf("primary key column %q cannot be virtual", col.Name)
+		}
 		famID, ok := colIDToFamilyID[colID]
 		if !ok || famID != FamilyID(0) {
 			return fmt.Errorf("primary key column %d is not in column family 0", colID)
@@ -1762,6 +1831,11 @@ func (desc *TableDescriptor) RenameColumnDescriptor(column ColumnDescriptor, new
 				idx.ColumnNames[i] = newColName
 			}
 		}
+		for i, id := range idx.StoreColumnIDs {
+			if id == colID {
+				idx.StoreColumnNames[i] = newColName
+			}
+		}
 	}
 	renameColumnInIndex(&desc.PrimaryIndex)
 	for i := range desc.Indexes {
@@ -1995,18 +2069,149 @@ func (desc *TableDescriptor) MakeMutationComplete(m DescriptorMutation) {
 			if err := desc.AddIndex(*t.Index, false); err != nil {
 				panic(err)
 			}
+
+		case *DescriptorMutation_Constraint:
+			switch t.Constraint.ConstraintType {
+			case ConstraintToUpdate_NOT_NULL:
+				// The existing rows have been validated: make the column
+				// non-nullable, which supersedes the check enforcing the
+				// constraint in the meantime.
+				for i := range desc.Columns {
+					if desc.Columns[i].ID == t.Constraint.NotNullColumn {
+						desc.Columns[i].Nullable = false
+						break
+					}
+				}
+				desc.removeCheck(t.Constraint.Name)
+			}
 		}
 
 	case DescriptorMutation_DROP:
 		switch t := m.Descriptor_.(type) {
 		case *DescriptorMutation_Column:
 			desc.RemoveColumnFromFamily(t.Column.ID)
+
+		case *DescriptorMutation_Constraint:
+			// The constraint is being rolled back: stop enforcing it.
+			desc.removeCheck(t.Constraint.Name)
 		}
 		// Nothing else to be done. The column/index was already removed from the
 		// set of column/index descriptors at mutation creation time.
 	}
 }
 
+// SwapReplacedColumns completes the first step of a column type change once
+// the mutations in completed, which carry the ReplacesColumnID and
+// ReplacesIndexID markers set by ALTER COLUMN ... SET DATA TYPE, have been
+// made complete. Each new column and index takes over the name and position
+// of the one it replaces, and the replaced column and indexes are queued for
+// removal at the front of the mutation list under mutationID, so that the
+// schema change continues with dropping them. It returns whether any column
+// or index was swapped.
+func (desc *TableDescriptor) SwapReplacedColumns(
+	completed []DescriptorMutation, mutationID MutationID,
+) (bool, error) {
+	var dropped []DescriptorMutation
+	dropMutation := func(m DescriptorMutation) {
+		m.Direction = DescriptorMutation_DROP
+		m.State = DescriptorMutation_DELETE_AND_WRITE_ONLY
+		m.MutationID = mutationID
+		dropped = append(dropped, m)
+	}
+
+	// Swap the columns first, so that the column names referenced by the
+	// indexes swapped below are already up to date.
+	for _, m := range completed {
+		col := m.GetColumn()
+		if col == nil || m.ReplacesColumnID == 0 || m.Direction != DescriptorMutation_ADD || m.Rollback {
+			continue
+		}
+		newIdx, oldIdx := -1, -1
+		for i := range desc.Columns {
+			switch desc.Columns[i].ID {
+			case col.ID:
+				newIdx = i
+			case m.ReplacesColumnID:
+				oldIdx = i
+			}
+		}
+		if newIdx == -1 || oldIdx == -1 {
+			return false, errors.Errorf("column %d or %d not found", col.ID, m.ReplacesColumnID)
+		}
+		newCol, oldCol := desc.Columns[newIdx], desc.Columns[oldIdx]
+		newName, oldName := newCol.Name, oldCol.Name
+		desc.RenameColumnDescriptor(oldCol, newName)
+		desc.RenameColumnDescriptor(newCol, oldName)
+
+		newCol = desc.Columns[newIdx]
+		newCol.ComputeExpr = nil
+		newCol.DefaultExpr = oldCol.DefaultExpr
+		newCol.Hidden = oldCol.Hidden
+		oldCol = desc.Columns[oldIdx]
+		for i := range desc.Checks {
+			for j, id := range desc.Checks[i].ColumnIDs {
+				if id == oldCol.ID {
+					desc.Checks[i].ColumnIDs[j] = newCol.ID
+				}
+			}
+		}
+
+		for i := range desc.Families {
+			family := &desc.Families[i]
+			newPos, oldPos := -1, -1
+			for j, id := range family.ColumnIDs {
+				switch id {
+				case newCol.ID:
+					newPos = j
+				case oldCol.ID:
+					oldPos = j
+				}
+			}
+			if newPos != -1 && oldPos != -1 {
+				family.ColumnIDs[newPos], family.ColumnIDs[oldPos] = oldCol.ID, newCol.ID
+				family.ColumnNames[newPos], family.ColumnNames[oldPos] = oldCol.Name, newCol.Name
+			}
+		}
+
+		// The new column takes the position of the column it replaces.
+		desc.Columns[oldIdx] = newCol
+		desc.Columns = append(desc.Columns[:newIdx], desc.Columns[newIdx+1:]...)
+		dropMutation(DescriptorMutation{Descriptor_: &DescriptorMutation_Column{Column: &oldCol}})
+	}
+
+	for _, m := range completed {
+		idx := m.GetIndex()
+		if idx == nil || m.ReplacesIndexID == 0 || m.Direction != DescriptorMutation_ADD || m.Rollback {
+			continue
+		}
+		newIdx, oldIdx := -1, -1
+		for i := range desc.Indexes {
+			switch desc.Indexes[i].ID {
+			case idx.ID:
+				newIdx = i
+			case m.ReplacesIndexID:
+				oldIdx = i
+			}
+		}
+		if newIdx == -1 || oldIdx == -1 {
+			return false, errors.Errorf("index %d or %d not found", idx.ID, m.ReplacesIndexID)
+		}
+		newIndex, oldIndex := desc.Indexes[newIdx], desc.Indexes[oldIdx]
+		newIndex.Name, oldIndex.Name = oldIndex.Name, newIndex.Name
+
+		// The new index takes the position of the index it replaces.
+		desc.Indexes[oldIdx] = newIndex
+		desc.Indexes = append(desc.Indexes[:newIdx], desc.Indexes[newIdx+1:]...)
+		dropMutation(DescriptorMutation{Descriptor_: &DescriptorMutation_Index{Index: &oldIndex}})
+	}
+
+	if len(dropped) == 0 {
+		return false, nil
+	}
+	desc.Mutations = append(dropped, desc.Mutations...)
+	return true, nil
+}
+
 // AddColumnMutation adds a column mutation to desc.Mutations.
 func (desc *TableDescriptor) AddColumnMutation(
 	c ColumnDescriptor, direction DescriptorMutation_Direction,
@@ -2036,6 +2241,31 @@ func (desc *TableDescriptor) AddIndexMutation(
 	return nil
 }
 
+// AddNotNullMutation adds a mutation to desc.Mutations making the column
+// colID non-nullable. The check constraint named name must enforce the
+// constraint on writes while the existing rows are validated.
+func (desc *TableDescriptor) AddNotNullMutation(
+	name string, colID ColumnID, direction DescriptorMutation_Direction,
+) {
+	c := ConstraintToUpdate{
+		ConstraintType: ConstraintToUpdate_NOT_NULL,
+		Name:           name,
+		NotNullColumn:  colID,
+	}
+	m := DescriptorMutation{Descriptor_: &DescriptorMutation_Constraint{Constraint: &c}, Direction: direction}
+	desc.addMutation(m)
+}
+
+// removeCheck removes the check constraint with the given name, if any.
+func (desc *TableDescriptor) removeCheck(name string) {
+	for i := range desc.Checks {
+		if desc.Checks[i].Name == name {
+			desc.Checks = append(desc.Checks[:i], desc.Checks[i+1:]...)
+			return
+		}
+	}
+}
+
 func (desc *TableDescriptor) addMutation(m DescriptorMutation) {
 	switch m.Direction {
 	case DescriptorMutation_ADD:
@@ -2422,6 +2652,76 @@ func (desc *DatabaseDescriptor) Validate() error {
 	return desc.Privileges.Validate(desc.GetID())
 }
 
+// SetID implements the DescriptorProto interface.
+func (desc *FunctionDescriptor) SetID(id ID) {
+	desc.ID = id
+}
+
+// TypeName returns the plain type of this descriptor.
+func (desc *FunctionDescriptor) TypeName() string {
+	return "function"
+}
+
+// SetName implements the DescriptorProto interface.
+func (desc *FunctionDescriptor) SetName(name string) {
+	desc.Name = name
+}
+
+// GetAuditMode is part of the DescriptorProto interface.
+// Functions are not audited.
+func (desc *FunctionDescriptor) GetAuditMode() TableDescriptor_AuditMode {
+	return TableDescriptor_DISABLED
+}
+
+// Validate validates that the function descriptor is well formed.
+func (desc *FunctionDescriptor) Validate() error {
+	if err := validateName(desc.Name, "function"); err != nil {
+		return err
+	}
+	if desc.ID == 0 {
+		return fmt.Errorf("invalid function ID %d", desc.ID)
+	}
+	if desc.ParentID == 0 {
+		return fmt.Errorf("invalid parent ID %d", desc.ParentID)
+	}
+	names := make(map[string]struct{}, len(desc.Params))
+	for _, param := range desc.Params {
+		if err := validateName(param.Name, "parameter"); err != nil {
+			return err
+		}
+		if _, ok := names[param.Name]; ok {
+			return fmt.Errorf("duplicate parameter name: %q", param.Name)
+		}
+		names[param.Name] = struct{}{}
+	}
+	if desc.Body == "" {
+		return fmt.Errorf("function %q has no body", desc.Name)
+	}
+	return desc.Privileges.Validate(desc.GetID())
+}
+
+// FindFunction returns the ID of the function with the given name in the
+// database, if it exists.
+func (desc *DatabaseDescriptor) FindFunction(name string) (ID, bool) {
+	for _, fn := range desc.Functions {
+		if fn.Name == name {
+			return fn.ID, true
+		}
+	}
+	return 0, false
+}
+
+// RemoveFunction removes the function with the given name from the
+// database.
+func (desc *DatabaseDescriptor) RemoveFunction(name string) {
+	for i, fn := range desc.Functions {
+		if fn.Name == name {
+			desc.Functions = append(desc.Functions[:i], desc.Functions[i+1:]...)
+			return
+		}
+	}
+}
+
 // GetID returns the ID of the descriptor.
 func (desc *Descriptor) GetID() ID {
 	switch t := desc.Union.(type) {
@@ -2429,6 +2729,8 @@ func (desc *Descriptor) GetID() ID {
 		return t.Table.ID
 	case *Descriptor_Database:
 		return t.Database.ID
+	case *Descriptor_Function:
+		return t.Function.ID
 	default:
 		return 0
 	}
@@ -2441,6 +2743,8 @@ func (desc *Descriptor) GetName() string {
 		return t.Table.Name
 	case *Descriptor_Database:
 		return t.Database.Name
+	case *Descriptor_Function:
+		return t.Function.Name
 	default:
 		return ""
 	}
@@ -2525,7 +2829,11 @@ func (desc *ColumnDescriptor) SQLString() string {
 	if desc.IsComputed() {
 		f.WriteString(" AS (")
 		f.WriteString(*desc.ComputeExpr)
-		f.WriteString(") STORED")
+		if desc.Virtual {
+			f.WriteString(") VIRTUAL")
+		} else {
+			f.WriteString(") STORED")
+		}
 	}
 	return f.CloseAndGetString()
 }
@@ -2603,6 +2911,14 @@ var ForeignKeyReferenceActionValue = [...]ForeignKeyReference_Action{
 	tree.Cascade:    ForeignKeyReference_CASCADE,
 }
 
+// ForeignKeyReferenceMatchValue allows the conversion from a
+// tree.CompositeKeyMatchMethod to a ForeignKeyReference_Match.
+var ForeignKeyReferenceMatchValue = [...]ForeignKeyReference_Match{
+	tree.MatchSimple:  ForeignKeyReference_SIMPLE,
+	tree.MatchFull:    ForeignKeyReference_FULL,
+	tree.MatchPartial: ForeignKeyReference_PARTIAL,
+}
+
 var _ opt.Column = &ColumnDescriptor{}
 
 // IsNullable is part of the opt.Column interface.
@@ -2630,6 +2946,14 @@ func (desc *ColumnDescriptor) IsComputed() bool {
 	return desc.ComputeExpr != nil
 }
 
+// ComputedExprStr is part of the opt.Column interface.
+func (desc *ColumnDescriptor) ComputedExprStr() string {
+	if desc.ComputeExpr == nil {
+		return ""
+	}
+	return *desc.ComputeExpr
+}
+
 // CheckCanBeFKRef returns whether the given column is computed.
 func (desc *ColumnDescriptor) CheckCanBeFKRef() error {
 	if desc.IsComputed() {
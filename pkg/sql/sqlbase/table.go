@@ -150,6 +150,17 @@ func populateTypeAttrs(base ColumnType, typ coltypes.T) (ColumnType, error) {
 	return base, nil
 }
 
+// MakeColumnType returns the column type of a column defined with the given
+// SQL type.
+func MakeColumnType(typ coltypes.T) (ColumnType, error) {
+	// Set Type.SemanticType and Type.Locale.
+	colTyp, err := DatumTypeToColumnType(coltypes.CastTargetToDatumType(typ))
+	if err != nil {
+		return ColumnType{}, err
+	}
+	return populateTypeAttrs(colTyp, typ)
+}
+
 // MakeColumnDefDescs creates the column descriptor for a column, as well as the
 // index descriptor if the column is a primary key or unique.
 //
@@ -166,14 +177,9 @@ func MakeColumnDefDescs(
 		Nullable: d.Nullable.Nullability != tree.NotNull && !d.PrimaryKey,
 	}
 
-	// Set Type.SemanticType and Type.Locale.
 	colDatumType := coltypes.CastTargetToDatumType(d.Type)
-	colTyp, err := DatumTypeToColumnType(colDatumType)
-	if err != nil {
-		return nil, nil, nil, err
-	}
-
-	col.Type, err = populateTypeAttrs(colTyp, d.Type)
+	var err error
+	col.Type, err = MakeColumnType(d.Type)
 	if err != nil {
 		return nil, nil, nil, err
 	}
@@ -229,6 +235,7 @@ func MakeColumnDefDescs(
 	if d.IsComputed() {
 		s := tree.Serialize(d.Computed.Expr)
 		col.ComputeExpr = &s
+		col.Virtual = d.Computed.Virtual
 	}
 
 	var idx *IndexDescriptor
@@ -2363,7 +2370,7 @@ func (desc *TableDescriptor) collectConstraintInfo(
 			return nil, errors.Errorf("duplicate constraint name: %q", c.Name)
 		}
 		detail := ConstraintDetail{Kind: ConstraintTypeCheck}
-		detail.Unvalidated = c.Validity == ConstraintValidity_Unvalidated
+		detail.Unvalidated = c.Validity != ConstraintValidity_Validated
 		if tableLookup != nil {
 			detail.Details = c.Expr
 			detail.CheckConstraint = c
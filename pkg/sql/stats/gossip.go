@@ -0,0 +1,31 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package stats
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/gossip"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+)
+
+// GossipTableStatAdded notifies the statistics caches of all the nodes that
+// new statistics are available for the given table, so that they evict the
+// stale statistics of the table.
+func GossipTableStatAdded(g *gossip.Gossip, tableID sqlbase.ID) error {
+	return g.AddInfo(
+		gossip.MakeTableStatAddedKey(uint32(tableID)),
+		nil, /* val */
+		0,   /* ttl */
+	)
+}
@@ -19,7 +19,9 @@ import (
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws/awsutil"
+	"github.com/cockroachdb/cockroach/pkg/gossip"
 	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/types"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
@@ -97,8 +99,14 @@ type TableStatisticsCache struct {
 // size of the underlying statsCache set to statsCacheSize, and the size of
 // the underlying histogramCache set to histogramCacheSize.
 // Both underlying caches internally use a hash map, so lookups are cheap.
+// The cache evicts the statistics of a table when new statistics for the
+// table are announced through gossip (see GossipTableStatAdded).
 func NewTableStatisticsCache(
-	statsCacheSize int, histogramCacheSize int, db *client.DB, sqlExecutor sqlutil.InternalExecutor,
+	statsCacheSize int,
+	histogramCacheSize int,
+	g *gossip.Gossip,
+	db *client.DB,
+	sqlExecutor sqlutil.InternalExecutor,
 ) *TableStatisticsCache {
 	tableStatsCache := &TableStatisticsCache{
 		ClientDB:    db,
@@ -112,9 +120,27 @@ func NewTableStatisticsCache(
 		Policy:      cache.CacheLRU,
 		ShouldEvict: func(s int, key, value interface{}) bool { return s > histogramCacheSize },
 	})
+	g.RegisterCallback(
+		gossip.MakePrefixPattern(gossip.KeyTableStatAddedPrefix),
+		tableStatsCache.tableStatAddedGossipUpdate,
+	)
 	return tableStatsCache
 }
 
+// tableStatAddedGossipUpdate is the gossip callback that evicts the
+// statistics of a table when new statistics are created for it on any node.
+// The histograms don't need to be evicted: they are keyed by statistic ID,
+// and new statistics have new IDs.
+func (sc *TableStatisticsCache) tableStatAddedGossipUpdate(key string, _ roachpb.Value) {
+	ctx := context.TODO()
+	tableID, err := gossip.TableIDFromTableStatAddedKey(key)
+	if err != nil {
+		log.Errorf(ctx, "malformed gossip key %s: %s", key, err)
+		return
+	}
+	sc.InvalidateTableStats(ctx, sqlbase.ID(tableID))
+}
+
 // lookupTableStats returns the cached statistics of the given table ID.
 // The second return value is true if the stats were found in the
 // cache, and false otherwise.
@@ -119,6 +119,24 @@ func (p *planTop) evalSubqueries(params runParams) error {
 	return nil
 }
 
+// releaseSubqueries removes the subqueries at indexes [start, end) from the
+// plan, once the deferred plan that added them (for example the plan of an
+// iteration of a recursive CTE) is closed. This prevents the subquery plans
+// from growing with every iteration. The subqueries are only removed if no
+// other subqueries were added after them, since these may still be in use.
+func (p *planTop) releaseSubqueries(ctx context.Context, start, end int) {
+	if len(p.subqueryPlans) != end {
+		return
+	}
+	for i := start; i < end; i++ {
+		if p.subqueryPlans[i].plan != nil {
+			p.subqueryPlans[i].plan.Close(ctx)
+		}
+		p.subqueryPlans[i] = subquery{}
+	}
+	p.subqueryPlans = p.subqueryPlans[:start]
+}
+
 func (s *subquery) doEval(params runParams) (result tree.Datum, err error) {
 	// After evaluation, there is no plan remaining.
 	defer func() { s.plan.Close(params.ctx); s.plan = nil }()
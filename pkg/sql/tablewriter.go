@@ -390,6 +390,7 @@ func (tu *tableUpserter) init(txn *client.Txn, evalCtx *tree.EvalContext) error
 		ColIdxMap:       tu.fetchColIDtoRowIndex,
 		Cols:            tu.fetchCols,
 		ValNeededForCol: valNeededForCol,
+		EvalCtx:         tu.evalCtx,
 	}
 
 	return tu.fetcher.Init(
@@ -1271,6 +1272,7 @@ func (td *tableDeleter) deleteAllRowsScan(
 		ColIdxMap:       td.rd.FetchColIDtoRowIndex,
 		Cols:            td.rd.FetchCols,
 		ValNeededForCol: valNeededForCol,
+		EvalCtx:         td.evalCtx,
 	}
 	if err := rf.Init(
 		false /* reverse */, false /* returnRangeInfo */, false /* isCheck */, td.alloc, tableArgs,
@@ -1382,6 +1384,7 @@ func (td *tableDeleter) deleteIndexScan(
 		ColIdxMap:       td.rd.FetchColIDtoRowIndex,
 		Cols:            td.rd.FetchCols,
 		ValNeededForCol: valNeededForCol,
+		EvalCtx:         td.evalCtx,
 	}
 	if err := rf.Init(
 		false /* reverse */, false /* returnRangeInfo */, false /* isCheck */, td.alloc, tableArgs,
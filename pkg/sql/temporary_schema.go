@@ -0,0 +1,269 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/sql/jobs"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/uint128"
+)
+
+// This file implements the temporary schemas that hold the tables
+// created with CREATE TEMP TABLE.
+//
+// Each session that creates a temporary table gets its own temporary
+// schema. Until user-defined schemas are supported, the temporary
+// schema is stored as a database named after the session ID, for
+// example pg_temp_1528731498231540000_1. The pg_temp schema in any
+// database designates the temporary schema of the current session;
+// the temporary schemas of other sessions are invisible.
+//
+// A temporary schema is dropped, with everything in it, when its
+// session ends or runs DISCARD TEMP. The temporary schemas left behind
+// by sessions that could not clean up after themselves, for example
+// because their node died, are dropped by the TemporarySchemaCleaner.
+
+const temporarySchemaPrefix = "pg_temp_"
+
+var temporarySchemaCleanupInterval = settings.RegisterValidatedDurationSetting(
+	"sql.temp_object_cleaner.cleanup_interval",
+	"how often to drop the temporary schemas of sessions that ended without dropping them",
+	30*time.Minute,
+	func(v time.Duration) error {
+		if v <= 0 {
+			return errors.Errorf("cannot set sql.temp_object_cleaner.cleanup_interval to a non-positive duration: %s", v)
+		}
+		return nil
+	},
+)
+
+// temporarySchemaName returns the name of the temporary schema of the
+// session with the given ID.
+func temporarySchemaName(sessionID ClusterWideID) string {
+	return fmt.Sprintf("%s%d_%d", temporarySchemaPrefix, sessionID.Hi, sessionID.Lo)
+}
+
+// isTemporarySchemaName returns true if the given database name is
+// reserved for temporary schemas.
+func isTemporarySchemaName(name string) bool {
+	return strings.HasPrefix(name, temporarySchemaPrefix)
+}
+
+// temporarySchemaSessionID extracts the ID of the session that owns
+// the temporary schema with the given name.
+func temporarySchemaSessionID(name string) (ClusterWideID, bool) {
+	if !isTemporarySchemaName(name) {
+		return ClusterWideID{}, false
+	}
+	parts := strings.Split(strings.TrimPrefix(name, temporarySchemaPrefix), "_")
+	if len(parts) != 2 {
+		return ClusterWideID{}, false
+	}
+	hi, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return ClusterWideID{}, false
+	}
+	lo, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil {
+		return ClusterWideID{}, false
+	}
+	return ClusterWideID{Uint128: uint128.FromInts(hi, lo)}, true
+}
+
+// checkNotTemporarySchemaName rejects database names reserved for
+// temporary schemas.
+func checkNotTemporarySchemaName(name string) error {
+	if isTemporarySchemaName(name) {
+		return pgerror.NewErrorf(pgerror.CodeReservedNameError,
+			"database name %q is reserved: the prefix %q is used for temporary schemas",
+			name, temporarySchemaPrefix)
+	}
+	return nil
+}
+
+// isTemporaryTarget returns true if a new object with the given name
+// would be created in the temporary schema.
+func (p *planner) isTemporaryTarget(tn *ObjectName) bool {
+	if tn.ExplicitSchema {
+		return tn.Schema() == sessiondata.PgTempSchemaName
+	}
+	hasFirst, firstSchema := p.CurrentSearchPath().FirstSpecified()
+	return hasFirst && firstSchema == sessiondata.PgTempSchemaName
+}
+
+// resolveTemporaryTarget qualifies the name of a new temporary object
+// and returns the descriptor of the session's temporary schema, or nil
+// if the session has not created it yet.
+func (p *planner) resolveTemporaryTarget(
+	ctx context.Context, tn *ObjectName,
+) (*DatabaseDescriptor, error) {
+	if tn.ExplicitSchema && tn.Schema() != sessiondata.PgTempSchemaName {
+		return nil, pgerror.NewError(pgerror.CodeInvalidTableDefinitionError,
+			"cannot create temporary relation in non-temporary schema")
+	}
+	tempSchemaName := p.ExtendedEvalContext().TemporarySchemaName
+	if tempSchemaName == "" {
+		return nil, pgerror.NewError(pgerror.CodeFeatureNotSupportedError,
+			"temporary tables are not supported in internal sessions")
+	}
+	if !tn.ExplicitCatalog {
+		tn.CatalogName = tree.Name(p.CurrentDatabase())
+	}
+	tn.SchemaName = sessiondata.PgTempSchemaName
+	return p.LogicalSchemaAccessor().GetDatabaseDesc(tempSchemaName,
+		p.CommonLookupFlags(ctx, false /*required*/))
+}
+
+// createTemporarySchema creates the temporary schema of the session.
+func (p *planner) createTemporarySchema(ctx context.Context) (*DatabaseDescriptor, error) {
+	desc := sqlbase.DatabaseDescriptor{
+		Name:       p.ExtendedEvalContext().TemporarySchemaName,
+		Privileges: sqlbase.NewDefaultPrivilegeDescriptor(),
+	}
+	// The session's user owns the temporary schema and everything in it.
+	desc.Privileges.Grant(p.SessionData().User, privilege.List{privilege.ALL})
+	if _, err := p.createDatabase(ctx, &desc, false /* ifNotExists */); err != nil {
+		return nil, err
+	}
+	p.Tables().addUncommittedDatabase(desc.Name, desc.ID, dbCreated)
+	p.sessionDataMutator.SetTemporarySchemaName(desc.Name)
+	return &desc, nil
+}
+
+// discardTemporarySchema drops the temporary schema of the session, if
+// it has one. The session keeps the name of the schema in its search
+// path, so that the schema is still dropped when the session ends if
+// the transaction that drops it now is rolled back.
+func (p *planner) discardTemporarySchema(ctx context.Context) (planNode, error) {
+	tempSchemaName := p.SessionData().SearchPath.GetTemporarySchemaName()
+	if tempSchemaName == "" {
+		return newZeroNode(nil /* columns */), nil
+	}
+	return p.DropDatabase(ctx, &tree.DropDatabase{
+		Name:         tree.Name(tempSchemaName),
+		IfExists:     true,
+		DropBehavior: tree.DropCascade,
+	})
+}
+
+// isOtherSessionTemporarySchema returns true if the database with the
+// given name is the temporary schema of another session, and hence
+// invisible to the current session.
+func (p *planner) isOtherSessionTemporarySchema(name string) bool {
+	tempSchemaName := p.ExtendedEvalContext().TemporarySchemaName
+	return tempSchemaName != "" && name != tempSchemaName && isTemporarySchemaName(name)
+}
+
+// dropTemporarySchema drops the temporary schema with the given name,
+// if it exists, in a new transaction.
+func dropTemporarySchema(ctx context.Context, execCfg *ExecutorConfig, name string) error {
+	ie := InternalExecutor{ExecCfg: execCfg}
+	_, err := ie.ExecuteStatement(ctx, "drop-temp-schema",
+		fmt.Sprintf(`DROP DATABASE IF EXISTS %s CASCADE`, tree.NameString(name)))
+	return err
+}
+
+// TemporarySchemaCleaner periodically drops the temporary schemas of
+// sessions that are gone.
+//
+// Each node drops the temporary schemas of the sessions it hosted that
+// are no longer in its session registry, and the temporary schemas of
+// the sessions hosted by nodes that node liveness reports as dead.
+type TemporarySchemaCleaner struct {
+	execCfg *ExecutorConfig
+	nl      jobs.NodeLiveness
+}
+
+// NewTemporarySchemaCleaner creates a TemporarySchemaCleaner.
+func NewTemporarySchemaCleaner(
+	execCfg *ExecutorConfig, nl jobs.NodeLiveness,
+) *TemporarySchemaCleaner {
+	return &TemporarySchemaCleaner{execCfg: execCfg, nl: nl}
+}
+
+// Start runs the cleaner until the stopper stops.
+func (c *TemporarySchemaCleaner) Start(ctx context.Context, stopper *stop.Stopper) {
+	stopper.RunWorker(ctx, func(ctx context.Context) {
+		for {
+			select {
+			case <-time.After(temporarySchemaCleanupInterval.Get(&c.execCfg.Settings.SV)):
+				if err := c.cleanup(ctx); err != nil {
+					log.Warningf(ctx, "error while dropping orphaned temporary schemas: %s", err)
+				}
+			case <-stopper.ShouldStop():
+				return
+			}
+		}
+	})
+}
+
+// cleanup drops the temporary schemas whose sessions are gone.
+func (c *TemporarySchemaCleaner) cleanup(ctx context.Context) error {
+	ie := InternalExecutor{ExecCfg: c.execCfg}
+	rows, _ /* cols */, err := ie.QueryRows(ctx, "find-temp-schemas",
+		`SELECT name FROM system.namespace WHERE "parentID" = 0 AND name LIKE $1`,
+		strings.Replace(temporarySchemaPrefix, "_", `\_`, -1)+"%")
+	if err != nil {
+		return err
+	}
+
+	now, maxOffset := c.execCfg.Clock.Now(), c.execCfg.Clock.MaxOffset()
+	isLive := make(map[roachpb.NodeID]bool)
+	for _, liveness := range c.nl.GetLivenesses() {
+		isLive[liveness.NodeID] = liveness.IsLive(now, maxOffset)
+	}
+
+	for _, row := range rows {
+		name := string(tree.MustBeDString(row[0]))
+		sessionID, ok := temporarySchemaSessionID(name)
+		if !ok {
+			continue
+		}
+		nodeID := roachpb.NodeID(sessionID.GetNodeID())
+		if nodeID == c.execCfg.NodeID.Get() {
+			// The sessions hosted by this node are known to its registry.
+			if c.execCfg.SessionRegistry.isRegistered(sessionID) {
+				continue
+			}
+		} else if live, ok := isLive[nodeID]; !ok || live {
+			// The session may still be running on another node, which
+			// will clean up after it. The livenesses are learned through
+			// gossip, so a node that is missing from them may well be
+			// alive.
+			continue
+		}
+		log.Infof(ctx, "dropping orphaned temporary schema %s", name)
+		if err := dropTemporarySchema(ctx, c.execCfg, name); err != nil {
+			log.Warningf(ctx, "error while dropping temporary schema %s: %s", name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,208 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"context"
+	gosql "database/sql"
+	"net/url"
+	"testing"
+
+	"github.com/pkg/errors"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/sql/jobs"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/uint128"
+)
+
+func TestTemporarySchemaName(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	sessionID := ClusterWideID{Uint128: uint128.FromInts(1528731498231540000, 3)}
+	name := temporarySchemaName(sessionID)
+	if expected := "pg_temp_1528731498231540000_3"; name != expected {
+		t.Fatalf("expected %s, got %s", expected, name)
+	}
+	if !isTemporarySchemaName(name) {
+		t.Fatalf("expected %s to be a temporary schema name", name)
+	}
+	if id, ok := temporarySchemaSessionID(name); !ok || id != sessionID {
+		t.Fatalf("expected session ID %s, got %s (ok=%t)", sessionID, id, ok)
+	}
+	if id := sessionID.GetNodeID(); id != 3 {
+		t.Fatalf("expected node ID 3, got %d", id)
+	}
+
+	for _, name := range []string{"pg_temp", "pg_temp_1", "pg_temp_x_1", "pg_temp_1_2_3", "test"} {
+		if _, ok := temporarySchemaSessionID(name); ok {
+			t.Errorf("expected %s not to name a temporary schema", name)
+		}
+	}
+}
+
+// openSession opens a connection to the server that is backed by a single
+// session.
+func openSession(t *testing.T, s serverutils.TestServerInterface, name string) (*gosql.DB, func()) {
+	pgURL, cleanupURL := sqlutils.PGUrl(t, s.ServingAddr(), name, url.User(security.RootUser))
+	db, err := gosql.Open("postgres", pgURL.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.SetMaxOpenConns(1)
+	return db, func() {
+		_ = db.Close()
+		cleanupURL()
+	}
+}
+
+// temporarySchemaExists returns true if the temporary schema with the given
+// name exists.
+func temporarySchemaExists(t *testing.T, db *gosql.DB, name string) bool {
+	var count int
+	if err := db.QueryRow(
+		`SELECT count(*) FROM system.namespace WHERE "parentID" = 0 AND name = $1`, name,
+	).Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	return count > 0
+}
+
+// createTemporaryTable creates a temporary table in the given session and
+// returns the name of the session's temporary schema.
+func createTemporaryTable(t *testing.T, db *gosql.DB) string {
+	if _, err := db.Exec(`CREATE TEMP TABLE t.pg_temp.tmp (a INT)`); err != nil {
+		t.Fatal(err)
+	}
+	var name string
+	if err := db.QueryRow(
+		`SELECT table_catalog FROM "".information_schema.tables WHERE table_name = 'tmp'`,
+	).Scan(&name); err != nil {
+		t.Fatal(err)
+	}
+	if !isTemporarySchemaName(name) {
+		t.Fatalf("expected a temporary schema, got %s", name)
+	}
+	return name
+}
+
+func TestTemporarySchemaDroppedOnSessionClose(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	s, sqlDB, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(context.TODO())
+
+	if _, err := sqlDB.Exec(`CREATE DATABASE t`); err != nil {
+		t.Fatal(err)
+	}
+
+	db, cleanup := openSession(t, s, "TestTemporarySchemaDroppedOnSessionClose")
+	name := createTemporaryTable(t, db)
+
+	// The temporary schema is invisible to other sessions.
+	if _, err := sqlDB.Exec(`SELECT * FROM ` + name + `.tmp`); !testutils.IsError(err, "does not exist") {
+		t.Fatalf("expected the temporary table to be invisible, got %v", err)
+	}
+
+	cleanup()
+	testutils.SucceedsSoon(t, func() error {
+		if temporarySchemaExists(t, sqlDB, name) {
+			return errors.Errorf("temporary schema %s still exists", name)
+		}
+		return nil
+	})
+}
+
+func TestTemporarySchemaCleaner(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	s, sqlDB, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(context.TODO())
+	ctx := context.TODO()
+	execCfg := s.InternalExecutor().(*InternalExecutor).ExecCfg
+
+	if _, err := sqlDB.Exec(`CREATE DATABASE t`); err != nil {
+		t.Fatal(err)
+	}
+
+	db, cleanup := openSession(t, s, "TestTemporarySchemaCleaner")
+	defer cleanup()
+	name := createTemporaryTable(t, db)
+	sessionID, ok := temporarySchemaSessionID(name)
+	if !ok {
+		t.Fatalf("could not extract the session ID from %s", name)
+	}
+
+	cleaner := NewTemporarySchemaCleaner(execCfg, jobs.NewFakeNodeLiveness(1))
+
+	// The session is still registered, so its temporary schema is kept.
+	if err := cleaner.cleanup(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if !temporarySchemaExists(t, sqlDB, name) {
+		t.Fatalf("expected temporary schema %s to exist", name)
+	}
+
+	// Pretend the session ended without dropping its temporary schema.
+	execCfg.SessionRegistry.deregister(sessionID)
+	if err := cleaner.cleanup(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if temporarySchemaExists(t, sqlDB, name) {
+		t.Fatalf("expected temporary schema %s to be dropped", name)
+	}
+
+	// Pretend the sessions of this node are hosted by another node. The
+	// temporary schema of a session is kept while node liveness reports its
+	// node as live, or doesn't know about the node, and dropped once the
+	// node is reported as dead.
+	db2, cleanup2 := openSession(t, s, "TestTemporarySchemaCleaner2")
+	defer cleanup2()
+	name2 := createTemporaryTable(t, db2)
+	otherNodeCfg := *execCfg
+	otherNodeCfg.NodeID = &base.NodeIDContainer{}
+	otherNodeCfg.NodeID.Reset(execCfg.NodeID.Get() + 1)
+	sessionNodeID := execCfg.NodeID.Get()
+
+	nl := jobs.NewFakeNodeLiveness(int(sessionNodeID))
+	otherCleaner := NewTemporarySchemaCleaner(&otherNodeCfg, nl)
+	if err := otherCleaner.cleanup(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if !temporarySchemaExists(t, sqlDB, name2) {
+		t.Fatalf("expected temporary schema %s to exist", name2)
+	}
+
+	unknownCleaner := NewTemporarySchemaCleaner(&otherNodeCfg, jobs.NewFakeNodeLiveness(0))
+	if err := unknownCleaner.cleanup(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if !temporarySchemaExists(t, sqlDB, name2) {
+		t.Fatalf("expected temporary schema %s to exist", name2)
+	}
+
+	nl.FakeSetExpiration(sessionNodeID, hlc.MinTimestamp)
+	if err := otherCleaner.cleanup(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if temporarySchemaExists(t, sqlDB, name2) {
+		t.Fatalf("expected temporary schema %s to be dropped", name2)
+	}
+}
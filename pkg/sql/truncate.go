@@ -200,17 +200,23 @@ func (p *planner) truncateTable(ctx context.Context, id sqlbase.ID, traceKV bool
 	}
 	// Resolve all outstanding mutations. Make all new schema elements
 	// public because the table is empty and doesn't need to be backfilled.
+	// Completing the mutations also makes in-progress NOT NULL constraints
+	// take effect, and finishes column type changes, whose new columns
+	// replace the old ones and stop being hidden computed columns.
+	mutations := newTableDesc.Mutations
+	newTableDesc.Mutations = nil
+	for _, m := range mutations {
+		newTableDesc.MakeMutationComplete(m)
+	}
+	if _, err := newTableDesc.SwapReplacedColumns(mutations, sqlbase.InvalidMutationID); err != nil {
+		return err
+	}
+	// Drop the columns and indexes replaced by type changes.
 	for _, m := range newTableDesc.Mutations {
-		if m.Direction == sqlbase.DescriptorMutation_ADD {
-			if col := m.GetColumn(); col != nil {
-				newTableDesc.Columns = append(newTableDesc.Columns, *col)
-			}
-			if idx := m.GetIndex(); idx != nil {
-				newTableDesc.Indexes = append(newTableDesc.Indexes, *idx)
-			}
-		}
+		newTableDesc.MakeMutationComplete(m)
 	}
 	newTableDesc.Mutations = nil
+	newTableDesc.MutationJobs = nil
 	tKey := tableKey{parentID: newTableDesc.ParentID, name: newTableDesc.Name}
 	key := tKey.Key()
 	if err := p.createDescriptorWithID(ctx, key, newID, &newTableDesc); err != nil {
@@ -271,6 +277,12 @@ func (p *planner) findAllReferences(
 		refs[c.ID] = struct{}{}
 	}
 
+	for _, col := range table.Columns {
+		for _, seqID := range col.OwnsSequenceIds {
+			refs[seqID] = struct{}{}
+		}
+	}
+
 	tables := make([]*sqlbase.TableDescriptor, 0, len(refs))
 	for id := range refs {
 		if id == table.ID {
@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"sync"
 
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/types"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
@@ -739,6 +740,14 @@ func upsertExprsAndIndex(
 
 	// General case: INSERT with an ON CONFLICT clause.
 
+	if onConflict.Constraint != "" {
+		conflictIndex, err := upsertConstraintIndex(tableDesc, string(onConflict.Constraint))
+		if err != nil {
+			return nil, nil, err
+		}
+		return onConflict.Exprs, conflictIndex, nil
+	}
+
 	indexMatch := func(index sqlbase.IndexDescriptor) bool {
 		if !index.Unique {
 			return false
@@ -764,3 +773,29 @@ func upsertExprsAndIndex(
 	}
 	return nil, nil, fmt.Errorf("there is no unique or exclusion constraint matching the ON CONFLICT specification")
 }
+
+// upsertConstraintIndex returns the unique index backing the named constraint,
+// for use as the conflict index of an `ON CONFLICT ON CONSTRAINT name` clause.
+func upsertConstraintIndex(
+	tableDesc *sqlbase.TableDescriptor, name string,
+) (*sqlbase.IndexDescriptor, error) {
+	if tableDesc.PrimaryIndex.Name == name {
+		return &tableDesc.PrimaryIndex, nil
+	}
+	for i := range tableDesc.Indexes {
+		index := &tableDesc.Indexes[i]
+		if index.Unique && index.Name == name {
+			return index, nil
+		}
+		if index.ForeignKey.IsSet() && index.ForeignKey.Name == name {
+			return nil, fmt.Errorf("constraint %q in ON CONFLICT clause has no associated unique index", name)
+		}
+	}
+	for _, check := range tableDesc.Checks {
+		if check.Name == name {
+			return nil, fmt.Errorf("constraint %q in ON CONFLICT clause has no associated unique index", name)
+		}
+	}
+	return nil, pgerror.NewErrorf(pgerror.CodeUndefinedObjectError,
+		"constraint %q of relation %q does not exist", name, tableDesc.Name)
+}
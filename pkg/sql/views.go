@@ -19,6 +19,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/types"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
@@ -85,6 +86,10 @@ func (p *planner) analyzeViewQuery(
 	defer func(prev bool) { p.curPlan.hasStar = prev }(p.curPlan.hasStar)
 	p.curPlan.hasStar = false
 
+	// Request user-defined function detection.
+	defer func(prev bool) { p.curPlan.hasUserDefinedFunction = prev }(p.curPlan.hasUserDefinedFunction)
+	p.curPlan.hasUserDefinedFunction = false
+
 	// Now generate the source plan.
 	sourcePlan, err := p.Select(ctx, viewSelect, []types.T{})
 	if err != nil {
@@ -97,6 +102,12 @@ func (p *planner) analyzeViewQuery(
 	if p.curPlan.hasStar {
 		return nil, nil, fmt.Errorf("views do not currently support * expressions")
 	}
+	// A view calling a user-defined function would be broken by DROP
+	// FUNCTION, since the dependency is not recorded.
+	if p.curPlan.hasUserDefinedFunction {
+		return nil, nil, pgerror.NewError(pgerror.CodeFeatureNotSupportedError,
+			"views do not currently support user-defined functions")
+	}
 
 	return p.curPlan.deps, planColumns(sourcePlan), nil
 }
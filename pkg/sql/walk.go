@@ -150,6 +150,12 @@ func (v *planVisitor) visit(plan planNode) {
 			if n.hardLimit > 0 && isFilterTrue(n.filter) {
 				v.observer.attr(name, "limit", fmt.Sprintf("%d", n.hardLimit))
 			}
+			if n.lockingStrength != tree.ForNone {
+				v.observer.attr(name, "locking strength", n.lockingStrength.String())
+			}
+			if n.lockingWaitPolicy != tree.LockWaitBlock {
+				v.observer.attr(name, "locking wait policy", n.lockingWaitPolicy.String())
+			}
 		}
 		if v.observer.expr != nil {
 			v.expr(name, "filter", -1, n.filter)
@@ -219,6 +225,30 @@ func (v *planVisitor) visit(plan planNode) {
 		v.visit(n.left.plan)
 		v.visit(n.right.plan)
 
+	case *applyJoinNode:
+		if v.observer.attr != nil {
+			jType := "inner"
+			if n.joinType == sqlbase.LeftOuterJoin {
+				jType = "left outer"
+			}
+			v.observer.attr(name, "type", jType)
+
+			if len(n.pred.leftColNames) > 0 {
+				f := tree.NewFmtCtxWithBuf(tree.FmtSimple)
+				f.WriteByte('(')
+				f.FormatNode(&n.pred.leftColNames)
+				f.WriteString(") = (")
+				f.FormatNode(&n.pred.rightColNames)
+				f.WriteByte(')')
+				v.observer.attr(name, "equality", f.CloseAndGetString())
+			}
+		}
+		if v.observer.expr != nil {
+			v.expr(name, "pred", -1, n.pred.onCond)
+		}
+		v.visit(n.left.plan)
+		v.visit(n.right.plan)
+
 	case *limitNode:
 		if v.observer.expr != nil {
 			v.expr(name, "count", -1, n.countExpr)
@@ -297,6 +327,10 @@ func (v *planVisitor) visit(plan planNode) {
 							buf.WriteString("DISTINCT ")
 						}
 						buf.WriteString(inputCols[agg.argRenderIdx].Name)
+						for _, idx := range agg.otherArgRenderIdxs {
+							buf.WriteString(", ")
+							buf.WriteString(inputCols[idx].Name)
+						}
 					}
 					buf.WriteByte(')')
 					if agg.filterRenderIdx != noRenderIdx {
@@ -327,6 +361,23 @@ func (v *planVisitor) visit(plan planNode) {
 					v.observer.attr(name, "group by", buf.String())
 				}
 			}
+			if n.groupingSets != nil {
+				var buf bytes.Buffer
+				for i, set := range n.groupingSets {
+					if i > 0 {
+						buf.WriteString(", ")
+					}
+					buf.WriteByte('(')
+					for j, idx := range set {
+						if j > 0 {
+							buf.WriteByte(',')
+						}
+						fmt.Fprintf(&buf, "@%d", idx+1)
+					}
+					buf.WriteByte(')')
+				}
+				v.observer.attr(name, "grouping sets", buf.String())
+			}
 		}
 
 		v.visit(n.plan)
@@ -346,6 +397,22 @@ func (v *planVisitor) visit(plan planNode) {
 		v.visit(n.left)
 		v.visit(n.right)
 
+	case *recursiveCTENode:
+		if v.observer.attr != nil {
+			v.observer.attr(name, "label", n.label)
+		}
+		if n.initial != nil {
+			v.visit(n.initial)
+		}
+		if n.recursive != nil {
+			v.visit(n.recursive)
+		}
+
+	case *scanBufferNode:
+		if v.observer.attr != nil {
+			v.observer.attr(name, "label", n.label)
+		}
+
 	case *splitNode:
 		v.visit(n.rows)
 
@@ -482,6 +549,9 @@ func (v *planVisitor) visit(plan planNode) {
 	case *explainDistSQLNode:
 		v.visit(n.plan)
 
+	case *explainAnalyzeNode:
+		v.visit(n.plan)
+
 	case *ordinalityNode:
 		v.visit(n.source)
 
@@ -566,6 +636,7 @@ var planNodeNames = map[reflect.Type]string{
 	reflect.TypeOf(&alterTableNode{}):           "alter table",
 	reflect.TypeOf(&alterSequenceNode{}):        "alter sequence",
 	reflect.TypeOf(&alterUserSetPasswordNode{}): "alter user",
+	reflect.TypeOf(&applyJoinNode{}):            "apply join",
 	reflect.TypeOf(&cancelQueryNode{}):          "cancel query",
 	reflect.TypeOf(&cancelSessionNode{}):        "cancel session",
 	reflect.TypeOf(&controlJobNode{}):           "control job",
@@ -575,6 +646,7 @@ var planNodeNames = map[reflect.Type]string{
 	reflect.TypeOf(&CreateUserNode{}):           "create user | role",
 	reflect.TypeOf(&createViewNode{}):           "create view",
 	reflect.TypeOf(&createSequenceNode{}):       "create sequence",
+	reflect.TypeOf(&createFunctionNode{}):       "create function",
 	reflect.TypeOf(&createStatsNode{}):          "create statistics",
 	reflect.TypeOf(&delayedNode{}):              "virtual table",
 	reflect.TypeOf(&deleteNode{}):               "delete",
@@ -584,7 +656,9 @@ var planNodeNames = map[reflect.Type]string{
 	reflect.TypeOf(&dropTableNode{}):            "drop table",
 	reflect.TypeOf(&dropViewNode{}):             "drop view",
 	reflect.TypeOf(&dropSequenceNode{}):         "drop sequence",
+	reflect.TypeOf(&dropFunctionNode{}):         "drop function",
 	reflect.TypeOf(&DropUserNode{}):             "drop user | role",
+	reflect.TypeOf(&explainAnalyzeNode{}):       "explain analyze",
 	reflect.TypeOf(&explainDistSQLNode{}):       "explain dist_sql",
 	reflect.TypeOf(&explainPlanNode{}):          "explain plan",
 	reflect.TypeOf(&showTraceNode{}):            "show trace for",
@@ -598,9 +672,12 @@ var planNodeNames = map[reflect.Type]string{
 	reflect.TypeOf(&joinNode{}):                 "join",
 	reflect.TypeOf(&limitNode{}):                "limit",
 	reflect.TypeOf(&ordinalityNode{}):           "ordinality",
+	reflect.TypeOf(&recursiveCTENode{}):         "recursive cte",
 	reflect.TypeOf(&testingRelocateNode{}):      "testingRelocate",
+	reflect.TypeOf(&refreshViewNode{}):          "refresh materialized view",
 	reflect.TypeOf(&renderNode{}):               "render",
 	reflect.TypeOf(&scanNode{}):                 "scan",
+	reflect.TypeOf(&scanBufferNode{}):           "scan buffer",
 	reflect.TypeOf(&scatterNode{}):              "scatter",
 	reflect.TypeOf(&scrubNode{}):                "scrub",
 	reflect.TypeOf(&sequenceSelectNode{}):       "sequence select",
@@ -22,10 +22,12 @@ import (
 
 	"github.com/pkg/errors"
 
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/transform"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/types"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/cockroachdb/cockroach/pkg/util/encoding"
 	"github.com/cockroachdb/cockroach/pkg/util/mon"
 )
@@ -59,8 +61,9 @@ type windowNode struct {
 // window constructs a windowNode according to window function applications. This may
 // adjust the render targets in the renderNode as necessary. The use of window functions
 // will run with a space complexity of O(NW) (N = number of rows, W = number of windows)
-// and a time complexity of O(NW) (no ordering), O(W*NlogN) (with ordering), and
-// O(W*N^2) (with constant or variable sized window-frames, which are not yet supported).
+// and a time complexity of O(NW) (no ordering) and O(W*NlogN) (with ordering). Window
+// frames add a factor of O(logN) in RANGE mode with an offset, and aggregates over a
+// frame whose start moves may take O(N*F) (F = frame size) unless they are removable.
 //
 // This code uses the following terminology throughout:
 // - window:
@@ -321,11 +324,122 @@ func (p *planner) constructWindowDefinitions(
 			}
 		}
 
+		if err := p.constructWindowFrame(ctx, windowFn, windowDef.Frame, s); err != nil {
+			return err
+		}
+
 		windowFn.windowDef = windowDef
 	}
 	return nil
 }
 
+// constructWindowFrame validates the frame clause of a window definition, and
+// type checks the offsets of its bounds. In RANGE mode, the offsets are added
+// to and subtracted from the values of the single ORDER BY column, so their
+// type depends on the type of that column.
+func (p *planner) constructWindowFrame(
+	ctx context.Context, windowFn *windowFuncHolder, frame *tree.WindowFrame, s *renderNode,
+) error {
+	if frame == nil {
+		return nil
+	}
+	windowFn.frame = frame
+	startBound, endBound := frame.Bounds.StartBound, frame.Bounds.EndBound
+	hasOffset := startBound.HasOffset() || (endBound != nil && endBound.HasOffset())
+	if !hasOffset {
+		return nil
+	}
+
+	modeName := frame.Mode.String()
+	offsetType := types.Int
+	if frame.Mode == tree.RANGE {
+		if len(windowFn.columnOrdering) != 1 {
+			return pgerror.NewErrorf(pgerror.CodeWindowingError,
+				"RANGE with offset PRECEDING/FOLLOWING requires exactly one ORDER BY column")
+		}
+		ordType := s.columns[windowFn.columnOrdering[0].ColIdx].Typ
+		var ok bool
+		offsetType, ok = windowRangeOffsetType(ordType)
+		if ok {
+			windowFn.rangeOps, ok = tree.LookupWindowRangeOps(ordType, offsetType)
+		}
+		if !ok {
+			return pgerror.NewErrorf(pgerror.CodeFeatureNotSupportedError,
+				"RANGE with offset PRECEDING/FOLLOWING is not supported for column type %s", ordType)
+		}
+	}
+
+	typeOffset := func(bound *tree.WindowFrameBound) (tree.TypedExpr, error) {
+		if bound == nil || !bound.HasOffset() {
+			return nil, nil
+		}
+		if tree.ContainsVars(nil /* evalCtx */, bound.OffsetExpr) {
+			return nil, pgerror.NewErrorf(pgerror.CodeWindowingError,
+				"argument of %s must not contain variables", modeName)
+		}
+		if err := p.txCtx.AssertNoAggregationOrWindowing(
+			bound.OffsetExpr, modeName, p.SessionData().SearchPath,
+		); err != nil {
+			return nil, err
+		}
+		return p.analyzeExpr(ctx, bound.OffsetExpr, nil, tree.IndexedVarHelper{},
+			offsetType, true, modeName)
+	}
+	var err error
+	if windowFn.startOffset, err = typeOffset(startBound); err != nil {
+		return err
+	}
+	windowFn.endOffset, err = typeOffset(endBound)
+	return err
+}
+
+// windowRangeOffsetType returns the type of the offsets of a window frame in
+// RANGE mode, given the type of the ORDER BY column of the window.
+func windowRangeOffsetType(ordType types.T) (types.T, bool) {
+	switch ordType {
+	case types.Int, types.Float, types.Decimal:
+		return ordType, true
+	case types.Date, types.Timestamp, types.TimestampTZ:
+		return types.Interval, true
+	default:
+		return nil, false
+	}
+}
+
+// evalFrameOffset evaluates the offset of a frame bound, which must be
+// neither NULL nor negative.
+func evalFrameOffset(
+	evalCtx *tree.EvalContext, expr tree.TypedExpr, bound string,
+) (tree.Datum, error) {
+	if expr == nil {
+		return nil, nil
+	}
+	offset, err := expr.Eval(evalCtx)
+	if err != nil {
+		return nil, err
+	}
+	if offset == tree.DNull {
+		return nil, pgerror.NewErrorf(pgerror.CodeNullValueNotAllowedError,
+			"frame %s offset must not be null", bound)
+	}
+	var negative bool
+	switch t := offset.(type) {
+	case *tree.DInt:
+		negative = *t < 0
+	case *tree.DFloat:
+		negative = *t < 0
+	case *tree.DDecimal:
+		negative = t.Sign() < 0
+	case *tree.DInterval:
+		negative = t.Duration.Compare(duration.Duration{}) < 0
+	}
+	if negative {
+		return nil, pgerror.NewErrorf(pgerror.CodeInvalidPrecedingOrFollowingSizeError,
+			"frame %s offset must not be negative", bound)
+	}
+	return offset, nil
+}
+
 // constructWindowDef constructs a WindowDef using the provided WindowDef value and the
 // set of named window specifications on the current SELECT clause. If the provided
 // WindowDef does not reference a named window spec, then it will simply be returned without
@@ -372,6 +486,12 @@ func constructWindowDef(
 		}
 		def.OrderBy = referencedSpec.OrderBy
 	}
+
+	// referencedSpec.Frame prevents the spec from being copied.
+	if referencedSpec.Frame != nil {
+		return def, pgerror.NewErrorf(pgerror.CodeWindowingError,
+			"cannot copy window %q because it has a frame clause", refName)
+	}
 	return def, nil
 }
 
@@ -606,6 +726,15 @@ func (n *windowNode) computeWindows(ctx context.Context, evalCtx *tree.EvalConte
 	var scratchBytes []byte
 	var scratchDatum []tree.Datum
 	for windowIdx, windowFn := range n.funcs {
+		startOffset, err := evalFrameOffset(evalCtx, windowFn.startOffset, "starting")
+		if err != nil {
+			return err
+		}
+		endOffset, err := evalFrameOffset(evalCtx, windowFn.endOffset, "ending")
+		if err != nil {
+			return err
+		}
+
 		partitions := make(map[string][]tree.IndexedRow)
 
 		if len(windowFn.partitionIdxs) == 0 {
@@ -636,8 +765,7 @@ func (n *windowNode) computeWindows(ctx context.Context, evalCtx *tree.EvalConte
 		// See Cao et al. [http://vldb.org/pvldb/vol5/p1244_yucao_vldb2012.pdf]
 		for rowI := 0; rowI < rowCount; rowI++ {
 			row := n.run.wrappedRenderVals.At(rowI)
-			sourceVals := row[:n.sourceCols]
-			entry := tree.IndexedRow{Idx: rowI, Row: sourceVals}
+			entry := tree.IndexedRow{Idx: rowI, Row: row}
 			if len(windowFn.partitionIdxs) == 0 {
 				// If no partition indexes are included for the window function, all
 				// rows are added to the same partition.
@@ -674,18 +802,18 @@ func (n *windowNode) computeWindows(ctx context.Context, evalCtx *tree.EvalConte
 		//   * Segment Tree
 		// See Leis et al. [http://www.vldb.org/pvldb/vol8/p1058-leis.pdf]
 		for _, partition := range partitions {
-			// TODO(nvanbenschoten): Handle framing here. Right now we only handle the default
-			// framing option of RANGE UNBOUNDED PRECEDING. With ORDER BY, this sets the frame
-			// to be all rows from the partition start up through the current row's last ORDER BY
-			// peer. Without ORDER BY, all rows of the partition are included in the window frame,
-			// since all rows become peers of the current row. Once we add better framing support,
-			// we should flesh this logic out more.
+			// The default framing option is RANGE UNBOUNDED PRECEDING. With ORDER BY,
+			// this sets the frame to be all rows from the partition start up through the
+			// current row's last ORDER BY peer. Without ORDER BY, all rows of the partition
+			// are included in the window frame, since all rows become peers of the current
+			// row. Other frames are computed by the window functions themselves from the
+			// frame specification and the peer groups (see tree.WindowFrameRun).
 			builtin := windowFn.expr.GetWindowConstructor()(evalCtx)
 			defer builtin.Close(ctx, evalCtx)
 
-			// Since we only support two types of window frames (see TODO above), we only
-			// need two possible types of peerGroupChecker's to help determine peer groups
-			// for given tuples.
+			// Peer groups are determined by the ORDER BY clause, so we only need two
+			// possible types of peerGroupChecker's to help determine peer groups for
+			// given tuples.
 			var peerGrouper peerGroupChecker
 			if windowFn.columnOrdering != nil {
 				// If an ORDER BY clause is provided, order the partition and use the
@@ -710,11 +838,19 @@ func (n *windowNode) computeWindows(ctx context.Context, evalCtx *tree.EvalConte
 			}
 
 			// Iterate over peer groups within partition using a window frame.
-			frame := tree.WindowFrame{
-				Rows:        partition,
-				ArgIdxStart: windowFn.argIdxStart,
-				ArgCount:    windowFn.argCount,
-				RowIdx:      0,
+			frame := tree.WindowFrameRun{
+				Rows:             partition,
+				ArgIdxStart:      windowFn.argIdxStart,
+				ArgCount:         windowFn.argCount,
+				Frame:            windowFn.frame,
+				StartBoundOffset: startOffset,
+				EndBoundOffset:   endOffset,
+				OrdBinOps:        windowFn.rangeOps,
+				RowIdx:           0,
+			}
+			if len(windowFn.columnOrdering) > 0 {
+				frame.OrdColIdx = windowFn.columnOrdering[0].ColIdx
+				frame.OrdDescending = windowFn.columnOrdering[0].Direction == encoding.Descending
 			}
 			for frame.RowIdx < len(partition) {
 				// Compute the size of the current peer group.
@@ -729,7 +865,7 @@ func (n *windowNode) computeWindows(ctx context.Context, evalCtx *tree.EvalConte
 
 				// Perform calculations on each row in the current peer group.
 				for ; frame.RowIdx < frame.FirstPeerIdx+frame.PeerRowCount; frame.RowIdx++ {
-					res, err := builtin.Compute(ctx, evalCtx, frame)
+					res, err := builtin.Compute(ctx, evalCtx, &frame)
 					if err != nil {
 						return err
 					}
@@ -808,6 +944,69 @@ func (n *windowNode) populateValues(ctx context.Context, evalCtx *tree.EvalConte
 	return nil
 }
 
+// distSQLRenders returns the renders of the windowNode as expressions whose
+// IndexedVars refer to the columns of the wrapped plan, followed by one column
+// per window function holding its results. It is used by the DistSQL physical
+// planner, which computes window functions in windower processors and then
+// evaluates these renders on their output.
+func (n *windowNode) distSQLRenders() []tree.TypedExpr {
+	wrappedCols := planColumns(n.plan)
+	colTypes := make([]types.T, len(wrappedCols)+len(n.funcs))
+	for i, col := range wrappedCols {
+		colTypes[i] = col.Typ
+	}
+	for i, fn := range n.funcs {
+		colTypes[len(wrappedCols)+i] = fn.ResolvedType()
+	}
+	h := tree.MakeTypesOnlyIndexedVarHelper(colTypes)
+
+	// IndexedVars found above the windowing level either belong to
+	// n.ivarHelper, in which case they refer to a column through
+	// colContainer, or stand in for an aggregate function through
+	// aggContainer. See replaceIndexVarsAndAggFuncs.
+	colVars := n.ivarHelper.GetIndexedVars()
+	replace := func(expr tree.Expr) (error, bool, tree.Expr) {
+		switch t := expr.(type) {
+		case *windowFuncHolder:
+			return nil, false, h.IndexedVar(len(wrappedCols) + t.funcIdx)
+		case *tree.IndexedVar:
+			if t.Idx < len(colVars) && t == &colVars[t.Idx] {
+				return nil, false, h.IndexedVar(n.colContainer.idxMap[t.Idx])
+			}
+			return nil, false, h.IndexedVar(n.aggContainer.idxMap[t.Idx])
+		default:
+			return nil, true, expr
+		}
+	}
+
+	renders := make([]tree.TypedExpr, len(n.windowRender))
+	curColIdx := 0
+	curFnIdx := 0
+	for i, render := range n.windowRender {
+		if render == nil {
+			// The render is propagated directly from the wrapped plan.
+			renders[i] = h.IndexedVar(curColIdx)
+			curColIdx++
+			continue
+		}
+		// Skip the arguments of the window functions beneath this render, as
+		// populateValues does.
+		for ; curFnIdx < len(n.funcs); curFnIdx++ {
+			windowFn := n.funcs[curFnIdx]
+			if windowFn.argIdxStart != curColIdx {
+				break
+			}
+			curColIdx += windowFn.argCount
+		}
+		expr, err := tree.SimpleVisit(render, replace)
+		if err != nil {
+			panic(err)
+		}
+		renders[i] = expr.(tree.TypedExpr)
+	}
+	return renders
+}
+
 type extractWindowFuncsVisitor struct {
 	n *windowNode
 
@@ -913,6 +1112,11 @@ type windowFuncHolder struct {
 	windowDef      tree.WindowDef
 	partitionIdxs  []int
 	columnOrdering sqlbase.ColumnOrdering
+
+	frame       *tree.WindowFrame   // the window frame, or nil for the default frame
+	startOffset tree.TypedExpr      // the offset of the frame start, if any
+	endOffset   tree.TypedExpr      // the offset of the frame end, if any
+	rangeOps    tree.WindowRangeOps // the operators used to apply offsets in RANGE mode
 }
 
 func (*windowFuncHolder) Variable() {}
@@ -56,6 +56,10 @@ type cteSource struct {
 	// alias holds the name of the CTE and the renaming of its columns, if
 	// present.
 	alias tree.AliasClause
+	// recursionErr, if set, is the error reported when the CTE is referenced.
+	// It is used while planning a CTE defined in a WITH RECURSIVE clause, at
+	// places where the CTE must not refer to itself.
+	recursionErr error
 }
 
 func (e cteNameEnvironment) push(frame cteNameEnvironmentFrame) cteNameEnvironment {
@@ -85,7 +89,13 @@ func (p *planner) initWith(ctx context.Context, with *tree.With) (func(p *planne
 					"WITH query name %s specified more than once",
 					cte.Name.Alias)
 			}
-			ctePlan, err := p.newPlan(ctx, cte.Stmt, nil)
+			var ctePlan planNode
+			var err error
+			if with.Recursive {
+				ctePlan, err = p.planRecursiveCTE(ctx, frame, cte)
+			} else {
+				ctePlan, err = p.newPlan(ctx, cte.Stmt, nil)
+			}
 			if err != nil {
 				return nil, err
 			}
@@ -111,6 +121,9 @@ func (p *planner) getCTEDataSource(tn *tree.TableName) (planDataSource, bool, er
 	for i := range p.curPlan.cteNameEnvironment {
 		frame := p.curPlan.cteNameEnvironment[len(p.curPlan.cteNameEnvironment)-1-i]
 		if cteSource, ok := frame[tn.TableName]; ok {
+			if cteSource.recursionErr != nil {
+				return planDataSource{}, false, cteSource.recursionErr
+			}
 			if cteSource.used {
 				// TODO(jordan): figure out how to lift this restriction.
 				// CTE expressions that are used more than once will need to be
@@ -929,6 +929,9 @@ func upgradeDescsWithFn(
 						}
 					}
 
+				case *sqlbase.Descriptor_Function:
+					// Function descriptors have no format upgrades.
+
 				default:
 					return errors.Errorf("Descriptor.Union has unexpected type %T", t)
 				}
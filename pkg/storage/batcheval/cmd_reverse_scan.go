@@ -37,8 +37,19 @@ func ReverseScan(
 	h := cArgs.Header
 	reply := resp.(*roachpb.ReverseScanResponse)
 
-	rows, resumeSpan, intents, err := engine.MVCCReverseScan(ctx, batch, args.Key, args.EndKey,
-		cArgs.MaxKeys, h.Timestamp, h.ReadConsistency == roachpb.CONSISTENT, h.Txn)
+	var rows []roachpb.KeyValue
+	var resumeSpan *roachpb.Span
+	var intents []roachpb.Intent
+	var err error
+	if h.WaitPolicy == roachpb.WaitPolicy_SkipLocked && h.ReadConsistency == roachpb.CONSISTENT {
+		// The intents of the skipped rows are not returned to the client,
+		// but they are cleaned up if their transactions were abandoned.
+		rows, resumeSpan, intents, err = mvccScanSkipLocked(ctx, batch, args.Key, args.EndKey,
+			cArgs.MaxKeys, h.Timestamp, h.Txn, true /* reverse */)
+	} else {
+		rows, resumeSpan, intents, err = engine.MVCCReverseScan(ctx, batch, args.Key, args.EndKey,
+			cArgs.MaxKeys, h.Timestamp, h.ReadConsistency == roachpb.CONSISTENT, h.Txn)
+	}
 	if err != nil {
 		return result.Result{}, err
 	}
@@ -0,0 +1,127 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package batcheval
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+)
+
+// mvccScanSkipLocked performs a consistent scan of the key range [key,
+// endKey) which omits the rows covered by intents of other transactions,
+// as required by the SkipLocked wait policy. The skipped intents are
+// returned so that the ones of abandoned transactions can be cleaned up
+// asynchronously.
+//
+// Every time the scan runs into intents, the first one in scan order is
+// skipped along with the rest of its SQL row, and the scan is retried on
+// the two parts of the remaining range around that row.
+func mvccScanSkipLocked(
+	ctx context.Context,
+	batch engine.Reader,
+	key, endKey roachpb.Key,
+	max int64,
+	timestamp hlc.Timestamp,
+	txn *roachpb.Transaction,
+	reverse bool,
+) ([]roachpb.KeyValue, *roachpb.Span, []roachpb.Intent, error) {
+	scan := engine.MVCCScan
+	if reverse {
+		scan = engine.MVCCReverseScan
+	}
+
+	var rows []roachpb.KeyValue
+	var skipped []roachpb.Intent
+	for {
+		kvs, resumeSpan, _, err := scan(ctx, batch, key, endKey, max, timestamp, true /* consistent */, txn)
+		wiErr, ok := err.(*roachpb.WriteIntentError)
+		if !ok {
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			return append(rows, kvs...), resumeSpan, skipped, nil
+		}
+
+		// Find the first intent in scan order. All the keys preceding it
+		// were scanned without running into intents.
+		intent := wiErr.Intents[0]
+		for _, i := range wiErr.Intents[1:] {
+			if (i.Key.Compare(intent.Key) < 0) != reverse {
+				intent = i
+			}
+		}
+		skipped = append(skipped, intent)
+		rowStart, rowEnd := lockedRowSpan(intent.Key)
+		if rowStart.Compare(key) < 0 {
+			rowStart = key
+		}
+		if rowEnd.Compare(endKey) > 0 {
+			rowEnd = endKey
+		}
+
+		// Scan the part of the range preceding the locked row.
+		var preKey, preEndKey roachpb.Key
+		if reverse {
+			preKey, preEndKey = rowEnd, endKey
+		} else {
+			preKey, preEndKey = key, rowStart
+		}
+		if preKey.Compare(preEndKey) < 0 {
+			kvs, resumeSpan, _, err = scan(ctx, batch, preKey, preEndKey, max, timestamp, true /* consistent */, txn)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			rows = append(rows, kvs...)
+			max -= int64(len(kvs))
+			if resumeSpan != nil {
+				// The limit was reached: the rest of the range, including
+				// the locked row, remains to be scanned.
+				if reverse {
+					resumeSpan.Key = key
+				} else {
+					resumeSpan.EndKey = endKey
+				}
+				return rows, resumeSpan, skipped, nil
+			}
+		}
+
+		// Continue past the locked row.
+		if reverse {
+			endKey = rowStart
+		} else {
+			key = rowEnd
+		}
+		if key.Compare(endKey) >= 0 {
+			return rows, nil, skipped, nil
+		}
+		if max == 0 {
+			return rows, &roachpb.Span{Key: key, EndKey: endKey}, skipped, nil
+		}
+	}
+}
+
+// lockedRowSpan returns the span of the SQL row containing the given key.
+// Keys that do not belong to a table are their own rows.
+func lockedRowSpan(key roachpb.Key) (roachpb.Key, roachpb.Key) {
+	rowKey, err := keys.EnsureSafeSplitKey(key)
+	if err != nil || len(rowKey) == 0 || len(rowKey) == len(key) {
+		return key, key.Next()
+	}
+	return rowKey, rowKey.PrefixEnd()
+}
@@ -0,0 +1,144 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package batcheval
+
+import (
+	"context"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+func TestMVCCScanSkipLocked(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	eng := engine.NewInMem(roachpb.Attributes{}, 1<<20)
+	defer eng.Close()
+
+	// Every row has two column families.
+	tablePrefix := keys.MakeTablePrefix(50)
+	rowKey := func(row uint64) roachpb.Key {
+		k := encoding.EncodeUvarintAscending(append([]byte(nil), tablePrefix...), 1)
+		return encoding.EncodeUvarintAscending(k, row)
+	}
+	famKey := func(row uint64, fam uint32) roachpb.Key {
+		return keys.MakeFamilyKey(rowKey(row), fam)
+	}
+
+	ts1 := hlc.Timestamp{WallTime: 1}
+	ts2 := hlc.Timestamp{WallTime: 2}
+	ts3 := hlc.Timestamp{WallTime: 3}
+	for row := uint64(1); row <= 5; row++ {
+		for fam := uint32(0); fam < 2; fam++ {
+			if err := engine.MVCCPut(
+				ctx, eng, nil, famKey(row, fam), ts1, roachpb.MakeValueFromString("v"), nil,
+			); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	// Lock rows 2 and 4 by writing intents on one of their families.
+	txn := roachpb.MakeTransaction("test", nil, roachpb.NormalUserPriority,
+		enginepb.SERIALIZABLE, ts2, 0)
+	for _, k := range []roachpb.Key{famKey(2, 1), famKey(4, 0)} {
+		if err := engine.MVCCPut(
+			ctx, eng, nil, k, ts2, roachpb.MakeValueFromString("w"), &txn,
+		); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	start, end := rowKey(0), rowKey(10)
+	keysOf := func(kvs []roachpb.KeyValue) []roachpb.Key {
+		var res []roachpb.Key
+		for _, kv := range kvs {
+			res = append(res, kv.Key)
+		}
+		return res
+	}
+
+	testCases := []struct {
+		reverse    bool
+		max        int64
+		expKeys    []roachpb.Key
+		expResume  *roachpb.Span
+		expSkipped int
+	}{
+		{
+			max: math.MaxInt64,
+			expKeys: []roachpb.Key{
+				famKey(1, 0), famKey(1, 1), famKey(3, 0), famKey(3, 1), famKey(5, 0), famKey(5, 1),
+			},
+			expSkipped: 2,
+		},
+		{
+			reverse: true,
+			max:     math.MaxInt64,
+			expKeys: []roachpb.Key{
+				famKey(5, 1), famKey(5, 0), famKey(3, 1), famKey(3, 0), famKey(1, 1), famKey(1, 0),
+			},
+			expSkipped: 2,
+		},
+		{
+			max:        3,
+			expKeys:    []roachpb.Key{famKey(1, 0), famKey(1, 1), famKey(3, 0)},
+			expResume:  &roachpb.Span{Key: famKey(3, 1), EndKey: end},
+			expSkipped: 1,
+		},
+		{
+			max:       2,
+			expKeys:   []roachpb.Key{famKey(1, 0), famKey(1, 1)},
+			expResume: &roachpb.Span{Key: famKey(2, 0), EndKey: end},
+		},
+	}
+	for _, tc := range testCases {
+		kvs, resume, skipped, err := mvccScanSkipLocked(
+			ctx, eng, start, end, tc.max, ts3, nil /* txn */, tc.reverse,
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := keysOf(kvs); !reflect.DeepEqual(got, tc.expKeys) {
+			t.Errorf("reverse=%t max=%d: expected keys %s, got %s", tc.reverse, tc.max, tc.expKeys, got)
+		}
+		if !reflect.DeepEqual(resume, tc.expResume) {
+			t.Errorf("reverse=%t max=%d: expected resume span %v, got %v", tc.reverse, tc.max, tc.expResume, resume)
+		}
+		if len(skipped) != tc.expSkipped {
+			t.Errorf("reverse=%t max=%d: expected %d skipped intents, got %d", tc.reverse, tc.max, tc.expSkipped, len(skipped))
+		}
+	}
+
+	// The transaction holding the locks sees its own writes.
+	kvs, _, skipped, err := mvccScanSkipLocked(
+		ctx, eng, start, end, math.MaxInt64, ts3, &txn, false, /* reverse */
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(kvs) != 10 || len(skipped) != 0 {
+		t.Errorf("expected 10 rows and no skipped intents, got %d and %d", len(kvs), len(skipped))
+	}
+}
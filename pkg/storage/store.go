@@ -2864,7 +2864,11 @@ func (s *Store) Send(
 			// this is the code path with the requesting client waiting.
 			if pErr.Index != nil {
 				var pushType roachpb.PushTxnType
-				if ba.IsWrite() {
+				if ba.WaitPolicy != roachpb.WaitPolicy_Block {
+					// The request must not wait for the conflicting transactions:
+					// only clean up after the ones that are abandoned.
+					pushType = roachpb.PUSH_TOUCH
+				} else if ba.IsWrite() {
 					pushType = roachpb.PUSH_ABORT
 				} else {
 					pushType = roachpb.PUSH_TIMESTAMP
@@ -2890,7 +2894,13 @@ func (s *Store) Send(
 					clonedTxn := h.Txn.Clone()
 					h.Txn = &clonedTxn
 				}
+				wiErr := pErr
 				if pErr = s.intentResolver.processWriteIntentError(ctx, pErr, args, h, pushType); pErr != nil {
+					// If the conflicting transaction is still active and the request
+					// cannot wait for it, return the original error to the client.
+					if ba.WaitPolicy != roachpb.WaitPolicy_Block {
+						return nil, wiErr
+					}
 					// Do not propagate ambiguous results; assume success and retry original op.
 					if _, ok := pErr.GetDetail().(*roachpb.AmbiguousResultError); !ok {
 						// Preserve the error index.
@@ -32,6 +32,10 @@ export const CREATE_SEQUENCE = "create_sequence";
 export const ALTER_SEQUENCE = "alter_sequence";
 // Recorded when a sequence is dropped.
 export const DROP_SEQUENCE = "drop_sequence";
+// Recorded when a function is created.
+export const CREATE_FUNCTION = "create_function";
+// Recorded when a function is dropped.
+export const DROP_FUNCTION = "drop_function";
 // Recorded when an in-progress schema change encounters a problem and is
 // reversed.
 export const REVERSE_SCHEMA_CHANGE = "reverse_schema_change";
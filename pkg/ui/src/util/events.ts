@@ -18,6 +18,7 @@ export function getEventDescription(e: Event$Properties): string {
     User: string,
     ViewName: string,
     SequenceName: string,
+    FunctionName: string,
     SettingName: string,
     Value: string,
   } = protobuf.util.isset(e, "info") ? JSON.parse(e.info) : {};
@@ -57,6 +58,10 @@ export function getEventDescription(e: Event$Properties): string {
       return `Sequence Altered: User ${info.User} altered sequence ${info.SequenceName}`;
     case eventTypes.DROP_SEQUENCE:
       return `Sequence Dropped: User ${info.User} dropped sequence ${info.SequenceName}`;
+    case eventTypes.CREATE_FUNCTION:
+      return `Function Created: User ${info.User} created function ${info.FunctionName}`;
+    case eventTypes.DROP_FUNCTION:
+      return `Function Dropped: User ${info.User} dropped function ${info.FunctionName}`;
     case eventTypes.REVERSE_SCHEMA_CHANGE:
       return `Schema Change Reversed: Schema change with ID ${info.MutationID} was reversed.`;
     case eventTypes.FINISH_SCHEMA_CHANGE:
@@ -33,13 +33,28 @@ func FixedOffsetTimeZoneToLocation(offset int, origRepr string) *time.Location {
 
 // TimeZoneStringToLocation transforms a string into a time.Location. It
 // supports the usual locations and also time zones with fixed offsets created
-// by FixedOffsetTimeZoneToLocation().
+// by FixedOffsetTimeZoneToLocation(). Location names are matched
+// case-insensitively against the names of the IANA Time Zone database, so
+// that for example "utc" and "europe/rome" are accepted.
 func TimeZoneStringToLocation(location string) (*time.Location, error) {
 	offset, origRepr, parsed := ParseFixedOffsetTimeZone(location)
 	if parsed {
 		return FixedOffsetTimeZoneToLocation(offset, origRepr), nil
 	}
-	return LoadLocation(location)
+	loc, err := LoadLocation(location)
+	if err == nil {
+		return loc, nil
+	}
+	if name, ok := lookupZoneName(location); ok {
+		if loc, err1 := LoadLocation(name); err1 == nil {
+			return loc, nil
+		}
+	}
+	// UTC is known to time.LoadLocation() even without the database.
+	if strings.EqualFold(location, "UTC") {
+		return time.UTC, nil
+	}
+	return nil, fmt.Errorf("cannot find time zone %q: %v", location, err)
 }
 
 // ParseFixedOffsetTimeZone takes the string representation of a time.Location
@@ -0,0 +1,55 @@
+// Copyright 2018 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package timeutil
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTimeZoneStringToLocation(t *testing.T) {
+	if _, err := LoadLocation("Europe/Rome"); err != nil {
+		t.Skipf("time zone database not available: %v", err)
+	}
+
+	testCases := []struct {
+		location string
+		expected string
+	}{
+		{"UTC", "UTC"},
+		{"utc", "UTC"},
+		{"Europe/Rome", "Europe/Rome"},
+		{"europe/rome", "Europe/Rome"},
+		{"EUROPE/ROME", "Europe/Rome"},
+		{"america/new_york", "America/New_York"},
+		{"fixed offset:3600 (+01:00)", "fixed offset:3600 (+01:00)"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.location, func(t *testing.T) {
+			loc, err := TimeZoneStringToLocation(tc.location)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if loc.String() != tc.expected {
+				t.Fatalf("expected %s, got %s", tc.expected, loc)
+			}
+		})
+	}
+
+	if _, err := TimeZoneStringToLocation("foo"); err == nil ||
+		!strings.Contains(err.Error(), `cannot find time zone "foo"`) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
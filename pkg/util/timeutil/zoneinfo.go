@@ -16,7 +16,10 @@ package timeutil
 
 import (
 	"errors"
+	"os"
+	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -43,3 +46,49 @@ func LoadLocation(name string) (*time.Location, error) {
 	}
 	return l, err
 }
+
+// zoneinfoDirs are the directories searched for the IANA Time Zone database,
+// in the order used by time.LoadLocation() on Unix systems. The ZONEINFO
+// environment variable is searched first.
+var zoneinfoDirs = []string{
+	"/usr/share/zoneinfo/",
+	"/usr/share/lib/zoneinfo/",
+	"/usr/lib/locale/TZ/",
+}
+
+// zoneNames maps the lower case names of the locations in the IANA Time Zone
+// database to their actual names. It is built on first use.
+var zoneNames struct {
+	once  sync.Once
+	names map[string]string
+}
+
+// lookupZoneName returns the name of the location in the IANA Time Zone
+// database that matches name case-insensitively, if any.
+func lookupZoneName(name string) (string, bool) {
+	zoneNames.once.Do(func() {
+		zoneNames.names = make(map[string]string)
+		dirs := zoneinfoDirs
+		if dir := os.Getenv("ZONEINFO"); dir != "" {
+			dirs = append([]string{dir}, dirs...)
+		}
+		for _, dir := range dirs {
+			_ = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+				if err != nil || info.IsDir() {
+					return nil
+				}
+				rel, err := filepath.Rel(dir, path)
+				if err != nil {
+					return nil
+				}
+				rel = filepath.ToSlash(rel)
+				if _, ok := zoneNames.names[strings.ToLower(rel)]; !ok {
+					zoneNames.names[strings.ToLower(rel)] = rel
+				}
+				return nil
+			})
+		}
+	})
+	actual, ok := zoneNames.names[strings.ToLower(name)]
+	return actual, ok
+}